@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// adminEnabled reports whether the /admin group can be authenticated, either by ADMIN_TOKEN or a JWT admin role
+func (s *Server) adminEnabled() bool {
+	return s.adminToken != "" || s.auth != nil
+}
+
+// HandleAdminResync re-runs the bootstrap sync in the background
+// The url query parameter overrides BOOTSTRAP_URL. Progress is reported on /v2/status and the SSE stream.
+func (s *Server) HandleAdminResync(c *fiber.Ctx) error {
+	urls := s.bootstrapURLs
+	if url := c.Query("url"); url != "" {
+		urls = []string{url}
+	}
+	if len(urls) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "No bootstrap URL configured or given",
+		})
+	}
+	if !s.resyncing.CompareAndSwap(false, true) {
+		return c.Status(fiber.StatusConflict).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_CONFLICT",
+			Description: "Resync already running",
+		})
+	}
+
+	go func() {
+		defer s.resyncing.Store(false)
+		s.cm.BootstrapSync(s.ctx, urls...)
+	}()
+
+	return c.Status(fiber.StatusAccepted).JSON(Response{
+		Status: "success",
+		Value:  fiber.Map{"urls": urls},
+	})
+}
+
+// HandleAdminInvalidate marks a block invalid and returns the resulting tip
+func (s *Server) HandleAdminInvalidate(c *fiber.Ctx) error {
+	return s.adminBlockOperation(c, s.cm.InvalidateBlock)
+}
+
+// HandleAdminReconsider clears a block's invalid mark and returns the resulting tip
+func (s *Server) HandleAdminReconsider(c *fiber.Ctx) error {
+	return s.adminBlockOperation(c, s.cm.ReconsiderBlock)
+}
+
+// adminBlockOperation applies op to the :hash parameter and reports the new tip
+func (s *Server) adminBlockOperation(c *fiber.Ctx, op func(ctx context.Context, hash *chainhash.Hash) error) error {
+	hash, err := chainhash.NewHashFromHex(c.Params("hash"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid hash parameter",
+		})
+	}
+
+	if err := op(c.UserContext(), hash); err != nil {
+		status, code := fiber.StatusInternalServerError, "ERR_INTERNAL"
+		switch {
+		case errors.Is(err, chaintracks.ErrHeaderNotFound), errors.Is(err, chaintracks.ErrBlockNotInvalidated):
+			status, code = fiber.StatusNotFound, "ERR_NOT_FOUND"
+		case errors.Is(err, chaintracks.ErrInvalidateGenesis):
+			status, code = fiber.StatusBadRequest, "ERR_INVALID_PARAMS"
+		}
+		return c.Status(status).JSON(Response{
+			Status:      "error",
+			Code:        code,
+			Description: err.Error(),
+		})
+	}
+
+	return c.JSON(Response{
+		Status: "success",
+		Value:  s.cm.GetTip(c.UserContext()),
+	})
+}
+
+// HandleAdminInvalidated lists the blocks marked invalid
+func (s *Server) HandleAdminInvalidated(c *fiber.Ctx) error {
+	c.Set("Cache-Control", "no-cache")
+	return c.JSON(Response{
+		Status: "success",
+		Value:  s.cm.GetInvalidatedBlocks(),
+	})
+}
+
+// HandleAdminClearOrphans drops every header not on the main chain
+func (s *Server) HandleAdminClearOrphans(c *fiber.Ctx) error {
+	return c.JSON(Response{
+		Status: "success",
+		Value:  fiber.Map{"removed": s.cm.ClearOrphans()},
+	})
+}
+
+// adminPeerRequest is the body of POST /admin/peers
+type adminPeerRequest struct {
+	Address string `json:"address"` // Multiaddr ending in /p2p/<peer ID>
+}
+
+// HandleAdminConnectPeer pins a peer and dials it when the P2P client supports it
+func (s *Server) HandleAdminConnectPeer(c *fiber.Ctx) error {
+	var req adminPeerRequest
+	if err := c.BodyParser(&req); err != nil || req.Address == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Body must be {\"address\": \"<multiaddr>/p2p/<peer ID>\"}",
+		})
+	}
+
+	connected, err := s.cm.ConnectPeer(c.UserContext(), req.Address)
+	if err != nil {
+		return peerError(c, err)
+	}
+	return c.JSON(Response{
+		Status: "success",
+		Value:  fiber.Map{"address": req.Address, "connected": connected},
+	})
+}
+
+// HandleAdminBanPeer bans a peer and disconnects it when the P2P client supports it
+func (s *Server) HandleAdminBanPeer(c *fiber.Ctx) error {
+	id := c.Params("id")
+	disconnected, err := s.cm.BanPeer(c.UserContext(), id)
+	if err != nil {
+		return peerError(c, err)
+	}
+	return c.JSON(Response{
+		Status: "success",
+		Value:  fiber.Map{"id": id, "banned": true, "disconnected": disconnected},
+	})
+}
+
+// peerError maps a peer management error to a response
+func peerError(c *fiber.Ctx, err error) error {
+	status, code := fiber.StatusBadGateway, "ERR_PEER"
+	switch {
+	case errors.Is(err, chaintracks.ErrInvalidPeerAddress), errors.Is(err, chaintracks.ErrInvalidPeerID):
+		status, code = fiber.StatusBadRequest, "ERR_INVALID_PARAMS"
+	case errors.Is(err, chaintracks.ErrP2PNotStarted):
+		status, code = fiber.StatusServiceUnavailable, "ERR_P2P_NOT_STARTED"
+	}
+	return c.Status(status).JSON(Response{
+		Status:      "error",
+		Code:        code,
+		Description: err.Error(),
+	})
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+const testAdminToken = "admin-secret"
+
+// setupAdminApp serves a synthetic chain with ADMIN_TOKEN set
+func setupAdminApp(t *testing.T) (*fiber.App, *Server) {
+	t.Helper()
+
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 3))
+	server.adminToken = testAdminToken
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	return app, server
+}
+
+// adminPost performs an admin POST request and decodes the response
+func adminPost(t *testing.T, app *fiber.App, path string) (int, Response) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testAdminToken)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestAdminAuth(t *testing.T) {
+	t.Run("DisabledWithoutCredentials", func(t *testing.T) {
+		server := NewServer(t.Context(), newSyntheticChainManager(t, 3))
+		app := fiber.New()
+		server.SetupRoutes(app, NewDashboardHandler(server))
+
+		assert.Equal(t, http.StatusNotFound, authGet(t, app, "/admin/invalidated", ""))
+	})
+
+	t.Run("AdminToken", func(t *testing.T) {
+		app, _ := setupAdminApp(t)
+
+		assert.Equal(t, http.StatusOK, authGet(t, app, "/admin/invalidated", testAdminToken))
+		assert.Equal(t, http.StatusUnauthorized, authGet(t, app, "/admin/invalidated", "wrong"))
+		assert.Equal(t, http.StatusUnauthorized, authGet(t, app, "/admin/invalidated", ""))
+		assert.Equal(t, http.StatusUnauthorized, authGet(t, app, "/admin/invalidated?access_token="+testAdminToken, ""))
+	})
+
+	t.Run("JWTAdminRole", func(t *testing.T) {
+		app := setupAuthApp(t)
+
+		assert.Equal(t, http.StatusOK, authGet(t, app, "/admin/invalidated", signHMAC(t, jwt.MapClaims{"roles": roleAdmin})))
+		assert.Equal(t, http.StatusForbidden, authGet(t, app, "/admin/invalidated", signHMAC(t, jwt.MapClaims{"roles": roleRead})))
+	})
+
+	t.Run("AdminTokenAlongsideJWT", func(t *testing.T) {
+		app, server := setupAdminApp(t)
+		auth, err := newJWTAuth(t.Context(), AuthConfig{JWTSecret: testJWTSecret})
+		require.NoError(t, err)
+		server.auth = auth
+
+		assert.Equal(t, http.StatusOK, authGet(t, app, "/admin/invalidated", testAdminToken))
+		assert.Equal(t, http.StatusOK, authGet(t, app, "/admin/invalidated", signHMAC(t, jwt.MapClaims{"roles": roleAdmin})))
+		assert.Equal(t, http.StatusUnauthorized, authGet(t, app, "/admin/invalidated", "wrong"))
+	})
+}
+
+func TestAdminInvalidateAndReconsider(t *testing.T) {
+	app, server := setupAdminApp(t)
+	hash := chainhash.Hash{1}
+
+	status, body := adminPost(t, app, "/admin/invalidate/"+hash.String())
+	require.Equal(t, http.StatusOK, status)
+	assert.InDelta(t, 0, body.Value.(map[string]any)["height"], 0)
+	assert.Equal(t, []chainhash.Hash{hash}, server.cm.GetInvalidatedBlocks())
+
+	status, body = adminPost(t, app, "/admin/reconsider/"+hash.String())
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "success", body.Status)
+	assert.Empty(t, server.cm.GetInvalidatedBlocks())
+
+	tests := []struct {
+		name     string
+		path     string
+		expected int
+	}{
+		{"InvalidHash", "/admin/invalidate/zz", http.StatusBadRequest},
+		{"UnknownBlock", "/admin/invalidate/" + chainhash.Hash{0xff}.String(), http.StatusNotFound},
+		{"Genesis", "/admin/invalidate/" + chainhash.Hash{}.String(), http.StatusBadRequest},
+		{"NotInvalidated", "/admin/reconsider/" + hash.String(), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := adminPost(t, app, tt.path)
+			assert.Equal(t, tt.expected, status)
+			assert.Equal(t, "error", body.Status)
+		})
+	}
+}
+
+func TestAdminClearOrphans(t *testing.T) {
+	app, server := setupAdminApp(t)
+	orphan := &chaintracks.BlockHeader{Header: &block.Header{Nonce: 9}, Height: 2, Hash: chainhash.Hash{9}}
+	require.NoError(t, server.cm.AddHeader(orphan))
+
+	status, body := adminPost(t, app, "/admin/clear-orphans")
+	require.Equal(t, http.StatusOK, status)
+	assert.InDelta(t, 1, body.Value.(map[string]any)["removed"], 0)
+}
+
+func TestAdminResync(t *testing.T) {
+	t.Run("RequiresURL", func(t *testing.T) {
+		app, _ := setupAdminApp(t)
+		status, _ := adminPost(t, app, "/admin/resync")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("RunsInBackground", func(t *testing.T) {
+		bootstrap := httptest.NewServer(http.NotFoundHandler())
+		defer bootstrap.Close()
+
+		app, server := setupAdminApp(t)
+		server.bootstrapURLs = []string{bootstrap.URL}
+
+		status, body := adminPost(t, app, "/admin/resync")
+		require.Equal(t, http.StatusAccepted, status)
+		assert.Equal(t, []any{bootstrap.URL}, body.Value.(map[string]any)["urls"])
+		require.Eventually(t, func() bool { return !server.resyncing.Load() }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("RejectsConcurrent", func(t *testing.T) {
+		app, server := setupAdminApp(t)
+		server.resyncing.Store(true)
+
+		status, _ := adminPost(t, app, "/admin/resync?url=http://127.0.0.1:1")
+		assert.Equal(t, http.StatusConflict, status)
+	})
+}
+
+// stubP2PClient is a p2p.Client without peers whose subscriptions close immediately
+type stubP2PClient struct{}
+
+func (stubP2PClient) Subscribe(string) <-chan p2p.Message {
+	ch := make(chan p2p.Message)
+	close(ch)
+	return ch
+}
+func (stubP2PClient) Publish(context.Context, string, []byte) error { return nil }
+func (stubP2PClient) GetPeers() []p2p.PeerInfo                      { return []p2p.PeerInfo{{ID: "peer"}} }
+func (stubP2PClient) GetID() string                                 { return "self" }
+func (stubP2PClient) Close() error                                  { return nil }
+
+func TestAdminPeers(t *testing.T) {
+	addr := chaintracks.DefaultsForNetwork("main").BootstrapPeers[0]
+	info, err := peer.AddrInfoFromString(addr)
+	require.NoError(t, err)
+
+	request := func(t *testing.T, app *fiber.App, method, path, body string) (int, Response) {
+		t.Helper()
+
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testAdminToken)
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		var decoded Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+		return resp.StatusCode, decoded
+	}
+
+	t.Run("P2PNotStarted", func(t *testing.T) {
+		app, _ := setupAdminApp(t)
+		status, _ := request(t, app, http.MethodPost, "/admin/peers", `{"address":"`+addr+`"}`)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+	})
+
+	cm, err := chaintracks.NewChainManager(t.Context(), "test", t.TempDir(), stubP2PClient{})
+	require.NoError(t, err)
+	_, err = cm.Start(t.Context())
+	require.NoError(t, err)
+	server := NewServer(t.Context(), cm)
+	server.adminToken = testAdminToken
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+
+	t.Run("Connect", func(t *testing.T) {
+		status, body := request(t, app, http.MethodPost, "/admin/peers", `{"address":"`+addr+`"}`)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, body.Value.(map[string]any)["connected"])
+	})
+
+	t.Run("Ban", func(t *testing.T) {
+		status, body := request(t, app, http.MethodDelete, "/admin/peers/"+info.ID.String(), "")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, true, body.Value.(map[string]any)["banned"])
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		status, _ := request(t, app, http.MethodPost, "/admin/peers", `{}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _ = request(t, app, http.MethodPost, "/admin/peers", `{"address":"/ip4/203.0.113.1/tcp/9905"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _ = request(t, app, http.MethodDelete, "/admin/peers/not-a-peer", "")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("PublicPeerList", func(t *testing.T) {
+		resp := httpGet(t, app, "/v2/peers")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), `"id":"peer"`)
+	})
+}
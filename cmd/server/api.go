@@ -5,18 +5,22 @@ import (
 	"bufio"
 	"context"
 	_ "embed"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bsv-blockchain/go-sdk/chainhash"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/valyala/fasthttp"
 
 	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	fiberroutes "github.com/bsv-blockchain/go-chaintracks/routes/fiber"
 )
 
 //go:embed openapi.yaml
@@ -26,10 +30,28 @@ var openapiSpec string
 //
 //nolint:containedctx // Context stored for SSE stream shutdown detection
 type Server struct {
-	ctx          context.Context
-	cm           *chaintracks.ChainManager
-	sseClients   map[int64]*bufio.Writer
-	sseClientsMu sync.RWMutex
+	ctx           context.Context
+	cm            *chaintracks.ChainManager
+	sseClients    map[int64]*sseClient
+	sseClientsMu  sync.RWMutex
+	sseDropped    atomic.Uint64                 // Messages dropped from slow SSE clients' queues
+	sseReplay     uint32                        // Max missed tips replayed to a resuming client
+	tipMu         sync.Mutex                    // Guards tipChanged
+	tipChanged    chan struct{}                 // Closed at the next tip change to wake /v2/tip/wait requests
+	maxHeaders    uint32                        // Max headers per /v2/headers request, 0 for the routes default
+	cache         fiberroutes.CachePolicy       // Cache-Control policy of the header routes, zero values for the defaults
+	tsCompat      bool                          // Serve the TypeScript chaintracks client routes at the root
+	debugRoutes   bool                          // Serve /debug/pprof and /debug/vars on the main port
+	cdnServe      bool                          // Serve the complete local header files under /headers
+	prom          *prometheusMetrics            // nil unless Prometheus metrics are enabled
+	staleTip      *chaintracks.StaleTipWatchdog // nil unless the stale-tip watchdog is enabled
+	fallback      *chaintracks.PollFallback     // nil unless the P2P polling fallback is enabled
+	auth          *jwtAuth                      // nil unless JWT auth is enabled
+	mutualAuth    *mutualAuth                   // nil unless BRC-103 mutual auth is enabled
+	adminToken    string                        // Static bearer token for the /admin group, empty to rely on JWT auth
+	bootstrapURLs []string                      // Default sources for /admin/resync
+	resyncing     atomic.Bool                   // Set while an /admin/resync is running
+	logger        chaintracks.Logger
 }
 
 // NewServer creates a new API server
@@ -37,78 +59,204 @@ func NewServer(ctx context.Context, cm *chaintracks.ChainManager) *Server {
 	return &Server{
 		ctx:        ctx,
 		cm:         cm,
-		sseClients: make(map[int64]*bufio.Writer),
+		sseClients: make(map[int64]*sseClient),
+		sseReplay:  maxSSEReplay,
+		logger:     slog.Default(),
 	}
 }
 
-// StartBroadcasting listens to ChainManager tip changes and broadcasts to all SSE clients
-func (s *Server) StartBroadcasting(ctx context.Context, tipChan <-chan *chaintracks.BlockHeader) {
+// SSE event names emitted on /v2/tip/stream
+const (
+	sseEventTip          = "tip"
+	sseEventReorg        = "reorg"
+	sseEventSyncProgress = "sync-progress"
+)
+
+// StartBroadcasting listens to ChainManager events and broadcasts them to all SSE clients and tip waiters
+func (s *Server) StartBroadcasting(ctx context.Context, events <-chan *chaintracks.ChainEvent) {
 	go func() {
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case tip := <-tipChan:
-				if tip == nil {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event == nil || (event.Tip == nil && event.Lag == nil) {
 					continue
 				}
-				s.broadcastTip(tip)
+				s.broadcastEvent(event)
+				if event.Tip != nil {
+					s.notifyTipChange()
+				}
+				s.cm.RecordDelivery(event)
+				if s.prom != nil {
+					s.prom.observeEvent(event)
+				}
 			}
 		}
 	}()
 }
 
-// broadcastTip sends a tip update to all connected SSE clients
-func (s *Server) broadcastTip(tip *chaintracks.BlockHeader) {
-	data, err := json.Marshal(tip)
-	if err != nil {
-		return
+// broadcastEvent converts a chain event into named SSE events and sends them to all clients
+func (s *Server) broadcastEvent(event *chaintracks.ChainEvent) {
+	if message := formatEventSSE(event, false); message != "" {
+		s.broadcast(message, formatEventSSE(event, true))
 	}
+}
 
-	sseMessage := fmt.Sprintf("data: %s\n\n", string(data))
-
+// broadcast queues a preformatted SSE message for all connected clients, compactMessage for those that asked
+// for compact payloads
+// Queuing never blocks, so a slow client cannot hold up the others; it loses its oldest messages instead.
+func (s *Server) broadcast(sseMessage, compactMessage string) {
 	s.sseClientsMu.RLock()
-	clientsCopy := make(map[int64]*bufio.Writer, len(s.sseClients))
-	for id, writer := range s.sseClients {
-		clientsCopy[id] = writer
+	defer s.sseClientsMu.RUnlock()
+
+	for _, client := range s.sseClients {
+		message := sseMessage
+		if client.compact {
+			message = compactMessage
+		}
+		if client.enqueue(message) {
+			s.sseDropped.Add(1)
+		}
 	}
-	s.sseClientsMu.RUnlock()
+}
 
-	var failedClients []int64
-	for id, writer := range clientsCopy {
-		if _, err := fmt.Fprint(writer, sseMessage); err != nil {
-			failedClients = append(failedClients, id)
-			continue
+// sseClientQueue is how many messages an SSE client may fall behind before the oldest are dropped
+const sseClientQueue = 64
+
+// sseClient is a connected /v2/tip/stream client, fed by the broadcaster and drained by its own writer
+type sseClient struct {
+	queue   chan string
+	compact bool // Send tips as height, hash and previous hash only
+}
+
+func newSSEClient(compact bool) *sseClient {
+	return &sseClient{queue: make(chan string, sseClientQueue), compact: compact}
+}
+
+// enqueue queues message without blocking, dropping the oldest queued message while the queue is full
+// It reports whether a message was dropped; a client that misses events can resume with Last-Event-ID.
+func (c *sseClient) enqueue(message string) bool {
+	dropped := false
+	for {
+		select {
+		case c.queue <- message:
+			return dropped
+		default:
 		}
-		if err := writer.Flush(); err != nil {
-			failedClients = append(failedClients, id)
+		select {
+		case <-c.queue:
+			dropped = true
+		default:
 		}
 	}
+}
 
-	if len(failedClients) > 0 {
-		s.sseClientsMu.Lock()
-		for _, id := range failedClients {
-			delete(s.sseClients, id)
+// formatEventSSE formats a chain event as SSE, using its sequence number as the event ID
+// A reorg is always followed by a tip event so clients that only track tips stay current;
+// the trailing tip carries no ID since it belongs to the same sequenced event. With compact set, tips are
+// sent as compactTip.
+func formatEventSSE(event *chaintracks.ChainEvent, compact bool) string {
+	id := ""
+	if event.Seq > 0 {
+		id = strconv.FormatUint(event.Seq, 10)
+	}
+
+	switch event.Type {
+	case chaintracks.EventReorg:
+		data, err := json.Marshal(event)
+		if err != nil {
+			return ""
 		}
-		s.sseClientsMu.Unlock()
+		tip, err := formatTipSSE(event.Tip, "", compact)
+		if err != nil {
+			return ""
+		}
+		return formatSSE(sseEventReorg, id, data) + tip
+	case chaintracks.EventSyncStatus, chaintracks.EventSyncProgress:
+		if event.Lag == nil {
+			return ""
+		}
+		data, err := json.Marshal(syncProgressSSE{LagStatus: *event.Lag, Progress: event.Progress})
+		if err != nil {
+			return ""
+		}
+		return formatSSE(sseEventSyncProgress, "", data)
+	case chaintracks.EventTipAdvanced:
+		tip, err := formatTipSSE(event.Tip, id, compact)
+		if err != nil {
+			return ""
+		}
+		return tip
+	}
+	return ""
+}
+
+// syncProgressSSE is the sync-progress payload: the lag status, plus the bulk sync when the event reports one
+type syncProgressSSE struct {
+	chaintracks.LagStatus
+	Progress *chaintracks.SyncProgress `json:"progress,omitempty"`
+}
+
+// compactTip is the tip payload of compact SSE streams, with the field names of the full header
+type compactTip struct {
+	Height   uint32         `json:"height"`
+	Hash     chainhash.Hash `json:"hash"`
+	PrevHash chainhash.Hash `json:"previousHash"`
+}
+
+// formatTipSSE formats a tip as a named SSE event, as a compactTip when compact is set
+func formatTipSSE(tip *chaintracks.BlockHeader, id string, compact bool) (string, error) {
+	var payload any = tip
+	if compact {
+		payload = compactTip{Height: tip.Height, Hash: tip.Hash, PrevHash: tip.PrevHash}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return formatSSE(sseEventTip, id, data), nil
+}
+
+// formatSSE formats a single Server-Sent Event; an empty id omits the id field
+func formatSSE(event, id string, data []byte) string {
+	var sb strings.Builder
+	sb.WriteString("event: ")
+	sb.WriteString(event)
+	sb.WriteString("\n")
+	if id != "" {
+		sb.WriteString("id: ")
+		sb.WriteString(id)
+		sb.WriteString("\n")
 	}
+	sb.WriteString("data: ")
+	sb.Write(data)
+	sb.WriteString("\n\n")
+	return sb.String()
 }
 
 // HandleTipStream handles SSE connections for tip updates
+// Clients passing fields=compact receive tips as height, hash and previous hash only.
 func (s *Server) HandleTipStream(c *fiber.Ctx) error {
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
 	c.Set("Connection", "keep-alive")
 	c.Set("Transfer-Encoding", "chunked")
 
-	// Capture context before entering stream writer
+	// Capture context and resume point before entering stream writer
 	ctx := c.UserContext()
+	lastEventID, resume := parseLastEventID(c.Get("Last-Event-ID"))
+	compact := c.Query("fields") == "compact"
 
 	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
 		clientID := time.Now().UnixNano()
+		client := newSSEClient(compact)
 
 		s.sseClientsMu.Lock()
-		s.sseClients[clientID] = w
+		s.sseClients[clientID] = client
 		s.sseClientsMu.Unlock()
 
 		defer func() {
@@ -117,22 +265,12 @@ func (s *Server) HandleTipStream(c *fiber.Ctx) error {
 			s.sseClientsMu.Unlock()
 		}()
 
-		// Send initial tip
-		tip := s.cm.GetTip(ctx)
-		if tip != nil { //nolint:nestif // SSE stream initialization logic
-
-			data, err := json.Marshal(tip)
-			if err == nil {
-				if _, writeErr := fmt.Fprintf(w, "data: %s\n\n", string(data)); writeErr != nil {
-					return
-				}
-				if flushErr := w.Flush(); flushErr != nil {
-					return
-				}
-			}
+		// Replay missed events when resuming, otherwise just the current tip
+		if err := s.sendInitialEvents(ctx, w, lastEventID, resume, compact); err != nil {
+			return
 		}
 
-		// Keep connection alive with periodic keepalive messages
+		// Write queued events as they arrive, with periodic keepalive messages in between
 		ticker := time.NewTicker(15 * time.Second)
 		defer ticker.Stop()
 
@@ -140,28 +278,82 @@ func (s *Server) HandleTipStream(c *fiber.Ctx) error {
 			select {
 			case <-s.ctx.Done():
 				return
-			case <-ticker.C:
-				if _, writeErr := fmt.Fprintf(w, ": keepalive\n\n"); writeErr != nil {
+			case message := <-client.queue:
+				if _, err := fmt.Fprint(w, message); err != nil {
 					return
 				}
-				if err := w.Flush(); err != nil {
+			case <-ticker.C:
+				if _, writeErr := fmt.Fprintf(w, ": keepalive\n\n"); writeErr != nil {
 					return
 				}
 			}
+			if err := w.Flush(); err != nil {
+				return
+			}
 		}
 	}))
 
 	return nil
 }
 
-// Response represents the standard API response format
-type Response struct {
-	Status      string      `json:"status"`
-	Value       interface{} `json:"value,omitempty"`
-	Code        string      `json:"code,omitempty"`
-	Description string      `json:"description,omitempty"`
+// maxSSEReplay is the default cap on missed events replayed to a resuming SSE client
+const maxSSEReplay = 1000
+
+// parseLastEventID parses the Last-Event-ID header sent by reconnecting SSE clients
+func parseLastEventID(value string) (uint64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// sendInitialEvents writes the events a client has not seen yet
+// A resuming client receives every sequenced event after lastEventID when the server still holds them
+// (capped at sseReplay); otherwise the client gets the current tip and detects the gap from its ID
+func (s *Server) sendInitialEvents(ctx context.Context, w *bufio.Writer, lastEventID uint64, resume, compact bool) error {
+	limit := s.sseReplay
+	if limit == 0 {
+		limit = maxSSEReplay
+	}
+
+	if resume {
+		events, ok := s.cm.EventsSince(lastEventID)
+		if ok && len(events) > 0 && len(events) <= int(limit) {
+			for _, event := range events {
+				if _, err := fmt.Fprint(w, formatEventSSE(event, compact)); err != nil {
+					return err
+				}
+			}
+			return w.Flush()
+		}
+	}
+
+	tip := s.cm.GetTip(ctx)
+	if tip == nil {
+		return nil
+	}
+
+	id := ""
+	if seq := s.cm.LastEventSeq(); seq > 0 {
+		id = strconv.FormatUint(seq, 10)
+	}
+	message, err := formatTipSSE(tip, id, compact)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(w, message); err != nil {
+		return err
+	}
+	return w.Flush()
 }
 
+// Response represents the standard API response format
+type Response = fiberroutes.Response
+
 // HandleRoot returns service identification
 func (s *Server) HandleRoot(c *fiber.Ctx) error {
 	return c.JSON(Response{
@@ -176,206 +368,52 @@ func (s *Server) HandleRobots(c *fiber.Ctx) error {
 	return c.SendString("User-agent: *\nDisallow: /\n")
 }
 
-// HandleGetNetwork returns the network name
-func (s *Server) HandleGetNetwork(c *fiber.Ctx) error {
-	network, err := s.cm.GetNetwork(c.UserContext())
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(Response{
-			Status: "error",
-			Value:  err.Error(),
-		})
+// HandleGetReorgs returns recently detected chain reorganizations, newest first
+func (s *Server) HandleGetReorgs(c *fiber.Ctx) error {
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: "Invalid limit parameter",
+			})
+		}
+		limit = l
 	}
-	return c.JSON(Response{
-		Status: "success",
-		Value:  network,
-	})
-}
 
-// HandleGetHeight returns the current blockchain height
-func (s *Server) HandleGetHeight(c *fiber.Ctx) error {
-	c.Set("Cache-Control", "public, max-age=60")
-	return c.JSON(Response{
-		Status: "success",
-		Value:  s.cm.GetHeight(c.UserContext()),
-	})
-}
-
-// HandleGetTipHash returns the chain tip hash
-func (s *Server) HandleGetTipHash(c *fiber.Ctx) error {
 	c.Set("Cache-Control", "no-cache")
-
-	tip := s.cm.GetTip(c.UserContext())
-	if tip == nil {
-		return c.Status(fiber.StatusNotFound).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_NO_TIP",
-			Description: "Chain tip not found",
-		})
-	}
-
-	hash := tip.Header.Hash()
 	return c.JSON(Response{
 		Status: "success",
-		Value:  &hash,
+		Value:  s.cm.GetReorgs(limit),
 	})
 }
 
-// HandleGetTipHeader returns the full chain tip header
-func (s *Server) HandleGetTipHeader(c *fiber.Ctx) error {
+// HandlePeers returns the connected P2P peers with their last-seen time and announcement latency
+func (s *Server) HandlePeers(c *fiber.Ctx) error {
 	c.Set("Cache-Control", "no-cache")
-
-	tip := s.cm.GetTip(c.UserContext())
-	if tip == nil {
-		return c.Status(fiber.StatusNotFound).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_NO_TIP",
-			Description: "Chain tip not found",
-		})
-	}
-
-	return c.JSON(Response{
-		Status: "success",
-		Value:  tip,
-	})
-}
-
-// HandleGetHeaderByHeight returns a header by height
-func (s *Server) HandleGetHeaderByHeight(c *fiber.Ctx) error {
-	heightStr := c.Params("height")
-	if heightStr == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_INVALID_PARAMS",
-			Description: "Missing height parameter",
-		})
-	}
-
-	height, err := strconv.ParseUint(heightStr, 10, 32)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_INVALID_PARAMS",
-			Description: "Invalid height parameter",
-		})
-	}
-
-	tip := s.cm.GetHeight(c.UserContext())
-	if uint32(height) < tip-100 {
-		c.Set("Cache-Control", "public, max-age=3600")
-	} else {
-		c.Set("Cache-Control", "no-cache")
-	}
-
-	header, err := s.cm.GetHeaderByHeight(c.UserContext(), uint32(height))
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_NOT_FOUND",
-			Description: "Header not found at height " + heightStr,
-		})
-	}
-
 	return c.JSON(Response{
 		Status: "success",
-		Value:  header,
+		Value:  s.cm.GetPeers(),
 	})
 }
 
-// HandleGetHeaderByHash returns a header by hash
-func (s *Server) HandleGetHeaderByHash(c *fiber.Ctx) error {
-	hashStr := c.Params("hash")
-	if hashStr == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_INVALID_PARAMS",
-			Description: "Missing hash parameter",
-		})
-	}
-
-	hash, err := chainhash.NewHashFromHex(hashStr)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_INVALID_PARAMS",
-			Description: "Invalid hash parameter",
-		})
-	}
-
-	header, err := s.cm.GetHeaderByHash(c.UserContext(), hash)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_NOT_FOUND",
-			Description: "Header not found for hash " + hashStr,
-		})
-	}
-
-	tip := s.cm.GetHeight(c.UserContext())
-	if header.Height < tip-100 {
-		c.Set("Cache-Control", "public, max-age=3600")
-	} else {
-		c.Set("Cache-Control", "no-cache")
-	}
-
+// HandleLatency returns the block propagation latency breakdown per pipeline stage
+func (s *Server) HandleLatency(c *fiber.Ctx) error {
+	c.Set("Cache-Control", "no-cache")
 	return c.JSON(Response{
 		Status: "success",
-		Value:  header,
+		Value:  s.cm.GetLatencyStats(),
 	})
 }
 
-// HandleGetHeaders returns multiple headers as concatenated hex
-func (s *Server) HandleGetHeaders(c *fiber.Ctx) error {
-	heightStr := c.Query("height")
-	countStr := c.Query("count")
-
-	if heightStr == "" || countStr == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_INVALID_PARAMS",
-			Description: "Missing height or count parameter",
-		})
-	}
-
-	height, err := strconv.ParseUint(heightStr, 10, 32)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_INVALID_PARAMS",
-			Description: "Invalid height parameter",
-		})
-	}
-
-	count, err := strconv.ParseUint(countStr, 10, 32)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(Response{
-			Status:      "error",
-			Code:        "ERR_INVALID_PARAMS",
-			Description: "Invalid count parameter",
-		})
-	}
-
-	tip := s.cm.GetHeight(c.UserContext())
-	if uint32(height) < tip-100 {
-		c.Set("Cache-Control", "public, max-age=3600")
-	} else {
-		c.Set("Cache-Control", "no-cache")
-	}
-
-	var hexData string
-	for i := uint32(0); i < uint32(count); i++ {
-		h := uint32(height) + i
-		header, err := s.cm.GetHeaderByHeight(c.UserContext(), h)
-		if err != nil {
-			break
-		}
-
-		headerBytes := header.Bytes()
-		hexData += hex.EncodeToString(headerBytes)
-	}
-
+// HandleMetrics returns the counters accumulated across restarts
+func (s *Server) HandleMetrics(c *fiber.Ctx) error {
+	c.Set("Cache-Control", "no-cache")
 	return c.JSON(Response{
 		Status: "success",
-		Value:  hexData,
+		Value:  s.cm.GetMetrics(),
 	})
 }
 
@@ -419,18 +457,61 @@ func (s *Server) HandleSwaggerUI(c *fiber.Ctx) error {
 
 // SetupRoutes configures all Fiber routes
 func (s *Server) SetupRoutes(app *fiber.App, dashboard *DashboardHandler) {
-	app.Get("/", dashboard.HandleStatus)
+	identity := s.requireIdentity()
+	read, admin := s.requireRole(roleRead), s.requireRole(roleAdmin)
+
+	app.Get("/", identity, admin, dashboard.HandleStatus)
 	app.Get("/robots.txt", s.HandleRobots)
 	app.Get("/docs", s.HandleSwaggerUI)
 	app.Get("/openapi.yaml", s.HandleOpenAPISpec)
+	app.Post("/rpc", identity, read, s.HandleRPC)
+	if s.prom != nil {
+		app.Get("/metrics", identity, admin, s.prom.handler())
+	}
+	if s.mutualAuth != nil {
+		app.Post("/.well-known/auth", s.mutualAuth.HandleAuth)
+	}
+	if s.debugRoutes {
+		app.Use("/debug", identity, admin, adaptor.HTTPHandler(newDebugMux()))
+	}
+	if s.cdnServe {
+		app.Get("/headers/:file", identity, read, s.HandleCDNFile)
+	}
 
+	routes := fiberroutes.NewRoutes(s.cm,
+		fiberroutes.WithMiddleware(identity, read),
+		fiberroutes.WithMaxHeaders(s.maxHeaders),
+		fiberroutes.WithCachePolicy(s.cache),
+	)
+	routes.RegisterBHS(app.Group("/api/v1"))
+	if s.tsCompat {
+		routes.RegisterTS(app)
+	}
+
+	// The SSE stream is never signed, since BRC-104 signs a complete response body
 	v2 := app.Group("/v2")
-	v2.Get("/network", s.HandleGetNetwork)
-	v2.Get("/height", s.HandleGetHeight)
-	v2.Get("/tip/hash", s.HandleGetTipHash)
-	v2.Get("/tip/header", s.HandleGetTipHeader)
-	v2.Get("/tip/stream", s.HandleTipStream)
-	v2.Get("/header/height/:height", s.HandleGetHeaderByHeight)
-	v2.Get("/header/hash/:hash", s.HandleGetHeaderByHash)
-	v2.Get("/headers", s.HandleGetHeaders)
+	routes.Register(v2)
+	v2.Get("/tip/stream", read, s.HandleTipStream)
+	v2.Get("/tip/wait", identity, read, s.HandleTipWait)
+	v2.Get("/reorgs", identity, read, s.HandleGetReorgs)
+	v2.Get("/peers", s.HandlePeers)
+	v2.Get("/debug/latency", identity, admin, s.HandleLatency)
+	v2.Get("/metrics", identity, read, s.HandleMetrics)
+	v2.Get("/status", identity, admin, s.HandleStatus)
+
+	// Recovery operations are never open; the group only exists when it can be authenticated
+	if s.adminEnabled() {
+		ops := app.Group("/admin", identity, s.requireAdmin())
+		ops.Post("/resync", s.HandleAdminResync)
+		ops.Post("/invalidate/:hash", s.HandleAdminInvalidate)
+		ops.Post("/reconsider/:hash", s.HandleAdminReconsider)
+		ops.Get("/invalidated", s.HandleAdminInvalidated)
+		ops.Post("/clear-orphans", s.HandleAdminClearOrphans)
+		ops.Post("/peers", s.HandleAdminConnectPeer)
+		ops.Delete("/peers/:id", s.HandleAdminBanPeer)
+		if s.isRegtest() {
+			ops.Post("/regtest/mine", s.HandleRegtestMine)
+			ops.Post("/regtest/reorg", s.HandleRegtestReorg)
+		}
+	}
 }
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	fiberroutes "github.com/bsv-blockchain/go-chaintracks/routes/fiber"
 )
 
 func TestHandleGetNetwork(t *testing.T) {
@@ -170,6 +173,19 @@ func TestHandleGetHeaders_MissingParams(t *testing.T) {
 	requireErrorResponse(t, resp.Body)
 }
 
+func TestHandleGetHeaders_OutOfRange(t *testing.T) {
+	app, cm := setupTestApp(t)
+	tip := cm.GetHeight(t.Context())
+
+	resp := httpGet(t, app, fmt.Sprintf("/v2/headers?height=%d&count=1", tip+1))
+	requireStatus(t, resp, 416)
+	requireErrorResponse(t, resp.Body)
+
+	resp = httpGet(t, app, fmt.Sprintf("/v2/headers?height=0&count=%d", fiberroutes.DefaultMaxHeaders+1))
+	requireStatus(t, resp, 400)
+	requireErrorResponse(t, resp.Body)
+}
+
 func TestHandleRobots(t *testing.T) {
 	app, _ := setupTestApp(t)
 
@@ -202,3 +218,77 @@ func TestHandleSwaggerUI(t *testing.T) {
 	assert.Contains(t, bodyStr, "Chaintracks API Documentation", "Expected title")
 	assert.Contains(t, bodyStr, "/openapi.yaml", "Expected openapi.yaml reference")
 }
+
+func TestHandleGetReorgs(t *testing.T) {
+	app, _ := setupTestApp(t)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "ReturnsEmptyHistory", path: "/v2/reorgs", expectedStatus: 200},
+		{name: "AcceptsLimit", path: "/v2/reorgs?limit=5", expectedStatus: 200},
+		{name: "RejectsZeroLimit", path: "/v2/reorgs?limit=0", expectedStatus: 400},
+		{name: "RejectsInvalidLimit", path: "/v2/reorgs?limit=abc", expectedStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httpGet(t, app, tt.path)
+			requireStatus(t, resp, tt.expectedStatus)
+			if tt.expectedStatus == 200 {
+				var response struct {
+					Status string                    `json:"status"`
+					Value  []chaintracks.ReorgRecord `json:"value"`
+				}
+				parseJSONResponse(t, resp.Body, &response)
+				assert.Equal(t, "success", response.Status)
+				assert.Empty(t, response.Value)
+			} else {
+				requireErrorResponse(t, resp.Body)
+			}
+		})
+	}
+}
+
+func TestHandleLatency(t *testing.T) {
+	cm := newSyntheticChainManager(t, 1)
+	s := &Server{cm: cm}
+
+	app := fiber.New()
+	app.Get("/v2/debug/latency", s.HandleLatency)
+
+	resp := httpGet(t, app, "/v2/debug/latency")
+	requireStatus(t, resp, 200)
+	assert.Equal(t, "no-cache", resp.Headers["Cache-Control"])
+
+	var response struct {
+		Status string                                                  `json:"status"`
+		Value  map[chaintracks.LatencyStage]chaintracks.LatencySummary `json:"value"`
+	}
+	parseJSONResponse(t, resp.Body, &response)
+	assert.Equal(t, "success", response.Status)
+	assert.Empty(t, response.Value, "synthetic headers carry no P2P trace")
+}
+
+func TestHandleMetrics(t *testing.T) {
+	cm := newSyntheticChainManager(t, 1)
+	s := &Server{cm: cm}
+
+	app := fiber.New()
+	app.Get("/v2/metrics", s.HandleMetrics)
+
+	resp := httpGet(t, app, "/v2/metrics")
+	requireStatus(t, resp, 200)
+	assert.Equal(t, "no-cache", resp.Headers["Cache-Control"])
+
+	var response struct {
+		Status string                      `json:"status"`
+		Value  chaintracks.MetricsSnapshot `json:"value"`
+	}
+	parseJSONResponse(t, resp.Body, &response)
+	assert.Equal(t, "success", response.Status)
+	assert.Equal(t, uint64(1), response.Value.Starts)
+	assert.Len(t, response.Value.Runs, 1, "the current run is always reported")
+}
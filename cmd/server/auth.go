@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Roles granted by the roles claim of a bearer token; admin implies read
+const (
+	roleRead  = "read"
+	roleAdmin = "admin"
+)
+
+const defaultRolesClaim = "roles"
+
+var (
+	errAuthConfig   = errors.New("set either AUTH_JWT_SECRET or AUTH_JWKS_URL, not both")
+	errMissingToken = errors.New("missing bearer token")
+	errMissingRole  = errors.New("token lacks required role")
+
+	errInvalidAdminToken = errors.New("missing or invalid admin token")
+)
+
+// AuthConfig configures JWT bearer-token auth; it is disabled when neither JWTSecret nor JWKSURL is set
+type AuthConfig struct {
+	JWTSecret  string // HMAC (HS256/384/512) shared secret
+	JWKSURL    string // URL of a JSON Web Key Set for RSA, ECDSA or EdDSA tokens, refreshed in the background
+	Issuer     string // Required iss claim, if set
+	Audience   string // Required aud claim, if set
+	RolesClaim string // Claim listing the token's roles, as an array or a space-separated string
+}
+
+// Enabled reports whether a verification key source is configured
+func (c AuthConfig) Enabled() bool {
+	return c.JWTSecret != "" || c.JWKSURL != ""
+}
+
+// jwtAuth verifies bearer tokens and extracts their roles
+type jwtAuth struct {
+	keyfunc    jwt.Keyfunc
+	parser     *jwt.Parser
+	rolesClaim string
+}
+
+// newJWTAuth builds a verifier from an enabled config; with a JWKS URL the key set is fetched before returning
+func newJWTAuth(ctx context.Context, config AuthConfig) (*jwtAuth, error) {
+	if config.JWTSecret != "" && config.JWKSURL != "" {
+		return nil, errAuthConfig
+	}
+
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(config.Audience))
+	}
+
+	auth := &jwtAuth{rolesClaim: config.RolesClaim}
+	if auth.rolesClaim == "" {
+		auth.rolesClaim = defaultRolesClaim
+	}
+
+	if config.JWTSecret != "" {
+		secret := []byte(config.JWTSecret)
+		auth.keyfunc = func(*jwt.Token) (any, error) { return secret, nil }
+		opts = append(opts, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
+	} else {
+		jwks, err := keyfunc.NewDefaultCtx(ctx, []string{config.JWKSURL})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWKS from %s: %w", config.JWKSURL, err)
+		}
+		auth.keyfunc = jwks.Keyfunc
+		opts = append(opts, jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}))
+	}
+
+	auth.parser = jwt.NewParser(opts...)
+	return auth, nil
+}
+
+// roles verifies a token and returns the roles it grants
+func (a *jwtAuth) roles(tokenString string) ([]string, error) {
+	if tokenString == "" {
+		return nil, errMissingToken
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(tokenString, claims, a.keyfunc); err != nil {
+		return nil, err
+	}
+
+	switch value := claims[a.rolesClaim].(type) {
+	case string:
+		return strings.Fields(value), nil
+	case []any:
+		roles := make([]string, 0, len(value))
+		for _, role := range value {
+			if s, ok := role.(string); ok {
+				roles = append(roles, s)
+			}
+		}
+		return roles, nil
+	default:
+		return nil, nil
+	}
+}
+
+// authorize checks that a token grants role
+func (a *jwtAuth) authorize(tokenString, role string) error {
+	roles, err := a.roles(tokenString)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(roles, role) || slices.Contains(roles, roleAdmin) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errMissingRole, role)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "test-secret"
+
+// signHMAC returns an HS256 token for testJWTSecret with the given claims and a one-hour expiry
+func signHMAC(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	return token
+}
+
+// setupAuthApp creates an app over a synthetic chain with HMAC auth enabled
+func setupAuthApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	auth, err := newJWTAuth(t.Context(), AuthConfig{JWTSecret: testJWTSecret})
+	require.NoError(t, err)
+
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 3))
+	server.auth = auth
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	return app
+}
+
+// authGet performs a GET request with an optional bearer token and returns the status code
+func authGet(t *testing.T, app *fiber.App, path, token string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRequireRole(t *testing.T) {
+	app := setupAuthApp(t)
+	readToken := signHMAC(t, jwt.MapClaims{"roles": []string{roleRead}})
+	adminToken := signHMAC(t, jwt.MapClaims{"roles": roleAdmin})
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		expected int
+	}{
+		{"PublicWithoutToken", "/openapi.yaml", "", http.StatusOK},
+		{"ReadWithoutToken", "/v2/height", "", http.StatusUnauthorized},
+		{"ReadWithReadRole", "/v2/height", readToken, http.StatusOK},
+		{"ReadWithAdminRole", "/v2/height", adminToken, http.StatusOK},
+		{"BHSWithReadRole", "/api/v1/chain/tip/longest", readToken, http.StatusOK},
+		{"AdminWithReadRole", "/v2/status", readToken, http.StatusForbidden},
+		{"AdminWithAdminRole", "/v2/status", adminToken, http.StatusOK},
+		{"DashboardWithReadRole", "/", readToken, http.StatusForbidden},
+		{"InvalidToken", "/v2/height", "not-a-jwt", http.StatusUnauthorized},
+		{"WrongSecret", "/v2/height", func() string {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"roles": roleAdmin,
+				"exp":   time.Now().Add(time.Hour).Unix(),
+			}).SignedString([]byte("other"))
+			require.NoError(t, err)
+			return token
+		}(), http.StatusUnauthorized},
+		{"NoRoles", "/v2/height", signHMAC(t, jwt.MapClaims{"sub": "alice"}), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, authGet(t, app, tt.path, tt.token))
+		})
+	}
+
+	t.Run("QueryToken", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, authGet(t, app, "/v2/height?access_token="+readToken, ""))
+	})
+}
+
+func TestRequireRoleWithoutAuth(t *testing.T) {
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 3))
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+
+	assert.Equal(t, http.StatusOK, authGet(t, app, "/v2/status", ""))
+}
+
+func TestJWTAuthClaims(t *testing.T) {
+	t.Run("ExpiryRequired", func(t *testing.T) {
+		auth, err := newJWTAuth(t.Context(), AuthConfig{JWTSecret: testJWTSecret})
+		require.NoError(t, err)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"roles": roleRead}).SignedString([]byte(testJWTSecret))
+		require.NoError(t, err)
+		require.Error(t, auth.authorize(token, roleRead))
+	})
+
+	t.Run("IssuerAndAudience", func(t *testing.T) {
+		auth, err := newJWTAuth(t.Context(), AuthConfig{JWTSecret: testJWTSecret, Issuer: "issuer", Audience: "chaintracks"})
+		require.NoError(t, err)
+
+		require.NoError(t, auth.authorize(signHMAC(t, jwt.MapClaims{"iss": "issuer", "aud": "chaintracks", "roles": roleRead}), roleRead))
+		require.Error(t, auth.authorize(signHMAC(t, jwt.MapClaims{"iss": "other", "aud": "chaintracks", "roles": roleRead}), roleRead))
+		require.Error(t, auth.authorize(signHMAC(t, jwt.MapClaims{"iss": "issuer", "roles": roleRead}), roleRead))
+	})
+
+	t.Run("CustomRolesClaim", func(t *testing.T) {
+		auth, err := newJWTAuth(t.Context(), AuthConfig{JWTSecret: testJWTSecret, RolesClaim: "scope"})
+		require.NoError(t, err)
+
+		require.NoError(t, auth.authorize(signHMAC(t, jwt.MapClaims{"scope": "openid read"}), roleRead))
+		require.ErrorIs(t, auth.authorize(signHMAC(t, jwt.MapClaims{"roles": roleRead}), roleRead), errMissingRole)
+	})
+
+	t.Run("SecretAndJWKS", func(t *testing.T) {
+		_, err := newJWTAuth(t.Context(), AuthConfig{JWTSecret: testJWTSecret, JWKSURL: "http://localhost/jwks.json"})
+		require.ErrorIs(t, err, errAuthConfig)
+	})
+}
+
+func TestJWTAuthJWKS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks := `{"keys":[{"kty":"EC","crv":"P-256","kid":"test","alg":"ES256","use":"sig","x":"` +
+		encode(key.X.FillBytes(make([]byte, 32))) + `","y":"` + encode(key.Y.FillBytes(make([]byte, 32))) + `"}]}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(jwks))
+	}))
+	defer srv.Close()
+
+	auth, err := newJWTAuth(t.Context(), AuthConfig{JWKSURL: srv.URL})
+	require.NoError(t, err)
+
+	sign := func(method jwt.SigningMethod, signingKey any) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"roles": []string{roleAdmin}, "exp": time.Now().Add(time.Hour).Unix()})
+		token.Header["kid"] = "test"
+		signed, err := token.SignedString(signingKey)
+		require.NoError(t, err)
+		return signed
+	}
+
+	require.NoError(t, auth.authorize(sign(jwt.SigningMethodES256, key), roleAdmin))
+
+	t.Run("RejectsHMAC", func(t *testing.T) {
+		require.Error(t, auth.authorize(sign(jwt.SigningMethodHS256, []byte(testJWTSecret)), roleAdmin))
+	})
+}
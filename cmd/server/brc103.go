@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/bsv-blockchain/go-sdk/auth"
+	"github.com/bsv-blockchain/go-sdk/auth/authpayload"
+	"github.com/bsv-blockchain/go-sdk/auth/brc104"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/wallet"
+	"github.com/gofiber/fiber/v2"
+)
+
+// identityKeyLocal is the fiber.Ctx local holding the caller's verified identity key
+const identityKeyLocal = "brc103IdentityKey"
+
+var (
+	errMissingAuthHeaders = errors.New("missing BRC-104 auth headers")
+	errNoAuthReply        = errors.New("no BRC-104 request in progress")
+	errNoAuthHandler      = errors.New("peer has not registered a message handler")
+	errInvalidRequestID   = errors.New("invalid request ID")
+	errIdentityNotAllowed = errors.New("identity key not allowed")
+)
+
+// MutualAuthConfig configures BRC-103 mutual authentication; it is disabled when PrivateKey is empty
+type MutualAuthConfig struct {
+	PrivateKey           string   // Server identity key, hex or WIF
+	AllowedKeys          []string // Identity public keys (hex) allowed to authenticate, empty allows any
+	AllowUnauthenticated bool     // Serve requests without BRC-104 headers instead of rejecting them
+}
+
+// mutualAuth implements the server side of BRC-103 over the BRC-104 HTTP transport
+// Handshake messages arrive on POST /.well-known/auth; general messages are ordinary requests carrying
+// x-bsv-auth-* headers, and their responses are signed the same way.
+type mutualAuth struct {
+	// The SDK peer updates shared session state without locking, so calls into it are serialized
+	peerMu               sync.Mutex
+	peer                 *auth.Peer
+	identity             *ec.PublicKey
+	transport            *brc104Transport
+	allowed              []string
+	allowUnauthenticated bool
+}
+
+// newMutualAuth creates the server's BRC-103 peer from its identity key
+func newMutualAuth(config MutualAuthConfig) (*mutualAuth, error) {
+	key, err := ec.PrivateKeyFromHex(config.PrivateKey)
+	if err != nil {
+		if key, err = ec.PrivateKeyFromWif(config.PrivateKey); err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+	}
+
+	w, err := wallet.NewCompletedProtoWallet(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+
+	allowed := make([]string, 0, len(config.AllowedKeys))
+	for _, k := range config.AllowedKeys {
+		pub, err := ec.PublicKeyFromString(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed identity key %s: %w", k, err)
+		}
+		allowed = append(allowed, pub.ToDERHex())
+	}
+
+	// Sessions are looked up per request, so the peer must not fall back to the last caller
+	autoPersist := false
+	transport := &brc104Transport{}
+	return &mutualAuth{
+		peer: auth.NewPeer(&auth.PeerOptions{
+			Wallet:                 w,
+			Transport:              transport,
+			AutoPersistLastSession: &autoPersist,
+		}),
+		identity:             key.PubKey(),
+		transport:            transport,
+		allowed:              allowed,
+		allowUnauthenticated: config.AllowUnauthenticated,
+	}, nil
+}
+
+// identityKey returns the server's identity public key, which clients see in the handshake
+func (m *mutualAuth) identityKey() string {
+	return m.identity.ToDERHex()
+}
+
+// brc104Reply collects the message the peer sends back while handling one HTTP request
+type brc104Reply struct {
+	message *auth.AuthMessage
+}
+
+type brc104ReplyKey struct{}
+
+// brc104Transport hands incoming messages to the peer and captures its reply for the current request
+type brc104Transport struct {
+	mu     sync.Mutex
+	onData func(context.Context, *auth.AuthMessage) error
+}
+
+// OnData implements auth.Transport
+func (t *brc104Transport) OnData(callback func(context.Context, *auth.AuthMessage) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onData = callback
+	return nil
+}
+
+// GetRegisteredOnData implements auth.Transport
+func (t *brc104Transport) GetRegisteredOnData() (func(context.Context, *auth.AuthMessage) error, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.onData == nil {
+		return nil, errNoAuthHandler
+	}
+	return t.onData, nil
+}
+
+// Send implements auth.Transport, recording the message as the reply to the request in ctx
+func (t *brc104Transport) Send(ctx context.Context, message *auth.AuthMessage) error {
+	reply, ok := ctx.Value(brc104ReplyKey{}).(*brc104Reply)
+	if !ok {
+		return errNoAuthReply
+	}
+	reply.message = message
+	return nil
+}
+
+// receive passes a message to the peer and returns its reply, if any
+func (t *brc104Transport) receive(ctx context.Context, message *auth.AuthMessage) (*auth.AuthMessage, error) {
+	onData, err := t.GetRegisteredOnData()
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &brc104Reply{}
+	if err := onData(context.WithValue(ctx, brc104ReplyKey{}, reply), message); err != nil {
+		return nil, err
+	}
+	return reply.message, nil
+}
+
+// HandleAuth handles POST /.well-known/auth, where clients perform the BRC-103 handshake
+func (m *mutualAuth) HandleAuth(c *fiber.Ctx) error {
+	var message auth.AuthMessage
+	if err := json.Unmarshal(c.Body(), &message); err != nil {
+		return authError(c, fiber.StatusBadRequest, fmt.Errorf("invalid auth message: %w", err))
+	}
+	if message.IdentityKey == nil {
+		return authError(c, fiber.StatusBadRequest, auth.ErrInvalidMessage)
+	}
+	if !m.isAllowed(message.IdentityKey) {
+		return authError(c, fiber.StatusForbidden, errIdentityNotAllowed)
+	}
+
+	m.peerMu.Lock()
+	reply, err := m.transport.receive(c.UserContext(), &message)
+	m.peerMu.Unlock()
+	if err != nil {
+		return authError(c, fiber.StatusUnauthorized, err)
+	}
+	if reply == nil {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	return c.JSON(reply)
+}
+
+// middleware verifies the request signature, runs the route and signs its response
+func (m *mutualAuth) middleware(c *fiber.Ctx) error {
+	if c.Get(brc104.HeaderVersion) == "" {
+		if m.allowUnauthenticated {
+			return c.Next()
+		}
+		c.Set(fiber.HeaderWWWAuthenticate, "BRC-104")
+		return authError(c, fiber.StatusUnauthorized, errMissingAuthHeaders)
+	}
+
+	message, requestID, err := m.requestMessage(c)
+	if err != nil {
+		return authError(c, fiber.StatusBadRequest, err)
+	}
+	if !m.isAllowed(message.IdentityKey) {
+		return authError(c, fiber.StatusForbidden, errIdentityNotAllowed)
+	}
+	m.peerMu.Lock()
+	_, err = m.transport.receive(c.UserContext(), message)
+	m.peerMu.Unlock()
+	if err != nil {
+		return authError(c, fiber.StatusUnauthorized, err)
+	}
+	c.Locals(identityKeyLocal, message.IdentityKey)
+
+	if err := c.Next(); err != nil {
+		if err := c.App().ErrorHandler(c, err); err != nil {
+			return err
+		}
+	}
+	return m.signResponse(c, message.IdentityKey, requestID)
+}
+
+// requestMessage rebuilds the signed general message from the request's BRC-104 headers
+func (m *mutualAuth) requestMessage(c *fiber.Ctx) (*auth.AuthMessage, []byte, error) {
+	identityKey, err := ec.PublicKeyFromString(c.Get(brc104.HeaderIdentityKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid identity key: %w", err)
+	}
+	signature, err := hex.DecodeString(c.Get(brc104.HeaderSignature))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	requestID, err := base64.StdEncoding.DecodeString(c.Get(brc104.HeaderRequestID))
+	if err != nil || len(requestID) != brc104.RequestIDLength {
+		return nil, nil, errInvalidRequestID
+	}
+
+	req := &http.Request{
+		Method: c.Method(),
+		URL:    &url.URL{Path: c.Path(), RawQuery: string(c.Request().URI().QueryString())},
+		Header: make(http.Header),
+		Body:   io.NopCloser(bytes.NewReader(c.Body())),
+	}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		req.Header.Add(string(key), string(value))
+	})
+	payload, err := authpayload.FromHTTPRequest(requestID, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &auth.AuthMessage{
+		Version:     c.Get(brc104.HeaderVersion),
+		MessageType: auth.MessageTypeGeneral,
+		IdentityKey: identityKey,
+		Nonce:       c.Get(brc104.HeaderNonce),
+		YourNonce:   c.Get(brc104.HeaderYourNonce),
+		Signature:   signature,
+		Payload:     payload,
+	}, requestID, nil
+}
+
+// signResponse signs the finished response and adds the BRC-104 headers the client verifies
+func (m *mutualAuth) signResponse(c *fiber.Ctx, identityKey *ec.PublicKey, requestID []byte) error {
+	res := c.Response()
+	header := make(http.Header)
+	res.Header.VisitAll(func(key, value []byte) {
+		header.Add(string(key), string(value))
+	})
+	payload, err := authpayload.FromResponse(requestID, authpayload.SimplifiedHttpResponse{
+		StatusCode: res.StatusCode(),
+		Header:     header,
+		Body:       res.Body(),
+	})
+	if err != nil {
+		return err
+	}
+
+	reply := &brc104Reply{}
+	m.peerMu.Lock()
+	err = m.peer.ToPeer(context.WithValue(c.UserContext(), brc104ReplyKey{}, reply), payload, identityKey, 0)
+	m.peerMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to sign response: %w", err)
+	}
+	if reply.message == nil {
+		return errNoAuthReply
+	}
+
+	c.Set(brc104.HeaderVersion, reply.message.Version)
+	c.Set(brc104.HeaderMessageType, string(reply.message.MessageType))
+	c.Set(brc104.HeaderIdentityKey, reply.message.IdentityKey.ToDERHex())
+	c.Set(brc104.HeaderNonce, reply.message.Nonce)
+	c.Set(brc104.HeaderYourNonce, reply.message.YourNonce)
+	c.Set(brc104.HeaderSignature, hex.EncodeToString(reply.message.Signature))
+	c.Set(brc104.HeaderRequestID, base64.StdEncoding.EncodeToString(requestID))
+	return nil
+}
+
+// isAllowed reports whether an identity key may authenticate
+func (m *mutualAuth) isAllowed(identityKey *ec.PublicKey) bool {
+	return len(m.allowed) == 0 || slices.Contains(m.allowed, identityKey.ToDERHex())
+}
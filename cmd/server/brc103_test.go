@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/wallet"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// newTestWallet returns a wallet for a random identity key
+func newTestWallet(t *testing.T) (wallet.Interface, *ec.PublicKey) {
+	t.Helper()
+
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	w, err := wallet.NewCompletedProtoWallet(key)
+	require.NoError(t, err)
+	return w, key.PubKey()
+}
+
+// setupMutualAuthApp serves a synthetic chain with BRC-103 auth enabled and returns its base URL
+func setupMutualAuthApp(t *testing.T, config MutualAuthConfig) (string, *Server) {
+	t.Helper()
+
+	serverKey, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	config.PrivateKey = serverKey.Hex()
+	mutual, err := newMutualAuth(config)
+	require.NoError(t, err)
+
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 3))
+	server.mutualAuth = mutual
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	return listenTestApp(t, app), server
+}
+
+func TestMutualAuth(t *testing.T) {
+	url, server := setupMutualAuthApp(t, MutualAuthConfig{})
+
+	w, _ := newTestWallet(t)
+	client := chaintracks.NewClient(url)
+	require.NoError(t, client.SetWallet(w))
+
+	network, err := client.GetNetwork(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "test", network)
+
+	header, err := client.GetHeaderByHeight(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), header.Height)
+
+	_, err = client.GetHeaderByHeight(t.Context(), 100)
+	require.ErrorIs(t, err, chaintracks.ErrServerRequestFailed)
+
+	t.Run("RejectsUnauthenticated", func(t *testing.T) {
+		resp, err := http.Get(url + "/v2/network") //nolint:noctx // Test request
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("PublicRoutes", func(t *testing.T) {
+		resp, err := http.Get(url + "/openapi.yaml") //nolint:noctx // Test request
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("RequestBody", func(t *testing.T) {
+		headers, err := client.GetHeadersByHashes(t.Context(), []chainhash.Hash{{1}, {9}})
+		require.NoError(t, err)
+		require.Len(t, headers, 2)
+		require.NotNil(t, headers[0])
+		assert.Equal(t, uint32(1), headers[0].Height)
+		assert.Nil(t, headers[1])
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 8)
+		for i := range 8 {
+			wg.Go(func() {
+				_, err := client.GetHeaderByHeight(t.Context(), uint32(i%3)) //nolint:gosec // Test data
+				errs <- err
+			})
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("TipStreamRequiresIdentity", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, tipStreamStatus(t, url))
+	})
+
+	t.Run("IdentityGrantsReadRole", func(t *testing.T) {
+		auth, err := newJWTAuth(t.Context(), AuthConfig{JWTSecret: testJWTSecret})
+		require.NoError(t, err)
+		server.auth = auth
+
+		_, err = client.GetHeaderByHeight(t.Context(), 1)
+		require.NoError(t, err)
+	})
+}
+
+func TestMutualAuthAllowedKeys(t *testing.T) {
+	allowedWallet, allowedKey := newTestWallet(t)
+	url, _ := setupMutualAuthApp(t, MutualAuthConfig{AllowedKeys: []string{allowedKey.ToDERHex()}})
+
+	client := chaintracks.NewClient(url)
+	require.NoError(t, client.SetWallet(allowedWallet))
+	_, err := client.GetNetwork(t.Context())
+	require.NoError(t, err)
+
+	otherWallet, _ := newTestWallet(t)
+	other := chaintracks.NewClient(url)
+	require.NoError(t, other.SetWallet(otherWallet))
+	_, err = other.GetNetwork(t.Context())
+	require.Error(t, err)
+}
+
+func TestMutualAuthAllowUnauthenticated(t *testing.T) {
+	url, _ := setupMutualAuthApp(t, MutualAuthConfig{AllowUnauthenticated: true})
+
+	network, err := chaintracks.NewClient(url).GetNetwork(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "test", network)
+	assert.Equal(t, http.StatusOK, tipStreamStatus(t, url))
+}
+
+// tipStreamStatus opens the SSE tip stream without credentials and returns the response status
+func tipStreamStatus(t *testing.T, url string) int {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/v2/tip/stream", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestNewMutualAuthErrors(t *testing.T) {
+	_, err := newMutualAuth(MutualAuthConfig{PrivateKey: "not-a-key"})
+	require.Error(t, err)
+
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	_, err = newMutualAuth(MutualAuthConfig{PrivateKey: key.Wif(), AllowedKeys: []string{"zz"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleCDNFile serves local storage as a CDN origin under /headers, so other instances can list this server
+// in CDN_URLS
+// <network>NetBlockHeaders.json lists the complete header files, and each listed file is served with range
+// request support. The file holding the tip is not served; see ChainManager.ServedCDNMetadata.
+func (s *Server) HandleCDNFile(c *fiber.Ctx) error {
+	metadata, err := s.cm.ServedCDNMetadata()
+	if err != nil || len(metadata.Files) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_FOUND",
+			Description: "No complete header files to serve",
+		})
+	}
+
+	name := c.Params("file")
+	if name == metadata.JSONFilename {
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		return c.JSON(metadata)
+	}
+
+	for _, entry := range metadata.Files {
+		if filepath.Base(entry.FileName) != name {
+			continue
+		}
+		// A reorg reaching into a complete file rewrites it, so clients revalidate against Last-Modified
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		if err := c.SendFile(filepath.Join(s.cm.GetStoragePath(), name)); err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+		return nil
+	}
+
+	return c.Status(fiber.StatusNotFound).JSON(Response{
+		Status:      "error",
+		Code:        "ERR_NOT_FOUND",
+		Description: "Header file not found: " + name,
+	})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// setupCDNServeApp serves a 10-header chain whose storage metadata lists its one file as complete, followed
+// by a tip file still being written
+func setupCDNServeApp(t *testing.T) (*fiber.App, []byte) {
+	t.Helper()
+
+	cm := newSyntheticChainManager(t, 10)
+	data, err := os.ReadFile(filepath.Join(cm.GetStoragePath(), "testNet_0.headers")) //nolint:gosec // Test code: path is from t.TempDir()
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+
+	metadata, err := json.Marshal(chaintracks.CDNMetadata{
+		JSONFilename:   "testNetBlockHeaders.json",
+		HeadersPerFile: 10,
+		Files: []chaintracks.CDNFileEntry{
+			{Chain: "test", FileName: "testNet_0.headers", Count: 10, FileHash: base64.StdEncoding.EncodeToString(sum[:])},
+			{Chain: "test", FileName: "testNet_1.headers", Count: 2, FirstHeight: 10},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cm.GetStoragePath(), "testNetBlockHeaders.json"), metadata, 0o600))
+
+	server := NewServer(t.Context(), cm)
+	server.cdnServe = true
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	return app, data
+}
+
+func TestHandleCDNFile(t *testing.T) {
+	app, data := setupCDNServeApp(t)
+
+	t.Run("Metadata", func(t *testing.T) {
+		resp := httpGet(t, app, "/headers/testNetBlockHeaders.json")
+		requireStatus(t, resp, http.StatusOK)
+
+		var metadata chaintracks.CDNMetadata
+		parseJSONResponse(t, resp.Body, &metadata)
+		require.Len(t, metadata.Files, 1, "the tip file is not served")
+		assert.Equal(t, "testNet_0.headers", metadata.Files[0].FileName)
+	})
+
+	t.Run("HeaderFile", func(t *testing.T) {
+		resp := httpGet(t, app, "/headers/testNet_0.headers")
+		requireStatus(t, resp, http.StatusOK)
+		assert.Equal(t, fiber.MIMEOctetStream, resp.Headers[fiber.HeaderContentType])
+		assert.Equal(t, data, resp.Body)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/headers/testNet_0.headers", nil)
+		req.Header.Set(fiber.HeaderRange, "bytes=80-159")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Equal(t, data[80:160], body)
+	})
+
+	for _, path := range []string{"/headers/testNet_1.headers", "/headers/peer_book.json", "/headers/mainNetBlockHeaders.json"} {
+		t.Run("NotServed"+filepath.Base(path), func(t *testing.T) {
+			requireStatus(t, httpGet(t, app, path), http.StatusNotFound)
+		})
+	}
+}
+
+func TestHandleCDNFileDisabled(t *testing.T) {
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 10))
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+
+	requireStatus(t, httpGet(t, app, "/headers/testNetBlockHeaders.json"), http.StatusNotFound)
+}
+
+func TestDownloadCDNHeadersFromServer(t *testing.T) {
+	app, data := setupCDNServeApp(t)
+	baseURL := listenTestApp(t, app)
+
+	dest := t.TempDir()
+	require.NoError(t, chaintracks.DownloadCDNHeaders(t.Context(), baseURL+"/headers", "test", dest))
+
+	downloaded, err := os.ReadFile(filepath.Join(dest, "testNet_0.headers")) //nolint:gosec // Test code: path is from t.TempDir()
+	require.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// defaultCompressMinSize is the smallest response body compressed, below which the encoding overhead outweighs
+// the saving
+const defaultCompressMinSize = 1024
+
+// compressResponses encodes response bodies of at least minSize bytes with brotli, gzip, deflate or zstd,
+// whichever the client accepts first in that order
+// Streamed bodies are compressed when they declare a Content-Length, such as /v2/headers; streams of unknown
+// length, such as the SSE tip stream, are left alone so each event still reaches the client as it is written.
+func compressResponses(minSize int) fiber.Handler {
+	compress := fasthttp.CompressHandlerBrotliLevel(func(*fasthttp.RequestCtx) {},
+		fasthttp.CompressBrotliDefaultCompression,
+		fasthttp.CompressDefaultCompression,
+	)
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		resp := c.Response()
+		size := resp.Header.ContentLength()
+		if !resp.IsBodyStream() {
+			size = len(resp.Body())
+		}
+		if size < 0 || size < minSize {
+			return nil
+		}
+		compress(c.Context())
+		return nil
+	}
+}
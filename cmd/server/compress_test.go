@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressResponses(t *testing.T) {
+	large := strings.Repeat("00000020", 512)
+
+	app := fiber.New()
+	app.Use(compressResponses(defaultCompressMinSize))
+	app.Get("/small", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	app.Get("/large", func(c *fiber.Ctx) error {
+		return c.SendString(large)
+	})
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		c.Response().SetBodyStream(strings.NewReader(large), len(large))
+		return nil
+	})
+	app.Get("/events", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			_, _ = w.WriteString(large)
+		})
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		encoding   string
+		compressed bool
+		expected   string
+	}{
+		{name: "Large", path: "/large", encoding: "gzip", compressed: true, expected: large},
+		{name: "Stream", path: "/stream", encoding: "gzip", compressed: true, expected: large},
+		{name: "BelowMinSize", path: "/small", encoding: "gzip", expected: "ok"},
+		{name: "NotAccepted", path: "/large", expected: large},
+		{name: "UnknownLengthStream", path: "/events", encoding: "gzip", expected: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.encoding != "" {
+				req.Header.Set(fiber.HeaderAcceptEncoding, tt.encoding)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			if !tt.compressed {
+				assert.Empty(t, resp.Header.Get(fiber.HeaderContentEncoding))
+				assert.Equal(t, tt.expected, string(body))
+				return
+			}
+
+			require.Equal(t, tt.encoding, resp.Header.Get(fiber.HeaderContentEncoding))
+			assert.Less(t, len(body), len(tt.expected))
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(zr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(decoded))
+		})
+	}
+}
@@ -2,23 +2,143 @@ package main
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	fiberroutes "github.com/bsv-blockchain/go-chaintracks/routes/fiber"
+)
+
+// defaultP2PFallbackSilence is how long P2P may stay silent before bootstrap URLs are polled
+const defaultP2PFallbackSilence = 10 * time.Minute
+
+// Header sources selectable with HEADER_SOURCE
+const (
+	headerSourceP2P      = "p2p"
+	headerSourceTeranode = "teranode"
 )
 
 // Config holds the server configuration
 type Config struct {
-	Port           int
-	Network        string
-	StoragePath    string
-	BootstrapURL   string
-	BootstrapPeers []string
+	Profile         string
+	Port            int
+	Network         string
+	StoragePath     string
+	BootstrapURLs   []string
+	BootstrapQuorum int // Bootstrap URLs that must agree on the tip, 0 for a majority of those answering
+	BootstrapPeers  []string
+	CDNURLs         []string
+	CDNDownload     chaintracks.CDNDownloadConfig
+	CDNServe        bool                   // Serve the complete local header files under /headers for other instances' CDN_URLS
+	Sync            chaintracks.SyncConfig // Catch-up sync batch size, workers and request window; zero values for the defaults
+	MemoryWindow    int                    // Most recent headers kept in memory, older ones are read from disk; 0 keeps all
+
+	// P2P host tuning; zero values keep the libp2p defaults
+	P2PPort           int
+	P2PAnnounceAddrs  []string
+	P2PMaxConnections int
+	P2PMinConnections int
+	P2PPortReuse      bool
+
+	// CDN artifact publishing (disabled when CDNPublishDir is empty), uploaded to CDNPublishS3 when its Bucket is set
+	CDNPublishDir      string
+	CDNPublishInterval time.Duration
+	CDNPublishS3       chaintracks.S3Config
+	CDNSigningKey      string // Hex or WIF private key the published metadata is signed with, empty to publish unsigned
+
+	// Where new block headers come from: "p2p" for the libp2p message bus or "teranode" for TeranodeURLs
+	HeaderSource string
+	TeranodeURLs []string // Teranode Asset Service API base URLs, the first preferred and the rest fallbacks
+
+	// Time without P2P block messages before BootstrapURLs are polled for catch-up, 0 disables the fallback
+	P2PFallbackSilence time.Duration
+
+	// BSV nodes to follow over the Bitcoin P2P protocol, host or host:port (disabled when empty)
+	WireNodes []string
+
+	// Poll WhatsOnChain for headers while no P2P peers are connected; ignored when BootstrapURLs are set
+	WhatsOnChainSync bool
+
+	// Chaintracks servers to mirror as a read replica, the first preferred and the rest fallbacks (disabled when empty)
+	MirrorURLs []string
+
+	// Divergence watchdog (disabled when WatchdogReference is empty)
+	WatchdogReference string // "whatsonchain" or the URL of another chaintracks server
+	WatchdogInterval  time.Duration
+	WhatsOnChainKey   string
+
+	// Kafka event sink (disabled when Kafka.Brokers is empty)
+	Kafka KafkaConfig
+
+	// NATS event sink (disabled when NATS.URL is empty)
+	NATS NATSConfig
+
+	// ZMQEndpoint publishes bitcoind-style hashblock/rawheader notifications, e.g. tcp://0.0.0.0:28332
+	ZMQEndpoint string
+
+	// MQTT tip publisher (disabled when MQTT.BrokerURL is empty)
+	MQTT MQTTConfig
+
+	// Redis publisher (disabled when Redis.URL is empty)
+	Redis RedisConfig
+
+	// Native TLS, with mTLS when TLS.ClientCAFile is set
+	TLS TLSConfig
+
+	// UnixSocket also serves the API on this unix domain socket path, beside the TCP port, for sidecars on the
+	// same host (disabled when empty)
+	UnixSocket string
+
+	// JWT bearer-token auth (disabled when neither Auth.JWTSecret nor Auth.JWKSURL is set)
+	Auth AuthConfig
+
+	// Static bearer token for the /admin group (admin routes also accept a JWT admin role)
+	AdminToken string
+
+	// BRC-103 mutual authentication (disabled when MutualAuth.PrivateKey is empty)
+	MutualAuth MutualAuthConfig
+
+	// StaleTipThreshold alerts when no new tip arrives for this long, 0 disables the stale-tip watchdog
+	StaleTipThreshold time.Duration
+
+	// MaxHeaders caps the count accepted by /v2/headers, 0 keeps the routes default
+	MaxHeaders int
+
+	// Cache-Control policy of the header routes; zero values keep the routes defaults
+	Cache fiberroutes.CachePolicy
+
+	// TSCompat serves the routes the TypeScript wallet-toolbox chaintracks client expects
+	TSCompat bool
+
+	// MetricsEnabled exposes Prometheus metrics on /metrics
+	MetricsEnabled bool
+
+	// Response compression for bodies of at least CompressMinSize bytes
+	Compression     bool
+	CompressMinSize int
+
+	// Debug endpoints, /debug/pprof and /debug/vars: on DebugAddr when set, otherwise on the main port behind the
+	// admin role when DebugEnabled
+	DebugEnabled bool
+	DebugAddr    string
+
+	// Settings seeded from the selected profile
+	RateLimit    int // Requests per minute per client IP, 0 disables limiting
+	LagThreshold uint32
+	QuietPeriod  time.Duration
+	SSEReplay    uint32
 }
 
 // LoadConfig loads configuration from environment variables with defaults
+// PROFILE selects a preset (see profile.go); explicit environment variables override it
 func LoadConfig() *Config {
+	profile := resolveProfile(os.Getenv("PROFILE"))
+
 	port := 3011
 	if portStr := os.Getenv("PORT"); portStr != "" {
 		if p, err := strconv.Atoi(portStr); err == nil {
@@ -36,42 +156,300 @@ func LoadConfig() *Config {
 		storagePath = path
 	}
 
-	bootstrapURL := os.Getenv("BOOTSTRAP_URL")
+	bootstrapURLs := splitList(os.Getenv("BOOTSTRAP_URL"))
+
+	defaults := chaintracks.DefaultsForNetwork(network)
 
-	bootstrapPeers := loadBootstrapPeers(network)
+	bootstrapPeers := splitList(os.Getenv("BOOTSTRAP_PEERS"))
+	if len(bootstrapPeers) == 0 {
+		bootstrapPeers = loadBootstrapPeers(network)
+	}
+	if len(bootstrapPeers) == 0 {
+		bootstrapPeers = defaults.BootstrapPeers
+	}
+
+	cdnURLs := splitList(os.Getenv("CDN_URLS"))
+	if len(cdnURLs) == 0 && profile.CDNFallback {
+		cdnURLs = defaults.CDNURLs
+	}
+
+	watchdogInterval := profile.WatchdogInterval
+	if intervalStr := os.Getenv("WATCHDOG_INTERVAL"); intervalStr != "" {
+		if d, err := time.ParseDuration(intervalStr); err == nil && d > 0 {
+			watchdogInterval = d
+		}
+	}
+
+	staleTipThreshold := defaults.StaleTipThreshold
+	if thresholdStr := os.Getenv("STALE_TIP_THRESHOLD"); thresholdStr != "" {
+		if d, err := time.ParseDuration(thresholdStr); err == nil && d >= 0 {
+			staleTipThreshold = d
+		}
+	}
+
+	p2pFallbackSilence := defaultP2PFallbackSilence
+	if silenceStr := os.Getenv("P2P_FALLBACK_SILENCE"); silenceStr != "" {
+		if d, err := time.ParseDuration(silenceStr); err == nil && d >= 0 {
+			p2pFallbackSilence = d
+		}
+	}
+
+	var cdnPublishInterval time.Duration
+	if intervalStr := os.Getenv("CDN_PUBLISH_INTERVAL"); intervalStr != "" {
+		if d, err := time.ParseDuration(intervalStr); err == nil && d > 0 {
+			cdnPublishInterval = d
+		}
+	}
+
+	natsStreamMaxAge := defaultNATSStreamMaxAge
+	if maxAgeStr := os.Getenv("NATS_STREAM_MAX_AGE"); maxAgeStr != "" {
+		if d, err := time.ParseDuration(maxAgeStr); err == nil && d >= 0 {
+			natsStreamMaxAge = d
+		}
+	}
+
+	mqttQoS := byte(defaultMQTTQoS)
+	if qosStr := os.Getenv("MQTT_QOS"); qosStr != "" {
+		if q, err := strconv.ParseUint(qosStr, 10, 8); err == nil && q <= 2 {
+			mqttQoS = byte(q)
+		}
+	}
+
+	rateLimit := profile.RateLimit
+	if limitStr := os.Getenv("RATE_LIMIT"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l >= 0 {
+			rateLimit = l
+		}
+	}
+
+	lagThreshold := profile.LagThreshold
+	if thresholdStr := os.Getenv("LAG_THRESHOLD"); thresholdStr != "" {
+		if t, err := strconv.ParseUint(thresholdStr, 10, 32); err == nil && t > 0 {
+			lagThreshold = uint32(t)
+		}
+	}
+
+	p2pPortReuse := true
+	if reuseStr := os.Getenv("P2P_PORT_REUSE"); reuseStr != "" {
+		if b, err := strconv.ParseBool(reuseStr); err == nil {
+			p2pPortReuse = b
+		}
+	}
+
+	var cacheMaxAge time.Duration
+	if maxAgeStr := os.Getenv("CACHE_MAX_AGE"); maxAgeStr != "" {
+		if d, err := time.ParseDuration(maxAgeStr); err == nil && d > 0 {
+			cacheMaxAge = d
+		}
+	}
+
+	var cacheHeightMaxAge time.Duration
+	if maxAgeStr := os.Getenv("CACHE_HEIGHT_MAX_AGE"); maxAgeStr != "" {
+		if d, err := time.ParseDuration(maxAgeStr); err == nil && d > 0 {
+			cacheHeightMaxAge = d
+		}
+	}
+
+	cdnServe := true
+	if cdnServeStr := os.Getenv("CDN_SERVE"); cdnServeStr != "" {
+		if b, err := strconv.ParseBool(cdnServeStr); err == nil {
+			cdnServe = b
+		}
+	}
+
+	compression := true
+	if compressionStr := os.Getenv("COMPRESSION"); compressionStr != "" {
+		if b, err := strconv.ParseBool(compressionStr); err == nil {
+			compression = b
+		}
+	}
+
+	headerSource := headerSourceP2P
+	if source := os.Getenv("HEADER_SOURCE"); source != "" {
+		if source == headerSourceP2P || source == headerSourceTeranode {
+			headerSource = source
+		} else {
+			slog.Warn("Unknown HEADER_SOURCE, using p2p", "headerSource", source)
+		}
+	}
+
+	tsCompat, _ := strconv.ParseBool(os.Getenv("TS_COMPAT"))
+	metricsEnabled, _ := strconv.ParseBool(os.Getenv("METRICS_ENABLED"))
+	debugEnabled, _ := strconv.ParseBool(os.Getenv("DEBUG_ENABLED"))
+	kafkaTLS, _ := strconv.ParseBool(os.Getenv("KAFKA_TLS"))
+	natsJetStream, _ := strconv.ParseBool(os.Getenv("NATS_JETSTREAM"))
+	brc103AllowUnauthenticated, _ := strconv.ParseBool(os.Getenv("BRC103_ALLOW_UNAUTHENTICATED"))
+	cdnSkipFileHash, _ := strconv.ParseBool(os.Getenv("CDN_SKIP_FILE_HASH"))
+	whatsOnChainSync, _ := strconv.ParseBool(os.Getenv("WHATSONCHAIN_SYNC"))
+	kafkaTopic := os.Getenv("KAFKA_TOPIC")
+	if kafkaTopic == "" {
+		kafkaTopic = defaultKafkaTopic
+	}
 
 	return &Config{
-		Port:           port,
-		Network:        network,
-		StoragePath:    storagePath,
-		BootstrapURL:   bootstrapURL,
-		BootstrapPeers: bootstrapPeers,
+		Profile:            profile.Name,
+		Port:               port,
+		Network:            network,
+		StoragePath:        storagePath,
+		BootstrapURLs:      bootstrapURLs,
+		BootstrapQuorum:    getEnvInt("BOOTSTRAP_QUORUM", 0),
+		BootstrapPeers:     bootstrapPeers,
+		CDNURLs:            cdnURLs,
+		CDNServe:           cdnServe,
+		P2PPort:            getEnvInt("P2P_PORT", 0),
+		P2PAnnounceAddrs:   splitList(os.Getenv("P2P_ANNOUNCE_ADDRS")),
+		P2PMaxConnections:  getEnvInt("P2P_MAX_CONNECTIONS", 0),
+		P2PMinConnections:  getEnvInt("P2P_MIN_CONNECTIONS", 0),
+		P2PPortReuse:       p2pPortReuse,
+		MaxHeaders:         getEnvInt("MAX_HEADERS", 0),
+		TSCompat:           tsCompat,
+		MetricsEnabled:     metricsEnabled,
+		Compression:        compression,
+		CompressMinSize:    getEnvInt("COMPRESS_MIN_SIZE", defaultCompressMinSize),
+		DebugEnabled:       debugEnabled,
+		DebugAddr:          os.Getenv("DEBUG_ADDR"),
+		UnixSocket:         strings.TrimPrefix(os.Getenv("UNIX_SOCKET"), "unix://"),
+		CDNDownload:        chaintracks.CDNDownloadConfig{Workers: getEnvInt("CDN_DOWNLOAD_WORKERS", 0), SkipFileHash: cdnSkipFileHash, PublicKey: os.Getenv("CDN_PUBLIC_KEY")},
+		P2PFallbackSilence: p2pFallbackSilence,
+		Sync: chaintracks.SyncConfig{
+			BatchSize:     getEnvInt("SYNC_BATCH_SIZE", 0),
+			Workers:       getEnvInt("SYNC_WORKERS", 0),
+			RequestWindow: getEnvInt("SYNC_REQUEST_WINDOW", 0),
+		},
+		MemoryWindow:       getEnvInt("MEMORY_WINDOW", 0),
+		CDNPublishDir:      os.Getenv("CDN_PUBLISH_DIR"),
+		CDNPublishInterval: cdnPublishInterval,
+		CDNSigningKey:      os.Getenv("CDN_SIGNING_KEY"),
+		HeaderSource:       headerSource,
+		TeranodeURLs:       splitList(os.Getenv("TERANODE_URL")),
+		WireNodes:          splitList(os.Getenv("WIRE_NODES")),
+		WhatsOnChainSync:   whatsOnChainSync,
+		MirrorURLs:         splitList(os.Getenv("MIRROR_URL")),
+		WatchdogReference:  os.Getenv("WATCHDOG_REFERENCE"),
+		WatchdogInterval:   watchdogInterval,
+		WhatsOnChainKey:    os.Getenv("WHATSONCHAIN_API_KEY"),
+		StaleTipThreshold:  staleTipThreshold,
+		CDNPublishS3: chaintracks.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Prefix:    os.Getenv("S3_PREFIX"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		},
+		Cache: fiberroutes.CachePolicy{
+			ImmutableDepth:  uint32(min(getEnvInt("CACHE_IMMUTABLE_DEPTH", 0), math.MaxUint32)), //nolint:gosec // Clamped to uint32
+			ImmutableMaxAge: cacheMaxAge,
+			HeightMaxAge:    cacheHeightMaxAge,
+			NoCache:         splitList(os.Getenv("CACHE_NO_CACHE_ROUTES")),
+		},
+		Kafka: KafkaConfig{
+			Brokers:       splitList(os.Getenv("KAFKA_BROKERS")),
+			Topic:         kafkaTopic,
+			Username:      os.Getenv("KAFKA_USERNAME"),
+			Password:      os.Getenv("KAFKA_PASSWORD"),
+			SASLMechanism: os.Getenv("KAFKA_SASL_MECHANISM"),
+			TLS:           kafkaTLS,
+		},
+		ZMQEndpoint: os.Getenv("ZMQ_ENDPOINT"),
+		Redis: RedisConfig{
+			URL:           os.Getenv("REDIS_URL"),
+			ChannelPrefix: getEnvString("REDIS_CHANNEL_PREFIX", defaultRedisChannelPrefix),
+			TipKey:        os.Getenv("REDIS_TIP_KEY"),
+		},
+		MQTT: MQTTConfig{
+			BrokerURL:   os.Getenv("MQTT_BROKER_URL"),
+			TopicPrefix: getEnvString("MQTT_TOPIC_PREFIX", defaultMQTTTopicPrefix),
+			QoS:         mqttQoS,
+			ClientID:    os.Getenv("MQTT_CLIENT_ID"),
+			Username:    os.Getenv("MQTT_USERNAME"),
+			Password:    os.Getenv("MQTT_PASSWORD"),
+		},
+		TLS: TLSConfig{
+			CertFile:     os.Getenv("TLS_CERT_FILE"),
+			KeyFile:      os.Getenv("TLS_KEY_FILE"),
+			ClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		},
+		Auth: AuthConfig{
+			JWTSecret:  os.Getenv("AUTH_JWT_SECRET"),
+			JWKSURL:    os.Getenv("AUTH_JWKS_URL"),
+			Issuer:     os.Getenv("AUTH_JWT_ISSUER"),
+			Audience:   os.Getenv("AUTH_JWT_AUDIENCE"),
+			RolesClaim: getEnvString("AUTH_ROLES_CLAIM", defaultRolesClaim),
+		},
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+		MutualAuth: MutualAuthConfig{
+			PrivateKey:           os.Getenv("BRC103_PRIVATE_KEY"),
+			AllowedKeys:          splitList(os.Getenv("BRC103_ALLOWED_KEYS")),
+			AllowUnauthenticated: brc103AllowUnauthenticated,
+		},
+		NATS: NATSConfig{
+			URL:           os.Getenv("NATS_URL"),
+			SubjectPrefix: getEnvString("NATS_SUBJECT_PREFIX", defaultNATSSubjectPrefix),
+			Credentials:   os.Getenv("NATS_CREDS"),
+			Token:         os.Getenv("NATS_TOKEN"),
+			JetStream:     natsJetStream,
+			Stream:        getEnvString("NATS_STREAM", defaultNATSStream),
+			StreamMaxAge:  natsStreamMaxAge,
+		},
+		RateLimit:    rateLimit,
+		LagThreshold: lagThreshold,
+		QuietPeriod:  profile.QuietPeriod,
+		SSEReplay:    profile.SSEReplay,
 	}
 }
 
 // loadBootstrapPeers loads P2P bootstrap peers from data/bootstrap_peers.json
+// Returns nil if the file is absent so the built-in defaults apply
 func loadBootstrapPeers(network string) []string {
 	data, err := os.ReadFile("data/bootstrap_peers.json")
 	if err != nil {
-		log.Printf("Warning: could not load bootstrap_peers.json: %v", err)
 		return nil
 	}
 
 	var peers map[string][]string
 	if err := json.Unmarshal(data, &peers); err != nil {
-		log.Printf("Warning: could not parse bootstrap_peers.json: %v", err)
+		slog.Warn("Could not parse bootstrap_peers.json", "error", err)
 		return nil
 	}
 
 	if networkPeers, ok := peers[network]; ok {
-		log.Printf("Loaded %d bootstrap peers for network %s", len(networkPeers), network)
+		slog.Info("Loaded bootstrap peers", "count", len(networkPeers), "network", network)
 		return networkPeers
 	}
 
-	log.Printf("Warning: no bootstrap peers found for network %s", network)
 	return nil
 }
 
+// getEnvString returns an environment value, or def when unset
+func getEnvString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// getEnvInt returns a non-negative integer environment value, or def when unset or invalid
+func getEnvInt(key string, def int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil && i >= 0 {
+			return i
+		}
+	}
+	return def
+}
+
+// splitList parses a comma-separated environment value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getDefaultStoragePath returns ~/.chaintracks as the default storage path
 func getDefaultStoragePath() string {
 	home, err := os.UserHomeDir()
@@ -4,9 +4,13 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	fiberroutes "github.com/bsv-blockchain/go-chaintracks/routes/fiber"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -16,7 +20,7 @@ func TestLoadConfig(t *testing.T) {
 		expectedPort   int
 		expectedNet    string
 		expectedPath   string
-		expectedBootst string
+		expectedBootst []string
 	}{
 		{
 			name:           "LoadsDefaultValues",
@@ -24,7 +28,7 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   3011,
 			expectedNet:    "main",
 			expectedPath:   getDefaultStoragePath(),
-			expectedBootst: "",
+			expectedBootst: nil,
 		},
 		{
 			name:           "LoadsPortFromEnvironment",
@@ -32,7 +36,7 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   8080,
 			expectedNet:    "main",
 			expectedPath:   getDefaultStoragePath(),
-			expectedBootst: "",
+			expectedBootst: nil,
 		},
 		{
 			name:           "LoadsNetworkFromEnvironment",
@@ -40,7 +44,7 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   3011,
 			expectedNet:    "testnet",
 			expectedPath:   getDefaultStoragePath(),
-			expectedBootst: "",
+			expectedBootst: nil,
 		},
 		{
 			name:           "LoadsStoragePathFromEnvironment",
@@ -48,7 +52,7 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   3011,
 			expectedNet:    "main",
 			expectedPath:   "/custom/path",
-			expectedBootst: "",
+			expectedBootst: nil,
 		},
 		{
 			name:           "LoadsBootstrapURLFromEnvironment",
@@ -56,7 +60,7 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   3011,
 			expectedNet:    "main",
 			expectedPath:   getDefaultStoragePath(),
-			expectedBootst: "http://example.com",
+			expectedBootst: []string{"http://example.com"},
 		},
 		{
 			name: "LoadsAllValuesFromEnvironment",
@@ -69,7 +73,15 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   9999,
 			expectedNet:    "regtest",
 			expectedPath:   "/full/custom/path",
-			expectedBootst: "http://bootstrap.example.com",
+			expectedBootst: []string{"http://bootstrap.example.com"},
+		},
+		{
+			name:           "LoadsBootstrapURLList",
+			envVars:        map[string]string{"BOOTSTRAP_URL": "http://a.example.com, http://b.example.com"},
+			expectedPort:   3011,
+			expectedNet:    "main",
+			expectedPath:   getDefaultStoragePath(),
+			expectedBootst: []string{"http://a.example.com", "http://b.example.com"},
 		},
 		{
 			name:           "UsesDefaultPortWhenPortIsInvalid",
@@ -77,7 +89,7 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   3011,
 			expectedNet:    "main",
 			expectedPath:   getDefaultStoragePath(),
-			expectedBootst: "",
+			expectedBootst: nil,
 		},
 		{
 			name:           "UsesDefaultPortWhenPortIsEmpty",
@@ -85,7 +97,7 @@ func TestLoadConfig(t *testing.T) {
 			expectedPort:   3011,
 			expectedNet:    "main",
 			expectedPath:   getDefaultStoragePath(),
-			expectedBootst: "",
+			expectedBootst: nil,
 		},
 	}
 
@@ -100,11 +112,355 @@ func TestLoadConfig(t *testing.T) {
 			assert.Equal(t, tt.expectedPort, config.Port)
 			assert.Equal(t, tt.expectedNet, config.Network)
 			assert.Equal(t, tt.expectedPath, config.StoragePath)
-			assert.Equal(t, tt.expectedBootst, config.BootstrapURL)
+			assert.Equal(t, tt.expectedBootst, config.BootstrapURLs)
+		})
+	}
+}
+
+func TestLoadConfigWatchdog(t *testing.T) {
+	tests := []struct {
+		name              string
+		envVars           map[string]string
+		expectedReference string
+		expectedInterval  time.Duration
+	}{
+		{
+			name:             "DisabledByDefault",
+			envVars:          nil,
+			expectedInterval: 5 * time.Minute,
+		},
+		{
+			name: "LoadsReferenceAndInterval",
+			envVars: map[string]string{
+				"WATCHDOG_REFERENCE": "whatsonchain",
+				"WATCHDOG_INTERVAL":  "30s",
+			},
+			expectedReference: "whatsonchain",
+			expectedInterval:  30 * time.Second,
+		},
+		{
+			name:             "UsesDefaultIntervalWhenInvalid",
+			envVars:          map[string]string{"WATCHDOG_INTERVAL": "soon"},
+			expectedInterval: 5 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			config := LoadConfig()
+
+			assert.Equal(t, tt.expectedReference, config.WatchdogReference)
+			assert.Equal(t, tt.expectedInterval, config.WatchdogInterval)
+		})
+	}
+}
+
+func TestLoadConfigNetworkDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		envVars       map[string]string
+		expectedPeers []string
+		expectedCDN   []string
+	}{
+		{
+			name:          "UsesBuiltInDefaults",
+			envVars:       map[string]string{"CHAIN": "main"},
+			expectedPeers: chaintracks.DefaultsForNetwork("main").BootstrapPeers,
+			expectedCDN:   chaintracks.DefaultsForNetwork("main").CDNURLs,
+		},
+		{
+			name: "EnvironmentOverridesDefaults",
+			envVars: map[string]string{
+				"BOOTSTRAP_PEERS": "/dns4/a/tcp/1/p2p/x, /dns4/b/tcp/2/p2p/y",
+				"CDN_URLS":        "https://cdn.example.com",
+			},
+			expectedPeers: []string{"/dns4/a/tcp/1/p2p/x", "/dns4/b/tcp/2/p2p/y"},
+			expectedCDN:   []string{"https://cdn.example.com"},
+		},
+		{
+			name:    "UnknownNetworkHasNoDefaults",
+			envVars: map[string]string{"CHAIN": "regtest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			config := LoadConfig()
+
+			assert.Equal(t, tt.expectedPeers, config.BootstrapPeers)
+			assert.Equal(t, tt.expectedCDN, config.CDNURLs)
 		})
 	}
 }
 
+func TestLoadConfigP2P(t *testing.T) {
+	tests := []struct {
+		name             string
+		envVars          map[string]string
+		expectedPort     int
+		expectedAnnounce []string
+		expectedMax      int
+		expectedMin      int
+		expectedReuse    bool
+	}{
+		{
+			name:          "DefaultsKeepLibraryBehavior",
+			expectedReuse: true,
+		},
+		{
+			name: "LoadsFromEnvironment",
+			envVars: map[string]string{
+				"P2P_PORT":            "9905",
+				"P2P_ANNOUNCE_ADDRS":  "/ip4/203.0.113.1/tcp/9905, /ip6/2001:db8::1/tcp/9905",
+				"P2P_MAX_CONNECTIONS": "60",
+				"P2P_MIN_CONNECTIONS": "40",
+				"P2P_PORT_REUSE":      "false",
+			},
+			expectedPort:     9905,
+			expectedAnnounce: []string{"/ip4/203.0.113.1/tcp/9905", "/ip6/2001:db8::1/tcp/9905"},
+			expectedMax:      60,
+			expectedMin:      40,
+			expectedReuse:    false,
+		},
+		{
+			name: "IgnoresInvalidValues",
+			envVars: map[string]string{
+				"P2P_PORT":            "-1",
+				"P2P_MAX_CONNECTIONS": "many",
+				"P2P_PORT_REUSE":      "maybe",
+			},
+			expectedReuse: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			config := LoadConfig()
+
+			assert.Equal(t, tt.expectedPort, config.P2PPort)
+			assert.Equal(t, tt.expectedAnnounce, config.P2PAnnounceAddrs)
+			assert.Equal(t, tt.expectedMax, config.P2PMaxConnections)
+			assert.Equal(t, tt.expectedMin, config.P2PMinConnections)
+			assert.Equal(t, tt.expectedReuse, config.P2PPortReuse)
+		})
+	}
+}
+
+func TestLoadConfigTSCompat(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected bool
+	}{
+		{name: "DisabledByDefault", expected: false},
+		{name: "EnabledFromEnvironment", envVars: map[string]string{"TS_COMPAT": "true"}, expected: true},
+		{name: "InvalidValueDisables", envVars: map[string]string{"TS_COMPAT": "yes please"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().TSCompat)
+		})
+	}
+}
+
+func TestLoadConfigMetricsEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected bool
+	}{
+		{name: "DisabledByDefault", expected: false},
+		{name: "EnabledFromEnvironment", envVars: map[string]string{"METRICS_ENABLED": "true"}, expected: true},
+		{name: "InvalidValueDisables", envVars: map[string]string{"METRICS_ENABLED": "on"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().MetricsEnabled)
+		})
+	}
+}
+
+func TestLoadConfigStaleTipThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected time.Duration
+	}{
+		{name: "MainnetDefault", expected: time.Hour},
+		{name: "TestnetDefault", envVars: map[string]string{"CHAIN": "test"}, expected: 2 * time.Hour},
+		{name: "UnknownNetworkDisabled", envVars: map[string]string{"CHAIN": "regtest"}, expected: 0},
+		{name: "FromEnvironment", envVars: map[string]string{"STALE_TIP_THRESHOLD": "30m"}, expected: 30 * time.Minute},
+		{name: "ZeroDisables", envVars: map[string]string{"STALE_TIP_THRESHOLD": "0"}, expected: 0},
+		{name: "InvalidKeepsDefault", envVars: map[string]string{"STALE_TIP_THRESHOLD": "soon"}, expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().StaleTipThreshold)
+		})
+	}
+}
+
+func TestLoadConfigKafka(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cleanup := withEnvVars(t, nil)
+		defer cleanup()
+
+		config := LoadConfig()
+		assert.Empty(t, config.Kafka.Brokers)
+		assert.Equal(t, defaultKafkaTopic, config.Kafka.Topic)
+	})
+
+	t.Run("FromEnvironment", func(t *testing.T) {
+		cleanup := withEnvVars(t, map[string]string{
+			"KAFKA_BROKERS":        "a:9092, b:9092",
+			"KAFKA_TOPIC":          "headers",
+			"KAFKA_USERNAME":       "user",
+			"KAFKA_PASSWORD":       "secret",
+			"KAFKA_SASL_MECHANISM": "scram-sha-512",
+			"KAFKA_TLS":            "true",
+		})
+		defer cleanup()
+
+		assert.Equal(t, KafkaConfig{
+			Brokers:       []string{"a:9092", "b:9092"},
+			Topic:         "headers",
+			Username:      "user",
+			Password:      "secret",
+			SASLMechanism: "scram-sha-512",
+			TLS:           true,
+		}, LoadConfig().Kafka)
+	})
+}
+
+func TestLoadConfigNATS(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		cleanup := withEnvVars(t, nil)
+		defer cleanup()
+
+		assert.Equal(t, NATSConfig{
+			SubjectPrefix: defaultNATSSubjectPrefix,
+			Stream:        defaultNATSStream,
+			StreamMaxAge:  defaultNATSStreamMaxAge,
+		}, LoadConfig().NATS)
+	})
+
+	t.Run("FromEnvironment", func(t *testing.T) {
+		cleanup := withEnvVars(t, map[string]string{
+			"NATS_URL":            "nats://localhost:4222",
+			"NATS_SUBJECT_PREFIX": "headers",
+			"NATS_CREDS":          "/etc/nats/user.creds",
+			"NATS_TOKEN":          "secret",
+			"NATS_JETSTREAM":      "true",
+			"NATS_STREAM":         "HEADERS",
+			"NATS_STREAM_MAX_AGE": "0",
+		})
+		defer cleanup()
+
+		assert.Equal(t, NATSConfig{
+			URL:           "nats://localhost:4222",
+			SubjectPrefix: "headers",
+			Credentials:   "/etc/nats/user.creds",
+			Token:         "secret",
+			JetStream:     true,
+			Stream:        "HEADERS",
+		}, LoadConfig().NATS)
+	})
+}
+
+func TestLoadConfigZMQEndpoint(t *testing.T) {
+	cleanup := withEnvVars(t, map[string]string{"ZMQ_ENDPOINT": "tcp://0.0.0.0:28332"})
+	defer cleanup()
+
+	assert.Equal(t, "tcp://0.0.0.0:28332", LoadConfig().ZMQEndpoint)
+}
+
+func TestLoadConfigMQTT(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected MQTTConfig
+	}{
+		{
+			name:     "Defaults",
+			expected: MQTTConfig{TopicPrefix: defaultMQTTTopicPrefix, QoS: defaultMQTTQoS},
+		},
+		{
+			name: "FromEnvironment",
+			envVars: map[string]string{
+				"MQTT_BROKER_URL":   "ssl://broker:8883",
+				"MQTT_TOPIC_PREFIX": "bsv",
+				"MQTT_QOS":          "2",
+				"MQTT_CLIENT_ID":    "edge-1",
+				"MQTT_USERNAME":     "user",
+				"MQTT_PASSWORD":     "secret",
+			},
+			expected: MQTTConfig{
+				BrokerURL:   "ssl://broker:8883",
+				TopicPrefix: "bsv",
+				QoS:         2,
+				ClientID:    "edge-1",
+				Username:    "user",
+				Password:    "secret",
+			},
+		},
+		{
+			name:     "InvalidQoSKeepsDefault",
+			envVars:  map[string]string{"MQTT_QOS": "3"},
+			expected: MQTTConfig{TopicPrefix: defaultMQTTTopicPrefix, QoS: defaultMQTTQoS},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().MQTT)
+		})
+	}
+}
+
+func TestLoadConfigRedis(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		cleanup := withEnvVars(t, nil)
+		defer cleanup()
+
+		assert.Equal(t, RedisConfig{ChannelPrefix: defaultRedisChannelPrefix}, LoadConfig().Redis)
+	})
+
+	t.Run("FromEnvironment", func(t *testing.T) {
+		cleanup := withEnvVars(t, map[string]string{
+			"REDIS_URL":            "rediss://cache:6380/1",
+			"REDIS_CHANNEL_PREFIX": "bsv",
+			"REDIS_TIP_KEY":        "bsv:tip",
+		})
+		defer cleanup()
+
+		assert.Equal(t, RedisConfig{URL: "rediss://cache:6380/1", ChannelPrefix: "bsv", TipKey: "bsv:tip"}, LoadConfig().Redis)
+	})
+}
+
 func TestGetDefaultStoragePath(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -129,3 +485,362 @@ func TestGetDefaultStoragePath(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigAuth(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		cleanup := withEnvVars(t, nil)
+		defer cleanup()
+
+		config := LoadConfig()
+		assert.Equal(t, AuthConfig{RolesClaim: defaultRolesClaim}, config.Auth)
+		assert.False(t, config.Auth.Enabled())
+	})
+
+	t.Run("FromEnvironment", func(t *testing.T) {
+		cleanup := withEnvVars(t, map[string]string{
+			"AUTH_JWKS_URL":     "https://auth.example.com/.well-known/jwks.json",
+			"AUTH_JWT_ISSUER":   "https://auth.example.com/",
+			"AUTH_JWT_AUDIENCE": "chaintracks",
+			"AUTH_ROLES_CLAIM":  "scope",
+		})
+		defer cleanup()
+
+		config := LoadConfig()
+		assert.Equal(t, AuthConfig{
+			JWKSURL:    "https://auth.example.com/.well-known/jwks.json",
+			Issuer:     "https://auth.example.com/",
+			Audience:   "chaintracks",
+			RolesClaim: "scope",
+		}, config.Auth)
+		assert.True(t, config.Auth.Enabled())
+	})
+}
+
+func TestLoadConfigAdminToken(t *testing.T) {
+	cleanup := withEnvVars(t, map[string]string{"ADMIN_TOKEN": "s3cret"})
+	defer cleanup()
+
+	assert.Equal(t, "s3cret", LoadConfig().AdminToken)
+}
+
+func TestLoadConfigMutualAuth(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		cleanup := withEnvVars(t, nil)
+		defer cleanup()
+
+		assert.Equal(t, MutualAuthConfig{}, LoadConfig().MutualAuth)
+	})
+
+	t.Run("FromEnvironment", func(t *testing.T) {
+		cleanup := withEnvVars(t, map[string]string{
+			"BRC103_PRIVATE_KEY":           "0000000000000000000000000000000000000000000000000000000000000001",
+			"BRC103_ALLOWED_KEYS":          "02aa, 03bb",
+			"BRC103_ALLOW_UNAUTHENTICATED": "true",
+		})
+		defer cleanup()
+
+		assert.Equal(t, MutualAuthConfig{
+			PrivateKey:           "0000000000000000000000000000000000000000000000000000000000000001",
+			AllowedKeys:          []string{"02aa", "03bb"},
+			AllowUnauthenticated: true,
+		}, LoadConfig().MutualAuth)
+	})
+}
+
+func TestLoadConfigTLS(t *testing.T) {
+	cleanup := withEnvVars(t, map[string]string{
+		"TLS_CERT_FILE":      "/etc/chaintracks/tls.crt",
+		"TLS_KEY_FILE":       "/etc/chaintracks/tls.key",
+		"TLS_CLIENT_CA_FILE": "/etc/chaintracks/clients.pem",
+	})
+	defer cleanup()
+
+	config := LoadConfig()
+	assert.Equal(t, TLSConfig{
+		CertFile:     "/etc/chaintracks/tls.crt",
+		KeyFile:      "/etc/chaintracks/tls.key",
+		ClientCAFile: "/etc/chaintracks/clients.pem",
+	}, config.TLS)
+	assert.True(t, config.TLS.Enabled())
+}
+
+func TestLoadConfigHeaderSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		envVars      map[string]string
+		expected     string
+		expectedURLs []string
+	}{
+		{name: "P2PByDefault", expected: headerSourceP2P},
+		{
+			name:         "Teranode",
+			envVars:      map[string]string{"HEADER_SOURCE": "teranode", "TERANODE_URL": "http://a:8090/api/v1, http://b:8090/api/v1"},
+			expected:     headerSourceTeranode,
+			expectedURLs: []string{"http://a:8090/api/v1", "http://b:8090/api/v1"},
+		},
+		{name: "UnknownFallsBackToP2P", envVars: map[string]string{"HEADER_SOURCE": "carrier-pigeon"}, expected: headerSourceP2P},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			config := LoadConfig()
+			assert.Equal(t, tt.expected, config.HeaderSource)
+			assert.Equal(t, tt.expectedURLs, config.TeranodeURLs)
+		})
+	}
+}
+
+func TestLoadConfigP2PFallbackSilence(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected time.Duration
+	}{
+		{name: "Default", expected: defaultP2PFallbackSilence},
+		{name: "FromEnvironment", envVars: map[string]string{"P2P_FALLBACK_SILENCE": "5m"}, expected: 5 * time.Minute},
+		{name: "ZeroDisables", envVars: map[string]string{"P2P_FALLBACK_SILENCE": "0"}, expected: 0},
+		{name: "InvalidKeepsDefault", envVars: map[string]string{"P2P_FALLBACK_SILENCE": "later"}, expected: defaultP2PFallbackSilence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().P2PFallbackSilence)
+		})
+	}
+}
+
+func TestLoadConfigCDNPublish(t *testing.T) {
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cleanup := withEnvVars(t, nil)
+		defer cleanup()
+
+		config := LoadConfig()
+		assert.Empty(t, config.CDNPublishDir)
+		assert.Empty(t, config.CDNPublishS3.Bucket)
+	})
+
+	t.Run("FromEnvironment", func(t *testing.T) {
+		cleanup := withEnvVars(t, map[string]string{
+			"CDN_PUBLISH_DIR":      "/var/www/headers",
+			"CDN_PUBLISH_INTERVAL": "5m",
+			"S3_ENDPOINT":          "https://storage.googleapis.com",
+			"S3_REGION":            "auto",
+			"S3_BUCKET":            "headers",
+			"S3_PREFIX":            "blockheaders",
+			"S3_ACCESS_KEY_ID":     "key",
+			"S3_SECRET_ACCESS_KEY": "secret",
+		})
+		defer cleanup()
+
+		config := LoadConfig()
+		assert.Equal(t, "/var/www/headers", config.CDNPublishDir)
+		assert.Equal(t, 5*time.Minute, config.CDNPublishInterval)
+		assert.Equal(t, chaintracks.S3Config{
+			Endpoint:  "https://storage.googleapis.com",
+			Region:    "auto",
+			Bucket:    "headers",
+			Prefix:    "blockheaders",
+			AccessKey: "key",
+			SecretKey: "secret",
+		}, config.CDNPublishS3)
+	})
+}
+
+func TestLoadConfigMaxHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected int
+	}{
+		{name: "DefaultsToZero", expected: 0},
+		{name: "FromEnvironment", envVars: map[string]string{"MAX_HEADERS": "2000"}, expected: 2000},
+		{name: "InvalidIgnored", envVars: map[string]string{"MAX_HEADERS": "-1"}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().MaxHeaders)
+		})
+	}
+}
+
+func TestLoadConfigMemoryWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected int
+	}{
+		{name: "DefaultsToZero", expected: 0},
+		{name: "FromEnvironment", envVars: map[string]string{"MEMORY_WINDOW": "10000"}, expected: 10000},
+		{name: "InvalidIgnored", envVars: map[string]string{"MEMORY_WINDOW": "small"}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().MemoryWindow)
+		})
+	}
+}
+
+func TestLoadConfigSync(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected chaintracks.SyncConfig
+	}{
+		{name: "DefaultsToZero", expected: chaintracks.SyncConfig{}},
+		{
+			name:     "FromEnvironment",
+			envVars:  map[string]string{"SYNC_BATCH_SIZE": "250", "SYNC_WORKERS": "2", "SYNC_REQUEST_WINDOW": "1"},
+			expected: chaintracks.SyncConfig{BatchSize: 250, Workers: 2, RequestWindow: 1},
+		},
+		{name: "InvalidIgnored", envVars: map[string]string{"SYNC_BATCH_SIZE": "-5", "SYNC_WORKERS": "many"}, expected: chaintracks.SyncConfig{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().Sync)
+		})
+	}
+}
+
+func TestLoadConfigDebug(t *testing.T) {
+	tests := []struct {
+		name            string
+		envVars         map[string]string
+		expectedEnabled bool
+		expectedAddr    string
+	}{
+		{name: "DisabledByDefault"},
+		{name: "MainPort", envVars: map[string]string{"DEBUG_ENABLED": "true"}, expectedEnabled: true},
+		{name: "SeparateListener", envVars: map[string]string{"DEBUG_ADDR": "localhost:6060"}, expectedAddr: "localhost:6060"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			config := LoadConfig()
+			assert.Equal(t, tt.expectedEnabled, config.DebugEnabled)
+			assert.Equal(t, tt.expectedAddr, config.DebugAddr)
+		})
+	}
+}
+
+func TestLoadConfigCompression(t *testing.T) {
+	tests := []struct {
+		name            string
+		envVars         map[string]string
+		expectedEnabled bool
+		expectedMinSize int
+	}{
+		{name: "EnabledByDefault", expectedEnabled: true, expectedMinSize: defaultCompressMinSize},
+		{name: "Disabled", envVars: map[string]string{"COMPRESSION": "false"}, expectedMinSize: defaultCompressMinSize},
+		{name: "MinSize", envVars: map[string]string{"COMPRESS_MIN_SIZE": "4096"}, expectedEnabled: true, expectedMinSize: 4096},
+		{name: "InvalidIgnored", envVars: map[string]string{"COMPRESSION": "maybe", "COMPRESS_MIN_SIZE": "-1"}, expectedEnabled: true, expectedMinSize: defaultCompressMinSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			config := LoadConfig()
+			assert.Equal(t, tt.expectedEnabled, config.Compression)
+			assert.Equal(t, tt.expectedMinSize, config.CompressMinSize)
+		})
+	}
+}
+
+func TestLoadConfigUnixSocket(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected string
+	}{
+		{name: "DisabledByDefault"},
+		{name: "URL", envVars: map[string]string{"UNIX_SOCKET": "unix:///var/run/chaintracks.sock"}, expected: "/var/run/chaintracks.sock"},
+		{name: "Path", envVars: map[string]string{"UNIX_SOCKET": "/tmp/chaintracks.sock"}, expected: "/tmp/chaintracks.sock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().UnixSocket)
+		})
+	}
+}
+
+func TestLoadConfigCDNServe(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected bool
+	}{
+		{name: "EnabledByDefault", expected: true},
+		{name: "Disabled", envVars: map[string]string{"CDN_SERVE": "false"}},
+		{name: "InvalidIgnored", envVars: map[string]string{"CDN_SERVE": "maybe"}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().CDNServe)
+		})
+	}
+}
+
+func TestLoadConfigCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected fiberroutes.CachePolicy
+	}{
+		{name: "DefaultsToZero"},
+		{
+			name: "FromEnvironment",
+			envVars: map[string]string{
+				"CACHE_IMMUTABLE_DEPTH": "6",
+				"CACHE_MAX_AGE":         "24h",
+				"CACHE_HEIGHT_MAX_AGE":  "10s",
+				"CACHE_NO_CACHE_ROUTES": "/headers, /chainwork/:height",
+			},
+			expected: fiberroutes.CachePolicy{
+				ImmutableDepth:  6,
+				ImmutableMaxAge: 24 * time.Hour,
+				HeightMaxAge:    10 * time.Second,
+				NoCache:         []string{fiberroutes.RouteHeaders, fiberroutes.RouteChainWork},
+			},
+		},
+		{name: "InvalidIgnored", envVars: map[string]string{"CACHE_IMMUTABLE_DEPTH": "-1", "CACHE_MAX_AGE": "-1h", "CACHE_HEIGHT_MAX_AGE": "soon"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			assert.Equal(t, tt.expected, LoadConfig().Cache)
+		})
+	}
+}
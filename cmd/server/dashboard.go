@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"html"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,26 +25,17 @@ func NewDashboardHandler(server *Server) *DashboardHandler {
 
 // HandleStatus renders the status dashboard
 func (h *DashboardHandler) HandleStatus(c *fiber.Ctx) error {
-	tip := h.server.cm.GetTip(c.UserContext())
-	height := h.server.cm.GetHeight(c.UserContext())
-
-	var tipHash string
-	var tipChainwork string
-	if tip != nil {
-		tipHash = tip.Hash.String()
-		tipChainwork = tip.ChainWork.String()
-	} else {
-		tipHash = "N/A"
-		tipChainwork = "N/A"
-	}
+	status := h.server.buildStatus(c.UserContext())
+	metrics := h.server.cm.GetMetrics()
 
-	network, err := h.server.cm.GetNetwork(c.UserContext())
-	if err != nil {
-		network = "unknown"
+	tipHash, tipChainwork, tipAge := "N/A", "N/A", "N/A"
+	if status.TipHash != "" {
+		tipHash = status.TipHash
+		tipAge = (time.Duration(status.TipAgeSeconds) * time.Second).String()
+	}
+	if status.TipChainWork != "" {
+		tipChainwork = status.TipChainWork
 	}
-
-	peers := h.server.cm.GetPeers()
-	peerCount := len(peers)
 
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -117,24 +110,58 @@ func (h *DashboardHandler) HandleStatus(c *fiber.Ctx) error {
             0%%, 100%% { opacity: 1; }
             50%% { opacity: 0.5; }
         }
+        .banner {
+            padding: 15px 20px;
+            margin: 20px 0;
+            border-radius: 5px;
+            font-weight: bold;
+        }
+        .banner-behind {
+            background: #330000;
+            border: 1px solid #ff3333;
+            color: #ff3333;
+        }
+        .banner-quiet {
+            background: #332b00;
+            border: 1px solid #ffcc00;
+            color: #ffcc00;
+        }
         .timestamp {
             color: #808080;
             font-size: 0.9em;
             text-align: right;
             margin-top: 20px;
         }
+        .timestamp a {
+            color: #00cccc;
+        }
     </style>
 </head>
 <body>
     <div class="container">
         <h1><span class="status-indicator"></span>Chaintracks Status Dashboard</h1>
+        %s
 
         <div class="section">
             <h2>Chain Status</h2>
             <div><span class="label">Network:</span><span class="value">%s</span></div>
             <div><span class="label">Current Height:</span><span class="value">%d</span></div>
             <div><span class="label">Tip Hash:</span><span class="value hash">%s</span></div>
-            <div><span class="label">Chainwork:</span><span class="value">%s</span></div>
+            <div><span class="label">Chainwork:</span><span class="value hash">%s</span></div>
+            <div><span class="label">Tip Age:</span><span class="value">%s</span></div>
+        </div>
+        %s
+
+        <div class="section">
+            <h2>Lifetime</h2>
+            <div><span class="label">Version:</span><span class="value">%s</span></div>
+            <div><span class="label">Storage Path:</span><span class="value">%s</span></div>
+            <div><span class="label">Current Uptime:</span><span class="value">%s</span></div>
+            <div><span class="label">Running Since:</span><span class="value">%s</span></div>
+            <div><span class="label">Total Uptime:</span><span class="value">%s</span></div>
+            <div><span class="label">Starts:</span><span class="value">%d</span></div>
+            <div><span class="label">Headers:</span><span class="value">%d</span></div>
+            <div><span class="label">Reorgs:</span><span class="value">%d</span></div>
         </div>
 
         <div class="section">
@@ -146,17 +173,28 @@ func (h *DashboardHandler) HandleStatus(c *fiber.Ctx) error {
         </div>
 
         <div class="timestamp">
-            Last updated: %s (auto-refresh every 10s)
+            Last updated: %s (auto-refresh every 10s, <a href="/v2/status">JSON</a>)
         </div>
     </div>
 </body>
 </html>`,
-		network,
-		height,
+		h.renderLagBanner(status.Sync),
+		status.Network,
+		status.Height,
 		tipHash,
 		tipChainwork,
-		peerCount,
-		h.renderPeerList(peers),
+		tipAge,
+		h.renderSyncProgress(status.SyncProgress),
+		status.Version,
+		status.StoragePath,
+		(time.Duration(status.UptimeSeconds) * time.Second).String(),
+		metrics.FirstStart.Format("2006-01-02 15:04:05 MST"),
+		(time.Duration(metrics.UptimeSeconds) * time.Second).String(),
+		metrics.Starts,
+		metrics.HeadersProcessed,
+		metrics.TotalReorgs,
+		status.PeerCount,
+		h.renderPeerList(status.Peers),
 		time.Now().Format("2006-01-02 15:04:05 MST"),
 	)
 
@@ -164,6 +202,64 @@ func (h *DashboardHandler) HandleStatus(c *fiber.Ctx) error {
 	return c.SendString(html)
 }
 
+// renderLagBanner generates a warning banner when the chain is behind or the network is quiet
+func (h *DashboardHandler) renderLagBanner(lag chaintracks.LagStatus) string {
+	switch lag.State {
+	case chaintracks.SyncStateBehind:
+		return fmt.Sprintf(`<div class="banner banner-behind">Falling behind network tip: local height %d, network height %d (%d blocks behind)</div>`,
+			lag.LocalHeight, lag.NetworkHeight, lag.Deficit)
+	case chaintracks.SyncStateNetworkQuiet:
+		return fmt.Sprintf(`<div class="banner banner-quiet">No blocks seen on the network since %s</div>`,
+			lag.LastBlockSeen.Format("2006-01-02 15:04:05 MST"))
+	case chaintracks.SyncStateSynced:
+	}
+	return ""
+}
+
+// renderSyncProgress generates the sync section for the current or most recent bulk sync
+func (h *DashboardHandler) renderSyncProgress(progress *chaintracks.SyncProgress) string {
+	if progress == nil {
+		return ""
+	}
+
+	state := "complete"
+	eta := "-"
+	switch {
+	case progress.Active:
+		state = "syncing"
+		eta = (time.Duration(progress.ETASeconds) * time.Second).String()
+	case progress.Error != "":
+		state = "failed: " + progress.Error
+	}
+
+	target := strconv.FormatUint(uint64(progress.TargetHeight), 10)
+	if progress.TargetEstimated {
+		target = "~" + target
+	}
+
+	percent := 100.0
+	if span := progress.TargetHeight - progress.StartHeight; span > 0 {
+		percent = float64(progress.CurrentHeight-progress.StartHeight) / float64(span) * 100
+	}
+
+	return fmt.Sprintf(`<div class="section">
+            <h2>Sync</h2>
+            <div><span class="label">State:</span><span class="value">%s</span></div>
+            <div><span class="label">Source:</span><span class="value">%s</span></div>
+            <div><span class="label">Progress:</span><span class="value">%d / %s (%.1f%%)</span></div>
+            <div><span class="label">Rate:</span><span class="value">%.0f headers/s</span></div>
+            <div><span class="label">ETA:</span><span class="value">%s</span></div>
+        </div>`,
+		html.EscapeString(state),
+		html.EscapeString(progress.Source),
+		progress.CurrentHeight,
+		target,
+		percent,
+		progress.HeadersPerSecond,
+		eta,
+	)
+}
+
 // renderPeerList generates HTML for the peer list
 func (h *DashboardHandler) renderPeerList(peers []chaintracks.PeerInfo) string {
 	if len(peers) == 0 {
@@ -231,3 +231,92 @@ func TestDashboardHandlerRenderPeerList(t *testing.T) {
 		})
 	}
 }
+
+func TestDashboardHandlerRenderLagBanner(t *testing.T) {
+	tests := []struct {
+		name           string
+		lag            chaintracks.LagStatus
+		expectContains string
+	}{
+		{
+			name:           "SyncedRendersNothing",
+			lag:            chaintracks.LagStatus{State: chaintracks.SyncStateSynced},
+			expectContains: "",
+		},
+		{
+			name: "BehindRendersDeficit",
+			lag: chaintracks.LagStatus{
+				State:         chaintracks.SyncStateBehind,
+				LocalHeight:   100,
+				NetworkHeight: 110,
+				Deficit:       10,
+			},
+			expectContains: "(10 blocks behind)",
+		},
+		{
+			name:           "QuietRendersLastSeen",
+			lag:            chaintracks.LagStatus{State: chaintracks.SyncStateNetworkQuiet},
+			expectContains: "No blocks seen on the network",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &DashboardHandler{}
+			result := handler.renderLagBanner(tt.lag)
+
+			if tt.expectContains == "" {
+				assert.Empty(t, result)
+				return
+			}
+			assert.Contains(t, result, tt.expectContains)
+		})
+	}
+}
+
+func TestDashboardHandlerRenderSyncProgress(t *testing.T) {
+	tests := []struct {
+		name           string
+		progress       *chaintracks.SyncProgress
+		expectContains []string
+	}{
+		{
+			name:     "NoSyncRendersNothing",
+			progress: nil,
+		},
+		{
+			name: "ActiveRendersEstimatedTargetAndETA",
+			progress: &chaintracks.SyncProgress{
+				Active:           true,
+				Source:           "https://node.example.com",
+				StartHeight:      100,
+				CurrentHeight:    150,
+				TargetHeight:     200,
+				TargetEstimated:  true,
+				HeadersPerSecond: 25,
+				ETASeconds:       2,
+			},
+			expectContains: []string{"syncing", "https://node.example.com", "150 / ~200 (50.0%)", "25 headers/s", "2s"},
+		},
+		{
+			name:           "FailedRendersEscapedError",
+			progress:       &chaintracks.SyncProgress{Error: "status <500>", CurrentHeight: 5, TargetHeight: 5},
+			expectContains: []string{"failed: status &lt;500&gt;", "5 / 5 (100.0%)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &DashboardHandler{}
+			result := handler.renderSyncProgress(tt.progress)
+
+			if len(tt.expectContains) == 0 {
+				assert.Empty(t, result)
+				return
+			}
+			for _, expected := range tt.expectContains {
+				assert.Contains(t, result, expected)
+			}
+		})
+	}
+}
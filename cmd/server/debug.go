@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof" //nolint:gosec // Served only on newDebugMux, which is off unless configured
+	"time"
+)
+
+// debugShutdownTimeout bounds how long the separate debug listener waits for in-flight profiles on shutdown
+const debugShutdownTimeout = 5 * time.Second
+
+// newDebugMux serves the runtime profiles under /debug/pprof/ and the expvar variables on /debug/vars
+func newDebugMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// startDebugServer serves the debug endpoints on their own listener, e.g. localhost:6060, until ctx is done
+// It returns the address listened on.
+func startDebugServer(ctx context.Context, addr string) (net.Addr, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           newDebugMux(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Debug server failed", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), debugShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx) //nolint:contextcheck // ctx is already done
+	}()
+
+	return ln.Addr(), nil
+}
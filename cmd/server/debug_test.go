@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugRoutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		path     string
+		expected int
+	}{
+		{name: "DisabledByDefault", path: "/debug/vars", expected: http.StatusNotFound},
+		{name: "Vars", enabled: true, path: "/debug/vars", expected: http.StatusOK},
+		{name: "PprofIndex", enabled: true, path: "/debug/pprof/", expected: http.StatusOK},
+		{name: "HeapProfile", enabled: true, path: "/debug/pprof/heap", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(t.Context(), newSyntheticChainManager(t, 3))
+			server.debugRoutes = tt.enabled
+			app := fiber.New()
+			server.SetupRoutes(app, NewDashboardHandler(server))
+
+			assert.Equal(t, tt.expected, authGet(t, app, tt.path, ""))
+		})
+	}
+}
+
+func TestStartDebugServer(t *testing.T) {
+	addr, err := startDebugServer(t.Context(), "127.0.0.1:0")
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+addr.String()+"/debug/vars", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"memstats"`)
+}
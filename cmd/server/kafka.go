@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+const defaultKafkaTopic = "chaintracks-events"
+
+var errUnknownSASLMechanism = errors.New("unknown SASL mechanism")
+
+// KafkaConfig configures the Kafka event sink; it is disabled when Brokers is empty
+type KafkaConfig struct {
+	Brokers       []string
+	Topic         string
+	Username      string
+	Password      string
+	SASLMechanism string // "plain", "scram-sha-256" or "scram-sha-512"; empty disables SASL
+	TLS           bool
+}
+
+// kafkaWriter is the subset of *kafka.Writer used by the sink
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// kafkaSink publishes events to a Kafka topic
+// Every message is keyed by network so all events land on one partition and keep their order.
+type kafkaSink struct {
+	writer kafkaWriter
+	key    []byte
+}
+
+// newKafkaSink creates a synchronous producer for the configured brokers and topic
+func newKafkaSink(config KafkaConfig, network string) (*kafkaSink, error) {
+	mechanism, err := kafkaSASLMechanism(config)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &kafka.Transport{SASL: mechanism}
+	if config.TLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return &kafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(config.Brokers...),
+			Topic:        config.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			Transport:    transport,
+		},
+		key: []byte(network),
+	}, nil
+}
+
+// kafkaSASLMechanism builds the SASL mechanism for the configured credentials, nil when SASL is off
+func kafkaSASLMechanism(config KafkaConfig) (sasl.Mechanism, error) {
+	switch strings.ToLower(config.SASLMechanism) {
+	case "":
+		return nil, nil //nolint:nilnil // No SASL is a valid configuration
+	case "plain":
+		return plain.Mechanism{Username: config.Username, Password: config.Password}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, config.Username, config.Password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, config.Username, config.Password)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownSASLMechanism, config.SASLMechanism)
+	}
+}
+
+// Name implements eventSink
+func (k *kafkaSink) Name() string {
+	return "kafka"
+}
+
+// Publish implements eventSink, waiting until every in-sync replica has the message
+func (k *kafkaSink) Publish(ctx context.Context, event *chaintracks.ChainEvent, payload []byte) error {
+	return k.writer.WriteMessages(ctx, kafka.Message{
+		Key:     k.key,
+		Value:   payload,
+		Headers: []kafka.Header{{Key: "type", Value: []byte(event.Type)}},
+	})
+}
+
+// Close implements eventSink, flushing pending messages
+func (k *kafkaSink) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// fakeKafkaWriter records written messages
+type fakeKafkaWriter struct {
+	messages []kafka.Message
+	closed   bool
+}
+
+func (f *fakeKafkaWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	f.messages = append(f.messages, msgs...)
+	return nil
+}
+
+func (f *fakeKafkaWriter) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestKafkaSASLMechanism(t *testing.T) {
+	tests := []struct {
+		name        string
+		mechanism   string
+		expected    string
+		expectError bool
+	}{
+		{name: "NoneWhenEmpty", mechanism: "", expected: ""},
+		{name: "Plain", mechanism: "plain", expected: "PLAIN"},
+		{name: "ScramSHA256", mechanism: "scram-sha-256", expected: "SCRAM-SHA-256"},
+		{name: "ScramSHA512CaseInsensitive", mechanism: "SCRAM-SHA-512", expected: "SCRAM-SHA-512"},
+		{name: "Unknown", mechanism: "gssapi", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mechanism, err := kafkaSASLMechanism(KafkaConfig{SASLMechanism: tt.mechanism, Username: "user", Password: "pass"})
+			if tt.expectError {
+				require.ErrorIs(t, err, errUnknownSASLMechanism)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, mechanism)
+				return
+			}
+			assert.Equal(t, tt.expected, mechanism.Name())
+		})
+	}
+}
+
+func TestNewKafkaSink(t *testing.T) {
+	sink, err := newKafkaSink(KafkaConfig{
+		Brokers: []string{"broker1:9092", "broker2:9092"},
+		Topic:   "headers",
+		TLS:     true,
+	}, "main")
+	require.NoError(t, err)
+
+	writer, ok := sink.writer.(*kafka.Writer)
+	require.True(t, ok)
+	assert.Equal(t, "headers", writer.Topic)
+	assert.Equal(t, "broker1:9092,broker2:9092", writer.Addr.String())
+	assert.Equal(t, kafka.RequireAll, writer.RequiredAcks)
+	assert.Equal(t, []byte("main"), sink.key)
+
+	transport, ok := writer.Transport.(*kafka.Transport)
+	require.True(t, ok)
+	assert.NotNil(t, transport.TLS)
+	assert.Nil(t, transport.SASL)
+
+	_, err = newKafkaSink(KafkaConfig{Brokers: []string{"broker:9092"}, SASLMechanism: "bogus"}, "main")
+	require.ErrorIs(t, err, errUnknownSASLMechanism)
+}
+
+func TestKafkaSinkPublish(t *testing.T) {
+	writer := &fakeKafkaWriter{}
+	sink := &kafkaSink{writer: writer, key: []byte("test")}
+
+	event := &chaintracks.ChainEvent{Seq: 7, Type: chaintracks.EventReorg}
+	require.NoError(t, sink.Publish(t.Context(), event, []byte(`{"seq":7}`)))
+	require.NoError(t, sink.Close())
+
+	require.Len(t, writer.messages, 1)
+	msg := writer.messages[0]
+	assert.Equal(t, []byte("test"), msg.Key)
+	assert.JSONEq(t, `{"seq":7}`, string(msg.Value))
+	assert.Equal(t, []kafka.Header{{Key: "type", Value: []byte("reorg")}}, msg.Headers)
+	assert.True(t, writer.closed)
+}
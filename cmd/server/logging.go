@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newLogger builds the JSON logger used by the server and the chaintracks package
+// level is one of debug, info, warn or error; anything else logs at info
+func newLogger(w io.Writer, level string) *slog.Logger {
+	var lvl slog.Level
+	invalid := level != "" && lvl.UnmarshalText([]byte(level)) != nil
+	if invalid {
+		lvl = slog.LevelInfo
+	}
+
+	logger := slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}))
+	if invalid {
+		logger.Warn("Unknown LOG_LEVEL, using info", "level", level)
+	}
+	return logger
+}
+
+// fatal logs an error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// requestLogger logs one structured line per HTTP request
+func (s *Server) requestLogger(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+	}
+	s.logger.Info("HTTP request", "method", c.Method(), "path", c.Path(), "status", status, "latency", time.Since(start))
+	return err
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name         string
+		level        string
+		debugEnabled bool
+		infoEnabled  bool
+		expectWarn   bool
+	}{
+		{name: "DefaultsToInfo", level: "", infoEnabled: true},
+		{name: "Debug", level: "debug", debugEnabled: true, infoEnabled: true},
+		{name: "CaseInsensitive", level: "WARN"},
+		{name: "UnknownLevelFallsBackToInfo", level: "verbose", infoEnabled: true, expectWarn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newLogger(&buf, tt.level)
+
+			assert.Equal(t, tt.debugEnabled, logger.Enabled(t.Context(), slog.LevelDebug))
+			assert.Equal(t, tt.infoEnabled, logger.Enabled(t.Context(), slog.LevelInfo))
+			assert.Equal(t, tt.expectWarn, bytes.Contains(buf.Bytes(), []byte("Unknown LOG_LEVEL")))
+		})
+	}
+}
+
+func TestServerRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 3))
+	server.logger = newLogger(&buf, "info")
+
+	app := fiber.New()
+	app.Use(server.requestLogger)
+	server.SetupRoutes(app, NewDashboardHandler(server))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v2/height", nil))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "HTTP request", entry["msg"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/v2/height", entry["path"])
+	assert.InDelta(t, http.StatusOK, entry["status"], 0)
+	assert.Contains(t, entry, "latency")
+}
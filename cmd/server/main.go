@@ -2,54 +2,102 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
 	"time"
 
-	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/joho/godotenv"
 
 	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+		os.Exit(runSelfTest(context.Background(), os.Args[2:], os.Stdout))
+	}
+
 	_ = godotenv.Load()
 
+	logger := newLogger(os.Stderr, os.Getenv("LOG_LEVEL"))
+	slog.SetDefault(logger)
+	chaintracks.SetDefaultLogger(logger)
+
 	config := LoadConfig()
 	logConfig(config)
 
-	if err := ensureHeadersExist(config.StoragePath, config.Network); err != nil {
-		log.Fatalf("Failed to initialize headers: %v", err)
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if config.Network == chaintracks.NetworkRegtest {
+		cleanup, err := prepareRegtestStorage(config)
+		if err != nil {
+			fatal("Failed to initialize regtest", "error", err)
+		}
+		defer cleanup()
+	} else if err := ensureHeadersExist(ctx, config.StoragePath, config.Network, config.CDNURLs, config.CDNDownload); err != nil {
+		fatal("Failed to initialize headers", "error", err)
+	}
+
 	cm, err := createChainManager(ctx, config)
 	if err != nil {
-		log.Fatalf("Failed to create chain manager: %v", err)
+		fatal("Failed to create chain manager", "error", err)
 	}
 
 	logChainState(ctx, cm)
+	cm.SetLagPolicy(config.LagThreshold, config.QuietPeriod)
+	cm.SetSyncConfig(config.Sync)
 
-	blockMsgChan, err := cm.Start(ctx)
-	if err != nil {
-		log.Fatalf("Failed to start P2P: %v", err)
+	app := createFiberApp(ctx, cm, config, logger)
+
+	startDebugEndpoints(ctx, config)
+
+	if err := startSinks(ctx, cm, config); err != nil {
+		fatal("Failed to start event sinks", "error", err)
 	}
-	log.Printf("P2P listener started for network: %s", config.Network)
 
-	// Start periodic peer status logging
-	go logPeerStatus(ctx, cm)
+	if len(config.BootstrapURLs) > 0 {
+		cm.SetBootstrapQuorum(config.BootstrapQuorum)
+		cm.BootstrapSync(ctx, config.BootstrapURLs...)
+	}
+
+	switch {
+	case config.Network == chaintracks.NetworkRegtest:
+		if config.AdminToken == "" && !config.Auth.Enabled() {
+			slog.Warn("Regtest blocks can only be mined through /admin/regtest, set ADMIN_TOKEN")
+		}
+		slog.Info("Regtest mode, mine blocks with POST /admin/regtest/mine", "storagePath", config.StoragePath)
+	case config.HeaderSource == headerSourceTeranode:
+		startTeranodeSync(ctx, cm, config)
+	default:
+		if _, err := cm.Start(ctx); err != nil {
+			fatal("Failed to start P2P", "error", err)
+		}
+		slog.Info("P2P listener started", "network", config.Network)
+
+		// Start periodic peer status logging
+		go logPeerStatus(ctx, cm)
+	}
+
+	startWireSync(ctx, cm, config)
+
+	startWhatsOnChainSync(ctx, cm, config)
+
+	startMirrorSync(ctx, cm, config)
+
+	startWatchdog(ctx, cm, config)
 
-	app := createFiberApp(ctx, cm, blockMsgChan, config.Port)
+	startCDNPublisher(ctx, cm, config)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -59,34 +107,142 @@ func main() {
 }
 
 func logConfig(config *Config) {
-	log.Printf("Starting chaintracks-server")
-	log.Printf("  Network: %s", config.Network)
-	log.Printf("  Port: %d", config.Port)
-	log.Printf("  Storage Path: %s", config.StoragePath)
-	if config.BootstrapURL != "" {
-		log.Printf("  Bootstrap URL: %s", config.BootstrapURL)
+	args := []any{
+		"profile", config.Profile,
+		"network", config.Network,
+		"port", config.Port,
+		"storagePath", config.StoragePath,
+	}
+	if config.UnixSocket != "" {
+		args = append(args, "unixSocket", config.UnixSocket)
+	}
+	if config.CDNDownload.PublicKey != "" {
+		args = append(args, "cdnPublicKey", config.CDNDownload.PublicKey)
+	}
+	if len(config.BootstrapURLs) > 0 {
+		args = append(args, "bootstrapURLs", config.BootstrapURLs, "bootstrapQuorum", config.BootstrapQuorum)
+	}
+	if config.MemoryWindow > 0 {
+		args = append(args, "memoryWindow", config.MemoryWindow)
+	}
+	if config.Sync != (chaintracks.SyncConfig{}) {
+		args = append(args, "syncBatchSize", config.Sync.BatchSize, "syncWorkers", config.Sync.Workers, "syncRequestWindow", config.Sync.RequestWindow)
+	}
+	if config.HeaderSource == headerSourceTeranode {
+		args = append(args, "headerSource", config.HeaderSource, "teranodeURLs", config.TeranodeURLs)
+	}
+	if len(config.BootstrapURLs) > 0 && config.P2PFallbackSilence > 0 && config.HeaderSource == headerSourceP2P {
+		args = append(args, "p2pFallbackSilence", config.P2PFallbackSilence)
+	}
+	if len(config.WireNodes) > 0 {
+		args = append(args, "wireNodes", config.WireNodes)
+	}
+	if config.WhatsOnChainSync {
+		args = append(args, "whatsOnChainSync", true)
+	}
+	if len(config.MirrorURLs) > 0 {
+		args = append(args, "mirrorURLs", config.MirrorURLs)
+	}
+	if config.CDNPublishDir != "" {
+		args = append(args, "cdnPublishDir", config.CDNPublishDir)
+		if config.CDNSigningKey != "" {
+			args = append(args, "cdnSigned", true)
+		}
+		if config.CDNPublishS3.Bucket != "" {
+			args = append(args, "s3Bucket", config.CDNPublishS3.Bucket, "s3Prefix", config.CDNPublishS3.Prefix)
+		}
+	}
+	if config.WatchdogReference != "" {
+		args = append(args, "watchdogReference", config.WatchdogReference, "watchdogInterval", config.WatchdogInterval)
+	}
+	if config.MaxHeaders > 0 {
+		args = append(args, "maxHeaders", config.MaxHeaders)
+	}
+	if cache := config.Cache; cache.ImmutableDepth > 0 || cache.ImmutableMaxAge > 0 || cache.HeightMaxAge > 0 || len(cache.NoCache) > 0 {
+		args = append(args, "cacheImmutableDepth", cache.ImmutableDepth, "cacheMaxAge", cache.ImmutableMaxAge,
+			"cacheHeightMaxAge", cache.HeightMaxAge, "cacheNoCacheRoutes", cache.NoCache)
+	}
+	if config.StaleTipThreshold > 0 {
+		args = append(args, "staleTipThreshold", config.StaleTipThreshold)
+	}
+	if len(config.Kafka.Brokers) > 0 {
+		args = append(args, "kafkaBrokers", config.Kafka.Brokers, "kafkaTopic", config.Kafka.Topic)
+	}
+	if config.NATS.URL != "" {
+		args = append(args, "natsSubjectPrefix", config.NATS.SubjectPrefix, "natsJetStream", config.NATS.JetStream)
+	}
+	if config.ZMQEndpoint != "" {
+		args = append(args, "zmqEndpoint", config.ZMQEndpoint)
+	}
+	if config.MQTT.BrokerURL != "" {
+		args = append(args, "mqttBroker", config.MQTT.BrokerURL, "mqttTopicPrefix", config.MQTT.TopicPrefix, "mqttQoS", config.MQTT.QoS)
 	}
+	if config.Redis.URL != "" {
+		args = append(args, "redisChannelPrefix", config.Redis.ChannelPrefix, "redisTipKey", config.Redis.TipKey)
+	}
+	if config.Auth.Enabled() {
+		args = append(args, "authJWKS", config.Auth.JWKSURL, "authIssuer", config.Auth.Issuer, "authAudience", config.Auth.Audience)
+	}
+	if config.AdminToken != "" {
+		args = append(args, "adminToken", true)
+	}
+	if config.MutualAuth.PrivateKey != "" {
+		args = append(args, "brc103AllowedKeys", len(config.MutualAuth.AllowedKeys), "brc103AllowUnauthenticated", config.MutualAuth.AllowUnauthenticated)
+	}
+	if config.P2PPort > 0 {
+		args = append(args, "p2pPort", config.P2PPort)
+	}
+	if len(config.P2PAnnounceAddrs) > 0 {
+		args = append(args, "p2pAnnounceAddrs", config.P2PAnnounceAddrs)
+	}
+	if config.TSCompat {
+		args = append(args, "tsCompat", true)
+	}
+	if config.MetricsEnabled {
+		args = append(args, "metrics", true)
+	}
+	if !config.CDNServe {
+		args = append(args, "cdnServe", false)
+	}
+	if !config.Compression {
+		args = append(args, "compression", false)
+	} else if config.CompressMinSize != defaultCompressMinSize {
+		args = append(args, "compressMinSize", config.CompressMinSize)
+	}
+	if config.DebugAddr != "" {
+		args = append(args, "debugAddr", config.DebugAddr)
+	} else if config.DebugEnabled {
+		args = append(args, "debug", true)
+	}
+	if config.RateLimit > 0 {
+		args = append(args, "rateLimit", config.RateLimit)
+	}
+	slog.Info("Starting chaintracks-server", args...)
 }
 
 func createChainManager(ctx context.Context, config *Config) (*chaintracks.ChainManager, error) {
-	privKey, err := chaintracks.LoadOrGeneratePrivateKey(config.StoragePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load or generate private key: %w", err)
+	memoryWindow := uint32(config.MemoryWindow) //nolint:gosec // Non-negative, set by the operator
+
+	// A Teranode header source replaces the message bus, and a regtest chain is mined locally, so no libp2p host
+	// is created
+	if config.HeaderSource == headerSourceTeranode || config.Network == chaintracks.NetworkRegtest {
+		return chaintracks.NewWindowedChainManager(ctx, config.Network, config.StoragePath, nil, memoryWindow)
 	}
 
-	p2pClient, err := p2p.NewClient(p2p.Config{
-		Name:           "go-chaintracks",
-		Logger:         &p2p.DefaultLogger{},
-		PrivateKey:     privKey,
-		Port:           0,
-		PeerCacheFile:  filepath.Join(config.StoragePath, "peer_cache.json"),
-		BootstrapPeers: config.BootstrapPeers,
+	p2pClient, err := chaintracks.NewP2PClient(config.StoragePath, config.Network, chaintracks.P2PConfig{
+		Port:             config.P2PPort,
+		AnnounceAddrs:    config.P2PAnnounceAddrs,
+		BootstrapPeers:   config.BootstrapPeers,
+		MaxConnections:   config.P2PMaxConnections,
+		MinConnections:   config.P2PMinConnections,
+		DisablePortReuse: !config.P2PPortReuse,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create P2P client: %w", err)
+		return nil, err
 	}
 
-	return chaintracks.NewChainManager(ctx, config.Network, config.StoragePath, p2pClient, config.BootstrapURL)
+	// Bootstrap sync runs after the HTTP server is up so its progress can be watched
+	return chaintracks.NewWindowedChainManager(ctx, config.Network, config.StoragePath, p2pClient, memoryWindow)
 }
 
 func logPeerStatus(ctx context.Context, cm *chaintracks.ChainManager) {
@@ -98,22 +254,252 @@ func logPeerStatus(ctx context.Context, cm *chaintracks.ChainManager) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			peers := cm.GetPeers()
-			log.Printf("Connected to %d P2P peers", len(peers))
+			slog.Info("P2P peer status", "peers", cm.PeerCount())
 		}
 	}
 }
 
+// startWireSync follows the configured BSV nodes over the Bitcoin P2P protocol, if any
+func startWireSync(ctx context.Context, cm *chaintracks.ChainManager, config *Config) {
+	if len(config.WireNodes) == 0 {
+		return
+	}
+
+	wireSync, err := chaintracks.NewWireSync(cm, chaintracks.WireSyncConfig{Nodes: config.WireNodes})
+	if err != nil {
+		fatal("Failed to start wire sync", "error", err)
+	}
+	go wireSync.Run(ctx)
+}
+
+// startTeranodeSync follows the configured Teranode Asset Services in place of the P2P message bus
+func startTeranodeSync(ctx context.Context, cm *chaintracks.ChainManager, config *Config) {
+	teranodeSync, err := chaintracks.NewTeranodeSync(cm, chaintracks.TeranodeSyncConfig{URLs: config.TeranodeURLs})
+	if err != nil {
+		fatal("Failed to start Teranode sync", "error", err)
+	}
+	slog.Info("Teranode header source started", "urls", config.TeranodeURLs)
+	go teranodeSync.Run(ctx)
+}
+
+// startWhatsOnChainSync polls WhatsOnChain while no P2P peers are connected, unless bootstrap URLs are configured
+// or Teranode is the header source
+func startWhatsOnChainSync(ctx context.Context, cm *chaintracks.ChainManager, config *Config) {
+	if !config.WhatsOnChainSync {
+		return
+	}
+	if config.HeaderSource == headerSourceTeranode {
+		slog.Info("WhatsOnChain sync fallback disabled, Teranode is the header source")
+		return
+	}
+	if len(config.BootstrapURLs) > 0 {
+		slog.Info("WhatsOnChain sync fallback disabled, bootstrap URLs are configured")
+		return
+	}
+
+	wocSync, err := chaintracks.NewWhatsOnChainSync(cm, chaintracks.WhatsOnChainSyncConfig{APIKey: config.WhatsOnChainKey})
+	if err != nil {
+		fatal("Failed to start WhatsOnChain sync", "error", err)
+	}
+	go wocSync.Run(ctx)
+}
+
+// startMirrorSync follows another chaintracks server as a read replica, if one is configured
+func startMirrorSync(ctx context.Context, cm *chaintracks.ChainManager, config *Config) {
+	if len(config.MirrorURLs) == 0 {
+		return
+	}
+
+	mirror, err := chaintracks.NewMirrorSync(cm, chaintracks.MirrorSyncConfig{
+		URL:          config.MirrorURLs[0],
+		FallbackURLs: config.MirrorURLs[1:],
+	})
+	if err != nil {
+		fatal("Failed to start mirror sync", "error", err)
+	}
+	go mirror.Run(ctx)
+}
+
+// startCDNPublisher keeps generated CDN artifacts current, uploading them to S3 when a bucket is configured
+func startCDNPublisher(ctx context.Context, cm *chaintracks.ChainManager, config *Config) {
+	if config.CDNPublishDir == "" {
+		return
+	}
+
+	publishConfig := chaintracks.CDNPublishConfig{OutputDir: config.CDNPublishDir, Interval: config.CDNPublishInterval}
+	if config.CDNPublishS3.Bucket != "" {
+		bucket, err := chaintracks.NewS3Bucket(config.CDNPublishS3)
+		if err != nil {
+			fatal("Failed to configure CDN upload", "error", err)
+		}
+		publishConfig.Bucket = bucket
+	}
+	if config.CDNSigningKey != "" {
+		key, err := ec.PrivateKeyFromHex(config.CDNSigningKey)
+		if err != nil {
+			if key, err = ec.PrivateKeyFromWif(config.CDNSigningKey); err != nil {
+				fatal("Invalid CDN signing key", "error", err)
+			}
+		}
+		publishConfig.SigningKey = key
+		slog.Info("Signing CDN metadata", "publicKey", key.PubKey().ToDERHex())
+	}
+	publisher, err := chaintracks.NewCDNPublisher(cm, publishConfig)
+	if err != nil {
+		fatal("Failed to start CDN publisher", "error", err)
+	}
+	go publisher.Run(ctx)
+}
+
+// startWatchdog runs the divergence watchdog against the configured reference, if any
+func startWatchdog(ctx context.Context, cm *chaintracks.ChainManager, config *Config) {
+	if config.WatchdogReference == "" {
+		return
+	}
+
+	var reference chaintracks.ReferenceSource
+	if config.WatchdogReference == "whatsonchain" {
+		reference = chaintracks.NewWhatsOnChainReference(config.Network, config.WhatsOnChainKey)
+	} else {
+		reference = chaintracks.NewChaintracksReference(chaintracks.NewClient(config.WatchdogReference))
+	}
+
+	watchdog := chaintracks.NewDivergenceWatchdog(cm, reference, chaintracks.WatchdogConfig{
+		Interval: config.WatchdogInterval,
+	})
+	go watchdog.Run(ctx)
+}
+
+// startDebugEndpoints serves pprof and expvar on their own listener, if DEBUG_ADDR is set
+func startDebugEndpoints(ctx context.Context, config *Config) {
+	if config.DebugAddr == "" {
+		return
+	}
+
+	addr, err := startDebugServer(ctx, config.DebugAddr)
+	if err != nil {
+		fatal("Failed to start debug server", "error", err)
+	}
+	slog.Info("Debug endpoints listening", "url", "http://"+addr.String()+"/debug/pprof/")
+}
+
+// startSinks publishes chain events to every configured external sink
+func startSinks(ctx context.Context, cm *chaintracks.ChainManager, config *Config) error {
+	factories := []struct {
+		name    string
+		enabled bool
+		create  func() (eventSink, error)
+	}{
+		{"kafka", len(config.Kafka.Brokers) > 0, func() (eventSink, error) {
+			return newKafkaSink(config.Kafka, config.Network)
+		}},
+		{"nats", config.NATS.URL != "", func() (eventSink, error) {
+			return newNATSSink(ctx, config.NATS, config.Network)
+		}},
+		{"zmq", config.ZMQEndpoint != "", func() (eventSink, error) {
+			return newZMQSink(ctx, config.ZMQEndpoint, cm)
+		}},
+		{"mqtt", config.MQTT.BrokerURL != "", func() (eventSink, error) {
+			return newMQTTSink(config.MQTT, config.Network)
+		}},
+		{"redis", config.Redis.URL != "", func() (eventSink, error) {
+			return newRedisSink(ctx, config.Redis, config.Network, cm.GetTip(ctx))
+		}},
+	}
+
+	var sinks []eventSink
+	for _, factory := range factories {
+		if !factory.enabled {
+			continue
+		}
+		sink, err := factory.create()
+		if err != nil {
+			closeSinks(sinks)
+			return fmt.Errorf("%s: %w", factory.name, err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if len(sinks) > 0 {
+		publishEvents(ctx, cm, sinks)
+	}
+	return nil
+}
+
+// newStaleTipWatchdog alerts when the tip stops moving, re-polling the bootstrap node if one is configured
+func newStaleTipWatchdog(cm *chaintracks.ChainManager, config *Config) *chaintracks.StaleTipWatchdog {
+	staleConfig := chaintracks.StaleTipConfig{Threshold: config.StaleTipThreshold}
+	if len(config.BootstrapURLs) > 0 {
+		staleConfig.Repoll = func(ctx context.Context) {
+			cm.BootstrapSync(ctx, config.BootstrapURLs...)
+		}
+	}
+	return chaintracks.NewStaleTipWatchdog(cm, staleConfig)
+}
+
+// newPollFallback returns a fallback polling the bootstrap URLs while P2P is silent, or nil when it is disabled
+// or P2P is not the header source
+func newPollFallback(cm *chaintracks.ChainManager, config *Config) *chaintracks.PollFallback {
+	if config.P2PFallbackSilence <= 0 || len(config.BootstrapURLs) == 0 || config.HeaderSource != headerSourceP2P {
+		return nil
+	}
+
+	fallback, err := chaintracks.NewPollFallback(cm, chaintracks.PollFallbackConfig{
+		URLs:    config.BootstrapURLs,
+		Silence: config.P2PFallbackSilence,
+	})
+	if err != nil {
+		fatal("Failed to start P2P polling fallback", "error", err)
+	}
+	return fallback
+}
+
 func logChainState(ctx context.Context, cm *chaintracks.ChainManager) {
-	log.Printf("Loaded %d headers", cm.GetHeight(ctx))
 	if tip := cm.GetTip(ctx); tip != nil {
-		log.Printf("Chain tip: %s at height %d", tip.Header.Hash().String(), tip.Height)
+		slog.Info("Loaded headers", "height", tip.Height, "hash", tip.Hash)
 	}
 }
 
-func createFiberApp(ctx context.Context, cm *chaintracks.ChainManager, blockMsgChan <-chan *chaintracks.BlockHeader, port int) *fiber.App {
+func createFiberApp(ctx context.Context, cm *chaintracks.ChainManager, config *Config, logger chaintracks.Logger) *fiber.App {
 	server := NewServer(ctx, cm)
-	server.StartBroadcasting(ctx, blockMsgChan)
+	server.logger = logger
+	if config.SSEReplay > 0 {
+		server.sseReplay = config.SSEReplay
+	}
+	server.maxHeaders = uint32(config.MaxHeaders) //nolint:gosec // Non-negative, set by the operator
+	server.cache = config.Cache
+	server.tsCompat = config.TSCompat
+	server.debugRoutes = config.DebugEnabled && config.DebugAddr == ""
+	server.cdnServe = config.CDNServe
+	server.adminToken = config.AdminToken
+	server.bootstrapURLs = config.BootstrapURLs
+	if config.Auth.Enabled() {
+		auth, err := newJWTAuth(ctx, config.Auth)
+		if err != nil {
+			fatal("Failed to configure auth", "error", err)
+		}
+		server.auth = auth
+	}
+	if config.MutualAuth.PrivateKey != "" {
+		mutual, err := newMutualAuth(config.MutualAuth)
+		if err != nil {
+			fatal("Failed to configure BRC-103 auth", "error", err)
+		}
+		server.mutualAuth = mutual
+		slog.Info("BRC-103 mutual auth enabled", "identityKey", mutual.identityKey())
+	}
+	if config.StaleTipThreshold > 0 {
+		server.staleTip = newStaleTipWatchdog(cm, config)
+		go server.staleTip.Run(ctx)
+	}
+	if fallback := newPollFallback(cm, config); fallback != nil {
+		server.fallback = fallback
+		go fallback.Run(ctx)
+	}
+	if config.MetricsEnabled {
+		server.prom = newPrometheusMetrics(server)
+	}
+	server.StartBroadcasting(ctx, cm.SubscribeEvents(ctx))
 
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
@@ -122,64 +508,110 @@ func createFiberApp(ctx context.Context, cm *chaintracks.ChainManager, blockMsgC
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowHeaders: "*",
-		AllowMethods: "GET,POST,OPTIONS",
+		AllowMethods: "GET,POST,DELETE,OPTIONS",
+		// Browser wallets read the x-bsv-auth-* response headers to verify the server
+		ExposeHeaders: "*",
 	}))
 
-	app.Use(logger.New(logger.Config{
-		Format: "${method} ${path} - ${status} (${latency})\n",
-	}))
+	if config.Compression {
+		app.Use(compressResponses(config.CompressMinSize))
+	}
+
+	if server.prom != nil {
+		app.Use(server.prom.middleware)
+	}
+
+	app.Use(server.requestLogger)
+
+	if config.RateLimit > 0 {
+		app.Use(limiter.New(limiter.Config{
+			Max:        config.RateLimit,
+			Expiration: time.Minute,
+		}))
+	}
 
 	dashboard := NewDashboardHandler(server)
 	server.SetupRoutes(app, dashboard)
 
-	addr := fmt.Sprintf(":%d", port)
+	addr := fmt.Sprintf(":%d", config.Port)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal("Failed to start server", "error", err)
+	}
+	scheme := "http"
+	if config.TLS.Enabled() {
+		tlsConfig, err := newTLSConfig(config.TLS)
+		if err != nil {
+			fatal("Failed to configure TLS", "error", err)
+		}
+		ln = tls.NewListener(ln, tlsConfig)
+		scheme = "https"
+	}
+
 	go func() {
-		log.Printf("Server listening on http://localhost%s", addr)
-		log.Printf("Available endpoints:")
-		log.Printf("  GET  http://localhost%s/ - Status Dashboard", addr)
-		log.Printf("  GET  http://localhost%s/docs - API Documentation (Swagger UI)", addr)
-		log.Printf("  GET  http://localhost%s/v2/network - Network name", addr)
-		log.Printf("  GET  http://localhost%s/v2/height - Current blockchain height", addr)
-		log.Printf("  GET  http://localhost%s/v2/tip/header - Chain tip header", addr)
-		log.Printf("  GET  http://localhost%s/v2/tip/stream - SSE stream for tip updates", addr)
-		log.Printf("Press Ctrl+C to stop")
-
-		if err := app.Listen(addr); err != nil {
-			log.Fatalf("Failed to start server: %v", err)
+		slog.Info("Server listening", "url", scheme+"://localhost"+addr, "dashboard", "/", "docs", "/docs",
+			"mTLS", config.TLS.ClientCAFile != "")
+
+		if err := app.Listener(ln); err != nil {
+			fatal("Failed to start server", "error", err)
 		}
 	}()
 
+	// The socket is served without TLS; it is reachable only by processes on this host allowed to open it
+	if config.UnixSocket != "" {
+		unixLn, err := listenUnix(config.UnixSocket)
+		if err != nil {
+			fatal("Failed to listen on unix socket", "path", config.UnixSocket, "error", err)
+		}
+		go func() {
+			slog.Info("Server listening", "url", "unix://"+config.UnixSocket)
+
+			if err := app.Listener(unixLn); err != nil {
+				fatal("Failed to start server", "error", err)
+			}
+		}()
+	}
+
 	return app
 }
 
 func shutdown(cancel context.CancelFunc, cm *chaintracks.ChainManager, app *fiber.App) {
-	log.Println("Shutting down gracefully...")
+	slog.Info("Shutting down gracefully")
 	cancel()
 	if err := cm.Stop(); err != nil {
-		log.Printf("Error closing P2P: %v", err)
+		slog.Error("Error closing P2P", "error", err)
 	}
 	if err := app.Shutdown(); err != nil {
-		log.Printf("Error closing server: %v", err)
+		slog.Error("Error closing server", "error", err)
 	}
-	log.Println("Server stopped")
+	slog.Info("Server stopped")
 }
 
 // ensureHeadersExist checks if headers exist at storagePath, and if not, copies from checkpoint
-func ensureHeadersExist(storagePath, network string) error {
+// Falls back to downloading from the CDN mirrors when no local checkpoint is available. Headers that fail
+// their file hash check are replaced the same way.
+func ensureHeadersExist(ctx context.Context, storagePath, network string, cdnURLs []string, download chaintracks.CDNDownloadConfig) error {
 	metadataFile := filepath.Join(storagePath, network+"NetBlockHeaders.json")
 
 	if _, err := os.Stat(metadataFile); err == nil {
-		return nil
+		err := chaintracks.VerifyHeaderFiles(storagePath, network)
+		if err == nil {
+			return nil
+		}
+		slog.Warn("Local headers failed verification, reinitializing", "path", storagePath, "error", err)
+		if err := os.Remove(metadataFile); err != nil {
+			return fmt.Errorf("failed to remove corrupted metadata: %w", err)
+		}
 	}
 
-	log.Printf("No headers found at %s, initializing from checkpoint...", storagePath)
+	slog.Info("No headers found, initializing from checkpoint", "path", storagePath)
 
 	checkpointPath := filepath.Join("data", "headers")
 	checkpointMetadata := filepath.Join(checkpointPath, network+"NetBlockHeaders.json")
 
 	if _, err := os.Stat(checkpointMetadata); os.IsNotExist(err) {
-		log.Printf("Warning: No checkpoint headers found at %s", checkpointPath)
-		return nil
+		slog.Info("No checkpoint headers found", "path", checkpointPath)
+		return downloadFromCDN(ctx, storagePath, network, cdnURLs, download)
 	}
 
 	if err := os.MkdirAll(storagePath, 0o750); err != nil {
@@ -191,7 +623,7 @@ func ensureHeadersExist(storagePath, network string) error {
 		return fmt.Errorf("failed to list checkpoint files: %w", err)
 	}
 
-	log.Printf("Copying %d checkpoint files to %s...", len(files), storagePath)
+	slog.Info("Copying checkpoint files", "files", len(files), "path", storagePath)
 	for _, srcFile := range files {
 		dstFile := filepath.Join(storagePath, filepath.Base(srcFile))
 		if err := copyFile(srcFile, dstFile); err != nil {
@@ -199,7 +631,26 @@ func ensureHeadersExist(storagePath, network string) error {
 		}
 	}
 
-	log.Printf("Checkpoint headers initialized successfully")
+	slog.Info("Checkpoint headers initialized")
+	return nil
+}
+
+// downloadFromCDN tries each CDN mirror in turn until one succeeds
+func downloadFromCDN(ctx context.Context, storagePath, network string, cdnURLs []string, download chaintracks.CDNDownloadConfig) error {
+	if len(cdnURLs) == 0 {
+		slog.Warn("No CDN mirrors configured, starting with empty chain", "network", network)
+		return nil
+	}
+
+	for _, cdnURL := range cdnURLs {
+		if err := chaintracks.DownloadCDNHeadersWithConfig(ctx, cdnURL, network, storagePath, download); err != nil {
+			slog.Warn("CDN download failed", "url", cdnURL, "error", err)
+			continue
+		}
+		return nil
+	}
+
+	slog.Warn("All CDN mirrors failed, starting with empty chain")
 	return nil
 }
 
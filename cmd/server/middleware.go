@@ -1 +1,89 @@
 package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requireRole rejects requests whose bearer token does not grant role
+// Without JWT auth configured every request passes, unless BRC-103 mutual auth is required and the request
+// has no verified identity, as on the SSE stream, which the signing middleware cannot cover. EventSource
+// clients cannot set headers, so the token is also accepted in the access_token query parameter.
+func (s *Server) requireRole(role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identified := c.Locals(identityKeyLocal) != nil
+		if s.auth == nil {
+			if !identified && s.mutualAuth != nil && !s.mutualAuth.allowUnauthenticated {
+				c.Set(fiber.HeaderWWWAuthenticate, "BRC-104")
+				return authError(c, fiber.StatusUnauthorized, errMissingAuthHeaders)
+			}
+			return c.Next()
+		}
+		if role == roleRead && identified {
+			return c.Next()
+		}
+
+		token := bearerToken(c)
+		if token == "" {
+			token = c.Query("access_token")
+		}
+
+		if err := s.auth.authorize(token, role); err != nil {
+			status := fiber.StatusUnauthorized
+			if errors.Is(err, errMissingRole) {
+				status = fiber.StatusForbidden
+			} else {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="chaintracks"`)
+			}
+			return authError(c, status, err)
+		}
+		return c.Next()
+	}
+}
+
+// requireAdmin guards the /admin group with ADMIN_TOKEN, falling back to the JWT admin role
+// The static token keeps the recovery operations reachable when the JWT issuer is unavailable. It is only
+// accepted in the Authorization header, never the query string, so it stays out of access logs.
+func (s *Server) requireAdmin() fiber.Handler {
+	role := s.requireRole(roleAdmin)
+	return func(c *fiber.Ctx) error {
+		if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(bearerToken(c)), []byte(s.adminToken)) == 1 {
+			return c.Next()
+		}
+		if s.auth == nil {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="chaintracks-admin"`)
+			return authError(c, fiber.StatusUnauthorized, errInvalidAdminToken)
+		}
+		return role(c)
+	}
+}
+
+// bearerToken returns the token from a Bearer Authorization header, or an empty string
+func bearerToken(c *fiber.Ctx) string {
+	scheme, value, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// requireIdentity verifies BRC-103 mutual authentication and signs the response
+// Without mutual auth configured every request passes. A verified identity key grants the read role.
+func (s *Server) requireIdentity() fiber.Handler {
+	if s.mutualAuth == nil {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return s.mutualAuth.middleware
+}
+
+// authError writes an authentication failure in the API's error format
+func authError(c *fiber.Ctx, status int, err error) error {
+	return c.Status(status).JSON(Response{
+		Status:      "error",
+		Code:        "ERR_UNAUTHORIZED",
+		Description: err.Error(),
+	})
+}
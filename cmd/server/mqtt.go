@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+const (
+	defaultMQTTTopicPrefix = "chaintracks"
+	defaultMQTTQoS         = 1
+	mqttTimeout            = 10 * time.Second
+)
+
+var errMQTTTimeout = errors.New("timed out waiting for broker")
+
+// MQTTConfig configures the MQTT tip publisher; it is disabled when BrokerURL is empty
+type MQTTConfig struct {
+	BrokerURL   string // e.g. tcp://broker:1883 or ssl://broker:8883
+	TopicPrefix string // Tips go to <prefix>/<network>/tip, reorgs to <prefix>/<network>/reorg
+	QoS         byte   // 0, 1 or 2
+	ClientID    string // Defaults to chaintracks-<network>
+	Username    string
+	Password    string
+}
+
+// mqttPublisher is the subset of mqtt.Client used by the sink
+type mqttPublisher interface {
+	Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token
+	Disconnect(quiesce uint)
+}
+
+// mqttSink publishes the current tip as a retained message, so a device that connects later receives it at once
+// Tip messages carry only the tip header JSON to keep them small; reorgs also publish the full event.
+type mqttSink struct {
+	client     mqttPublisher
+	tipTopic   string
+	reorgTopic string
+	qos        byte
+}
+
+// newMQTTSink connects to the broker, reconnecting automatically if the connection drops later
+func newMQTTSink(config MQTTConfig, network string) (*mqttSink, error) {
+	clientID := config.ClientID
+	if clientID == "" {
+		clientID = "chaintracks-" + network
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(config.BrokerURL).
+		SetClientID(clientID).
+		SetUsername(config.Username).
+		SetPassword(config.Password).
+		SetAutoReconnect(true)
+
+	client := mqtt.NewClient(opts)
+	if err := waitMQTT(client.Connect()); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", config.BrokerURL, err)
+	}
+
+	prefix := fmt.Sprintf("%s/%s/", config.TopicPrefix, network)
+	return &mqttSink{client: client, tipTopic: prefix + "tip", reorgTopic: prefix + "reorg", qos: config.QoS}, nil
+}
+
+// waitMQTT waits for a token to complete
+func waitMQTT(token mqtt.Token) error {
+	if !token.WaitTimeout(mqttTimeout) {
+		return errMQTTTimeout
+	}
+	return token.Error()
+}
+
+// Name implements eventSink
+func (m *mqttSink) Name() string {
+	return "mqtt"
+}
+
+// Publish implements eventSink, waiting for the broker to acknowledge at the configured QoS
+func (m *mqttSink) Publish(_ context.Context, event *chaintracks.ChainEvent, payload []byte) error {
+	if event.Type == chaintracks.EventReorg {
+		if err := waitMQTT(m.client.Publish(m.reorgTopic, m.qos, false, payload)); err != nil {
+			return err
+		}
+	}
+	if event.Tip == nil {
+		return nil
+	}
+
+	tip, err := json.Marshal(event.Tip)
+	if err != nil {
+		return fmt.Errorf("failed to encode tip: %w", err)
+	}
+	return waitMQTT(m.client.Publish(m.tipTopic, m.qos, true, tip))
+}
+
+// Close implements eventSink, giving in-flight messages time to complete
+func (m *mqttSink) Close() error {
+	m.client.Disconnect(uint(mqttTimeout / time.Millisecond))
+	return nil
+}
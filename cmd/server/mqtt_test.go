@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// runMQTTBroker starts an in-process MQTT broker on a random port and returns it with its tcp:// URL
+func runMQTTBroker(t *testing.T) (*mochi.Server, string) {
+	t.Helper()
+
+	broker := mochi.New(&mochi.Options{InlineClient: true})
+	require.NoError(t, broker.AddHook(new(auth.AllowHook), nil))
+	tcp := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0"})
+	require.NoError(t, broker.AddListener(tcp))
+	require.NoError(t, broker.Serve())
+	t.Cleanup(func() {
+		_ = broker.Close()
+	})
+	return broker, "tcp://" + tcp.Address()
+}
+
+// subscribeMQTT delivers messages on filter to the returned channel
+func subscribeMQTT(t *testing.T, broker *mochi.Server, filter string, id int) <-chan packets.Packet {
+	t.Helper()
+
+	received := make(chan packets.Packet, 10)
+	require.NoError(t, broker.Subscribe(filter, id, func(_ *mochi.Client, _ packets.Subscription, pk packets.Packet) {
+		received <- pk
+	}))
+	return received
+}
+
+func receiveMQTT(t *testing.T, received <-chan packets.Packet) packets.Packet {
+	t.Helper()
+
+	select {
+	case pk := <-received:
+		return pk
+	case <-time.After(2 * time.Second):
+		t.Fatal("no MQTT message received")
+		return packets.Packet{}
+	}
+}
+
+func TestMQTTSinkPublish(t *testing.T) {
+	broker, url := runMQTTBroker(t)
+	cm := newSyntheticChainManager(t, 3)
+	tip := cm.GetTip(t.Context())
+
+	sink, err := newMQTTSink(MQTTConfig{BrokerURL: url, TopicPrefix: "ct", QoS: 1}, "test")
+	require.NoError(t, err)
+	defer func() {
+		_ = sink.Close()
+	}()
+
+	received := subscribeMQTT(t, broker, "ct/test/#", 1)
+
+	reorg := &chaintracks.ChainEvent{Seq: 4, Type: chaintracks.EventReorg, Tip: tip, Reorg: &chaintracks.ReorgInfo{ForkHeight: 1}}
+	require.NoError(t, sink.Publish(t.Context(), reorg, []byte(`{"seq":4}`)))
+
+	pk := receiveMQTT(t, received)
+	assert.Equal(t, "ct/test/reorg", pk.TopicName)
+	assert.JSONEq(t, `{"seq":4}`, string(pk.Payload))
+
+	pk = receiveMQTT(t, received)
+	assert.Equal(t, "ct/test/tip", pk.TopicName)
+	var header chaintracks.BlockHeader
+	require.NoError(t, json.Unmarshal(pk.Payload, &header))
+	assert.Equal(t, tip.Hash, header.Hash)
+	assert.Equal(t, tip.Height, header.Height)
+
+	// A device subscribing later gets the retained tip straight away
+	late := subscribeMQTT(t, broker, "ct/test/tip", 2)
+	pk = receiveMQTT(t, late)
+	assert.Equal(t, "ct/test/tip", pk.TopicName)
+	assert.True(t, pk.FixedHeader.Retain)
+}
+
+func TestNewMQTTSinkUnreachable(t *testing.T) {
+	_, err := newMQTTSink(MQTTConfig{BrokerURL: "tcp://127.0.0.1:1", TopicPrefix: "ct"}, "main")
+	require.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+const (
+	defaultNATSSubjectPrefix = "chaintracks"
+	defaultNATSStream        = "CHAINTRACKS"
+	defaultNATSStreamMaxAge  = 7 * 24 * time.Hour
+)
+
+// NATSConfig configures the NATS event sink; it is disabled when URL is empty
+type NATSConfig struct {
+	URL           string
+	SubjectPrefix string // Events go to <prefix>.<network>.<type>, e.g. chaintracks.main.tip
+	Credentials   string // Optional .creds file
+	Token         string // Optional auth token
+	JetStream     bool   // Persist events in a stream so consumers can replay them
+	Stream        string
+	StreamMaxAge  time.Duration // How long the stream keeps events, 0 keeps them forever
+}
+
+// natsSink publishes events to NATS subjects, through JetStream when enabled
+type natsSink struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream // nil publishes with core NATS, without persistence
+	prefix  string
+	network string
+}
+
+// newNATSSink connects to the server and, with JetStream, creates or updates the stream covering the subjects
+func newNATSSink(ctx context.Context, config NATSConfig, network string) (*natsSink, error) {
+	opts := []nats.Option{nats.Name("chaintracks-server"), nats.MaxReconnects(-1)}
+	if config.Credentials != "" {
+		opts = append(opts, nats.UserCredentials(config.Credentials))
+	}
+	if config.Token != "" {
+		opts = append(opts, nats.Token(config.Token))
+	}
+
+	conn, err := nats.Connect(config.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	sink := &natsSink{conn: conn, prefix: config.SubjectPrefix, network: network}
+	if !config.JetStream {
+		return sink, nil
+	}
+
+	if sink.js, err = jetstream.New(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open JetStream: %w", err)
+	}
+	if _, err = sink.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     config.Stream,
+		Subjects: []string{fmt.Sprintf("%s.%s.>", sink.prefix, network)},
+		MaxAge:   config.StreamMaxAge,
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", config.Stream, err)
+	}
+	return sink, nil
+}
+
+// message builds the NATS message for an event
+// The message ID lets JetStream drop duplicates when an event is replayed after a restart.
+func (n *natsSink) message(event *chaintracks.ChainEvent, payload []byte) *nats.Msg {
+	msg := nats.NewMsg(fmt.Sprintf("%s.%s.%s", n.prefix, n.network, event.Type))
+	msg.Data = payload
+	msg.Header.Set(nats.MsgIdHdr, fmt.Sprintf("%s-%d", n.network, event.Seq))
+	return msg
+}
+
+// Name implements eventSink
+func (n *natsSink) Name() string {
+	return "nats"
+}
+
+// Publish implements eventSink; with JetStream it waits for the stream to acknowledge the event
+func (n *natsSink) Publish(ctx context.Context, event *chaintracks.ChainEvent, payload []byte) error {
+	msg := n.message(event, payload)
+	if n.js == nil {
+		return n.conn.PublishMsg(msg)
+	}
+	_, err := n.js.PublishMsg(ctx, msg)
+	return err
+}
+
+// Close implements eventSink, flushing pending messages
+func (n *natsSink) Close() error {
+	return n.conn.Drain()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// runNATSServer starts an in-process NATS server with JetStream on a random port
+func runNATSServer(t *testing.T) *server.Server {
+	t.Helper()
+
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	require.NoError(t, err)
+	go ns.Start()
+	require.True(t, ns.ReadyForConnections(5*time.Second))
+	t.Cleanup(ns.Shutdown)
+	return ns
+}
+
+func TestNATSSinkMessage(t *testing.T) {
+	sink := &natsSink{prefix: "chaintracks", network: "main"}
+
+	msg := sink.message(&chaintracks.ChainEvent{Seq: 42, Type: chaintracks.EventReorg}, []byte(`{"seq":42}`))
+	assert.Equal(t, "chaintracks.main.reorg", msg.Subject)
+	assert.JSONEq(t, `{"seq":42}`, string(msg.Data))
+	assert.Equal(t, "main-42", msg.Header.Get(nats.MsgIdHdr))
+}
+
+func TestNATSSinkPublish(t *testing.T) {
+	ns := runNATSServer(t)
+	config := NATSConfig{URL: ns.ClientURL(), SubjectPrefix: "ct"}
+
+	sink, err := newNATSSink(t.Context(), config, "test")
+	require.NoError(t, err)
+
+	sub, err := sink.conn.SubscribeSync("ct.test.>")
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Publish(t.Context(), &chaintracks.ChainEvent{Seq: 1, Type: chaintracks.EventTipAdvanced}, []byte(`{"seq":1}`)))
+
+	msg, err := sub.NextMsg(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "ct.test.tip", msg.Subject)
+	require.NoError(t, sink.Close())
+}
+
+func TestNATSSinkJetStream(t *testing.T) {
+	ns := runNATSServer(t)
+	config := NATSConfig{URL: ns.ClientURL(), SubjectPrefix: "ct", JetStream: true, Stream: "CT", StreamMaxAge: time.Hour}
+
+	sink, err := newNATSSink(t.Context(), config, "test")
+	require.NoError(t, err)
+	defer func() {
+		_ = sink.Close()
+	}()
+
+	tip := &chaintracks.ChainEvent{Seq: 1, Type: chaintracks.EventTipAdvanced}
+	require.NoError(t, sink.Publish(t.Context(), tip, []byte(`{"seq":1}`)))
+	require.NoError(t, sink.Publish(t.Context(), tip, []byte(`{"seq":1}`)), "replayed events are deduplicated")
+	require.NoError(t, sink.Publish(t.Context(), &chaintracks.ChainEvent{Seq: 2, Type: chaintracks.EventReorg}, []byte(`{"seq":2}`)))
+
+	stream, err := sink.js.Stream(t.Context(), "CT")
+	require.NoError(t, err)
+	info, err := stream.Info(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ct.test.>"}, info.Config.Subjects)
+	assert.Equal(t, time.Hour, info.Config.MaxAge)
+	assert.Equal(t, uint64(2), info.State.Msgs)
+
+	msg, err := stream.GetMsg(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "ct.test.reorg", msg.Subject)
+
+	// Restarting the sink keeps the stream and its events
+	again, err := newNATSSink(t.Context(), config, "test")
+	require.NoError(t, err)
+	require.NoError(t, again.Close())
+	info, err = stream.Info(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), info.State.Msgs)
+}
+
+func TestNewNATSSinkUnreachable(t *testing.T) {
+	_, err := newNATSSink(t.Context(), NATSConfig{URL: "nats://127.0.0.1:1"}, "main")
+	require.Error(t, err)
+}
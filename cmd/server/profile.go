@@ -0,0 +1,103 @@
+package main
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// Profile is a named preset of deployment settings
+// Individual environment variables still override any value a profile sets
+type Profile struct {
+	Name             string
+	RateLimit        int           // Requests per minute per client IP, 0 disables limiting
+	CDNFallback      bool          // Download headers from CDN mirrors when no checkpoint is present
+	LagThreshold     uint32        // Blocks behind the network before reporting "behind"
+	QuietPeriod      time.Duration // Time without announcements before reporting "network-quiet"
+	WatchdogInterval time.Duration
+	SSEReplay        uint32 // Max missed tips replayed to a resuming SSE client
+}
+
+// Profile names accepted by the PROFILE environment variable
+const (
+	ProfileDefault       = "default"
+	ProfileArchival      = "archival"
+	ProfileEdge          = "edge"
+	ProfileEmbeddedLight = "embedded-light"
+	ProfilePublicAPI     = "public-api"
+)
+
+//nolint:gochecknoglobals // Read-only preset table
+var profiles = map[string]Profile{
+	ProfileDefault: {
+		CDNFallback:      true,
+		LagThreshold:     3,
+		QuietPeriod:      time.Hour,
+		WatchdogInterval: 5 * time.Minute,
+		SSEReplay:        1000,
+	},
+	// Full node of record: never throttle, tight lag reporting, deep replay for catching-up consumers
+	ProfileArchival: {
+		CDNFallback:      true,
+		LagThreshold:     1,
+		QuietPeriod:      time.Hour,
+		WatchdogInterval: time.Minute,
+		SSEReplay:        10000,
+	},
+	// Regional cache in front of a trusted upstream: moderate limits, short replay
+	ProfileEdge: {
+		RateLimit:        600,
+		CDNFallback:      true,
+		LagThreshold:     3,
+		QuietPeriod:      time.Hour,
+		WatchdogInterval: 5 * time.Minute,
+		SSEReplay:        100,
+	},
+	// Sidecar next to a single application: checkpoint only, relaxed monitoring
+	ProfileEmbeddedLight: {
+		CDNFallback:      false,
+		LagThreshold:     6,
+		QuietPeriod:      2 * time.Hour,
+		WatchdogInterval: 15 * time.Minute,
+		SSEReplay:        10,
+	},
+	// Internet-facing service: strict per-client limits
+	ProfilePublicAPI: {
+		RateLimit:        120,
+		CDNFallback:      true,
+		LagThreshold:     3,
+		QuietPeriod:      time.Hour,
+		WatchdogInterval: 5 * time.Minute,
+		SSEReplay:        1000,
+	},
+}
+
+// LookupProfile returns the named profile and whether it exists
+func LookupProfile(name string) (Profile, bool) {
+	if name == "" {
+		name = ProfileDefault
+	}
+	p, ok := profiles[name]
+	p.Name = name
+	return p, ok
+}
+
+// resolveProfile returns the named profile, falling back to the default for unknown names
+func resolveProfile(name string) Profile {
+	p, ok := LookupProfile(name)
+	if !ok {
+		slog.Warn("Unknown profile, using default", "profile", name, "available", ProfileNames(), "default", ProfileDefault)
+		p, _ = LookupProfile(ProfileDefault)
+	}
+	return p
+}
+
+// ProfileNames returns the names of all built-in profiles, sorted
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+func TestLookupProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  string
+		expected string
+		found    bool
+	}{
+		{name: "EmptyNameIsDefault", profile: "", expected: ProfileDefault, found: true},
+		{name: "FindsPublicAPI", profile: ProfilePublicAPI, expected: ProfilePublicAPI, found: true},
+		{name: "FindsEmbeddedLight", profile: ProfileEmbeddedLight, expected: ProfileEmbeddedLight, found: true},
+		{name: "UnknownNameNotFound", profile: "turbo", expected: "turbo", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := LookupProfile(tt.profile)
+			assert.Equal(t, tt.found, ok)
+			assert.Equal(t, tt.expected, p.Name)
+		})
+	}
+}
+
+func TestProfileNames(t *testing.T) {
+	assert.Equal(t, []string{
+		ProfileArchival, ProfileDefault, ProfileEdge, ProfileEmbeddedLight, ProfilePublicAPI,
+	}, ProfileNames())
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	mainCDN := chaintracks.DefaultsForNetwork("main").CDNURLs
+
+	tests := []struct {
+		name              string
+		envVars           map[string]string
+		expectedProfile   string
+		expectedRateLimit int
+		expectedLag       uint32
+		expectedInterval  time.Duration
+		expectedReplay    uint32
+		expectedCDN       []string
+	}{
+		{
+			name:             "DefaultProfileKeepsExistingBehavior",
+			expectedProfile:  ProfileDefault,
+			expectedLag:      3,
+			expectedInterval: 5 * time.Minute,
+			expectedReplay:   1000,
+			expectedCDN:      mainCDN,
+		},
+		{
+			name:              "PublicAPIEnablesRateLimit",
+			envVars:           map[string]string{"PROFILE": "public-api"},
+			expectedProfile:   ProfilePublicAPI,
+			expectedRateLimit: 120,
+			expectedLag:       3,
+			expectedInterval:  5 * time.Minute,
+			expectedReplay:    1000,
+			expectedCDN:       mainCDN,
+		},
+		{
+			name:             "EmbeddedLightDisablesCDNFallback",
+			envVars:          map[string]string{"PROFILE": "embedded-light"},
+			expectedProfile:  ProfileEmbeddedLight,
+			expectedLag:      6,
+			expectedInterval: 15 * time.Minute,
+			expectedReplay:   10,
+		},
+		{
+			name: "EnvironmentOverridesProfile",
+			envVars: map[string]string{
+				"PROFILE":           "embedded-light",
+				"RATE_LIMIT":        "50",
+				"LAG_THRESHOLD":     "2",
+				"WATCHDOG_INTERVAL": "1m",
+				"CDN_URLS":          "https://cdn.example.com",
+			},
+			expectedProfile:   ProfileEmbeddedLight,
+			expectedRateLimit: 50,
+			expectedLag:       2,
+			expectedInterval:  time.Minute,
+			expectedReplay:    10,
+			expectedCDN:       []string{"https://cdn.example.com"},
+		},
+		{
+			name:             "UnknownProfileFallsBackToDefault",
+			envVars:          map[string]string{"PROFILE": "turbo"},
+			expectedProfile:  ProfileDefault,
+			expectedLag:      3,
+			expectedInterval: 5 * time.Minute,
+			expectedReplay:   1000,
+			expectedCDN:      mainCDN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := withEnvVars(t, tt.envVars)
+			defer cleanup()
+
+			config := LoadConfig()
+
+			require.NotNil(t, config)
+			assert.Equal(t, tt.expectedProfile, config.Profile)
+			assert.Equal(t, tt.expectedRateLimit, config.RateLimit)
+			assert.Equal(t, tt.expectedLag, config.LagThreshold)
+			assert.Equal(t, tt.expectedInterval, config.WatchdogInterval)
+			assert.Equal(t, tt.expectedReplay, config.SSEReplay)
+			assert.Equal(t, tt.expectedCDN, config.CDNURLs)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+const prometheusNamespace = "chaintracks"
+
+// prometheusMetrics holds the collectors served on /metrics
+// Chain state gauges are read from the ChainManager at scrape time; only HTTP latency and reorg depth are recorded as they happen.
+type prometheusMetrics struct {
+	registry     *prometheus.Registry
+	httpDuration *prometheus.HistogramVec
+	reorgDepth   prometheus.Histogram
+}
+
+// newPrometheusMetrics registers the chain, SSE and HTTP collectors for a server
+func newPrometheusMetrics(s *Server) *prometheusMetrics {
+	m := &prometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: prometheusNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status code",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		reorgDepth: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: prometheusNamespace,
+			Name:      "reorg_depth_blocks",
+			Help:      "Number of main chain blocks orphaned per reorg",
+			Buckets:   []float64{1, 2, 3, 5, 10, 20, 50, 100},
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.reorgDepth,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "height",
+			Help:      "Height of the local chain tip",
+		}, func() float64 {
+			return float64(s.cm.GetHeight(s.ctx))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "tip_lag_seconds",
+			Help:      "Seconds between now and the timestamp of the local chain tip",
+		}, func() float64 {
+			tip := s.cm.GetTip(s.ctx)
+			if tip == nil {
+				return 0
+			}
+			return time.Since(time.Unix(int64(tip.Header.Timestamp), 0)).Seconds()
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "peers",
+			Help:      "Number of connected P2P peers",
+		}, func() float64 {
+			return float64(s.cm.PeerCount())
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "headers_processed_total",
+			Help:      "Headers accepted into the chain; rate() gives headers per second during sync",
+		}, func() float64 {
+			return float64(s.cm.GetMetrics().HeadersProcessed)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "reorgs_total",
+			Help:      "Chain reorganizations observed, persisted across restarts",
+		}, func() float64 {
+			return float64(s.cm.GetMetrics().TotalReorgs)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "load_duration_seconds",
+			Help:      "Time startup spent loading the local header files",
+		}, func() float64 {
+			return s.cm.GetMetrics().LoadSeconds
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "sse_clients",
+			Help:      "Number of connected /v2/tip/stream clients",
+		}, func() float64 {
+			s.sseClientsMu.RLock()
+			defer s.sseClientsMu.RUnlock()
+			return float64(len(s.sseClients))
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "sse_dropped_events_total",
+			Help:      "Events dropped from the queues of /v2/tip/stream clients that fell too far behind",
+		}, func() float64 {
+			return float64(s.sseDropped.Load())
+		}),
+	)
+
+	if s.staleTip != nil {
+		m.registry.MustRegister(
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: prometheusNamespace,
+				Name:      "seconds_since_new_tip",
+				Help:      "Seconds since this node first saw the current tip",
+			}, func() float64 {
+				return s.staleTip.TipAge().Seconds()
+			}),
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: prometheusNamespace,
+				Name:      "tip_stale",
+				Help:      "1 while the stale-tip watchdog is alerting, else 0",
+			}, func() float64 {
+				if s.staleTip.Stale() {
+					return 1
+				}
+				return 0
+			}),
+		)
+	}
+	if s.fallback != nil {
+		m.registry.MustRegister(
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: prometheusNamespace,
+				Name:      "p2p_fallback_active",
+				Help:      "1 while P2P is silent and bootstrap URLs are polled instead, else 0",
+			}, func() float64 {
+				if s.fallback.Status().Active {
+					return 1
+				}
+				return 0
+			}),
+			prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: prometheusNamespace,
+				Name:      "p2p_fallback_polls_total",
+				Help:      "Bootstrap polls made while P2P was silent",
+			}, func() float64 {
+				return float64(s.fallback.Status().Polls)
+			}),
+		)
+	}
+	return m
+}
+
+// middleware records the latency of every request under its route pattern
+func (m *prometheusMetrics) middleware(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+	}
+	m.httpDuration.WithLabelValues(c.Method(), c.Route().Path, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// observeEvent records the depth of reorg events
+func (m *prometheusMetrics) observeEvent(event *chaintracks.ChainEvent) {
+	if event.Type == chaintracks.EventReorg && event.Reorg != nil {
+		m.reorgDepth.Observe(float64(len(event.Reorg.OrphanedHashes)))
+	}
+}
+
+// handler serves the registry in the Prometheus text format
+func (m *prometheusMetrics) handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// newPrometheusTestApp serves the routes with Prometheus metrics enabled, as createFiberApp does
+func newPrometheusTestApp(t *testing.T, server *Server) *fiber.App {
+	t.Helper()
+
+	server.prom = newPrometheusMetrics(server)
+	app := fiber.New()
+	app.Use(server.prom.middleware)
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	return app
+}
+
+func scrapeMetrics(t *testing.T, app *fiber.App) string {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 10))
+	app := newPrometheusTestApp(t, server)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v2/height", nil))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	server.prom.observeEvent(&chaintracks.ChainEvent{
+		Type:  chaintracks.EventReorg,
+		Reorg: &chaintracks.ReorgInfo{OrphanedHashes: []chainhash.Hash{{1}, {2}}},
+	})
+
+	body := scrapeMetrics(t, app)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Height", expected: "chaintracks_height 9\n"},
+		{name: "TipLag", expected: "chaintracks_tip_lag_seconds "},
+		{name: "Peers", expected: "chaintracks_peers 0\n"},
+		{name: "HeadersProcessed", expected: "chaintracks_headers_processed_total "},
+		{name: "Reorgs", expected: "chaintracks_reorgs_total "},
+		{name: "ReorgDepth", expected: "chaintracks_reorg_depth_blocks_sum 2\n"},
+		{name: "LoadDuration", expected: "chaintracks_load_duration_seconds "},
+		{name: "SSEClients", expected: "chaintracks_sse_clients 0\n"},
+		{name: "SSEDroppedEvents", expected: "chaintracks_sse_dropped_events_total 0\n"},
+		{name: "HTTPLatencyByRoute", expected: `chaintracks_http_request_duration_seconds_count{method="GET",route="/v2/height",status="200"} 1`},
+		{name: "GoRuntime", expected: "go_goroutines "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, body, tt.expected)
+		})
+	}
+}
+
+func TestPrometheusMetricsDisabled(t *testing.T) {
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 1))
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestPrometheusReorgDepthIgnoresTips(t *testing.T) {
+	server := NewServer(t.Context(), newSyntheticChainManager(t, 1))
+	app := newPrometheusTestApp(t, server)
+
+	server.prom.observeEvent(&chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced})
+
+	assert.Contains(t, scrapeMetrics(t, app), "chaintracks_reorg_depth_blocks_count 0\n")
+}
+
+func TestPrometheusStaleTipMetrics(t *testing.T) {
+	cm := newSyntheticChainManager(t, 1)
+
+	t.Run("OmittedWithoutWatchdog", func(t *testing.T) {
+		body := scrapeMetrics(t, newPrometheusTestApp(t, NewServer(t.Context(), cm)))
+		assert.NotContains(t, body, "chaintracks_tip_stale")
+	})
+
+	t.Run("ReportedWithWatchdog", func(t *testing.T) {
+		server := NewServer(t.Context(), cm)
+		server.staleTip = chaintracks.NewStaleTipWatchdog(cm, chaintracks.StaleTipConfig{})
+		server.staleTip.Check(t.Context())
+
+		body := scrapeMetrics(t, newPrometheusTestApp(t, server))
+		assert.Contains(t, body, "chaintracks_tip_stale 0\n")
+		assert.Contains(t, body, "chaintracks_seconds_since_new_tip ")
+	})
+}
+
+func TestPrometheusPollFallbackMetrics(t *testing.T) {
+	cm := newSyntheticChainManager(t, 1)
+
+	t.Run("OmittedWithoutFallback", func(t *testing.T) {
+		body := scrapeMetrics(t, newPrometheusTestApp(t, NewServer(t.Context(), cm)))
+		assert.NotContains(t, body, "chaintracks_p2p_fallback_active")
+	})
+
+	t.Run("ReportedWithFallback", func(t *testing.T) {
+		server := NewServer(t.Context(), cm)
+		fallback, err := chaintracks.NewPollFallback(cm, chaintracks.PollFallbackConfig{URLs: []string{"http://bootstrap.example"}})
+		require.NoError(t, err)
+		server.fallback = fallback
+
+		body := scrapeMetrics(t, newPrometheusTestApp(t, server))
+		assert.Contains(t, body, "chaintracks_p2p_fallback_active 0\n")
+		assert.Contains(t, body, "chaintracks_p2p_fallback_polls_total 0\n")
+	})
+}
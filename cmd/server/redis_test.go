@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// storedTip decodes the header JSON held under key
+func storedTip(t *testing.T, mr *miniredis.Miniredis, key string) chaintracks.BlockHeader {
+	t.Helper()
+
+	data, err := mr.Get(key)
+	require.NoError(t, err)
+	var header chaintracks.BlockHeader
+	require.NoError(t, json.Unmarshal([]byte(data), &header))
+	return header
+}
+
+func TestRedisSinkPublish(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cm := newSyntheticChainManager(t, 5)
+	start := cm.GetTip(t.Context())
+
+	config := RedisConfig{URL: "redis://" + mr.Addr(), ChannelPrefix: "ct", TipKey: "ct:test:last-tip"}
+	sink, err := newRedisSink(t.Context(), config, "test", start)
+	require.NoError(t, err)
+	defer func() {
+		_ = sink.Close()
+	}()
+	assert.Equal(t, start.Hash, storedTip(t, mr, config.TipKey).Hash, "tip key is seeded on startup")
+
+	pubsub := sink.client.Subscribe(t.Context(), "ct:test:tip", "ct:test:reorg")
+	defer func() {
+		_ = pubsub.Close()
+	}()
+	_, err = pubsub.Receive(t.Context())
+	require.NoError(t, err)
+	messages := pubsub.Channel()
+
+	extendSyntheticChain(t, cm, 1)
+	tip := cm.GetTip(t.Context())
+	require.NoError(t, sink.Publish(t.Context(), &chaintracks.ChainEvent{Seq: 6, Type: chaintracks.EventTipAdvanced, Tip: tip}, []byte(`{"seq":6}`)))
+	require.NoError(t, sink.Publish(t.Context(), &chaintracks.ChainEvent{Seq: 7, Type: chaintracks.EventReorg, Tip: tip}, []byte(`{"seq":7}`)))
+
+	expected := []struct{ channel, payload string }{
+		{"ct:test:tip", `{"seq":6}`},
+		{"ct:test:reorg", `{"seq":7}`},
+		{"ct:test:tip", `{"seq":7}`},
+	}
+	for _, want := range expected {
+		select {
+		case msg := <-messages:
+			assert.Equal(t, want.channel, msg.Channel)
+			assert.JSONEq(t, want.payload, msg.Payload)
+		case <-time.After(time.Second):
+			t.Fatalf("no message on %s", want.channel)
+		}
+	}
+	assert.Equal(t, tip.Height, storedTip(t, mr, config.TipKey).Height)
+}
+
+func TestRedisSinkWithoutTipKey(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cm := newSyntheticChainManager(t, 1)
+
+	sink, err := newRedisSink(t.Context(), RedisConfig{URL: "redis://" + mr.Addr(), ChannelPrefix: "ct"}, "test", cm.GetTip(t.Context()))
+	require.NoError(t, err)
+	defer func() {
+		_ = sink.Close()
+	}()
+
+	require.NoError(t, sink.Publish(t.Context(), &chaintracks.ChainEvent{Seq: 1, Type: chaintracks.EventTipAdvanced, Tip: cm.GetTip(t.Context())}, []byte(`{}`)))
+	assert.Empty(t, mr.Keys())
+}
+
+func TestNewRedisSinkErrors(t *testing.T) {
+	_, err := newRedisSink(t.Context(), RedisConfig{URL: "http://localhost"}, "main", nil)
+	require.ErrorContains(t, err, "invalid URL")
+
+	_, err = newRedisSink(t.Context(), RedisConfig{URL: "redis://127.0.0.1:1"}, "main", nil)
+	require.ErrorContains(t, err, "failed to connect")
+}
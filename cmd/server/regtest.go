@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// regtestMineRequest is the optional body of POST /admin/regtest/mine
+type regtestMineRequest struct {
+	MerkleRoots []string `json:"merkleRoots"` // Merkle roots of the first mined blocks, hex
+}
+
+// isRegtest reports whether the server runs a regtest chain whose blocks are mined through /admin/regtest
+func (s *Server) isRegtest() bool {
+	network, _ := s.cm.GetNetwork(s.ctx)
+	return network == chaintracks.NetworkRegtest
+}
+
+// prepareRegtestStorage points a regtest server at a fresh temporary directory, so every run starts from genesis
+// The returned function removes the directory.
+func prepareRegtestStorage(config *Config) (func(), error) {
+	dir, err := os.MkdirTemp("", "chaintracks-regtest-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regtest storage: %w", err)
+	}
+	config.StoragePath = dir
+	return func() { _ = os.RemoveAll(dir) }, nil
+}
+
+// HandleRegtestMine mines count blocks (default 1) on the tip and returns them, oldest first
+// A JSON body of {"merkleRoots": [...]} sets the merkle roots of the first blocks so a wallet's proofs verify.
+func (s *Server) HandleRegtestMine(c *fiber.Ctx) error {
+	var req regtestMineRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: "Body must be {\"merkleRoots\": [\"<hex>\", ...]}",
+			})
+		}
+	}
+
+	roots := make([]chainhash.Hash, len(req.MerkleRoots))
+	for i, root := range req.MerkleRoots {
+		hash, err := chainhash.NewHashFromHex(root)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: fmt.Sprintf("Invalid merkle root at index %d", i),
+			})
+		}
+		roots[i] = *hash
+	}
+
+	headers, err := s.cm.MineBlocks(c.UserContext(), c.QueryInt("count", 1), roots...)
+	return regtestResult(c, headers, err)
+}
+
+// HandleRegtestReorg replaces the top depth blocks (default 1) with count new ones (default depth+1)
+func (s *Server) HandleRegtestReorg(c *fiber.Ctx) error {
+	depth := c.QueryInt("depth", 1)
+	headers, err := s.cm.MineReorg(c.UserContext(), depth, c.QueryInt("count", depth+1))
+	return regtestResult(c, headers, err)
+}
+
+// regtestResult reports mined headers or maps a mining error to a response
+func regtestResult(c *fiber.Ctx, headers []*chaintracks.BlockHeader, err error) error {
+	if err != nil {
+		status, code := fiber.StatusInternalServerError, "ERR_INTERNAL"
+		if errors.Is(err, chaintracks.ErrInvalidMineRequest) {
+			status, code = fiber.StatusBadRequest, "ERR_INVALID_PARAMS"
+		}
+		return c.Status(status).JSON(Response{
+			Status:      "error",
+			Code:        code,
+			Description: err.Error(),
+		})
+	}
+
+	return c.JSON(Response{
+		Status: "success",
+		Value:  headers,
+	})
+}
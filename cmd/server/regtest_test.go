@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// setupRegtestApp serves a regtest chain at genesis with ADMIN_TOKEN set
+func setupRegtestApp(t *testing.T) (*fiber.App, *chaintracks.ChainManager) {
+	t.Helper()
+
+	cm, err := chaintracks.NewChainManager(t.Context(), chaintracks.NetworkRegtest, t.TempDir(), nil)
+	require.NoError(t, err)
+	server := NewServer(t.Context(), cm)
+	server.adminToken = testAdminToken
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	return app, cm
+}
+
+// regtestPost performs a POST request with an optional bearer token and JSON body and returns the status code
+func regtestPost(t *testing.T, app *fiber.App, path, token, body string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRegtestMine(t *testing.T) {
+	t.Run("MinesOnTip", func(t *testing.T) {
+		app, cm := setupRegtestApp(t)
+
+		status, body := adminPost(t, app, "/admin/regtest/mine?count=3")
+		require.Equal(t, http.StatusOK, status, body)
+		assert.Len(t, body.Value, 3)
+		assert.Equal(t, uint32(3), cm.GetHeight(t.Context()))
+
+		status, _ = adminPost(t, app, "/admin/regtest/mine")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, uint32(4), cm.GetHeight(t.Context()), "count defaults to 1")
+	})
+
+	t.Run("MerkleRoots", func(t *testing.T) {
+		app, cm := setupRegtestApp(t)
+		root := chainhash.Hash{0xab}
+
+		status := regtestPost(t, app, "/admin/regtest/mine?count=2", testAdminToken, `{"merkleRoots":["`+root.String()+`"]}`)
+		require.Equal(t, http.StatusOK, status)
+
+		valid, err := cm.IsValidRootForHeight(t.Context(), &root, 1)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("InvalidCount", func(t *testing.T) {
+		app, _ := setupRegtestApp(t)
+
+		status, body := adminPost(t, app, "/admin/regtest/mine?count=0")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "ERR_INVALID_PARAMS", body.Code)
+		assert.Equal(t, http.StatusBadRequest, regtestPost(t, app, "/admin/regtest/mine", testAdminToken, `{"merkleRoots":["zz"]}`))
+	})
+
+	t.Run("OnlyOnRegtest", func(t *testing.T) {
+		app, _ := setupAdminApp(t)
+
+		assert.Equal(t, http.StatusNotFound, regtestPost(t, app, "/admin/regtest/mine", testAdminToken, ""))
+	})
+
+	t.Run("RequiresAdmin", func(t *testing.T) {
+		app, _ := setupRegtestApp(t)
+
+		assert.Equal(t, http.StatusUnauthorized, regtestPost(t, app, "/admin/regtest/mine", "", ""))
+	})
+}
+
+func TestRegtestReorg(t *testing.T) {
+	app, cm := setupRegtestApp(t)
+	status, _ := adminPost(t, app, "/admin/regtest/mine?count=5")
+	require.Equal(t, http.StatusOK, status)
+	replaced, err := cm.GetHeaderByHeight(t.Context(), 5)
+	require.NoError(t, err)
+
+	status, body := adminPost(t, app, "/admin/regtest/reorg?depth=2")
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Len(t, body.Value, 3, "count defaults to depth+1")
+	assert.Equal(t, uint32(6), cm.GetHeight(t.Context()))
+	current, err := cm.GetHeaderByHeight(t.Context(), 5)
+	require.NoError(t, err)
+	assert.NotEqual(t, replaced.Hash, current.Hash)
+
+	status, _ = adminPost(t, app, "/admin/regtest/reorg?depth=10")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
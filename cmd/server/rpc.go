@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// JSON-RPC error codes, matching bitcoind where one exists
+const (
+	rpcErrParse          = -32700
+	rpcErrInvalidRequest = -32600
+	rpcErrMethodNotFound = -32601
+	rpcErrInvalidParams  = -32602
+	rpcErrInvalidParam   = -8 // RPC_INVALID_PARAMETER
+	rpcErrNotFound       = -5 // RPC_INVALID_ADDRESS_OR_KEY
+)
+
+// rpcRequest is a single JSON-RPC 1.0 or 2.0 request
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// rpcResponse mirrors bitcoind: result and error are always present, one of them null
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc,omitempty"`
+	Result  interface{}     `json:"result"`
+	Error   *rpcError       `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcError is a JSON-RPC error object
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcBlockHeader is the verbose getblockheader result
+type rpcBlockHeader struct {
+	Hash              string  `json:"hash"`
+	Confirmations     int64   `json:"confirmations"` // -1 when not on the main chain, as bitcoind reports
+	Height            uint32  `json:"height"`
+	Version           int32   `json:"version"`
+	VersionHex        string  `json:"versionHex"`
+	MerkleRoot        string  `json:"merkleroot"`
+	Time              uint32  `json:"time"`
+	Nonce             uint32  `json:"nonce"`
+	Bits              string  `json:"bits"`
+	Difficulty        float64 `json:"difficulty"`
+	ChainWork         string  `json:"chainwork,omitempty"`
+	PreviousBlockHash string  `json:"previousblockhash,omitempty"`
+	NextBlockHash     string  `json:"nextblockhash,omitempty"`
+}
+
+// HandleRPC serves a bitcoind-compatible JSON-RPC subset for header data
+// Supported methods: getbestblockhash, getblockcount, getblockhash, getblockheader, getchaintips.
+// Batch requests (a JSON array) are answered with an array in the same order.
+func (s *Server) HandleRPC(c *fiber.Ctx) error {
+	body := bytes.TrimSpace(c.Body())
+
+	if len(body) > 0 && body[0] == '[' {
+		var requests []rpcRequest
+		if err := json.Unmarshal(body, &requests); err != nil {
+			return c.JSON(rpcFailure(nil, "", rpcErrParse, "Parse error"))
+		}
+		responses := make([]rpcResponse, len(requests))
+		for i := range requests {
+			responses[i] = s.dispatchRPC(c, &requests[i])
+		}
+		return c.JSON(responses)
+	}
+
+	var request rpcRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return c.JSON(rpcFailure(nil, "", rpcErrParse, "Parse error"))
+	}
+	return c.JSON(s.dispatchRPC(c, &request))
+}
+
+// dispatchRPC executes a single request
+func (s *Server) dispatchRPC(c *fiber.Ctx, req *rpcRequest) rpcResponse {
+	if req.Method == "" {
+		return rpcFailure(req.ID, req.JSONRPC, rpcErrInvalidRequest, "Invalid request")
+	}
+
+	var result interface{}
+	var rpcErr *rpcError
+
+	switch req.Method {
+	case "getbestblockhash":
+		tip := s.cm.GetTip(c.UserContext())
+		if tip == nil {
+			return rpcFailure(req.ID, req.JSONRPC, rpcErrNotFound, "No chain tip")
+		}
+		result = tip.Hash.String()
+	case "getblockcount":
+		result = s.cm.GetHeight(c.UserContext())
+	case "getblockhash":
+		result, rpcErr = s.rpcGetBlockHash(c, req.Params)
+	case "getblockheader":
+		result, rpcErr = s.rpcGetBlockHeader(c, req.Params)
+	case "getchaintips":
+		result = s.cm.GetChainTips(c.UserContext())
+	default:
+		return rpcFailure(req.ID, req.JSONRPC, rpcErrMethodNotFound, "Method not found")
+	}
+
+	if rpcErr != nil {
+		return rpcResponse{JSONRPC: req.JSONRPC, Error: rpcErr, ID: rpcID(req.ID)}
+	}
+	return rpcResponse{JSONRPC: req.JSONRPC, Result: result, ID: rpcID(req.ID)}
+}
+
+// rpcGetBlockHash returns the main chain hash at a height
+func (s *Server) rpcGetBlockHash(c *fiber.Ctx, params []json.RawMessage) (interface{}, *rpcError) {
+	var height int64
+	if len(params) < 1 || json.Unmarshal(params[0], &height) != nil {
+		return nil, &rpcError{Code: rpcErrInvalidParams, Message: "Expected height parameter"}
+	}
+	if height < 0 || height > int64(s.cm.GetHeight(c.UserContext())) {
+		return nil, &rpcError{Code: rpcErrInvalidParam, Message: "Block height out of range"}
+	}
+
+	header, err := s.cm.GetHeaderByHeight(c.UserContext(), uint32(height))
+	if err != nil {
+		return nil, &rpcError{Code: rpcErrInvalidParam, Message: "Block height out of range"}
+	}
+	return header.Hash.String(), nil
+}
+
+// rpcGetBlockHeader returns a header as a verbose object or, with verbose=false, as hex
+func (s *Server) rpcGetBlockHeader(c *fiber.Ctx, params []json.RawMessage) (interface{}, *rpcError) {
+	var hashStr string
+	if len(params) < 1 || json.Unmarshal(params[0], &hashStr) != nil {
+		return nil, &rpcError{Code: rpcErrInvalidParams, Message: "Expected blockhash parameter"}
+	}
+	verbose := true
+	if len(params) > 1 && json.Unmarshal(params[1], &verbose) != nil {
+		return nil, &rpcError{Code: rpcErrInvalidParams, Message: "verbose must be a boolean"}
+	}
+
+	hash, err := chainhash.NewHashFromHex(hashStr)
+	if err != nil {
+		return nil, &rpcError{Code: rpcErrInvalidParam, Message: "blockhash must be a 64 character hex string"}
+	}
+
+	ctx := c.UserContext()
+	header, err := s.cm.GetHeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, &rpcError{Code: rpcErrNotFound, Message: "Block not found"}
+	}
+
+	if !verbose {
+		return hex.EncodeToString(header.Bytes()), nil
+	}
+
+	result := rpcBlockHeader{
+		Hash:          header.Hash.String(),
+		Confirmations: -1,
+		Height:        header.Height,
+		Version:       header.Version,
+		VersionHex:    fmt.Sprintf("%08x", uint32(header.Version)), //nolint:gosec // Bit pattern reinterpretation
+		MerkleRoot:    header.MerkleRoot.String(),
+		Time:          header.Timestamp,
+		Nonce:         header.Nonce,
+		Bits:          fmt.Sprintf("%08x", header.Bits),
+		Difficulty:    difficultyFromBits(header.Bits),
+	}
+	if header.ChainWork != nil {
+		result.ChainWork = chaintracks.ChainWorkToHex(header.ChainWork)
+	}
+	if header.Height > 0 {
+		result.PreviousBlockHash = header.PrevHash.String()
+	}
+
+	if mainHeader, err := s.cm.GetHeaderByHeight(ctx, header.Height); err == nil && mainHeader.Hash == header.Hash {
+		result.Confirmations = int64(s.cm.GetHeight(ctx)) - int64(header.Height) + 1
+		if next, err := s.cm.GetHeaderByHeight(ctx, header.Height+1); err == nil {
+			result.NextBlockHash = next.Hash.String()
+		}
+	}
+
+	return result, nil
+}
+
+// difficultyFromBits converts a compact target into bitcoind's difficulty relative to the genesis target
+func difficultyFromBits(bits uint32) float64 {
+	target := chaintracks.CompactToBig(bits)
+	if target.Sign() <= 0 {
+		return 0
+	}
+	difficulty, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(chaintracks.CompactToBig(0x1d00ffff)),
+		new(big.Float).SetInt(target),
+	).Float64()
+	return difficulty
+}
+
+// rpcFailure builds an error response
+func rpcFailure(id json.RawMessage, version string, code int, message string) rpcResponse {
+	return rpcResponse{JSONRPC: version, Error: &rpcError{Code: code, Message: message}, ID: rpcID(id)}
+}
+
+// rpcID returns the request ID, or JSON null when absent
+func rpcID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
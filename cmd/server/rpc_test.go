@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRPCTestApp serves /rpc over a synthetic 10-header chain
+func newRPCTestApp(t *testing.T) (*fiber.App, *Server) {
+	t.Helper()
+	s := &Server{cm: newSyntheticChainManager(t, 10)}
+	app := fiber.New()
+	app.Post("/rpc", s.HandleRPC)
+	return app, s
+}
+
+// rpcPost posts a raw JSON-RPC body and returns the decoded response body
+func rpcPost(t *testing.T, app *fiber.App, body string) []byte {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/rpc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, 200, resp.StatusCode)
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return data
+}
+
+func TestHandleRPC(t *testing.T) {
+	app, s := newRPCTestApp(t)
+	tip := s.cm.GetTip(t.Context())
+	genesis, err := s.cm.GetHeaderByHeight(t.Context(), 0)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedResult string
+		expectedCode   int
+	}{
+		{name: "GetBestBlockHash", body: `{"id":1,"method":"getbestblockhash"}`, expectedResult: `"` + tip.Hash.String() + `"`},
+		{name: "GetBlockCount", body: `{"id":1,"method":"getblockcount"}`, expectedResult: `9`},
+		{name: "GetBlockHash", body: `{"id":1,"method":"getblockhash","params":[0]}`, expectedResult: `"` + genesis.Hash.String() + `"`},
+		{name: "GetBlockHashOutOfRange", body: `{"id":1,"method":"getblockhash","params":[10]}`, expectedCode: rpcErrInvalidParam},
+		{name: "GetBlockHashMissingParams", body: `{"id":1,"method":"getblockhash"}`, expectedCode: rpcErrInvalidParams},
+		{
+			name:           "GetBlockHeaderHex",
+			body:           `{"id":1,"method":"getblockheader","params":["` + tip.Hash.String() + `",false]}`,
+			expectedResult: `"` + hex.EncodeToString(tip.Bytes()) + `"`,
+		},
+		{name: "GetBlockHeaderUnknownHash", body: `{"id":1,"method":"getblockheader","params":["` + strings.Repeat("ab", 32) + `"]}`, expectedCode: rpcErrNotFound},
+		{name: "GetBlockHeaderInvalidHash", body: `{"id":1,"method":"getblockheader","params":["xyz"]}`, expectedCode: rpcErrInvalidParam},
+		{name: "UnknownMethod", body: `{"id":1,"method":"getrawmempool"}`, expectedCode: rpcErrMethodNotFound},
+		{name: "MissingMethod", body: `{"id":1}`, expectedCode: rpcErrInvalidRequest},
+		{name: "MalformedJSON", body: `{"id":`, expectedCode: rpcErrParse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response struct {
+				Result json.RawMessage `json:"result"`
+				Error  *rpcError       `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rpcPost(t, app, tt.body), &response))
+
+			if tt.expectedCode != 0 {
+				require.NotNil(t, response.Error)
+				assert.Equal(t, tt.expectedCode, response.Error.Code)
+				return
+			}
+			require.Nil(t, response.Error)
+			assert.JSONEq(t, tt.expectedResult, string(response.Result))
+		})
+	}
+}
+
+func TestHandleRPCGetBlockHeaderVerbose(t *testing.T) {
+	app, s := newRPCTestApp(t)
+	header, err := s.cm.GetHeaderByHeight(t.Context(), 8)
+	require.NoError(t, err)
+
+	var response struct {
+		Result rpcBlockHeader `json:"result"`
+		Error  *rpcError      `json:"error"`
+	}
+	body := `{"jsonrpc":"2.0","id":"a","method":"getblockheader","params":["` + header.Hash.String() + `"]}`
+	require.NoError(t, json.Unmarshal(rpcPost(t, app, body), &response))
+	require.Nil(t, response.Error)
+
+	assert.Equal(t, header.Hash.String(), response.Result.Hash)
+	assert.Equal(t, uint32(8), response.Result.Height)
+	assert.Equal(t, int64(2), response.Result.Confirmations)
+	assert.NotEmpty(t, response.Result.NextBlockHash)
+	assert.Equal(t, header.PrevHash.String(), response.Result.PreviousBlockHash)
+}
+
+func TestHandleRPCBatchAndIDs(t *testing.T) {
+	app, _ := newRPCTestApp(t)
+
+	var responses []struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Result  json.RawMessage `json:"result"`
+		ID      json.RawMessage `json:"id"`
+	}
+	body := `[{"jsonrpc":"2.0","id":7,"method":"getblockcount"},{"id":"x","method":"getchaintips"},{"method":"getblockcount"}]`
+	require.NoError(t, json.Unmarshal(rpcPost(t, app, body), &responses))
+	require.Len(t, responses, 3)
+
+	assert.Equal(t, "2.0", responses[0].JSONRPC)
+	assert.JSONEq(t, `7`, string(responses[0].ID))
+	assert.JSONEq(t, `9`, string(responses[0].Result))
+
+	assert.Empty(t, responses[1].JSONRPC)
+	assert.JSONEq(t, `"x"`, string(responses[1].ID))
+	assert.Contains(t, string(responses[1].Result), `"status":"active"`)
+
+	assert.JSONEq(t, `null`, string(responses[2].ID))
+}
+
+func TestDifficultyFromBits(t *testing.T) {
+	tests := []struct {
+		name     string
+		bits     uint32
+		expected float64
+	}{
+		{name: "GenesisTargetIsOne", bits: 0x1d00ffff, expected: 1},
+		{name: "HalfTargetIsTwo", bits: 0x1c7fff80, expected: 2},
+		{name: "ZeroTargetIsZero", bits: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, difficultyFromBits(tt.bits), 0.0001)
+		})
+	}
+}
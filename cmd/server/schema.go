@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// errNoSchema is returned when the spec documents no JSON schema for a response
+	errNoSchema = errors.New("no schema in spec")
+
+	// errSchemaMismatch is returned when a value does not conform to its schema
+	errSchemaMismatch = errors.New("schema mismatch")
+)
+
+// specValidator checks JSON values against the schemas of an OpenAPI document
+// It covers the subset of JSON Schema used by openapi.yaml: $ref, allOf, oneOf, type, nullable,
+// required, properties, additionalProperties, items, maxItems and enum
+type specValidator struct {
+	doc map[string]any
+}
+
+// newSpecValidator parses an OpenAPI document
+func newSpecValidator(spec string) (*specValidator, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	return &specValidator{doc: doc}, nil
+}
+
+// responseSchema returns the application/json schema documented for a path, method and status
+func (v *specValidator) responseSchema(path, method string, status int) (map[string]any, error) {
+	schema, ok := dig(v.doc, "paths", path, strings.ToLower(method), "responses", strconv.Itoa(status), "content", "application/json", "schema")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s %d", errNoSchema, method, path, status)
+	}
+	return asMap(schema), nil
+}
+
+// componentSchema returns a schema from components/schemas
+func (v *specValidator) componentSchema(name string) (map[string]any, error) {
+	schema, ok := dig(v.doc, "components", "schemas", name)
+	if !ok {
+		return nil, fmt.Errorf("%w: component %s", errNoSchema, name)
+	}
+	return asMap(schema), nil
+}
+
+// validate checks a decoded JSON value against schema; at names the location for error messages
+func (v *specValidator) validate(schema map[string]any, value any, at string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, ok := dig(v.doc, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...)
+		if !ok {
+			return fmt.Errorf("%w: unresolved $ref %s", errNoSchema, ref)
+		}
+		return v.validate(asMap(resolved), value, at)
+	}
+
+	if value == nil && (schema["nullable"] == true || schema["type"] == "null") {
+		return nil
+	}
+
+	for _, sub := range asSlice(schema["allOf"]) {
+		if err := v.validate(asMap(sub), value, at); err != nil {
+			return err
+		}
+	}
+
+	if oneOf := asSlice(schema["oneOf"]); len(oneOf) > 0 {
+		matches := 0
+		for _, sub := range oneOf {
+			if v.validate(asMap(sub), value, at) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%w: %s matches %d oneOf alternatives", errSchemaMismatch, at, matches)
+		}
+	}
+
+	if value == nil {
+		if schema["type"] == nil {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is null", errSchemaMismatch, at)
+	}
+
+	if typ, ok := schema["type"].(string); ok {
+		if err := checkType(typ, value, at); err != nil {
+			return err
+		}
+	}
+
+	if enum := asSlice(schema["enum"]); len(enum) > 0 && !slices.Contains(enum, value) {
+		return fmt.Errorf("%w: %s value %v not in enum", errSchemaMismatch, at, value)
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		return v.validateObject(schema, val, at)
+	case []any:
+		if maxItems, ok := schema["maxItems"].(int); ok && len(val) > maxItems {
+			return fmt.Errorf("%w: %s has %d items, max %d", errSchemaMismatch, at, len(val), maxItems)
+		}
+		if items, ok := schema["items"]; ok {
+			for i, item := range val {
+				if err := v.validate(asMap(items), item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateObject checks required and declared properties of an object
+func (v *specValidator) validateObject(schema, obj map[string]any, at string) error {
+	for _, name := range asSlice(schema["required"]) {
+		if _, ok := obj[fmt.Sprint(name)]; !ok {
+			return fmt.Errorf("%w: %s missing required property %v", errSchemaMismatch, at, name)
+		}
+	}
+
+	properties := asMap(schema["properties"])
+	for name, propValue := range obj {
+		propSchema, ok := properties[name]
+		if !ok {
+			propSchema, ok = schema["additionalProperties"].(map[string]any)
+		}
+		if !ok {
+			continue
+		}
+		if err := v.validate(asMap(propSchema), propValue, at+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkType matches a JSON value against an OpenAPI primitive type
+func checkType(typ string, value any, at string) error {
+	ok := true
+	switch typ {
+	case "object":
+		_, ok = value.(map[string]any)
+	case "array":
+		_, ok = value.([]any)
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "number":
+		_, ok = value.(float64)
+	case "integer":
+		var n float64
+		n, ok = value.(float64)
+		ok = ok && n == math.Trunc(n)
+	case "null":
+		ok = false
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s is not %s", errSchemaMismatch, at, typ)
+	}
+	return nil
+}
+
+// dig walks nested maps by key
+func dig(node any, keys ...string) (any, bool) {
+	for _, key := range keys {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func asMap(node any) map[string]any {
+	m, _ := node.(map[string]any)
+	return m
+}
+
+func asSlice(node any) []any {
+	s, _ := node.([]any)
+	return s
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecValidatorValidate(t *testing.T) {
+	v, err := newSpecValidator(openapiSpec)
+	require.NoError(t, err)
+
+	header := `{"version":1,"previousHash":"00","merkleRoot":"00","time":0,"bits":0,"nonce":0,"height":5,"hash":"00"}`
+
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		expectErr error
+	}{
+		{name: "String", path: "/v2/network", body: `{"status":"success","value":"main"}`},
+		{name: "WrongType", path: "/v2/network", body: `{"status":"success","value":1}`, expectErr: errSchemaMismatch},
+		{name: "EnumMismatch", path: "/v2/network", body: `{"status":"ok","value":"main"}`, expectErr: errSchemaMismatch},
+		{name: "MissingRequired", path: "/v2/network", body: `{"value":"main"}`, expectErr: errSchemaMismatch},
+		{name: "OneOfHeader", path: "/v2/header/hash/{hash}", body: `{"status":"success","value":` + header + `}`},
+		{name: "OneOfNull", path: "/v2/header/hash/{hash}", body: `{"status":"success","value":null}`},
+		{name: "HeaderMissingHash", path: "/v2/header/hash/{hash}", body: `{"status":"success","value":{"height":5}}`, expectErr: errSchemaMismatch},
+		{name: "ArrayItems", path: "/v2/headers/byHashes", body: `{"status":"success","value":[` + header + `,null]}`},
+		{name: "FractionalInteger", path: "/v2/height", body: `{"status":"success","value":1.5}`, expectErr: errSchemaMismatch},
+		{name: "UndocumentedPath", path: "/v2/nope", body: `{}`, expectErr: errNoSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := v.responseSchema(tt.path, "GET", 200)
+			if err != nil {
+				schema, err = v.responseSchema(tt.path, "POST", 200)
+			}
+			if err == nil {
+				var value any
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &value))
+				err = v.validate(schema, value, "body")
+			}
+
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errSelfTest is returned when an endpoint misbehaves during the self-test
+var errSelfTest = errors.New("self-test check failed")
+
+// selfTest exercises a running server's public endpoints and records one result per check
+type selfTest struct {
+	baseURL    string
+	client     *http.Client
+	spec       *specValidator
+	sseTimeout time.Duration
+	out        io.Writer
+	passed     int
+	failed     int
+}
+
+// selfTestTip is the part of the tip header the dependent checks need
+type selfTestTip struct {
+	Hash       string `json:"hash"`
+	Height     uint32 `json:"height"`
+	MerkleRoot string `json:"merkleRoot"`
+}
+
+// runSelfTest implements `server selftest`; it returns the process exit code
+func runSelfTest(ctx context.Context, args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	flags.SetOutput(out)
+	baseURL := flags.String("url", "http://localhost:3011", "Base URL of the chaintracks server to test")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout for each request")
+	sseTimeout := flags.Duration("sse-timeout", 10*time.Second, "How long to wait for the first SSE tip event")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, err := newSpecValidator(openapiSpec)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "FAIL  %v\n", err)
+		return 1
+	}
+
+	st := &selfTest{
+		baseURL:    strings.TrimRight(*baseURL, "/"),
+		client:     &http.Client{Timeout: *timeout},
+		spec:       spec,
+		sseTimeout: *sseTimeout,
+		out:        out,
+	}
+	_, _ = fmt.Fprintf(out, "Self-testing %s\n", st.baseURL)
+	st.run(ctx)
+
+	_, _ = fmt.Fprintf(out, "\n%d passed, %d failed\n", st.passed, st.failed)
+	if st.failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// run executes every check; checks that need the tip are reported as failed when the tip is unavailable
+func (st *selfTest) run(ctx context.Context) {
+	st.check(ctx, "GET", "/v2/network", "/v2/network", nil, nil)
+	st.check(ctx, "GET", "/v2/height", "/v2/height", nil, nil)
+	st.check(ctx, "GET", "/v2/tip/hash", "/v2/tip/hash", nil, nil)
+
+	var tipResp struct {
+		Value *selfTestTip `json:"value"`
+	}
+	st.check(ctx, "GET", "/v2/tip/header", "/v2/tip/header", nil, &tipResp)
+	tip := tipResp.Value
+	if tip == nil || tip.Hash == "" {
+		st.fail("dependent checks", fmt.Errorf("%w: no chain tip to test against", errSelfTest))
+		return
+	}
+
+	height := strconv.FormatUint(uint64(tip.Height), 10)
+	from := strconv.FormatUint(uint64(tip.Height-min(tip.Height, 1)), 10)
+	st.check(ctx, "GET", "/v2/header/height/{height}", "/v2/header/height/"+height, nil, nil)
+	st.check(ctx, "GET", "/v2/header/hash/{hash}", "/v2/header/hash/"+tip.Hash, nil, nil)
+	st.check(ctx, "GET", "/v2/headers", "/v2/headers?height="+from+"&count=2", nil, nil)
+	st.check(ctx, "GET", "/v2/headers/backwards/{hash}", "/v2/headers/backwards/"+tip.Hash+"?count=2", nil, nil)
+	st.check(ctx, "GET", "/v2/headers/range", "/v2/headers/range?from="+tip.Hash+"&to="+tip.Hash, nil, nil)
+	st.check(ctx, "GET", "/v2/anchor/{blockHash}", "/v2/anchor/"+tip.Hash, nil, nil)
+	st.check(ctx, "GET", "/v2/confirmations/{hash}", "/v2/confirmations/"+tip.Hash, nil, nil)
+	st.check(ctx, "GET", "/v2/mainchain/{hash}", "/v2/mainchain/"+tip.Hash, nil, nil)
+	st.check(ctx, "GET", "/v2/chainwork/{height}", "/v2/chainwork/"+height, nil, nil)
+	st.check(ctx, "POST", "/v2/headers/byHashes", "/v2/headers/byHashes", []string{tip.Hash}, nil)
+
+	roots := []map[string]any{{"merkleRoot": tip.MerkleRoot, "blockHeight": tip.Height}}
+	var verifyResp struct {
+		Value struct {
+			State string `json:"state"`
+		} `json:"value"`
+	}
+	if st.check(ctx, "POST", "/v2/merkleroots/verify", "/v2/merkleroots/verify", roots, &verifyResp) && verifyResp.Value.State != "CONFIRMED" {
+		st.fail("tip merkle root confirmed", fmt.Errorf("%w: state %q", errSelfTest, verifyResp.Value.State))
+	}
+
+	rpc := map[string]any{"jsonrpc": "2.0", "id": 1, "method": "getblockcount"}
+	st.check(ctx, "POST", "/rpc", "/rpc", rpc, nil)
+
+	st.check(ctx, "GET", "/api/v1/chain/tip/longest", "/api/v1/chain/tip/longest", nil, nil)
+	st.check(ctx, "GET", "/api/v1/chain/header/{hash}", "/api/v1/chain/header/"+tip.Hash, nil, nil)
+	st.check(ctx, "POST", "/api/v1/chain/merkleroot/verify", "/api/v1/chain/merkleroot/verify", roots, nil)
+
+	st.check(ctx, "GET", "/v2/reorgs", "/v2/reorgs", nil, nil)
+	st.check(ctx, "GET", "/v2/debug/latency", "/v2/debug/latency", nil, nil)
+	st.check(ctx, "GET", "/v2/metrics", "/v2/metrics", nil, nil)
+	st.check(ctx, "GET", "/v2/status", "/v2/status", nil, nil)
+	st.check(ctx, "GET", "", "/openapi.yaml", nil, nil)
+
+	st.checkSSE(ctx, tip)
+}
+
+// check requests path and validates a 200 JSON response against the spec entry for specPath
+// An empty specPath only checks the status. If into is set the body is also decoded into it.
+func (st *selfTest) check(ctx context.Context, method, specPath, path string, body, into any) bool {
+	name := method + " " + path
+	start := time.Now()
+
+	respBody, err := st.do(ctx, method, path, body)
+	if err == nil && specPath != "" {
+		err = st.conforms(method, specPath, respBody)
+	}
+	if err == nil && into != nil {
+		err = json.Unmarshal(respBody, into)
+	}
+	if err != nil {
+		st.fail(name, err)
+		return false
+	}
+	st.pass(name, time.Since(start))
+	return true
+}
+
+// do performs a request and returns the body of a 200 response
+func (st *selfTest) do(ctx context.Context, method, path string, body any) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, st.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := st.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", errSelfTest, resp.StatusCode)
+	}
+	return respBody, nil
+}
+
+// conforms validates a JSON body against the documented 200 response schema
+func (st *selfTest) conforms(method, specPath string, body []byte) error {
+	schema, err := st.spec.responseSchema(specPath, method, http.StatusOK)
+	if err != nil {
+		return err
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return st.spec.validate(schema, value, "body")
+}
+
+// checkSSE connects to the tip stream and waits for the initial tip event, which must match the tip
+func (st *selfTest) checkSSE(ctx context.Context, tip *selfTestTip) {
+	name := "GET /v2/tip/stream"
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, st.sseTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", st.baseURL+"/v2/tip/stream", nil)
+	if err != nil {
+		st.fail(name, fmt.Errorf("failed to create request: %w", err))
+		return
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream never ends, so the request timeout must not apply
+	resp, err := (&http.Client{Transport: st.client.Transport}).Do(req)
+	if err != nil {
+		st.fail(name, fmt.Errorf("request failed: %w", err))
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		st.fail(name, fmt.Errorf("%w: status %d", errSelfTest, resp.StatusCode))
+		return
+	}
+
+	event := ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "tip":
+			if err := st.checkTipEvent([]byte(strings.TrimPrefix(line, "data: ")), tip); err != nil {
+				st.fail(name, err)
+				return
+			}
+			st.pass(name, time.Since(start))
+			return
+		}
+	}
+	st.fail(name, fmt.Errorf("%w: no tip event within %s", errSelfTest, st.sseTimeout))
+}
+
+// checkTipEvent validates an SSE tip payload as a BlockHeader at or above the tip seen earlier
+func (st *selfTest) checkTipEvent(data []byte, tip *selfTestTip) error {
+	schema, err := st.spec.componentSchema("BlockHeader")
+	if err != nil {
+		return err
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to decode tip event: %w", err)
+	}
+	if err := st.spec.validate(schema, value, "tip"); err != nil {
+		return err
+	}
+
+	var event selfTestTip
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode tip event: %w", err)
+	}
+	if event.Height < tip.Height {
+		return fmt.Errorf("%w: streamed tip height %d below REST tip height %d", errSelfTest, event.Height, tip.Height)
+	}
+	return nil
+}
+
+func (st *selfTest) pass(name string, elapsed time.Duration) {
+	st.passed++
+	_, _ = fmt.Fprintf(st.out, "PASS  %s (%s)\n", name, elapsed.Round(time.Millisecond))
+}
+
+func (st *selfTest) fail(name string, err error) {
+	st.failed++
+	_, _ = fmt.Fprintf(st.out, "FAIL  %s: %v\n", name, err)
+}
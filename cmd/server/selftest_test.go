@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// listenTestApp serves app on a random local port and returns its base URL
+func listenTestApp(t *testing.T, app *fiber.App) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = app.Listener(ln)
+	}()
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+	return "http://" + ln.Addr().String()
+}
+
+func TestRunSelfTestPassesAgainstServer(t *testing.T) {
+	cm, err := chaintracks.NewChainManager(t.Context(), "test", t.TempDir(), nil)
+	require.NoError(t, err)
+
+	var prev chainhash.Hash
+	for i := range 3 {
+		header := &block.Header{PrevHash: prev, Nonce: uint32(i), Bits: 0x207fffff} //nolint:gosec // Test data
+		prev = header.Hash()
+		require.NoError(t, cm.SetChainTip(t.Context(), []*chaintracks.BlockHeader{{
+			Header:    header,
+			Height:    uint32(i), //nolint:gosec // Test data
+			Hash:      prev,
+			ChainWork: big.NewInt(int64(i + 1)),
+		}}))
+	}
+
+	app := fiber.New()
+	server := NewServer(t.Context(), cm)
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	url := listenTestApp(t, app)
+
+	var out bytes.Buffer
+	code := runSelfTest(t.Context(), []string{"--url", url}, &out)
+	assert.Equal(t, 0, code, out.String())
+	assert.NotContains(t, out.String(), "FAIL")
+	assert.Contains(t, out.String(), "PASS  GET /v2/tip/stream")
+}
+
+func TestRunSelfTestReportsFailures(t *testing.T) {
+	app := fiber.New()
+	app.Get("/v2/network", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "success", "value": 42})
+	})
+	url := listenTestApp(t, app)
+
+	var out bytes.Buffer
+	code := runSelfTest(t.Context(), []string{"--url", url, "--sse-timeout", "100ms"}, &out)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out.String(), "FAIL  GET /v2/network: schema mismatch: body.value is not string")
+	assert.Contains(t, out.String(), "FAIL  GET /v2/height: self-test check failed: status 404")
+	assert.Contains(t, out.String(), "no chain tip to test against")
+}
+
+func TestRunSelfTestRejectsUnknownFlag(t *testing.T) {
+	var out bytes.Buffer
+	assert.Equal(t, 2, runSelfTest(t.Context(), []string{"--bogus"}, &out))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// eventSink publishes tip and reorg events to an external system
+type eventSink interface {
+	// Name identifies the sink in logs
+	Name() string
+
+	// Publish delivers one event; payload is the event encoded as JSON, as sent on the SSE reorg stream
+	Publish(ctx context.Context, event *chaintracks.ChainEvent, payload []byte) error
+
+	Close() error
+}
+
+// publishEvents publishes every sequenced chain event to each sink, in order, until ctx is cancelled
+// Events dropped because a sink was slow are replayed from the ChainManager history when still held.
+// The subscription is in place when it returns; sinks are closed once ctx is cancelled.
+func publishEvents(ctx context.Context, cm *chaintracks.ChainManager, sinks []eventSink) {
+	events := cm.SubscribeEvents(ctx)
+	lastSeq := cm.LastEventSeq()
+
+	go func() {
+		defer closeSinks(sinks)
+
+		for event := range events {
+			if event == nil || event.Seq <= lastSeq {
+				// Sync status and progress events are not sequenced and not published
+				continue
+			}
+
+			batch := []*chaintracks.ChainEvent{event}
+			if event.Seq > lastSeq+1 {
+				if missed, ok := cm.EventsSince(lastSeq); ok {
+					batch = missed
+				} else {
+					slog.Warn("Event sinks missed events", "from", lastSeq+1, "to", event.Seq-1)
+				}
+			}
+
+			for _, e := range batch {
+				publishToSinks(ctx, sinks, e)
+			}
+			lastSeq = batch[len(batch)-1].Seq
+		}
+	}()
+}
+
+// closeSinks closes every sink, logging failures
+func closeSinks(sinks []eventSink) {
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil {
+			slog.Error("Error closing event sink", "sink", sink.Name(), "error", err)
+		}
+	}
+}
+
+// publishToSinks encodes an event once and hands it to every sink, logging failures
+func publishToSinks(ctx context.Context, sinks []eventSink, event *chaintracks.ChainEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to encode event", "seq", event.Seq, "error", err)
+		return
+	}
+	for _, sink := range sinks {
+		if err := sink.Publish(ctx, event, payload); err != nil {
+			slog.Warn("Failed to publish event", "sink", sink.Name(), "seq", event.Seq, "type", event.Type, "error", err)
+		}
+	}
+}
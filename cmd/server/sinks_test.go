@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// recordingSink records published events; Publish signals entered, then blocks while gate is held
+type recordingSink struct {
+	mu       sync.Mutex
+	gate     sync.Mutex
+	seqs     []uint64
+	payloads [][]byte
+	entered  chan struct{}
+	closed   chan struct{}
+}
+
+func newRecordingSink() *recordingSink {
+	return &recordingSink{entered: make(chan struct{}, 100), closed: make(chan struct{})}
+}
+
+func (r *recordingSink) Name() string { return "recording" }
+
+func (r *recordingSink) Publish(_ context.Context, event *chaintracks.ChainEvent, payload []byte) error {
+	select {
+	case r.entered <- struct{}{}:
+	default:
+	}
+	r.gate.Lock()
+	defer r.gate.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seqs = append(r.seqs, event.Seq)
+	r.payloads = append(r.payloads, payload)
+	return nil
+}
+
+func (r *recordingSink) Close() error {
+	close(r.closed)
+	return nil
+}
+
+func (r *recordingSink) published() []uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]uint64(nil), r.seqs...)
+}
+
+// extendSyntheticChain adds count headers on top of a chain from newSyntheticChainManager
+func extendSyntheticChain(t *testing.T, cm *chaintracks.ChainManager, count int) {
+	t.Helper()
+
+	start := int(cm.GetHeight(t.Context())) + 1
+	for i := start; i < start+count; i++ {
+		require.NoError(t, cm.SetChainTip(t.Context(), []*chaintracks.BlockHeader{{
+			Header: &block.Header{Nonce: uint32(i)}, //nolint:gosec // Test data
+			Height: uint32(i),                       //nolint:gosec // Test data
+			Hash:   chainhash.Hash{byte(i), byte(i >> 8)},
+		}}))
+	}
+}
+
+// seqRange returns from..to inclusive
+func seqRange(from, to uint64) []uint64 {
+	var seqs []uint64
+	for seq := from; seq <= to; seq++ {
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+func TestPublishEvents(t *testing.T) {
+	cm := newSyntheticChainManager(t, 3)
+	sink := newRecordingSink()
+	ctx, cancel := context.WithCancel(t.Context())
+
+	publishEvents(ctx, cm, []eventSink{sink})
+	extendSyntheticChain(t, cm, 2)
+
+	require.Eventually(t, func() bool { return len(sink.published()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{4, 5}, sink.published(), "only events after startup are published")
+
+	sink.mu.Lock()
+	payload := sink.payloads[1]
+	sink.mu.Unlock()
+	var event chaintracks.ChainEvent
+	require.NoError(t, json.Unmarshal(payload, &event))
+	assert.Equal(t, chaintracks.EventTipAdvanced, event.Type)
+	assert.Equal(t, uint64(5), event.Seq)
+	assert.Equal(t, uint32(4), event.Tip.Height)
+
+	cancel()
+	select {
+	case <-sink.closed:
+	case <-time.After(time.Second):
+		t.Fatal("sink not closed after cancel")
+	}
+}
+
+func TestPublishEventsReplaysDroppedEvents(t *testing.T) {
+	cm := newSyntheticChainManager(t, 1)
+	sink := newRecordingSink()
+
+	publishEvents(t.Context(), cm, []eventSink{sink})
+
+	// Hold the sink so the subscription overflows and drops events
+	sink.gate.Lock()
+	extendSyntheticChain(t, cm, 1)
+	<-sink.entered
+	extendSyntheticChain(t, cm, 40)
+	sink.gate.Unlock()
+
+	// The held event and the buffered ones drain; the next event reveals the gap
+	require.Eventually(t, func() bool { return len(sink.published()) == 17 }, time.Second, 10*time.Millisecond)
+	extendSyntheticChain(t, cm, 1)
+
+	require.Eventually(t, func() bool { return len(sink.published()) == 42 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, seqRange(2, 43), sink.published())
+}
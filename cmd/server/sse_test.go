@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+func TestFormatSSE(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    string
+		id       string
+		data     string
+		expected string
+	}{
+		{
+			name:     "IncludesEventAndID",
+			event:    "tip",
+			id:       "100",
+			data:     `{"height":100}`,
+			expected: "event: tip\nid: 100\ndata: {\"height\":100}\n\n",
+		},
+		{
+			name:     "OmitsEmptyID",
+			event:    "sync-progress",
+			data:     `{}`,
+			expected: "event: sync-progress\ndata: {}\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatSSE(tt.event, tt.id, []byte(tt.data)))
+		})
+	}
+}
+
+func TestServerBroadcastEvent(t *testing.T) {
+	tip := &chaintracks.BlockHeader{Header: &block.Header{}, Height: 42, Hash: chainhash.Hash{1}}
+
+	tests := []struct {
+		name           string
+		event          *chaintracks.ChainEvent
+		expectedEvents []string
+	}{
+		{
+			name:           "TipAdvancedSendsTip",
+			event:          &chaintracks.ChainEvent{Seq: 7, Type: chaintracks.EventTipAdvanced, Tip: tip},
+			expectedEvents: []string{"event: tip\nid: 7\n"},
+		},
+		{
+			name: "ReorgSendsReorgThenTip",
+			event: &chaintracks.ChainEvent{
+				Seq:   8,
+				Type:  chaintracks.EventReorg,
+				Tip:   tip,
+				Reorg: &chaintracks.ReorgInfo{ForkHeight: 40},
+			},
+			expectedEvents: []string{"event: reorg\nid: 8\n", "event: tip\ndata: "},
+		},
+		{
+			name: "SyncStatusSendsSyncProgress",
+			event: &chaintracks.ChainEvent{
+				Type: chaintracks.EventSyncStatus,
+				Tip:  tip,
+				Lag:  &chaintracks.LagStatus{State: chaintracks.SyncStateBehind},
+			},
+			expectedEvents: []string{"event: sync-progress\ndata: {\"state\":\"behind\""},
+		},
+		{
+			name: "SyncProgressAddsProgressToLagStatus",
+			event: &chaintracks.ChainEvent{
+				Type:     chaintracks.EventSyncProgress,
+				Tip:      tip,
+				Lag:      &chaintracks.LagStatus{State: chaintracks.SyncStateSynced},
+				Progress: &chaintracks.SyncProgress{Active: true, CurrentHeight: 10, TargetHeight: 100},
+			},
+			expectedEvents: []string{"event: sync-progress\ndata: {\"state\":\"synced\"", `"progress":{"active":true`, `"targetHeight":100`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newSSEClient(false)
+			s := &Server{sseClients: map[int64]*sseClient{1: client}}
+
+			s.broadcastEvent(tt.event)
+
+			require.Len(t, client.queue, 1)
+			output := <-client.queue
+			last := -1
+			for _, expected := range tt.expectedEvents {
+				idx := bytes.Index([]byte(output), []byte(expected))
+				require.GreaterOrEqual(t, idx, 0, "missing %q in %q", expected, output)
+				assert.Greater(t, idx, last, "events out of order")
+				last = idx
+			}
+		})
+	}
+}
+
+func TestServerBroadcastDropsOldest(t *testing.T) {
+	slow, fast := newSSEClient(false), newSSEClient(false)
+	s := &Server{sseClients: map[int64]*sseClient{1: slow, 2: fast}}
+
+	for i := range sseClientQueue + 3 {
+		s.broadcast(strconv.Itoa(i), strconv.Itoa(i))
+		assert.Equal(t, strconv.Itoa(i), <-fast.queue)
+	}
+
+	assert.Len(t, slow.queue, sseClientQueue)
+	assert.Equal(t, "3", <-slow.queue, "the oldest messages are dropped")
+	assert.Equal(t, uint64(3), s.sseDropped.Load())
+}
+
+func TestServerBroadcastCompact(t *testing.T) {
+	tip := &chaintracks.BlockHeader{Header: &block.Header{PrevHash: chainhash.Hash{2}, Nonce: 7}, Height: 42, Hash: chainhash.Hash{1}}
+	full, compact := newSSEClient(false), newSSEClient(true)
+	s := &Server{sseClients: map[int64]*sseClient{1: full, 2: compact}}
+
+	s.broadcastEvent(&chaintracks.ChainEvent{Seq: 7, Type: chaintracks.EventTipAdvanced, Tip: tip})
+
+	assert.Contains(t, <-full.queue, `"nonce":7`)
+
+	message := <-compact.queue
+	require.True(t, strings.HasPrefix(message, "event: tip\nid: 7\ndata: "), message)
+	data := strings.TrimSpace(strings.TrimPrefix(message, "event: tip\nid: 7\ndata: "))
+	expected, err := json.Marshal(map[string]any{"height": 42, "hash": tip.Hash, "previousHash": tip.PrevHash})
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), data)
+}
+
+// newSyntheticChainManager creates a ChainManager holding count synthetic headers
+// Headers are added one at a time so each produces a tip event with seq == height+1
+func newSyntheticChainManager(t *testing.T, count int) *chaintracks.ChainManager {
+	t.Helper()
+
+	cm, err := chaintracks.NewChainManager(t.Context(), "test", t.TempDir(), nil)
+	require.NoError(t, err)
+
+	headers := make([]*chaintracks.BlockHeader, count)
+	for i := range headers {
+		headers[i] = &chaintracks.BlockHeader{
+			Header: &block.Header{Nonce: uint32(i)}, //nolint:gosec // Test data
+			Height: uint32(i),                       //nolint:gosec // Test data
+			Hash:   chainhash.Hash{byte(i), byte(i >> 8)},
+		}
+	}
+	for _, header := range headers {
+		require.NoError(t, cm.SetChainTip(t.Context(), []*chaintracks.BlockHeader{header}))
+	}
+	return cm
+}
+
+func TestServerSendInitialEvents(t *testing.T) {
+	cm := newSyntheticChainManager(t, 10)
+
+	tests := []struct {
+		name          string
+		lastEventID   string
+		expectedFirst string
+		expectedCount int
+	}{
+		{name: "NewClientGetsCurrentTip", lastEventID: "", expectedFirst: "id: 10\n", expectedCount: 1},
+		{name: "ResumingClientGetsMissedEvents", lastEventID: "7", expectedFirst: "id: 8\n", expectedCount: 3},
+		{name: "UpToDateClientGetsCurrentTip", lastEventID: "10", expectedFirst: "id: 10\n", expectedCount: 1},
+		{name: "ClientAheadOfServerGetsCurrentTip", lastEventID: "20", expectedFirst: "id: 10\n", expectedCount: 1},
+		{name: "InvalidIDTreatedAsNewClient", lastEventID: "abc", expectedFirst: "id: 10\n", expectedCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := &Server{cm: cm}
+
+			lastEventID, resume := parseLastEventID(tt.lastEventID)
+			require.NoError(t, s.sendInitialEvents(t.Context(), bufio.NewWriter(&buf), lastEventID, resume, false))
+
+			output := buf.String()
+			assert.Equal(t, tt.expectedCount, strings.Count(output, "event: tip\n"))
+			assert.True(t, strings.HasPrefix(output, "event: tip\n"+tt.expectedFirst), output)
+		})
+	}
+
+	t.Run("ReplayBeyondCapSendsCurrentTip", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := &Server{cm: cm, sseReplay: 2}
+
+		require.NoError(t, s.sendInitialEvents(t.Context(), bufio.NewWriter(&buf), 5, true, false))
+
+		output := buf.String()
+		assert.Equal(t, 1, strings.Count(output, "event: tip\n"))
+		assert.True(t, strings.HasPrefix(output, "event: tip\nid: 10\n"), output)
+	})
+}
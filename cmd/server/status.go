@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// version is the server release, set at build time with -ldflags "-X main.version=v1.2.3"
+var version = "" //nolint:gochecknoglobals // Set via -ldflags
+
+// ServerStatus is the machine-readable server state served on /v2/status and rendered by the dashboard
+type ServerStatus struct {
+	Version       string                    `json:"version"`
+	Network       string                    `json:"network"`
+	Height        uint32                    `json:"height"`
+	TipHash       string                    `json:"tipHash,omitempty"`
+	TipChainWork  string                    `json:"tipChainWork,omitempty"` // 64-character hex, as in BlockHeader.chainWork
+	TipTime       *time.Time                `json:"tipTime,omitempty"`      // Timestamp from the tip header
+	TipAgeSeconds int64                     `json:"tipAgeSeconds"`          // Seconds since TipTime, 0 without a tip
+	Sync          chaintracks.LagStatus     `json:"sync"`
+	SyncProgress  *chaintracks.SyncProgress `json:"syncProgress,omitempty"` // Current or most recent bulk sync, omitted if none ran
+	PeerCount     int                       `json:"peerCount"`
+	Peers         []chaintracks.PeerInfo    `json:"peers"`
+	SSEClients    int                       `json:"sseClients"`
+	StoragePath   string                    `json:"storagePath"`
+	StartedAt     time.Time                 `json:"startedAt"`
+	UptimeSeconds int64                     `json:"uptimeSeconds"`
+}
+
+// serverVersion returns the -ldflags version, else the module version from the build info, else "dev"
+func serverVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// buildStatus snapshots the chain, peer and process state
+func (s *Server) buildStatus(ctx context.Context) ServerStatus {
+	network, err := s.cm.GetNetwork(ctx)
+	if err != nil {
+		network = "unknown"
+	}
+
+	peers := s.cm.GetPeers()
+	metrics := s.cm.GetMetrics()
+
+	s.sseClientsMu.RLock()
+	sseClients := len(s.sseClients)
+	s.sseClientsMu.RUnlock()
+
+	status := ServerStatus{
+		Version:       serverVersion(),
+		Network:       network,
+		Height:        s.cm.GetHeight(ctx),
+		Sync:          s.cm.GetLagStatus(),
+		PeerCount:     len(peers),
+		Peers:         peers,
+		SSEClients:    sseClients,
+		StoragePath:   s.cm.GetStoragePath(),
+		UptimeSeconds: int64(metrics.CurrentUptimeSeconds),
+	}
+	if progress := s.cm.SyncStatus(); !progress.StartedAt.IsZero() {
+		status.SyncProgress = &progress
+	}
+	if len(metrics.Runs) > 0 {
+		status.StartedAt = metrics.Runs[len(metrics.Runs)-1].StartedAt
+	}
+
+	if tip := s.cm.GetTip(ctx); tip != nil {
+		status.TipHash = tip.Hash.String()
+		if tip.ChainWork != nil {
+			status.TipChainWork = chaintracks.ChainWorkToHex(tip.ChainWork)
+		}
+		tipTime := time.Unix(int64(tip.Header.Timestamp), 0).UTC()
+		status.TipTime = &tipTime
+		status.TipAgeSeconds = int64(time.Since(tipTime).Seconds())
+	}
+	return status
+}
+
+// HandleStatus returns the server status as JSON
+func (s *Server) HandleStatus(c *fiber.Ctx) error {
+	c.Set("Cache-Control", "no-cache")
+	return c.JSON(Response{
+		Status: "success",
+		Value:  s.buildStatus(c.UserContext()),
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getStatus requests /v2/status and returns the raw value object
+func getStatus(t *testing.T, server *Server) map[string]any {
+	t.Helper()
+
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v2/status", nil))
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var decoded any
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	spec, err := newSpecValidator(openapiSpec)
+	require.NoError(t, err)
+	schema, err := spec.responseSchema("/v2/status", "GET", http.StatusOK)
+	require.NoError(t, err)
+	require.NoError(t, spec.validate(schema, decoded, "body"))
+
+	var response struct {
+		Status string         `json:"status"`
+		Value  map[string]any `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+	assert.Equal(t, "success", response.Status)
+	return response.Value
+}
+
+func TestHandleStatus(t *testing.T) {
+	cm := newSyntheticChainManager(t, 10)
+	tip := cm.GetTip(t.Context())
+
+	status := getStatus(t, NewServer(t.Context(), cm))
+
+	assert.Equal(t, "test", status["network"])
+	assert.InDelta(t, 9, status["height"], 0)
+	assert.Equal(t, tip.Hash.String(), status["tipHash"])
+	assert.Equal(t, "1970-01-01T00:00:00Z", status["tipTime"])
+	assert.Greater(t, status["tipAgeSeconds"], float64(0))
+	assert.Equal(t, "synced", status["sync"].(map[string]any)["state"])
+	assert.InDelta(t, 0, status["peerCount"], 0)
+	assert.Empty(t, status["peers"])
+	assert.Equal(t, cm.GetStoragePath(), status["storagePath"])
+	assert.NotEmpty(t, status["version"])
+	assert.Contains(t, status, "startedAt")
+	assert.Contains(t, status, "uptimeSeconds")
+}
+
+func TestHandleStatusEmptyChain(t *testing.T) {
+	status := getStatus(t, NewServer(t.Context(), newSyntheticChainManager(t, 0)))
+
+	assert.InDelta(t, 0, status["height"], 0)
+	assert.NotContains(t, status, "tipHash")
+	assert.NotContains(t, status, "tipTime")
+	assert.InDelta(t, 0, status["tipAgeSeconds"], 0)
+}
+
+func TestServerVersion(t *testing.T) {
+	previous := version
+	t.Cleanup(func() { version = previous })
+
+	version = "v1.2.3"
+	assert.Equal(t, "v1.2.3", serverVersion())
+
+	version = ""
+	assert.NotEmpty(t, serverVersion())
+}
@@ -16,7 +16,7 @@ import (
 
 // getConfigEnvVars returns the environment variables used for configuration
 func getConfigEnvVars() []string {
-	return []string{"PORT", "CHAIN", "STORAGE_PATH", "BOOTSTRAP_URL"}
+	return []string{"PORT", "CHAIN", "STORAGE_PATH", "BOOTSTRAP_URL", "BOOTSTRAP_QUORUM", "WATCHDOG_REFERENCE", "WATCHDOG_INTERVAL", "WHATSONCHAIN_API_KEY", "WHATSONCHAIN_SYNC", "MIRROR_URL", "HEADER_SOURCE", "TERANODE_URL", "BOOTSTRAP_PEERS", "CDN_URLS", "CDN_DOWNLOAD_WORKERS", "CDN_SKIP_FILE_HASH", "CDN_PUBLISH_DIR", "CDN_PUBLISH_INTERVAL", "CDN_SIGNING_KEY", "CDN_PUBLIC_KEY", "CDN_SERVE", "S3_ENDPOINT", "S3_REGION", "S3_BUCKET", "S3_PREFIX", "S3_ACCESS_KEY_ID", "S3_SECRET_ACCESS_KEY", "PROFILE", "RATE_LIMIT", "LAG_THRESHOLD", "P2P_PORT", "P2P_ANNOUNCE_ADDRS", "P2P_MAX_CONNECTIONS", "P2P_MIN_CONNECTIONS", "P2P_PORT_REUSE", "P2P_FALLBACK_SILENCE", "SYNC_BATCH_SIZE", "SYNC_WORKERS", "SYNC_REQUEST_WINDOW", "MEMORY_WINDOW", "MAX_HEADERS", "CACHE_IMMUTABLE_DEPTH", "CACHE_MAX_AGE", "CACHE_HEIGHT_MAX_AGE", "CACHE_NO_CACHE_ROUTES", "TS_COMPAT", "METRICS_ENABLED", "COMPRESSION", "COMPRESS_MIN_SIZE", "DEBUG_ENABLED", "DEBUG_ADDR", "STALE_TIP_THRESHOLD", "KAFKA_BROKERS", "KAFKA_TOPIC", "KAFKA_USERNAME", "KAFKA_PASSWORD", "KAFKA_SASL_MECHANISM", "KAFKA_TLS", "NATS_URL", "NATS_SUBJECT_PREFIX", "NATS_CREDS", "NATS_TOKEN", "NATS_JETSTREAM", "NATS_STREAM", "NATS_STREAM_MAX_AGE", "ZMQ_ENDPOINT", "MQTT_BROKER_URL", "MQTT_TOPIC_PREFIX", "MQTT_QOS", "MQTT_CLIENT_ID", "MQTT_USERNAME", "MQTT_PASSWORD", "REDIS_URL", "REDIS_CHANNEL_PREFIX", "REDIS_TIP_KEY", "AUTH_JWT_SECRET", "AUTH_JWKS_URL", "AUTH_JWT_ISSUER", "AUTH_JWT_AUDIENCE", "AUTH_ROLES_CLAIM", "ADMIN_TOKEN", "BRC103_PRIVATE_KEY", "BRC103_ALLOWED_KEYS", "BRC103_ALLOW_UNAUTHENTICATED", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CLIENT_CA_FILE", "UNIX_SOCKET"}
 }
 
 // withEnvVars sets environment variables for a test and returns a cleanup function
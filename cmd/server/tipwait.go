@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// defaultTipWaitTimeout is how long /v2/tip/wait holds a request when no timeout is given
+	defaultTipWaitTimeout = 30 * time.Second
+
+	// maxTipWaitTimeout caps the timeout accepted by /v2/tip/wait, below common proxy idle timeouts
+	maxTipWaitTimeout = time.Minute
+)
+
+// HandleTipWait long-polls for a tip above the since height, for clients whose proxies cut SSE streams
+// It answers at once with the tip when the tip is already above since, otherwise holds the request until a
+// new tip arrives or the timeout passes, answering 204 on timeout. A reorg that keeps the tip height does not
+// wake the request.
+func (s *Server) HandleTipWait(c *fiber.Ctx) error {
+	since, err := strconv.ParseUint(c.Query("since"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid since parameter",
+		})
+	}
+
+	timeout := defaultTipWaitTimeout
+	if timeoutStr := c.Query("timeout"); timeoutStr != "" {
+		timeout, err = time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 || timeout > maxTipWaitTimeout {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: "Invalid timeout parameter (up to " + maxTipWaitTimeout.String() + ")",
+			})
+		}
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx := c.UserContext()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		// Take the change channel before reading the tip, so a tip arriving in between still wakes us
+		changed := s.tipChange()
+		if tip := s.cm.GetTip(ctx); tip != nil && uint64(tip.Height) > since {
+			return c.JSON(Response{
+				Status: "success",
+				Value:  tip,
+			})
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return c.SendStatus(fiber.StatusNoContent)
+		case <-s.ctx.Done():
+			return c.SendStatus(fiber.StatusServiceUnavailable)
+		}
+	}
+}
+
+// tipChange returns a channel closed at the next tip change
+func (s *Server) tipChange() <-chan struct{} {
+	s.tipMu.Lock()
+	defer s.tipMu.Unlock()
+
+	if s.tipChanged == nil {
+		s.tipChanged = make(chan struct{})
+	}
+	return s.tipChanged
+}
+
+// notifyTipChange wakes every request waiting in HandleTipWait
+func (s *Server) notifyTipChange() {
+	s.tipMu.Lock()
+	defer s.tipMu.Unlock()
+
+	if s.tipChanged != nil {
+		close(s.tipChanged)
+		s.tipChanged = nil
+	}
+}
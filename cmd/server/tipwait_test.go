@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// setupTipWaitApp serves a 10-header synthetic chain with tip events delivered to waiters
+func setupTipWaitApp(t *testing.T) (*fiber.App, *chaintracks.ChainManager) {
+	t.Helper()
+
+	cm := newSyntheticChainManager(t, 10)
+	server := NewServer(t.Context(), cm)
+	server.StartBroadcasting(t.Context(), cm.SubscribeEvents(t.Context()))
+	app := fiber.New()
+	server.SetupRoutes(app, NewDashboardHandler(server))
+	return app, cm
+}
+
+func TestHandleTipWait(t *testing.T) {
+	app, _ := setupTipWaitApp(t)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "NewerTipReturnsAtOnce", path: "/v2/tip/wait?since=5", expectedStatus: http.StatusOK},
+		{name: "TimesOut", path: "/v2/tip/wait?since=9&timeout=50ms", expectedStatus: http.StatusNoContent},
+		{name: "MissingSince", path: "/v2/tip/wait", expectedStatus: http.StatusBadRequest},
+		{name: "InvalidTimeout", path: "/v2/tip/wait?since=9&timeout=soon", expectedStatus: http.StatusBadRequest},
+		{name: "TimeoutAboveMax", path: "/v2/tip/wait?since=9&timeout=1h", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httpGet(t, app, tt.path)
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusOK {
+				body := requireSuccessResponse(t, resp.Body)
+				assert.InDelta(t, 9, body.Value.(map[string]any)["height"], 0)
+			}
+		})
+	}
+}
+
+func TestHandleTipWaitWakesOnNewTip(t *testing.T) {
+	app, cm := setupTipWaitApp(t)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		next := &chaintracks.BlockHeader{Header: &block.Header{Nonce: 10}, Height: 10, Hash: chainhash.Hash{10}}
+		_ = cm.SetChainTip(t.Context(), []*chaintracks.BlockHeader{next})
+	}()
+
+	start := time.Now()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v2/tip/wait?since=9&timeout=5s", nil), 5000)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Less(t, time.Since(start), 5*time.Second, "the request returns when the tip arrives, not at the timeout")
+	assert.Equal(t, uint32(10), cm.GetHeight(t.Context()))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// certCheckInterval limits how often handshakes stat the certificate files for a renewal
+const certCheckInterval = 10 * time.Second
+
+var (
+	errTLSKeyPair    = errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	errTLSClientCA   = errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	errNoClientCAPEM = errors.New("no PEM certificates found")
+)
+
+// TLSConfig configures native TLS; it is disabled when no file is set
+type TLSConfig struct {
+	CertFile     string // PEM certificate chain, reloaded when the file changes
+	KeyFile      string // PEM private key
+	ClientCAFile string // PEM CA bundle; when set, clients must present a certificate it signed (mTLS)
+}
+
+// Enabled reports whether the server should serve HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.ClientCAFile != ""
+}
+
+// newTLSConfig loads the certificate and client CA, failing fast on a bad setup rather than at the first handshake
+func newTLSConfig(config TLSConfig) (*tls.Config, error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		if config.CertFile == "" && config.KeyFile == "" {
+			return nil, errTLSClientCA
+		}
+		return nil, errTLSKeyPair
+	}
+
+	reloader, err := newCertReloader(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if config.ClientCAFile != "" {
+		pem, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid client CA %s: %w", config.ClientCAFile, errNoClientCAPEM)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// certReloader serves the current certificate and picks up renewals without a restart
+// A renewal that fails to load, e.g. because only the certificate has been replaced so far, keeps the previous
+// certificate and is retried at the next check.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time // Latest modification time of the two files when cert was loaded
+	checked time.Time
+}
+
+// newCertReloader loads the initial certificate
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, interval: certCheckInterval}
+	modTime, err := r.modified()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	r.checked = time.Now()
+	return r, nil
+}
+
+// modified returns the latest modification time of the certificate and key files
+func (r *certReloader) modified() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// load reads the key pair; the caller holds mu or has not shared r yet
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.checked) >= r.interval {
+		r.checked = now
+		modTime, err := r.modified()
+		switch {
+		case err != nil:
+			slog.Warn("Failed to check TLS certificate, keeping the current one", "error", err)
+		case !modTime.Equal(r.modTime):
+			if err := r.load(modTime); err != nil {
+				slog.Warn("Failed to reload TLS certificate, keeping the current one", "error", err)
+			} else {
+				slog.Info("Reloaded TLS certificate", "certFile", r.certFile)
+			}
+		}
+	}
+	return r.cert, nil
+}
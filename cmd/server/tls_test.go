@@ -0,0 +1,224 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCert is a generated certificate with its key, written as PEM files
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// writeTestCert generates a certificate signed by parent, or self-signed CA when parent is nil
+func writeTestCert(t *testing.T, dir, name string, serial int64, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	tc := &testCert{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(dir, name+".crt"),
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+	require.NoError(t, os.WriteFile(tc.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(tc.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return tc
+}
+
+// serveTLS serves a minimal app over TLS and returns its base URL
+func serveTLS(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = app.Listener(tls.NewListener(ln, tlsConfig))
+	}()
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+	return "https://" + ln.Addr().String()
+}
+
+// tlsClient trusts ca and presents client, if set
+func tlsClient(ca *testCert, client *testCert) *http.Client {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	config := &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	if client != nil {
+		config.Certificates = []tls.Certificate{{Certificate: [][]byte{client.cert.Raw}, PrivateKey: client.key}}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: config}, Timeout: 5 * time.Second}
+}
+
+// servedSerial returns the serial number of the certificate the server presents
+func servedSerial(t *testing.T, client *http.Client, url string) int64 {
+	t.Helper()
+
+	resp, err := client.Get(url) //nolint:noctx // Test request
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+}
+
+func TestTLSServesCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeTestCert(t, dir, "ca", 1, nil)
+	writeTestCert(t, dir, "server", 2, ca)
+
+	tlsConfig, err := newTLSConfig(TLSConfig{
+		CertFile: filepath.Join(dir, "server.crt"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	})
+	require.NoError(t, err)
+	url := serveTLS(t, tlsConfig)
+
+	client := tlsClient(ca, nil)
+	assert.Equal(t, int64(2), servedSerial(t, client, url))
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeTestCert(t, dir, "ca", 1, nil)
+	server := writeTestCert(t, dir, "server", 2, ca)
+
+	reloader, err := newCertReloader(server.certFile, server.keyFile)
+	require.NoError(t, err)
+	reloader.interval = 0
+
+	serial := func() int64 {
+		cert, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.SerialNumber.Int64()
+	}
+	assert.Equal(t, int64(2), serial())
+
+	t.Run("KeepsCertificateWhileRenewalIsPartial", func(t *testing.T) {
+		renewed := writeTestCert(t, t.TempDir(), "server", 3, ca)
+		certPEM, err := os.ReadFile(renewed.certFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(server.certFile, certPEM, 0o600))
+		future := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(server.certFile, future, future))
+
+		assert.Equal(t, int64(2), serial())
+
+		keyPEM, err := os.ReadFile(renewed.keyFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(server.keyFile, keyPEM, 0o600))
+		require.NoError(t, os.Chtimes(server.keyFile, future, future))
+
+		assert.Equal(t, int64(3), serial())
+	})
+
+	t.Run("KeepsCertificateWhenFilesDisappear", func(t *testing.T) {
+		require.NoError(t, os.Remove(server.keyFile))
+		assert.Equal(t, int64(3), serial())
+	})
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeTestCert(t, dir, "ca", 1, nil)
+	server := writeTestCert(t, dir, "server", 2, ca)
+	client := writeTestCert(t, dir, "client", 3, ca)
+
+	otherCA := writeTestCert(t, t.TempDir(), "other-ca", 4, nil)
+	stranger := writeTestCert(t, t.TempDir(), "stranger", 5, otherCA)
+
+	tlsConfig, err := newTLSConfig(TLSConfig{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: ca.certFile})
+	require.NoError(t, err)
+	url := serveTLS(t, tlsConfig)
+
+	assert.Equal(t, int64(2), servedSerial(t, tlsClient(ca, client), url))
+
+	for name, cert := range map[string]*testCert{"NoClientCertificate": nil, "UntrustedClientCertificate": stranger} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := tlsClient(ca, cert).Get(url) //nolint:noctx // Test request
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	server := writeTestCert(t, dir, "server", 1, nil)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.pem"), []byte("not pem"), 0o600))
+
+	tests := []struct {
+		name   string
+		config TLSConfig
+		err    error
+	}{
+		{"CertWithoutKey", TLSConfig{CertFile: server.certFile}, errTLSKeyPair},
+		{"ClientCAWithoutCert", TLSConfig{ClientCAFile: server.certFile}, errTLSClientCA},
+		{"InvalidClientCA", TLSConfig{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: filepath.Join(dir, "empty.pem")}, errNoClientCAPEM},
+		{"MissingCertFile", TLSConfig{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: server.keyFile}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTLSConfig(tt.config)
+			require.Error(t, err)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
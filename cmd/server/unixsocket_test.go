@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+func TestListenUnix(t *testing.T) {
+	t.Run("ServesClients", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "chaintracks.sock")
+		ln, err := listenUnix(path)
+		require.NoError(t, err)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+
+		server := NewServer(t.Context(), newSyntheticChainManager(t, 10))
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		server.SetupRoutes(app, NewDashboardHandler(server))
+		go func() {
+			_ = app.Listener(ln)
+		}()
+		t.Cleanup(func() {
+			_ = app.Shutdown()
+		})
+
+		header, err := chaintracks.NewClient("unix://"+path).GetHeaderByHeight(t.Context(), 9)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(9), header.Height)
+	})
+
+	t.Run("ReplacesStaleSocket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "chaintracks.sock")
+		stale, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		stale.(*net.UnixListener).SetUnlinkOnClose(false) //nolint:forcetypeassert // Always a unix listener
+		require.NoError(t, stale.Close())
+
+		ln, err := listenUnix(path)
+		require.NoError(t, err)
+		require.NoError(t, ln.Close())
+		assert.NoFileExists(t, path, "the socket is removed when the listener closes")
+	})
+
+	t.Run("RefusesSocketInUse", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "chaintracks.sock")
+		live, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		defer func() {
+			_ = live.Close()
+		}()
+
+		_, err = listenUnix(path)
+		require.ErrorIs(t, err, errSocketInUse)
+	})
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/go-zeromq/zmq4"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// bitcoind-compatible ZMQ topics
+const (
+	zmqTopicHashBlock = "hashblock"
+	zmqTopicRawHeader = "rawheader"
+)
+
+// maxZMQCatchUp caps the blocks announced for one tip event when the tip jumps, e.g. after a bootstrap sync
+const maxZMQCatchUp = 1000
+
+// zmqSink publishes hashblock and rawheader notifications in the bitcoind wire format
+// Each message is three frames: topic, body and a little-endian uint32 sequence counted per topic.
+// Like bitcoind, every newly connected block is announced, including each block of a reorg's new branch.
+type zmqSink struct {
+	pub        zmq4.Socket
+	headers    chaintracks.Chaintracks // Looks up the blocks a tip event skipped over
+	seq        map[string]uint32
+	lastHeight uint32
+	hasLast    bool
+}
+
+// newZMQSink binds a PUB socket on endpoint, e.g. tcp://0.0.0.0:28332
+func newZMQSink(ctx context.Context, endpoint string, headers chaintracks.Chaintracks) (*zmqSink, error) {
+	pub := zmq4.NewPub(ctx)
+	if err := pub.Listen(endpoint); err != nil {
+		_ = pub.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", endpoint, err)
+	}
+
+	sink := &zmqSink{pub: pub, headers: headers, seq: make(map[string]uint32)}
+	if tip := headers.GetTip(ctx); tip != nil {
+		sink.lastHeight, sink.hasLast = tip.Height, true
+	}
+	return sink, nil
+}
+
+// Name implements eventSink
+func (z *zmqSink) Name() string {
+	return "zmq"
+}
+
+// Publish implements eventSink, sending hashblock then rawheader for every connected block, oldest first
+func (z *zmqSink) Publish(ctx context.Context, event *chaintracks.ChainEvent, _ []byte) error {
+	for _, header := range z.connected(ctx, event) {
+		hash := displayOrder(header.Hash)
+		if err := z.send(zmqTopicHashBlock, hash[:]); err != nil {
+			return err
+		}
+		if err := z.send(zmqTopicRawHeader, header.Header.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connected returns the blocks an event added to the main chain, oldest first
+func (z *zmqSink) connected(ctx context.Context, event *chaintracks.ChainEvent) []*chaintracks.BlockHeader {
+	tip := event.Tip
+	if tip == nil {
+		return nil
+	}
+
+	var blocks []*chaintracks.BlockHeader
+	switch {
+	case event.Type == chaintracks.EventReorg && event.Reorg != nil && len(event.Reorg.NewBranch) > 0:
+		blocks = event.Reorg.NewBranch
+	case z.hasLast && tip.Height > z.lastHeight+1:
+		from := z.lastHeight + 1
+		if tip.Height-from >= maxZMQCatchUp {
+			from = tip.Height - maxZMQCatchUp + 1
+		}
+		for height := from; height < tip.Height; height++ {
+			if header, err := z.headers.GetHeaderByHeight(ctx, height); err == nil {
+				blocks = append(blocks, header)
+			}
+		}
+		blocks = append(blocks, tip)
+	default:
+		blocks = []*chaintracks.BlockHeader{tip}
+	}
+
+	z.lastHeight, z.hasLast = tip.Height, true
+	return blocks
+}
+
+// displayOrder reverses a hash into the byte order it is displayed in, which bitcoind uses on the wire
+func displayOrder(hash chainhash.Hash) chainhash.Hash {
+	for i, j := 0, len(hash)-1; i < j; i, j = i+1, j-1 {
+		hash[i], hash[j] = hash[j], hash[i]
+	}
+	return hash
+}
+
+// send publishes one notification and advances the topic sequence
+func (z *zmqSink) send(topic string, body []byte) error {
+	seq := make([]byte, 4)
+	binary.LittleEndian.PutUint32(seq, z.seq[topic])
+	z.seq[topic]++
+	return z.pub.Send(zmq4.NewMsgFrom([]byte(topic), body, seq))
+}
+
+// Close implements eventSink
+func (z *zmqSink) Close() error {
+	return z.pub.Close()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/go-zeromq/zmq4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// connectedHeights returns the heights zmqSink.connected announces for event
+func connectedHeights(sink *zmqSink, t *testing.T, event *chaintracks.ChainEvent) []uint32 {
+	var heights []uint32
+	for _, header := range sink.connected(t.Context(), event) {
+		heights = append(heights, header.Height)
+	}
+	return heights
+}
+
+func TestZMQSinkConnected(t *testing.T) {
+	cm := newSyntheticChainManager(t, 2000)
+	header := func(height uint32) *chaintracks.BlockHeader {
+		h, err := cm.GetHeaderByHeight(t.Context(), height)
+		require.NoError(t, err)
+		return h
+	}
+
+	tests := []struct {
+		name       string
+		lastHeight uint32
+		hasLast    bool
+		event      *chaintracks.ChainEvent
+		expected   []uint32
+	}{
+		{
+			name:     "FirstEventAnnouncesTip",
+			event:    &chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced, Tip: header(10)},
+			expected: []uint32{10},
+		},
+		{
+			name:       "NextBlock",
+			lastHeight: 9,
+			hasLast:    true,
+			event:      &chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced, Tip: header(10)},
+			expected:   []uint32{10},
+		},
+		{
+			name:       "JumpAnnouncesSkippedBlocks",
+			lastHeight: 7,
+			hasLast:    true,
+			event:      &chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced, Tip: header(10)},
+			expected:   []uint32{8, 9, 10},
+		},
+		{
+			name:       "ReorgAnnouncesNewBranch",
+			lastHeight: 10,
+			hasLast:    true,
+			event: &chaintracks.ChainEvent{
+				Type:  chaintracks.EventReorg,
+				Tip:   header(11),
+				Reorg: &chaintracks.ReorgInfo{ForkHeight: 8, NewBranch: []*chaintracks.BlockHeader{header(9), header(10), header(11)}},
+			},
+			expected: []uint32{9, 10, 11},
+		},
+		{
+			name:     "NoTip",
+			event:    &chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &zmqSink{headers: cm, lastHeight: tt.lastHeight, hasLast: tt.hasLast}
+			assert.Equal(t, tt.expected, connectedHeights(sink, t, tt.event))
+		})
+	}
+
+	t.Run("CatchUpIsCapped", func(t *testing.T) {
+		sink := &zmqSink{headers: cm, hasLast: true}
+		heights := connectedHeights(sink, t, &chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced, Tip: header(1999)})
+		require.Len(t, heights, maxZMQCatchUp)
+		assert.Equal(t, uint32(1000), heights[0])
+		assert.Equal(t, uint32(1999), heights[len(heights)-1])
+		assert.Equal(t, uint32(1999), sink.lastHeight)
+	})
+}
+
+func TestZMQSinkPublish(t *testing.T) {
+	cm := newSyntheticChainManager(t, 3)
+	sink, err := newZMQSink(t.Context(), "tcp://127.0.0.1:0", cm)
+	require.NoError(t, err)
+	defer func() {
+		_ = sink.Close()
+	}()
+	assert.Equal(t, uint32(2), sink.lastHeight)
+
+	sub := zmq4.NewSub(t.Context())
+	defer func() {
+		_ = sub.Close()
+	}()
+	require.NoError(t, sub.Dial("tcp://"+sink.pub.Addr().String()))
+	require.NoError(t, sub.SetOption(zmq4.OptionSubscribe, ""))
+	time.Sleep(200 * time.Millisecond) // Let the subscription reach the publisher
+
+	extendSyntheticChain(t, cm, 2)
+	for height := uint32(3); height <= 4; height++ {
+		tip, err := cm.GetHeaderByHeight(t.Context(), height)
+		require.NoError(t, err)
+		require.NoError(t, sink.Publish(t.Context(), &chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced, Tip: tip}, nil))
+
+		msg, err := sub.Recv()
+		require.NoError(t, err)
+		require.Len(t, msg.Frames, 3)
+		assert.Equal(t, zmqTopicHashBlock, string(msg.Frames[0]))
+		assert.Equal(t, tip.Hash.String(), hex.EncodeToString(msg.Frames[1]), "hash is sent in display order")
+		assert.Equal(t, height-3, binary.LittleEndian.Uint32(msg.Frames[2]))
+
+		msg, err = sub.Recv()
+		require.NoError(t, err)
+		require.Len(t, msg.Frames, 3)
+		assert.Equal(t, zmqTopicRawHeader, string(msg.Frames[0]))
+		assert.Equal(t, tip.Header.Bytes(), msg.Frames[1])
+		assert.Equal(t, height-3, binary.LittleEndian.Uint32(msg.Frames[2]))
+	}
+}
@@ -0,0 +1,166 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// FindCommonAncestor returns the most recent header shared by the branches ending at hashA and hashB
+// Both headers must be known, either on the main chain or as a retained orphan
+func (cm *ChainManager) FindCommonAncestor(_ context.Context, hashA, hashB *chainhash.Hash) (*BlockHeader, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	a, ok := cm.headerByHash(*hashA)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrHeaderNotFound, hashA)
+	}
+	b, ok := cm.headerByHash(*hashB)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrHeaderNotFound, hashB)
+	}
+
+	var err error
+	for a.Hash != b.Hash {
+		switch {
+		case a.Height > b.Height:
+			a, err = cm.stepBack(a, b.Height)
+		case b.Height > a.Height:
+			b, err = cm.stepBack(b, a.Height)
+		default:
+			// Same height, different hashes: the ancestor is strictly below
+			if a, err = cm.parentOf(a); err == nil {
+				b, err = cm.parentOf(b)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// FindFork returns the first header in a block locator that is on the main chain
+// Locators list hashes newest first, as in the P2P getheaders message. If none match,
+// the genesis header is returned so the caller can resync from the start.
+func (cm *ChainManager) FindFork(_ context.Context, locator []chainhash.Hash) (*BlockHeader, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, hash := range locator {
+		if header, ok := cm.headerByHash(hash); ok && cm.isMainChain(header) {
+			return header, nil
+		}
+	}
+
+	genesis, ok := cm.mainHeaderAt(0)
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+	return genesis, nil
+}
+
+// isMainChain reports whether a header is on the current main chain (must be called with lock held)
+func (cm *ChainManager) isMainChain(header *BlockHeader) bool {
+	return int(header.Height) < len(cm.byHeight) && cm.byHeight[header.Height] == header.Hash
+}
+
+// stepBack moves a header towards height, jumping directly when it is on the main chain (must be called with lock held)
+func (cm *ChainManager) stepBack(header *BlockHeader, height uint32) (*BlockHeader, error) {
+	if cm.isMainChain(header) {
+		if target, ok := cm.mainHeaderAt(height); ok {
+			return target, nil
+		}
+	}
+	return cm.parentOf(header)
+}
+
+// parentOf returns the parent of a header (must be called with lock held)
+func (cm *ChainManager) parentOf(header *BlockHeader) (*BlockHeader, error) {
+	if header.Height == 0 || header.Header == nil {
+		return nil, ErrCommonAncestorNotFound
+	}
+	parent, ok := cm.parentHeader(header)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing parent of %s", ErrCommonAncestorNotFound, header.Hash)
+	}
+	return parent, nil
+}
+
+// ChainTipStatus describes a branch tip, using bitcoind's getchaintips vocabulary
+type ChainTipStatus string
+
+const (
+	// ChainTipActive is the tip of the main chain
+	ChainTipActive ChainTipStatus = "active"
+
+	// ChainTipValidHeaders is a stale branch whose headers connect to the main chain
+	ChainTipValidHeaders ChainTipStatus = "valid-headers"
+
+	// ChainTipHeadersOnly is a branch whose ancestry could not be traced back to the main chain
+	ChainTipHeadersOnly ChainTipStatus = "headers-only"
+
+	// ChainTipInvalid is a branch containing a block marked invalid by InvalidateBlock
+	ChainTipInvalid ChainTipStatus = "invalid"
+)
+
+// ChainTip describes the tip of the main chain or of a known side branch
+type ChainTip struct {
+	Height    uint32         `json:"height"`
+	Hash      chainhash.Hash `json:"hash"`
+	BranchLen uint32         `json:"branchlen"` // Blocks between the tip and the main chain, 0 for the active tip
+	Status    ChainTipStatus `json:"status"`
+}
+
+// GetChainTips returns the main chain tip followed by the tips of retained side branches, highest first
+func (cm *ChainManager) GetChainTips(_ context.Context) []ChainTip {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	mainTip := cm.tip.Load()
+	if mainTip == nil {
+		return []ChainTip{}
+	}
+
+	hasChild := make(map[chainhash.Hash]bool, len(cm.byHash))
+	for _, header := range cm.byHash {
+		if header.Header != nil {
+			hasChild[header.PrevHash] = true
+		}
+	}
+
+	var sideTips []ChainTip
+	for hash, header := range cm.byHash {
+		if hasChild[hash] || cm.isMainChain(header) {
+			continue
+		}
+
+		tip := ChainTip{Height: header.Height, Hash: hash, Status: ChainTipValidHeaders}
+		for walk := header; !cm.isMainChain(walk); tip.BranchLen++ {
+			parent, err := cm.parentOf(walk)
+			if err != nil {
+				tip.Status = ChainTipHeadersOnly
+				tip.BranchLen++
+				break
+			}
+			walk = parent
+		}
+		if cm.isInvalid(header, mainTip.Height) {
+			tip.Status = ChainTipInvalid
+		}
+		sideTips = append(sideTips, tip)
+	}
+
+	sort.Slice(sideTips, func(i, j int) bool {
+		if sideTips[i].Height != sideTips[j].Height {
+			return sideTips[i].Height > sideTips[j].Height
+		}
+		return sideTips[i].Hash.String() < sideTips[j].Hash.String()
+	})
+
+	return append([]ChainTip{{Height: mainTip.Height, Hash: mainTip.Hash, Status: ChainTipActive}}, sideTips...)
+}
@@ -0,0 +1,133 @@
+package chaintracks
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkedHeader builds a synthetic header whose PrevHash points at parent
+func linkedHeader(parent *BlockHeader, tag byte) *BlockHeader {
+	header := &BlockHeader{Header: &block.Header{}}
+	if parent != nil {
+		header.PrevHash = parent.Hash
+		header.Height = parent.Height + 1
+	}
+	header.Hash = chainhash.Hash{tag, byte(header.Height)}
+	return header
+}
+
+// newForkedChainManager builds a main chain 0..5 with a stale fork 3'..4' branching after height 2
+func newForkedChainManager(t *testing.T) (*ChainManager, []*BlockHeader, []*BlockHeader) {
+	t.Helper()
+
+	main := []*BlockHeader{linkedHeader(nil, 1)}
+	for i := 1; i <= 5; i++ {
+		main = append(main, linkedHeader(main[i-1], 1))
+	}
+
+	fork := []*BlockHeader{linkedHeader(main[2], 2)}
+	fork = append(fork, linkedHeader(fork[0], 2))
+
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+	require.NoError(t, cm.SetChainTip(t.Context(), main))
+	for _, header := range fork {
+		require.NoError(t, cm.AddHeader(header))
+	}
+	return cm, main, fork
+}
+
+func TestChainManagerFindCommonAncestor(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+	forkTip := linkedHeader(fork[1], 3)
+	require.NoError(t, cm.AddHeader(forkTip))
+
+	tests := []struct {
+		name     string
+		a        *BlockHeader
+		b        *BlockHeader
+		expected *BlockHeader
+	}{
+		{name: "SameHeaderIsItsOwnAncestor", a: main[4], b: main[4], expected: main[4]},
+		{name: "MainChainHeadersReturnLower", a: main[5], b: main[1], expected: main[1]},
+		{name: "ForkAndMainTipMeetAtForkPoint", a: fork[1], b: main[5], expected: main[2]},
+		{name: "OrderDoesNotMatter", a: main[5], b: fork[1], expected: main[2]},
+		{name: "SameHeightBranchesMeetAtForkPoint", a: fork[0], b: main[3], expected: main[2]},
+		{name: "HeadersOnSameForkMeetOnFork", a: forkTip, b: fork[0], expected: fork[0]},
+		{name: "ForkAndMainAncestorBelowForkPoint", a: fork[1], b: main[1], expected: main[1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ancestor, err := cm.FindCommonAncestor(t.Context(), &tt.a.Hash, &tt.b.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Hash, ancestor.Hash)
+		})
+	}
+
+	t.Run("UnknownHashReturnsNotFound", func(t *testing.T) {
+		unknown := chainhash.Hash{0xff}
+		_, err := cm.FindCommonAncestor(t.Context(), &unknown, &main[0].Hash)
+		require.ErrorIs(t, err, ErrHeaderNotFound)
+	})
+
+	t.Run("DisconnectedBranchReturnsNotFound", func(t *testing.T) {
+		island := &BlockHeader{Header: &block.Header{PrevHash: chainhash.Hash{0xee}}, Height: 4, Hash: chainhash.Hash{0xdd}}
+		require.NoError(t, cm.AddHeader(island))
+
+		_, err := cm.FindCommonAncestor(t.Context(), &island.Hash, &main[5].Hash)
+		require.ErrorIs(t, err, ErrCommonAncestorNotFound)
+	})
+}
+
+func TestChainManagerFindFork(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+
+	tests := []struct {
+		name     string
+		locator  []chainhash.Hash
+		expected *BlockHeader
+	}{
+		{name: "ReturnsFirstMainChainMatch", locator: []chainhash.Hash{main[4].Hash, main[2].Hash}, expected: main[4]},
+		{name: "SkipsStaleForkHashes", locator: []chainhash.Hash{fork[1].Hash, fork[0].Hash, main[2].Hash, main[0].Hash}, expected: main[2]},
+		{name: "SkipsUnknownHashes", locator: []chainhash.Hash{{0xaa}, main[1].Hash}, expected: main[1]},
+		{name: "NoMatchReturnsGenesis", locator: []chainhash.Hash{{0xaa}}, expected: main[0]},
+		{name: "EmptyLocatorReturnsGenesis", expected: main[0]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, err := cm.FindFork(t.Context(), tt.locator)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Hash, header.Hash)
+		})
+	}
+
+	t.Run("EmptyChainReturnsNotFound", func(t *testing.T) {
+		empty := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+		_, err := empty.FindFork(t.Context(), nil)
+		require.ErrorIs(t, err, ErrHeaderNotFound)
+	})
+}
+
+func TestChainManagerGetChainTips(t *testing.T) {
+	t.Run("EmptyChainHasNoTips", func(t *testing.T) {
+		cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+		assert.Empty(t, cm.GetChainTips(t.Context()))
+	})
+
+	t.Run("ReportsActiveTipAndSideBranches", func(t *testing.T) {
+		cm, main, fork := newForkedChainManager(t)
+		island := &BlockHeader{Header: &block.Header{PrevHash: chainhash.Hash{0xee}}, Height: 3, Hash: chainhash.Hash{0xdd}}
+		require.NoError(t, cm.AddHeader(island))
+
+		assert.Equal(t, []ChainTip{
+			{Height: main[5].Height, Hash: main[5].Hash, BranchLen: 0, Status: ChainTipActive},
+			{Height: fork[1].Height, Hash: fork[1].Hash, BranchLen: 2, Status: ChainTipValidHeaders},
+			{Height: island.Height, Hash: island.Hash, BranchLen: 1, Status: ChainTipHeadersOnly},
+		}, cm.GetChainTips(t.Context()))
+	})
+}
@@ -0,0 +1,42 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// Anchor bundles the block data an SPV receipt needs to anchor a transaction to a block
+type Anchor struct {
+	Height        uint32         `json:"height"`
+	MerkleRoot    chainhash.Hash `json:"merkleRoot"`
+	Confirmations uint32         `json:"confirmations"`       // 0 when the block is not on the main chain
+	ChainWork     string         `json:"chainwork,omitempty"` // Cumulative work as hex, when the source tracks it
+	HeaderHex     string         `json:"headerHex"`
+}
+
+// GetAnchor builds the anchor bundle for a block hash from any Chaintracks implementation
+func GetAnchor(ctx context.Context, ct Chaintracks, blockHash *chainhash.Hash) (*Anchor, error) {
+	header, err := ct.GetHeaderByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if header.Header == nil {
+		return nil, fmt.Errorf("%w: missing header fields for %s", ErrInvalidHeader, blockHash)
+	}
+
+	anchor := &Anchor{
+		Height:     header.Height,
+		MerkleRoot: header.MerkleRoot,
+		HeaderHex:  hex.EncodeToString(header.Bytes()),
+	}
+	if header.ChainWork != nil {
+		anchor.ChainWork = ChainWorkToHex(header.ChainWork)
+	}
+
+	anchor.Confirmations = confirmationsFor(ctx, ct, header).Confirmations
+
+	return anchor, nil
+}
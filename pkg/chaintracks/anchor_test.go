@@ -0,0 +1,51 @@
+package chaintracks
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAnchor(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+	main[3].ChainWork = big.NewInt(0x1234)
+	main[3].MerkleRoot = chainhash.Hash{0xab}
+
+	tests := []struct {
+		name                  string
+		header                *BlockHeader
+		expectedConfirmations uint32
+		expectedChainWork     string
+	}{
+		{name: "TipHasOneConfirmation", header: main[5], expectedConfirmations: 1},
+		{
+			name:                  "MainChainBlockCountsConfirmations",
+			header:                main[3],
+			expectedConfirmations: 3,
+			expectedChainWork:     ChainWorkToHex(big.NewInt(0x1234)),
+		},
+		{name: "OrphanHasNoConfirmations", header: fork[1], expectedConfirmations: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anchor, err := GetAnchor(t.Context(), cm, &tt.header.Hash)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.header.Height, anchor.Height)
+			assert.Equal(t, tt.header.MerkleRoot, anchor.MerkleRoot)
+			assert.Equal(t, tt.expectedConfirmations, anchor.Confirmations)
+			assert.Equal(t, tt.expectedChainWork, anchor.ChainWork)
+			assert.Equal(t, hex.EncodeToString(tt.header.Bytes()), anchor.HeaderHex)
+		})
+	}
+
+	t.Run("UnknownHashReturnsNotFound", func(t *testing.T) {
+		_, err := GetAnchor(t.Context(), cm, &chainhash.Hash{0xff})
+		require.ErrorIs(t, err, ErrHeaderNotFound)
+	})
+}
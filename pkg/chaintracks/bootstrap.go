@@ -0,0 +1,127 @@
+package chaintracks
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// bootstrapTip is one bootstrap source's answer to the tip probe
+type bootstrapTip struct {
+	url     string
+	hash    chainhash.Hash
+	latency time.Duration
+	err     error
+}
+
+// SetBootstrapQuorum sets how many bootstrap sources must report the same tip before BootstrapSync trusts it
+// Zero, the default, requires a majority of the sources that answered.
+func (cm *ChainManager) SetBootstrapQuorum(quorum int) {
+	cm.bootstrapQuorum = max(quorum, 0)
+}
+
+// BootstrapSync performs an initial sync from bootstrap nodes; failures are logged and left to P2P sync
+// Every source is asked for its tip concurrently. Sync proceeds only if a quorum reports the same tip hash,
+// which pins its height too, and downloads from the fastest of those sources, failing over to the next
+// fastest when one fails. Unreachable sources are skipped. Passing bootstrap URLs to NewChainManager runs
+// it during construction. Call it directly instead to observe progress through SyncStatus and
+// EventSyncProgress while it runs.
+func (cm *ChainManager) BootstrapSync(ctx context.Context, urls ...string) {
+	urls = slices.DeleteFunc(slices.Clone(urls), func(url string) bool { return url == "" })
+	if len(urls) == 0 {
+		return
+	}
+	cm.log().Info("Bootstrap URLs configured", "urls", urls)
+
+	tips := probeBootstrapTips(ctx, urls)
+	for _, tip := range tips {
+		if tip.err != nil {
+			cm.log().Warn("Failed to get bootstrap node tip", "url", tip.url, "error", tip.err)
+		}
+	}
+
+	sources, ok := bootstrapAgreement(tips, cm.bootstrapQuorum)
+	if !ok {
+		args := make([]any, 0, 2*len(tips))
+		for _, tip := range tips {
+			if tip.err == nil {
+				args = append(args, tip.url, tip.hash)
+			}
+		}
+		cm.log().Warn("Bootstrap nodes do not agree on a tip, continuing with P2P sync", args...)
+		return
+	}
+
+	remoteTipHash := sources[0].hash
+	cm.log().Info("Bootstrap node tip", "hash", remoteTipHash, "agreeing", len(sources), "answering", countAnswered(tips))
+	for _, source := range sources {
+		if err := cm.SyncFromRemoteTip(ctx, remoteTipHash, source.url); err != nil {
+			cm.log().Warn("Bootstrap sync failed, trying next source", "url", source.url, "error", err)
+			continue
+		}
+
+		// Log updated chain state after bootstrap
+		if tip := cm.GetTip(ctx); tip != nil {
+			cm.log().Info("Chain tip after bootstrap", "hash", tip.Hash, "height", tip.Height, "url", source.url)
+		}
+		return
+	}
+	cm.log().Warn("Bootstrap sync failed from every source, continuing with P2P sync")
+}
+
+// probeBootstrapTips fetches every source's tip concurrently, timing each request
+func probeBootstrapTips(ctx context.Context, urls []string) []bootstrapTip {
+	tips := make([]bootstrapTip, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Go(func() {
+			start := time.Now()
+			hash, err := FetchLatestBlock(ctx, url)
+			tips[i] = bootstrapTip{url: url, hash: hash, latency: time.Since(start), err: err}
+		})
+	}
+	wg.Wait()
+	return tips
+}
+
+// bootstrapAgreement returns the sources reporting the most common tip, fastest first, if they reach quorum
+// A zero quorum requires a majority of the sources that answered. Equally common tips go to the fastest source.
+func bootstrapAgreement(tips []bootstrapTip, quorum int) ([]bootstrapTip, bool) {
+	answered := slices.DeleteFunc(slices.Clone(tips), func(tip bootstrapTip) bool { return tip.err != nil })
+	if len(answered) == 0 {
+		return nil, false
+	}
+	if quorum == 0 {
+		quorum = len(answered)/2 + 1
+	}
+
+	slices.SortStableFunc(answered, func(a, b bootstrapTip) int { return cmp.Compare(a.latency, b.latency) })
+	var best []bootstrapTip
+	for _, tip := range answered {
+		var group []bootstrapTip
+		for _, other := range answered {
+			if other.hash == tip.hash {
+				group = append(group, other)
+			}
+		}
+		if len(group) > len(best) {
+			best = group
+		}
+	}
+	return best, len(best) >= quorum
+}
+
+// countAnswered counts the sources whose tip probe succeeded
+func countAnswered(tips []bootstrapTip) int {
+	n := 0
+	for _, tip := range tips {
+		if tip.err == nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,132 @@
+package chaintracks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapAgreement(t *testing.T) {
+	a, b := chainhash.Hash{0xa}, chainhash.Hash{0xb}
+	tip := func(url string, hash chainhash.Hash, latency time.Duration) bootstrapTip {
+		return bootstrapTip{url: url, hash: hash, latency: latency}
+	}
+	down := bootstrapTip{url: "down", err: errSourceDown}
+
+	tests := []struct {
+		name            string
+		tips            []bootstrapTip
+		quorum          int
+		expectedOK      bool
+		expectedSources []string
+	}{
+		{
+			name:            "SingleSource",
+			tips:            []bootstrapTip{tip("one", a, time.Millisecond)},
+			expectedOK:      true,
+			expectedSources: []string{"one"},
+		},
+		{
+			name:            "MajorityFastestFirst",
+			tips:            []bootstrapTip{tip("slow", a, 3*time.Millisecond), tip("odd", b, time.Millisecond), tip("fast", a, 2*time.Millisecond)},
+			expectedOK:      true,
+			expectedSources: []string{"fast", "slow"},
+		},
+		{
+			name:            "UnreachableSourcesSkipped",
+			tips:            []bootstrapTip{down, tip("up", a, time.Millisecond)},
+			expectedOK:      true,
+			expectedSources: []string{"up"},
+		},
+		{
+			name: "NoMajority",
+			tips: []bootstrapTip{tip("one", a, time.Millisecond), tip("two", b, time.Millisecond)},
+		},
+		{
+			name:   "ExplicitQuorumNotMet",
+			tips:   []bootstrapTip{down, tip("up", a, time.Millisecond)},
+			quorum: 2,
+		},
+		{
+			name: "NoneAnswered",
+			tips: []bootstrapTip{down},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, ok := bootstrapAgreement(tt.tips, tt.quorum)
+			require.Equal(t, tt.expectedOK, ok)
+			if !ok {
+				return
+			}
+			urls := make([]string, len(sources))
+			for i, source := range sources {
+				urls[i] = source.url
+			}
+			assert.Equal(t, tt.expectedSources, urls)
+		})
+	}
+}
+
+// newBootstrapServer serves tip as /bestblockheader after delay, and the headers back to genesis unless broken
+func newBootstrapServer(t *testing.T, delay time.Duration, broken bool, chain ...*block.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var headerRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bestblockheader" {
+			time.Sleep(delay)
+			_, _ = w.Write(chain[len(chain)-1].Bytes())
+			return
+		}
+		headerRequests.Add(1)
+		if broken {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			_, _ = w.Write(chain[i].Bytes())
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &headerRequests
+}
+
+func TestBootstrapSync(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 2, 0)
+	fork := mineRegtestChain(t, genesis.Hash(), 2, 1)
+
+	t.Run("FailsOverFromFastestSource", func(t *testing.T) {
+		fast, fastRequests := newBootstrapServer(t, 0, true, genesis, chain[0], chain[1])
+		slow, slowRequests := newBootstrapServer(t, 50*time.Millisecond, false, genesis, chain[0], chain[1])
+		cm := newWireChainManager(t, genesis, nil)
+
+		cm.BootstrapSync(t.Context(), slow.URL, fast.URL)
+		assert.Equal(t, chain[1].Hash(), cm.GetTip(t.Context()).Hash)
+		assert.Equal(t, int32(1), fastRequests.Load())
+		assert.Equal(t, int32(1), slowRequests.Load())
+	})
+
+	t.Run("SkipsSyncWithoutQuorum", func(t *testing.T) {
+		one, oneRequests := newBootstrapServer(t, 0, false, genesis, chain[0], chain[1])
+		two, _ := newBootstrapServer(t, 0, false, genesis, fork[0], fork[1])
+		cm := newWireChainManager(t, genesis, nil)
+
+		cm.BootstrapSync(t.Context(), one.URL, two.URL)
+		assert.Equal(t, uint32(0), cm.GetHeight(t.Context()))
+		assert.Equal(t, int32(0), oneRequests.Load())
+
+		cm.SetBootstrapQuorum(1)
+		cm.BootstrapSync(t.Context(), one.URL, two.URL)
+		assert.Equal(t, uint32(2), cm.GetHeight(t.Context()), "a quorum of one takes the fastest source's tip")
+	})
+}
@@ -0,0 +1,123 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// BumpVerification is the outcome of verifying a BUMP against the main chain, see VerifyBump
+type BumpVerification struct {
+	BlockHeight uint32           `json:"blockHeight"`
+	MerkleRoot  string           `json:"merkleRoot"`          // Root computed from the path
+	BlockHash   string           `json:"blockHash,omitempty"` // Set when confirmed
+	Status      MerkleRootStatus `json:"status"`
+}
+
+// VerifyBump checks a BUMP (BRC-74 merkle path) against the main chain of any Chaintracks implementation
+// Every txid in the path must compute the merkle root of the block at the path's height for the result to be
+// CONFIRMED; the status is UNABLE_TO_VERIFY when no block is known at that height. A path without txids, or
+// one that cannot compute a root, fails with ErrInvalidBump.
+func VerifyBump(ctx context.Context, ct Chaintracks, bump *transaction.MerklePath) (*BumpVerification, error) {
+	root, consistent, err := bumpRoot(bump)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &BumpVerification{
+		BlockHeight: bump.BlockHeight,
+		MerkleRoot:  root.String(),
+		Status:      MerkleRootInvalid,
+	}
+	header, err := ct.GetHeaderByHeight(ctx, bump.BlockHeight)
+	switch {
+	case errors.Is(err, ErrHeaderNotFound):
+		verification.Status = MerkleRootUnableToVerify
+	case err != nil:
+		return nil, err
+	case consistent && header.MerkleRoot.IsEqual(root):
+		verification.Status = MerkleRootConfirmed
+		verification.BlockHash = header.Hash.String()
+	}
+	return verification, nil
+}
+
+// VerifyBump checks every txid in a BUMP against the merkle root of its block
+// Headers come from the client's cache when possible, so a wallet verifying many proofs
+// makes at most one request per block. Returns false if any txid computes a different root.
+func (cc *Client) VerifyBump(ctx context.Context, bump *transaction.MerklePath) (bool, error) {
+	root, consistent, err := bumpRoot(bump)
+	if err != nil {
+		return false, err
+	}
+
+	header, err := cc.GetHeaderByHeight(ctx, bump.BlockHeight)
+	if err != nil {
+		return false, err
+	}
+	return consistent && header.MerkleRoot.IsEqual(root), nil
+}
+
+// bumpRoot returns the merkle root computed from the first txid of bump, and whether every other txid
+// computes the same root
+func bumpRoot(bump *transaction.MerklePath) (*chainhash.Hash, bool, error) {
+	if bump == nil || len(bump.Path) == 0 {
+		return nil, false, fmt.Errorf("%w: empty merkle path", ErrInvalidBump)
+	}
+
+	txids := bumpTxids(bump)
+	if len(txids) == 0 {
+		return nil, false, fmt.Errorf("%w: no txids in merkle path", ErrInvalidBump)
+	}
+
+	var first *chainhash.Hash
+	for _, txid := range txids {
+		root, err := bump.ComputeRoot(txid)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %w", ErrInvalidBump, err)
+		}
+		if first == nil {
+			first = root
+		} else if !root.IsEqual(first) {
+			return first, false, nil
+		}
+	}
+	return first, true, nil
+}
+
+// bumpTxids returns the leaves flagged as txids, or the first leaf if none are flagged
+func bumpTxids(bump *transaction.MerklePath) []*chainhash.Hash {
+	var txids []*chainhash.Hash
+	var first *chainhash.Hash
+	for _, leaf := range bump.Path[0] {
+		if leaf.Hash == nil {
+			continue
+		}
+		if first == nil {
+			first = leaf.Hash
+		}
+		if leaf.Txid != nil && *leaf.Txid {
+			txids = append(txids, leaf.Hash)
+		}
+	}
+	if len(txids) == 0 && first != nil {
+		txids = append(txids, first)
+	}
+	return txids
+}
+
+// handleReorgEvent invalidates cached headers orphaned by a streamed reorg event and notifies OnReorg callbacks
+func (cc *Client) handleReorgEvent(payload string) {
+	var event struct {
+		Reorg *ReorgInfo `json:"reorg"`
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil || event.Reorg == nil {
+		return
+	}
+	cc.invalidateCacheAbove(event.Reorg.ForkHeight)
+	cc.callbacks.reorg(event.Reorg, cc.log())
+}
@@ -0,0 +1,161 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBump builds a two-leaf BUMP at height and returns it with its merkle root
+func testBump(height uint32) (*transaction.MerklePath, chainhash.Hash) {
+	isTxid := true
+	a, b := chainhash.Hash{0xa}, chainhash.Hash{0xb}
+	bump := transaction.NewMerklePath(height, [][]*transaction.PathElement{{
+		{Offset: 0, Hash: &a, Txid: &isTxid},
+		{Offset: 1, Hash: &b, Txid: &isTxid},
+	}})
+	return bump, *transaction.MerkleTreeParent(&a, &b)
+}
+
+// newHeaderServer serves a single header at height with the given merkle root and counts requests
+func newHeaderServer(t *testing.T, height uint32, root chainhash.Hash, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	header := &BlockHeader{Header: &block.Header{MerkleRoot: root}, Height: height}
+	body, err := json.Marshal(map[string]interface{}{"status": "success", "value": header})
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write(body)
+	}))
+}
+
+func TestClientVerifyBump(t *testing.T) {
+	bump, root := testBump(100)
+
+	tests := []struct {
+		name             string
+		serverRoot       chainhash.Hash
+		tipHeight        uint32
+		verifications    int
+		expectedValid    bool
+		expectedRequests int32
+	}{
+		{name: "ValidProofIsVerified", serverRoot: root, tipHeight: 200, verifications: 1, expectedValid: true, expectedRequests: 1},
+		{name: "WrongRootIsRejected", serverRoot: chainhash.Hash{0xff}, tipHeight: 200, verifications: 1, expectedValid: false, expectedRequests: 1},
+		{name: "BuriedHeaderIsCached", serverRoot: root, tipHeight: 200, verifications: 3, expectedValid: true, expectedRequests: 1},
+		{name: "HeaderNearTipIsNotCached", serverRoot: root, tipHeight: 102, verifications: 3, expectedValid: true, expectedRequests: 3},
+		{name: "UnknownTipDisablesCache", serverRoot: root, verifications: 2, expectedValid: true, expectedRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := newHeaderServer(t, 100, tt.serverRoot, &requests)
+			defer server.Close()
+
+			client := NewClient(server.URL)
+			if tt.tipHeight > 0 {
+				client.currentTip = &BlockHeader{Height: tt.tipHeight}
+			}
+
+			for range tt.verifications {
+				valid, err := client.VerifyBump(t.Context(), bump)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedValid, valid)
+			}
+			assert.Equal(t, tt.expectedRequests, requests.Load())
+		})
+	}
+}
+
+func TestClientVerifyBumpReorgInvalidatesCache(t *testing.T) {
+	bump, root := testBump(100)
+
+	var requests atomic.Int32
+	server := newHeaderServer(t, 100, root, &requests)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.currentTip = &BlockHeader{Height: 200}
+
+	_, err := client.VerifyBump(t.Context(), bump)
+	require.NoError(t, err)
+
+	client.handleReorgEvent(`{"type":"reorg","reorg":{"forkHeight":150}}`)
+	_, err = client.VerifyBump(t.Context(), bump)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "reorg above the cached height keeps it")
+
+	client.handleReorgEvent(`{"type":"reorg","reorg":{"forkHeight":99}}`)
+	_, err = client.VerifyBump(t.Context(), bump)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "reorg below the cached height drops it")
+}
+
+func TestClientVerifyBumpInvalidInput(t *testing.T) {
+	client := NewClient("http://localhost:0")
+
+	tests := []struct {
+		name string
+		bump *transaction.MerklePath
+	}{
+		{name: "NilBump"},
+		{name: "EmptyPath", bump: transaction.NewMerklePath(1, nil)},
+		{name: "NoLeaves", bump: transaction.NewMerklePath(1, [][]*transaction.PathElement{{}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := client.VerifyBump(t.Context(), tt.bump)
+			require.ErrorIs(t, err, ErrInvalidBump)
+			assert.False(t, valid)
+		})
+	}
+}
+
+func TestVerifyBump(t *testing.T) {
+	bump, root := testBump(1)
+	cm := newLinearChainManager(3)
+	cm.byHash[cm.byHeight[1]].MerkleRoot = root
+
+	t.Run("Confirmed", func(t *testing.T) {
+		verification, err := VerifyBump(t.Context(), cm, bump)
+		require.NoError(t, err)
+		assert.Equal(t, &BumpVerification{
+			BlockHeight: 1,
+			MerkleRoot:  root.String(),
+			BlockHash:   cm.byHeight[1].String(),
+			Status:      MerkleRootConfirmed,
+		}, verification)
+	})
+
+	t.Run("WrongBlock", func(t *testing.T) {
+		wrongHeight, _ := testBump(2)
+		verification, err := VerifyBump(t.Context(), cm, wrongHeight)
+		require.NoError(t, err)
+		assert.Equal(t, MerkleRootInvalid, verification.Status)
+		assert.Empty(t, verification.BlockHash)
+	})
+
+	t.Run("AboveTip", func(t *testing.T) {
+		aboveTip, _ := testBump(50)
+		verification, err := VerifyBump(t.Context(), cm, aboveTip)
+		require.NoError(t, err)
+		assert.Equal(t, MerkleRootUnableToVerify, verification.Status)
+	})
+
+	t.Run("InvalidBump", func(t *testing.T) {
+		_, err := VerifyBump(t.Context(), cm, transaction.NewMerklePath(1, nil))
+		require.ErrorIs(t, err, ErrInvalidBump)
+	})
+}
@@ -0,0 +1,183 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+)
+
+// maxPendingCallbacks caps the events queued behind slow callbacks; the oldest are dropped beyond it
+const maxPendingCallbacks = 1000
+
+// callback is a registered function with the ID its remove function looks it up by
+type callback[T any] struct {
+	id uint64
+	fn func(T)
+}
+
+// eventCallbacks runs the functions registered with OnTip, OnReorg and OnSyncComplete
+// Events are queued and delivered by a single goroutine, started on demand, so callbacks run one at a time in
+// the order the events occurred and never block the chain. A panicking callback is logged and the rest still run.
+type eventCallbacks struct {
+	mu       sync.Mutex
+	nextID   uint64
+	tips     []callback[*BlockHeader]
+	reorgs   []callback[*ReorgInfo]
+	syncs    []callback[SyncProgress]
+	pending  []func()
+	draining bool
+}
+
+// register adds fn to list and returns a function removing it again
+func register[T any](c *eventCallbacks, list *[]callback[T], fn func(T)) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	id := c.nextID
+	*list = append(*list, callback[T]{id: id, fn: fn})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			// Queued deliveries hold the old slice, so copy rather than delete in place
+			*list = slices.DeleteFunc(slices.Clone(*list), func(cb callback[T]) bool { return cb.id == id })
+		})
+	}
+}
+
+// notify queues a delivery of v to the callbacks currently in list
+func notify[T any](c *eventCallbacks, list *[]callback[T], name string, v T, logger Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	callbacks := *list
+	if len(callbacks) == 0 {
+		return
+	}
+	if len(c.pending) >= maxPendingCallbacks {
+		c.pending[0] = nil
+		c.pending = c.pending[1:]
+		logger.Warn("Dropping event for slow callbacks", "callback", name)
+	}
+	c.pending = append(c.pending, func() {
+		for _, cb := range callbacks {
+			runCallback(name, logger, func() { cb.fn(v) })
+		}
+	})
+
+	if !c.draining {
+		c.draining = true
+		go c.drain()
+	}
+}
+
+// drain runs queued deliveries in order until the queue is empty
+func (c *eventCallbacks) drain() {
+	for {
+		c.mu.Lock()
+		if len(c.pending) == 0 {
+			c.draining = false
+			c.mu.Unlock()
+			return
+		}
+		next := c.pending[0]
+		c.pending[0] = nil
+		c.pending = c.pending[1:]
+		c.mu.Unlock()
+
+		next()
+	}
+}
+
+// runCallback calls fn, logging rather than propagating a panic
+func runCallback(name string, logger Logger, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Callback panicked", "callback", name, "panic", r)
+		}
+	}()
+	fn()
+}
+
+// tip queues a new tip for OnTip callbacks
+func (c *eventCallbacks) tip(header *BlockHeader, logger Logger) {
+	notify(c, &c.tips, "tip", header, logger)
+}
+
+// reorg queues a reorg for OnReorg callbacks
+func (c *eventCallbacks) reorg(reorg *ReorgInfo, logger Logger) {
+	notify(c, &c.reorgs, "reorg", reorg, logger)
+}
+
+// syncProgress queues a finished sync for OnSyncComplete callbacks, ignoring progress of a running or failed one
+func (c *eventCallbacks) syncProgress(progress *SyncProgress, logger Logger) {
+	if progress == nil || progress.Active || progress.Error != "" {
+		return
+	}
+	notify(c, &c.syncs, "sync", *progress, logger)
+}
+
+// dispatch queues a chain event for the callbacks it concerns; a reorg is followed by its new tip
+func (c *eventCallbacks) dispatch(event *ChainEvent, logger Logger) {
+	switch event.Type {
+	case EventReorg:
+		c.reorg(event.Reorg, logger)
+		c.tip(event.Tip, logger)
+	case EventTipAdvanced:
+		c.tip(event.Tip, logger)
+	case EventSyncProgress:
+		c.syncProgress(event.Progress, logger)
+	}
+}
+
+// OnTip registers fn to be called with each new tip, including the tip after a reorg
+// Returns a function removing the callback. Callbacks run one at a time, in event order, on a goroutine of
+// their own, so they may call back into the ChainManager.
+func (cm *ChainManager) OnTip(fn func(*BlockHeader)) (remove func()) {
+	return register(&cm.callbacks, &cm.callbacks.tips, fn)
+}
+
+// OnReorg registers fn to be called with each reorg, before OnTip callbacks see the new tip
+// Returns a function removing the callback.
+func (cm *ChainManager) OnReorg(fn func(*ReorgInfo)) (remove func()) {
+	return register(&cm.callbacks, &cm.callbacks.reorgs, fn)
+}
+
+// OnSyncComplete registers fn to be called when a bulk sync from a remote node finishes successfully
+// Returns a function removing the callback.
+func (cm *ChainManager) OnSyncComplete(fn func(SyncProgress)) (remove func()) {
+	return register(&cm.callbacks, &cm.callbacks.syncs, fn)
+}
+
+// OnTip registers fn to be called with each tip received on the SSE stream, including the tip after a reorg
+// Returns a function removing the callback. Callbacks run one at a time, in stream order, and only while the
+// client is started.
+func (cc *Client) OnTip(fn func(*BlockHeader)) (remove func()) {
+	return register(&cc.callbacks, &cc.callbacks.tips, fn)
+}
+
+// OnReorg registers fn to be called with each reorg the server streams, before OnTip callbacks see the new tip
+// Returns a function removing the callback.
+func (cc *Client) OnReorg(fn func(*ReorgInfo)) (remove func()) {
+	return register(&cc.callbacks, &cc.callbacks.reorgs, fn)
+}
+
+// OnSyncComplete registers fn to be called when the server reports a bulk sync finished successfully
+// Returns a function removing the callback.
+func (cc *Client) OnSyncComplete(fn func(SyncProgress)) (remove func()) {
+	return register(&cc.callbacks, &cc.callbacks.syncs, fn)
+}
+
+// handleSyncProgressEvent passes the bulk sync in a streamed sync-progress event to OnSyncComplete callbacks
+func (cc *Client) handleSyncProgressEvent(payload string) {
+	var event struct {
+		Progress *SyncProgress `json:"progress"`
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return
+	}
+	cc.callbacks.syncProgress(event.Progress, cc.log())
+}
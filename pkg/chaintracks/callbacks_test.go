@@ -0,0 +1,120 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveCalls waits for count callback records and returns them
+func receiveCalls(t *testing.T, calls <-chan string, count int) []string {
+	t.Helper()
+	received := make([]string, 0, count)
+	for range count {
+		select {
+		case call := <-calls:
+			received = append(received, call)
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for callback", "received %v", received)
+		}
+	}
+	return received
+}
+
+func TestChainManagerCallbacks(t *testing.T) {
+	t.Run("OrderedTipsAndReorgs", func(t *testing.T) {
+		cm := newSubscriptionChainManager(t)
+		calls := make(chan string, 16)
+		cm.OnTip(func(tip *BlockHeader) { calls <- fmt.Sprintf("tip %d/%d", tip.Height, tip.Hash[0]) })
+		cm.OnReorg(func(reorg *ReorgInfo) { calls <- fmt.Sprintf("reorg %d", reorg.ForkHeight) })
+
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 1)}))
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 2), testHeader(4, 2)}))
+
+		assert.Equal(t, []string{"tip 3/1", "reorg 2", "tip 4/2"}, receiveCalls(t, calls, 3))
+	})
+
+	t.Run("PanicIsolated", func(t *testing.T) {
+		cm := newSubscriptionChainManager(t)
+		cm.logger = slog.New(slog.DiscardHandler)
+		calls := make(chan string, 16)
+		cm.OnTip(func(*BlockHeader) { panic("boom") })
+		cm.OnTip(func(tip *BlockHeader) { calls <- fmt.Sprintf("tip %d", tip.Height) })
+
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 1)}))
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(4, 1)}))
+
+		assert.Equal(t, []string{"tip 3", "tip 4"}, receiveCalls(t, calls, 2))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		cm := newSubscriptionChainManager(t)
+		removed := make(chan string, 16)
+		calls := make(chan string, 16)
+		remove := cm.OnTip(func(*BlockHeader) { removed <- "called" })
+		cm.OnTip(func(tip *BlockHeader) { calls <- fmt.Sprintf("tip %d", tip.Height) })
+		remove()
+		remove()
+
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 1)}))
+		receiveCalls(t, calls, 1)
+		assert.Empty(t, removed)
+	})
+
+	t.Run("SyncComplete", func(t *testing.T) {
+		cm := newSubscriptionChainManager(t)
+		calls := make(chan string, 16)
+		cm.OnSyncComplete(func(progress SyncProgress) {
+			calls <- fmt.Sprintf("sync %s %d", progress.Source, progress.CurrentHeight)
+		})
+
+		cm.beginSync(t.Context(), "failing", 10)
+		cm.endSync(t.Context(), errors.New("node unreachable"))
+		cm.beginSync(t.Context(), "node", 10)
+		cm.advanceSync(t.Context(), 5, nil)
+		cm.endSync(t.Context(), nil)
+
+		assert.Equal(t, []string{"sync node 2"}, receiveCalls(t, calls, 1), "only the successful sync completes")
+	})
+}
+
+func TestClientCallbacks(t *testing.T) {
+	tipJSON, err := json.Marshal(&BlockHeader{Header: &block.Header{}, Height: 7, Hash: chainhash.Hash{5}})
+	require.NoError(t, err)
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("event: sync-progress\ndata: {\"state\":\"synced\"}\n\n"))
+		_, _ = w.Write([]byte("event: reorg\nid: 7\ndata: {\"type\":\"reorg\",\"reorg\":{\"forkHeight\":5}}\n\n"))
+		_, _ = w.Write([]byte("event: tip\ndata: " + string(tipJSON) + "\n\n"))
+		_, _ = w.Write([]byte("event: sync-progress\ndata: {\"state\":\"synced\",\"progress\":{\"active\":false,\"source\":\"node\",\"currentHeight\":7}}\n\n"))
+		w.(http.Flusher).Flush() //nolint:forcetypeassert // httptest writers flush
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client := NewClient(server.URL)
+	calls := make(chan string, 16)
+	client.OnTip(func(tip *BlockHeader) { calls <- fmt.Sprintf("tip %d", tip.Height) })
+	client.OnReorg(func(reorg *ReorgInfo) { calls <- fmt.Sprintf("reorg %d", reorg.ForkHeight) })
+	client.OnSyncComplete(func(progress SyncProgress) {
+		calls <- fmt.Sprintf("sync %s %d", progress.Source, progress.CurrentHeight)
+	})
+
+	_, err = client.Start(t.Context())
+	require.NoError(t, err)
+	defer func() { _ = client.Stop() }()
+
+	assert.Equal(t, []string{"reorg 5", "tip 7", "sync node 7"}, receiveCalls(t, calls, 3))
+}
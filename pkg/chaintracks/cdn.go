@@ -0,0 +1,346 @@
+package chaintracks
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// metadataSuffix follows the network name in CDN and local metadata file names
+const metadataSuffix = "NetBlockHeaders.json"
+
+// progressSuffix follows the metadata file name in the record of an unfinished download
+const progressSuffix = ".progress"
+
+// defaultCDNDownloadWorkers is how many header files are downloaded at once by default
+const defaultCDNDownloadWorkers = 8
+
+// CDNDownloadConfig tunes CDN header downloads
+type CDNDownloadConfig struct {
+	Workers      int    // Header files downloaded concurrently (default 8)
+	SkipFileHash bool   // Accept files without checking their fileHash, for custom CDNs that publish none
+	PublicKey    string // Hex public key the metadata must be signed by (see SignCDNMetadata); empty accepts unsigned
+}
+
+// DownloadCDNHeaders downloads the header files for a network from a CDN into destPath
+// The metadata file is written last so an interrupted download is never mistaken for a complete one
+func DownloadCDNHeaders(ctx context.Context, cdnURL, network, destPath string) error {
+	return DownloadCDNHeadersWithConfig(ctx, cdnURL, network, destPath, CDNDownloadConfig{})
+}
+
+// DownloadCDNHeadersWithConfig is DownloadCDNHeaders with tuning
+func DownloadCDNHeadersWithConfig(ctx context.Context, cdnURL, network, destPath string, config CDNDownloadConfig) error {
+	return downloadCDN(ctx, strings.TrimSuffix(cdnURL, "/"), network+metadataSuffix, destPath, config)
+}
+
+// DownloadHeaders pre-seeds storagePath with the header files described by a CDN metadata file, so an
+// application embedding ChainManager can start with the full chain, e.g. in CI or on first run
+// bootstrapURL is the URL of a <network>NetBlockHeaders.json file; the header files are fetched from
+// alongside it. Storage already holding the same complete download is left untouched, so repeated runs
+// cost one request. Returns the network the headers belong to.
+func DownloadHeaders(ctx context.Context, bootstrapURL, storagePath string) (string, error) {
+	base, metadataName := path.Split(bootstrapURL)
+	network, ok := strings.CutSuffix(metadataName, metadataSuffix)
+	if !ok || network == "" {
+		return "", fmt.Errorf("%w: %s does not name a <network>%s file", ErrInvalidBootstrapURL, bootstrapURL, metadataSuffix)
+	}
+	return network, downloadCDN(ctx, strings.TrimSuffix(base, "/"), metadataName, storagePath, CDNDownloadConfig{})
+}
+
+// downloadCDN downloads metadataName and the header files it lists from baseURL into destPath
+// Files are fetched by a bounded pool of workers; the first failure cancels the rest. Each verified file is
+// recorded in a progress file next to the metadata, so a restarted download skips the files already done.
+// With a PublicKey the metadata signature is checked before anything else and every file must match its fileHash.
+func downloadCDN(ctx context.Context, baseURL, metadataName, destPath string, config CDNDownloadConfig) error {
+	metadataBytes, err := fetchCDNFile(ctx, baseURL+"/"+metadataName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch CDN metadata: %w", err)
+	}
+
+	if config.PublicKey != "" {
+		sig, err := fetchCDNFile(ctx, baseURL+"/"+metadataName+signatureSuffix)
+		if err != nil {
+			return fmt.Errorf("failed to fetch CDN metadata signature: %w", err)
+		}
+		if err := verifyCDNMetadataSignature(metadataBytes, sig, config.PublicKey); err != nil {
+			return err
+		}
+		config.SkipFileHash = false
+	}
+
+	var metadata CDNMetadata
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return fmt.Errorf("failed to parse CDN metadata: %w", err)
+	}
+
+	if cdnDownloadComplete(destPath, metadataName, metadataBytes, &metadata, config) {
+		getDefaultLogger().Info("CDN headers already present", "path", destPath, "files", len(metadata.Files))
+		return nil
+	}
+
+	if err := os.MkdirAll(destPath, 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	progress := loadCDNProgress(filepath.Join(destPath, metadataName+progressSuffix))
+	pending := make([]CDNFileEntry, 0, len(metadata.Files))
+	for _, entry := range metadata.Files {
+		if !progress.done(destPath, entry, config) {
+			pending = append(pending, entry)
+		}
+	}
+	if skipped := len(metadata.Files) - len(pending); skipped > 0 {
+		getDefaultLogger().Info("Resuming CDN download", "done", skipped, "remaining", len(pending))
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = defaultCDNDownloadWorkers
+	}
+	getDefaultLogger().Info("Downloading CDN header files", "files", len(pending), "workers", workers, "url", baseURL)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	jobs := make(chan CDNFileEntry)
+	var wg sync.WaitGroup
+	for range min(workers, len(pending)) {
+		wg.Go(func() {
+			for entry := range jobs {
+				err := downloadCDNFile(ctx, baseURL, destPath, entry, config)
+				if err == nil {
+					err = progress.record(entry)
+				}
+				if err != nil {
+					cancel(err)
+					return
+				}
+			}
+		})
+	}
+feed:
+	for _, entry := range pending {
+		select {
+		case jobs <- entry:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(destPath, metadataName), metadataBytes, 0o600); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	if err := os.Remove(progress.path); err != nil && !os.IsNotExist(err) {
+		getDefaultLogger().Warn("Failed to remove CDN download progress", "path", progress.path, "error", err)
+	}
+
+	getDefaultLogger().Info("CDN header download complete", "files", len(metadata.Files))
+	return nil
+}
+
+// cdnProgress records the header files of an unfinished download that were fully downloaded and verified
+type cdnProgress struct {
+	mu       sync.Mutex
+	path     string
+	Verified []CDNFileEntry `json:"verified"`
+}
+
+// loadCDNProgress reads the progress of an earlier download, starting afresh if there is none or it is unreadable
+func loadCDNProgress(path string) *cdnProgress {
+	progress := &cdnProgress{path: path}
+	data, err := os.ReadFile(path) //nolint:gosec // Path within the storage directory
+	if err != nil {
+		return progress
+	}
+	if err := json.Unmarshal(data, progress); err != nil {
+		getDefaultLogger().Warn("Ignoring unreadable CDN download progress", "path", path, "error", err)
+		progress.Verified = nil
+	}
+	return progress
+}
+
+// done reports whether entry, unchanged on the CDN, was verified earlier and its file still verifies
+func (p *cdnProgress) done(destPath string, entry CDNFileEntry, config CDNDownloadConfig) bool {
+	return slices.Contains(p.Verified, entry) && localCDNFileValid(destPath, entry, config)
+}
+
+// record adds a verified entry and persists the progress
+func (p *cdnProgress) record(entry CDNFileEntry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.Verified = append(p.Verified, entry)
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode CDN download progress: %w", err)
+	}
+	if err := os.WriteFile(p.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write CDN download progress: %w", err)
+	}
+	if err := os.Rename(p.path+".tmp", p.path); err != nil {
+		return fmt.Errorf("failed to write CDN download progress: %w", err)
+	}
+	return nil
+}
+
+// downloadCDNFile fetches one header file, verifies it against its metadata entry and stores it
+// The file is written under a temporary name and renamed once complete.
+func downloadCDNFile(ctx context.Context, baseURL, destPath string, entry CDNFileEntry, config CDNDownloadConfig) error {
+	fileName := filepath.Base(entry.FileName)
+	data, err := fetchCDNFile(ctx, baseURL+"/"+fileName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", fileName, err)
+	}
+	if err := verifyCDNFile(fileName, data, entry, config); err != nil {
+		return err
+	}
+
+	target := filepath.Join(destPath, fileName)
+	if err := os.WriteFile(target+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+	if err := os.Rename(target+".tmp", target); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+	return nil
+}
+
+// verifyCDNFile checks a header file's size, its fileHash and that it ends where its metadata entry says
+// PrevHash names the parent of the last header, as in metadata written by ChainManager. Entries without
+// PrevHash or LastHash skip that part of the check; a missing fileHash is an error unless SkipFileHash is set.
+func verifyCDNFile(fileName string, data []byte, entry CDNFileEntry, config CDNDownloadConfig) error {
+	if len(data) != entry.Count*headerSize {
+		return fmt.Errorf("%w: %s has %d bytes, expected %d", ErrInvalidFileSize, fileName, len(data), entry.Count*headerSize)
+	}
+	if !config.SkipFileHash {
+		if entry.FileHash == "" {
+			return fmt.Errorf("%w: %s", ErrMissingFileHash, fileName)
+		}
+		if err := verifyFileHash(fileName, data, entry.FileHash); err != nil {
+			return err
+		}
+	}
+	if entry.Count == 0 {
+		return nil
+	}
+
+	var zero chainhash.Hash
+	last, err := block.NewHeaderFromBytes(data[len(data)-headerSize:])
+	if err != nil {
+		return fmt.Errorf("failed to parse last header of %s: %w", fileName, err)
+	}
+	if entry.PrevHash != zero && last.PrevHash != entry.PrevHash {
+		return fmt.Errorf("%w: %s does not end after %s", ErrBrokenChain, fileName, entry.PrevHash)
+	}
+	if hash := last.Hash(); entry.LastHash != zero && hash != entry.LastHash {
+		return fmt.Errorf("%w: %s ends at %s, expected %s", ErrBrokenChain, fileName, hash, entry.LastHash)
+	}
+	return nil
+}
+
+// fileHash returns the fileHash of header file data: its SHA-256 digest, base64 encoded
+func fileHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// verifyFileHash checks header file data against a fileHash from metadata
+func verifyFileHash(fileName string, data []byte, want string) error {
+	if got := fileHash(data); got != want {
+		return fmt.Errorf("%w: %s hashes to %s, expected %s", ErrFileHashMismatch, fileName, got, want)
+	}
+	return nil
+}
+
+// localCDNFileValid reports whether destPath holds entry's file and it passes verifyCDNFile
+func localCDNFileValid(destPath string, entry CDNFileEntry, config CDNDownloadConfig) bool {
+	fileName := filepath.Base(entry.FileName)
+	data, err := os.ReadFile(filepath.Join(destPath, fileName)) //nolint:gosec // Path within the storage directory
+	return err == nil && verifyCDNFile(fileName, data, entry, config) == nil
+}
+
+// cdnDownloadComplete reports whether destPath already holds this metadata and every file it lists verifies
+func cdnDownloadComplete(destPath, metadataName string, metadataBytes []byte, metadata *CDNMetadata, config CDNDownloadConfig) bool {
+	local, err := os.ReadFile(filepath.Join(destPath, metadataName)) //nolint:gosec // Path within the storage directory
+	if err != nil || !bytes.Equal(local, metadataBytes) {
+		return false
+	}
+	for _, entry := range metadata.Files {
+		if !localCDNFileValid(destPath, entry, config) {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyHeaderFiles checks the header files in storagePath against the fileHash recorded for each in the
+// network's local metadata, returning ErrFileHashMismatch for the first corrupted file
+// Files without a recorded hash, such as the one holding the tip, are not checked. Missing metadata is not an error.
+func VerifyHeaderFiles(storagePath, network string) error {
+	metadata, err := parseMetadata(filepath.Join(storagePath, network+metadataSuffix))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range metadata.Files {
+		if entry.FileHash == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(storagePath, filepath.Base(entry.FileName))) //nolint:gosec // Path within the storage directory
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.FileName, err)
+		}
+		if err := verifyFileHash(entry.FileName, data, entry.FileHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchCDNFile downloads a single file from the CDN
+func fetchCDNFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrServerRequestFailed, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,289 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCDNServer serves a metadata file describing one file with count headers, the given hash and payload
+func newCDNServer(t *testing.T, count int, hash string, payload []byte) *httptest.Server {
+	t.Helper()
+
+	metadata, err := json.Marshal(CDNMetadata{
+		JSONFilename:   "testNetBlockHeaders.json",
+		HeadersPerFile: 100000,
+		Files: []CDNFileEntry{
+			{Chain: "test", Count: count, FileHash: hash, FileName: "testNet_0.headers"},
+		},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/testNetBlockHeaders.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(metadata)
+	})
+	mux.HandleFunc("/testNet_0.headers", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(payload)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestDownloadCDNHeaders(t *testing.T) {
+	good := fileHash(make([]byte, 160))
+
+	tests := []struct {
+		name          string
+		count         int
+		hash          string
+		payload       []byte
+		config        CDNDownloadConfig
+		expectedError error
+	}{
+		{
+			name:    "DownloadsMetadataAndFiles",
+			count:   2,
+			hash:    good,
+			payload: make([]byte, 160),
+		},
+		{
+			name:          "RejectsFileWithWrongSize",
+			count:         2,
+			hash:          good,
+			payload:       make([]byte, 100),
+			expectedError: ErrInvalidFileSize,
+		},
+		{
+			name:          "RejectsCorruptedFile",
+			count:         2,
+			hash:          good,
+			payload:       append(make([]byte, 159), 1),
+			expectedError: ErrFileHashMismatch,
+		},
+		{
+			name:          "RejectsMissingFileHash",
+			count:         2,
+			payload:       make([]byte, 160),
+			expectedError: ErrMissingFileHash,
+		},
+		{
+			name:    "SkipFileHashAcceptsUnhashedCDN",
+			count:   2,
+			payload: make([]byte, 160),
+			config:  CDNDownloadConfig{SkipFileHash: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCDNServer(t, tt.count, tt.hash, tt.payload)
+			defer server.Close()
+
+			dest := t.TempDir()
+			err := DownloadCDNHeadersWithConfig(t.Context(), server.URL+"/", "test", dest, tt.config)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				_, statErr := os.Stat(filepath.Join(dest, "testNetBlockHeaders.json"))
+				assert.True(t, os.IsNotExist(statErr), "metadata must not be written on failure")
+				return
+			}
+
+			require.NoError(t, err)
+			data, err := os.ReadFile(filepath.Join(dest, "testNet_0.headers")) //nolint:gosec // Test temp dir
+			require.NoError(t, err)
+			assert.Len(t, data, 160)
+			assert.FileExists(t, filepath.Join(dest, "testNetBlockHeaders.json"))
+		})
+	}
+}
+
+func TestDownloadCDNHeadersMissingMetadata(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := DownloadCDNHeaders(t.Context(), server.URL, "test", t.TempDir())
+	require.ErrorIs(t, err, ErrServerRequestFailed)
+}
+
+func TestDownloadHeaders(t *testing.T) {
+	var fileRequests int
+	server := newCDNServer(t, 2, fileHash(make([]byte, 160)), make([]byte, 160))
+	defer server.Close()
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filepath.Ext(r.URL.Path) == ".headers" {
+			fileRequests++
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer counting.Close()
+
+	dest := t.TempDir()
+	network, err := DownloadHeaders(t.Context(), counting.URL+"/testNetBlockHeaders.json", dest)
+	require.NoError(t, err)
+	assert.Equal(t, "test", network)
+	assert.FileExists(t, filepath.Join(dest, "testNet_0.headers"))
+	assert.FileExists(t, filepath.Join(dest, "testNetBlockHeaders.json"))
+
+	_, err = DownloadHeaders(t.Context(), counting.URL+"/testNetBlockHeaders.json", dest)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fileRequests, "a complete download is not fetched again")
+
+	require.NoError(t, os.Truncate(filepath.Join(dest, "testNet_0.headers"), 80))
+	_, err = DownloadHeaders(t.Context(), counting.URL+"/testNetBlockHeaders.json", dest)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fileRequests, "a truncated file is fetched again")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "testNet_0.headers"), append(make([]byte, 159), 1), 0o600))
+	_, err = DownloadHeaders(t.Context(), counting.URL+"/testNetBlockHeaders.json", dest)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fileRequests, "a corrupted file is fetched again")
+
+	_, err = DownloadHeaders(t.Context(), counting.URL+"/", dest)
+	require.ErrorIs(t, err, ErrInvalidBootstrapURL)
+}
+
+// newChainCDNServer serves a test network chain split into files of perFile linked headers, each request
+// taking delay; inflight records the most file requests served at once
+func newChainCDNServer(t *testing.T, files, perFile int, delay time.Duration, inflight *atomic.Int32) (*httptest.Server, *CDNMetadata) {
+	t.Helper()
+
+	metadata := &CDNMetadata{JSONFilename: "testNetBlockHeaders.json", HeadersPerFile: perFile}
+	payloads := map[string][]byte{}
+	var prev chainhash.Hash
+	for i := range files {
+		entry := CDNFileEntry{Chain: "test", Count: perFile, FileName: fmt.Sprintf("testNet_%d.headers", i)}
+		var data []byte
+		for j := range perFile {
+			header := &block.Header{PrevHash: prev, Nonce: uint32(i*perFile + j)} //nolint:gosec // Test data
+			data = append(data, header.Bytes()...)
+			entry.PrevHash, prev = prev, header.Hash()
+		}
+		entry.LastHash = prev
+		entry.FileHash = fileHash(data)
+		metadata.Files = append(metadata.Files, entry)
+		payloads["/"+entry.FileName] = data
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	require.NoError(t, err)
+
+	var current atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/testNetBlockHeaders.json" {
+			_, _ = w.Write(metadataBytes)
+			return
+		}
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			seen := inflight.Load()
+			if n <= seen || inflight.CompareAndSwap(seen, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		_, _ = w.Write(payloads[r.URL.Path])
+	}))
+	t.Cleanup(server.Close)
+	return server, metadata
+}
+
+func TestDownloadCDNHeadersWorkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+	}{
+		{name: "Sequential", workers: 1},
+		{name: "Parallel", workers: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inflight atomic.Int32
+			server, metadata := newChainCDNServer(t, 8, 3, 20*time.Millisecond, &inflight)
+
+			dest := t.TempDir()
+			require.NoError(t, DownloadCDNHeadersWithConfig(t.Context(), server.URL, "test", dest, CDNDownloadConfig{Workers: tt.workers}))
+			for _, entry := range metadata.Files {
+				assert.FileExists(t, filepath.Join(dest, entry.FileName))
+				assert.NoFileExists(t, filepath.Join(dest, entry.FileName+".tmp"))
+			}
+			assert.LessOrEqual(t, inflight.Load(), int32(tt.workers))
+			if tt.workers > 1 {
+				assert.Greater(t, inflight.Load(), int32(1), "files are fetched concurrently")
+			}
+		})
+	}
+}
+
+func TestDownloadCDNHeadersVerifiesFileEnds(t *testing.T) {
+	var inflight atomic.Int32
+	server, metadata := newChainCDNServer(t, 3, 2, 0, &inflight)
+	metadata.Files[1].LastHash = chainhash.Hash{0xee}
+	metadataBytes, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	tampered := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/testNetBlockHeaders.json" {
+			_, _ = w.Write(metadataBytes)
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer tampered.Close()
+
+	dest := t.TempDir()
+	err = DownloadCDNHeaders(t.Context(), tampered.URL, "test", dest)
+	require.ErrorIs(t, err, ErrBrokenChain)
+	assert.NoFileExists(t, filepath.Join(dest, "testNetBlockHeaders.json"))
+}
+
+func TestDownloadCDNHeadersResumes(t *testing.T) {
+	var inflight atomic.Int32
+	server, metadata := newChainCDNServer(t, 3, 2, 0, &inflight)
+	good := metadata.Files[1].LastHash
+	metadata.Files[1].LastHash = chainhash.Hash{0xee}
+	tamperedBytes, err := json.Marshal(metadata)
+	require.NoError(t, err)
+
+	var tamper atomic.Bool
+	var fetched sync.Map
+	resumable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/testNetBlockHeaders.json" && tamper.Load() {
+			_, _ = w.Write(tamperedBytes)
+			return
+		}
+		fetched.Store(r.URL.Path, true)
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer resumable.Close()
+
+	dest := t.TempDir()
+	progressPath := filepath.Join(dest, "testNetBlockHeaders.json.progress")
+	tamper.Store(true)
+	err = DownloadCDNHeadersWithConfig(t.Context(), resumable.URL, "test", dest, CDNDownloadConfig{Workers: 1})
+	require.ErrorIs(t, err, ErrBrokenChain)
+	assert.FileExists(t, progressPath, "the verified first file is recorded")
+
+	tamper.Store(false)
+	fetched.Clear()
+	metadata.Files[1].LastHash = good
+	require.NoError(t, DownloadCDNHeadersWithConfig(t.Context(), resumable.URL, "test", dest, CDNDownloadConfig{Workers: 1}))
+	_, refetched := fetched.Load("/testNet_0.headers")
+	assert.False(t, refetched, "files verified before the interruption are not fetched again")
+	_, resumed := fetched.Load("/testNet_1.headers")
+	assert.True(t, resumed)
+	assert.FileExists(t, filepath.Join(dest, "testNetBlockHeaders.json"))
+	assert.NoFileExists(t, progressPath)
+}
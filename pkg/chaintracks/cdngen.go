@@ -0,0 +1,124 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+// defaultHeadersPerFile is the number of headers in each CDN header file, as on the public CDN
+const defaultHeadersPerFile = 100000
+
+// GenerateCDN writes the current main chain to outputDir as CDN artifacts: <network>Net_<n>.headers files of
+// headersPerFile raw headers each (default 100000) and the <network>NetBlockHeaders.json metadata describing them
+// Serving outputDir over HTTP makes this node a CDN origin that other instances can bootstrap from with
+// CDN_URLS or DownloadCDNHeaders. The chain is snapshotted first, so headers arriving during generation are left
+// for the next run. Every file is written under a temporary name and renamed, and the metadata last, so a reader
+// never sees metadata describing files that are not fully written. ChainManager storage itself expects the
+// default file size; other sizes are for serving only.
+func (cm *ChainManager) GenerateCDN(ctx context.Context, outputDir string, headersPerFile int) error {
+	if headersPerFile <= 0 {
+		headersPerFile = defaultHeadersPerFile
+	}
+
+	chain, err := cm.snapshotMainChain()
+	if err != nil {
+		return err
+	}
+	if len(chain) == 0 {
+		return fmt.Errorf("%w: chain has no headers to export", ErrHeaderNotFound)
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil { //nolint:gosec // Served to other instances
+		return fmt.Errorf("failed to create CDN output directory: %w", err)
+	}
+
+	metadata := &CDNMetadata{
+		JSONFilename:   cm.network + metadataSuffix,
+		HeadersPerFile: headersPerFile,
+		Files:          make([]CDNFileEntry, 0, (len(chain)+headersPerFile-1)/headersPerFile),
+	}
+	for first := 0; first < len(chain); first += headersPerFile {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		headers := chain[first:min(first+headersPerFile, len(chain))]
+		data := make([]byte, 0, len(headers)*headerSize)
+		for _, header := range headers {
+			data = append(data, header.Bytes()...)
+		}
+
+		entry := cdnFileEntry(cm.network, len(metadata.Files), chain, headers, data)
+		if err := writeCDNArtifact(filepath.Join(outputDir, entry.FileName), data); err != nil {
+			return err
+		}
+		metadata.Files = append(metadata.Files, entry)
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal CDN metadata: %w", err)
+	}
+	if err := writeCDNArtifact(filepath.Join(outputDir, metadata.JSONFilename), data); err != nil {
+		return err
+	}
+
+	cm.log().Info("Generated CDN artifacts", "path", outputDir, "files", len(metadata.Files), "height", len(chain)-1)
+	return nil
+}
+
+// snapshotMainChain returns every main-chain header, reading those below the memory window from disk
+func (cm *ChainManager) snapshotMainChain() ([]*BlockHeader, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	chain := make([]*BlockHeader, 0, len(cm.byHeight))
+	for start := uint32(0); start < cm.windowFloor; start += defaultHeadersPerFile {
+		stored, err := cm.readStoredHeaders(start, min(defaultHeadersPerFile, cm.windowFloor-start))
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, stored...)
+	}
+	for _, hash := range cm.byHeight[cm.windowFloor:] {
+		chain = append(chain, cm.byHash[hash])
+	}
+	return chain, nil
+}
+
+// cdnFileEntry describes the index'th header file, holding headers from chain and encoded as data
+// PrevHash and PrevChainWork describe the parent of the last header, as in metadata written by ChainManager.
+func cdnFileEntry(network string, index int, chain, headers []*BlockHeader, data []byte) CDNFileEntry {
+	last := headers[len(headers)-1]
+	entry := CDNFileEntry{
+		Chain:         network,
+		Count:         len(headers),
+		FileHash:      fileHash(data),
+		FileName:      fmt.Sprintf("%sNet_%d.headers", network, index),
+		FirstHeight:   headers[0].Height,
+		LastChainWork: ChainWorkToHex(last.ChainWork),
+		LastHash:      last.Hash,
+		PrevChainWork: ChainWorkToHex(big.NewInt(0)),
+	}
+	if last.Height > 0 {
+		prev := chain[last.Height-1]
+		entry.PrevChainWork = ChainWorkToHex(prev.ChainWork)
+		entry.PrevHash = prev.Hash
+	}
+	return entry
+}
+
+// writeCDNArtifact writes data to path through a temporary file, so the file appears complete or not at all
+func writeCDNArtifact(path string, data []byte) error {
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil { //nolint:gosec // Served to other instances
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
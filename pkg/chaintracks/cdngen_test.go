@@ -0,0 +1,80 @@
+package chaintracks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCDN(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 250, 0)
+	cm := newWireChainManager(t, genesis, chain)
+
+	t.Run("SplitsChainIntoFiles", func(t *testing.T) {
+		out := t.TempDir()
+		require.NoError(t, cm.GenerateCDN(t.Context(), out, 100))
+
+		metadata, err := parseMetadata(filepath.Join(out, "stnNetBlockHeaders.json"))
+		require.NoError(t, err)
+		assert.Equal(t, "stnNetBlockHeaders.json", metadata.JSONFilename)
+		assert.Equal(t, 100, metadata.HeadersPerFile)
+		require.Len(t, metadata.Files, 3)
+
+		tests := []struct {
+			name        string
+			entry       CDNFileEntry
+			fileName    string
+			count       int
+			firstHeight uint32
+			last        *block.Header
+			prev        *block.Header
+		}{
+			{name: "First", entry: metadata.Files[0], fileName: "stnNet_0.headers", count: 100, firstHeight: 0, last: chain[98], prev: chain[97]},
+			{name: "Middle", entry: metadata.Files[1], fileName: "stnNet_1.headers", count: 100, firstHeight: 100, last: chain[198], prev: chain[197]},
+			{name: "Partial", entry: metadata.Files[2], fileName: "stnNet_2.headers", count: 51, firstHeight: 200, last: chain[249], prev: chain[248]},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.fileName, tt.entry.FileName)
+				assert.Equal(t, "stn", tt.entry.Chain)
+				assert.Equal(t, tt.count, tt.entry.Count)
+				assert.Equal(t, tt.firstHeight, tt.entry.FirstHeight)
+				assert.Equal(t, tt.last.Hash(), tt.entry.LastHash)
+				assert.Equal(t, tt.prev.Hash(), tt.entry.PrevHash)
+
+				data, err := os.ReadFile(filepath.Join(out, tt.fileName)) //nolint:gosec // Test path
+				require.NoError(t, err)
+				assert.Equal(t, fileHash(data), tt.entry.FileHash)
+			})
+		}
+
+		_, err = os.Stat(filepath.Join(out, "stnNet_0.headers.tmp"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("ServesAsBootstrapOrigin", func(t *testing.T) {
+		out := t.TempDir()
+		require.NoError(t, cm.GenerateCDN(t.Context(), out, 0))
+		server := httptest.NewServer(http.FileServer(http.Dir(out)))
+		t.Cleanup(server.Close)
+
+		dest := t.TempDir()
+		require.NoError(t, DownloadCDNHeaders(t.Context(), server.URL, "stn", dest))
+
+		replica, err := NewChainManager(t.Context(), "stn", dest, nil)
+		require.NoError(t, err)
+		assert.Equal(t, chain[249].Hash(), replica.GetTip(t.Context()).Hash)
+	})
+
+	t.Run("RejectsEmptyChain", func(t *testing.T) {
+		empty := &ChainManager{network: "stn"}
+		require.ErrorIs(t, empty.GenerateCDN(t.Context(), t.TempDir(), 0), ErrHeaderNotFound)
+	})
+}
@@ -0,0 +1,130 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+)
+
+// defaultCDNPublishInterval is how often a CDNPublisher regenerates the artifacts by default
+const defaultCDNPublishInterval = 10 * time.Minute
+
+// CDNPublishConfig configures a CDNPublisher
+type CDNPublishConfig struct {
+	OutputDir      string         // Directory the artifacts are generated in
+	HeadersPerFile int            // Headers per file (default 100000)
+	Interval       time.Duration  // Time between publishes (default 10m)
+	Bucket         *S3Bucket      // Optional object storage the artifacts are uploaded to
+	SigningKey     *ec.PrivateKey // Optional key the metadata is signed with, see SignCDNMetadata
+	Logger         Logger         // Defaults to the package default logger
+}
+
+// CDNPublisher keeps a bootstrap CDN current by regenerating the artifacts with GenerateCDN as the chain grows
+// and, when a Bucket is configured, uploading them. Only files whose content changed are uploaded, normally the
+// one holding the tip, and the metadata goes last so a reader never sees it describe a file not yet uploaded. With
+// a SigningKey the metadata is signed and the signature uploaded just before it; a consumer fetching between the
+// two sees a mismatch and retries.
+type CDNPublisher struct {
+	cm     *ChainManager
+	config CDNPublishConfig
+}
+
+// NewCDNPublisher creates a CDNPublisher for cm
+func NewCDNPublisher(cm *ChainManager, config CDNPublishConfig) (*CDNPublisher, error) {
+	if config.OutputDir == "" {
+		return nil, fmt.Errorf("%w: CDN publishing requires an output directory", ErrInvalidConfig)
+	}
+	if config.HeadersPerFile <= 0 {
+		config.HeadersPerFile = defaultHeadersPerFile
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultCDNPublishInterval
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &CDNPublisher{cm: cm, config: config}, nil
+}
+
+// Run publishes immediately and then every Interval until ctx is cancelled, skipping runs where the tip has not moved
+func (p *CDNPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	var published chainhash.Hash
+	for {
+		if tip := p.cm.GetTip(ctx); tip != nil && tip.Hash != published {
+			if err := p.Publish(ctx); err != nil {
+				if ctx.Err() == nil {
+					p.config.Logger.Warn("CDN publish failed", "error", err)
+				}
+			} else {
+				published = tip.Hash
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publish regenerates the artifacts and uploads the changed ones
+func (p *CDNPublisher) Publish(ctx context.Context) error {
+	if err := p.cm.GenerateCDN(ctx, p.config.OutputDir, p.config.HeadersPerFile); err != nil {
+		return err
+	}
+	if p.config.SigningKey != nil {
+		if err := SignCDNMetadata(p.config.OutputDir, p.cm.network, p.config.SigningKey); err != nil {
+			return err
+		}
+	}
+	if p.config.Bucket == nil {
+		return nil
+	}
+
+	metadataName := p.cm.network + metadataSuffix
+	metadata, err := parseMetadata(filepath.Join(p.config.OutputDir, metadataName))
+	if err != nil {
+		return err
+	}
+
+	uploaded := 0
+	for _, entry := range metadata.Files {
+		changed, err := p.upload(ctx, entry.FileName, "application/octet-stream")
+		if err != nil {
+			return err
+		}
+		if changed {
+			uploaded++
+		}
+	}
+	if p.config.SigningKey != nil {
+		if _, err := p.upload(ctx, metadataName+signatureSuffix, "text/plain"); err != nil {
+			return err
+		}
+	}
+	if _, err := p.upload(ctx, metadataName, "application/json"); err != nil {
+		return err
+	}
+
+	p.config.Logger.Info("Published CDN artifacts", "bucket", p.config.Bucket.config.Bucket, "uploaded", uploaded, "files", len(metadata.Files))
+	return nil
+}
+
+// upload puts one generated file into the bucket, reporting whether its content changed
+func (p *CDNPublisher) upload(ctx context.Context, name, contentType string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(p.config.OutputDir, name)) //nolint:gosec // Name comes from the generated metadata
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return p.config.Bucket.Put(ctx, name, data, contentType)
+}
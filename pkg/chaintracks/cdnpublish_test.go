@@ -0,0 +1,78 @@
+package chaintracks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCDNPublisherPublish(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 250, 0)
+	cm := newWireChainManager(t, genesis, chain[:200])
+
+	f, server := newFakeS3(t)
+	bucket, err := NewS3Bucket(S3Config{Endpoint: server.URL, Bucket: "headers", AccessKey: "key", SecretKey: "secret"})
+	require.NoError(t, err)
+	p, err := NewCDNPublisher(cm, CDNPublishConfig{OutputDir: t.TempDir(), HeadersPerFile: 100, Bucket: bucket})
+	require.NoError(t, err)
+
+	require.NoError(t, p.Publish(t.Context()))
+	assert.Equal(t, []string{
+		"/headers/stnNet_0.headers",
+		"/headers/stnNet_1.headers",
+		"/headers/stnNet_2.headers",
+		"/headers/stnNetBlockHeaders.json",
+	}, f.takePuts())
+
+	t.Run("SkipsUnchangedFiles", func(t *testing.T) {
+		require.NoError(t, p.Publish(t.Context()))
+		assert.Empty(t, f.takePuts())
+	})
+
+	t.Run("UploadsTipFileAndMetadata", func(t *testing.T) {
+		require.NoError(t, cm.connectHeaders(t.Context(), chain[200:], 0))
+		require.NoError(t, p.Publish(t.Context()))
+		assert.Equal(t, []string{"/headers/stnNet_2.headers", "/headers/stnNetBlockHeaders.json"}, f.takePuts())
+	})
+}
+
+func TestCDNPublisherSignsMetadata(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	cm := newWireChainManager(t, genesis, mineRegtestChain(t, genesis.Hash(), 10, 0))
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	f, server := newFakeS3(t)
+	bucket, err := NewS3Bucket(S3Config{Endpoint: server.URL, Bucket: "headers", AccessKey: "key", SecretKey: "secret"})
+	require.NoError(t, err)
+	out := t.TempDir()
+	p, err := NewCDNPublisher(cm, CDNPublishConfig{OutputDir: out, Bucket: bucket, SigningKey: key})
+	require.NoError(t, err)
+
+	require.NoError(t, p.Publish(t.Context()))
+	assert.Equal(t, []string{
+		"/headers/stnNet_0.headers",
+		"/headers/stnNetBlockHeaders.json.sig",
+		"/headers/stnNetBlockHeaders.json",
+	}, f.takePuts())
+
+	metadata, err := os.ReadFile(filepath.Join(out, "stnNetBlockHeaders.json")) //nolint:gosec // Test path
+	require.NoError(t, err)
+	require.NoError(t, verifyCDNMetadataSignature(metadata, f.objects["/headers/stnNetBlockHeaders.json.sig"], key.PubKey().ToDERHex()))
+}
+
+func TestNewCDNPublisherValidatesConfig(t *testing.T) {
+	_, err := NewCDNPublisher(&ChainManager{}, CDNPublishConfig{})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	p, err := NewCDNPublisher(&ChainManager{}, CDNPublishConfig{OutputDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, defaultHeadersPerFile, p.config.HeadersPerFile)
+	assert.Equal(t, defaultCDNPublishInterval, p.config.Interval)
+}
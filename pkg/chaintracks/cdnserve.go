@@ -0,0 +1,27 @@
+package chaintracks
+
+import "path/filepath"
+
+// ServedCDNMetadata returns the metadata of local storage listing only its complete header files, each with a
+// fileHash, so the storage directory can be served as a CDN origin for DownloadCDNHeaders
+// The files from the first incomplete or unhashed one on are left out: the file holding the tip keeps growing,
+// so its entry would stop matching the file during a download. An instance bootstrapping from this one syncs
+// the headers after the last complete file as usual.
+func (cm *ChainManager) ServedCDNMetadata() (*CDNMetadata, error) {
+	metadata, err := parseMetadata(filepath.Join(cm.localStoragePath, cm.network+metadataSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	complete := metadata.Files[:0]
+	for _, entry := range metadata.Files {
+		if entry.Count != metadata.HeadersPerFile || entry.FileHash == "" {
+			break
+		}
+		complete = append(complete, entry)
+	}
+	metadata.Files = complete
+	metadata.JSONFilename = cm.network + metadataSuffix
+	metadata.EventSeq = 0
+	return metadata, nil
+}
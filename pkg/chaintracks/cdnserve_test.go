@@ -0,0 +1,58 @@
+package chaintracks
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainManagerServedCDNMetadata(t *testing.T) {
+	entry := func(name string, count int, hash string) CDNFileEntry {
+		return CDNFileEntry{Chain: "main", FileName: name, Count: count, FileHash: hash}
+	}
+
+	tests := []struct {
+		name     string
+		files    []CDNFileEntry
+		expected []string
+	}{
+		{name: "NoFiles", expected: []string{}},
+		{name: "OnlyTipFile", files: []CDNFileEntry{entry("mainNet_0.headers", 5, "")}, expected: []string{}},
+		{
+			name:     "CompleteFilesBeforeTip",
+			files:    []CDNFileEntry{entry("mainNet_0.headers", 10, "a"), entry("mainNet_1.headers", 10, "b"), entry("mainNet_2.headers", 3, "")},
+			expected: []string{"mainNet_0.headers", "mainNet_1.headers"},
+		},
+		{
+			name:     "StopsAtUnhashedFile",
+			files:    []CDNFileEntry{entry("mainNet_0.headers", 10, "a"), entry("mainNet_1.headers", 10, ""), entry("mainNet_2.headers", 10, "c")},
+			expected: []string{"mainNet_0.headers"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &ChainManager{localStoragePath: t.TempDir(), network: "main"}
+			require.NoError(t, cm.writeLocalMetadata(&CDNMetadata{JSONFilename: "checkpoint.json", HeadersPerFile: 10, Files: tt.files, EventSeq: 42}))
+
+			metadata, err := cm.ServedCDNMetadata()
+			require.NoError(t, err)
+			assert.Equal(t, "mainNetBlockHeaders.json", metadata.JSONFilename)
+			assert.Zero(t, metadata.EventSeq, "the event sequence is local state")
+
+			names := []string{}
+			for _, file := range metadata.Files {
+				names = append(names, file.FileName)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	t.Run("NoMetadata", func(t *testing.T) {
+		cm := &ChainManager{localStoragePath: t.TempDir(), network: "main"}
+		_, err := cm.ServedCDNMetadata()
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
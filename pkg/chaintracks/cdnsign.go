@@ -0,0 +1,60 @@
+package chaintracks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+)
+
+// signatureSuffix follows the metadata file name in the name of its detached signature
+const signatureSuffix = ".sig"
+
+// SignCDNMetadata signs the <network>NetBlockHeaders.json in dir with key, writing the hex DER ECDSA signature
+// over its SHA-256 digest next to it as <network>NetBlockHeaders.json.sig
+// The metadata carries every file's fileHash, so consumers configured with the matching public key
+// (CDNDownloadConfig.PublicKey) accept only header files this key's holder published.
+func SignCDNMetadata(dir, network string, key *ec.PrivateKey) error {
+	metadataPath := filepath.Join(dir, network+metadataSuffix)
+	data, err := os.ReadFile(metadataPath) //nolint:gosec // Path within the output directory
+	if err != nil {
+		return fmt.Errorf("failed to read CDN metadata: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	sig, err := key.Sign(digest[:])
+	if err != nil {
+		return fmt.Errorf("failed to sign CDN metadata: %w", err)
+	}
+	der, err := sig.ToDER()
+	if err != nil {
+		return fmt.Errorf("failed to encode CDN metadata signature: %w", err)
+	}
+	return writeCDNArtifact(metadataPath+signatureSuffix, []byte(hex.EncodeToString(der)))
+}
+
+// verifyCDNMetadataSignature checks that sigHex is publicKey's signature over metadata
+func verifyCDNMetadataSignature(metadata, sigHex []byte, publicKey string) error {
+	pub, err := ec.PublicKeyFromString(publicKey)
+	if err != nil {
+		return fmt.Errorf("%w: public key: %w", ErrInvalidConfig, err)
+	}
+	der, err := hex.DecodeString(strings.TrimSpace(string(sigHex)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMetadataSignature, err)
+	}
+	sig, err := ec.FromDER(der)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMetadataSignature, err)
+	}
+
+	digest := sha256.Sum256(metadata)
+	if !sig.Verify(digest[:], pub) {
+		return fmt.Errorf("%w: not signed by %s", ErrInvalidMetadataSignature, publicKey)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package chaintracks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedCDNMetadata(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 10, 0)
+	cm := newWireChainManager(t, genesis, chain)
+
+	publisher, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	other, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	publicKey := publisher.PubKey().ToDERHex()
+
+	tests := []struct {
+		name          string
+		signer        *ec.PrivateKey
+		tamper        func(t *testing.T, dir string)
+		publicKey     string
+		expectedError error
+	}{
+		{name: "AcceptsPublisherSignature", signer: publisher, publicKey: publicKey},
+		{name: "AcceptsUnsignedWithoutKey", publicKey: ""},
+		{name: "RejectsOtherSigner", signer: other, publicKey: publicKey, expectedError: ErrInvalidMetadataSignature},
+		{name: "RejectsMissingSignature", publicKey: publicKey, expectedError: ErrServerRequestFailed},
+		{
+			name:      "RejectsAlteredMetadata",
+			signer:    publisher,
+			publicKey: publicKey,
+			tamper: func(t *testing.T, dir string) {
+				path := filepath.Join(dir, "stnNetBlockHeaders.json")
+				data, err := os.ReadFile(path) //nolint:gosec // Test path
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, append(data, '\n'), 0o600))
+			},
+			expectedError: ErrInvalidMetadataSignature,
+		},
+		{name: "RejectsInvalidKey", signer: publisher, publicKey: "not-a-key", expectedError: ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := t.TempDir()
+			require.NoError(t, cm.GenerateCDN(t.Context(), out, 0))
+			if tt.signer != nil {
+				require.NoError(t, SignCDNMetadata(out, "stn", tt.signer))
+			}
+			if tt.tamper != nil {
+				tt.tamper(t, out)
+			}
+			server := httptest.NewServer(http.FileServer(http.Dir(out)))
+			t.Cleanup(server.Close)
+
+			dest := t.TempDir()
+			err := DownloadCDNHeadersWithConfig(t.Context(), server.URL, "stn", dest, CDNDownloadConfig{PublicKey: tt.publicKey})
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				_, statErr := os.Stat(filepath.Join(dest, "stnNet_0.headers"))
+				assert.True(t, os.IsNotExist(statErr))
+				return
+			}
+			require.NoError(t, err)
+			assert.FileExists(t, filepath.Join(dest, "stnNetBlockHeaders.json"))
+		})
+	}
+}
@@ -4,22 +4,43 @@ package chaintracks
 import (
 	"context"
 	"fmt"
-	"log"
+	"math/big"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
 	"github.com/bsv-blockchain/go-sdk/chainhash"
 )
 
+// maxHeadersBackwards caps the preallocation for GetHeadersBackwards
+const maxHeadersBackwards = 10000
+
 // ChainManager is the main orchestrator for chain management
 type ChainManager struct {
 	mu sync.RWMutex
 
 	byHeight []chainhash.Hash                // Main chain hashes indexed by height
 	byHash   map[chainhash.Hash]*BlockHeader // Hash → Header (all headers: main + orphans)
-	tip      *BlockHeader                    // Current chain tip
+
+	// Memory window, see SetMemoryWindow; main-chain headers below windowFloor are only on disk
+	memoryWindow  uint32
+	windowFloor   uint32
+	storedWork    [][32]byte                // Big-endian chainwork of each main-chain height below windowFloor
+	storedHeights map[chainhash.Hash]uint32 // Height of each main-chain hash below windowFloor
+	unwritten     map[uint32]int            // Start heights of applied branches not yet on disk, which a failed write keeps in memory
+
+	// Current chain tip, stored under mu but loaded without it so GetTip and GetHeight never wait on a sync
+	tip atomic.Pointer[BlockHeader]
+
+	// Blocks marked invalid by an operator, guarded by mu; adminMu serializes the operations changing them
+	adminMu     sync.Mutex
+	invalidated map[chainhash.Hash]struct{}
+
+	eventSeq     uint64        // Sequence number of the last tip or reorg event, persisted in metadata
+	eventHistory []*ChainEvent // Recent sequenced events for replay
 
 	localStoragePath string
 	network          string
@@ -27,12 +48,52 @@ type ChainManager struct {
 	// P2P fields
 	p2pClient p2p.Client        // P2P client for network communication
 	msgChan   chan *BlockHeader // Channel for broadcasting tip changes to consumers
+	peerBook  *PeerBook         // Peer history with trust tiers, loaded on Start
+	topics    topicTracker      // Per-version block topic statistics
+
+	// Typed event subscribers
+	subMu     sync.RWMutex
+	eventSubs map[chan *ChainEvent]struct{}
+	callbacks eventCallbacks // Registered with OnTip, OnReorg and OnSyncComplete
+
+	// Reorg history
+	reorgMu sync.RWMutex
+	reorgs  []ReorgRecord
+
+	// Network lag tracking
+	lagMu         sync.RWMutex
+	lagThreshold  uint32
+	quietPeriod   time.Duration
+	networkHeight uint32
+	lastBlockSeen time.Time
+	lagState      SyncState
+
+	// Block propagation latency
+	latency latencyRecorder
+
+	// Counters persisted across restarts
+	metrics metricsTracker
+
+	// Bulk sync progress
+	syncs syncTracker
+
+	bootstrapQuorum int        // Sources that must agree on the tip, 0 for a majority of those answering
+	syncConfig      SyncConfig // Catch-up sync tuning, zero values for the defaults
+
+	logger Logger
 }
 
 // NewChainManager creates a new ChainManager and restores from local files if present
 // If p2pClient is provided, it will use that instead of creating its own
-// If bootstrapURL is provided, it will sync from a remote teranode before returning
+// If bootstrap URLs are provided, it syncs from the remote teranodes before returning, see BootstrapSync
 func NewChainManager(ctx context.Context, network, localStoragePath string, p2pClient p2p.Client, bootstrapURL ...string) (*ChainManager, error) {
+	return NewWindowedChainManager(ctx, network, localStoragePath, p2pClient, 0, bootstrapURL...)
+}
+
+// NewWindowedChainManager is NewChainManager with a memory window, see SetMemoryWindow
+// The window applies while the local files are restored, so headers below it leave memory as each file is
+// linked and startup never holds the whole chain.
+func NewWindowedChainManager(ctx context.Context, network, localStoragePath string, p2pClient p2p.Client, memoryWindow uint32, bootstrapURL ...string) (*ChainManager, error) {
 	// Default to ~/.chaintracks if no path provided
 	if localStoragePath == "" {
 		homeDir, err := os.UserHomeDir()
@@ -55,44 +116,56 @@ func NewChainManager(ctx context.Context, network, localStoragePath string, p2pC
 		network:          network,
 		localStoragePath: localStoragePath,
 		p2pClient:        p2pClient,
+		memoryWindow:     windowSize(memoryWindow),
+		logger:           getDefaultLogger(),
 	}
 
-	log.Printf("ChainManager initializing: network=%s, path=%s", network, localStoragePath)
+	cm.log().Info("ChainManager initializing", "network", network, "path", localStoragePath)
 
 	// Auto-restore from local files if they exist
+	loadStart := time.Now()
 	if err := cm.loadFromLocalFiles(ctx); err != nil {
 		return nil, fmt.Errorf("failed to load checkpoint files: %w", err)
 	}
+	loadDuration := time.Since(loadStart)
+	cm.log().Info("Loaded checkpoint files", "height", cm.GetHeight(ctx), "duration", loadDuration)
 
-	// Run bootstrap sync if configured (optional parameter)
-	if len(bootstrapURL) > 0 && bootstrapURL[0] != "" {
-		cm.runBootstrapSync(ctx, bootstrapURL[0])
+	if err := cm.loadReorgLog(); err != nil {
+		return nil, fmt.Errorf("failed to load reorg history: %w", err)
 	}
 
-	return cm, nil
-}
-
-// runBootstrapSync performs initial sync from a bootstrap node
-func (cm *ChainManager) runBootstrapSync(ctx context.Context, url string) {
-	log.Printf("Bootstrap URL configured: %s", url)
-
-	// Get the latest block hash from the bootstrap node
-	remoteTipHash, err := FetchLatestBlock(ctx, url)
-	if err != nil {
-		log.Printf("Failed to get bootstrap node tip: %v (will continue with P2P sync)", err)
-		return
+	if err := cm.loadInvalidated(); err != nil {
+		return nil, fmt.Errorf("failed to load invalidated blocks: %w", err)
+	}
+	if err := cm.applyLoadedInvalidated(ctx); err != nil {
+		return nil, fmt.Errorf("failed to apply invalidated blocks: %w", err)
 	}
 
-	log.Printf("Bootstrap node tip: %s", remoteTipHash.String())
-	if err := cm.SyncFromRemoteTip(ctx, remoteTipHash, url); err != nil {
-		log.Printf("Bootstrap sync failed: %v (will continue with P2P sync)", err)
-		return
+	if err := cm.seedRegtest(ctx); err != nil {
+		return nil, fmt.Errorf("failed to seed regtest genesis: %w", err)
 	}
 
-	// Log updated chain state after bootstrap
-	if tip := cm.GetTip(ctx); tip != nil {
-		log.Printf("Chain tip after bootstrap: %s at height %d", tip.Header.Hash().String(), tip.Height)
+	cm.metrics.load(cm.metricsPath(), time.Now(), cm.log())
+	cm.metrics.setLoadDuration(loadDuration)
+
+	// Run bootstrap sync if configured (optional parameter)
+	cm.BootstrapSync(ctx, bootstrapURL...)
+
+	return cm, nil
+}
+
+// SetLogger replaces the chain manager's logger; call it before Start
+// Construction logs go to the package default logger, see SetDefaultLogger
+func (cm *ChainManager) SetLogger(logger Logger) {
+	cm.logger = logger
+}
+
+// log returns the configured logger, falling back to the package default
+func (cm *ChainManager) log() Logger {
+	if cm.logger == nil {
+		return getDefaultLogger()
 	}
+	return cm.logger
 }
 
 // GetHeaderByHeight retrieves a header by height
@@ -107,8 +180,7 @@ func (cm *ChainManager) GetHeaderByHeight(_ context.Context, height uint32) (*Bl
 		return nil, ErrHeaderNotFound
 	}
 
-	hash := cm.byHeight[height]
-	header, ok := cm.byHash[hash]
+	header, ok := cm.mainHeaderAt(height)
 	if !ok {
 		return nil, ErrHeaderNotFound
 	}
@@ -121,7 +193,7 @@ func (cm *ChainManager) GetHeaderByHash(_ context.Context, hash *chainhash.Hash)
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
-	header, ok := cm.byHash[*hash]
+	header, ok := cm.headerByHash(*hash)
 	if !ok {
 		return nil, ErrHeaderNotFound
 	}
@@ -129,21 +201,43 @@ func (cm *ChainManager) GetHeaderByHash(_ context.Context, hash *chainhash.Hash)
 	return header, nil
 }
 
-// GetTip returns the current chain tip
-func (cm *ChainManager) GetTip(_ context.Context) *BlockHeader {
+// GetHeadersBackwards returns up to count headers starting at fromHash and walking back through
+// its ancestors, newest first. The walk follows the branch of fromHash, so it also works for orphans,
+// and stops early at genesis or at the first ancestor that is not known.
+func (cm *ChainManager) GetHeadersBackwards(_ context.Context, fromHash *chainhash.Hash, count uint32) ([]*BlockHeader, error) {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
-	return cm.tip
+
+	header, ok := cm.headerByHash(*fromHash)
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+
+	headers := make([]*BlockHeader, 0, min(count, maxHeadersBackwards))
+	for uint32(len(headers)) < count { //nolint:gosec // Bounded by count
+		headers = append(headers, header)
+		if header.Height == 0 || header.Header == nil {
+			break
+		}
+		if header, ok = cm.parentHeader(header); !ok {
+			break
+		}
+	}
+
+	return headers, nil
+}
+
+// GetTip returns the current chain tip
+func (cm *ChainManager) GetTip(_ context.Context) *BlockHeader {
+	return cm.tip.Load()
 }
 
 // GetHeight returns the current chain height
 func (cm *ChainManager) GetHeight(_ context.Context) uint32 {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	if cm.tip == nil {
-		return 0
+	if tip := cm.tip.Load(); tip != nil {
+		return tip.Height
 	}
-	return cm.tip.Height
+	return 0
 }
 
 // AddHeader adds a header to byHash for lookups without modifying the chain tip
@@ -156,6 +250,83 @@ func (cm *ChainManager) AddHeader(header *BlockHeader) error {
 	return nil
 }
 
+// AddHeaders links a batch of consecutive headers onto a known parent, taking the lock once for the whole batch
+// Headers are ordered oldest first with Header and Hash set, and each must link to the one before it; Height
+// and ChainWork are filled in from the parent once the whole batch is found to link. Headers already on the main chain are skipped. A batch ending
+// with more work than the tip becomes the main chain with a single tip update, otherwise it is kept as an
+// alternate chain. Returns ErrHeaderNotFound when the parent of the first header is unknown.
+func (cm *ChainManager) AddHeaders(ctx context.Context, headers []*BlockHeader) error {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	cm.mu.Lock()
+	parent, ok := cm.headerByHash(headers[0].Header.PrevHash)
+	if !ok {
+		cm.mu.Unlock()
+		return fmt.Errorf("%w: parent of %s", ErrHeaderNotFound, headers[0].Hash)
+	}
+
+	// The whole batch must link before any header is filled in, so a broken batch leaves the caller's headers untouched
+	for i := 1; i < len(headers); i++ {
+		if headers[i].Header.PrevHash != headers[i-1].Hash {
+			cm.mu.Unlock()
+			return fmt.Errorf("%w: header %d does not link to its predecessor", ErrBrokenChain, parent.Height+uint32(i)+1) //nolint:gosec // Batch index is small
+		}
+	}
+
+	prev := parent
+	for _, header := range headers {
+		header.Height = prev.Height + 1
+		header.ChainWork = new(big.Int).Add(prev.ChainWork, CalculateWork(header.Header.Bits))
+		prev = header
+	}
+
+	branch := headers
+	for len(branch) > 0 && cm.isMainChain(branch[0]) {
+		parent, branch = branch[0], branch[1:]
+	}
+	if len(branch) == 0 {
+		cm.mu.Unlock()
+		return nil
+	}
+
+	if tip := cm.tip.Load(); tip != nil && prev.ChainWork.Cmp(tip.ChainWork) <= 0 {
+		for _, header := range branch {
+			cm.byHash[header.Hash] = header
+		}
+		cm.mu.Unlock()
+		cm.log().Info("Headers added as alternate chain", "height", prev.Height, "hash", prev.Hash)
+		return nil
+	}
+
+	// The branch must start on the main chain, so pull in any known alternate-chain ancestors
+	for !cm.isMainChain(parent) {
+		branch = append([]*BlockHeader{parent}, branch...)
+		var err error
+		if parent, err = cm.parentOf(parent); err != nil {
+			cm.mu.Unlock()
+			return fmt.Errorf("failed to find fork point: %w", err)
+		}
+	}
+
+	if err := cm.checkNotInvalidated(branch); err != nil {
+		cm.mu.Unlock()
+		return err
+	}
+
+	update := cm.applyBranch(ctx, branch)
+	cm.mu.Unlock()
+
+	cm.metrics.addHeaders(len(branch))
+	return cm.finishTipUpdate(ctx, update)
+}
+
+// GetStoragePath returns the directory holding the header files and persisted state
+func (cm *ChainManager) GetStoragePath() string {
+	return cm.localStoragePath
+}
+
 // GetNetwork returns the network name
 func (cm *ChainManager) GetNetwork(_ context.Context) (string, error) {
 	return cm.network, nil
@@ -163,13 +334,14 @@ func (cm *ChainManager) GetNetwork(_ context.Context) (string, error) {
 
 // pruneOrphans removes old orphaned headers (must be called with lock held)
 func (cm *ChainManager) pruneOrphans() {
-	if cm.tip == nil {
+	tip := cm.tip.Load()
+	if tip == nil {
 		return
 	}
 
 	pruneHeight := uint32(0)
-	if cm.tip.Height > 100 {
-		pruneHeight = cm.tip.Height - 100
+	if tip.Height > 100 {
+		pruneHeight = tip.Height - 100
 	}
 
 	// Remove headers that are not in byHeight (orphans) and too old
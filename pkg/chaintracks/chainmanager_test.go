@@ -1,6 +1,9 @@
 package chaintracks
 
 import (
+	"context"
+	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/bsv-blockchain/go-sdk/block"
@@ -9,6 +12,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// withTip sets cm's tip and returns cm
+func withTip(cm *ChainManager, tip *BlockHeader) *ChainManager {
+	cm.tip.Store(tip)
+	return cm
+}
+
 func TestChainManagerGetTip(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -18,9 +27,7 @@ func TestChainManagerGetTip(t *testing.T) {
 		{
 			name: "ReturnsNilWhenTipIsNil",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: nil,
-				}
+				return &ChainManager{}
 			},
 			expected: nil,
 		},
@@ -33,9 +40,7 @@ func TestChainManagerGetTip(t *testing.T) {
 					Height: 12345,
 					Hash:   hash,
 				}
-				return &ChainManager{
-					tip: header,
-				}
+				return withTip(&ChainManager{}, header)
 			},
 			expected: &BlockHeader{
 				Header: &block.Header{},
@@ -70,45 +75,37 @@ func TestChainManagerGetHeight(t *testing.T) {
 		{
 			name: "ReturnsZeroWhenTipIsNil",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: nil,
-				}
+				return &ChainManager{}
 			},
 			expected: 0,
 		},
 		{
 			name: "ReturnsTipHeightWhenTipExists",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 12345,
-					},
-				}
+				return withTip(&ChainManager{}, &BlockHeader{
+					Header: &block.Header{},
+					Height: 12345,
+				})
 			},
 			expected: 12345,
 		},
 		{
 			name: "ReturnsCorrectHeightForGenesisBlock",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 0,
-					},
-				}
+				return withTip(&ChainManager{}, &BlockHeader{
+					Header: &block.Header{},
+					Height: 0,
+				})
 			},
 			expected: 0,
 		},
 		{
 			name: "ReturnsCorrectHeightForHighBlock",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 800000,
-					},
-				}
+				return withTip(&ChainManager{}, &BlockHeader{
+					Header: &block.Header{},
+					Height: 800000,
+				})
 			},
 			expected: 800000,
 		},
@@ -477,6 +474,76 @@ func TestChainManagerAddHeader(t *testing.T) {
 	}
 }
 
+// toBlockHeaders wraps headers for AddHeaders, leaving Height and ChainWork to be filled in
+func toBlockHeaders(headers []*block.Header) []*BlockHeader {
+	branch := make([]*BlockHeader, len(headers))
+	for i, header := range headers {
+		branch[i] = &BlockHeader{Header: header, Hash: header.Hash()}
+	}
+	return branch
+}
+
+func TestChainManagerAddHeaders(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 3, 0)
+	fork := mineRegtestChain(t, chain[0].Hash(), 3, 1)
+
+	t.Run("ExtendsTipWithOneEvent", func(t *testing.T) {
+		cm := newWireChainManager(t, genesis, nil)
+		events := cm.SubscribeEvents(t.Context())
+
+		branch := toBlockHeaders(chain)
+		require.NoError(t, cm.AddHeaders(t.Context(), branch))
+		assert.Equal(t, chain[2].Hash(), cm.GetTip(t.Context()).Hash)
+		assert.Equal(t, uint32(3), branch[2].Height)
+		assert.Equal(t, 1, cm.GetTip(t.Context()).ChainWork.Cmp(branch[1].ChainWork))
+
+		event := <-events
+		assert.Equal(t, EventTipAdvanced, event.Type)
+		assert.Equal(t, chain[2].Hash(), event.Tip.Hash)
+		assert.Empty(t, events, "a batch publishes a single tip update")
+	})
+
+	t.Run("KeepsLighterBranchAsAlternate", func(t *testing.T) {
+		cm := newWireChainManager(t, genesis, chain)
+
+		require.NoError(t, cm.AddHeaders(t.Context(), toBlockHeaders(fork[:2])))
+		assert.Equal(t, chain[2].Hash(), cm.GetTip(t.Context()).Hash)
+		hash := fork[1].Hash()
+		stored, err := cm.GetHeaderByHash(t.Context(), &hash)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3), stored.Height)
+	})
+
+	t.Run("ReorgsThroughKnownAlternateAncestors", func(t *testing.T) {
+		cm := newWireChainManager(t, genesis, chain)
+		require.NoError(t, cm.AddHeaders(t.Context(), toBlockHeaders(fork[:2])))
+
+		require.NoError(t, cm.AddHeaders(t.Context(), toBlockHeaders(fork[2:])))
+		assert.Equal(t, fork[2].Hash(), cm.GetTip(t.Context()).Hash)
+		main, err := cm.GetHeaderByHeight(t.Context(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, fork[0].Hash(), main.Hash)
+	})
+
+	t.Run("RejectsUnknownParent", func(t *testing.T) {
+		cm := newWireChainManager(t, genesis, nil)
+		require.ErrorIs(t, cm.AddHeaders(t.Context(), toBlockHeaders(chain[1:])), ErrHeaderNotFound)
+	})
+
+	t.Run("RejectsBrokenLinkage", func(t *testing.T) {
+		cm := newWireChainManager(t, genesis, nil)
+		headers := toBlockHeaders([]*block.Header{chain[0], chain[1], chain[0]})
+		err := cm.AddHeaders(t.Context(), headers)
+		require.ErrorIs(t, err, ErrBrokenChain)
+		assert.Equal(t, uint32(0), cm.GetHeight(t.Context()))
+		for _, header := range headers {
+			assert.Zero(t, header.Height, "a broken batch leaves the caller's headers untouched")
+			assert.Nil(t, header.ChainWork)
+		}
+	})
+}
+
 func TestChainManagerPruneOrphans(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -487,7 +554,6 @@ func TestChainManagerPruneOrphans(t *testing.T) {
 			name: "NilTipReturnsEarly",
 			setupCM: func() *ChainManager {
 				return &ChainManager{
-					tip:      nil,
 					byHash:   make(map[chainhash.Hash]*BlockHeader),
 					byHeight: []chainhash.Hash{},
 				}
@@ -514,7 +580,7 @@ func TestChainManagerPruneOrphans(t *testing.T) {
 					Height: 50,
 					Hash:   tipHash,
 				}
-				cm.tip = tip
+				cm.tip.Store(tip)
 				cm.byHash[tipHash] = tip
 				cm.byHeight[50] = tipHash
 
@@ -552,7 +618,7 @@ func TestChainManagerPruneOrphans(t *testing.T) {
 					Height: 200,
 					Hash:   tipHash,
 				}
-				cm.tip = tip
+				cm.tip.Store(tip)
 				cm.byHash[tipHash] = tip
 				cm.byHeight[200] = tipHash
 
@@ -608,7 +674,7 @@ func TestChainManagerPruneOrphans(t *testing.T) {
 					Height: 200,
 					Hash:   tipHash,
 				}
-				cm.tip = tip
+				cm.tip.Store(tip)
 				cm.byHash[tipHash] = tip
 				cm.byHeight[200] = tipHash
 
@@ -661,7 +727,7 @@ func TestChainManagerPruneOrphans(t *testing.T) {
 					Height: 200,
 					Hash:   tipHash,
 				}
-				cm.tip = tip
+				cm.tip.Store(tip)
 				cm.byHash[tipHash] = tip
 
 				// Create orphan that would trigger overflow check
@@ -699,7 +765,7 @@ func TestChainManagerPruneOrphans(t *testing.T) {
 					Height: 300,
 					Hash:   tipHash,
 				}
-				cm.tip = tip
+				cm.tip.Store(tip)
 				cm.byHash[tipHash] = tip
 				cm.byHeight[300] = tipHash
 
@@ -758,3 +824,80 @@ func TestChainManagerPruneOrphans(t *testing.T) {
 		})
 	}
 }
+
+func TestChainManagerGetHeadersBackwards(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+
+	tests := []struct {
+		name     string
+		from     *BlockHeader
+		count    uint32
+		expected []*BlockHeader
+	}{
+		{name: "WalksMainChainNewestFirst", from: main[5], count: 3, expected: []*BlockHeader{main[5], main[4], main[3]}},
+		{name: "StopsAtGenesis", from: main[1], count: 10, expected: []*BlockHeader{main[1], main[0]}},
+		{name: "FollowsOrphanBranch", from: fork[1], count: 4, expected: []*BlockHeader{fork[1], fork[0], main[2], main[1]}},
+		{name: "ZeroCountReturnsNothing", from: main[5], count: 0, expected: []*BlockHeader{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers, err := cm.GetHeadersBackwards(t.Context(), &tt.from.Hash, tt.count)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, headers)
+		})
+	}
+
+	t.Run("UnknownHashReturnsNotFound", func(t *testing.T) {
+		_, err := cm.GetHeadersBackwards(t.Context(), &chainhash.Hash{0xff}, 5)
+		require.ErrorIs(t, err, ErrHeaderNotFound)
+	})
+}
+
+// BenchmarkChainManagerGetTip reads the tip from parallel goroutines while a writer keeps extending the chain
+// and subscribers drain the events, as when SSE clients are attached during sync
+func BenchmarkChainManagerGetTip(b *testing.B) {
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+	parent := &BlockHeader{Header: &block.Header{}, ChainWork: big.NewInt(1)}
+	parent.Hash = parent.Header.Hash()
+	require.NoError(b, cm.SetChainTip(b.Context(), []*BlockHeader{parent}))
+
+	ctx, cancel := context.WithCancel(b.Context())
+	for range 8 {
+		events := cm.SubscribeEvents(ctx)
+		go func() {
+			for event := range events {
+				_ = event.Tip // Consumed like an SSE client would
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for height := uint32(1); ctx.Err() == nil; height++ {
+			header := &BlockHeader{
+				Header:    &block.Header{PrevHash: parent.Hash, Nonce: height},
+				Height:    height,
+				ChainWork: new(big.Int).Add(parent.ChainWork, big.NewInt(1)),
+			}
+			header.Hash = header.Header.Hash()
+			if err := cm.SetChainTip(ctx, []*BlockHeader{header}); err != nil {
+				return
+			}
+			parent = header
+		}
+	})
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if tip := cm.GetTip(ctx); tip == nil || cm.GetHeight(ctx) < tip.Height {
+				b.Error("tip went backwards")
+			}
+		}
+	})
+	b.StopTimer()
+
+	cancel()
+	wg.Wait()
+}
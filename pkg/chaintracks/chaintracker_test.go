@@ -193,9 +193,7 @@ func TestChainManagerCurrentHeight(t *testing.T) {
 		{
 			name: "ReturnsZeroWhenTipIsNil",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: nil,
-				}
+				return &ChainManager{}
 			},
 			expectedHeight: 0,
 			expectedError:  nil,
@@ -203,12 +201,10 @@ func TestChainManagerCurrentHeight(t *testing.T) {
 		{
 			name: "ReturnsCorrectHeightWhenTipExists",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 12345,
-					},
-				}
+				return withTip(&ChainManager{}, &BlockHeader{
+					Header: &block.Header{},
+					Height: 12345,
+				})
 			},
 			expectedHeight: 12345,
 			expectedError:  nil,
@@ -216,12 +212,10 @@ func TestChainManagerCurrentHeight(t *testing.T) {
 		{
 			name: "ReturnsZeroForGenesisBlock",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 0,
-					},
-				}
+				return withTip(&ChainManager{}, &BlockHeader{
+					Header: &block.Header{},
+					Height: 0,
+				})
 			},
 			expectedHeight: 0,
 			expectedError:  nil,
@@ -229,12 +223,10 @@ func TestChainManagerCurrentHeight(t *testing.T) {
 		{
 			name: "ReturnsHighBlockHeight",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 800000,
-					},
-				}
+				return withTip(&ChainManager{}, &BlockHeader{
+					Header: &block.Header{},
+					Height: 800000,
+				})
 			},
 			expectedHeight: 800000,
 			expectedError:  nil,
@@ -242,12 +234,10 @@ func TestChainManagerCurrentHeight(t *testing.T) {
 		{
 			name: "ReturnsMaxUint32Height",
 			setupCM: func() *ChainManager {
-				return &ChainManager{
-					tip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 4294967295, // Max uint32
-					},
-				}
+				return withTip(&ChainManager{}, &BlockHeader{
+					Header: &block.Header{},
+					Height: 4294967295, // Max uint32
+				})
 			},
 			expectedHeight: 4294967295,
 			expectedError:  nil,
@@ -1,7 +1,13 @@
 package chaintracks
 
 import (
+	"context"
+	"fmt"
 	"math/big"
+	"slices"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
 )
 
 // oneLsh256 is 1 shifted left 256 bits (used for chainwork calculation)
@@ -74,6 +80,52 @@ func CompareChainWork(a, b *big.Int) int {
 	return a.Cmp(b)
 }
 
+// ChainWorkAtHeight is the cumulative work of the main chain up to and including a block
+type ChainWorkAtHeight struct {
+	Height    uint32         `json:"height"`
+	Hash      chainhash.Hash `json:"hash"`
+	ChainWork string         `json:"chainWork"` // 64-character hex
+}
+
+// GetChainWork returns the cumulative chain work of the main chain at height
+// Work is accumulated once as each header is linked and kept on its BlockHeader, and headers evicted by
+// SetMemoryWindow leave theirs in a per-height index, so this is a lookup at any height without reading disk.
+func (cm *ChainManager) GetChainWork(ctx context.Context, height uint32) (*big.Int, error) {
+	cm.mu.RLock()
+	if height < cm.windowFloor && uint64(height) < uint64(len(cm.byHeight)) {
+		work := cm.storedWorkAt(height)
+		cm.mu.RUnlock()
+		return work, nil
+	}
+	cm.mu.RUnlock()
+
+	header, err := cm.GetHeaderByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if header.ChainWork == nil {
+		return nil, fmt.Errorf("%w: no chainwork at height %d", ErrHeaderNotFound, height)
+	}
+	return new(big.Int).Set(header.ChainWork), nil
+}
+
+// GetChainWorkAtHeight looks up the main-chain work at height from any Chaintracks implementation
+// Returns ErrHeaderNotFound when the height is above the tip or the source does not track chainwork
+func GetChainWorkAtHeight(ctx context.Context, ct Chaintracks, height uint32) (*ChainWorkAtHeight, error) {
+	header, err := ct.GetHeaderByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if header.ChainWork == nil {
+		return nil, fmt.Errorf("%w: no chainwork at height %d", ErrHeaderNotFound, height)
+	}
+	return &ChainWorkAtHeight{
+		Height:    header.Height,
+		Hash:      header.Hash,
+		ChainWork: ChainWorkToHex(header.ChainWork),
+	}, nil
+}
+
 // ChainWorkToHex converts chainwork to a 64-character hex string (padded)
 func ChainWorkToHex(work *big.Int) string {
 	// Format as 64-character hex string (32 bytes)
@@ -94,3 +146,27 @@ func ChainWorkFromHex(hexStr string) (*big.Int, error) {
 	}
 	return work, nil
 }
+
+// CheckProofOfWork verifies that a header's target is within powLimitBits and that its hash meets the target
+// A powLimitBits of 0 applies the permissive regtest limit
+func CheckProofOfWork(header *block.Header, powLimitBits uint32) error {
+	if powLimitBits == 0 {
+		powLimitBits = regtestPowLimitBits
+	}
+
+	target := CompactToBig(header.Bits)
+	if target.Sign() <= 0 {
+		return fmt.Errorf("%w: non-positive target %08x", ErrInvalidProofOfWork, header.Bits)
+	}
+	if target.Cmp(CompactToBig(powLimitBits)) > 0 {
+		return fmt.Errorf("%w: target %08x above network limit %08x", ErrInvalidProofOfWork, header.Bits, powLimitBits)
+	}
+
+	hash := header.Hash()
+	hashBytes := slices.Clone(hash[:])
+	slices.Reverse(hashBytes) // Hashes are little-endian; compare as a big-endian number
+	if new(big.Int).SetBytes(hashBytes).Cmp(target) > 0 {
+		return fmt.Errorf("%w: hash %s above target %08x", ErrInvalidProofOfWork, hash, header.Bits)
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package chaintracks
 
 import (
+	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
 )
 
 func TestCompactToBig(t *testing.T) {
@@ -142,3 +146,77 @@ func TestChainWorkFromHex(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckProofOfWork(t *testing.T) {
+	genesisBytes, err := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := block.NewHeaderFromBytes(genesisBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withBits := func(bits uint32) *block.Header {
+		h := *genesis
+		h.Bits = bits
+		return &h
+	}
+	tampered := *genesis
+	tampered.Nonce++
+
+	tests := []struct {
+		name      string
+		header    *block.Header
+		powLimit  uint32
+		expectErr bool
+	}{
+		{name: "GenesisMeetsMainnetLimit", header: genesis, powLimit: 0x1d00ffff},
+		{name: "GenesisMeetsRegtestLimit", header: genesis, powLimit: 0},
+		{name: "TamperedNonceFails", header: &tampered, powLimit: 0x1d00ffff, expectErr: true},
+		{name: "TargetAboveLimitFails", header: withBits(0x1d01ffff), powLimit: 0x1d00ffff, expectErr: true},
+		{name: "ZeroTargetFails", header: withBits(0), powLimit: 0x1d00ffff, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckProofOfWork(tt.header, tt.powLimit)
+			if tt.expectErr && !errors.Is(err, ErrInvalidProofOfWork) {
+				t.Errorf("expected ErrInvalidProofOfWork, got %v", err)
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetChainWork(t *testing.T) {
+	cm, main, _ := newForkedChainManager(t)
+	main[3].ChainWork = big.NewInt(0x1234)
+
+	work, err := cm.GetChainWork(t.Context(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.Cmp(main[3].ChainWork) != 0 {
+		t.Errorf("GetChainWork(3) = %x, expected %x", work, main[3].ChainWork)
+	}
+
+	info, err := GetChainWorkAtHeight(t.Context(), cm, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Hash != main[3].Hash || info.ChainWork != ChainWorkToHex(main[3].ChainWork) {
+		t.Errorf("GetChainWorkAtHeight(3) = %+v", info)
+	}
+
+	for _, height := range []uint32{2, 9} {
+		if _, err := cm.GetChainWork(t.Context(), height); !errors.Is(err, ErrHeaderNotFound) {
+			t.Errorf("GetChainWork(%d) expected ErrHeaderNotFound, got %v", height, err)
+		}
+		if _, err := GetChainWorkAtHeight(t.Context(), cm, height); !errors.Is(err, ErrHeaderNotFound) {
+			t.Errorf("GetChainWorkAtHeight(%d) expected ErrHeaderNotFound, got %v", height, err)
+		}
+	}
+}
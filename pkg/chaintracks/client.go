@@ -2,48 +2,207 @@ package chaintracks
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/bsv-blockchain/go-sdk/block"
 	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/wallet"
+)
+
+const (
+	sseReconnectMin = time.Second
+	sseReconnectMax = 30 * time.Second
 )
 
 // Client is an HTTP client for chaintracks server with SSE support
+// Given several server URLs it fails over between them, returning to the primary once it recovers.
 type Client struct {
-	baseURL    string
+	baseURL    string // Primary server; requests are built against it and rebased onto the endpoint used
 	httpClient *http.Client
 	currentTip *BlockHeader
 	tipMu      sync.RWMutex
 	msgChan    chan *BlockHeader
-	cancelFunc context.CancelFunc
+
+	// Stream lifecycle, guarded by lifecycleMu; done closes when the stream goroutine exits
+	lifecycleMu sync.Mutex
+	cancelFunc  context.CancelFunc
+	done        chan struct{}
+
+	// SSE reconnect state, only touched by the stream goroutine
+	reconnectMin time.Duration
+	reconnectMax time.Duration
+	lastEventID  string
+	lastHash     *chainhash.Hash
+	streamURL    string // Server the stream last connected to, its event IDs are only valid there
+
+	// Headers buried below the tip, nil when disabled
+	cache *headerCache
+
+	endpointsMu sync.Mutex
+	endpoints   []*endpoint
+	sockets     unixSockets // Socket paths of unix:// servers by the placeholder host in their endpoint URL
+
+	retry RetryPolicy
+
+	headers http.Header // Added to every request, including the SSE stream
+
+	callbacks eventCallbacks // Registered with OnTip, OnReorg and OnSyncComplete
+
+	logger Logger
 }
 
 // NewClient creates a new HTTP client for chaintracks server
-func NewClient(baseURL string) *Client {
-	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
-		baseURL = "http://" + baseURL
+// Requests go to baseURL and fail over to fallbackURLs, in order, while it is unreachable or
+// returning 429/5xx. A failing server is skipped for a cooldown that grows with each consecutive
+// failure, then tried again, so the client fails back once it recovers. A server on the same host can be
+// reached over a unix domain socket with a URL like unix:///var/run/chaintracks.sock.
+func NewClient(baseURL string, fallbackURLs ...string) *Client {
+	sockets := unixSockets{}
+	baseURL = sockets.baseURL(baseURL)
+	endpoints := []*endpoint{{url: baseURL}}
+	for _, u := range fallbackURLs {
+		endpoints = append(endpoints, &endpoint{url: sockets.baseURL(u)})
+	}
+
+	httpClient := &http.Client{}
+	if len(sockets) > 0 {
+		httpClient.Transport = sockets.transport()
 	}
-	baseURL = strings.TrimSuffix(baseURL, "/")
 
 	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
+		baseURL:      baseURL,
+		endpoints:    endpoints,
+		sockets:      sockets,
+		httpClient:   httpClient,
+		reconnectMin: sseReconnectMin,
+		reconnectMax: sseReconnectMax,
+		retry:        DefaultRetryPolicy(),
+		headers:      http.Header{},
+		cache:        newHeaderCache(defaultHeaderCacheSize, 0),
+		logger:       getDefaultLogger(),
+	}
+}
+
+// SetLogger replaces the client's logger; call it before Start
+func (cc *Client) SetLogger(logger Logger) {
+	cc.logger = logger
+}
+
+// SetWallet enables BRC-103 mutual authentication with the wallet's identity key; call it before Start
+// Requests are signed and the server's signature on each response is verified. The SSE stream is not
+// covered, since BRC-104 signs complete responses, so a server requiring mutual auth needs SetBearerToken for it.
+func (cc *Client) SetWallet(w wallet.Interface) error {
+	sessions := make([]*mutualAuthClient, len(cc.endpoints))
+	for i, ep := range cc.endpoints {
+		m, err := newMutualAuthClient(w, ep.url, cc.httpClient)
+		if err != nil {
+			return err
+		}
+		sessions[i] = m
+	}
+
+	cc.endpointsMu.Lock()
+	defer cc.endpointsMu.Unlock()
+	for i, ep := range cc.endpoints {
+		ep.mutualAuth = sessions[i]
+	}
+	return nil
+}
+
+// SetBearerToken sends token as a Bearer Authorization header on every request; call it before Start
+// This pairs with a server requiring an API key or JWT, and unlike a browser EventSource it also
+// authenticates the SSE stream. An empty token removes the header.
+func (cc *Client) SetBearerToken(token string) {
+	if token == "" {
+		cc.SetHeader("Authorization", "")
+		return
+	}
+	cc.SetHeader("Authorization", "Bearer "+token)
+}
+
+// SetHeader adds a header to every request, including the SSE stream; call it before Start
+// A header a request sets itself, such as Accept, takes precedence. An empty value removes the header.
+func (cc *Client) SetHeader(name, value string) {
+	if value == "" {
+		cc.headers.Del(name)
+		return
+	}
+	cc.headers.Set(name, value)
+}
+
+// do sends a request with retries and failover, through the BRC-104 transport when a wallet is set
+func (cc *Client) do(req *http.Request) (*http.Response, error) {
+	return cc.withRetry(req, func(r *http.Request) (*http.Response, error) {
+		return cc.send(r, true)
+	})
+}
+
+// log returns the configured logger, falling back to the package default
+func (cc *Client) log() Logger {
+	if cc.logger == nil {
+		return getDefaultLogger()
 	}
+	return cc.logger
 }
 
 // Start connects to the SSE stream and returns a channel for tip updates
+// If the stream drops, the client reconnects with exponential backoff and resumes
+// from the last tip it saw. Like ChainManager, the channel holds only the latest tip. It is
+// closed when ctx is cancelled or Stop is called, after which the client can be started again.
 func (cc *Client) Start(ctx context.Context) (<-chan *BlockHeader, error) {
-	cc.msgChan = make(chan *BlockHeader, 1)
+	cc.lifecycleMu.Lock()
+	defer cc.lifecycleMu.Unlock()
+
+	if cc.running() {
+		return nil, ErrClientAlreadyStarted
+	}
 
 	childCtx, cancel := context.WithCancel(ctx)
+	body, err := cc.connectSSE(childCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	cc.msgChan = make(chan *BlockHeader, 1)
 	cc.cancelFunc = cancel
+	cc.done = make(chan struct{})
+	go func() {
+		defer close(cc.done)
+		cc.runSSE(childCtx, body)
+	}()
+
+	return cc.msgChan, nil
+}
 
-	req, err := http.NewRequestWithContext(childCtx, "GET", cc.baseURL+"/v2/tip/stream", nil)
+// running reports whether the stream goroutine is active; lifecycleMu must be held
+func (cc *Client) running() bool {
+	if cc.done == nil {
+		return false
+	}
+	select {
+	case <-cc.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// connectSSE opens the SSE stream on the healthiest server, sending Last-Event-ID when resuming on the same one
+// The stream stays on a fallback until it drops; the next connection returns to the primary if it has recovered.
+func (cc *Client) connectSSE(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", cc.baseURL+"/v2/tip/stream", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SSE request: %w", err)
 	}
@@ -52,7 +211,15 @@ func (cc *Client) Start(ctx context.Context) (<-chan *BlockHeader, error) {
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
 
-	resp, err := cc.httpClient.Do(req)
+	var connected *endpoint
+	resp, err := cc.withRetry(req, func(r *http.Request) (*http.Response, error) {
+		connected = cc.endpointOrder()[0]
+		r.Header.Del("Last-Event-ID")
+		if cc.lastEventID != "" && connected.url == cc.streamURL {
+			r.Header.Set("Last-Event-ID", cc.lastEventID)
+		}
+		return cc.sendTo(connected, r, false, false)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to SSE stream: %w", err)
 	}
@@ -62,20 +229,49 @@ func (cc *Client) Start(ctx context.Context) (<-chan *BlockHeader, error) {
 		return nil, fmt.Errorf("%w: status %d", ErrSSEStreamFailed, resp.StatusCode)
 	}
 
-	go cc.readSSE(childCtx, resp.Body)
+	cc.streamURL = connected.url
+	return resp.Body, nil
+}
 
-	return cc.msgChan, nil
+// runSSE reads the stream and reconnects with exponential backoff until ctx is cancelled
+func (cc *Client) runSSE(ctx context.Context, body io.ReadCloser) {
+	defer close(cc.msgChan)
+
+	for {
+		cc.readSSE(ctx, body)
+
+		backoff := cc.reconnectMin
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			var err error
+			body, err = cc.connectSSE(ctx)
+			if err == nil {
+				break
+			}
+
+			cc.log().Warn("SSE reconnect failed", "url", cc.baseURL, "error", err, "retryIn", backoff)
+			backoff = min(backoff*2, cc.reconnectMax)
+		}
+	}
 }
 
-// readSSE reads Server-Sent Events from the response body
+// readSSE reads Server-Sent Events from the response body until the stream ends
+// Reorg events invalidate the header cache and, like tips and finished syncs, are passed to registered
+// callbacks; other named events besides "tip" are skipped.
+// The server always follows a reorg with a tip event.
 //
 //nolint:gocyclo // Inherent complexity of SSE parsing logic
 func (cc *Client) readSSE(ctx context.Context, body io.ReadCloser) {
 	defer func() { _ = body.Close() }()
-	defer close(cc.msgChan)
 
 	reader := bufio.NewReader(body)
-	var lastHash *chainhash.Hash
+	var eventName string
+	var data strings.Builder
 
 	for {
 		select {
@@ -86,51 +282,107 @@ func (cc *Client) readSSE(ctx context.Context, body io.ReadCloser) {
 
 		line, err := reader.ReadString('\n')
 		if err != nil {
-			if err != io.EOF {
-				return
-			}
 			return
 		}
 
-		line = strings.TrimSpace(line)
-		if !strings.HasPrefix(line, "data: ") {
+		field, value := parseSSELine(line)
+		switch field {
+		case "event":
+			eventName = value
+			continue
+		case "id":
+			cc.lastEventID = value
+			continue
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			continue
+		case "":
+			// Blank line dispatches the event
+		default:
 			continue
 		}
 
-		data := strings.TrimPrefix(line, "data: ")
-		if data == "" {
+		payload := data.String()
+		name := eventName
+		eventName = ""
+		data.Reset()
+
+		switch name {
+		case "reorg":
+			cc.handleReorgEvent(payload)
+			continue
+		case "sync-progress":
+			cc.handleSyncProgressEvent(payload)
+			continue
+		}
+		if payload == "" || (name != "" && name != "tip") {
 			continue
 		}
 
 		var blockHeader BlockHeader
-		if err := json.Unmarshal([]byte(data), &blockHeader); err != nil {
+		if err := json.Unmarshal([]byte(payload), &blockHeader); err != nil {
 			continue
 		}
 
-		if lastHash != nil && lastHash.IsEqual(&blockHeader.Hash) {
+		if cc.lastHash != nil && cc.lastHash.IsEqual(&blockHeader.Hash) {
 			continue
 		}
 
-		lastHash = &blockHeader.Hash
+		cc.lastHash = &blockHeader.Hash
 
 		cc.tipMu.Lock()
 		cc.currentTip = &blockHeader
 		cc.tipMu.Unlock()
+		cc.callbacks.tip(&blockHeader, cc.log())
 
+		// Replace any unread tip, only the latest matters
+		select {
+		case <-cc.msgChan:
+		default:
+		}
 		select {
 		case cc.msgChan <- &blockHeader:
-		case <-ctx.Done():
-			return
 		default:
 		}
 	}
 }
 
-// Stop closes the SSE connection
+// parseSSELine splits an SSE line into field and value
+// Blank lines return an empty field; comment lines return "comment"
+func parseSSELine(line string) (string, string) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", ""
+	}
+	if strings.HasPrefix(line, ":") {
+		return "comment", ""
+	}
+
+	field, value, _ := strings.Cut(line, ":")
+	return field, strings.TrimPrefix(value, " ")
+}
+
+// Stop closes the SSE connection and waits for the stream to shut down
+// The tip, stream resume point and header cache are cleared, so a later Start begins fresh.
 func (cc *Client) Stop() error {
-	if cc.cancelFunc != nil {
-		cc.cancelFunc()
+	cc.lifecycleMu.Lock()
+	defer cc.lifecycleMu.Unlock()
+
+	if cc.cancelFunc == nil {
+		return nil
 	}
+	cc.cancelFunc()
+	<-cc.done
+	cc.cancelFunc = nil
+
+	cc.tipMu.Lock()
+	cc.currentTip = nil
+	cc.tipMu.Unlock()
+	cc.lastEventID, cc.lastHash, cc.streamURL = "", nil, ""
+	cc.cache.purge()
 	return nil
 }
 
@@ -151,94 +403,244 @@ func (cc *Client) GetHeight(_ context.Context) uint32 {
 	return cc.currentTip.Height
 }
 
-// GetHeaderByHeight retrieves a header by height from the server
+// GetHeaderByHeight retrieves a header by height from the cache or the server
 func (cc *Client) GetHeaderByHeight(ctx context.Context, height uint32) (*BlockHeader, error) {
+	if header, ok := cc.cachedByHeight(height); ok {
+		return header, nil
+	}
 	url := fmt.Sprintf("%s/v2/header/height/%d", cc.baseURL, height)
-	return cc.fetchHeader(ctx, url)
+	header, err := cc.fetchHeader(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	cc.cacheHeader(ctx, header, true)
+	return header, nil
 }
 
-// GetHeaderByHash retrieves a header by hash from the server
+// GetHeaderByHash retrieves a header by hash from the cache or the server
 func (cc *Client) GetHeaderByHash(ctx context.Context, hash *chainhash.Hash) (*BlockHeader, error) {
+	if header, ok := cc.cachedByHash(*hash); ok {
+		return header, nil
+	}
 	url := fmt.Sprintf("%s/v2/header/hash/%s", cc.baseURL, hash.String())
-	return cc.fetchHeader(ctx, url)
+	header, err := cc.fetchHeader(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	cc.cacheHeader(ctx, header, false)
+	return header, nil
 }
 
-// fetchHeader is a helper to fetch and parse a header from the server
-func (cc *Client) fetchHeader(ctx context.Context, url string) (*BlockHeader, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// GetHeadersBackwards retrieves up to count headers from fromHash back through its ancestors, newest first
+func (cc *Client) GetHeadersBackwards(ctx context.Context, fromHash *chainhash.Hash, count uint32) ([]*BlockHeader, error) {
+	url := fmt.Sprintf("%s/v2/headers/backwards/%s?count=%d", cc.baseURL, fromHash.String(), count)
+
+	var headers []*BlockHeader
+	status, err := cc.getJSON(ctx, url, "headers", &headers)
+	if status == http.StatusNotFound {
+		return nil, ErrHeaderNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
+	return headers, nil
+}
 
-	resp, err := cc.httpClient.Do(req)
+// GetHeaders retrieves up to count main-chain headers starting at height, oldest first
+// Fewer are returned when the server's chain ends sooner.
+func (cc *Client) GetHeaders(ctx context.Context, height, count uint32) ([]*block.Header, error) {
+	url := fmt.Sprintf("%s/v2/headers?height=%d&count=%d", cc.baseURL, height, count)
+
+	var value string
+	status, err := cc.getJSON(ctx, url, "headers", &value)
+	// A height past the server's tip is answered with 416
+	if status == http.StatusRequestedRangeNotSatisfiable {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch header: %w", err)
+		return nil, err
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: status %d", ErrServerRequestFailed, resp.StatusCode)
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode headers: %w", err)
 	}
+	return parseHeaderFile(data)
+}
 
-	var response struct {
-		Status string       `json:"status"`
-		Value  *BlockHeader `json:"value"`
+// GetHeadersByHashes retrieves the headers for several hashes in a single request
+// The result matches the input order, with nil entries for hashes the server does not know
+func (cc *Client) GetHeadersByHashes(ctx context.Context, hashes []chainhash.Hash) ([]*BlockHeader, error) {
+	hashStrs := make([]string, len(hashes))
+	for i := range hashes {
+		hashStrs[i] = hashes[i].String()
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	var headers []*BlockHeader
+	if _, err := cc.requestJSON(ctx, http.MethodPost, cc.baseURL+"/v2/headers/byHashes", hashStrs, "headers", &headers); err != nil {
+		return nil, err
+	}
+	if len(headers) != len(hashes) {
+		return nil, ErrServerReturnedError
 	}
+	return headers, nil
+}
+
+// GetAnchor retrieves the anchor bundle for a block hash in a single request
+func (cc *Client) GetAnchor(ctx context.Context, blockHash *chainhash.Hash) (*Anchor, error) {
+	url := fmt.Sprintf("%s/v2/anchor/%s", cc.baseURL, blockHash.String())
 
-	if response.Status != "success" || response.Value == nil {
+	var anchor *Anchor
+	status, err := cc.getJSON(ctx, url, "anchor", &anchor)
+	if status == http.StatusNotFound {
 		return nil, ErrHeaderNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
+	if anchor == nil {
+		return nil, ErrServerReturnedError
+	}
+	return anchor, nil
+}
+
+// GetConfirmations retrieves the height, confirmation count and main-chain status of a block
+func (cc *Client) GetConfirmations(ctx context.Context, blockHash *chainhash.Hash) (*BlockConfirmations, error) {
+	url := fmt.Sprintf("%s/v2/confirmations/%s", cc.baseURL, blockHash.String())
 
-	return response.Value, nil
+	var confirmations *BlockConfirmations
+	status, err := cc.getJSON(ctx, url, "confirmations", &confirmations)
+	if status == http.StatusNotFound {
+		return nil, ErrHeaderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if confirmations == nil {
+		return nil, ErrServerReturnedError
+	}
+	return confirmations, nil
 }
 
-// IsValidRootForHeight implements the ChainTracker interface
-func (cc *Client) IsValidRootForHeight(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
-	header, err := cc.GetHeaderByHeight(ctx, height)
+// GetChainWork retrieves the cumulative main-chain work at height
+func (cc *Client) GetChainWork(ctx context.Context, height uint32) (*big.Int, error) {
+	url := fmt.Sprintf("%s/v2/chainwork/%d", cc.baseURL, height)
+
+	var work *ChainWorkAtHeight
+	status, err := cc.getJSON(ctx, url, "chainwork", &work)
+	if status == http.StatusNotFound {
+		return nil, ErrHeaderNotFound
+	}
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return header.MerkleRoot.IsEqual(root), nil
+	if work == nil {
+		return nil, ErrServerReturnedError
+	}
+	return ChainWorkFromHex(work.ChainWork)
 }
 
-// CurrentHeight implements the ChainTracker interface
-func (cc *Client) CurrentHeight(ctx context.Context) (uint32, error) {
-	return cc.GetHeight(ctx), nil
+// fetchHeader is a helper to fetch and parse a header from the server
+func (cc *Client) fetchHeader(ctx context.Context, url string) (*BlockHeader, error) {
+	var header *BlockHeader
+	_, err := cc.getJSON(ctx, url, "header", &header)
+	if errors.Is(err, ErrServerReturnedError) {
+		return nil, ErrHeaderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, ErrHeaderNotFound
+	}
+	return header, nil
 }
 
-// GetNetwork returns the network name from the server
-func (cc *Client) GetNetwork(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", cc.baseURL+"/v2/network", nil)
+// getJSON GETs url and decodes the value of a success response into value, see requestJSON
+func (cc *Client) getJSON(ctx context.Context, url, what string, value any) (int, error) {
+	return cc.requestJSON(ctx, http.MethodGet, url, nil, what, value)
+}
+
+// requestJSON sends a request, with body encoded as JSON unless nil, and decodes the value of a success response
+// into value. It returns the response status so callers can map statuses such as 404 to their own errors; any
+// status but 200 also returns an error wrapping ErrServerRequestFailed, and a body whose status is not "success"
+// returns ErrServerReturnedError. what names the resource in error messages.
+func (cc *Client) requestJSON(ctx context.Context, method, url string, body any, what string, value any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := cc.httpClient.Do(req)
+	resp, err := cc.do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch network: %w", err)
+		return 0, fmt.Errorf("failed to fetch %s: %w", what, err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	var response struct {
-		Status string `json:"status"`
-		Value  string `json:"value"`
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("%w: status %d", ErrServerRequestFailed, resp.StatusCode)
 	}
 
+	var response struct {
+		Status string          `json:"status"`
+		Value  json.RawMessage `json:"value"`
+	}
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
 	}
-
 	if response.Status != "success" {
-		return "", ErrServerReturnedError
+		return resp.StatusCode, ErrServerReturnedError
 	}
+	if len(response.Value) > 0 {
+		if err := json.Unmarshal(response.Value, value); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode %s: %w", what, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// IsValidRootForHeight implements the ChainTracker interface
+func (cc *Client) IsValidRootForHeight(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	header, err := cc.GetHeaderByHeight(ctx, height)
+	if err != nil {
+		return false, err
+	}
+	return header.MerkleRoot.IsEqual(root), nil
+}
 
-	return response.Value, nil
+// CurrentHeight implements the ChainTracker interface
+// It answers from the tip the SSE stream delivered, and asks the server for its tip until there is one, so a
+// client that was never started still reports the real height.
+func (cc *Client) CurrentHeight(ctx context.Context) (uint32, error) {
+	if tip := cc.GetTip(ctx); tip != nil {
+		return tip.Height, nil
+	}
+	tip, err := cc.fetchHeader(ctx, cc.baseURL+"/v2/tip/header")
+	if err != nil {
+		return 0, err
+	}
+	return tip.Height, nil
+}
+
+// GetNetwork returns the network name from the server
+func (cc *Client) GetNetwork(ctx context.Context) (string, error) {
+	var network string
+	if _, err := cc.getJSON(ctx, cc.baseURL+"/v2/network", "network", &network); err != nil {
+		return "", err
+	}
+	return network, nil
 }
@@ -1,13 +1,19 @@
 package chaintracks
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bsv-blockchain/go-sdk/block"
 	"github.com/bsv-blockchain/go-sdk/chainhash"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/wallet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -372,6 +378,323 @@ func TestClientGetHeaderByHash(t *testing.T) {
 	}
 }
 
+func TestClientGetHeadersBackwards(t *testing.T) {
+	fromHash := chainhash.Hash{2}
+
+	tests := []struct {
+		name            string
+		handler         http.HandlerFunc
+		expectedHeights []uint32
+		expectedError   error
+	}{
+		{
+			name: "ReturnsHeadersNewestFirst",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/headers/backwards/"+fromHash.String(), r.URL.Path)
+				assert.Equal(t, "2", r.URL.Query().Get("count"))
+				_, _ = w.Write([]byte(`{"status":"success","value":[{"height":5},{"height":4}]}`))
+			},
+			expectedHeights: []uint32{5, 4},
+		},
+		{
+			name: "ReturnsNotFoundFor404",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedError: ErrHeaderNotFound,
+		},
+		{
+			name: "ReturnsErrorForServerFailure",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedError: ErrServerRequestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			headers, err := NewClient(server.URL).GetHeadersBackwards(t.Context(), &fromHash, 2)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			heights := make([]uint32, 0, len(headers))
+			for _, header := range headers {
+				heights = append(heights, header.Height)
+			}
+			assert.Equal(t, tt.expectedHeights, heights)
+		})
+	}
+}
+
+func TestClientGetHeaders(t *testing.T) {
+	headers := []*block.Header{{Nonce: 1}, {Nonce: 2}}
+	payload := hex.EncodeToString(append(headers[0].Bytes(), headers[1].Bytes()...))
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedCount int
+		expectedError error
+	}{
+		{
+			name: "DecodesHexHeaders",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/headers", r.URL.Path)
+				assert.Equal(t, "7", r.URL.Query().Get("height"))
+				assert.Equal(t, "2", r.URL.Query().Get("count"))
+				_, _ = w.Write([]byte(`{"status":"success","value":"` + payload + `"}`))
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "EmptyPastTip",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"status":"success","value":""}`))
+			},
+		},
+		{
+			name: "EmptyOutOfRange",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			},
+		},
+		{
+			name: "RejectsTruncatedHeader",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"status":"success","value":"` + payload[:100] + `"}`))
+			},
+			expectedError: ErrInvalidFileSize,
+		},
+		{
+			name: "ReturnsErrorForServerFailure",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedError: ErrServerRequestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			got, err := NewClient(server.URL).GetHeaders(t.Context(), 7, 2)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, tt.expectedCount)
+			for i, header := range got {
+				assert.Equal(t, headers[i].Hash(), header.Hash())
+			}
+		})
+	}
+}
+
+func TestClientGetHeadersByHashes(t *testing.T) {
+	hashes := []chainhash.Hash{{1}, {2}}
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedFound []bool
+		expectedError error
+	}{
+		{
+			name: "ReturnsHeadersInRequestOrderWithNilForUnknown",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "/v2/headers/byHashes", r.URL.Path)
+				var body []string
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, []string{hashes[0].String(), hashes[1].String()}, body)
+				_, _ = w.Write([]byte(`{"status":"success","value":[{"height":7},null]}`))
+			},
+			expectedFound: []bool{true, false},
+		},
+		{
+			name: "RejectsMismatchedLength",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"status":"success","value":[{"height":7}]}`))
+			},
+			expectedError: ErrServerReturnedError,
+		},
+		{
+			name: "ReturnsErrorForBadRequest",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			expectedError: ErrServerRequestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			headers, err := NewClient(server.URL).GetHeadersByHashes(t.Context(), hashes)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			found := make([]bool, len(headers))
+			for i, header := range headers {
+				found[i] = header != nil
+			}
+			assert.Equal(t, tt.expectedFound, found)
+		})
+	}
+}
+
+func TestClientGetAnchor(t *testing.T) {
+	blockHash := chainhash.Hash{3}
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedError error
+	}{
+		{
+			name: "ReturnsAnchor",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/anchor/"+blockHash.String(), r.URL.Path)
+				_, _ = w.Write([]byte(`{"status":"success","value":{"height":7,"confirmations":2,"headerHex":"00"}}`))
+			},
+		},
+		{
+			name: "ReturnsNotFoundFor404",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedError: ErrHeaderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			anchor, err := NewClient(server.URL).GetAnchor(t.Context(), &blockHash)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, uint32(7), anchor.Height)
+			assert.Equal(t, uint32(2), anchor.Confirmations)
+			assert.Equal(t, "00", anchor.HeaderHex)
+		})
+	}
+}
+
+func TestClientGetConfirmations(t *testing.T) {
+	blockHash := chainhash.Hash{3}
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedError error
+	}{
+		{
+			name: "ReturnsConfirmations",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/confirmations/"+blockHash.String(), r.URL.Path)
+				_, _ = w.Write([]byte(`{"status":"success","value":{"height":7,"confirmations":2,"isOnMainChain":true}}`))
+			},
+		},
+		{
+			name: "ReturnsNotFoundFor404",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedError: ErrHeaderNotFound,
+		},
+		{
+			name: "RejectsErrorStatus",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"status":"error"}`))
+			},
+			expectedError: ErrServerReturnedError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			result, err := NewClient(server.URL).GetConfirmations(t.Context(), &blockHash)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, BlockConfirmations{Height: 7, Confirmations: 2, IsOnMainChain: true}, *result)
+		})
+	}
+}
+
+func TestClientGetChainWork(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedError error
+	}{
+		{
+			name: "ReturnsChainWork",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/chainwork/7", r.URL.Path)
+				_, _ = w.Write([]byte(`{"status":"success","value":{"height":7,"chainWork":"` + ChainWorkToHex(big.NewInt(0x1234)) + `"}}`))
+			},
+		},
+		{
+			name: "ReturnsNotFoundFor404",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedError: ErrHeaderNotFound,
+		},
+		{
+			name: "RejectsMalformedWork",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"status":"success","value":{"height":7,"chainWork":"zz"}}`))
+			},
+			expectedError: ErrInvalidHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			work, err := NewClient(server.URL).GetChainWork(t.Context(), 7)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, 0, work.Cmp(big.NewInt(0x1234)))
+		})
+	}
+}
+
 func TestClientGetNetwork(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -424,6 +747,17 @@ func TestClientGetNetwork(t *testing.T) {
 			},
 			expectedError: ErrServerReturnedError,
 		},
+		{
+			name: "ReturnsErrorForErrorStatus",
+			setupServer: func() *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(http.StatusBadGateway)
+					_, _ = w.Write([]byte("<html>Bad Gateway</html>"))
+				}))
+			},
+			expectedError: ErrServerRequestFailed,
+		},
 	}
 
 	for _, tt := range tests {
@@ -445,43 +779,67 @@ func TestClientGetNetwork(t *testing.T) {
 	}
 }
 
+func TestClientSetWalletRequiresMutualAuth(t *testing.T) {
+	var handshakes int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/auth" {
+			handshakes++
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+	w, err := wallet.NewCompletedProtoWallet(key)
+	require.NoError(t, err)
+
+	client := NewClient(srv.URL)
+	require.NoError(t, client.SetWallet(w))
+
+	_, err = client.GetNetwork(t.Context())
+	require.Error(t, err)
+	assert.Equal(t, 1, handshakes)
+}
+
 func TestClientCurrentHeight(t *testing.T) {
-	tests := []struct {
-		name           string
-		setupClient    func() *Client
-		expectedHeight uint32
-	}{
-		{
-			name: "ReturnsZeroWhenCurrentTipIsNil",
-			setupClient: func() *Client {
-				return &Client{
-					currentTip: nil,
-				}
-			},
-			expectedHeight: 0,
-		},
-		{
-			name: "ReturnsCorrectHeightWhenCurrentTipIsSet",
-			setupClient: func() *Client {
-				return &Client{
-					currentTip: &BlockHeader{
-						Header: &block.Header{},
-						Height: 54321,
-					},
-				}
+	t.Run("ReturnsStreamedTipHeight", func(t *testing.T) {
+		client := &Client{
+			currentTip: &BlockHeader{
+				Header: &block.Header{},
+				Height: 54321,
 			},
-			expectedHeight: 54321,
-		},
-	}
+		}
+		result, err := client.CurrentHeight(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(54321), result)
+	})
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			client := tt.setupClient()
-			result, err := client.CurrentHeight(t.Context())
-			require.NoError(t, err)
-			assert.Equal(t, tt.expectedHeight, result)
-		})
-	}
+	t.Run("FetchesTipBeforeStreamDeliversOne", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2/tip/header", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"status": "success",
+				"value":  &BlockHeader{Header: &block.Header{}, Height: 777},
+			})
+		}))
+		defer server.Close()
+
+		result, err := NewClient(server.URL).CurrentHeight(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(777), result)
+	})
+
+	t.Run("ReturnsErrorWhenTipUnavailable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := NewClient(server.URL).CurrentHeight(t.Context())
+		assert.ErrorIs(t, err, ErrServerRequestFailed)
+	})
 }
 
 func TestClientIsValidRootForHeight(t *testing.T) {
@@ -576,3 +934,207 @@ func TestClientIsValidRootForHeight(t *testing.T) {
 		})
 	}
 }
+
+func TestClientStartParsesNamedEvents(t *testing.T) {
+	tipHash := chainhash.Hash{5}
+	tipJSON, err := json.Marshal(&BlockHeader{Header: &block.Header{}, Height: 7, Hash: tipHash})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": keepalive\n\n"))
+		_, _ = w.Write([]byte("event: reorg\nid: 7\ndata: {\"type\":\"reorg\"}\n\n"))
+		_, _ = w.Write([]byte("event: sync-progress\ndata: {\"state\":\"synced\"}\n\n"))
+		_, _ = w.Write([]byte("event: tip\nid: 7\ndata: " + string(tipJSON) + "\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	tips, err := client.Start(t.Context())
+	require.NoError(t, err)
+	defer func() { _ = client.Stop() }()
+
+	tip, ok := <-tips
+	require.True(t, ok)
+	assert.Equal(t, uint32(7), tip.Height)
+	assert.Equal(t, tipHash, tip.Hash)
+	assert.Equal(t, uint32(7), client.GetHeight(t.Context()))
+}
+
+func TestParseSSELine(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          string
+		expectedField string
+		expectedValue string
+	}{
+		{name: "BlankLine", line: "\n", expectedField: "", expectedValue: ""},
+		{name: "Comment", line: ": keepalive\n", expectedField: "comment", expectedValue: ""},
+		{name: "EventField", line: "event: tip\n", expectedField: "event", expectedValue: "tip"},
+		{name: "DataWithoutSpace", line: "data:{}\r\n", expectedField: "data", expectedValue: "{}"},
+		{name: "DataWithColons", line: "data: {\"a\":1}\n", expectedField: "data", expectedValue: "{\"a\":1}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, value := parseSSELine(tt.line)
+			assert.Equal(t, tt.expectedField, field)
+			assert.Equal(t, tt.expectedValue, value)
+		})
+	}
+}
+
+func TestClientStartReconnectsWithLastEventID(t *testing.T) {
+	tipJSON := func(height uint32) string {
+		data, err := json.Marshal(&BlockHeader{Header: &block.Header{}, Height: height, Hash: chainhash.Hash{byte(height)}})
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	var mu sync.Mutex
+	var lastEventIDs []string
+	connections := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		attempt := connections
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		mu.Unlock()
+
+		switch attempt {
+		case 1:
+			_, _ = w.Write([]byte("event: tip\nid: 7\ndata: " + tipJSON(7) + "\n\n"))
+		case 2:
+			// Transient failure
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte("event: tip\nid: 8\ndata: " + tipJSON(8) + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.reconnectMin = 5 * time.Millisecond
+	client.reconnectMax = 20 * time.Millisecond
+
+	tips, err := client.Start(t.Context())
+	require.NoError(t, err)
+	defer func() { _ = client.Stop() }()
+
+	first := <-tips
+	require.NotNil(t, first)
+	assert.Equal(t, uint32(7), first.Height)
+
+	second := <-tips
+	require.NotNil(t, second)
+	assert.Equal(t, uint32(8), second.Height)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, len(lastEventIDs), 3)
+	assert.Empty(t, lastEventIDs[0])
+	assert.Equal(t, "7", lastEventIDs[1])
+	assert.Equal(t, "7", lastEventIDs[2])
+}
+
+func TestClientStopClosesChannel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.reconnectMin = 5 * time.Millisecond
+
+	tips, err := client.Start(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, client.Stop())
+
+	select {
+	case _, ok := <-tips:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		require.FailNow(t, "channel was not closed after Stop")
+	}
+}
+
+func TestClientLifecycle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("event: tip\nid: 1\ndata: {\"height\":200,\"hash\":\"" + chainhash.Hash{2}.String() + "\"}\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.reconnectMin = 5 * time.Millisecond
+	require.NoError(t, client.Stop(), "stopping a client that never started is a no-op")
+
+	tips, err := client.Start(t.Context())
+	require.NoError(t, err)
+	_, err = client.Start(t.Context())
+	require.ErrorIs(t, err, ErrClientAlreadyStarted)
+
+	tip := <-tips
+	require.NotNil(t, tip)
+	assert.Equal(t, uint32(200), client.GetHeight(t.Context()))
+	client.cacheHeader(t.Context(), &BlockHeader{Height: 100, Hash: chainhash.Hash{1}}, true)
+
+	require.NoError(t, client.Stop())
+	_, ok := <-tips
+	assert.False(t, ok)
+	assert.Nil(t, client.GetTip(t.Context()))
+	assert.Empty(t, client.lastEventID)
+	_, cached := client.cachedByHeight(100)
+	assert.False(t, cached, "Stop clears the header cache")
+
+	tips, err = client.Start(t.Context())
+	require.NoError(t, err, "a stopped client can be started again")
+	require.NotNil(t, <-tips)
+	require.NoError(t, client.Stop())
+}
+
+func TestClientHeaders(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		if r.URL.Path == "/v2/tip/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("event: tip\nid: 1\ndata: {\"height\":1,\"hash\":\"" + chainhash.Hash{1}.String() + "\"}\n\n"))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "value": "main"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetBearerToken("secret")
+	client.SetHeader("X-Tenant", "acme")
+	client.SetHeader("Accept", "text/plain")
+
+	_, err := client.GetNetwork(t.Context())
+	require.NoError(t, err)
+	tips, err := client.Start(t.Context())
+	require.NoError(t, err)
+	<-tips
+	require.NoError(t, client.Stop())
+
+	mu.Lock()
+	for _, path := range []string{"/v2/network", "/v2/tip/stream"} {
+		assert.Equal(t, "Bearer secret", seen[path].Get("Authorization"), path)
+		assert.Equal(t, "acme", seen[path].Get("X-Tenant"), path)
+	}
+	assert.Equal(t, "text/event-stream", seen["/v2/tip/stream"].Get("Accept"), "a header set by the request wins")
+	mu.Unlock()
+
+	client.SetBearerToken("")
+	_, err = client.GetNetwork(t.Context())
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Empty(t, seen["/v2/network"].Get("Authorization"))
+}
@@ -0,0 +1,79 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+)
+
+// Mode selects the Chaintracks implementation built by New
+type Mode string
+
+const (
+	ModeEmbedded Mode = "embedded" // Local ChainManager synced over P2P
+	ModeRemote   Mode = "remote"   // Client of a chaintracks server
+	ModeHybrid   Mode = "hybrid"   // Both, combined by a Hybrid
+)
+
+// Config configures New
+type Config struct {
+	Mode Mode // Defaults to ModeEmbedded
+
+	// Embedded and hybrid modes
+	Network       string     // main, test or teratest
+	StoragePath   string     // Directory for header files, peer book and P2P identity
+	BootstrapURLs []string   // Optional teranodes to bulk-sync from before New returns, see BootstrapSync
+	P2P           P2PConfig  // P2P host settings
+	Sync          SyncConfig // Catch-up sync tuning
+	MemoryWindow  uint32     // Most recent headers kept in memory, 0 for all, see SetMemoryWindow
+
+	// Remote and hybrid modes
+	URL          string   // Chaintracks server
+	FallbackURLs []string // Servers to fail over to
+
+	Hybrid HybridConfig // Hybrid mode
+}
+
+// New builds the Chaintracks implementation selected by config.Mode
+func New(ctx context.Context, config Config) (Chaintracks, error) {
+	switch config.Mode {
+	case ModeEmbedded, "":
+		return newEmbedded(ctx, config)
+	case ModeRemote:
+		return newRemote(config)
+	case ModeHybrid:
+		remote, err := newRemote(config)
+		if err != nil {
+			return nil, err
+		}
+		local, err := newEmbedded(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		return NewHybrid(local, remote, config.Hybrid), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMode, config.Mode)
+	}
+}
+
+// newEmbedded creates the ChainManager for embedded and hybrid modes
+func newEmbedded(ctx context.Context, config Config) (*ChainManager, error) {
+	p2pClient, err := NewP2PClient(config.StoragePath, config.Network, config.P2P)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := NewWindowedChainManager(ctx, config.Network, config.StoragePath, p2pClient, config.MemoryWindow)
+	if err != nil {
+		return nil, err
+	}
+	cm.SetSyncConfig(config.Sync)
+	cm.BootstrapSync(ctx, config.BootstrapURLs...)
+	return cm, nil
+}
+
+// newRemote creates the Client for remote and hybrid modes
+func newRemote(config Config) (*Client, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("%w: %s mode requires URL", ErrInvalidConfig, config.Mode)
+	}
+	return NewClient(config.URL, config.FallbackURLs...), nil
+}
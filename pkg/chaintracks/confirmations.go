@@ -0,0 +1,36 @@
+package chaintracks
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// BlockConfirmations reports how deep a block is buried in the main chain
+type BlockConfirmations struct {
+	Height        uint32 `json:"height"`
+	Confirmations uint32 `json:"confirmations"` // 0 when the block is not on the main chain
+	IsOnMainChain bool   `json:"isOnMainChain"`
+}
+
+// GetConfirmations returns the depth of a known block from any Chaintracks implementation
+// The tip itself has one confirmation; returns ErrHeaderNotFound for unknown hashes
+func GetConfirmations(ctx context.Context, ct Chaintracks, blockHash *chainhash.Hash) (*BlockConfirmations, error) {
+	header, err := ct.GetHeaderByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return confirmationsFor(ctx, ct, header), nil
+}
+
+// confirmationsFor computes the depth of a header already looked up by hash
+func confirmationsFor(ctx context.Context, ct Chaintracks, header *BlockHeader) *BlockConfirmations {
+	result := &BlockConfirmations{Height: header.Height}
+	if mainHeader, err := ct.GetHeaderByHeight(ctx, header.Height); err == nil && mainHeader.Hash == header.Hash {
+		result.IsOnMainChain = true
+		if tip := ct.GetHeight(ctx); tip >= header.Height {
+			result.Confirmations = tip - header.Height + 1
+		}
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package chaintracks
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetConfirmations(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+
+	tests := []struct {
+		name     string
+		header   *BlockHeader
+		expected BlockConfirmations
+	}{
+		{name: "TipHasOneConfirmation", header: main[5], expected: BlockConfirmations{Height: 5, Confirmations: 1, IsOnMainChain: true}},
+		{name: "GenesisCountsWholeChain", header: main[0], expected: BlockConfirmations{Height: 0, Confirmations: 6, IsOnMainChain: true}},
+		{name: "OrphanIsNotOnMainChain", header: fork[1], expected: BlockConfirmations{Height: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetConfirmations(t.Context(), cm, &tt.header.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *result)
+		})
+	}
+
+	t.Run("UnknownHashReturnsNotFound", func(t *testing.T) {
+		_, err := GetConfirmations(t.Context(), cm, &chainhash.Hash{0xff})
+		require.ErrorIs(t, err, ErrHeaderNotFound)
+	})
+}
@@ -30,12 +30,42 @@ var (
 	// ErrServerReturnedError is returned when server returns an error status
 	ErrServerReturnedError = errors.New("server returned error status")
 
+	// ErrInvalidBootstrapURL is returned by DownloadHeaders when the URL does not name a CDN metadata file
+	ErrInvalidBootstrapURL = errors.New("invalid bootstrap URL")
+
 	// ErrInvalidFileSize is returned when file size is invalid
 	ErrInvalidFileSize = errors.New("invalid file size")
 
+	// ErrFileHashMismatch is returned when a header file's contents do not match the fileHash in its metadata
+	ErrFileHashMismatch = errors.New("file hash mismatch")
+
+	// ErrMissingFileHash is returned when a CDN metadata entry has no fileHash to verify its file against
+	ErrMissingFileHash = errors.New("missing file hash")
+
+	// ErrInvalidMetadataSignature is returned when CDN metadata is not signed by the configured publisher key
+	ErrInvalidMetadataSignature = errors.New("invalid metadata signature")
+
+	// ErrClientAlreadyStarted is returned by Client.Start while the SSE stream is running
+	ErrClientAlreadyStarted = errors.New("client already started")
+
 	// ErrP2PAlreadyStarted is returned when P2P is already running
 	ErrP2PAlreadyStarted = errors.New("P2P already started")
 
+	// ErrP2PNotStarted is returned by peer management before Start
+	ErrP2PNotStarted = errors.New("P2P not started")
+
+	// ErrInvalidPeerAddress is returned when a peer multiaddr cannot be parsed or lacks a /p2p/ peer ID
+	ErrInvalidPeerAddress = errors.New("invalid peer address")
+
+	// ErrInvalidPeerID is returned when a peer ID cannot be decoded
+	ErrInvalidPeerID = errors.New("invalid peer ID")
+
+	// ErrInvalidP2PConfig is returned when P2P settings are out of range
+	ErrInvalidP2PConfig = errors.New("invalid P2P config")
+
+	// ErrInvalidProofOfWork is returned when a header's hash does not meet its target or the target is out of range
+	ErrInvalidProofOfWork = errors.New("invalid proof of work")
+
 	// ErrInvalidHeaderSize is returned when header size is invalid
 	ErrInvalidHeaderSize = errors.New("invalid header size")
 
@@ -53,4 +83,40 @@ var (
 
 	// ErrIntegerOverflow is returned when an integer overflow would occur
 	ErrIntegerOverflow = errors.New("integer overflow in conversion")
+
+	// ErrInvalidBump is returned when a BUMP cannot be evaluated
+	ErrInvalidBump = errors.New("invalid BUMP")
+
+	// ErrBlockInvalidated is returned when a header is or descends from a block marked invalid by InvalidateBlock
+	ErrBlockInvalidated = errors.New("block invalidated")
+
+	// ErrBlockNotInvalidated is returned when reconsidering a block that is not marked invalid
+	ErrBlockNotInvalidated = errors.New("block not invalidated")
+
+	// ErrInvalidateGenesis is returned when trying to invalidate the genesis block
+	ErrInvalidateGenesis = errors.New("cannot invalidate the genesis block")
+
+	// ErrUnsignedResponse is returned when mutual auth is enabled and the server's response is not signed for the request
+	ErrUnsignedResponse = errors.New("server response not signed")
+
+	// ErrUnknownMode is returned by New for a Mode it does not recognize
+	ErrUnknownMode = errors.New("unknown mode")
+
+	// ErrInvalidConfig is returned by New when a setting the mode needs is missing
+	ErrInvalidConfig = errors.New("invalid config")
+
+	// ErrWireProtocol is returned when a node violates the Bitcoin P2P protocol
+	ErrWireProtocol = errors.New("wire protocol violation")
+
+	// ErrHybridMismatch is returned by a cross-checking Hybrid when its sources return different headers
+	ErrHybridMismatch = errors.New("hybrid sources disagree")
+
+	// ErrSubscriptionReorg is reported on a Subscription's Err channel when a reorg orphans delivered headers
+	ErrSubscriptionReorg = errors.New("reorg replaced delivered headers")
+
+	// ErrNotRegtest is returned when mining blocks on a ChainManager for any network other than regtest
+	ErrNotRegtest = errors.New("not a regtest chain")
+
+	// ErrInvalidMineRequest is returned when a block count, reorg depth or merkle root list cannot be mined
+	ErrInvalidMineRequest = errors.New("invalid mine request")
 )
@@ -0,0 +1,173 @@
+package chaintracks
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+const (
+	// eventBufferSize is the per-subscriber buffer for chain events
+	eventBufferSize = 16
+
+	// maxEventHistory caps the number of sequenced events kept for replay
+	maxEventHistory = 1000
+)
+
+// ChainEventType identifies the kind of chain event
+type ChainEventType string
+
+const (
+	// EventTipAdvanced is emitted when the tip moves forward on the current main chain
+	EventTipAdvanced ChainEventType = "tip"
+
+	// EventReorg is emitted when the main chain switches to a different branch
+	EventReorg ChainEventType = "reorg"
+
+	// EventSyncStatus is emitted when the local chain starts or stops lagging the network
+	EventSyncStatus ChainEventType = "sync"
+
+	// EventSyncProgress is emitted as a bulk header sync from a remote node starts, advances and finishes
+	EventSyncProgress ChainEventType = "sync-progress"
+)
+
+// ReorgInfo describes a chain reorganization
+type ReorgInfo struct {
+	ForkHeight     uint32           `json:"forkHeight"`     // Height of the last block shared by both branches
+	OrphanedHashes []chainhash.Hash `json:"orphanedHashes"` // Former main chain blocks, oldest first
+	NewBranch      []*BlockHeader   `json:"newBranch"`      // Headers of the new main chain after the fork, oldest first
+}
+
+// ChainEvent is a typed notification about a change to the main chain
+// Tip and reorg events carry a sequence number that increases by one per event and survives restarts,
+// so consumers can detect gaps and resume with EventsSince. Sync status events are not sequenced.
+type ChainEvent struct {
+	Seq      uint64         `json:"seq,omitempty"`
+	Type     ChainEventType `json:"type"`
+	Tip      *BlockHeader   `json:"tip"`
+	Reorg    *ReorgInfo     `json:"reorg,omitempty"`    // Set only for EventReorg
+	Lag      *LagStatus     `json:"lag,omitempty"`      // Set for EventSyncStatus and EventSyncProgress
+	Progress *SyncProgress  `json:"progress,omitempty"` // Set only for EventSyncProgress
+	Trace    *LatencyTrace  `json:"-"`                  // Pipeline timestamps when the event came from a P2P announcement
+}
+
+// SubscribeEvents returns a channel of typed chain events
+// The channel is closed when ctx is cancelled. Slow consumers miss events rather than block the chain.
+func (cm *ChainManager) SubscribeEvents(ctx context.Context) <-chan *ChainEvent {
+	ch := make(chan *ChainEvent, eventBufferSize)
+
+	cm.subMu.Lock()
+	if cm.eventSubs == nil {
+		cm.eventSubs = make(map[chan *ChainEvent]struct{})
+	}
+	cm.eventSubs[ch] = struct{}{}
+	cm.subMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		cm.subMu.Lock()
+		delete(cm.eventSubs, ch)
+		cm.subMu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// publishEvent delivers an event to all subscribers and callbacks without blocking
+func (cm *ChainManager) publishEvent(event *ChainEvent) {
+	cm.callbacks.dispatch(event, cm.log())
+
+	cm.subMu.RLock()
+	defer cm.subMu.RUnlock()
+
+	for ch := range cm.eventSubs {
+		select {
+		case ch <- event:
+		default:
+			cm.log().Warn("Dropping event for slow subscriber", "type", event.Type, "seq", event.Seq)
+		}
+	}
+}
+
+// sequenceEvent assigns the next sequence number and records the event for replay (must be called with lock held)
+func (cm *ChainManager) sequenceEvent(event *ChainEvent) {
+	cm.eventSeq++
+	event.Seq = cm.eventSeq
+
+	cm.eventHistory = append(cm.eventHistory, event)
+	if len(cm.eventHistory) > maxEventHistory {
+		cm.eventHistory = cm.eventHistory[len(cm.eventHistory)-maxEventHistory:]
+	}
+}
+
+// LastEventSeq returns the sequence number of the most recent tip or reorg event
+func (cm *ChainManager) LastEventSeq() uint64 {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.eventSeq
+}
+
+// EventsSince returns the sequenced events after seq, oldest first
+// ok is false when events after seq are no longer held in memory and the caller cannot replay without a gap
+func (cm *ChainManager) EventsSince(seq uint64) (events []*ChainEvent, ok bool) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if seq >= cm.eventSeq {
+		return nil, seq == cm.eventSeq
+	}
+	if len(cm.eventHistory) == 0 || cm.eventHistory[0].Seq > seq+1 {
+		return nil, false
+	}
+
+	start := int(seq + 1 - cm.eventHistory[0].Seq) //nolint:gosec // Bounded by history length
+	events = make([]*ChainEvent, len(cm.eventHistory)-start)
+	copy(events, cm.eventHistory[start:])
+	return events, true
+}
+
+// detectReorg compares the current main chain with an incoming branch (must be called with lock held)
+// Returns nil if the branch only extends or re-applies the current main chain
+func (cm *ChainManager) detectReorg(branchHeaders []*BlockHeader) *ReorgInfo {
+	tip := cm.tip.Load()
+	if tip == nil || len(branchHeaders) == 0 {
+		return nil
+	}
+
+	first := branchHeaders[0].Height
+	newTip := branchHeaders[len(branchHeaders)-1].Height
+
+	var orphaned []chainhash.Hash
+	for h := first; h <= tip.Height && int(h) < len(cm.byHeight); h++ {
+		existing := cm.byHeight[h]
+		if h <= newTip && branchHeaders[h-first].Hash == existing {
+			continue
+		}
+		orphaned = append(orphaned, existing)
+	}
+
+	if len(orphaned) == 0 {
+		return nil
+	}
+
+	var forkHeight uint32
+	if first > 0 {
+		forkHeight = first - 1
+	}
+	newBranch := branchHeaders
+	for i, header := range branchHeaders {
+		if int(header.Height) < len(cm.byHeight) && cm.byHeight[header.Height] == header.Hash {
+			forkHeight = header.Height
+			newBranch = branchHeaders[i+1:]
+			continue
+		}
+		break
+	}
+
+	return &ReorgInfo{
+		ForkHeight:     forkHeight,
+		OrphanedHashes: orphaned,
+		NewBranch:      newBranch,
+	}
+}
@@ -0,0 +1,194 @@
+package chaintracks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHeader builds a BlockHeader with a synthetic hash for event tests
+func testHeader(height uint32, tag byte) *BlockHeader {
+	return &BlockHeader{
+		Header: &block.Header{},
+		Height: height,
+		Hash:   chainhash.Hash{tag, byte(height)},
+	}
+}
+
+// receiveEvent waits briefly for the next event
+func receiveEvent(t *testing.T, ch <-chan *ChainEvent) *ChainEvent {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for chain event")
+		return nil
+	}
+}
+
+func TestChainManagerSubscribeEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		branch         []*BlockHeader
+		expectedType   ChainEventType
+		expectedFork   uint32
+		expectedOrphan int
+		expectedNew    int
+	}{
+		{
+			name:         "ExtendingTipEmitsTipAdvanced",
+			branch:       []*BlockHeader{testHeader(3, 1)},
+			expectedType: EventTipAdvanced,
+		},
+		{
+			name:           "CompetingBranchEmitsReorg",
+			branch:         []*BlockHeader{testHeader(2, 2), testHeader(3, 2)},
+			expectedType:   EventReorg,
+			expectedFork:   1,
+			expectedOrphan: 1,
+			expectedNew:    2,
+		},
+		{
+			name:           "ShorterBranchEmitsReorg",
+			branch:         []*BlockHeader{testHeader(1, 2)},
+			expectedType:   EventReorg,
+			expectedFork:   0,
+			expectedOrphan: 2,
+			expectedNew:    1,
+		},
+		{
+			name:           "BranchIncludingSharedHeadersReportsForkPoint",
+			branch:         []*BlockHeader{testHeader(1, 1), testHeader(2, 3)},
+			expectedType:   EventReorg,
+			expectedFork:   1,
+			expectedOrphan: 1,
+			expectedNew:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+			require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(0, 1), testHeader(1, 1), testHeader(2, 1)}))
+
+			events := cm.SubscribeEvents(ctx)
+			require.NoError(t, cm.SetChainTip(ctx, tt.branch))
+
+			event := receiveEvent(t, events)
+			assert.Equal(t, tt.expectedType, event.Type)
+			assert.Equal(t, tt.branch[len(tt.branch)-1].Hash, event.Tip.Hash)
+
+			if tt.expectedType == EventReorg {
+				require.NotNil(t, event.Reorg)
+				assert.Equal(t, tt.expectedFork, event.Reorg.ForkHeight)
+				assert.Len(t, event.Reorg.OrphanedHashes, tt.expectedOrphan)
+				assert.Len(t, event.Reorg.NewBranch, tt.expectedNew)
+			} else {
+				assert.Nil(t, event.Reorg)
+			}
+		})
+	}
+}
+
+func TestChainManagerSubscribeEventsClosesOnCancel(t *testing.T) {
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	events := cm.SubscribeEvents(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "expected channel to be closed")
+	case <-time.After(time.Second):
+		require.FailNow(t, "channel was not closed")
+	}
+}
+
+func TestChainManagerEventSequence(t *testing.T) {
+	ctx := t.Context()
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+	events := cm.SubscribeEvents(ctx)
+
+	require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(0, 1), testHeader(1, 1)}))
+	require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(2, 1)}))
+	require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(2, 2)}))
+
+	for i, expectedType := range []ChainEventType{EventTipAdvanced, EventTipAdvanced, EventReorg} {
+		event := receiveEvent(t, events)
+		assert.Equal(t, uint64(i+1), event.Seq) //nolint:gosec // Test index
+		assert.Equal(t, expectedType, event.Type)
+	}
+	assert.Equal(t, uint64(3), cm.LastEventSeq())
+
+	tests := []struct {
+		name        string
+		since       uint64
+		expectedSeq []uint64
+		expectedOK  bool
+	}{
+		{name: "ReturnsEventsAfterSeq", since: 1, expectedSeq: []uint64{2, 3}, expectedOK: true},
+		{name: "ReturnsAllFromZero", since: 0, expectedSeq: []uint64{1, 2, 3}, expectedOK: true},
+		{name: "UpToDateReturnsNothing", since: 3, expectedOK: true},
+		{name: "FutureSeqIsNotOK", since: 9, expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, ok := cm.EventsSince(tt.since)
+			assert.Equal(t, tt.expectedOK, ok)
+
+			var seqs []uint64
+			for _, event := range events {
+				seqs = append(seqs, event.Seq)
+			}
+			assert.Equal(t, tt.expectedSeq, seqs)
+		})
+	}
+}
+
+func TestChainManagerEventsSinceTrimmedHistory(t *testing.T) {
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+	for i := range maxEventHistory + 5 {
+		cm.sequenceEvent(&ChainEvent{Type: EventTipAdvanced, Tip: testHeader(uint32(i), 1)}) //nolint:gosec // Test index
+	}
+
+	_, ok := cm.EventsSince(2)
+	assert.False(t, ok, "trimmed events cannot be replayed")
+
+	events, ok := cm.EventsSince(5)
+	require.True(t, ok)
+	assert.Len(t, events, maxEventHistory)
+}
+
+func TestChainManagerEventSequencePersistence(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+
+	cm, err := NewChainManager(ctx, "test", dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(0, 1)}))
+	require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(1, 1)}))
+	require.Equal(t, uint64(2), cm.LastEventSeq())
+
+	restored, err := NewChainManager(ctx, "test", dir, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), restored.LastEventSeq(), "loading the files must not sequence events")
+	_, ok := restored.EventsSince(1)
+	assert.False(t, ok, "no events are held to replay after a restart")
+
+	again, err := NewChainManager(ctx, "test", dir, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), again.LastEventSeq(), "restarts must not advance the sequence")
+
+	before := restored.LastEventSeq()
+	require.NoError(t, restored.SetChainTip(ctx, []*BlockHeader{testHeader(2, 1)}))
+	assert.Equal(t, before+1, restored.LastEventSeq())
+}
@@ -0,0 +1,187 @@
+package chaintracks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	endpointCooldownMin = time.Second
+	endpointCooldownMax = time.Minute
+)
+
+// endpoint is one server a Client sends requests to, with its health
+type endpoint struct {
+	url        string
+	mutualAuth *mutualAuthClient // nil unless SetWallet was called
+
+	failures    int       // Consecutive transient failures
+	retryAt     time.Time // Skipped until then, unless every endpoint is
+	lastError   string
+	lastSuccess time.Time
+}
+
+// EndpointStatus is the health of one server, as reported by Client.Endpoints
+type EndpointStatus struct {
+	URL         string    `json:"url"`
+	Healthy     bool      `json:"healthy"`
+	Failures    int       `json:"failures"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+}
+
+// Endpoints reports the health of each server URL in the order given to NewClient
+func (cc *Client) Endpoints() []EndpointStatus {
+	cc.endpointsMu.Lock()
+	defer cc.endpointsMu.Unlock()
+
+	now := time.Now()
+	statuses := make([]EndpointStatus, len(cc.endpoints))
+	for i, ep := range cc.endpoints {
+		statuses[i] = EndpointStatus{
+			URL:         cc.sockets.serverURL(ep.url),
+			Healthy:     !now.Before(ep.retryAt),
+			Failures:    ep.failures,
+			LastError:   ep.lastError,
+			LastSuccess: ep.lastSuccess,
+		}
+	}
+	return statuses
+}
+
+// endpointOrder returns the endpoints to try: healthy ones in configured order, then those cooling
+// down, soonest to recover first. The primary is thus used again as soon as its cooldown ends.
+func (cc *Client) endpointOrder() []*endpoint {
+	cc.endpointsMu.Lock()
+	defer cc.endpointsMu.Unlock()
+
+	now := time.Now()
+	order := make([]*endpoint, 0, len(cc.endpoints))
+	var cooling []*endpoint
+	for _, ep := range cc.endpoints {
+		if now.Before(ep.retryAt) {
+			cooling = append(cooling, ep)
+		} else {
+			order = append(order, ep)
+		}
+	}
+	slices.SortStableFunc(cooling, func(a, b *endpoint) int { return a.retryAt.Compare(b.retryAt) })
+	return append(order, cooling...)
+}
+
+// send sends req to the endpoints in health order, failing over to the next on a transient failure
+func (cc *Client) send(req *http.Request, authenticated bool) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+	var prev *endpoint
+
+	for i, ep := range cc.endpointOrder() {
+		if i > 0 {
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				return resp, err
+			}
+			if resp != nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+			}
+			cc.log().Warn("Failing over", "from", prev.url, "to", ep.url, "error", err)
+		}
+
+		prev = ep
+		resp, err = cc.sendTo(ep, req, authenticated, i > 0)
+		if !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+// sendTo sends req to one endpoint and records the outcome in its health
+// The request is built against the primary URL and rebased onto ep; rewind replays its body.
+func (cc *Client) sendTo(ep *endpoint, req *http.Request, authenticated, rewind bool) (*http.Response, error) {
+	target, err := rebase(req, cc.baseURL, ep.url, rewind)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range cc.headers {
+		if target.Header.Get(name) == "" {
+			target.Header[name] = slices.Clone(values)
+		}
+	}
+
+	var resp *http.Response
+	if authenticated && ep.mutualAuth != nil {
+		resp, err = ep.mutualAuth.do(target)
+	} else {
+		resp, err = cc.httpClient.Do(target)
+	}
+
+	switch {
+	case req.Context().Err() != nil:
+	case retryable(resp, err):
+		cc.recordFailure(ep, resp, err)
+	case err == nil:
+		cc.recordSuccess(ep)
+	}
+	return resp, err
+}
+
+// recordFailure puts an endpoint in a cooldown that doubles with each consecutive failure
+func (cc *Client) recordFailure(ep *endpoint, resp *http.Response, err error) {
+	cc.endpointsMu.Lock()
+	defer cc.endpointsMu.Unlock()
+
+	ep.failures++
+	cooldown := endpointCooldownMin << min(ep.failures-1, 6)
+	ep.retryAt = time.Now().Add(min(cooldown, endpointCooldownMax))
+	if err != nil {
+		ep.lastError = err.Error()
+	} else {
+		ep.lastError = resp.Status
+	}
+}
+
+// recordSuccess marks an endpoint healthy again
+func (cc *Client) recordSuccess(ep *endpoint) {
+	cc.endpointsMu.Lock()
+	defer cc.endpointsMu.Unlock()
+
+	ep.failures = 0
+	ep.retryAt = time.Time{}
+	ep.lastSuccess = time.Now()
+}
+
+// rebase points a request built against base at target instead, with a fresh body when rewind is set
+func rebase(req *http.Request, base, target string, rewind bool) (*http.Request, error) {
+	if base == target && !rewind {
+		return req, nil
+	}
+
+	u, err := url.Parse(target + strings.TrimPrefix(req.URL.String(), base))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.URL = u
+	clone.Host = ""
+	if rewind && req.GetBody != nil {
+		if clone.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return clone, nil
+}
+
+// normalizeBaseURL adds a missing http:// scheme and drops a trailing slash
+func normalizeBaseURL(baseURL string) string {
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
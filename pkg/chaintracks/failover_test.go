@@ -0,0 +1,146 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingServer serves a fixed height from /v2/header/height and headers from /v2/headers/byHashes,
+// failing with 503 while down is set
+type countingServer struct {
+	*httptest.Server
+	height   uint32
+	down     atomic.Bool
+	requests atomic.Int32
+	lastID   atomic.Value
+}
+
+func newCountingServer(t *testing.T, height uint32) *countingServer {
+	t.Helper()
+
+	s := &countingServer{height: height}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.lastID.Store(r.Header.Get("Last-Event-ID"))
+		if s.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/tip/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+		case "/v2/headers/byHashes":
+			var body []string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "value": []map[string]any{{"height": s.height}}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "value": map[string]any{"height": s.height}})
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newFailoverClient returns a client for primary and fallback that fails over without retrying
+func newFailoverClient(primary, fallback *countingServer) *Client {
+	client := NewClient(primary.URL, fallback.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	return client
+}
+
+func TestClientFailover(t *testing.T) {
+	t.Run("FailsOverAndBack", func(t *testing.T) {
+		primary, fallback := newCountingServer(t, 1), newCountingServer(t, 2)
+		client := newFailoverClient(primary, fallback)
+
+		primary.down.Store(true)
+		header, err := client.GetHeaderByHeight(t.Context(), 5)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), header.Height)
+
+		statuses := client.Endpoints()
+		assert.False(t, statuses[0].Healthy)
+		assert.Equal(t, 1, statuses[0].Failures)
+		assert.Equal(t, "503 Service Unavailable", statuses[0].LastError)
+		assert.True(t, statuses[1].Healthy)
+		assert.False(t, statuses[1].LastSuccess.IsZero())
+
+		// The primary is skipped while it cools down
+		header, err = client.GetHeaderByHeight(t.Context(), 5)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), header.Height)
+		assert.Equal(t, int32(1), primary.requests.Load())
+
+		// Once the cooldown ends the primary is probed again and takes over
+		primary.down.Store(false)
+		client.endpoints[0].retryAt = time.Now()
+		header, err = client.GetHeaderByHeight(t.Context(), 5)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), header.Height)
+		assert.Zero(t, client.Endpoints()[0].Failures)
+	})
+
+	t.Run("ReplaysBody", func(t *testing.T) {
+		primary, fallback := newCountingServer(t, 1), newCountingServer(t, 2)
+		client := newFailoverClient(primary, fallback)
+		primary.down.Store(true)
+
+		headers, err := client.GetHeadersByHashes(t.Context(), []chainhash.Hash{{1}})
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), headers[0].Height)
+	})
+
+	t.Run("AllDown", func(t *testing.T) {
+		primary, fallback := newCountingServer(t, 1), newCountingServer(t, 2)
+		client := newFailoverClient(primary, fallback)
+		primary.down.Store(true)
+		fallback.down.Store(true)
+
+		_, err := client.GetHeaderByHeight(t.Context(), 5)
+		require.ErrorIs(t, err, ErrServerRequestFailed)
+		assert.Equal(t, int32(1), primary.requests.Load())
+		assert.Equal(t, int32(1), fallback.requests.Load())
+	})
+
+	t.Run("CooldownGrows", func(t *testing.T) {
+		primary, fallback := newCountingServer(t, 1), newCountingServer(t, 2)
+		client := newFailoverClient(primary, fallback)
+		ep := client.endpoints[0]
+
+		for range 10 {
+			client.recordFailure(ep, &http.Response{Status: "500 Internal Server Error"}, nil)
+		}
+		assert.WithinDuration(t, time.Now().Add(endpointCooldownMax), ep.retryAt, time.Second)
+		assert.Equal(t, fallback.URL, client.endpointOrder()[0].url)
+	})
+}
+
+func TestClientStreamResumesOnlyOnSameServer(t *testing.T) {
+	primary, fallback := newCountingServer(t, 1), newCountingServer(t, 2)
+	client := newFailoverClient(primary, fallback)
+
+	client.lastEventID, client.streamURL = "7", primary.URL
+	body, err := client.connectSSE(t.Context())
+	require.NoError(t, err)
+	_ = body.Close()
+	assert.Equal(t, "7", primary.lastID.Load())
+
+	primary.down.Store(true)
+	_, err = client.connectSSE(t.Context())
+	require.ErrorIs(t, err, ErrSSEStreamFailed)
+
+	body, err = client.connectSSE(t.Context())
+	require.NoError(t, err)
+	_ = body.Close()
+	assert.Empty(t, fallback.lastID.Load(), "event IDs from another server are not sent")
+	assert.Equal(t, fallback.URL, client.streamURL)
+}
@@ -0,0 +1,158 @@
+package chaintracks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFallbackSilence      = 10 * time.Minute
+	defaultFallbackPollInterval = time.Minute
+)
+
+// PollFallbackConfig configures a PollFallback
+type PollFallbackConfig struct {
+	URLs         []string      // Bootstrap endpoints serving /bestblockheader and /headers, tried in order
+	Silence      time.Duration // Time without a P2P block message before polling starts (default 10m)
+	PollInterval time.Duration // Time between checks, and between polls while active (default 1m)
+	Logger       Logger        // Defaults to the package default logger
+}
+
+// PollFallbackStatus reports whether a PollFallback is standing in for P2P
+type PollFallbackStatus struct {
+	Active    bool      `json:"active"`
+	Since     time.Time `json:"since,omitzero"` // When the current active period began
+	Polls     uint64    `json:"polls"`          // Polls made since start, across active periods
+	LastPoll  time.Time `json:"lastPoll,omitzero"`
+	LastError string    `json:"lastError,omitempty"`
+}
+
+// PollFallback polls bootstrap endpoints for catch-up while P2P is silent
+// P2P is silent when no peers are connected or no block topic has delivered a message for Silence, counted from
+// the fallback's creation before the first message. Polls go through SyncFromRemoteTip, so a tip already known
+// costs one request and a reorg is followed as in bootstrap sync.
+type PollFallback struct {
+	cm      *ChainManager
+	config  PollFallbackConfig
+	started time.Time
+
+	mu     sync.RWMutex
+	status PollFallbackStatus
+}
+
+// NewPollFallback creates a PollFallback for cm polling the configured endpoints
+func NewPollFallback(cm *ChainManager, config PollFallbackConfig) (*PollFallback, error) {
+	config.URLs = slices.DeleteFunc(slices.Clone(config.URLs), func(url string) bool { return url == "" })
+	if len(config.URLs) == 0 {
+		return nil, fmt.Errorf("%w: polling fallback requires at least one URL", ErrInvalidConfig)
+	}
+	if config.Silence <= 0 {
+		config.Silence = defaultFallbackSilence
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultFallbackPollInterval
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &PollFallback{cm: cm, config: config, started: time.Now()}, nil
+}
+
+// Run checks every PollInterval until ctx is cancelled
+func (f *PollFallback) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.Check(ctx)
+		}
+	}
+}
+
+// Check polls for catch-up if P2P is silent, reporting whether the fallback is active
+func (f *PollFallback) Check(ctx context.Context) bool {
+	return f.check(ctx, time.Now())
+}
+
+// check runs Check as of now
+func (f *PollFallback) check(ctx context.Context, now time.Time) bool {
+	peers := f.cm.PeerCount()
+	lastMessage := f.lastMessage()
+	silent := peers == 0 || now.Sub(lastMessage) >= f.config.Silence
+
+	f.mu.Lock()
+	switch {
+	case silent && !f.status.Active:
+		f.status.Active = true
+		f.status.Since = now
+		f.config.Logger.Warn("P2P silent, polling fallback active", "peers", peers, "lastMessage", lastMessage.Format(time.RFC3339))
+	case !silent && f.status.Active:
+		f.status.Active = false
+		f.status.Since = time.Time{}
+		f.config.Logger.Info("P2P recovered, polling fallback inactive", "peers", peers, "polls", f.status.Polls)
+	}
+	f.mu.Unlock()
+
+	if !silent {
+		return false
+	}
+
+	err := syncFromFirst(ctx, f.cm, f.config.URLs)
+	if err != nil && ctx.Err() == nil {
+		f.config.Logger.Warn("Polling fallback failed", "error", err)
+	}
+
+	f.mu.Lock()
+	f.status.Polls++
+	f.status.LastPoll = now
+	f.status.LastError = ""
+	if err != nil {
+		f.status.LastError = err.Error()
+	}
+	f.mu.Unlock()
+	return true
+}
+
+// lastMessage returns when a block topic last delivered a message, or the creation time if none has
+func (f *PollFallback) lastMessage() time.Time {
+	last := f.started
+	for _, topic := range f.cm.GetTopicStatus() {
+		if topic.LastMessage.After(last) {
+			last = topic.LastMessage
+		}
+	}
+	return last
+}
+
+// Status returns the fallback's current state
+func (f *PollFallback) Status() PollFallbackStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.status
+}
+
+// syncFromFirst imports the best chain of the first endpoint that answers, returning every endpoint's error if
+// none does
+func syncFromFirst(ctx context.Context, cm *ChainManager, urls []string) error {
+	errs := make([]error, 0, len(urls))
+	for _, url := range urls {
+		hash, err := FetchLatestBlock(ctx, url)
+		if err == nil {
+			err = cm.SyncFromRemoteTip(ctx, hash, url)
+		}
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", url, err))
+	}
+	return errors.Join(errs...)
+}
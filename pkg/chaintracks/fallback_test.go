@@ -0,0 +1,92 @@
+package chaintracks
+
+import (
+	"testing"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollFallbackCheck(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 3, 0)
+	onePeer := []p2p.PeerInfo{{ID: "peer"}}
+
+	tests := []struct {
+		name           string
+		peers          []p2p.PeerInfo
+		messageAge     time.Duration // Age of the last block message at the check, 0 for none
+		elapsed        time.Duration // Time since the fallback was created
+		expectedActive bool
+	}{
+		{name: "PollsWithoutPeers", elapsed: time.Minute, expectedActive: true},
+		{name: "IdleWhileMessagesArrive", peers: onePeer, messageAge: time.Minute, elapsed: time.Hour},
+		{name: "IdleWithinSilenceAfterStart", peers: onePeer, elapsed: 5 * time.Minute},
+		{name: "PollsWhenMessagesStop", peers: onePeer, messageAge: 20 * time.Minute, elapsed: time.Hour, expectedActive: true},
+		{name: "PollsWhenNoMessageSinceStart", peers: onePeer, elapsed: 20 * time.Minute, expectedActive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, headerRequests := newBootstrapServer(t, 0, false, append([]*block.Header{genesis}, chain...)...)
+			cm := newWireChainManager(t, genesis, chain[:1])
+			cm.p2pClient = &stubP2PClient{peers: tt.peers}
+
+			f, err := NewPollFallback(cm, PollFallbackConfig{URLs: []string{node.URL}})
+			require.NoError(t, err)
+			now := f.started.Add(tt.elapsed)
+			if tt.messageAge > 0 {
+				cm.topics.received(BlockTopic("stn", "1.0.0"), "1.0.0", now.Add(-tt.messageAge))
+			}
+
+			assert.Equal(t, tt.expectedActive, f.check(t.Context(), now))
+			status := f.Status()
+			assert.Equal(t, tt.expectedActive, status.Active)
+			if tt.expectedActive {
+				assert.Equal(t, uint64(1), status.Polls)
+				assert.Empty(t, status.LastError)
+				assert.Equal(t, chain[2].Hash(), cm.GetTip(t.Context()).Hash)
+			} else {
+				assert.Zero(t, status.Polls)
+				assert.Equal(t, int32(0), headerRequests.Load())
+				assert.Equal(t, chain[0].Hash(), cm.GetTip(t.Context()).Hash)
+			}
+		})
+	}
+}
+
+func TestPollFallbackRecovers(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	node, _ := newBootstrapServer(t, 0, true, genesis)
+	cm := newWireChainManager(t, genesis, nil)
+	cm.p2pClient = &stubP2PClient{}
+
+	f, err := NewPollFallback(cm, PollFallbackConfig{URLs: []string{node.URL}})
+	require.NoError(t, err)
+
+	now := f.started.Add(time.Minute)
+	require.True(t, f.check(t.Context(), now))
+	assert.Equal(t, now, f.Status().Since)
+
+	cm.p2pClient = &stubP2PClient{peers: []p2p.PeerInfo{{ID: "peer"}}}
+	cm.topics.received(BlockTopic("stn", "1.0.0"), "1.0.0", now)
+	require.False(t, f.check(t.Context(), now.Add(time.Minute)))
+
+	status := f.Status()
+	assert.False(t, status.Active)
+	assert.True(t, status.Since.IsZero())
+	assert.Equal(t, uint64(1), status.Polls)
+}
+
+func TestNewPollFallbackValidatesConfig(t *testing.T) {
+	_, err := NewPollFallback(&ChainManager{}, PollFallbackConfig{URLs: []string{""}})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	f, err := NewPollFallback(&ChainManager{}, PollFallbackConfig{URLs: []string{"http://node"}})
+	require.NoError(t, err)
+	assert.Equal(t, defaultFallbackSilence, f.config.Silence)
+	assert.Equal(t, defaultFallbackPollInterval, f.config.PollInterval)
+}
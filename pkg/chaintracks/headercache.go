@@ -0,0 +1,194 @@
+package chaintracks
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+const (
+	// headerCacheDepth is how far below the streamed tip a header must be before the client caches it
+	headerCacheDepth = 6
+
+	// defaultHeaderCacheSize is how many headers the client caches per index unless SetHeaderCache is called
+	defaultHeaderCacheSize = 10000
+)
+
+// headerCache holds headers buried below the streamed tip, indexed by height and by hash
+// Only headers headerCacheDepth below the tip are cached, so results near the tip are never stale;
+// deeper reorgs announced on the SSE stream invalidate the entries above the fork.
+type headerCache struct {
+	mu       sync.Mutex
+	byHeight *headerLRU[uint32]
+	byHash   *headerLRU[chainhash.Hash]
+}
+
+// newHeaderCache returns a cache of size headers per index expiring after ttl, or nil when size is 0
+func newHeaderCache(size int, ttl time.Duration) *headerCache {
+	if size <= 0 {
+		return nil
+	}
+	return &headerCache{
+		byHeight: newHeaderLRU[uint32](size, ttl),
+		byHash:   newHeaderLRU[chainhash.Hash](size, ttl),
+	}
+}
+
+// SetHeaderCache sizes the client's header cache; call it before Start
+// GetHeaderByHeight, GetHeaderByHash, IsValidRootForHeight and VerifyBump keep up to size headers for
+// each lookup, evicting the least recently used first. A positive ttl also expires headers that old.
+// A size of 0 disables the cache.
+func (cc *Client) SetHeaderCache(size int, ttl time.Duration) {
+	cc.cache = newHeaderCache(size, ttl)
+}
+
+// cachedByHeight returns a cached header at height
+func (cc *Client) cachedByHeight(height uint32) (*BlockHeader, bool) {
+	if cc.cache == nil {
+		return nil, false
+	}
+	cc.cache.mu.Lock()
+	defer cc.cache.mu.Unlock()
+	return cc.cache.byHeight.get(height, time.Now())
+}
+
+// cachedByHash returns a cached header by hash
+func (cc *Client) cachedByHash(hash chainhash.Hash) (*BlockHeader, bool) {
+	if cc.cache == nil {
+		return nil, false
+	}
+	cc.cache.mu.Lock()
+	defer cc.cache.mu.Unlock()
+	return cc.cache.byHash.get(hash, time.Now())
+}
+
+// cacheHeader caches a header fetched from the server if it is buried deep enough below the streamed tip
+// Only headers looked up by height are indexed by height, since a lookup by hash may return a side branch.
+func (cc *Client) cacheHeader(ctx context.Context, header *BlockHeader, mainChain bool) {
+	if cc.cache == nil {
+		return
+	}
+	tip := cc.GetHeight(ctx)
+	if tip < headerCacheDepth || header.Height > tip-headerCacheDepth {
+		return
+	}
+
+	cc.cache.mu.Lock()
+	defer cc.cache.mu.Unlock()
+	now := time.Now()
+	if mainChain {
+		cc.cache.byHeight.add(header.Height, header, now)
+	}
+	if header.Hash != (chainhash.Hash{}) {
+		cc.cache.byHash.add(header.Hash, header, now)
+	}
+}
+
+// invalidateCacheAbove drops cached headers above a reorg's fork height
+func (cc *Client) invalidateCacheAbove(forkHeight uint32) {
+	if cc.cache == nil {
+		return
+	}
+	cc.cache.mu.Lock()
+	defer cc.cache.mu.Unlock()
+
+	above := func(header *BlockHeader) bool { return header.Height > forkHeight }
+	cc.cache.byHeight.removeIf(above)
+	cc.cache.byHash.removeIf(above)
+}
+
+// purge empties the cache, a no-op when disabled
+func (c *headerCache) purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byHeight.purge()
+	c.byHash.purge()
+}
+
+// headerLRU is a least-recently-used map of headers with an optional TTL, not safe for concurrent use
+// Expired entries are dropped when looked up or evicted.
+type headerLRU[K comparable] struct {
+	size  int
+	ttl   time.Duration
+	order *list.List // Most recently used at the front
+	items map[K]*list.Element
+}
+
+// lruEntry is the value stored in a headerLRU list element
+type lruEntry[K comparable] struct {
+	key     K
+	header  *BlockHeader
+	expires time.Time // Zero without a TTL
+}
+
+func newHeaderLRU[K comparable](size int, ttl time.Duration) *headerLRU[K] {
+	return &headerLRU[K]{size: size, ttl: ttl, order: list.New(), items: make(map[K]*list.Element)}
+}
+
+// get returns the header for key and marks it most recently used
+func (l *headerLRU[K]) get(key K, now time.Time) (*BlockHeader, bool) {
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry[K])
+	if !entry.expires.IsZero() && now.After(entry.expires) {
+		l.remove(elem)
+		return nil, false
+	}
+	l.order.MoveToFront(elem)
+	return entry.header, true
+}
+
+// add stores a header, evicting the least recently used entry when full
+func (l *headerLRU[K]) add(key K, header *BlockHeader, now time.Time) {
+	var expires time.Time
+	if l.ttl > 0 {
+		expires = now.Add(l.ttl)
+	}
+
+	if elem, ok := l.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K])
+		entry.header, entry.expires = header, expires
+		l.order.MoveToFront(elem)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry[K]{key: key, header: header, expires: expires})
+	for l.order.Len() > l.size {
+		l.remove(l.order.Back())
+	}
+}
+
+// removeIf drops every entry whose header matches
+func (l *headerLRU[K]) removeIf(match func(*BlockHeader) bool) {
+	for elem := l.order.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*lruEntry[K]).header) {
+			l.remove(elem)
+		}
+		elem = next
+	}
+}
+
+// purge drops every entry
+func (l *headerLRU[K]) purge() {
+	l.order.Init()
+	clear(l.items)
+}
+
+// len returns the number of entries, including expired ones not yet dropped
+func (l *headerLRU[K]) len() int {
+	return l.order.Len()
+}
+
+func (l *headerLRU[K]) remove(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.items, elem.Value.(*lruEntry[K]).key)
+}
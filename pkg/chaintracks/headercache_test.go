@@ -0,0 +1,117 @@
+package chaintracks
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderLRU(t *testing.T) {
+	now := time.Now()
+
+	t.Run("EvictsLeastRecentlyUsed", func(t *testing.T) {
+		l := newHeaderLRU[uint32](2, 0)
+		l.add(1, &BlockHeader{Height: 1}, now)
+		l.add(2, &BlockHeader{Height: 2}, now)
+		_, ok := l.get(1, now)
+		require.True(t, ok)
+
+		l.add(3, &BlockHeader{Height: 3}, now)
+		assert.Equal(t, 2, l.len())
+		_, ok = l.get(2, now)
+		assert.False(t, ok, "2 was least recently used")
+		_, ok = l.get(1, now)
+		assert.True(t, ok)
+	})
+
+	t.Run("Expires", func(t *testing.T) {
+		l := newHeaderLRU[uint32](2, time.Minute)
+		l.add(1, &BlockHeader{Height: 1}, now)
+
+		_, ok := l.get(1, now.Add(59*time.Second))
+		assert.True(t, ok)
+		_, ok = l.get(1, now.Add(61*time.Second))
+		assert.False(t, ok)
+		assert.Zero(t, l.len())
+	})
+
+	t.Run("RemoveIf", func(t *testing.T) {
+		l := newHeaderLRU[uint32](10, 0)
+		for height := range uint32(5) {
+			l.add(height, &BlockHeader{Height: height}, now)
+		}
+		l.removeIf(func(header *BlockHeader) bool { return header.Height > 2 })
+		assert.Equal(t, 3, l.len())
+	})
+}
+
+func TestClientHeaderCache(t *testing.T) {
+	bump, root := testBump(100)
+	hash := chainhash.Hash{1}
+
+	setup := func(t *testing.T) (*Client, *atomic.Int32) {
+		t.Helper()
+
+		var requests atomic.Int32
+		server := newHeaderServer(t, 100, root, &requests)
+		t.Cleanup(server.Close)
+		client := NewClient(server.URL)
+		client.currentTip = &BlockHeader{Height: 200}
+		return client, &requests
+	}
+
+	t.Run("SharedAcrossLookups", func(t *testing.T) {
+		client, requests := setup(t)
+
+		_, err := client.GetHeaderByHeight(t.Context(), 100)
+		require.NoError(t, err)
+		valid, err := client.IsValidRootForHeight(t.Context(), &root, 100)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		valid, err = client.VerifyBump(t.Context(), bump)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("ByHashNotIndexedByHeight", func(t *testing.T) {
+		client, requests := setup(t)
+		client.cacheHeader(t.Context(), &BlockHeader{Height: 100, Hash: hash}, false)
+
+		header, err := client.GetHeaderByHash(t.Context(), &hash)
+		require.NoError(t, err)
+		assert.Equal(t, hash, header.Hash)
+		assert.Zero(t, requests.Load())
+
+		_, err = client.GetHeaderByHeight(t.Context(), 100)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), requests.Load(), "a header looked up by hash may be on a side branch")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		client, requests := setup(t)
+		client.SetHeaderCache(0, 0)
+
+		for range 2 {
+			_, err := client.GetHeaderByHeight(t.Context(), 100)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		client, requests := setup(t)
+		client.SetHeaderCache(10, time.Nanosecond)
+
+		_, err := client.GetHeaderByHeight(t.Context(), 100)
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+		_, err = client.GetHeaderByHeight(t.Context(), 100)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+}
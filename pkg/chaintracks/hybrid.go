@@ -0,0 +1,238 @@
+package chaintracks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// hybridSeenTips is how many recently forwarded tip hashes Hybrid remembers to drop duplicates
+const hybridSeenTips = 16
+
+// HybridConfig configures a Hybrid
+type HybridConfig struct {
+	PreferRemote bool                 // Answer from the remote and fall back to the local chain, instead of the reverse
+	CrossCheck   bool                 // Confirm every header against the other source, rejecting disagreements
+	OnMismatch   func(HybridMismatch) // Optional alert hook, called for each disagreement found by CrossCheck
+	Logger       Logger               // Defaults to the package default logger
+}
+
+// HybridMismatch describes a header the two sources of a Hybrid disagree on
+type HybridMismatch struct {
+	Height        uint32         `json:"height"`
+	PrimaryHash   chainhash.Hash `json:"primaryHash"`
+	SecondaryHash chainhash.Hash `json:"secondaryHash"`
+	DetectedAt    time.Time      `json:"detectedAt"`
+}
+
+// Hybrid answers reads from one Chaintracks and falls back to another when it fails
+// Typically an embedded ChainManager backed by a remote Client, or the reverse with PreferRemote.
+// With CrossCheck a header is only returned when the other source agrees or cannot answer, so a
+// single faulty source cannot feed the caller a wrong merkle root unnoticed.
+type Hybrid struct {
+	primary   Chaintracks
+	secondary Chaintracks
+	config    HybridConfig
+}
+
+// NewHybrid creates a Hybrid over a local and a remote Chaintracks
+func NewHybrid(local, remote Chaintracks, config HybridConfig) *Hybrid {
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+	h := &Hybrid{primary: local, secondary: remote, config: config}
+	if config.PreferRemote {
+		h.primary, h.secondary = remote, local
+	}
+	return h
+}
+
+// Start starts both sources and merges their tip notifications
+// A source that fails to start is logged and skipped; Start fails only when both do. Each tip is
+// delivered once, whichever source announces it first. The channel closes once both sources' channels have.
+func (h *Hybrid) Start(ctx context.Context) (<-chan *BlockHeader, error) {
+	var sources []<-chan *BlockHeader
+	var errs []error
+	for _, ct := range []Chaintracks{h.primary, h.secondary} {
+		tips, err := ct.Start(ctx)
+		if err != nil {
+			h.config.Logger.Warn("Hybrid source failed to start", "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		sources = append(sources, tips)
+	}
+	if len(sources) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	out := make(chan *BlockHeader, 1)
+	merged := make(chan *BlockHeader)
+	var wg sync.WaitGroup
+	for _, tips := range sources {
+		wg.Go(func() {
+			for tip := range tips {
+				select {
+				case merged <- tip:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	go func() {
+		defer close(out)
+		var seen []chainhash.Hash
+		for tip := range merged {
+			if tip == nil || slices.Contains(seen, tip.Hash) {
+				continue
+			}
+			seen = append(seen, tip.Hash)
+			if len(seen) > hybridSeenTips {
+				seen = seen[1:]
+			}
+			select {
+			case out <- tip:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Stop stops both sources
+func (h *Hybrid) Stop() error {
+	return errors.Join(h.primary.Stop(), h.secondary.Stop())
+}
+
+// GetTip returns the primary's tip, or the secondary's while the primary has none
+func (h *Hybrid) GetTip(ctx context.Context) *BlockHeader {
+	if tip := h.primary.GetTip(ctx); tip != nil {
+		return tip
+	}
+	return h.secondary.GetTip(ctx)
+}
+
+// GetHeight returns the height of GetTip
+func (h *Hybrid) GetHeight(ctx context.Context) uint32 {
+	if tip := h.GetTip(ctx); tip != nil {
+		return tip.Height
+	}
+	return 0
+}
+
+// GetHeaderByHeight returns the header at height, cross-checked when configured
+func (h *Hybrid) GetHeaderByHeight(ctx context.Context, height uint32) (*BlockHeader, error) {
+	return h.header(func(ct Chaintracks) (*BlockHeader, error) {
+		return ct.GetHeaderByHeight(ctx, height)
+	})
+}
+
+// GetHeaderByHash returns the header for hash, cross-checked when configured
+func (h *Hybrid) GetHeaderByHash(ctx context.Context, hash *chainhash.Hash) (*BlockHeader, error) {
+	return h.header(func(ct Chaintracks) (*BlockHeader, error) {
+		return ct.GetHeaderByHash(ctx, hash)
+	})
+}
+
+// GetHeadersBackwards returns headers from fromHash back through its ancestors from the primary,
+// falling back to the secondary; the result is not cross-checked
+func (h *Hybrid) GetHeadersBackwards(ctx context.Context, fromHash *chainhash.Hash, count uint32) ([]*BlockHeader, error) {
+	headers, err := h.primary.GetHeadersBackwards(ctx, fromHash, count)
+	if err == nil {
+		return headers, nil
+	}
+	h.config.Logger.Debug("Hybrid primary failed, falling back", "error", err)
+	return h.secondary.GetHeadersBackwards(ctx, fromHash, count)
+}
+
+// GetNetwork returns the primary's network, falling back to the secondary
+func (h *Hybrid) GetNetwork(ctx context.Context) (string, error) {
+	network, err := h.primary.GetNetwork(ctx)
+	if err == nil {
+		return network, nil
+	}
+	return h.secondary.GetNetwork(ctx)
+}
+
+// IsValidRootForHeight implements the ChainTracker interface
+func (h *Hybrid) IsValidRootForHeight(ctx context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	header, err := h.GetHeaderByHeight(ctx, height)
+	if err != nil {
+		return false, err
+	}
+	return header.MerkleRoot.IsEqual(root), nil
+}
+
+// CurrentHeight implements the ChainTracker interface
+func (h *Hybrid) CurrentHeight(ctx context.Context) (uint32, error) {
+	return h.GetHeight(ctx), nil
+}
+
+// PeerCount returns the peers connected by both sources, counting those that report peers
+func (h *Hybrid) PeerCount() int {
+	count := 0
+	for _, ct := range []Chaintracks{h.primary, h.secondary} {
+		if reporter, ok := ct.(PeerReporter); ok {
+			count += reporter.PeerCount()
+		}
+	}
+	return count
+}
+
+// GetPeers returns the peers connected by both sources, primary first
+func (h *Hybrid) GetPeers() []PeerInfo {
+	peers := []PeerInfo{}
+	for _, ct := range []Chaintracks{h.primary, h.secondary} {
+		if reporter, ok := ct.(PeerReporter); ok {
+			peers = append(peers, reporter.GetPeers()...)
+		}
+	}
+	return peers
+}
+
+// header runs lookup against the primary, falling back to the secondary, and cross-checks the answer
+// A cross-check the secondary cannot answer is skipped; a different hash fails with ErrHybridMismatch.
+func (h *Hybrid) header(lookup func(Chaintracks) (*BlockHeader, error)) (*BlockHeader, error) {
+	header, err := lookup(h.primary)
+	if err != nil {
+		h.config.Logger.Debug("Hybrid primary failed, falling back", "error", err)
+		return lookup(h.secondary)
+	}
+	if !h.config.CrossCheck {
+		return header, nil
+	}
+
+	other, err := lookup(h.secondary)
+	if err != nil {
+		h.config.Logger.Debug("Hybrid cross-check skipped", "height", header.Height, "error", err)
+		return header, nil
+	}
+	if other.Hash == header.Hash {
+		return header, nil
+	}
+
+	mismatch := HybridMismatch{
+		Height:        header.Height,
+		PrimaryHash:   header.Hash,
+		SecondaryHash: other.Hash,
+		DetectedAt:    time.Now(),
+	}
+	h.config.Logger.Warn("Hybrid sources disagree", "height", mismatch.Height,
+		"primary", mismatch.PrimaryHash, "secondary", mismatch.SecondaryHash)
+	if h.config.OnMismatch != nil {
+		h.config.OnMismatch(mismatch)
+	}
+	return nil, fmt.Errorf("%w at height %d: %s vs %s", ErrHybridMismatch, mismatch.Height, mismatch.PrimaryHash, mismatch.SecondaryHash)
+}
@@ -0,0 +1,144 @@
+package chaintracks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSourceDown = errors.New("source down")
+
+// hybridSource is a ChainManager whose Start and Stop are replaced by a test channel
+type hybridSource struct {
+	*ChainManager
+	tips     chan *BlockHeader
+	startErr error
+	stopped  bool
+}
+
+func newHybridSource(count int) *hybridSource {
+	return &hybridSource{ChainManager: newLinearChainManager(count), tips: make(chan *BlockHeader, 4)}
+}
+
+func (s *hybridSource) Start(context.Context) (<-chan *BlockHeader, error) {
+	return s.tips, s.startErr
+}
+
+func (s *hybridSource) Stop() error {
+	s.stopped = true
+	return nil
+}
+
+func TestHybridReads(t *testing.T) {
+	t.Run("PrefersLocal", func(t *testing.T) {
+		local, remote := newHybridSource(3), newHybridSource(5)
+		h := NewHybrid(local, remote, HybridConfig{})
+
+		assert.Equal(t, uint32(2), h.GetHeight(t.Context()))
+		header, err := h.GetHeaderByHeight(t.Context(), 4)
+		require.NoError(t, err, "heights the local chain lacks come from the remote")
+		assert.Equal(t, chainhash.Hash{5}, header.Hash)
+	})
+
+	t.Run("PreferRemote", func(t *testing.T) {
+		local, remote := newHybridSource(3), newHybridSource(5)
+		h := NewHybrid(local, remote, HybridConfig{PreferRemote: true})
+		assert.Equal(t, uint32(4), h.GetHeight(t.Context()))
+	})
+
+	t.Run("CrossCheckAgrees", func(t *testing.T) {
+		h := NewHybrid(newHybridSource(3), newHybridSource(2), HybridConfig{CrossCheck: true})
+
+		valid, err := h.IsValidRootForHeight(t.Context(), &chainhash.Hash{}, 1)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		_, err = h.GetHeaderByHeight(t.Context(), 2)
+		require.NoError(t, err, "a cross-check the secondary cannot answer is skipped")
+	})
+
+	t.Run("CrossCheckMismatch", func(t *testing.T) {
+		local, remote := newHybridSource(3), newHybridSource(3)
+		forged := &BlockHeader{Header: &block.Header{}, Height: 1, Hash: chainhash.Hash{0xee}}
+		remote.byHeight[1] = forged.Hash
+		remote.byHash[forged.Hash] = forged
+
+		var mismatches []HybridMismatch
+		h := NewHybrid(local, remote, HybridConfig{
+			CrossCheck: true,
+			OnMismatch: func(m HybridMismatch) { mismatches = append(mismatches, m) },
+		})
+
+		_, err := h.GetHeaderByHeight(t.Context(), 1)
+		require.ErrorIs(t, err, ErrHybridMismatch)
+		require.Len(t, mismatches, 1)
+		assert.Equal(t, chainhash.Hash{2}, mismatches[0].PrimaryHash)
+		assert.Equal(t, forged.Hash, mismatches[0].SecondaryHash)
+	})
+}
+
+func TestHybridStart(t *testing.T) {
+	t.Run("MergesAndDeduplicates", func(t *testing.T) {
+		local, remote := newHybridSource(1), newHybridSource(1)
+		h := NewHybrid(local, remote, HybridConfig{})
+		tips, err := h.Start(t.Context())
+		require.NoError(t, err)
+
+		first, second := &BlockHeader{Height: 1, Hash: chainhash.Hash{1}}, &BlockHeader{Height: 2, Hash: chainhash.Hash{2}}
+		local.tips <- first
+		assert.Equal(t, first, <-tips)
+		remote.tips <- first
+		remote.tips <- second
+		assert.Equal(t, second, <-tips, "a tip already delivered by the other source is dropped")
+
+		close(local.tips)
+		close(remote.tips)
+		_, ok := <-tips
+		assert.False(t, ok)
+
+		require.NoError(t, h.Stop())
+		assert.True(t, local.stopped)
+		assert.True(t, remote.stopped)
+	})
+
+	t.Run("OneSourceDown", func(t *testing.T) {
+		local, remote := newHybridSource(1), newHybridSource(1)
+		remote.startErr = errSourceDown
+		_, err := NewHybrid(local, remote, HybridConfig{}).Start(t.Context())
+		require.NoError(t, err)
+
+		local.startErr = errSourceDown
+		_, err = NewHybrid(local, remote, HybridConfig{}).Start(t.Context())
+		require.ErrorIs(t, err, errSourceDown)
+	})
+}
+
+func TestHybridPeers(t *testing.T) {
+	local, remote := newHybridSource(1), newHybridSource(1)
+	local.p2pClient = &stubP2PClient{peers: []p2p.PeerInfo{{ID: "a"}, {ID: "b"}}}
+	var ct Chaintracks = NewHybrid(local, remote, HybridConfig{})
+
+	reporter, ok := ct.(PeerReporter)
+	require.True(t, ok)
+	assert.Equal(t, 2, reporter.PeerCount())
+	peers := reporter.GetPeers()
+	require.Len(t, peers, 2)
+	assert.Equal(t, "a", peers[0].ID)
+}
+
+func TestNewMode(t *testing.T) {
+	ct, err := New(t.Context(), Config{Mode: ModeRemote, URL: "localhost:3011"})
+	require.NoError(t, err)
+	assert.IsType(t, &Client{}, ct)
+
+	_, err = New(t.Context(), Config{Mode: ModeHybrid})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = New(t.Context(), Config{Mode: "sideways"})
+	require.ErrorIs(t, err, ErrUnknownMode)
+}
@@ -0,0 +1,104 @@
+package chaintracks
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+)
+
+// pendingValidations bounds how many received announcements may wait to be applied
+// The P2P reader only blocks once this many are queued behind a slow apply
+const pendingValidations = 256
+
+// ingestResult is a validated (or rejected) announcement waiting to be applied
+type ingestResult struct {
+	fromID   string
+	version  string
+	blockMsg *BlockMessage
+	header   *block.Header
+	trace    *LatencyTrace
+	err      error
+}
+
+// runIngest validates received announcements on a bounded worker pool and applies them in arrival order
+// Decoding and proof-of-work checks run in parallel; linking into the chain stays sequential, so
+// headers of the same branch are always applied parent first. Closes the tip channel when done.
+func (cm *ChainManager) runIngest(ctx context.Context, incoming <-chan topicMessage, book *PeerBook, tips chan *BlockHeader) {
+	workers := make(chan struct{}, max(runtime.GOMAXPROCS(0), 1))
+	pending := make(chan chan ingestResult, pendingValidations)
+	applied := make(chan struct{})
+
+	go func() {
+		defer close(applied)
+		cm.applyIngested(ctx, pending, book)
+	}()
+	defer func() {
+		close(pending)
+		<-applied
+		close(tips)
+	}()
+
+	for {
+		var tm topicMessage
+		select {
+		case <-ctx.Done():
+			return
+		case tm = <-incoming:
+		}
+		if book.isBanned(tm.msg.FromID) {
+			continue
+		}
+		trace := &LatencyTrace{ReceivedAt: time.Now()}
+
+		select {
+		case <-ctx.Done():
+			return
+		case workers <- struct{}{}:
+		}
+
+		result := make(chan ingestResult, 1)
+		go func() {
+			defer func() { <-workers }()
+			result <- cm.validateTopicMessage(tm, trace)
+		}()
+
+		select {
+		case <-ctx.Done():
+			return
+		case pending <- result:
+		}
+	}
+}
+
+// validateTopicMessage decodes and validates one received message
+func (cm *ChainManager) validateTopicMessage(tm topicMessage, trace *LatencyTrace) ingestResult {
+	result := ingestResult{fromID: tm.msg.FromID, version: tm.version.version, trace: trace}
+	cm.log().Debug("Raw block message", "peer", tm.msg.FromID, "version", tm.version.version, "data", string(tm.msg.Data))
+	result.blockMsg, result.err = tm.version.decode(tm.msg.Data)
+	if result.err == nil {
+		result.header, result.err = cm.validateBlockMessage(result.blockMsg)
+	}
+	return result
+}
+
+// applyIngested links validated announcements into the chain in the order they were received
+func (cm *ChainManager) applyIngested(ctx context.Context, pending <-chan chan ingestResult, book *PeerBook) {
+	for next := range pending {
+		result := <-next
+		if ctx.Err() != nil {
+			continue
+		}
+
+		err := result.err
+		if err == nil {
+			book.recordAnnouncement(result.fromID, result.header.Hash(), result.trace.ReceivedAt)
+			err = cm.handleBlockMessage(withLatencyTrace(ctx, result.trace), result.blockMsg, result.header)
+		}
+		book.recordBlock(result.fromID, err == nil, time.Now())
+		if err != nil {
+			cm.log().Error("Failed to handle block message", "version", result.version, "peer", result.fromID, "error", err)
+		}
+	}
+}
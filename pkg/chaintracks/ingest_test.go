@@ -0,0 +1,120 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mineRegtestHeader returns a child of parent that satisfies the regtest proof-of-work limit
+func mineRegtestHeader(t *testing.T, parent chainhash.Hash) *block.Header {
+	t.Helper()
+
+	header := &block.Header{Version: 1, PrevHash: parent, Bits: regtestPowLimitBits}
+	for CheckProofOfWork(header, regtestPowLimitBits) != nil {
+		header.Nonce++
+	}
+	return header
+}
+
+// announce wraps a header in a 1.0.0 block message from a peer
+func announce(t *testing.T, from string, height uint32, header *block.Header) topicMessage {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{"Height": height, "Header": hex.EncodeToString(header.Bytes())})
+	require.NoError(t, err)
+	return topicMessage{version: &blockTopicVersions[0], msg: p2p.Message{FromID: from, Data: data}}
+}
+
+func TestRunIngestAppliesInOrderAndRecordsPeers(t *testing.T) {
+	genesis := &BlockHeader{Header: &block.Header{Bits: regtestPowLimitBits}, ChainWork: big.NewInt(1)}
+	genesis.Hash = genesis.Header.Hash()
+
+	tips := make(chan *BlockHeader, 1)
+	cm := &ChainManager{network: "regtest", byHash: make(map[chainhash.Hash]*BlockHeader), msgChan: tips}
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{genesis}))
+
+	child := mineRegtestHeader(t, genesis.Hash)
+	grandchild := mineRegtestHeader(t, child.Hash())
+	unmined := &block.Header{Version: 1, PrevHash: genesis.Hash, Bits: 0x1d00ffff}
+
+	book := &PeerBook{peers: make(map[string]*PeerRecord)}
+	incoming := make(chan topicMessage, 4)
+	incoming <- topicMessage{version: &blockTopicVersions[0], msg: p2p.Message{FromID: "bad", Data: []byte(`{"Height":`)}}
+	incoming <- announce(t, "good", 1, child)
+	incoming <- announce(t, "good", 2, grandchild)
+	incoming <- announce(t, "bad", 1, unmined)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		cm.runIngest(ctx, incoming, book, tips)
+		close(done)
+	}()
+
+	peerBlocks := func(id string) (uint64, uint64) {
+		book.mu.Lock()
+		defer book.mu.Unlock()
+		if record, ok := book.peers[id]; ok {
+			return record.ValidBlocks, record.InvalidBlocks
+		}
+		return 0, 0
+	}
+
+	assert.Eventually(t, func() bool {
+		_, badInvalid := peerBlocks("bad")
+		return cm.GetHeight(t.Context()) == 2 && badInvalid == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	goodValid, goodInvalid := peerBlocks("good")
+	assert.Equal(t, uint64(2), goodValid)
+	assert.Zero(t, goodInvalid)
+	assert.Equal(t, grandchild.Hash(), cm.GetTip(t.Context()).Hash)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "runIngest did not return after cancellation")
+	}
+	for range tips { //nolint:revive // Drain until runIngest closes the channel
+	}
+}
+
+func TestValidateBlockMessage(t *testing.T) {
+	mined := mineRegtestHeader(t, chainhash.Hash{})
+
+	tests := []struct {
+		name      string
+		network   string
+		header    string
+		expectErr error
+	}{
+		{name: "MinedHeaderOnRegtest", network: "regtest", header: hex.EncodeToString(mined.Bytes())},
+		{name: "RegtestWorkRejectedOnMainnet", network: "main", header: hex.EncodeToString(mined.Bytes()), expectErr: ErrInvalidProofOfWork},
+		{name: "WrongSize", network: "regtest", header: "00", expectErr: ErrInvalidHeaderSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &ChainManager{network: tt.network}
+			header, err := cm.validateBlockMessage(&BlockMessage{Header: tt.header})
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, mined.Hash(), header.Hash())
+		})
+	}
+}
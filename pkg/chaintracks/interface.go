@@ -32,6 +32,24 @@ type Chaintracks interface {
 	// GetHeaderByHash retrieves a block header by its hash
 	GetHeaderByHash(ctx context.Context, hash *chainhash.Hash) (*BlockHeader, error)
 
+	// GetHeadersBackwards returns up to count headers from fromHash back through its ancestors, newest first
+	GetHeadersBackwards(ctx context.Context, fromHash *chainhash.Hash, count uint32) ([]*BlockHeader, error)
+
 	// GetNetwork returns the network name (mainnet, testnet, etc.)
 	GetNetwork(ctx context.Context) (string, error)
 }
+
+// PeerReporter is implemented by Chaintracks that track P2P peers, such as ChainManager and a Hybrid over one
+// Callers holding a Chaintracks check for it instead of asserting a concrete type; a remote Client has no peers.
+type PeerReporter interface {
+	// PeerCount returns the number of connected peers
+	PeerCount() int
+
+	// GetPeers returns the connected peers
+	GetPeers() []PeerInfo
+}
+
+var (
+	_ PeerReporter = (*ChainManager)(nil)
+	_ PeerReporter = (*Hybrid)(nil)
+)
@@ -0,0 +1,329 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// InvalidateBlock marks a block invalid, like bitcoind's invalidateblock, so the chain never builds on it
+// If the block is on the main chain the tip rewinds to its parent, or moves to the side branch with the
+// most work that has no invalid blocks if that has more work than the parent. The mark is persisted, and
+// headers descending from the block are rejected until ReconsiderBlock clears it.
+func (cm *ChainManager) InvalidateBlock(ctx context.Context, hash *chainhash.Hash) error {
+	cm.adminMu.Lock()
+	defer cm.adminMu.Unlock()
+
+	cm.mu.Lock()
+	header, ok := cm.headerByHash(*hash)
+	if !ok {
+		cm.mu.Unlock()
+		return ErrHeaderNotFound
+	}
+	if header.Height == 0 {
+		cm.mu.Unlock()
+		return ErrInvalidateGenesis
+	}
+	if cm.invalidated == nil {
+		cm.invalidated = make(map[chainhash.Hash]struct{})
+	}
+	cm.invalidated[*hash] = struct{}{}
+	hashes := cm.invalidatedHashes()
+	update, moved := cm.applyBestValidBranch(ctx)
+	cm.mu.Unlock()
+
+	cm.log().Warn("Block invalidated", "hash", hash, "height", header.Height)
+	return cm.finishInvalidation(ctx, update, moved, hashes)
+}
+
+// ReconsiderBlock clears an invalid mark set by InvalidateBlock, like bitcoind's reconsiderblock
+// Marks on the block's known descendants are cleared too, and the chain moves to the reconsidered
+// branch if it now has the most work.
+func (cm *ChainManager) ReconsiderBlock(ctx context.Context, hash *chainhash.Hash) error {
+	cm.adminMu.Lock()
+	defer cm.adminMu.Unlock()
+
+	cm.mu.Lock()
+	if _, ok := cm.invalidated[*hash]; !ok {
+		cm.mu.Unlock()
+		return ErrBlockNotInvalidated
+	}
+	delete(cm.invalidated, *hash)
+	if header, ok := cm.headerByHash(*hash); ok {
+		for other := range cm.invalidated {
+			if descendant, ok := cm.headerByHash(other); ok && cm.descendsFrom(descendant, header) {
+				delete(cm.invalidated, other)
+			}
+		}
+	}
+	hashes := cm.invalidatedHashes()
+	update, moved := cm.applyBestValidBranch(ctx)
+	cm.mu.Unlock()
+
+	cm.log().Info("Block reconsidered", "hash", hash)
+	return cm.finishInvalidation(ctx, update, moved, hashes)
+}
+
+// applyBestValidBranch moves the tip to the best chain without invalid blocks (must be called with lock held)
+// Choosing and applying the branch under one lock keeps a tip update from P2P or sync landing in between and
+// being overwritten by a stale branch. moved is false when the tip already is on that chain.
+func (cm *ChainManager) applyBestValidBranch(ctx context.Context) (update tipUpdate, moved bool) {
+	branch := cm.bestValidBranch()
+	if len(branch) == 0 {
+		return tipUpdate{}, false
+	}
+	return cm.applyBranch(ctx, branch), true
+}
+
+// finishInvalidation completes a tip move made by applyBestValidBranch and persists the invalidated blocks
+func (cm *ChainManager) finishInvalidation(ctx context.Context, update tipUpdate, moved bool, hashes []chainhash.Hash) error {
+	var err error
+	if moved {
+		err = cm.finishTipUpdate(ctx, update)
+	}
+	return errors.Join(err, cm.saveInvalidated(hashes))
+}
+
+// applyLoadedInvalidated moves a chain loaded from disk off blocks invalidated in an earlier run
+// Rewinds are written to disk as they happen, so this only moves the tip if a run stopped before its rewind
+// reached the files.
+func (cm *ChainManager) applyLoadedInvalidated(ctx context.Context) error {
+	cm.mu.Lock()
+	if len(cm.invalidated) == 0 {
+		cm.mu.Unlock()
+		return nil
+	}
+	update, moved := cm.applyBestValidBranch(ctx)
+	cm.mu.Unlock()
+
+	if !moved {
+		return nil
+	}
+	return cm.finishTipUpdate(ctx, update)
+}
+
+// GetInvalidatedBlocks returns the hashes marked invalid by InvalidateBlock
+func (cm *ChainManager) GetInvalidatedBlocks() []chainhash.Hash {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.invalidatedHashes()
+}
+
+// ClearOrphans removes every known header that is not on the main chain and returns how many were removed
+// Orphans are otherwise only pruned once they fall 100 blocks behind the tip.
+func (cm *ChainManager) ClearOrphans() int {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	removed := 0
+	for hash, header := range cm.byHash {
+		if !cm.isMainChain(header) {
+			delete(cm.byHash, hash)
+			removed++
+		}
+	}
+	if removed > 0 {
+		cm.log().Info("Cleared orphaned headers", "count", removed)
+	}
+	return removed
+}
+
+// checkNotInvalidated rejects a branch that contains or descends from an invalidated block (must be called with lock held)
+func (cm *ChainManager) checkNotInvalidated(branchHeaders []*BlockHeader) error {
+	if len(cm.invalidated) == 0 {
+		return nil
+	}
+	for _, header := range branchHeaders {
+		if _, bad := cm.invalidated[header.Hash]; bad {
+			return fmt.Errorf("%w: %s", ErrBlockInvalidated, header.Hash)
+		}
+	}
+	if parent, ok := cm.headerByHash(branchHeaders[0].PrevHash); ok && branchHeaders[0].Height > 0 && cm.isInvalid(parent, cm.mainHeight()) {
+		return fmt.Errorf("%w: %s descends from an invalidated block", ErrBlockInvalidated, branchHeaders[0].Hash)
+	}
+	return nil
+}
+
+// isInvalid reports whether a header is or descends from an invalidated block (must be called with lock held)
+// Main chain headers up to validHeight are trusted, which ends the walk at the fork point.
+func (cm *ChainManager) isInvalid(header *BlockHeader, validHeight uint32) bool {
+	if len(cm.invalidated) == 0 {
+		return false
+	}
+	for walk := header; ; {
+		if _, bad := cm.invalidated[walk.Hash]; bad {
+			return true
+		}
+		if walk.Height <= validHeight && cm.isMainChain(walk) {
+			return false
+		}
+		parent, err := cm.parentOf(walk)
+		if err != nil {
+			return false
+		}
+		walk = parent
+	}
+}
+
+// descendsFrom reports whether header is ancestor or one of its known descendants (must be called with lock held)
+func (cm *ChainManager) descendsFrom(header, ancestor *BlockHeader) bool {
+	for walk := header; walk.Height >= ancestor.Height; {
+		if walk.Hash == ancestor.Hash {
+			return true
+		}
+		parent, err := cm.parentOf(walk)
+		if err != nil {
+			return false
+		}
+		walk = parent
+	}
+	return false
+}
+
+// bestValidBranch returns the branch applyBranch needs to move to the best chain without invalid blocks,
+// or nil if the current tip already is (must be called with lock held)
+func (cm *ChainManager) bestValidBranch() []*BlockHeader {
+	tip := cm.tip.Load()
+	if tip == nil {
+		return nil
+	}
+
+	// The main chain is kept up to its first invalid block
+	base := tip
+	for hash := range cm.invalidated {
+		if header, ok := cm.headerByHash(hash); ok && cm.isMainChain(header) && header.Height <= base.Height {
+			if parent, err := cm.parentOf(header); err == nil {
+				base = parent
+			}
+		}
+	}
+
+	hasChild := make(map[chainhash.Hash]bool, len(cm.byHash))
+	for _, header := range cm.byHash {
+		if header.Header != nil {
+			hasChild[header.PrevHash] = true
+		}
+	}
+
+	best, bestWork := base, chainWorkOf(base)
+	for hash, header := range cm.byHash {
+		if hasChild[hash] || cm.isMainChain(header) || header.ChainWork == nil {
+			continue
+		}
+		if header.ChainWork.Cmp(bestWork) <= 0 || cm.isInvalid(header, base.Height) {
+			continue
+		}
+		best, bestWork = header, header.ChainWork
+	}
+
+	if best == tip {
+		return nil
+	}
+	if best == base {
+		return []*BlockHeader{base}
+	}
+
+	// Collect the side branch back to where it leaves the valid main chain
+	var branch []*BlockHeader
+	for walk := best; walk.Height > base.Height || !cm.isMainChain(walk); {
+		branch = append(branch, walk)
+		parent, err := cm.parentOf(walk)
+		if err != nil {
+			return []*BlockHeader{base}
+		}
+		walk = parent
+	}
+	slices.Reverse(branch)
+	return branch
+}
+
+// mainHeight returns the height of the main chain tip (must be called with lock held)
+func (cm *ChainManager) mainHeight() uint32 {
+	if tip := cm.tip.Load(); tip != nil {
+		return tip.Height
+	}
+	return 0
+}
+
+// chainWorkOf returns a header's cumulative work, treating unknown work as zero
+func chainWorkOf(header *BlockHeader) *big.Int {
+	if header.ChainWork == nil {
+		return new(big.Int)
+	}
+	return header.ChainWork
+}
+
+// invalidatedHashes returns the invalidated hashes in a stable order (must be called with lock held)
+func (cm *ChainManager) invalidatedHashes() []chainhash.Hash {
+	hashes := make([]chainhash.Hash, 0, len(cm.invalidated))
+	for hash := range cm.invalidated {
+		hashes = append(hashes, hash)
+	}
+	slices.SortFunc(hashes, func(a, b chainhash.Hash) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return hashes
+}
+
+// invalidatedPath returns the path of the persisted invalidated block list
+func (cm *ChainManager) invalidatedPath() string {
+	return filepath.Join(cm.localStoragePath, cm.network+"NetInvalidated.json")
+}
+
+// saveInvalidated persists the invalidated block list
+func (cm *ChainManager) saveInvalidated(hashes []chainhash.Hash) error {
+	if cm.localStoragePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(cm.localStoragePath, 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal invalidated blocks: %w", err)
+	}
+	if err := os.WriteFile(cm.invalidatedPath(), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write invalidated blocks: %w", err)
+	}
+	return nil
+}
+
+// loadInvalidated restores the invalidated block list from disk
+func (cm *ChainManager) loadInvalidated() error {
+	if cm.localStoragePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(cm.invalidatedPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read invalidated blocks: %w", err)
+	}
+
+	var hashes []chainhash.Hash
+	if err := json.Unmarshal(data, &hashes); err != nil {
+		return fmt.Errorf("failed to parse invalidated blocks: %w", err)
+	}
+
+	cm.mu.Lock()
+	cm.invalidated = make(map[chainhash.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		cm.invalidated[hash] = struct{}{}
+	}
+	cm.mu.Unlock()
+
+	if len(hashes) > 0 {
+		cm.log().Warn("Loaded invalidated blocks", "count", len(hashes))
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package chaintracks
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWorkedForkChainManager is newForkedChainManager with chain work: 10 per main block, 35 and 45 on the fork
+// The fork outweighs the main chain below height 5, so it wins once main[3] or main[4] is invalidated.
+func newWorkedForkChainManager(t *testing.T) (*ChainManager, []*BlockHeader, []*BlockHeader) {
+	t.Helper()
+
+	cm, main, fork := newForkedChainManager(t)
+	for i, header := range main {
+		header.ChainWork = big.NewInt(int64(i * 10))
+	}
+	fork[0].ChainWork, fork[1].ChainWork = big.NewInt(35), big.NewInt(45)
+	return cm, main, fork
+}
+
+func TestChainManagerInvalidateBlock(t *testing.T) {
+	t.Run("RewindsToParent", func(t *testing.T) {
+		cm, main, _ := newForkedChainManager(t)
+		require.NoError(t, cm.InvalidateBlock(t.Context(), &main[4].Hash))
+
+		assert.Equal(t, main[3].Hash, cm.GetTip(t.Context()).Hash)
+		assert.Equal(t, []chainhash.Hash{main[4].Hash}, cm.GetInvalidatedBlocks())
+
+		tips := cm.GetChainTips(t.Context())
+		assert.Contains(t, tips, ChainTip{Height: 5, Hash: main[5].Hash, BranchLen: 2, Status: ChainTipInvalid})
+	})
+
+	t.Run("MovesToBestValidBranch", func(t *testing.T) {
+		cm, main, fork := newWorkedForkChainManager(t)
+		events := cm.SubscribeEvents(t.Context())
+
+		require.NoError(t, cm.InvalidateBlock(t.Context(), &main[3].Hash))
+
+		assert.Equal(t, fork[1].Hash, cm.GetTip(t.Context()).Hash)
+		event := <-events
+		assert.Equal(t, EventReorg, event.Type)
+		assert.Equal(t, []chainhash.Hash{main[3].Hash, main[4].Hash, main[5].Hash}, event.Reorg.OrphanedHashes)
+	})
+
+	t.Run("RejectsDescendants", func(t *testing.T) {
+		cm, main, _ := newForkedChainManager(t)
+		require.NoError(t, cm.InvalidateBlock(t.Context(), &main[4].Hash))
+
+		err := cm.SetChainTip(t.Context(), []*BlockHeader{linkedHeader(main[5], 1)})
+		require.ErrorIs(t, err, ErrBlockInvalidated)
+		err = cm.SetChainTip(t.Context(), []*BlockHeader{main[4]})
+		require.ErrorIs(t, err, ErrBlockInvalidated)
+
+		next := linkedHeader(main[3], 9)
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{next}))
+		assert.Equal(t, next.Hash, cm.GetTip(t.Context()).Hash)
+	})
+
+	t.Run("SideBranchLeavesTip", func(t *testing.T) {
+		cm, main, fork := newForkedChainManager(t)
+		require.NoError(t, cm.InvalidateBlock(t.Context(), &fork[0].Hash))
+		assert.Equal(t, main[5].Hash, cm.GetTip(t.Context()).Hash)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cm, main, _ := newForkedChainManager(t)
+		unknown := chainhash.Hash{0xff}
+		require.ErrorIs(t, cm.InvalidateBlock(t.Context(), &unknown), ErrHeaderNotFound)
+		require.ErrorIs(t, cm.InvalidateBlock(t.Context(), &main[0].Hash), ErrInvalidateGenesis)
+	})
+}
+
+func TestChainManagerReconsiderBlock(t *testing.T) {
+	t.Run("RestoresMostWorkChain", func(t *testing.T) {
+		cm, main, fork := newWorkedForkChainManager(t)
+		require.NoError(t, cm.InvalidateBlock(t.Context(), &main[3].Hash))
+		require.Equal(t, fork[1].Hash, cm.GetTip(t.Context()).Hash)
+
+		require.NoError(t, cm.ReconsiderBlock(t.Context(), &main[3].Hash))
+		assert.Equal(t, main[5].Hash, cm.GetTip(t.Context()).Hash)
+		assert.Empty(t, cm.GetInvalidatedBlocks())
+	})
+
+	t.Run("ClearsDescendantMarks", func(t *testing.T) {
+		cm, main, _ := newForkedChainManager(t)
+		require.NoError(t, cm.InvalidateBlock(t.Context(), &main[5].Hash))
+		require.NoError(t, cm.InvalidateBlock(t.Context(), &main[3].Hash))
+
+		require.NoError(t, cm.ReconsiderBlock(t.Context(), &main[3].Hash))
+		assert.Empty(t, cm.GetInvalidatedBlocks())
+	})
+
+	t.Run("NotInvalidated", func(t *testing.T) {
+		cm, main, _ := newForkedChainManager(t)
+		require.ErrorIs(t, cm.ReconsiderBlock(t.Context(), &main[3].Hash), ErrBlockNotInvalidated)
+	})
+}
+
+func TestChainManagerClearOrphans(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+
+	assert.Equal(t, len(fork), cm.ClearOrphans())
+	assert.Zero(t, cm.ClearOrphans())
+
+	_, err := cm.GetHeaderByHash(t.Context(), &fork[0].Hash)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+	_, err = cm.GetHeaderByHash(t.Context(), &main[3].Hash)
+	require.NoError(t, err)
+}
+
+func TestChainManagerInvalidatedPersistence(t *testing.T) {
+	cm := &ChainManager{localStoragePath: t.TempDir(), network: "test"}
+	hashes := []chainhash.Hash{{1}, {2}}
+	require.NoError(t, cm.saveInvalidated(hashes))
+
+	restored := &ChainManager{localStoragePath: cm.localStoragePath, network: "test"}
+	require.NoError(t, restored.loadInvalidated())
+	assert.ElementsMatch(t, hashes, restored.GetInvalidatedBlocks())
+
+	missing := &ChainManager{localStoragePath: t.TempDir(), network: "test"}
+	require.NoError(t, missing.loadInvalidated())
+	assert.Empty(t, missing.GetInvalidatedBlocks())
+}
+
+func TestChainManagerInvalidateBlockSurvivesRestart(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	cm, err := NewChainManager(ctx, NetworkRegtest, dir, nil)
+	require.NoError(t, err)
+	_, err = cm.MineBlocks(ctx, 9)
+	require.NoError(t, err)
+	invalid, err := cm.GetHeaderByHeight(ctx, 7)
+	require.NoError(t, err)
+
+	require.NoError(t, cm.InvalidateBlock(ctx, &invalid.Hash))
+	require.Equal(t, uint32(6), cm.GetHeight(ctx))
+
+	restored, err := NewChainManager(ctx, NetworkRegtest, dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(6), restored.GetHeight(ctx))
+	assert.Equal(t, cm.GetTip(ctx).Hash, restored.GetTip(ctx).Hash)
+	assert.Equal(t, []chainhash.Hash{invalid.Hash}, restored.GetInvalidatedBlocks())
+}
+
+func TestChainManagerInvalidatedAppliedOnLoad(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	cm, err := NewChainManager(ctx, NetworkRegtest, dir, nil)
+	require.NoError(t, err)
+	_, err = cm.MineBlocks(ctx, 9)
+	require.NoError(t, err)
+	invalid, err := cm.GetHeaderByHeight(ctx, 7)
+	require.NoError(t, err)
+
+	// A run that stopped after persisting the mark but before its rewind reached the header files
+	require.NoError(t, cm.saveInvalidated([]chainhash.Hash{invalid.Hash}))
+
+	restored, err := NewChainManager(ctx, NetworkRegtest, dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(6), restored.GetHeight(ctx))
+	assert.Equal(t, invalid.PrevHash, restored.GetTip(ctx).Hash)
+
+	again, err := NewChainManager(ctx, NetworkRegtest, dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(6), again.GetHeight(ctx))
+}
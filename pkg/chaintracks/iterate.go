@@ -0,0 +1,85 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"iter"
+	"net/http"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// iteratePageSize is how many headers IterateHeaders requests from /v2/headers at a time
+const iteratePageSize = 2000
+
+// IterateHeaders streams the main-chain headers from fromHeight to toHeight inclusive
+// Headers are fetched from /v2/headers a page at a time, and the next page only once the caller has
+// consumed the current one, so a whole chain can be walked in constant memory. Iteration ends early at
+// the server's tip. Each header must link to the one before; a reorg under the iterator yields
+// ErrBrokenChain. An error is yielded once and ends the iteration. ChainWork is not set.
+func (cc *Client) IterateHeaders(ctx context.Context, fromHeight, toHeight uint32) iter.Seq2[*BlockHeader, error] {
+	return func(yield func(*BlockHeader, error) bool) {
+		var prev *chainhash.Hash
+		for height := uint64(fromHeight); height <= uint64(toHeight); {
+			count := min(uint64(toHeight)-height+1, iteratePageSize)
+			page, err := cc.fetchHeaderPage(ctx, uint32(height), uint32(count)) //nolint:gosec // Bounded by toHeight
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, header := range page {
+				if prev != nil && header.PrevHash != *prev {
+					yield(nil, fmt.Errorf("%w: header %d does not link to its predecessor", ErrBrokenChain, header.Height))
+					return
+				}
+				prev = &header.Hash
+				if !yield(header, nil) {
+					return
+				}
+			}
+			if uint64(len(page)) < count {
+				return
+			}
+			height += count
+		}
+	}
+}
+
+// fetchHeaderPage fetches up to count consecutive headers starting at height from /v2/headers
+func (cc *Client) fetchHeaderPage(ctx context.Context, height, count uint32) ([]*BlockHeader, error) {
+	url := fmt.Sprintf("%s/v2/headers?height=%d&count=%d", cc.baseURL, height, count)
+
+	var value string
+	status, err := cc.getJSON(ctx, url, "headers", &value)
+	if status == http.StatusRequestedRangeNotSatisfiable {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode headers: %w", err)
+	}
+	if len(data)%headerSize != 0 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidHeaderDataLength, len(data))
+	}
+
+	headers := make([]*BlockHeader, 0, len(data)/headerSize)
+	for i := 0; i < len(data); i += headerSize {
+		header, err := block.NewHeaderFromBytes(data[i : i+headerSize])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse header: %w", err)
+		}
+		headers = append(headers, &BlockHeader{
+			Header: header,
+			Height: height + uint32(len(headers)), //nolint:gosec // At most count headers
+			Hash:   header.Hash(),
+		})
+	}
+	return headers, nil
+}
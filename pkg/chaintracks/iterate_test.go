@@ -0,0 +1,123 @@
+package chaintracks
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHeadersServer serves /v2/headers from a linked chain of count headers and counts requests
+func newHeadersServer(t *testing.T, count int, requests *atomic.Int32) (*httptest.Server, []*block.Header) {
+	t.Helper()
+
+	chain := make([]*block.Header, count)
+	for i := range chain {
+		chain[i] = &block.Header{Nonce: uint32(i)} //nolint:gosec // Test data
+		if i > 0 {
+			chain[i].PrevHash = chain[i-1].Hash()
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		height, _ := strconv.Atoi(r.URL.Query().Get("height"))
+		n, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		if height >= len(chain) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		var data []byte
+		for h := height; h < min(height+n, len(chain)); h++ {
+			data = append(data, chain[h].Bytes()...)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "value": hex.EncodeToString(data)})
+	}))
+	t.Cleanup(server.Close)
+	return server, chain
+}
+
+func TestClientIterateHeaders(t *testing.T) {
+	tests := []struct {
+		name             string
+		from, to         uint32
+		expectedCount    int
+		expectedRequests int32
+	}{
+		{name: "PagesLargeRanges", from: 0, to: 4499, expectedCount: 4500, expectedRequests: 3},
+		{name: "StopsAtTip", from: 4000, to: 9000, expectedCount: 500, expectedRequests: 1},
+		{name: "StopsPastTip", from: 2500, to: 9000, expectedCount: 2000, expectedRequests: 2},
+		{name: "SingleHeader", from: 7, to: 7, expectedCount: 1, expectedRequests: 1},
+		{name: "EmptyRange", from: 8, to: 7, expectedCount: 0, expectedRequests: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server, chain := newHeadersServer(t, 4500, &requests)
+
+			count := 0
+			for header, err := range NewClient(server.URL).IterateHeaders(t.Context(), tt.from, tt.to) {
+				require.NoError(t, err)
+				height := tt.from + uint32(count) //nolint:gosec // Test data
+				require.Equal(t, height, header.Height)
+				require.Equal(t, chain[height].Hash(), header.Hash)
+				count++
+			}
+			assert.Equal(t, tt.expectedCount, count)
+			assert.Equal(t, tt.expectedRequests, requests.Load())
+		})
+	}
+}
+
+func TestClientIterateHeadersBreakStopsFetching(t *testing.T) {
+	var requests atomic.Int32
+	server, _ := newHeadersServer(t, 4500, &requests)
+
+	for header, err := range NewClient(server.URL).IterateHeaders(t.Context(), 0, 4499) {
+		require.NoError(t, err)
+		if header.Height == 10 {
+			break
+		}
+	}
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClientIterateHeadersErrors(t *testing.T) {
+	t.Run("BrokenChain", func(t *testing.T) {
+		unlinked := append((&block.Header{Nonce: 1}).Bytes(), (&block.Header{Nonce: 2}).Bytes()...)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "value": hex.EncodeToString(unlinked)})
+		}))
+		defer server.Close()
+
+		var errs []error
+		for _, err := range NewClient(server.URL).IterateHeaders(t.Context(), 0, 1) {
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 2)
+		require.NoError(t, errs[0])
+		require.ErrorIs(t, errs[1], ErrBrokenChain)
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		calls := 0
+		for _, err := range NewClient(server.URL).IterateHeaders(t.Context(), 0, 1) {
+			calls++
+			require.ErrorIs(t, err, ErrServerRequestFailed)
+		}
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,133 @@
+package chaintracks
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultLagThreshold = 3
+	defaultQuietPeriod  = time.Hour
+	lagMonitorInterval  = time.Minute
+)
+
+// SyncState describes how the local chain compares to what the network advertises
+type SyncState string
+
+const (
+	// SyncStateSynced means the local tip is within the lag threshold of the network
+	SyncStateSynced SyncState = "synced"
+
+	// SyncStateBehind means peers advertise blocks we have not caught up to (slow sync)
+	SyncStateBehind SyncState = "behind"
+
+	// SyncStateNetworkQuiet means nothing has been announced for the quiet period (no blocks on the network)
+	SyncStateNetworkQuiet SyncState = "network-quiet"
+)
+
+// LagStatus reports the local height against the highest height advertised by the network
+type LagStatus struct {
+	State         SyncState `json:"state"`
+	LocalHeight   uint32    `json:"localHeight"`
+	NetworkHeight uint32    `json:"networkHeight"`
+	Deficit       uint32    `json:"deficit"`
+	LastBlockSeen time.Time `json:"lastBlockSeen"`
+}
+
+// SetLagPolicy configures when the chain is reported as behind or the network as quiet
+// threshold is the number of blocks the local tip may trail the network before reporting SyncStateBehind
+func (cm *ChainManager) SetLagPolicy(threshold uint32, quietPeriod time.Duration) {
+	cm.lagMu.Lock()
+	cm.lagThreshold = threshold
+	cm.quietPeriod = quietPeriod
+	cm.lagMu.Unlock()
+}
+
+// ObserveNetworkHeight records a height advertised by a peer or upstream source
+// P2P announcements are observed automatically; other sources (e.g. SSE) may call this directly
+func (cm *ChainManager) ObserveNetworkHeight(height uint32) {
+	cm.lagMu.Lock()
+	if height > cm.networkHeight {
+		cm.networkHeight = height
+	}
+	cm.lastBlockSeen = time.Now()
+	cm.lagMu.Unlock()
+
+	cm.evaluateLag()
+}
+
+// GetLagStatus returns the current lag status
+func (cm *ChainManager) GetLagStatus() LagStatus {
+	local := cm.GetHeight(context.Background())
+
+	cm.lagMu.RLock()
+	defer cm.lagMu.RUnlock()
+
+	threshold := cm.lagThreshold
+	if threshold == 0 {
+		threshold = defaultLagThreshold
+	}
+	quietPeriod := cm.quietPeriod
+	if quietPeriod <= 0 {
+		quietPeriod = defaultQuietPeriod
+	}
+
+	status := LagStatus{
+		State:         SyncStateSynced,
+		LocalHeight:   local,
+		NetworkHeight: cm.networkHeight,
+		LastBlockSeen: cm.lastBlockSeen,
+	}
+	if cm.networkHeight > local {
+		status.Deficit = cm.networkHeight - local
+	}
+
+	switch {
+	case status.Deficit > threshold:
+		status.State = SyncStateBehind
+	case !cm.lastBlockSeen.IsZero() && time.Since(cm.lastBlockSeen) > quietPeriod:
+		status.State = SyncStateNetworkQuiet
+	}
+
+	return status
+}
+
+// evaluateLag emits a sync event when the lag state changes
+func (cm *ChainManager) evaluateLag() {
+	status := cm.GetLagStatus()
+
+	cm.lagMu.Lock()
+	previous := cm.lagState
+	cm.lagState = status.State
+	cm.lagMu.Unlock()
+
+	if previous == status.State || (previous == "" && status.State == SyncStateSynced) {
+		return
+	}
+
+	switch status.State {
+	case SyncStateBehind:
+		cm.log().Warn("Falling behind network tip", "height", status.LocalHeight, "networkHeight", status.NetworkHeight, "behind", status.Deficit)
+	case SyncStateNetworkQuiet:
+		cm.log().Warn("No blocks seen on the network", "since", status.LastBlockSeen.Format(time.RFC3339))
+	case SyncStateSynced:
+		cm.log().Info("Caught up with network tip", "height", status.LocalHeight)
+	}
+
+	cm.publishEvent(&ChainEvent{Type: EventSyncStatus, Tip: cm.GetTip(context.Background()), Lag: &status})
+}
+
+// monitorLag periodically re-evaluates lag so quiet networks are detected without new messages
+func (cm *ChainManager) monitorLag(ctx context.Context) {
+	ticker := time.NewTicker(lagMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cm.evaluateLag()
+		}
+	}
+}
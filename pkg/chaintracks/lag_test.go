@@ -0,0 +1,95 @@
+package chaintracks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainManagerGetLagStatus(t *testing.T) {
+	tests := []struct {
+		name            string
+		localHeight     uint32
+		networkHeight   uint32
+		lastBlockSeen   time.Time
+		expectedState   SyncState
+		expectedDeficit uint32
+	}{
+		{
+			name:          "SyncedWhenAtNetworkHeight",
+			localHeight:   100,
+			networkHeight: 100,
+			lastBlockSeen: time.Now(),
+			expectedState: SyncStateSynced,
+		},
+		{
+			name:            "SyncedWithinThreshold",
+			localHeight:     100,
+			networkHeight:   102,
+			lastBlockSeen:   time.Now(),
+			expectedState:   SyncStateSynced,
+			expectedDeficit: 2,
+		},
+		{
+			name:            "BehindBeyondThreshold",
+			localHeight:     100,
+			networkHeight:   110,
+			lastBlockSeen:   time.Now(),
+			expectedState:   SyncStateBehind,
+			expectedDeficit: 10,
+		},
+		{
+			name:          "QuietWhenNoRecentBlocks",
+			localHeight:   100,
+			networkHeight: 100,
+			lastBlockSeen: time.Now().Add(-2 * time.Hour),
+			expectedState: SyncStateNetworkQuiet,
+		},
+		{
+			name:            "BehindTakesPrecedenceOverQuiet",
+			localHeight:     100,
+			networkHeight:   200,
+			lastBlockSeen:   time.Now().Add(-2 * time.Hour),
+			expectedState:   SyncStateBehind,
+			expectedDeficit: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := withTip(&ChainManager{
+				networkHeight: tt.networkHeight,
+				lastBlockSeen: tt.lastBlockSeen,
+			}, &BlockHeader{Header: &block.Header{}, Height: tt.localHeight})
+
+			status := cm.GetLagStatus()
+			assert.Equal(t, tt.expectedState, status.State)
+			assert.Equal(t, tt.expectedDeficit, status.Deficit)
+			assert.Equal(t, tt.localHeight, status.LocalHeight)
+		})
+	}
+}
+
+func TestChainManagerObserveNetworkHeightEmitsSyncEvents(t *testing.T) {
+	cm := withTip(&ChainManager{}, &BlockHeader{Header: &block.Header{}, Height: 100})
+	cm.SetLagPolicy(5, time.Hour)
+	events := cm.SubscribeEvents(t.Context())
+
+	cm.ObserveNetworkHeight(101)
+	cm.ObserveNetworkHeight(110)
+
+	event := receiveEvent(t, events)
+	assert.Equal(t, EventSyncStatus, event.Type)
+	assert.Equal(t, SyncStateBehind, event.Lag.State)
+	assert.Equal(t, uint32(10), event.Lag.Deficit)
+
+	cm.mu.Lock()
+	cm.tip.Store(&BlockHeader{Header: &block.Header{}, Height: 110})
+	cm.mu.Unlock()
+	cm.ObserveNetworkHeight(110)
+
+	event = receiveEvent(t, events)
+	assert.Equal(t, SyncStateSynced, event.Lag.State)
+}
@@ -0,0 +1,182 @@
+package chaintracks
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencyWindowSize is the number of recent samples kept per pipeline stage
+const latencyWindowSize = 256
+
+// LatencyStage names a step of the block propagation pipeline
+type LatencyStage string
+
+const (
+	// StageValidate is the time from P2P receipt until the header is validated and linked
+	StageValidate LatencyStage = "validate"
+
+	// StageTipUpdate is the time from validation until the new tip is applied
+	StageTipUpdate LatencyStage = "tipUpdate"
+
+	// StageDelivery is the time from the tip update until a consumer wrote the event to its subscribers
+	StageDelivery LatencyStage = "delivery"
+
+	// StageTotal is the time from P2P receipt until subscriber delivery
+	StageTotal LatencyStage = "total"
+)
+
+// LatencyTrace holds the pipeline timestamps for a single block announcement
+type LatencyTrace struct {
+	ReceivedAt   time.Time
+	ValidatedAt  time.Time
+	TipUpdatedAt time.Time
+}
+
+// LatencySummary summarizes the recent samples of one pipeline stage, in milliseconds
+type LatencySummary struct {
+	Count  uint64  `json:"count"` // Samples recorded since startup
+	LastMs float64 `json:"lastMs"`
+	MeanMs float64 `json:"meanMs"` // Over the recent window
+	P50Ms  float64 `json:"p50Ms"`
+	P95Ms  float64 `json:"p95Ms"`
+	MaxMs  float64 `json:"maxMs"`
+}
+
+// latencyTraceKey is the context key for the trace of the block being processed
+type latencyTraceKey struct{}
+
+// withLatencyTrace attaches a trace to ctx so later pipeline stages can stamp it
+func withLatencyTrace(ctx context.Context, trace *LatencyTrace) context.Context {
+	return context.WithValue(ctx, latencyTraceKey{}, trace)
+}
+
+// latencyTraceFrom returns the trace attached to ctx, or nil
+func latencyTraceFrom(ctx context.Context) *LatencyTrace {
+	trace, _ := ctx.Value(latencyTraceKey{}).(*LatencyTrace)
+	return trace
+}
+
+// markValidated stamps the validation time on the trace attached to ctx, if any
+func markValidated(ctx context.Context) {
+	if trace := latencyTraceFrom(ctx); trace != nil {
+		trace.ValidatedAt = time.Now()
+	}
+}
+
+// latencyWindow is a fixed-size ring of recent durations
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+	count   uint64
+	last    time.Duration
+}
+
+func (w *latencyWindow) add(d time.Duration) {
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, d)
+	} else {
+		w.samples[w.next] = d
+	}
+	w.next = (w.next + 1) % latencyWindowSize
+	w.count++
+	w.last = d
+}
+
+func (w *latencyWindow) summary() LatencySummary {
+	if len(w.samples) == 0 {
+		return LatencySummary{}
+	}
+
+	sorted := slices.Clone(w.samples)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	return LatencySummary{
+		Count:  w.count,
+		LastMs: toMillis(w.last),
+		MeanMs: toMillis(total / time.Duration(len(sorted))),
+		P50Ms:  toMillis(sorted[len(sorted)*50/100]),
+		P95Ms:  toMillis(sorted[len(sorted)*95/100]),
+		MaxMs:  toMillis(sorted[len(sorted)-1]),
+	}
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// latencyRecorder aggregates stage durations; the zero value is ready to use
+type latencyRecorder struct {
+	mu     sync.Mutex
+	stages map[LatencyStage]*latencyWindow
+}
+
+func (r *latencyRecorder) record(stage LatencyStage, from, to time.Time) {
+	if from.IsZero() || to.IsZero() {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stages == nil {
+		r.stages = make(map[LatencyStage]*latencyWindow)
+	}
+	w, ok := r.stages[stage]
+	if !ok {
+		w = &latencyWindow{}
+		r.stages[stage] = w
+	}
+	w.add(max(to.Sub(from), 0))
+}
+
+func (r *latencyRecorder) summaries() map[LatencyStage]LatencySummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[LatencyStage]LatencySummary, len(r.stages))
+	for stage, w := range r.stages {
+		result[stage] = w.summary()
+	}
+	return result
+}
+
+// recordTipUpdate stamps the tip update on the trace in ctx and records the upstream stages
+func (cm *ChainManager) recordTipUpdate(ctx context.Context) *LatencyTrace {
+	trace := latencyTraceFrom(ctx)
+	if trace == nil {
+		return nil
+	}
+
+	trace.TipUpdatedAt = time.Now()
+	if trace.ValidatedAt.IsZero() {
+		trace.ValidatedAt = trace.TipUpdatedAt
+	}
+
+	cm.latency.record(StageValidate, trace.ReceivedAt, trace.ValidatedAt)
+	cm.latency.record(StageTipUpdate, trace.ValidatedAt, trace.TipUpdatedAt)
+	return trace
+}
+
+// RecordDelivery records that a consumer finished writing an event to its subscribers
+// Only events that originated from a P2P announcement carry a trace; others are ignored
+func (cm *ChainManager) RecordDelivery(event *ChainEvent) {
+	if event == nil || event.Trace == nil {
+		return
+	}
+
+	now := time.Now()
+	cm.latency.record(StageDelivery, event.Trace.TipUpdatedAt, now)
+	cm.latency.record(StageTotal, event.Trace.ReceivedAt, now)
+}
+
+// GetLatencyStats returns the pipeline latency breakdown for recent P2P blocks
+func (cm *ChainManager) GetLatencyStats() map[LatencyStage]LatencySummary {
+	return cm.latency.summaries()
+}
@@ -0,0 +1,91 @@
+package chaintracks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyWindowSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []time.Duration
+		expected LatencySummary
+	}{
+		{name: "EmptyWindowIsZero", expected: LatencySummary{}},
+		{
+			name:     "SingleSample",
+			samples:  []time.Duration{5 * time.Millisecond},
+			expected: LatencySummary{Count: 1, LastMs: 5, MeanMs: 5, P50Ms: 5, P95Ms: 5, MaxMs: 5},
+		},
+		{
+			name:     "OrdersSamplesForPercentiles",
+			samples:  []time.Duration{40 * time.Millisecond, 10 * time.Millisecond, 30 * time.Millisecond, 20 * time.Millisecond},
+			expected: LatencySummary{Count: 4, LastMs: 20, MeanMs: 25, P50Ms: 30, P95Ms: 40, MaxMs: 40},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w latencyWindow
+			for _, d := range tt.samples {
+				w.add(d)
+			}
+			assert.Equal(t, tt.expected, w.summary())
+		})
+	}
+
+	t.Run("KeepsOnlyRecentSamples", func(t *testing.T) {
+		var w latencyWindow
+		for range latencyWindowSize {
+			w.add(time.Second)
+		}
+		for range latencyWindowSize {
+			w.add(time.Millisecond)
+		}
+
+		summary := w.summary()
+		assert.Equal(t, uint64(2*latencyWindowSize), summary.Count)
+		assert.InDelta(t, 1.0, summary.MaxMs, 0.001)
+	})
+}
+
+func TestChainManagerLatencyTrace(t *testing.T) {
+	t.Run("TracedTipUpdateRecordsAllStages", func(t *testing.T) {
+		cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+		events := cm.SubscribeEvents(t.Context())
+
+		trace := &LatencyTrace{ReceivedAt: time.Now().Add(-10 * time.Millisecond)}
+		ctx := withLatencyTrace(t.Context(), trace)
+		markValidated(ctx)
+		require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(0, 1)}))
+
+		event := receiveEvent(t, events)
+		require.Same(t, trace, event.Trace)
+		assert.False(t, trace.TipUpdatedAt.IsZero())
+
+		cm.RecordDelivery(event)
+
+		stats := cm.GetLatencyStats()
+		for _, stage := range []LatencyStage{StageValidate, StageTipUpdate, StageDelivery, StageTotal} {
+			assert.Equal(t, uint64(1), stats[stage].Count, stage)
+		}
+		assert.GreaterOrEqual(t, stats[StageTotal].LastMs, 10.0)
+	})
+
+	t.Run("UntracedTipUpdateRecordsNothing", func(t *testing.T) {
+		cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+		events := cm.SubscribeEvents(t.Context())
+
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(0, 1)}))
+
+		event := receiveEvent(t, events)
+		assert.Nil(t, event.Trace)
+
+		cm.RecordDelivery(event)
+		assert.Empty(t, cm.GetLatencyStats())
+	})
+}
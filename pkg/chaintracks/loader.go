@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/bsv-blockchain/go-sdk/block"
@@ -21,7 +21,11 @@ func loadHeadersFromFile(path string) ([]*block.Header, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	return parseHeaderFile(data)
+}
 
+// parseHeaderFile splits the contents of a .headers file into headers
+func parseHeaderFile(data []byte) ([]*block.Header, error) {
 	if len(data)%80 != 0 {
 		return nil, fmt.Errorf("%w: %d bytes (not multiple of 80)", ErrInvalidFileSize, len(data))
 	}
@@ -57,13 +61,15 @@ func parseMetadata(path string) (*CDNMetadata, error) {
 }
 
 // loadFromLocalFiles restores the chain from local header files
-// No validation is performed - we trust our own checkpoint and exported files
+// Headers are not validated - we trust our own checkpoint and exported files - but a file whose metadata
+// entry records a fileHash must match it, so corruption on disk is caught. Files are read, parsed and hashed
+// in parallel, and linked into the chain in order as each becomes ready.
 func (cm *ChainManager) loadFromLocalFiles(ctx context.Context) error {
 	metadataPath := filepath.Join(cm.localStoragePath, cm.network+"NetBlockHeaders.json")
-	log.Printf("Loading checkpoint metadata from: %s", metadataPath)
+	cm.log().Info("Loading checkpoint metadata", "path", metadataPath)
 
 	if _, err := os.Stat(metadataPath); os.IsNotExist(err) {
-		log.Printf("No checkpoint files found at %s, starting with empty chain", metadataPath)
+		cm.log().Info("No checkpoint files found, starting with empty chain", "path", metadataPath)
 		return nil
 	}
 
@@ -72,16 +78,27 @@ func (cm *ChainManager) loadFromLocalFiles(ctx context.Context) error {
 		return fmt.Errorf("failed to parse local metadata: %w", err)
 	}
 
-	log.Printf("Found %d checkpoint files to load", len(metadata.Files))
+	cm.log().Info("Found checkpoint files to load", "files", len(metadata.Files))
 
-	for _, fileEntry := range metadata.Files {
-		filePath := filepath.Join(cm.localStoragePath, fileEntry.FileName)
-		headers, err := loadHeadersFromFile(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to load file %s: %w", fileEntry.FileName, err)
-		}
+	// Continue the event sequence from where the previous run left off
+	cm.mu.Lock()
+	cm.eventSeq = metadata.EventSeq
+	cm.mu.Unlock()
 
-		blockHeaders := make([]*BlockHeader, 0, len(headers))
+	// Files before the last are complete, though metadata written while one held the tip may record a lower count
+	for i := range metadata.Files[:max(len(metadata.Files)-1, 0)] {
+		metadata.Files[i].Count = 100000
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	files := cm.readHeaderFiles(metadata.Files, stop)
+
+	for i, fileEntry := range metadata.Files {
+		loaded := <-files[i]
+		if loaded.err != nil {
+			return loaded.err
+		}
 
 		// Calculate chainwork incrementally
 		var prevChainWork *big.Int
@@ -96,41 +113,124 @@ func (cm *ChainManager) loadFromLocalFiles(ctx context.Context) error {
 			prevChainWork = prevHeader.ChainWork
 		}
 
-		for i, header := range headers {
-			height := fileEntry.FirstHeight + uint32(i) //nolint:gosec // Loop index bounded by slice length
+		for _, header := range loaded.headers {
+			if header.Height == 0 {
+				header.ChainWork.SetInt64(0)
+				continue
+			}
+			header.ChainWork.Add(header.ChainWork, prevChainWork)
+			prevChainWork = header.ChainWork
+		}
+
+		cm.linkStoredHeaders(loaded.headers)
+	}
+
+	// Refresh the metadata for the loaded tip once, which keeps the stored event sequence as it was
+	if tip := cm.GetTip(ctx); tip != nil {
+		if err := cm.updateMetadataForTip(ctx, tip.Height+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkStoredHeaders extends the main chain with headers read back from the local files and moves the tip to
+// the last of them
+// Unlike applyBranch no event is sequenced or published and nothing is written, since the headers are already
+// on disk and their events were sequenced when they were first applied. Being on disk, headers below the memory
+// window leave memory straight away.
+func (cm *ChainManager) linkStoredHeaders(headers []*BlockHeader) {
+	if len(headers) == 0 {
+		return
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	for _, header := range headers {
+		for uint32(len(cm.byHeight)) <= header.Height { //nolint:gosec // Height is bounded by the file entry
+			cm.byHeight = append(cm.byHeight, chainhash.Hash{})
+		}
+		cm.byHeight[header.Height] = header.Hash
+		cm.byHash[header.Hash] = header
+	}
+	cm.tip.Store(headers[len(headers)-1])
+	cm.evictBelowWindow()
+}
+
+// loadedFile is a header file read ahead of being linked into the chain
+// Each header's ChainWork holds only its own work until the file is linked.
+type loadedFile struct {
+	headers []*BlockHeader
+	err     error
+}
+
+// readHeaderFiles reads the given files on one worker per CPU, delivering each on the channel at its index
+// Workers take files in order, so the channels can be drained in order; closing stop abandons the rest.
+func (cm *ChainManager) readHeaderFiles(entries []CDNFileEntry, stop <-chan struct{}) []chan loadedFile {
+	results := make([]chan loadedFile, len(entries))
+	for i := range results {
+		results[i] = make(chan loadedFile, 1)
+	}
 
-			var chainWork *big.Int
-			if height == 0 {
-				chainWork = big.NewInt(0)
-			} else {
-				work := CalculateWork(header.Bits)
-				chainWork = new(big.Int).Add(prevChainWork, work)
-				prevChainWork = chainWork
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range entries {
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
 			}
+		}
+	}()
 
-			blockHeader := &BlockHeader{
-				Header:    header,
-				Height:    height,
-				Hash:      header.Hash(),
-				ChainWork: chainWork,
+	for range min(runtime.GOMAXPROCS(0), len(entries)) {
+		go func() {
+			for i := range jobs {
+				headers, err := cm.readHeaderFile(entries[i])
+				results[i] <- loadedFile{headers: headers, err: err}
 			}
+		}()
+	}
+	return results
+}
 
-			blockHeaders = append(blockHeaders, blockHeader)
+// readHeaderFile reads, verifies and parses one local header file, hashing each header and computing its work
+func (cm *ChainManager) readHeaderFile(entry CDNFileEntry) ([]*BlockHeader, error) {
+	data, err := os.ReadFile(filepath.Join(cm.localStoragePath, entry.FileName)) //nolint:gosec // Path is constructed internally, not from user input
+	if err != nil {
+		return nil, fmt.Errorf("failed to load file %s: %w", entry.FileName, err)
+	}
+	// Headers past the entry's count were left behind by a rewind, a zero count is read as the whole file
+	if limit := entry.Count * 80; entry.Count > 0 && len(data) > limit {
+		data = data[:limit]
+	}
+	if entry.FileHash != "" {
+		if err := verifyFileHash(entry.FileName, data, entry.FileHash); err != nil {
+			return nil, err
 		}
+	}
+	headers, err := parseHeaderFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load file %s: %w", entry.FileName, err)
+	}
 
-		if err := cm.SetChainTip(ctx, blockHeaders); err != nil {
-			return fmt.Errorf("failed to set chain tip for file %s: %w", fileEntry.FileName, err)
+	blockHeaders := make([]*BlockHeader, len(headers))
+	for i, header := range headers {
+		blockHeaders[i] = &BlockHeader{
+			Header:    header,
+			Height:    entry.FirstHeight + uint32(i), //nolint:gosec // Loop index bounded by slice length
+			Hash:      header.Hash(),
+			ChainWork: CalculateWork(header.Bits),
 		}
 	}
-
-	return nil
+	return blockHeaders, nil
 }
 
 // SetChainTip updates the chain tip with a new branch of headers
 // branchHeaders should be ordered from oldest to newest
 // The parent of branchHeaders[0] must exist in our current chain
-//
-//nolint:gocyclo // Complex validation and reorganization logic
 func (cm *ChainManager) SetChainTip(ctx context.Context, branchHeaders []*BlockHeader) error {
 	if len(branchHeaders) == 0 {
 		return nil
@@ -139,10 +239,32 @@ func (cm *ChainManager) SetChainTip(ctx context.Context, branchHeaders []*BlockH
 	// Update in-memory chain
 	cm.mu.Lock()
 
+	if err := cm.checkNotInvalidated(branchHeaders); err != nil {
+		cm.mu.Unlock()
+		return err
+	}
+
+	update := cm.applyBranch(ctx, branchHeaders)
+	cm.mu.Unlock()
+
+	return cm.finishTipUpdate(ctx, update)
+}
+
+// tipUpdate carries a branch applied under the lock to the work finishTipUpdate does after releasing it
+type tipUpdate struct {
+	branch  []*BlockHeader
+	tip     *BlockHeader
+	reorg   *ReorgInfo
+	msgChan chan *BlockHeader
+}
+
+// applyBranch makes branchHeaders the main chain and publishes the tip event (must be called with lock held)
+func (cm *ChainManager) applyBranch(ctx context.Context, branchHeaders []*BlockHeader) tipUpdate {
+	reorg := cm.detectReorg(branchHeaders)
+	cm.branchApplied(branchHeaders[0].Height)
+
 	// Update byHeight for all blocks in the new branch
 	for _, header := range branchHeaders {
-		// hash := header.Hash()
-
 		// Ensure slice is large enough
 		for uint32(len(cm.byHeight)) <= header.Height { //nolint:gosec // Height is validated before storage
 			cm.byHeight = append(cm.byHeight, chainhash.Hash{})
@@ -160,17 +282,37 @@ func (cm *ChainManager) SetChainTip(ctx context.Context, branchHeaders []*BlockH
 	}
 
 	// Always set tip to the last header in the branch
-	cm.tip = branchHeaders[len(branchHeaders)-1]
+	newTip := branchHeaders[len(branchHeaders)-1]
+	cm.tip.Store(newTip)
 
-	// Prune orphaned headers older than 100 blocks
+	// Prune orphaned headers older than 100 blocks; main-chain headers leave the memory window once written
 	cm.pruneOrphans()
 
-	// Get channel reference before unlocking
-	msgChan := cm.msgChan
-	cm.mu.Unlock()
+	event := &ChainEvent{Type: EventTipAdvanced, Tip: newTip}
+	if reorg != nil {
+		event = &ChainEvent{Type: EventReorg, Tip: newTip, Reorg: reorg}
+	}
+
+	event.Trace = cm.recordTipUpdate(ctx)
+
+	// Sequence and publish under the lock so delivery order matches chain order
+	cm.sequenceEvent(event)
+	cm.publishEvent(event)
+
+	return tipUpdate{branch: branchHeaders, tip: newTip, reorg: reorg, msgChan: cm.msgChan}
+}
+
+// finishTipUpdate records a reorg, notifies the tip channel and persists the branch, after the lock is released
+func (cm *ChainManager) finishTipUpdate(ctx context.Context, update tipUpdate) error {
+	newTip := update.tip
+	if update.reorg != nil {
+		cm.log().Warn("Reorg detected", "forkHeight", update.reorg.ForkHeight, "depth", len(update.reorg.OrphanedHashes), "height", newTip.Height, "hash", newTip.Hash)
+		cm.recordReorg(update.reorg, newTip)
+	}
+	cm.ObserveNetworkHeight(newTip.Height)
 
 	// Publish tip change event outside the lock (non-blocking)
-	if msgChan != nil {
+	if msgChan := update.msgChan; msgChan != nil {
 		// Drain any old tip (we only care about the latest)
 		select {
 		case <-msgChan:
@@ -179,7 +321,7 @@ func (cm *ChainManager) SetChainTip(ctx context.Context, branchHeaders []*BlockH
 
 		// Send the new tip (non-blocking)
 		select {
-		case msgChan <- cm.tip:
+		case msgChan <- newTip:
 		default:
 			// Channel full after drain shouldn't happen, but skip if it does
 		}
@@ -187,20 +329,32 @@ func (cm *ChainManager) SetChainTip(ctx context.Context, branchHeaders []*BlockH
 
 	// Write headers to files
 	startWrite := time.Now()
-	if err := cm.writeHeadersToFiles(branchHeaders); err != nil {
+	if err := cm.writeHeadersToFiles(update.branch); err != nil {
 		return fmt.Errorf("failed to write headers to files: %w", err)
 	}
 	writeDuration := time.Since(startWrite)
 
+	// Headers leave the memory window only once they are on disk
+	cm.mu.Lock()
+	cm.branchWritten(update.branch[0].Height)
+	var truncateErr error
+	if update.reorg != nil {
+		truncateErr = cm.truncateHeaderFile()
+	}
+	cm.mu.Unlock()
+	if truncateErr != nil {
+		return truncateErr
+	}
+
 	// Update metadata
 	startMeta := time.Now()
-	if err := cm.updateMetadataForTip(ctx); err != nil {
+	if err := cm.updateMetadataForTip(ctx, update.branch[0].Height); err != nil {
 		return fmt.Errorf("failed to update metadata: %w", err)
 	}
 	metaDuration := time.Since(startMeta)
 
 	if writeDuration > 100*time.Millisecond || metaDuration > 100*time.Millisecond {
-		log.Printf("SetChainTip timing: write=%v meta=%v", writeDuration, metaDuration)
+		cm.log().Warn("Slow SetChainTip", "write", writeDuration, "meta", metaDuration)
 	}
 
 	return nil
@@ -257,8 +411,35 @@ func (cm *ChainManager) writeHeadersToFiles(headers []*BlockHeader) error {
 	return nil
 }
 
+// truncateHeaderFile cuts the tip's header file back to the tip, dropping headers a rewind left past it
+// (must be called with lock held)
+// The current tip is used rather than the update's, so headers of a later update that is already applied
+// are never cut. Files past the tip's are dropped from the metadata by updateMetadataForTip.
+func (cm *ChainManager) truncateHeaderFile() error {
+	tip := cm.tip.Load()
+	if cm.localStoragePath == "" || tip == nil {
+		return nil
+	}
+
+	fileName := fmt.Sprintf("%sNet_%d.headers", cm.network, tip.Height/100000)
+	filePath := filepath.Join(cm.localStoragePath, fileName)
+	size := int64(tip.Height%100000+1) * 80
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file %s: %w", fileName, err)
+	}
+	if info.Size() <= size {
+		return nil
+	}
+	if err := os.Truncate(filePath, size); err != nil {
+		return fmt.Errorf("failed to truncate file %s: %w", fileName, err)
+	}
+	return nil
+}
+
 // updateMetadataForTip updates the metadata JSON with current chain tip info
-func (cm *ChainManager) updateMetadataForTip(ctx context.Context) error {
+// Files rewritten from fromHeight on lose their fileHash; complete files before the tip's are hashed afresh.
+func (cm *ChainManager) updateMetadataForTip(ctx context.Context, fromHeight uint32) error {
 	if cm.localStoragePath == "" {
 		return nil
 	}
@@ -302,12 +483,15 @@ func (cm *ChainManager) updateMetadataForTip(ctx context.Context) error {
 			SourceURL:     "",
 		})
 	}
+	// Drop entries past the tip, left behind when the tip rewinds across a file boundary
+	metadata.Files = metadata.Files[:fileIndex+1]
 
 	// Update the last file entry with current tip info
 	lastFileEntry := &metadata.Files[fileIndex]
 	lastFileEntry.Count = int((tip.Height % 100000) + 1)
 	lastFileEntry.LastChainWork = ChainWorkToHex(tip.ChainWork)
 	lastFileEntry.LastHash = tip.Hash
+	lastFileEntry.FileHash = "" // The tip's file keeps growing, so it is only hashed once complete
 
 	// Get previous header for prevChainWork and prevHash
 	if tip.Height > 0 {
@@ -318,11 +502,32 @@ func (cm *ChainManager) updateMetadataForTip(ctx context.Context) error {
 		}
 	}
 
+	// Hash complete files so loading can detect corruption, first dropping hashes of files just rewritten
+	for i := range metadata.Files[:fileIndex] {
+		entry := &metadata.Files[i]
+		// The tip is past this file, which may have been the tip's when last written
+		entry.Count = 100000
+		if uint32(i) >= fromHeight/100000 { //nolint:gosec // Bounded by fileIndex
+			entry.FileHash = ""
+		}
+		if entry.FileHash != "" || entry.Count != 100000 {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(cm.localStoragePath, entry.FileName)) //nolint:gosec // Path is constructed internally
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", entry.FileName, err)
+		}
+		entry.FileHash = fileHash(data)
+	}
+
+	metadata.EventSeq = cm.LastEventSeq()
+
 	// Write updated metadata
 	return cm.writeLocalMetadata(metadata)
 }
 
 // writeLocalMetadata writes the metadata JSON to local storage
+// It is written under a temporary name and renamed, so a reader such as ServedCDNMetadata never sees it half written.
 func (cm *ChainManager) writeLocalMetadata(metadata *CDNMetadata) error {
 	if cm.localStoragePath == "" {
 		return nil
@@ -334,7 +539,10 @@ func (cm *ChainManager) writeLocalMetadata(metadata *CDNMetadata) error {
 	}
 
 	metadataPath := filepath.Join(cm.localStoragePath, cm.network+"NetBlockHeaders.json")
-	if err := os.WriteFile(metadataPath, data, 0o600); err != nil {
+	if err := os.WriteFile(metadataPath+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	if err := os.Rename(metadataPath+".tmp", metadataPath); err != nil {
 		return fmt.Errorf("failed to write metadata: %w", err)
 	}
 
@@ -2,10 +2,13 @@ package chaintracks
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/bsv-blockchain/go-sdk/block"
 	"github.com/bsv-blockchain/go-sdk/chainhash"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -384,3 +387,84 @@ func TestChainManagerWriteLocalMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadFromLocalFilesVerifiesFileHash(t *testing.T) {
+	dir := t.TempDir()
+	var data [2][]byte
+	var prev chainhash.Hash
+	for height := range 100001 {
+		header := &block.Header{PrevHash: prev, Nonce: uint32(height), Bits: regtestPowLimitBits} //nolint:gosec // Test data
+		data[height/100000] = append(data[height/100000], header.Bytes()...)
+		prev = header.Hash()
+	}
+	metadata := CDNMetadata{JSONFilename: "testNetBlockHeaders.json", HeadersPerFile: 100000}
+	for i, fileData := range data {
+		entry := CDNFileEntry{Chain: "test", Count: len(fileData) / 80, FileHash: fileHash(fileData), FileName: fmt.Sprintf("testNet_%d.headers", i), FirstHeight: uint32(i * 100000)} //nolint:gosec // Test data
+		metadata.Files = append(metadata.Files, entry)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, entry.FileName), fileData, 0o600))
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "testNetBlockHeaders.json"), metadataBytes, 0o600))
+
+	cm, err := NewChainManager(t.Context(), "test", dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(100000), cm.GetHeight(t.Context()))
+	expectedWork := new(big.Int).Mul(CalculateWork(regtestPowLimitBits), big.NewInt(100000))
+	assert.Equal(t, 0, expectedWork.Cmp(cm.GetTip(t.Context()).ChainWork), "chainwork carries across files")
+	assert.Positive(t, cm.GetMetrics().LoadSeconds)
+
+	written, err := parseMetadata(filepath.Join(dir, "testNetBlockHeaders.json"))
+	require.NoError(t, err)
+	require.Len(t, written.Files, 2)
+	assert.Equal(t, fileHash(data[0]), written.Files[0].FileHash, "complete files keep a hash")
+	assert.Empty(t, written.Files[1].FileHash, "the tip's file is not hashed")
+	require.NoError(t, VerifyHeaderFiles(dir, "test"))
+
+	data[0][100] ^= 0xff
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "testNet_0.headers"), data[0], 0o600))
+	require.ErrorIs(t, VerifyHeaderFiles(dir, "test"), ErrFileHashMismatch)
+	_, err = NewChainManager(t.Context(), "test", dir, nil)
+	require.ErrorIs(t, err, ErrFileHashMismatch)
+}
+
+func TestReadHeaderFileRespectsCount(t *testing.T) {
+	cm := &ChainManager{network: "test", localStoragePath: t.TempDir()}
+	var data []byte
+	for height := range 5 {
+		data = append(data, (&block.Header{Nonce: uint32(height)}).Bytes()...) //nolint:gosec // Test data
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(cm.localStoragePath, "testNet_0.headers"), data, 0o600))
+
+	headers, err := cm.readHeaderFile(CDNFileEntry{FileName: "testNet_0.headers", Count: 3, FileHash: fileHash(data[:3*80])})
+	require.NoError(t, err)
+	require.Len(t, headers, 3, "headers past the count are left over from a rewind")
+	assert.Equal(t, uint32(2), headers[2].Nonce)
+
+	headers, err = cm.readHeaderFile(CDNFileEntry{FileName: "testNet_0.headers"})
+	require.NoError(t, err)
+	assert.Len(t, headers, 5, "a zero count reads the whole file")
+}
+
+func TestLoadFromLocalFilesAcrossFileBoundary(t *testing.T) {
+	src, chain := newStoredChainManager(t, 100050)
+	metadataPath := filepath.Join(src.localStoragePath, "stnNetBlockHeaders.json")
+
+	written, err := parseMetadata(metadataPath)
+	require.NoError(t, err)
+	require.Len(t, written.Files, 2)
+	assert.Equal(t, 100000, written.Files[0].Count, "the file the tip moved past is complete")
+	assert.NotEmpty(t, written.Files[0].FileHash)
+	assert.Equal(t, 51, written.Files[1].Count)
+
+	// Metadata from before complete files had their count corrected still loads every header
+	written.Files[0].Count = 1
+	written.Files[0].FileHash = ""
+	data, err := json.Marshal(written)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(metadataPath, data, 0o600))
+
+	cm, err := NewChainManager(t.Context(), "stn", src.localStoragePath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, chain[len(chain)-1].Hash, cm.GetTip(t.Context()).Hash)
+}
@@ -0,0 +1,49 @@
+package chaintracks
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+// Logger is the leveled, structured logger used by ChainManager, Client and the server
+// Arguments after the message are alternating key/value pairs as in log/slog, so *slog.Logger satisfies it
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// defaultLogger holds the logger set with SetDefaultLogger
+var defaultLogger atomic.Pointer[Logger] //nolint:gochecknoglobals // Package-wide default, like slog.Default
+
+// DefaultLogger returns a slog JSON logger writing info and above to stderr
+func DefaultLogger() Logger {
+	return slog.New(slog.NewJSONHandler(os.Stderr, nil))
+}
+
+// SetDefaultLogger sets the logger used by ChainManagers, Clients, watchdogs and P2P clients created afterwards,
+// and by package-level helpers such as DownloadCDNHeaders
+func SetDefaultLogger(logger Logger) {
+	defaultLogger.Store(&logger)
+}
+
+// getDefaultLogger returns the logger set with SetDefaultLogger, or DefaultLogger if none was set
+func getDefaultLogger() Logger {
+	if logger := defaultLogger.Load(); logger != nil {
+		return *logger
+	}
+	return DefaultLogger()
+}
+
+// p2pLogger adapts a Logger to the printf-style logger the P2P message bus expects
+type p2pLogger struct {
+	logger Logger
+}
+
+func (l p2pLogger) Debugf(format string, v ...any) { l.logger.Debug(fmt.Sprintf(format, v...)) }
+func (l p2pLogger) Infof(format string, v ...any)  { l.logger.Info(fmt.Sprintf(format, v...)) }
+func (l p2pLogger) Warnf(format string, v ...any)  { l.logger.Warn(fmt.Sprintf(format, v...)) }
+func (l p2pLogger) Errorf(format string, v ...any) { l.logger.Error(fmt.Sprintf(format, v...)) }
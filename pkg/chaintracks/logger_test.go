@@ -0,0 +1,88 @@
+package chaintracks
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBufferLogger returns a debug-level slog JSON logger writing to a buffer
+func newBufferLogger() (Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+// decodeLogLines parses every JSON log line in buf
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestSetDefaultLogger(t *testing.T) {
+	previous := defaultLogger.Load()
+	t.Cleanup(func() { defaultLogger.Store(previous) })
+
+	logger, _ := newBufferLogger()
+	SetDefaultLogger(logger)
+
+	assert.Same(t, logger, NewClient("localhost:3011").log())
+	assert.Same(t, logger, (&ChainManager{}).log())
+	assert.Same(t, logger, NewDivergenceWatchdog(&ChainManager{}, mapReference{}, WatchdogConfig{}).config.Logger)
+}
+
+func TestChainManagerSetLoggerWritesStructuredFields(t *testing.T) {
+	logger, buf := newBufferLogger()
+	cm := withTip(&ChainManager{}, &BlockHeader{Header: &block.Header{}, Height: 100})
+	cm.SetLogger(logger)
+	cm.SetLagPolicy(5, time.Hour)
+
+	cm.ObserveNetworkHeight(110)
+
+	lines := decodeLogLines(t, buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "WARN", lines[0]["level"])
+	assert.Equal(t, "Falling behind network tip", lines[0]["msg"])
+	assert.InDelta(t, 100, lines[0]["height"], 0)
+	assert.InDelta(t, 110, lines[0]["networkHeight"], 0)
+	assert.InDelta(t, 10, lines[0]["behind"], 0)
+}
+
+func TestP2PLogger(t *testing.T) {
+	logger, buf := newBufferLogger()
+	adapter := p2pLogger{logger: logger}
+
+	adapter.Debugf("debug %d", 1)
+	adapter.Infof("info %s", "two")
+	adapter.Warnf("warn")
+	adapter.Errorf("error %v", 3.5)
+
+	lines := decodeLogLines(t, buf)
+	require.Len(t, lines, 4)
+
+	expected := []struct{ level, msg string }{
+		{"DEBUG", "debug 1"},
+		{"INFO", "info two"},
+		{"WARN", "warn"},
+		{"ERROR", "error 3.5"},
+	}
+	for i, want := range expected {
+		assert.Equal(t, want.level, lines[i]["level"])
+		assert.Equal(t, want.msg, lines[i]["msg"])
+	}
+}
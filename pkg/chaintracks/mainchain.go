@@ -0,0 +1,62 @@
+package chaintracks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// BlockState says whether a block is part of the active chain
+type BlockState string
+
+const (
+	// BlockStateActive means the block is on the current main chain
+	BlockStateActive BlockState = "active"
+
+	// BlockStateOrphan means the block is known but not on the main chain, e.g. it was reorged out
+	BlockStateOrphan BlockState = "orphan"
+
+	// BlockStateUnknown means the block was never seen, or was an orphan old enough to be pruned
+	BlockStateUnknown BlockState = "unknown"
+)
+
+// BlockChainState is the main-chain status of a block hash
+type BlockChainState struct {
+	Hash   chainhash.Hash `json:"hash"`
+	State  BlockState     `json:"state"`
+	Height uint32         `json:"height"` // Zero for unknown blocks
+}
+
+// IsOnMainChain reports whether a block is on the current main chain
+// Returns false for known orphans and ErrHeaderNotFound for unknown blocks
+func (cm *ChainManager) IsOnMainChain(_ context.Context, hash *chainhash.Hash) (bool, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	header, ok := cm.headerByHash(*hash)
+	if !ok {
+		return false, ErrHeaderNotFound
+	}
+	return int(header.Height) < len(cm.byHeight) && cm.byHeight[header.Height] == *hash, nil
+}
+
+// GetBlockChainState classifies a block hash as active, orphan or unknown using any Chaintracks implementation
+func GetBlockChainState(ctx context.Context, ct Chaintracks, hash *chainhash.Hash) (*BlockChainState, error) {
+	state := &BlockChainState{Hash: *hash, State: BlockStateUnknown}
+
+	header, err := ct.GetHeaderByHash(ctx, hash)
+	if errors.Is(err, ErrHeaderNotFound) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state.Height = header.Height
+	state.State = BlockStateOrphan
+	if mainHeader, err := ct.GetHeaderByHeight(ctx, header.Height); err == nil && mainHeader.Hash == header.Hash {
+		state.State = BlockStateActive
+	}
+	return state, nil
+}
@@ -0,0 +1,68 @@
+package chaintracks
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainManagerIsOnMainChain(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+
+	tests := []struct {
+		name        string
+		hash        chainhash.Hash
+		expected    bool
+		expectedErr error
+	}{
+		{name: "MainChainBlock", hash: main[4].Hash, expected: true},
+		{name: "KnownOrphan", hash: fork[0].Hash, expected: false},
+		{name: "Unknown", hash: chainhash.Hash{0xff}, expectedErr: ErrHeaderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onMain, err := cm.IsOnMainChain(t.Context(), &tt.hash)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, onMain)
+		})
+	}
+}
+
+func TestGetBlockChainState(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+
+	tests := []struct {
+		name           string
+		hash           chainhash.Hash
+		expectedState  BlockState
+		expectedHeight uint32
+	}{
+		{name: "Active", hash: main[4].Hash, expectedState: BlockStateActive, expectedHeight: 4},
+		{name: "Orphan", hash: fork[1].Hash, expectedState: BlockStateOrphan, expectedHeight: 4},
+		{name: "Unknown", hash: chainhash.Hash{0xff}, expectedState: BlockStateUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := GetBlockChainState(t.Context(), cm, &tt.hash)
+			require.NoError(t, err)
+			assert.Equal(t, tt.hash, state.Hash)
+			assert.Equal(t, tt.expectedState, state.State)
+			assert.Equal(t, tt.expectedHeight, state.Height)
+		})
+	}
+
+	t.Run("OrphanAfterReorg", func(t *testing.T) {
+		require.NoError(t, cm.SetChainTip(t.Context(), fork))
+		state, err := GetBlockChainState(t.Context(), cm, &main[4].Hash)
+		require.NoError(t, err)
+		assert.Equal(t, BlockStateOrphan, state.State)
+	})
+}
@@ -0,0 +1,52 @@
+package chaintracks
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// medianTimeSpan is how many blocks, ending at the one in question, the median time past is taken over
+const medianTimeSpan = 11
+
+// GetHeaderTime returns the timestamp of the main-chain block at height from any Chaintracks implementation
+func GetHeaderTime(ctx context.Context, ct Chaintracks, height uint32) (time.Time, error) {
+	header, err := ct.GetHeaderByHeight(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(header.Timestamp), 0), nil
+}
+
+// GetMedianTimePast returns the median timestamp of the main-chain block at height and the ten before it
+// This is the time BIP 113 compares time-based locktimes against. Blocks near genesis use the blocks available.
+func GetMedianTimePast(ctx context.Context, ct Chaintracks, height uint32) (time.Time, error) {
+	header, err := ct.GetHeaderByHeight(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	median, err := MedianTimePast(ctx, ct, header)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(median), 0), nil
+}
+
+// MedianTimePast returns the median time past of header, in the seconds of the header time field
+// Only header's ancestors are read, so the result is fixed for a given hash.
+func MedianTimePast(ctx context.Context, ct Chaintracks, header *BlockHeader) (uint32, error) {
+	headers, err := ct.GetHeadersBackwards(ctx, &header.Hash, medianTimeSpan)
+	if err != nil {
+		return 0, err
+	}
+	if len(headers) == 0 {
+		return 0, ErrHeaderNotFound
+	}
+
+	times := make([]uint32, len(headers))
+	for i, h := range headers {
+		times[i] = h.Timestamp
+	}
+	slices.Sort(times)
+	return times[len(times)/2], nil
+}
@@ -0,0 +1,82 @@
+package chaintracks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTimedChainManager returns a ChainManager whose linked main chain has a block per timestamp
+func newTimedChainManager(t *testing.T, times []uint32) *ChainManager {
+	t.Helper()
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+	headers := make([]*BlockHeader, len(times))
+	var prev chainhash.Hash
+	for i, ts := range times {
+		header := &block.Header{PrevHash: prev, Timestamp: ts}
+		prev = header.Hash()
+		headers[i] = &BlockHeader{Header: header, Height: uint32(i), Hash: prev} //nolint:gosec // Test heights are small
+	}
+	require.NoError(t, cm.SetChainTip(t.Context(), headers))
+	return cm
+}
+
+func TestGetMedianTimePast(t *testing.T) {
+	// Out of order timestamps, as miners may set them
+	times := []uint32{100, 300, 200, 500, 400, 700, 600, 900, 800, 1100, 1000, 1300, 1200, 50}
+	cm := newTimedChainManager(t, times)
+
+	tests := []struct {
+		name     string
+		height   uint32
+		expected uint32
+	}{
+		{name: "Genesis", height: 0, expected: 100},
+		{name: "FewerThanElevenBlocks", height: 3, expected: 300},
+		{name: "ElevenBlocks", height: 10, expected: 600},
+		{name: "SlidingWindow", height: 12, expected: 800},
+		{name: "TimestampBelowMedian", height: 13, expected: 800},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			median, err := GetMedianTimePast(t.Context(), cm, tt.height)
+			require.NoError(t, err)
+			assert.Equal(t, time.Unix(int64(tt.expected), 0), median)
+		})
+	}
+
+	t.Run("UnknownHeight", func(t *testing.T) {
+		_, err := GetMedianTimePast(t.Context(), cm, 99)
+		require.ErrorIs(t, err, ErrHeaderNotFound)
+	})
+}
+
+func TestGetHeaderTime(t *testing.T) {
+	cm := newTimedChainManager(t, []uint32{1231006505, 1231469665})
+
+	headerTime, err := GetHeaderTime(t.Context(), cm, 1)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1231469665, 0), headerTime)
+
+	_, err = GetHeaderTime(t.Context(), cm, 2)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+}
+
+func TestBlockHeaderMedianTimeJSON(t *testing.T) {
+	header := testHeader(1, 1)
+	data, err := header.MarshalJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "medianTime", "omitted unless set")
+
+	header.MedianTime = 1231006505
+	data, err = header.MarshalJSON()
+	require.NoError(t, err)
+	var parsed BlockHeader
+	require.NoError(t, parsed.UnmarshalJSON(data))
+	assert.Equal(t, uint32(1231006505), parsed.MedianTime)
+}
@@ -0,0 +1,153 @@
+package chaintracks
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// MerkleRootStatus is the verification outcome of a merkle root, using ARC/Block Headers Service vocabulary
+type MerkleRootStatus string
+
+const (
+	// MerkleRootConfirmed means the root matches the main chain block at that height
+	MerkleRootConfirmed MerkleRootStatus = "CONFIRMED"
+
+	// MerkleRootInvalid means the root is malformed or does not match the main chain block at that height
+	MerkleRootInvalid MerkleRootStatus = "INVALID"
+
+	// MerkleRootUnableToVerify means the height is above the current tip
+	MerkleRootUnableToVerify MerkleRootStatus = "UNABLE_TO_VERIFY"
+)
+
+// MerkleRootCheck is one merkle root to verify against the main chain
+type MerkleRootCheck struct {
+	MerkleRoot  string `json:"merkleRoot"`
+	BlockHeight uint32 `json:"blockHeight"`
+}
+
+// MerkleRootResult is the outcome of one MerkleRootCheck
+type MerkleRootResult struct {
+	MerkleRoot  string           `json:"merkleRoot"`
+	BlockHeight uint32           `json:"blockHeight"`
+	BlockHash   string           `json:"blockHash,omitempty"` // Set when confirmed
+	Status      MerkleRootStatus `json:"status"`
+}
+
+// MerkleRootsVerification is the outcome of a batch of checks
+// State is INVALID if any root is invalid, otherwise UNABLE_TO_VERIFY if any height is above the tip,
+// otherwise CONFIRMED
+type MerkleRootsVerification struct {
+	State   MerkleRootStatus   `json:"state"`
+	Results []MerkleRootResult `json:"results"`
+}
+
+// VerifyMerkleRoots checks a batch of merkle roots against the main chain of any Chaintracks implementation
+// Results are returned in request order; each height is looked up once per batch
+func VerifyMerkleRoots(ctx context.Context, ct Chaintracks, checks []MerkleRootCheck) *MerkleRootsVerification {
+	tipHeight := ct.GetHeight(ctx)
+	headers := make(map[uint32]*BlockHeader)
+
+	verification := &MerkleRootsVerification{
+		State:   MerkleRootConfirmed,
+		Results: make([]MerkleRootResult, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		result := MerkleRootResult{
+			MerkleRoot:  check.MerkleRoot,
+			BlockHeight: check.BlockHeight,
+			Status:      MerkleRootInvalid,
+		}
+
+		root, err := chainhash.NewHashFromHex(check.MerkleRoot)
+		switch {
+		case err != nil:
+		case check.BlockHeight > tipHeight:
+			result.Status = MerkleRootUnableToVerify
+		default:
+			header, ok := headers[check.BlockHeight]
+			if !ok {
+				header, _ = ct.GetHeaderByHeight(ctx, check.BlockHeight)
+				headers[check.BlockHeight] = header
+			}
+			if header != nil && header.Header != nil && header.MerkleRoot.IsEqual(root) {
+				result.Status = MerkleRootConfirmed
+				result.BlockHash = header.Hash.String()
+			}
+		}
+
+		switch {
+		case result.Status == MerkleRootInvalid:
+			verification.State = MerkleRootInvalid
+		case result.Status == MerkleRootUnableToVerify && verification.State == MerkleRootConfirmed:
+			verification.State = MerkleRootUnableToVerify
+		}
+		verification.Results = append(verification.Results, result)
+	}
+
+	return verification
+}
+
+// maxVerifyRootsPerRequest is how many roots Client.IsValidRootsForHeights sends per request, the server's limit
+const maxVerifyRootsPerRequest = 1000
+
+// IsValidRootsForHeights checks many merkle roots at once, reporting for each height whether its root matches
+// the main chain block there
+// A height above the tip or a nil root is reported as invalid.
+func (cm *ChainManager) IsValidRootsForHeights(ctx context.Context, roots map[uint32]*chainhash.Hash) (map[uint32]bool, error) {
+	valid := make(map[uint32]bool, len(roots))
+	for height, root := range roots {
+		header, err := cm.GetHeaderByHeight(ctx, height)
+		valid[height] = err == nil && root != nil && header.MerkleRoot.IsEqual(root)
+	}
+	return valid, nil
+}
+
+// IsValidRootsForHeights checks many merkle roots at once, reporting for each height whether its root matches
+// the main chain block there
+// Heights in the header cache are answered locally; the rest go to the server's batch verification route, one
+// request per 1000 roots, instead of one request per height. A height above the server's tip or a nil root is
+// reported as invalid.
+func (cc *Client) IsValidRootsForHeights(ctx context.Context, roots map[uint32]*chainhash.Hash) (map[uint32]bool, error) {
+	valid := make(map[uint32]bool, len(roots))
+	var checks []MerkleRootCheck
+	for _, height := range slices.Sorted(maps.Keys(roots)) {
+		root := roots[height]
+		if root == nil {
+			valid[height] = false
+			continue
+		}
+		if header, ok := cc.cachedByHeight(height); ok {
+			valid[height] = header.MerkleRoot.IsEqual(root)
+			continue
+		}
+		checks = append(checks, MerkleRootCheck{MerkleRoot: root.String(), BlockHeight: height})
+	}
+
+	for batch := range slices.Chunk(checks, maxVerifyRootsPerRequest) {
+		verification, err := cc.verifyMerkleRoots(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range verification.Results {
+			valid[batch[i].BlockHeight] = result.Status == MerkleRootConfirmed
+		}
+	}
+	return valid, nil
+}
+
+// verifyMerkleRoots sends a batch of checks to the server's verification route
+func (cc *Client) verifyMerkleRoots(ctx context.Context, checks []MerkleRootCheck) (*MerkleRootsVerification, error) {
+	var verification *MerkleRootsVerification
+	if _, err := cc.requestJSON(ctx, http.MethodPost, cc.baseURL+"/v2/merkleroots/verify", checks, "merkle root verification", &verification); err != nil {
+		return nil, err
+	}
+	if verification == nil || len(verification.Results) != len(checks) {
+		return nil, ErrServerReturnedError
+	}
+	return verification, nil
+}
@@ -0,0 +1,160 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyMerkleRoots(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+	main[2].MerkleRoot = chainhash.Hash{0x22}
+	main[4].MerkleRoot = chainhash.Hash{0x44}
+	fork[0].MerkleRoot = chainhash.Hash{0x33}
+
+	confirmed2 := MerkleRootCheck{MerkleRoot: main[2].MerkleRoot.String(), BlockHeight: 2}
+	confirmed4 := MerkleRootCheck{MerkleRoot: main[4].MerkleRoot.String(), BlockHeight: 4}
+	wrongHeight := MerkleRootCheck{MerkleRoot: main[2].MerkleRoot.String(), BlockHeight: 4}
+	orphanRoot := MerkleRootCheck{MerkleRoot: fork[0].MerkleRoot.String(), BlockHeight: fork[0].Height}
+	aboveTip := MerkleRootCheck{MerkleRoot: chainhash.Hash{0x99}.String(), BlockHeight: 100}
+	malformed := MerkleRootCheck{MerkleRoot: "not-hex", BlockHeight: 1}
+
+	tests := []struct {
+		name             string
+		checks           []MerkleRootCheck
+		expectedState    MerkleRootStatus
+		expectedStatuses []MerkleRootStatus
+	}{
+		{
+			name:             "AllConfirmed",
+			checks:           []MerkleRootCheck{confirmed2, confirmed4, confirmed2},
+			expectedState:    MerkleRootConfirmed,
+			expectedStatuses: []MerkleRootStatus{MerkleRootConfirmed, MerkleRootConfirmed, MerkleRootConfirmed},
+		},
+		{
+			name:             "WrongHeightIsInvalid",
+			checks:           []MerkleRootCheck{confirmed2, wrongHeight},
+			expectedState:    MerkleRootInvalid,
+			expectedStatuses: []MerkleRootStatus{MerkleRootConfirmed, MerkleRootInvalid},
+		},
+		{
+			name:             "OrphanRootIsInvalid",
+			checks:           []MerkleRootCheck{orphanRoot},
+			expectedState:    MerkleRootInvalid,
+			expectedStatuses: []MerkleRootStatus{MerkleRootInvalid},
+		},
+		{
+			name:             "AboveTipIsUnableToVerify",
+			checks:           []MerkleRootCheck{confirmed4, aboveTip},
+			expectedState:    MerkleRootUnableToVerify,
+			expectedStatuses: []MerkleRootStatus{MerkleRootConfirmed, MerkleRootUnableToVerify},
+		},
+		{
+			name:             "InvalidOutranksUnableToVerify",
+			checks:           []MerkleRootCheck{aboveTip, malformed},
+			expectedState:    MerkleRootInvalid,
+			expectedStatuses: []MerkleRootStatus{MerkleRootUnableToVerify, MerkleRootInvalid},
+		},
+		{
+			name:             "EmptyBatchIsConfirmed",
+			checks:           nil,
+			expectedState:    MerkleRootConfirmed,
+			expectedStatuses: []MerkleRootStatus{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verification := VerifyMerkleRoots(t.Context(), cm, tt.checks)
+			require.Len(t, verification.Results, len(tt.checks))
+			assert.Equal(t, tt.expectedState, verification.State)
+
+			statuses := make([]MerkleRootStatus, len(verification.Results))
+			for i, result := range verification.Results {
+				statuses[i] = result.Status
+				if result.Status == MerkleRootConfirmed {
+					assert.Equal(t, main[result.BlockHeight].Hash.String(), result.BlockHash)
+				} else {
+					assert.Empty(t, result.BlockHash)
+				}
+			}
+			assert.Equal(t, tt.expectedStatuses, statuses)
+		})
+	}
+}
+
+func TestIsValidRootsForHeights(t *testing.T) {
+	cm, main, fork := newForkedChainManager(t)
+	main[2].MerkleRoot = chainhash.Hash{0x22}
+	main[4].MerkleRoot = chainhash.Hash{0x44}
+	fork[0].MerkleRoot = chainhash.Hash{0x33}
+
+	roots := map[uint32]*chainhash.Hash{
+		2:              &main[2].MerkleRoot,
+		4:              &main[2].MerkleRoot,
+		fork[0].Height: &fork[0].MerkleRoot,
+		5:              nil,
+		100:            {0x99},
+	}
+	expected := map[uint32]bool{2: true, 4: false, fork[0].Height: false, 5: false, 100: false}
+
+	t.Run("ChainManager", func(t *testing.T) {
+		valid, err := cm.IsValidRootsForHeights(t.Context(), roots)
+		require.NoError(t, err)
+		assert.Equal(t, expected, valid)
+	})
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/v2/merkleroots/verify" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var checks []MerkleRootCheck
+		if err := json.NewDecoder(r.Body).Decode(&checks); err != nil || len(checks) > maxVerifyRootsPerRequest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "value": VerifyMerkleRoots(r.Context(), cm, checks)})
+	}))
+	defer server.Close()
+
+	t.Run("ClientOneRequest", func(t *testing.T) {
+		requests.Store(0)
+		valid, err := NewClient(server.URL).IsValidRootsForHeights(t.Context(), roots)
+		require.NoError(t, err)
+		assert.Equal(t, expected, valid)
+		assert.Equal(t, int32(1), requests.Load(), "a nil root needs no request and the rest share one")
+	})
+
+	t.Run("ClientSplitsLargeBatches", func(t *testing.T) {
+		requests.Store(0)
+		many := make(map[uint32]*chainhash.Hash)
+		for height := range uint32(maxVerifyRootsPerRequest + 10) {
+			many[height] = &main[2].MerkleRoot
+		}
+		valid, err := NewClient(server.URL).IsValidRootsForHeights(t.Context(), many)
+		require.NoError(t, err)
+		assert.Len(t, valid, len(many))
+		assert.True(t, valid[2])
+		assert.False(t, valid[3])
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("ClientServerError", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer failing.Close()
+
+		_, err := NewClient(failing.URL).IsValidRootsForHeights(t.Context(), roots)
+		require.ErrorIs(t, err, ErrServerRequestFailed)
+	})
+}
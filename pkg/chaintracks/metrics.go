@@ -0,0 +1,176 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// metricsSaveInterval is how often the cumulative metrics are written to disk
+	metricsSaveInterval = time.Minute
+
+	// maxMetricsRuns caps the number of past runs kept in the uptime history
+	maxMetricsRuns = 100
+)
+
+// MetricsRun is one process lifetime in the uptime history
+// LastSeen is the last time the run saved its metrics, so a crashed run is cut short by at most metricsSaveInterval
+type MetricsRun struct {
+	StartedAt time.Time `json:"startedAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// MetricsSnapshot holds counters that accumulate across restarts
+type MetricsSnapshot struct {
+	TotalReorgs          uint64       `json:"totalReorgs"`
+	HeadersProcessed     uint64       `json:"headersProcessed"` // Headers received from P2P or remote sync, not loaded from disk
+	Starts               uint64       `json:"starts"`
+	FirstStart           time.Time    `json:"firstStart"`
+	UptimeSeconds        float64      `json:"uptimeSeconds"` // Summed over all runs in the history
+	CurrentUptimeSeconds float64      `json:"currentUptimeSeconds"`
+	LoadSeconds          float64      `json:"loadSeconds"` // Time the current run took to load the local header files
+	Runs                 []MetricsRun `json:"runs"`        // Oldest first, the last entry is the current run
+}
+
+// metricsTracker accumulates counters and persists them; the zero value counts in memory only
+type metricsTracker struct {
+	mu       sync.Mutex
+	path     string
+	snapshot MetricsSnapshot
+}
+
+// load restores the persisted counters from path and starts a new run
+// A missing or corrupt file starts the counters from zero
+func (m *metricsTracker) load(path string, now time.Time, logger Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.path = path
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // Path is built from the configured storage directory
+		if err := json.Unmarshal(data, &m.snapshot); err != nil {
+			logger.Warn("Ignoring corrupt metrics file", "path", path, "error", err)
+			m.snapshot = MetricsSnapshot{}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		logger.Error("Failed to read metrics file", "path", path, "error", err)
+	}
+
+	m.snapshot.Starts++
+	if m.snapshot.FirstStart.IsZero() {
+		m.snapshot.FirstStart = now
+	}
+	m.snapshot.Runs = append(m.snapshot.Runs, MetricsRun{StartedAt: now, LastSeen: now})
+	if len(m.snapshot.Runs) > maxMetricsRuns {
+		m.snapshot.Runs = m.snapshot.Runs[len(m.snapshot.Runs)-maxMetricsRuns:]
+	}
+}
+
+func (m *metricsTracker) addReorg() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot.TotalReorgs++
+}
+
+func (m *metricsTracker) addHeaders(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot.HeadersProcessed += uint64(n) //nolint:gosec // n is a slice length
+}
+
+func (m *metricsTracker) setLoadDuration(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot.LoadSeconds = d.Seconds()
+}
+
+// touchLocked extends the current run to now (must be called with lock held)
+func (m *metricsTracker) touchLocked(now time.Time) {
+	if len(m.snapshot.Runs) == 0 {
+		m.snapshot.Runs = append(m.snapshot.Runs, MetricsRun{StartedAt: now})
+	}
+	m.snapshot.Runs[len(m.snapshot.Runs)-1].LastSeen = now
+}
+
+// current returns a copy of the counters with uptime computed up to now
+func (m *metricsTracker) current(now time.Time) MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.touchLocked(now)
+	snapshot := m.snapshot
+	snapshot.Runs = append([]MetricsRun(nil), m.snapshot.Runs...)
+
+	snapshot.UptimeSeconds = 0
+	for _, run := range snapshot.Runs {
+		snapshot.UptimeSeconds += run.LastSeen.Sub(run.StartedAt).Seconds()
+	}
+	current := snapshot.Runs[len(snapshot.Runs)-1]
+	snapshot.CurrentUptimeSeconds = current.LastSeen.Sub(current.StartedAt).Seconds()
+	return snapshot
+}
+
+// save writes the counters atomically; a tracker without a path is a no-op
+func (m *metricsTracker) save(now time.Time) error {
+	snapshot := m.current(now)
+
+	m.mu.Lock()
+	path := m.path
+	m.mu.Unlock()
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(&snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to rename metrics file: %w", err)
+	}
+	return nil
+}
+
+// metricsPath returns the path of the persisted metrics file
+func (cm *ChainManager) metricsPath() string {
+	return filepath.Join(cm.localStoragePath, cm.network+"NetMetrics.json")
+}
+
+// persistMetrics saves the metrics periodically and once more when ctx is cancelled
+func (cm *ChainManager) persistMetrics(ctx context.Context) {
+	ticker := time.NewTicker(metricsSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := cm.metrics.save(time.Now()); err != nil {
+				cm.log().Error("Failed to save metrics", "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := cm.metrics.save(time.Now()); err != nil {
+				cm.log().Error("Failed to save metrics", "error", err)
+			}
+		}
+	}
+}
+
+// GetMetrics returns the counters accumulated across restarts
+func (cm *ChainManager) GetMetrics() MetricsSnapshot {
+	return cm.metrics.current(time.Now())
+}
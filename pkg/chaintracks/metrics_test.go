@@ -0,0 +1,106 @@
+package chaintracks
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsTrackerPersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mainNetMetrics.json")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := &metricsTracker{}
+	first.load(path, start, slog.New(slog.DiscardHandler))
+	first.addReorg()
+	first.addHeaders(10)
+	require.NoError(t, first.save(start.Add(time.Hour)))
+
+	second := &metricsTracker{}
+	second.load(path, start.Add(2*time.Hour), slog.New(slog.DiscardHandler))
+	second.addHeaders(5)
+
+	snapshot := second.current(start.Add(150 * time.Minute))
+	assert.Equal(t, uint64(1), snapshot.TotalReorgs)
+	assert.Equal(t, uint64(15), snapshot.HeadersProcessed)
+	assert.Equal(t, uint64(2), snapshot.Starts)
+	assert.Equal(t, start, snapshot.FirstStart)
+	require.Len(t, snapshot.Runs, 2)
+	assert.InDelta(t, 90*60, snapshot.UptimeSeconds, 0.001, "downtime between runs is not counted")
+	assert.InDelta(t, 30*60, snapshot.CurrentUptimeSeconds, 0.001)
+}
+
+func TestMetricsTrackerLoad(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{name: "MissingFile"},
+		{name: "CorruptFile", contents: "{not json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "metrics.json")
+			if tt.contents != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o600))
+			}
+
+			m := &metricsTracker{}
+			m.load(path, now, slog.New(slog.DiscardHandler))
+			snapshot := m.current(now)
+			assert.Equal(t, uint64(1), snapshot.Starts)
+			assert.Zero(t, snapshot.HeadersProcessed)
+			assert.Len(t, snapshot.Runs, 1)
+		})
+	}
+}
+
+func TestMetricsTrackerCapsRunHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	now := time.Now()
+
+	for i := range maxMetricsRuns + 5 {
+		m := &metricsTracker{}
+		m.load(path, now.Add(time.Duration(i)*time.Minute), slog.New(slog.DiscardHandler))
+		require.NoError(t, m.save(now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	m := &metricsTracker{}
+	m.load(path, now, slog.New(slog.DiscardHandler))
+	snapshot := m.current(now)
+	assert.Len(t, snapshot.Runs, maxMetricsRuns)
+	assert.Equal(t, uint64(maxMetricsRuns+6), snapshot.Starts)
+}
+
+func TestMetricsTrackerLoadDuration(t *testing.T) {
+	m := &metricsTracker{}
+	m.load(filepath.Join(t.TempDir(), "metrics.json"), time.Now(), slog.New(slog.DiscardHandler))
+	m.setLoadDuration(1500 * time.Millisecond)
+
+	assert.InDelta(t, 1.5, m.current(time.Now()).LoadSeconds, 0)
+}
+
+func TestMetricsTrackerWithoutPath(t *testing.T) {
+	m := &metricsTracker{}
+	m.addReorg()
+	require.NoError(t, m.save(time.Now()))
+
+	snapshot := m.current(time.Now())
+	assert.Equal(t, uint64(1), snapshot.TotalReorgs)
+	assert.Len(t, snapshot.Runs, 1)
+}
+
+func TestChainManagerCountsReorgs(t *testing.T) {
+	cm, _, fork := newForkedChainManager(t)
+
+	require.NoError(t, cm.SetChainTip(t.Context(), fork))
+	assert.Equal(t, uint64(1), cm.GetMetrics().TotalReorgs)
+}
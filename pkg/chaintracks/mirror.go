@@ -0,0 +1,184 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultMirrorPageSize       = 2000
+	defaultMirrorReconnectDelay = 10 * time.Second
+	mirrorMaxRewind             = 100 // Blocks searched back for the fork point of a reorg
+)
+
+// MirrorSyncConfig configures a MirrorSync
+type MirrorSyncConfig struct {
+	URL            string        // Chaintracks server to mirror
+	FallbackURLs   []string      // Servers to fail over to
+	Client         *Client       // Optional preconfigured client, e.g. with a bearer token; replaces URL and FallbackURLs
+	PageSize       int           // Headers per /v2/headers request (default 2000)
+	ReconnectDelay time.Duration // Wait before restarting after a failed catch-up (default 10s)
+	Logger         Logger        // Defaults to the package default logger
+}
+
+// MirrorSync follows another chaintracks server, making the ChainManager a read replica of it
+// It listens to the server's SSE tip stream and, on start and on every new tip, finds the highest local block the
+// server agrees with and pages /v2/headers from there, so initial sync, catch-up and reorgs take the same path.
+// Headers must link and carry valid proof of work. The ChainManager must already hold at least the genesis header.
+type MirrorSync struct {
+	cm           *ChainManager
+	client       *Client
+	powLimitBits uint32
+	config       MirrorSyncConfig
+}
+
+// NewMirrorSync creates a MirrorSync feeding cm from the configured server
+func NewMirrorSync(cm *ChainManager, config MirrorSyncConfig) (*MirrorSync, error) {
+	client := config.Client
+	if client == nil {
+		if config.URL == "" {
+			return nil, fmt.Errorf("%w: mirror sync requires a URL", ErrInvalidConfig)
+		}
+		client = NewClient(config.URL, config.FallbackURLs...)
+	}
+	if config.PageSize <= 0 {
+		config.PageSize = defaultMirrorPageSize
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = defaultMirrorReconnectDelay
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &MirrorSync{
+		cm:           cm,
+		client:       client,
+		powLimitBits: DefaultsForNetwork(cm.network).PowLimitBits,
+		config:       config,
+	}, nil
+}
+
+// Run mirrors the server until ctx is cancelled, restarting after ReconnectDelay whenever a catch-up fails
+func (m *MirrorSync) Run(ctx context.Context) {
+	for {
+		err := m.follow(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		m.config.Logger.Warn("Mirror sync interrupted", "url", m.client.baseURL, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.config.ReconnectDelay):
+		}
+	}
+}
+
+// follow subscribes to the tip stream, catches up and then catches up again on every new tip
+// The stream is opened first so no tip announced during the initial catch-up is missed.
+func (m *MirrorSync) follow(ctx context.Context) error {
+	network, err := m.client.GetNetwork(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get mirror network: %w", err)
+	}
+	if network != m.cm.network {
+		return fmt.Errorf("%w: mirror serves %q, chain is %q", ErrInvalidConfig, network, m.cm.network)
+	}
+
+	tips, err := m.client.Start(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = m.client.Stop()
+	}()
+
+	if err := m.CatchUp(ctx); err != nil {
+		return err
+	}
+	for range tips {
+		if err := m.CatchUp(ctx); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// CatchUp brings the local chain level with the server's tip
+func (m *MirrorSync) CatchUp(ctx context.Context) error {
+	tip := m.cm.GetTip(ctx)
+	if tip == nil {
+		return fmt.Errorf("%w: chain has no headers to extend", ErrHeaderNotFound)
+	}
+
+	remote, err := m.client.fetchHeader(ctx, m.client.baseURL+"/v2/tip/header")
+	if err != nil {
+		return fmt.Errorf("failed to get mirror tip: %w", err)
+	}
+	if remote.Hash == tip.Hash {
+		return nil
+	}
+
+	height, err := m.forkPoint(ctx, min(tip.Height, remote.Height))
+	if err != nil {
+		return err
+	}
+
+	syncing := remote.Height > tip.Height+1
+	if syncing {
+		m.cm.beginSync(ctx, m.client.baseURL, remote.Height)
+	}
+	err = m.fetchFrom(ctx, height+1, syncing)
+	if syncing {
+		m.cm.endSync(ctx, err)
+	}
+	if err == nil && syncing {
+		m.config.Logger.Info("Mirror sync caught up", "url", m.client.baseURL, "height", m.cm.GetHeight(ctx))
+	}
+	return err
+}
+
+// forkPoint returns the highest main-chain height at or below height where the server has the same block
+func (m *MirrorSync) forkPoint(ctx context.Context, height uint32) (uint32, error) {
+	for rewind := 0; rewind <= mirrorMaxRewind; rewind++ {
+		local, err := m.cm.GetHeaderByHeight(ctx, height)
+		if err != nil {
+			return 0, err
+		}
+		remote, err := m.client.GetHeaderByHeight(ctx, height)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get mirror header %d: %w", height, err)
+		}
+		if remote.Hash == local.Hash || height == 0 {
+			return height, nil
+		}
+		height--
+	}
+	return 0, fmt.Errorf("%w: no common block with mirror in the last %d", ErrCommonAncestorNotFound, mirrorMaxRewind)
+}
+
+// fetchFrom pages headers from height start until the server has no more, linking each page into the chain
+func (m *MirrorSync) fetchFrom(ctx context.Context, start uint32, syncing bool) error {
+	pageSize := uint32(m.config.PageSize) //nolint:gosec // Positive, set by the operator
+	for height := start; ; height += pageSize {
+		headers, err := m.client.GetHeaders(ctx, height, pageSize)
+		if err != nil {
+			return fmt.Errorf("failed to get mirror headers from %d: %w", height, err)
+		}
+		if len(headers) == 0 {
+			return nil
+		}
+		if err := m.cm.connectHeaders(ctx, headers, m.powLimitBits); err != nil {
+			return fmt.Errorf("rejected headers from mirror: %w", err)
+		}
+		if syncing {
+			m.cm.advanceSync(ctx, len(headers), nil)
+		}
+		if uint32(len(headers)) < pageSize { //nolint:gosec // Bounded by pageSize
+			return nil
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package chaintracks
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMirror serves the v2 endpoints MirrorSync uses for an stn chain, genesis first
+type fakeMirror struct {
+	mu       sync.Mutex
+	chain    []*block.Header
+	tipAdded chan struct{}
+}
+
+func newFakeMirror(t *testing.T, chain []*block.Header) (*fakeMirror, *httptest.Server) {
+	t.Helper()
+
+	f := &fakeMirror{chain: chain, tipAdded: make(chan struct{}, 1)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/network", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success","value":"stn"}`))
+	})
+	mux.HandleFunc("/v2/tip/header", func(w http.ResponseWriter, _ *http.Request) {
+		f.writeHeader(w, -1)
+	})
+	mux.HandleFunc("/v2/header/height/{height}", func(w http.ResponseWriter, r *http.Request) {
+		height, _ := strconv.Atoi(r.PathValue("height"))
+		f.writeHeader(w, height)
+	})
+	mux.HandleFunc("/v2/headers", func(w http.ResponseWriter, r *http.Request) {
+		height, _ := strconv.Atoi(r.URL.Query().Get("height"))
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		f.mu.Lock()
+		var data []byte
+		for i := height; i < min(height+count, len(f.chain)); i++ {
+			data = append(data, f.chain[i].Bytes()...)
+		}
+		f.mu.Unlock()
+		_, _ = fmt.Fprintf(w, `{"status":"success","value":"%s"}`, hex.EncodeToString(data))
+	})
+	mux.HandleFunc("/v2/tip/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-f.tipAdded:
+				tip := f.blockHeader(-1)
+				data, _ := json.Marshal(tip)
+				_, _ = fmt.Fprintf(w, "event: tip\ndata: %s\n\n", data)
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+// blockHeader returns the header at height, or the tip for -1
+func (f *fakeMirror) blockHeader(height int) *BlockHeader {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if height < 0 {
+		height = len(f.chain) - 1
+	}
+	if height >= len(f.chain) {
+		return nil
+	}
+	return &BlockHeader{Header: f.chain[height], Height: uint32(height), Hash: f.chain[height].Hash()} //nolint:gosec // Test data
+}
+
+func (f *fakeMirror) writeHeader(w http.ResponseWriter, height int) {
+	header := f.blockHeader(height)
+	if header == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	data, _ := json.Marshal(header)
+	_, _ = fmt.Fprintf(w, `{"status":"success","value":%s}`, data)
+}
+
+// extend appends a header to the served chain and announces it on the stream
+func (f *fakeMirror) extend(header *block.Header) {
+	f.mu.Lock()
+	f.chain = append(f.chain, header)
+	f.mu.Unlock()
+	f.tipAdded <- struct{}{}
+}
+
+func TestMirrorSyncCatchUp(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 120, 0)
+	fork := mineRegtestChain(t, chain[2].Hash(), 5, 1)
+
+	tests := []struct {
+		name          string
+		local         []*block.Header
+		remote        []*block.Header
+		expectedTip   *block.Header
+		expectedError error
+	}{
+		{name: "PagesFromLocalTip", local: chain[:5], remote: chain, expectedTip: chain[119]},
+		{name: "InitialSyncFromGenesis", remote: chain, expectedTip: chain[119]},
+		{name: "AlreadyCaughtUp", local: chain[:5], remote: chain[:5], expectedTip: chain[4]},
+		{name: "FollowsReorg", local: chain[:5], remote: append(chain[:3:3], fork...), expectedTip: fork[4]},
+		{
+			name:          "RejectsBrokenChain",
+			local:         chain[:5],
+			remote:        append(chain[:10:10], chain[11:]...),
+			expectedError: ErrBrokenChain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newWireChainManager(t, genesis, tt.local)
+			_, server := newFakeMirror(t, append([]*block.Header{genesis}, tt.remote...))
+			m, err := NewMirrorSync(cm, MirrorSyncConfig{URL: server.URL, PageSize: 50})
+			require.NoError(t, err)
+
+			err = m.CatchUp(t.Context())
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTip.Hash(), cm.GetTip(t.Context()).Hash)
+			assert.False(t, cm.SyncStatus().Active)
+		})
+	}
+}
+
+func TestMirrorSyncFollowsStream(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 10, 0)
+	mirror, server := newFakeMirror(t, append([]*block.Header{genesis}, chain...))
+	cm := newWireChainManager(t, genesis, nil)
+
+	m, err := NewMirrorSync(cm, MirrorSyncConfig{URL: server.URL})
+	require.NoError(t, err)
+	go m.Run(t.Context())
+
+	require.Eventually(t, func() bool { return cm.GetHeight(t.Context()) == 10 }, 5*time.Second, 10*time.Millisecond)
+
+	next := mineRegtestHeader(t, chain[9].Hash())
+	mirror.extend(next)
+	require.Eventually(t, func() bool { return cm.GetTip(t.Context()).Hash == next.Hash() }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNewMirrorSyncValidatesConfig(t *testing.T) {
+	_, err := NewMirrorSync(&ChainManager{network: "stn"}, MirrorSyncConfig{})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	m, err := NewMirrorSync(&ChainManager{network: "stn"}, MirrorSyncConfig{Client: NewClient("http://replica.example")})
+	require.NoError(t, err)
+	assert.Equal(t, defaultMirrorPageSize, m.config.PageSize)
+}
@@ -0,0 +1,132 @@
+package chaintracks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/bsv-blockchain/go-sdk/auth"
+	"github.com/bsv-blockchain/go-sdk/auth/authpayload"
+	"github.com/bsv-blockchain/go-sdk/auth/brc104"
+	"github.com/bsv-blockchain/go-sdk/auth/transports"
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/wallet"
+)
+
+// mutualAuthHandshakeTimeout bounds the BRC-103 handshake, in milliseconds as the SDK expects
+const mutualAuthHandshakeTimeout = 30000
+
+// mutualAuthClient sends requests as BRC-103 general messages over the BRC-104 HTTP transport
+// The server's signed response arrives through the peer's general message listener, matched by request ID.
+// The SDK peer updates shared session state without locking, so requests are sent one at a time.
+type mutualAuthClient struct {
+	mu        sync.Mutex
+	peer      *auth.Peer
+	sessions  *auth.DefaultSessionManager
+	serverKey *ec.PublicKey // Learned on the first handshake
+
+	// Request in flight and its verified response, set from within ToPeer while mu is held
+	awaiting string
+	response *http.Response
+}
+
+// newMutualAuthClient creates a peer for the server at baseURL; the handshake happens on the first request
+func newMutualAuthClient(w wallet.Interface, baseURL string, httpClient *http.Client) (*mutualAuthClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	// The transport joins request paths, and /.well-known/auth, onto the origin
+	transport, err := transports.NewSimplifiedHTTPTransport(&transports.SimplifiedHTTPTransportOptions{
+		BaseURL: u.Scheme + "://" + u.Host,
+		Client:  httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	autoPersist := false
+	m := &mutualAuthClient{sessions: auth.NewSessionManager()}
+	m.peer = auth.NewPeer(&auth.PeerOptions{
+		Wallet:                 w,
+		Transport:              transport,
+		SessionManager:         m.sessions,
+		AutoPersistLastSession: &autoPersist,
+	})
+	m.peer.ListenForGeneralMessages(m.onResponse)
+	return m, nil
+}
+
+// do sends req and returns the server's verified response
+func (m *mutualAuthClient) do(req *http.Request) (*http.Response, error) {
+	requestID := make([]byte, brc104.RequestIDLength)
+	if _, err := rand.Read(requestID); err != nil {
+		return nil, err
+	}
+	payload, err := authpayload.FromHTTPRequest(requestID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	serverKey, err := m.session(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// The transport performs the request synchronously and hands the response to onResponse
+	m.awaiting, m.response = base64.StdEncoding.EncodeToString(requestID), nil
+	defer func() { m.awaiting, m.response = "", nil }()
+	if err := m.peer.ToPeer(req.Context(), payload, serverKey, mutualAuthHandshakeTimeout); err != nil {
+		m.reset()
+		return nil, err
+	}
+
+	if m.response == nil {
+		return nil, ErrUnsignedResponse
+	}
+	return m.response, nil
+}
+
+// session returns the server's identity key, performing the handshake if there is no session yet
+func (m *mutualAuthClient) session(ctx context.Context) (*ec.PublicKey, error) {
+	if m.serverKey != nil {
+		return m.serverKey, nil
+	}
+	session, err := m.peer.GetAuthenticatedSession(ctx, nil, mutualAuthHandshakeTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("BRC-103 handshake failed: %w", err)
+	}
+	m.serverKey = session.PeerIdentityKey
+	return m.serverKey, nil
+}
+
+// reset drops the session after a failed request, e.g. because the server restarted, so the next request handshakes again
+func (m *mutualAuthClient) reset() {
+	if m.serverKey == nil {
+		return
+	}
+	if session, err := m.sessions.GetSession(m.serverKey.ToDERHex()); err == nil && session != nil {
+		m.sessions.RemoveSession(session)
+	}
+	m.serverKey = nil
+}
+
+// onResponse records the verified response if it answers the request in flight
+func (m *mutualAuthClient) onResponse(_ context.Context, sender *ec.PublicKey, payload []byte) error {
+	requestID, resp, err := authpayload.ToHTTPResponse(payload, authpayload.WithSenderPublicKey(sender))
+	if err != nil {
+		return err
+	}
+	if base64.StdEncoding.EncodeToString(requestID) == m.awaiting {
+		m.response = resp
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package chaintracks
+
+import "time"
+
+// NetworkDefaults holds the built-in bootstrap configuration for a network
+// Everything here can be overridden by the embedding application or server config
+type NetworkDefaults struct {
+	CDNURLs        []string // CDN mirrors serving <network>NetBlockHeaders.json and .headers files, tried in order
+	BootstrapPeers []string // Well-known libp2p multiaddrs used to join the P2P network
+	PowLimitBits   uint32   // Easiest allowed target in compact form, 0 uses the regtest limit
+
+	NodeMagic [4]byte // Bitcoin P2P message start bytes, zero when WireSync does not support the network
+	NodePort  string  // Default Bitcoin P2P port of the network's nodes
+
+	StaleTipThreshold time.Duration // Time without a new tip before the stale-tip watchdog alerts, 0 leaves it off
+}
+
+// regtestPowLimitBits is the easiest target accepted on networks without a configured limit
+const regtestPowLimitBits = 0x207fffff
+
+// networkDefaults is the curated per-network default configuration
+//
+//nolint:gochecknoglobals // Read-only built-in configuration
+var networkDefaults = map[string]NetworkDefaults{
+	"main": {
+		CDNURLs: []string{
+			"https://cdn.projectbabbage.com/blockheaders",
+		},
+		BootstrapPeers: []string{
+			"/dns4/teranode-eks-mainnet-us-1-p2p.bsvb.tech/tcp/9905/p2p/12D3KooWH5JVqGdaw7JEizmysCfRRcPGTFfvRJF7Hkure7oQWYnb",
+			"/dns4/teranode-eks-mainnet-eu-1-p2p.bsvb.tech/tcp/9905/p2p/12D3KooW9z2JRV37TqsmU8sDQcSQDZGSgtPpvWUmVegYxYvXfW9H",
+		},
+		PowLimitBits:      0x1d00ffff,
+		NodeMagic:         [4]byte{0xe3, 0xe1, 0xf3, 0xe8},
+		NodePort:          "8333",
+		StaleTipThreshold: 60 * time.Minute,
+	},
+	"test": {
+		CDNURLs: []string{
+			"https://cdn.projectbabbage.com/blockheaders",
+		},
+		BootstrapPeers: []string{
+			"/dns4/teranode-eks-testnet-us-1-p2p.bsvb.tech/tcp/9905/p2p/12D3KooWKHfBrniPSRUG7JbBp3mxK1dGkb3uKk4TbVC3Ew4vmcQk",
+			"/dns4/teranode-eks-testnet-eu-2-p2p.bsvb.tech/tcp/9905/p2p/12D3KooWR9DMm622shDLAe5hQZk4phNERF84S77JocXfLyZU9NsF",
+		},
+		PowLimitBits:      0x1d00ffff,
+		NodeMagic:         [4]byte{0xf4, 0xe5, 0xf3, 0xf4},
+		NodePort:          "18333",
+		StaleTipThreshold: 2 * time.Hour,
+	},
+	"stn": {
+		BootstrapPeers: []string{
+			"/dns4/teranode-eks-ttn-us-1-p2p.bsvb.tech/tcp/9905/p2p/12D3KooWFj5nh1m3iAooxnfp5VvDtufYajTpBSopUt7anj4XLqJp",
+			"/dns4/teranode-eks-ttn-eu-1-p2p.bsvb.tech/tcp/9905/p2p/12D3KooWDnQoDerA2KC8xD5hDqiSp21zf9zS5ezM32wuXgLUaden",
+		},
+		NodeMagic: [4]byte{0xfb, 0xce, 0xc4, 0xf9},
+		NodePort:  "9333",
+	},
+	NetworkRegtest: {
+		PowLimitBits: regtestPowLimitBits,
+	},
+}
+
+// DefaultsForNetwork returns a copy of the built-in defaults for a network
+// Unknown networks return empty defaults
+func DefaultsForNetwork(network string) NetworkDefaults {
+	defaults := networkDefaults[network]
+	return NetworkDefaults{
+		CDNURLs:        append([]string(nil), defaults.CDNURLs...),
+		BootstrapPeers: append([]string(nil), defaults.BootstrapPeers...),
+		PowLimitBits:   defaults.PowLimitBits,
+		NodeMagic:      defaults.NodeMagic,
+		NodePort:       defaults.NodePort,
+
+		StaleTipThreshold: defaults.StaleTipThreshold,
+	}
+}
@@ -0,0 +1,39 @@
+package chaintracks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultsForNetwork(t *testing.T) {
+	tests := []struct {
+		name        string
+		network     string
+		expectPeers bool
+		expectCDN   bool
+	}{
+		{name: "MainHasPeersAndCDN", network: "main", expectPeers: true, expectCDN: true},
+		{name: "TestHasPeersAndCDN", network: "test", expectPeers: true, expectCDN: true},
+		{name: "STNHasPeers", network: "stn", expectPeers: true, expectCDN: false},
+		{name: "UnknownNetworkIsEmpty", network: "regtest", expectPeers: false, expectCDN: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := DefaultsForNetwork(tt.network)
+			assert.Equal(t, tt.expectPeers, len(defaults.BootstrapPeers) > 0)
+			assert.Equal(t, tt.expectCDN, len(defaults.CDNURLs) > 0)
+		})
+	}
+}
+
+func TestDefaultsForNetworkReturnsCopy(t *testing.T) {
+	defaults := DefaultsForNetwork("main")
+	defaults.BootstrapPeers[0] = "modified"
+	defaults.CDNURLs[0] = "modified"
+
+	fresh := DefaultsForNetwork("main")
+	assert.NotEqual(t, "modified", fresh.BootstrapPeers[0])
+	assert.NotEqual(t, "modified", fresh.CDNURLs[0])
+}
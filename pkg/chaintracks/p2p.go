@@ -3,66 +3,139 @@ package chaintracks
 import (
 	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
 	"github.com/bsv-blockchain/go-sdk/block"
 	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/p2p/transport/tcpreuse"
 )
 
+// P2PConfig tunes the libp2p host used to receive block announcements
+// Zero values keep the go-p2p-message-bus defaults. The host always listens on all interfaces;
+// use AnnounceAddrs to control which addresses are advertised to peers.
+type P2PConfig struct {
+	Port             int      // TCP listen port, 0 picks a random port
+	AnnounceAddrs    []string // Multiaddrs advertised instead of the detected local addresses
+	BootstrapPeers   []string // Defaults to the network's well-known peers when empty
+	MaxConnections   int      // Connection manager high water mark
+	MinConnections   int      // Connection manager low water mark, must not exceed MaxConnections
+	DisablePortReuse bool     // Turn off SO_REUSEPORT for TCP; applies process-wide
+	Logger           Logger   // Defaults to the package default logger
+}
+
+// Validate checks the port range and connection limits
+func (c P2PConfig) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("%w: port %d out of range", ErrInvalidP2PConfig, c.Port)
+	}
+	if c.MaxConnections < 0 || c.MinConnections < 0 {
+		return fmt.Errorf("%w: connection limits must not be negative", ErrInvalidP2PConfig)
+	}
+	if c.MaxConnections > 0 && c.MinConnections > c.MaxConnections {
+		return fmt.Errorf("%w: min connections %d exceeds max connections %d", ErrInvalidP2PConfig, c.MinConnections, c.MaxConnections)
+	}
+	return nil
+}
+
+// NewP2PClient creates a P2P client whose identity and peer cache are persisted under storagePath
+func NewP2PClient(storagePath, network string, cfg P2PConfig) (p2p.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = getDefaultLogger()
+	}
+	return newP2PClient(storagePath, network, cfg, loadPeerBook(filepath.Join(storagePath, peerBookFile), logger), logger)
+}
+
+// newP2PClient creates a P2P client that also dials the reliable peers of book, marking the bootstrap peers in it
+func newP2PClient(storagePath, network string, cfg P2PConfig, book *PeerBook, logger Logger) (p2p.Client, error) {
+	privKey, err := LoadOrGeneratePrivateKey(storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load/generate private key: %w", err)
+	}
+
+	bootstrapPeers := cfg.BootstrapPeers
+	if len(bootstrapPeers) == 0 {
+		bootstrapPeers = DefaultsForNetwork(network).BootstrapPeers
+	}
+
+	// Historically reliable peers are dialed alongside the bootstrap peers for faster cold starts
+	book.markBootstrap(bootstrapPeers, time.Now())
+	if preferred := book.preferredAddrs(maxPreferredPeers); len(preferred) > 0 {
+		logger.Info("Reconnecting to historically reliable peers", "peers", len(preferred))
+		bootstrapPeers = append(slices.Clone(bootstrapPeers), preferred...)
+	}
+	if err := book.save(time.Now()); err != nil {
+		logger.Error("Failed to save peer book", "error", err)
+	}
+	bootstrapPeers = book.withoutBanned(bootstrapPeers)
+
+	if cfg.DisablePortReuse {
+		tcpreuse.EnvReuseportVal = false
+	}
+
+	p2pClient, err := p2p.NewClient(p2p.Config{
+		Name:           "go-chaintracks",
+		Logger:         p2pLogger{logger: logger},
+		PrivateKey:     privKey,
+		Port:           cfg.Port,
+		AnnounceAddrs:  cfg.AnnounceAddrs,
+		PeerCacheFile:  filepath.Join(storagePath, "peer_cache.json"),
+		BootstrapPeers: bootstrapPeers,
+		MaxConnections: cfg.MaxConnections,
+		MinConnections: cfg.MinConnections,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create P2P client: %w", err)
+	}
+	return p2pClient, nil
+}
+
 // Start initializes and starts the P2P listener for block announcements
 // Returns a channel that consumers can use to receive tip change notifications
 func (cm *ChainManager) Start(ctx context.Context) (<-chan *BlockHeader, error) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
+	// The peer book is loaded once and kept across restarts of P2P, so its in-memory history is never replaced
+	if cm.peerBook == nil {
+		cm.peerBook = loadPeerBook(filepath.Join(cm.localStoragePath, peerBookFile), cm.log())
+	}
+	book := cm.peerBook
+
 	// Create P2P client internally if one wasn't provided
 	if cm.p2pClient == nil {
-		privKey, err := LoadOrGeneratePrivateKey(cm.localStoragePath)
+		p2pClient, err := newP2PClient(cm.localStoragePath, cm.network, P2PConfig{Logger: cm.log()}, book, cm.log())
 		if err != nil {
-			return nil, fmt.Errorf("failed to load/generate private key: %w", err)
-		}
-
-		p2pClient, err := p2p.NewClient(p2p.Config{
-			Name:          "go-chaintracks",
-			Logger:        &p2p.DefaultLogger{},
-			PrivateKey:    privKey,
-			Port:          0,
-			PeerCacheFile: filepath.Join(cm.localStoragePath, "peer_cache.json"),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to create P2P client: %w", err)
+			return nil, err
 		}
 		cm.p2pClient = p2pClient
 	}
 
 	cm.msgChan = make(chan *BlockHeader, 1) // Buffered channel (size 1) for latest tip only
 
-	// Subscribe to block topic
-	topic := fmt.Sprintf("teranode/bitcoin/1.0.0/%snet-block", cm.network)
-	log.Printf("Subscribing to P2P topic: %s", topic)
-
-	msgChan := cm.p2pClient.Subscribe(topic)
-
-	// Start message handler goroutine
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				close(cm.msgChan)
-				return
-			case msg := <-msgChan:
-				if err := cm.handleBlockMessage(ctx, msg.Data); err != nil {
-					log.Printf("Error handling block message: %v", err)
-				}
-			}
-		}
-	}()
+	// Subscribe to every supported block topic version; messages are validated in parallel and applied in order
+	incoming := make(chan topicMessage, 100)
+	for i := range blockTopicVersions {
+		go cm.subscribeTopic(ctx, cm.p2pClient, &blockTopicVersions[i], incoming)
+	}
+
+	go cm.trackPeers(ctx, book)
+
+	go cm.monitorLag(ctx)
+
+	go cm.persistMetrics(ctx)
+
+	go cm.runIngest(ctx, incoming, book, cm.msgChan)
 
 	return cm.msgChan, nil
 }
@@ -76,11 +149,26 @@ func (cm *ChainManager) Stop() error {
 		return nil
 	}
 
+	if err := cm.metrics.save(time.Now()); err != nil {
+		cm.log().Error("Failed to save metrics", "error", err)
+	}
+
 	err := cm.p2pClient.Close()
 	cm.p2pClient = nil
 	return err
 }
 
+// PeerCount returns the number of connected P2P peers, 0 before P2P is started
+func (cm *ChainManager) PeerCount() int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if cm.p2pClient == nil {
+		return 0
+	}
+	return len(cm.p2pClient.GetPeers())
+}
+
 // GetPeers returns information about connected P2P peers
 // Returns empty slice if P2P is not running
 func (cm *ChainManager) GetPeers() []PeerInfo {
@@ -99,36 +187,45 @@ func (cm *ChainManager) GetPeers() []PeerInfo {
 			Name:  p.Name,
 			Addrs: p.Addrs,
 		}
+		if cm.peerBook == nil {
+			continue
+		}
+		if record, ok := cm.peerBook.lookup(p.ID); ok {
+			peers[i].LastSeen = record.LastSeen
+			peers[i].LatencyMs = record.LatencyMs
+		}
 	}
 	return peers
 }
 
-// handleBlockMessage processes a received block message
-func (cm *ChainManager) handleBlockMessage(ctx context.Context, data []byte) error {
-	log.Printf("Raw block message: %s", string(data))
-
-	var blockMsg BlockMessage
-	if err := json.Unmarshal(data, &blockMsg); err != nil {
-		return fmt.Errorf("failed to unmarshal block message: %w", err)
-	}
-
-	log.Printf("Received block: height=%d hash=%s from=%s datahub=%s", blockMsg.Height, blockMsg.Hash, blockMsg.PeerID, blockMsg.DataHubURL)
-
-	// Decode header from hex
+// validateBlockMessage decodes an announced header and checks its proof of work
+// It reads no chain state, so it runs on the validation workers off the receive path
+func (cm *ChainManager) validateBlockMessage(blockMsg *BlockMessage) (*block.Header, error) {
 	headerBytes, err := hex.DecodeString(blockMsg.Header)
 	if err != nil {
-		return fmt.Errorf("failed to decode header hex: %w", err)
+		return nil, fmt.Errorf("failed to decode header hex: %w", err)
 	}
 
 	if len(headerBytes) != 80 {
-		return fmt.Errorf("%w: %d bytes", ErrInvalidHeaderSize, len(headerBytes))
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidHeaderSize, len(headerBytes))
 	}
 
 	header, err := block.NewHeaderFromBytes(headerBytes)
 	if err != nil {
-		return fmt.Errorf("failed to parse header: %w", err)
+		return nil, fmt.Errorf("failed to parse header: %w", err)
 	}
 
+	if err := CheckProofOfWork(header, DefaultsForNetwork(cm.network).PowLimitBits); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// handleBlockMessage links a validated block announcement into the chain
+func (cm *ChainManager) handleBlockMessage(ctx context.Context, blockMsg *BlockMessage, header *block.Header) error {
+	cm.log().Info("Received block", "height", blockMsg.Height, "hash", blockMsg.Hash, "peer", blockMsg.PeerID, "datahub", blockMsg.DataHubURL)
+	cm.ObserveNetworkHeight(blockMsg.Height)
+
 	// Check if we already have this block
 	blockHash := header.Hash()
 	if _, existsErr := cm.GetHeaderByHash(ctx, &blockHash); existsErr == nil {
@@ -146,9 +243,9 @@ func (cm *ChainManager) handleBlockMessage(ctx context.Context, data []byte) err
 			return cm.addBlockToChain(ctx, header, blockMsg.Height)
 		}
 		// Parent exists but is an orphan - need to crawl back to find common ancestor
-		log.Printf("Parent %s exists but is not in main chain at height %d, crawling back...", parentHash, parentHeader.Height)
+		cm.log().Info("Parent is not in main chain, crawling back", "parent", parentHash, "height", parentHeader.Height)
 	} else {
-		log.Printf("Parent not found for block %s, crawling back...", blockMsg.Hash)
+		cm.log().Info("Parent not found, crawling back", "hash", blockMsg.Hash)
 	}
 	return cm.crawlBackAndMerge(ctx, header, blockMsg.Height, blockMsg.DataHubURL)
 }
@@ -178,23 +275,25 @@ func (cm *ChainManager) addBlockToChain(ctx context.Context, header *block.Heade
 	if err := cm.AddHeader(blockHeader); err != nil {
 		return fmt.Errorf("failed to add header: %w", err)
 	}
+	cm.metrics.addHeaders(1)
+	markValidated(ctx)
 
 	// Check if this is the new tip
 	currentTip := cm.GetTip(ctx)
 	if currentTip == nil || blockHeader.ChainWork.Cmp(currentTip.ChainWork) > 0 {
-		log.Printf("New tip: height=%d chainwork=%s", blockHeader.Height, blockHeader.ChainWork.String())
+		cm.log().Info("New tip", "height", blockHeader.Height, "hash", blockHeader.Hash, "chainwork", blockHeader.ChainWork.String())
 		return cm.SetChainTip(ctx, []*BlockHeader{blockHeader})
 	}
 
-	log.Printf("Block added as orphan/alternate chain: height=%d", blockHeader.Height)
+	cm.log().Info("Block added as orphan/alternate chain", "height", blockHeader.Height, "hash", blockHeader.Hash)
 	return nil
 }
 
 // crawlBackAndMerge fetches missing parents until we find a connection to our chain
-func (cm *ChainManager) crawlBackAndMerge(ctx context.Context, header *block.Header, _ uint32, dataHubURL string) error {
+func (cm *ChainManager) crawlBackAndMerge(ctx context.Context, header *block.Header, height uint32, dataHubURL string) error {
 	// Use the shared sync logic to walk backwards and find common ancestor
 	blockHash := header.Hash()
-	return cm.SyncFromRemoteTip(ctx, blockHash, dataHubURL)
+	return cm.syncFromRemote(ctx, blockHash, dataHubURL, height)
 }
 
 // LoadOrGeneratePrivateKey loads a private key from file or generates a new one
@@ -214,7 +313,7 @@ func LoadOrGeneratePrivateKey(storagePath string) (crypto.PrivKey, error) {
 			return nil, fmt.Errorf("failed to parse private key: %w", err)
 		}
 
-		log.Printf("Loaded P2P private key from %s", keyPath)
+		getDefaultLogger().Info("Loaded P2P private key", "path", keyPath)
 		return privKey, nil
 	}
 
@@ -238,6 +337,6 @@ func LoadOrGeneratePrivateKey(storagePath string) (crypto.PrivKey, error) {
 		return nil, fmt.Errorf("failed to write key file: %w", err)
 	}
 
-	log.Printf("Generated new P2P private key: %s", keyPath)
+	getDefaultLogger().Info("Generated new P2P private key", "path", keyPath)
 	return privKey, nil
 }
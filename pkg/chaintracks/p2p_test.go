@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChainManagerGetPeers(t *testing.T) {
@@ -40,9 +41,43 @@ func TestChainManagerGetPeers(t *testing.T) {
 
 			if tt.expectEmpty {
 				assert.Empty(t, peers, "Expected empty peer list")
+				assert.Zero(t, cm.PeerCount())
 			} else {
 				assert.NotEmpty(t, peers, "Expected non-empty peer list")
 			}
 		})
 	}
 }
+
+func TestP2PConfigValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    P2PConfig
+		expectErr bool
+	}{
+		{name: "ZeroValueIsValid", config: P2PConfig{}},
+		{name: "ExplicitLimits", config: P2PConfig{Port: 9905, MaxConnections: 50, MinConnections: 40}},
+		{name: "MinWithoutMaxUsesLibraryMax", config: P2PConfig{MinConnections: 10}},
+		{name: "RejectsPortOutOfRange", config: P2PConfig{Port: 70000}, expectErr: true},
+		{name: "RejectsNegativePort", config: P2PConfig{Port: -1}, expectErr: true},
+		{name: "RejectsNegativeLimits", config: P2PConfig{MaxConnections: -5}, expectErr: true},
+		{name: "RejectsMinAboveMax", config: P2PConfig{MaxConnections: 10, MinConnections: 20}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrInvalidP2PConfig)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewP2PClientRejectsInvalidConfig(t *testing.T) {
+	client, err := NewP2PClient(t.TempDir(), "main", P2PConfig{MaxConnections: 1, MinConnections: 2})
+	require.ErrorIs(t, err, ErrInvalidP2PConfig)
+	assert.Nil(t, client)
+}
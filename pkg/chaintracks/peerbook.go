@@ -0,0 +1,430 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// peerBookFile stores peer history next to the headers so it survives restarts
+	peerBookFile = "peer_book.json"
+
+	// maxPeerRecords bounds the peer book; the lowest ranked peers are dropped first
+	maxPeerRecords = 500
+
+	// transientPeerTTL is how long a transient peer is kept after it was last seen
+	transientPeerTTL = 7 * 24 * time.Hour
+
+	// maxPreferredPeers is how many historically reliable peers are dialed on startup
+	maxPreferredPeers = 8
+
+	// peerObserveInterval is how often connected peers are recorded
+	peerObserveInterval = 30 * time.Second
+
+	// maxTrackedAnnouncements is how many recent blocks keep their first announcement time for peer latency
+	maxTrackedAnnouncements = 64
+
+	// peerLatencyWeight is the weight of each new sample in a peer's smoothed announcement latency
+	peerLatencyWeight = 0.2
+)
+
+// PeerTier ranks how much a peer is trusted for reconnection
+type PeerTier string
+
+const (
+	// PeerTierBootstrap is a configured bootstrap peer
+	PeerTierBootstrap PeerTier = "bootstrap"
+
+	// PeerTierVerified is a peer that has delivered more valid than invalid block announcements
+	PeerTierVerified PeerTier = "verified"
+
+	// PeerTierTransient is any other peer that has been seen
+	PeerTierTransient PeerTier = "transient"
+)
+
+// rank orders tiers for reconnection, lowest first
+func (t PeerTier) rank() int {
+	switch t {
+	case PeerTierBootstrap:
+		return 0
+	case PeerTierVerified:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// PeerRecord is the persisted history of a single peer
+type PeerRecord struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name,omitempty"`
+	Addrs         []string  `json:"addrs"`
+	Tier          PeerTier  `json:"tier"`
+	FirstSeen     time.Time `json:"firstSeen"`
+	LastSeen      time.Time `json:"lastSeen"`
+	Observations  uint64    `json:"observations"` // Times the peer was found connected
+	ValidBlocks   uint64    `json:"validBlocks"`
+	InvalidBlocks uint64    `json:"invalidBlocks"`
+	LatencyMs     float64   `json:"latencyMs"`        // Smoothed delay behind the first peer to announce each block
+	Pinned        bool      `json:"pinned,omitempty"` // Added by ConnectPeer; dialed on startup whatever its tier
+	Banned        bool      `json:"banned,omitempty"` // Set by BanPeer; never dialed and its announcements are ignored
+	bootstrap     bool
+	latencySeen   bool // Whether LatencyMs holds a sample from this run
+}
+
+// Reliability is the share of valid block announcements, smoothed so unknown peers score 0.5
+func (r *PeerRecord) Reliability() float64 {
+	return float64(r.ValidBlocks+1) / float64(r.ValidBlocks+r.InvalidBlocks+2)
+}
+
+// updateTier recomputes the tier from the bootstrap flag and block history
+func (r *PeerRecord) updateTier() {
+	switch {
+	case r.bootstrap:
+		r.Tier = PeerTierBootstrap
+	case r.ValidBlocks > 0 && r.ValidBlocks > r.InvalidBlocks:
+		r.Tier = PeerTierVerified
+	default:
+		r.Tier = PeerTierTransient
+	}
+}
+
+// PeerBook tracks peer history across restarts for preferential reconnection
+type PeerBook struct {
+	mu    sync.Mutex
+	path  string
+	peers map[string]*PeerRecord
+
+	// First announcement of recent blocks, oldest first in announced
+	firstSeen map[chainhash.Hash]*announcement
+	announced []chainhash.Hash
+}
+
+// announcement is when a block was first announced and which peers have announced it since
+type announcement struct {
+	at    time.Time
+	peers map[string]struct{}
+}
+
+// loadPeerBook reads the peer book at path; a missing or unreadable file yields an empty book
+func loadPeerBook(path string, logger Logger) *PeerBook {
+	book := &PeerBook{path: path, peers: make(map[string]*PeerRecord)}
+
+	data, err := os.ReadFile(path) //nolint:gosec // Path is built from the configured storage directory
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Could not read peer book", "path", path, "error", err)
+		}
+		return book
+	}
+
+	var records []*PeerRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		logger.Warn("Could not parse peer book", "path", path, "error", err)
+		return book
+	}
+
+	for _, record := range records {
+		if record.ID == "" {
+			continue
+		}
+		record.bootstrap = record.Tier == PeerTierBootstrap
+		book.peers[record.ID] = record
+	}
+	return book
+}
+
+// save prunes stale transient peers and writes the book atomically
+func (b *PeerBook) save(now time.Time) error {
+	b.mu.Lock()
+	for id, record := range b.peers {
+		if record.Tier == PeerTierTransient && !record.Pinned && !record.Banned && now.Sub(record.LastSeen) > transientPeerTTL {
+			delete(b.peers, id)
+		}
+	}
+	records := b.rankedLocked()
+	if len(records) > maxPeerRecords {
+		for _, record := range records[maxPeerRecords:] {
+			delete(b.peers, record.ID)
+		}
+		records = records[:maxPeerRecords]
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	b.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal peer book: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create peer book directory: %w", err)
+	}
+	tmpPath := b.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write peer book: %w", err)
+	}
+	if err := os.Rename(tmpPath, b.path); err != nil {
+		return fmt.Errorf("failed to replace peer book: %w", err)
+	}
+	return nil
+}
+
+// record returns the entry for id, creating it if needed (must be called with lock held)
+func (b *PeerBook) record(id string, now time.Time) *PeerRecord {
+	record, ok := b.peers[id]
+	if !ok {
+		record = &PeerRecord{ID: id, FirstSeen: now, LastSeen: now, Tier: PeerTierTransient}
+		b.peers[id] = record
+	}
+	return record
+}
+
+// markBootstrap tags the peers named in bootstrap multiaddrs as the bootstrap tier
+// Peers dropped from the bootstrap list fall back to the tier their history earns
+func (b *PeerBook) markBootstrap(multiaddrs []string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, record := range b.peers {
+		record.bootstrap = false
+		record.updateTier()
+	}
+	for _, addr := range multiaddrs {
+		info, err := peer.AddrInfoFromString(addr)
+		if err != nil {
+			continue
+		}
+		record := b.record(info.ID.String(), now)
+		record.bootstrap = true
+		record.updateTier()
+	}
+}
+
+// observe records the currently connected peers
+func (b *PeerBook) observe(peers []PeerInfo, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, p := range peers {
+		if p.ID == "" {
+			continue
+		}
+		record := b.record(p.ID, now)
+		record.LastSeen = now
+		record.Observations++
+		if p.Name != "" {
+			record.Name = p.Name
+		}
+		if len(p.Addrs) > 0 {
+			record.Addrs = p.Addrs
+		}
+	}
+}
+
+// recordBlock records whether a block announcement from a peer could be processed
+func (b *PeerBook) recordBlock(peerID string, valid bool, now time.Time) {
+	if peerID == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	record := b.record(peerID, now)
+	record.LastSeen = now
+	if valid {
+		record.ValidBlocks++
+	} else {
+		record.InvalidBlocks++
+	}
+	record.updateTier()
+}
+
+// recordAnnouncement updates a peer's latency from how long after the first announcement it announced hash
+// Only a peer's first announcement of a block counts, since blocks arrive once per topic version.
+func (b *PeerBook) recordAnnouncement(peerID string, hash chainhash.Hash, at time.Time) {
+	if peerID == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	first, ok := b.firstSeen[hash]
+	if !ok {
+		if b.firstSeen == nil {
+			b.firstSeen = make(map[chainhash.Hash]*announcement)
+		}
+		first = &announcement{at: at, peers: make(map[string]struct{})}
+		b.firstSeen[hash] = first
+		b.announced = append(b.announced, hash)
+		if len(b.announced) > maxTrackedAnnouncements {
+			delete(b.firstSeen, b.announced[0])
+			b.announced = b.announced[1:]
+		}
+	}
+	if _, seen := first.peers[peerID]; seen {
+		return
+	}
+	first.peers[peerID] = struct{}{}
+
+	delay := float64(max(at.Sub(first.at), 0)) / float64(time.Millisecond)
+	record := b.record(peerID, at)
+	if record.latencySeen {
+		record.LatencyMs += peerLatencyWeight * (delay - record.LatencyMs)
+	} else {
+		record.LatencyMs, record.latencySeen = delay, true
+	}
+}
+
+// pin marks a peer as operator-added and records its addresses, lifting any ban
+func (b *PeerBook) pin(info *peer.AddrInfo, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	record := b.record(info.ID.String(), now)
+	record.Pinned, record.Banned = true, false
+	if len(info.Addrs) > 0 {
+		record.Addrs = make([]string, len(info.Addrs))
+		for i, addr := range info.Addrs {
+			record.Addrs[i] = addr.String()
+		}
+	}
+}
+
+// ban marks a peer as banned and unpins it
+func (b *PeerBook) ban(id string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	record := b.record(id, now)
+	record.Banned, record.Pinned = true, false
+}
+
+// isBanned reports whether a peer is banned
+func (b *PeerBook) isBanned(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	record, ok := b.peers[id]
+	return ok && record.Banned
+}
+
+// lookup returns a copy of the record for id
+func (b *PeerBook) lookup(id string) (PeerRecord, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	record, ok := b.peers[id]
+	if !ok {
+		return PeerRecord{}, false
+	}
+	return *record, true
+}
+
+// withoutBanned drops multiaddrs of banned peers
+func (b *PeerBook) withoutBanned(multiaddrs []string) []string {
+	return slices.DeleteFunc(slices.Clone(multiaddrs), func(addr string) bool {
+		info, err := peer.AddrInfoFromString(addr)
+		return err == nil && b.isBanned(info.ID.String())
+	})
+}
+
+// rankedLocked returns copies of all records, best reconnection candidates first (must be called with lock held)
+func (b *PeerBook) rankedLocked() []PeerRecord {
+	records := make([]PeerRecord, 0, len(b.peers))
+	for _, record := range b.peers {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, c := &records[i], &records[j]
+		if a.Tier.rank() != c.Tier.rank() {
+			return a.Tier.rank() < c.Tier.rank()
+		}
+		if a.Reliability() != c.Reliability() {
+			return a.Reliability() > c.Reliability()
+		}
+		if !a.LastSeen.Equal(c.LastSeen) {
+			return a.LastSeen.After(c.LastSeen)
+		}
+		return a.ID < c.ID
+	})
+	return records
+}
+
+// Records returns all known peers, best reconnection candidates first
+func (b *PeerBook) Records() []PeerRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rankedLocked()
+}
+
+// preferredAddrs returns dialable multiaddrs for every pinned peer and up to n reliable non-bootstrap peers
+// Only verified peers qualify; bootstrap peers are already dialed from the configuration
+func (b *PeerBook) preferredAddrs(n int) []string {
+	var addrs []string
+	picked := 0
+	for _, record := range b.Records() {
+		if record.Banned || len(record.Addrs) == 0 {
+			continue
+		}
+		if !record.Pinned {
+			if picked >= n || record.Tier != PeerTierVerified {
+				continue
+			}
+			picked++
+		}
+		for _, addr := range record.Addrs {
+			if !strings.Contains(addr, "/p2p/") {
+				addr += "/p2p/" + record.ID
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// trackPeers periodically records connected peers and persists the peer book
+func (cm *ChainManager) trackPeers(ctx context.Context, book *PeerBook) {
+	ticker := time.NewTicker(peerObserveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := book.save(time.Now()); err != nil {
+				cm.log().Error("Failed to save peer book", "error", err)
+			}
+			return
+		case <-ticker.C:
+			book.observe(cm.GetPeers(), time.Now())
+			if err := book.save(time.Now()); err != nil {
+				cm.log().Error("Failed to save peer book", "error", err)
+			}
+		}
+	}
+}
+
+// GetPeerRecords returns the persisted peer history with trust tiers, best peers first
+// Returns an empty slice if P2P has not been started
+func (cm *ChainManager) GetPeerRecords() []PeerRecord {
+	cm.mu.RLock()
+	book := cm.peerBook
+	cm.mu.RUnlock()
+
+	if book == nil {
+		return []PeerRecord{}
+	}
+	return book.Records()
+}
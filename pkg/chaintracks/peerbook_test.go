@@ -0,0 +1,126 @@
+package chaintracks
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerRecordUpdateTier(t *testing.T) {
+	tests := []struct {
+		name         string
+		record       PeerRecord
+		expectedTier PeerTier
+	}{
+		{name: "NewPeerIsTransient", record: PeerRecord{}, expectedTier: PeerTierTransient},
+		{name: "ValidBlocksVerify", record: PeerRecord{ValidBlocks: 3, InvalidBlocks: 1}, expectedTier: PeerTierVerified},
+		{name: "MostlyInvalidStaysTransient", record: PeerRecord{ValidBlocks: 1, InvalidBlocks: 1}, expectedTier: PeerTierTransient},
+		{name: "BootstrapWinsOverHistory", record: PeerRecord{InvalidBlocks: 5, bootstrap: true}, expectedTier: PeerTierBootstrap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.record.updateTier()
+			assert.Equal(t, tt.expectedTier, tt.record.Tier)
+		})
+	}
+}
+
+func TestPeerBookRanking(t *testing.T) {
+	now := time.Now()
+	book := loadPeerBook(filepath.Join(t.TempDir(), peerBookFile), slog.New(slog.DiscardHandler))
+
+	bootstrapAddr := DefaultsForNetwork("main").BootstrapPeers[0]
+	book.markBootstrap([]string{bootstrapAddr, "not-a-multiaddr"}, now)
+
+	book.observe([]PeerInfo{
+		{ID: "reliable", Addrs: []string{"/ip4/203.0.113.1/tcp/9905"}},
+		{ID: "flaky", Addrs: []string{"/ip4/203.0.113.2/tcp/9905"}},
+		{ID: "quiet", Addrs: []string{"/ip4/203.0.113.3/tcp/9905"}},
+	}, now)
+	for range 3 {
+		book.recordBlock("reliable", true, now)
+	}
+	book.recordBlock("flaky", true, now)
+	book.recordBlock("flaky", true, now)
+	book.recordBlock("flaky", false, now)
+	book.recordBlock("", true, now)
+
+	records := book.Records()
+	require.Len(t, records, 4)
+	assert.Equal(t, PeerTierBootstrap, records[0].Tier)
+	assert.Equal(t, "reliable", records[1].ID)
+	assert.Equal(t, PeerTierVerified, records[1].Tier)
+	assert.Equal(t, "flaky", records[2].ID)
+	assert.Equal(t, "quiet", records[3].ID)
+	assert.Equal(t, PeerTierTransient, records[3].Tier)
+
+	preferred := book.preferredAddrs(1)
+	assert.Equal(t, []string{"/ip4/203.0.113.1/tcp/9905/p2p/reliable"}, preferred)
+}
+
+func TestPeerBookPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), peerBookFile)
+	now := time.Now()
+
+	book := loadPeerBook(path, slog.New(slog.DiscardHandler))
+	book.markBootstrap([]string{DefaultsForNetwork("main").BootstrapPeers[0]}, now)
+	book.observe([]PeerInfo{{ID: "verified", Addrs: []string{"/ip4/203.0.113.1/tcp/9905"}}}, now)
+	book.recordBlock("verified", true, now)
+	book.observe([]PeerInfo{{ID: "stale"}}, now.Add(-2*transientPeerTTL))
+	require.NoError(t, book.save(now))
+
+	reloaded := loadPeerBook(path, slog.New(slog.DiscardHandler))
+	records := reloaded.Records()
+	require.Len(t, records, 2, "stale transient peer should be pruned")
+	assert.Equal(t, PeerTierBootstrap, records[0].Tier)
+	assert.Equal(t, "verified", records[1].ID)
+	assert.Equal(t, uint64(1), records[1].ValidBlocks)
+	assert.Equal(t, uint64(1), records[1].Observations)
+
+	t.Run("BootstrapTierDroppedWhenRemovedFromConfig", func(t *testing.T) {
+		reloaded.markBootstrap(nil, now)
+		for _, record := range reloaded.Records() {
+			assert.NotEqual(t, PeerTierBootstrap, record.Tier)
+		}
+	})
+}
+
+func TestGetPeerRecordsBeforeStart(t *testing.T) {
+	cm := &ChainManager{}
+	assert.Empty(t, cm.GetPeerRecords())
+}
+
+func TestStartLoadsPeerBookOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	dir := t.TempDir()
+	stored := &PeerBook{path: filepath.Join(dir, peerBookFile), peers: make(map[string]*PeerRecord)}
+	stored.observe([]PeerInfo{{ID: "stored"}}, time.Now())
+	require.NoError(t, stored.save(time.Now()))
+
+	client := &fakeP2PClient{channels: []chan p2p.Message{make(chan p2p.Message)}}
+	cm := &ChainManager{network: "main", localStoragePath: dir, byHash: make(map[chainhash.Hash]*BlockHeader), p2pClient: client}
+	_, err := cm.Start(ctx)
+	require.NoError(t, err)
+	book := cm.peerBook
+	require.NotNil(t, book)
+	_, ok := book.lookup("stored")
+	assert.True(t, ok)
+
+	// History gathered since Start is kept when P2P is started again
+	book.observe([]PeerInfo{{ID: "unsaved"}}, time.Now())
+	_, err = cm.Start(ctx)
+	require.NoError(t, err)
+	assert.Same(t, book, cm.peerBook)
+	_, ok = cm.peerBook.lookup("unsaved")
+	assert.True(t, ok)
+}
@@ -0,0 +1,91 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// PeerConnector is implemented by P2P clients that can dial and drop individual peers
+// The go-p2p-message-bus client does not, so with it ConnectPeer only pins the address for the next
+// start and BanPeer only stops processing the peer's announcements.
+type PeerConnector interface {
+	ConnectPeer(ctx context.Context, multiaddr string) error
+	DisconnectPeer(ctx context.Context, peerID string) error
+}
+
+// ConnectPeer pins a peer so it is dialed on every start, lifting any ban, and dials it now if the
+// P2P client implements PeerConnector. The multiaddr must end in /p2p/<peer ID>. Reports whether the
+// peer was dialed.
+func (cm *ChainManager) ConnectPeer(ctx context.Context, multiaddr string) (bool, error) {
+	info, err := peer.AddrInfoFromString(multiaddr)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidPeerAddress, err)
+	}
+
+	book, client := cm.peerManagement()
+	if book == nil {
+		return false, ErrP2PNotStarted
+	}
+
+	book.pin(info, time.Now())
+	cm.savePeerBook(book)
+	cm.log().Info("Pinned peer", "peer", info.ID, "addr", multiaddr)
+
+	connector, ok := client.(PeerConnector)
+	if !ok {
+		return false, nil
+	}
+	if err := connector.ConnectPeer(ctx, multiaddr); err != nil {
+		return false, fmt.Errorf("failed to connect to peer %s: %w", info.ID, err)
+	}
+	return true, nil
+}
+
+// BanPeer bans a peer: its announcements are ignored, it is never dialed again, and it is disconnected
+// now if the P2P client implements PeerConnector. The ban persists until ConnectPeer is called for the
+// peer. Reports whether the peer was disconnected.
+func (cm *ChainManager) BanPeer(ctx context.Context, peerID string) (bool, error) {
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidPeerID, err)
+	}
+
+	book, client := cm.peerManagement()
+	if book == nil {
+		return false, ErrP2PNotStarted
+	}
+
+	book.ban(id.String(), time.Now())
+	cm.savePeerBook(book)
+	cm.log().Warn("Banned peer", "peer", id)
+
+	connector, ok := client.(PeerConnector)
+	if !ok {
+		return false, nil
+	}
+	if err := connector.DisconnectPeer(ctx, id.String()); err != nil {
+		return false, fmt.Errorf("failed to disconnect peer %s: %w", id, err)
+	}
+	return true, nil
+}
+
+// peerManagement returns the peer book and P2P client, nil before Start
+func (cm *ChainManager) peerManagement() (*PeerBook, p2p.Client) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.peerBook, cm.p2pClient
+}
+
+// savePeerBook persists an operator change right away rather than at the next observation
+func (cm *ChainManager) savePeerBook(book *PeerBook) {
+	if book.path == "" {
+		return
+	}
+	if err := book.save(time.Now()); err != nil {
+		cm.log().Error("Failed to save peer book", "error", err)
+	}
+}
@@ -0,0 +1,214 @@
+package chaintracks
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubP2PClient is a p2p.Client reporting a fixed peer list
+type stubP2PClient struct {
+	peers []p2p.PeerInfo
+}
+
+func (c *stubP2PClient) Subscribe(string) <-chan p2p.Message           { return nil }
+func (c *stubP2PClient) Publish(context.Context, string, []byte) error { return nil }
+func (c *stubP2PClient) GetPeers() []p2p.PeerInfo                      { return c.peers }
+func (c *stubP2PClient) GetID() string                                 { return "self" }
+func (c *stubP2PClient) Close() error                                  { return nil }
+
+// connectingP2PClient also implements PeerConnector, recording the calls
+type connectingP2PClient struct {
+	stubP2PClient
+	connected    []string
+	disconnected []string
+}
+
+func (c *connectingP2PClient) ConnectPeer(_ context.Context, multiaddr string) error {
+	c.connected = append(c.connected, multiaddr)
+	return nil
+}
+
+func (c *connectingP2PClient) DisconnectPeer(_ context.Context, peerID string) error {
+	c.disconnected = append(c.disconnected, peerID)
+	return nil
+}
+
+// testPeerAddr returns a dialable multiaddr and its peer ID
+func testPeerAddr(t *testing.T) (string, string) {
+	t.Helper()
+
+	addr := DefaultsForNetwork("main").BootstrapPeers[0]
+	info, err := peer.AddrInfoFromString(addr)
+	require.NoError(t, err)
+	return addr, info.ID.String()
+}
+
+// newPeerManagedChainManager returns a ChainManager that looks started, with a peer book on disk
+func newPeerManagedChainManager(t *testing.T, client p2p.Client) *ChainManager {
+	t.Helper()
+
+	book := loadPeerBook(filepath.Join(t.TempDir(), peerBookFile), slog.New(slog.DiscardHandler))
+	return &ChainManager{p2pClient: client, peerBook: book}
+}
+
+func TestChainManagerConnectPeer(t *testing.T) {
+	addr, id := testPeerAddr(t)
+
+	t.Run("DialsWithPeerConnector", func(t *testing.T) {
+		client := &connectingP2PClient{}
+		cm := newPeerManagedChainManager(t, client)
+
+		connected, err := cm.ConnectPeer(t.Context(), addr)
+		require.NoError(t, err)
+		assert.True(t, connected)
+		assert.Equal(t, []string{addr}, client.connected)
+
+		record, ok := cm.peerBook.lookup(id)
+		require.True(t, ok)
+		assert.True(t, record.Pinned)
+		assert.Contains(t, cm.peerBook.preferredAddrs(0), addr)
+	})
+
+	t.Run("PinsWithoutPeerConnector", func(t *testing.T) {
+		cm := newPeerManagedChainManager(t, &stubP2PClient{})
+		_, err := cm.BanPeer(t.Context(), id)
+		require.NoError(t, err)
+
+		connected, err := cm.ConnectPeer(t.Context(), addr)
+		require.NoError(t, err)
+		assert.False(t, connected)
+
+		reloaded := loadPeerBook(cm.peerBook.path, slog.New(slog.DiscardHandler))
+		record, ok := reloaded.lookup(id)
+		require.True(t, ok)
+		assert.True(t, record.Pinned)
+		assert.False(t, record.Banned, "connecting lifts a ban")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := (&ChainManager{}).ConnectPeer(t.Context(), addr)
+		require.ErrorIs(t, err, ErrP2PNotStarted)
+
+		cm := newPeerManagedChainManager(t, &stubP2PClient{})
+		_, err = cm.ConnectPeer(t.Context(), "/ip4/203.0.113.1/tcp/9905")
+		require.ErrorIs(t, err, ErrInvalidPeerAddress)
+	})
+}
+
+func TestChainManagerBanPeer(t *testing.T) {
+	addr, id := testPeerAddr(t)
+
+	t.Run("DisconnectsWithPeerConnector", func(t *testing.T) {
+		client := &connectingP2PClient{}
+		cm := newPeerManagedChainManager(t, client)
+		_, err := cm.ConnectPeer(t.Context(), addr)
+		require.NoError(t, err)
+
+		disconnected, err := cm.BanPeer(t.Context(), id)
+		require.NoError(t, err)
+		assert.True(t, disconnected)
+		assert.Equal(t, []string{id}, client.disconnected)
+
+		assert.True(t, cm.peerBook.isBanned(id))
+		assert.Empty(t, cm.peerBook.preferredAddrs(maxPreferredPeers))
+		assert.Empty(t, cm.peerBook.withoutBanned([]string{addr}))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := (&ChainManager{}).BanPeer(t.Context(), id)
+		require.ErrorIs(t, err, ErrP2PNotStarted)
+
+		cm := newPeerManagedChainManager(t, &stubP2PClient{})
+		_, err = cm.BanPeer(t.Context(), "not-a-peer-id")
+		require.ErrorIs(t, err, ErrInvalidPeerID)
+	})
+}
+
+func TestRunIngestIgnoresBannedPeers(t *testing.T) {
+	genesis := &BlockHeader{Header: &block.Header{Bits: regtestPowLimitBits}, ChainWork: big.NewInt(1)}
+	genesis.Hash = genesis.Header.Hash()
+
+	tips := make(chan *BlockHeader, 1)
+	cm := &ChainManager{network: "regtest", byHash: make(map[chainhash.Hash]*BlockHeader), msgChan: tips}
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{genesis}))
+
+	book := &PeerBook{peers: make(map[string]*PeerRecord)}
+	book.ban("banned", time.Now())
+
+	incoming := make(chan topicMessage, 2)
+	incoming <- announce(t, "banned", 1, mineRegtestHeader(t, genesis.Hash))
+	child := mineRegtestHeader(t, genesis.Hash)
+	child.Nonce++
+	for CheckProofOfWork(child, regtestPowLimitBits) != nil {
+		child.Nonce++
+	}
+	incoming <- announce(t, "good", 1, child)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		cm.runIngest(ctx, incoming, book, tips)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return cm.GetHeight(t.Context()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, child.Hash(), cm.GetTip(t.Context()).Hash)
+	record, _ := book.lookup("banned")
+	assert.Zero(t, record.ValidBlocks+record.InvalidBlocks)
+
+	cancel()
+	<-done
+	for range tips { //nolint:revive // Drain until runIngest closes the channel
+	}
+}
+
+func TestPeerBookRecordAnnouncement(t *testing.T) {
+	book := &PeerBook{peers: make(map[string]*PeerRecord)}
+	now := time.Now()
+	hash := chainhash.Hash{1}
+
+	book.recordAnnouncement("first", hash, now)
+	book.recordAnnouncement("second", hash, now.Add(100*time.Millisecond))
+	book.recordAnnouncement("second", hash, now.Add(900*time.Millisecond))
+	book.recordAnnouncement("", hash, now)
+
+	first, _ := book.lookup("first")
+	second, _ := book.lookup("second")
+	assert.Zero(t, first.LatencyMs)
+	assert.InDelta(t, 100, second.LatencyMs, 0.001, "a repeated announcement of the same block does not count")
+
+	book.recordAnnouncement("first", chainhash.Hash{2}, now)
+	book.recordAnnouncement("second", chainhash.Hash{2}, now.Add(600*time.Millisecond))
+	second, _ = book.lookup("second")
+	assert.InDelta(t, 100+peerLatencyWeight*500, second.LatencyMs, 0.001)
+
+	for i := range maxTrackedAnnouncements + 1 {
+		book.recordAnnouncement("first", chainhash.Hash{byte(i), 1}, now)
+	}
+	assert.Len(t, book.firstSeen, maxTrackedAnnouncements)
+}
+
+func TestChainManagerGetPeersFromBook(t *testing.T) {
+	cm := newPeerManagedChainManager(t, &stubP2PClient{peers: []p2p.PeerInfo{{ID: "known"}, {ID: "new"}}})
+	now := time.Now().Truncate(time.Second)
+	cm.peerBook.observe([]PeerInfo{{ID: "known"}}, now)
+	cm.peerBook.recordAnnouncement("known", chainhash.Hash{1}, now)
+
+	peers := cm.GetPeers()
+	require.Len(t, peers, 2)
+	assert.Equal(t, 2, cm.PeerCount())
+	assert.Equal(t, now, peers[0].LastSeen)
+	assert.True(t, peers[1].LastSeen.IsZero())
+}
@@ -0,0 +1,87 @@
+package chaintracks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// readHeadersChunk is how many headers ReadHeadersInto serializes per lock hold
+const readHeadersChunk = 1000
+
+// ReadHeadersInto writes up to count consecutive main-chain headers starting at height to w, 80 bytes each
+// Headers are serialized a chunk at a time into one reused buffer, so a large read costs no per-header
+// allocations, and the lock is released while w is written. It stops at the tip, or early if the chain
+// reorganizes between chunks, and returns the number of headers written.
+func (cm *ChainManager) ReadHeadersInto(w io.Writer, height, count uint32) (uint32, error) {
+	buf := make([]byte, min(count, readHeadersChunk)*headerSize)
+	var written uint32
+	var last chainhash.Hash
+	for written < count {
+		var n uint32
+		n, last = cm.serializeHeaders(buf, height+written, min(count-written, readHeadersChunk), written > 0, last)
+		if n == 0 {
+			break
+		}
+		if _, err := w.Write(buf[:n*headerSize]); err != nil {
+			return written, fmt.Errorf("failed to write headers: %w", err)
+		}
+		written += n
+	}
+	return written, nil
+}
+
+// serializeHeaders fills buf with up to count main-chain headers from height, returning how many it wrote and
+// the hash of the last one
+// With linked set, the first header must follow prev or nothing is written. Headers below the memory window
+// are copied straight from the local header files.
+func (cm *ChainManager) serializeHeaders(buf []byte, height, count uint32, linked bool, prev chainhash.Hash) (uint32, chainhash.Hash) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var n uint32
+	last := prev
+	for n < count && uint64(height)+uint64(n) < uint64(len(cm.byHeight)) {
+		h := height + n
+		if h < cm.windowFloor {
+			run := min(count-n, cm.windowFloor-h, defaultHeadersPerFile-h%defaultHeadersPerFile)
+			dst := buf[n*headerSize : (n+run)*headerSize]
+			if err := cm.readStoredBytes(dst, h); err != nil {
+				cm.log().Warn("Failed to read stored headers", "height", h, "error", err)
+				break
+			}
+			if n == 0 && linked && !bytes.Equal(dst[4:36], prev[:]) {
+				break
+			}
+			n += run
+			last = cm.byHeight[h+run-1]
+			continue
+		}
+
+		header, ok := cm.byHash[cm.byHeight[h]]
+		if !ok || header.Header == nil {
+			break
+		}
+		if n == 0 && linked && header.PrevHash != prev {
+			break
+		}
+		putHeader(buf[n*headerSize:(n+1)*headerSize], header.Header)
+		last = header.Hash
+		n++
+	}
+	return n, last
+}
+
+// putHeader serializes header into the 80 bytes of dst without allocating
+func putHeader(dst []byte, header *block.Header) {
+	binary.LittleEndian.PutUint32(dst[0:4], uint32(header.Version)) //nolint:gosec // Version is serialized as its bit pattern
+	copy(dst[4:36], header.PrevHash[:])
+	copy(dst[36:68], header.MerkleRoot[:])
+	binary.LittleEndian.PutUint32(dst[68:72], header.Timestamp)
+	binary.LittleEndian.PutUint32(dst[72:76], header.Bits)
+	binary.LittleEndian.PutUint32(dst[76:80], header.Nonce)
+}
@@ -0,0 +1,73 @@
+package chaintracks
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errWriteFailed = errors.New("write failed")
+
+// failingWriter rejects every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
+
+func TestChainManagerReadHeadersInto(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), readHeadersChunk+200, 0)
+	cm := newWireChainManager(t, genesis, chain)
+	all := append([]*block.Header{genesis}, chain...)
+
+	tests := []struct {
+		name          string
+		height, count uint32
+		expected      []*block.Header
+	}{
+		{name: "Range", height: 1, count: 3, expected: all[1:4]},
+		{name: "StopsAtTip", height: uint32(len(all)) - 2, count: 10, expected: all[len(all)-2:]},
+		{name: "SpansChunks", height: 0, count: uint32(len(all)), expected: all},
+		{name: "ZeroCount", height: 0, count: 0},
+		{name: "BeyondTip", height: uint32(len(all)), count: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := cm.ReadHeadersInto(&buf, tt.height, tt.count)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(len(tt.expected)), n) //nolint:gosec // Test chain is small
+
+			var expected bytes.Buffer
+			for _, header := range tt.expected {
+				expected.Write(header.Bytes())
+			}
+			assert.Equal(t, expected.Bytes(), buf.Bytes())
+		})
+	}
+
+	t.Run("ReturnsWriteError", func(t *testing.T) {
+		n, err := cm.ReadHeadersInto(failingWriter{}, 0, 3)
+		require.ErrorIs(t, err, errWriteFailed)
+		assert.Equal(t, uint32(0), n)
+	})
+}
+
+func TestPutHeader(t *testing.T) {
+	header := &block.Header{
+		Version:    -2,
+		PrevHash:   chainhash.Hash{1, 2, 3},
+		MerkleRoot: chainhash.Hash{4, 5, 6},
+		Timestamp:  1700000000,
+		Bits:       0x1d00ffff,
+		Nonce:      42,
+	}
+	buf := make([]byte, headerSize)
+	putHeader(buf, header)
+	assert.Equal(t, header.Bytes(), buf)
+}
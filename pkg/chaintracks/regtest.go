@@ -0,0 +1,130 @@
+package chaintracks
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// NetworkRegtest is the network name of a local chain whose blocks are mined on demand with MineBlocks
+const NetworkRegtest = "regtest"
+
+// MaxMineBlocks caps the blocks one MineBlocks or MineReorg call produces
+const MaxMineBlocks = 10000
+
+// regtestGenesisMerkleRoot is the merkle root of the regtest genesis block, shared with mainnet
+const regtestGenesisMerkleRoot = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+
+// RegtestGenesis returns the genesis header of bitcoind's regtest network, which a regtest chain starts from
+func RegtestGenesis() *BlockHeader {
+	root, _ := chainhash.NewHashFromHex(regtestGenesisMerkleRoot)
+	header := &block.Header{
+		Version:    1,
+		MerkleRoot: *root,
+		Timestamp:  1296688602,
+		Bits:       regtestPowLimitBits,
+		Nonce:      2,
+	}
+	return &BlockHeader{Header: header, Height: 0, Hash: header.Hash(), ChainWork: CalculateWork(header.Bits)}
+}
+
+// seedRegtest starts an empty regtest chain at the regtest genesis block
+func (cm *ChainManager) seedRegtest(ctx context.Context) error {
+	if cm.network != NetworkRegtest || cm.tip.Load() != nil {
+		return nil
+	}
+	return cm.SetChainTip(ctx, []*BlockHeader{RegtestGenesis()})
+}
+
+// MineBlocks appends count blocks with solved regtest proof of work to the tip and returns them, oldest first
+// merkleRoots, if given, become the merkle roots of the first blocks so proofs built for them verify; the rest
+// get random roots. Returns ErrNotRegtest unless the chain manager was created for NetworkRegtest.
+func (cm *ChainManager) MineBlocks(ctx context.Context, count int, merkleRoots ...chainhash.Hash) ([]*BlockHeader, error) {
+	if cm.network != NetworkRegtest {
+		return nil, ErrNotRegtest
+	}
+	if count < 1 || count > MaxMineBlocks {
+		return nil, fmt.Errorf("%w: count %d outside 1..%d", ErrInvalidMineRequest, count, MaxMineBlocks)
+	}
+	if len(merkleRoots) > count {
+		return nil, fmt.Errorf("%w: %d merkle roots for %d blocks", ErrInvalidMineRequest, len(merkleRoots), count)
+	}
+
+	cm.adminMu.Lock()
+	defer cm.adminMu.Unlock()
+
+	tip := cm.tip.Load()
+	if tip == nil {
+		return nil, ErrHeaderNotFound
+	}
+	headers := mineOn(tip, count, merkleRoots)
+	if err := cm.AddHeaders(ctx, headers); err != nil {
+		return nil, err
+	}
+	cm.log().Info("Mined regtest blocks", "count", count, "height", headers[count-1].Height)
+	return headers, nil
+}
+
+// MineReorg replaces the top depth blocks of the main chain with count newly mined ones and returns them
+// count must exceed depth so the new branch has more work; the replaced blocks are kept as orphans. Returns
+// ErrNotRegtest unless the chain manager was created for NetworkRegtest.
+func (cm *ChainManager) MineReorg(ctx context.Context, depth, count int) ([]*BlockHeader, error) {
+	if cm.network != NetworkRegtest {
+		return nil, ErrNotRegtest
+	}
+	if depth < 1 || count <= depth || count > MaxMineBlocks {
+		return nil, fmt.Errorf("%w: need 1 <= depth < count <= %d, got depth %d and count %d",
+			ErrInvalidMineRequest, MaxMineBlocks, depth, count)
+	}
+
+	cm.adminMu.Lock()
+	defer cm.adminMu.Unlock()
+
+	tip := cm.tip.Load()
+	if tip == nil || uint32(depth) > tip.Height { //nolint:gosec // depth is positive and capped
+		return nil, fmt.Errorf("%w: depth %d would replace the genesis block", ErrInvalidMineRequest, depth)
+	}
+	parent, err := cm.GetHeaderByHeight(ctx, tip.Height-uint32(depth)) //nolint:gosec // Checked against the tip height
+	if err != nil {
+		return nil, err
+	}
+
+	headers := mineOn(parent, count, nil)
+	if err := cm.AddHeaders(ctx, headers); err != nil {
+		return nil, err
+	}
+	cm.log().Info("Mined regtest reorg", "depth", depth, "count", count, "height", headers[count-1].Height)
+	return headers, nil
+}
+
+// mineOn builds count linked headers on parent with solved regtest proof of work
+// Timestamps follow the wall clock but always increase, so bursts of blocks still have distinct times.
+func mineOn(parent *BlockHeader, count int, merkleRoots []chainhash.Hash) []*BlockHeader {
+	headers := make([]*BlockHeader, 0, count)
+	prevHash, prevTime := parent.Hash, parent.Timestamp
+	for i := range count {
+		header := &block.Header{
+			Version:   1,
+			PrevHash:  prevHash,
+			Timestamp: max(uint32(time.Now().Unix()), prevTime+1), //nolint:gosec // Unix time fits in uint32 until 2106
+			Bits:      regtestPowLimitBits,
+		}
+		if i < len(merkleRoots) {
+			header.MerkleRoot = merkleRoots[i]
+		} else {
+			_, _ = rand.Read(header.MerkleRoot[:])
+		}
+		for CheckProofOfWork(header, regtestPowLimitBits) != nil {
+			header.Nonce++
+		}
+
+		mined := &BlockHeader{Header: header, Hash: header.Hash()}
+		headers = append(headers, mined)
+		prevHash, prevTime = mined.Hash, header.Timestamp
+	}
+	return headers
+}
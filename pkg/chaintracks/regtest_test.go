@@ -0,0 +1,88 @@
+package chaintracks
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRegtestChainManager returns a ChainManager for regtest in a temporary directory
+func newRegtestChainManager(t *testing.T) *ChainManager {
+	t.Helper()
+	cm, err := NewChainManager(t.Context(), NetworkRegtest, t.TempDir(), nil)
+	require.NoError(t, err)
+	return cm
+}
+
+func TestRegtestGenesis(t *testing.T) {
+	assert.Equal(t, "0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206", RegtestGenesis().Hash.String())
+
+	cm := newRegtestChainManager(t)
+	assert.Equal(t, RegtestGenesis().Hash, cm.GetTip(t.Context()).Hash)
+}
+
+func TestMineBlocks(t *testing.T) {
+	t.Run("AppendsToTip", func(t *testing.T) {
+		cm := newRegtestChainManager(t)
+		root := chainhash.Hash{0xab}
+
+		headers, err := cm.MineBlocks(t.Context(), 3, root)
+		require.NoError(t, err)
+		require.Len(t, headers, 3)
+		assert.Equal(t, headers[2].Hash, cm.GetTip(t.Context()).Hash)
+		assert.Equal(t, uint32(3), cm.GetHeight(t.Context()))
+		for i, header := range headers {
+			require.NoError(t, CheckProofOfWork(header.Header, regtestPowLimitBits))
+			assert.Equal(t, uint32(i+1), header.Height) //nolint:gosec // Test heights are small
+			if i > 0 {
+				assert.Greater(t, header.Timestamp, headers[i-1].Timestamp)
+			}
+		}
+
+		valid, err := cm.IsValidRootForHeight(t.Context(), &root, 1)
+		require.NoError(t, err)
+		assert.True(t, valid, "given merkle roots are used for the first blocks")
+	})
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		cm := newRegtestChainManager(t)
+		_, err := cm.MineBlocks(t.Context(), 0)
+		require.ErrorIs(t, err, ErrInvalidMineRequest)
+		_, err = cm.MineBlocks(t.Context(), MaxMineBlocks+1)
+		require.ErrorIs(t, err, ErrInvalidMineRequest)
+		_, err = cm.MineBlocks(t.Context(), 1, chainhash.Hash{1}, chainhash.Hash{2})
+		require.ErrorIs(t, err, ErrInvalidMineRequest)
+	})
+
+	t.Run("NotRegtest", func(t *testing.T) {
+		_, err := newLinearChainManager(3).MineBlocks(t.Context(), 1)
+		require.ErrorIs(t, err, ErrNotRegtest)
+	})
+}
+
+func TestMineReorg(t *testing.T) {
+	cm := newRegtestChainManager(t)
+	mined, err := cm.MineBlocks(t.Context(), 5)
+	require.NoError(t, err)
+
+	events := cm.SubscribeEvents(t.Context())
+	headers, err := cm.MineReorg(t.Context(), 2, 3)
+	require.NoError(t, err)
+
+	tip := cm.GetTip(t.Context())
+	assert.Equal(t, headers[2].Hash, tip.Hash)
+	assert.Equal(t, uint32(6), tip.Height)
+	assert.Equal(t, mined[2].Hash, headers[0].PrevHash, "the branch forks below the replaced blocks")
+
+	event := <-events
+	require.NotNil(t, event.Reorg)
+	assert.Equal(t, uint32(3), event.Reorg.ForkHeight)
+	assert.Len(t, event.Reorg.OrphanedHashes, 2)
+
+	_, err = cm.MineReorg(t.Context(), 2, 2)
+	require.ErrorIs(t, err, ErrInvalidMineRequest, "an equal-work branch would not win")
+	_, err = cm.MineReorg(t.Context(), 7, 8)
+	require.ErrorIs(t, err, ErrInvalidMineRequest, "genesis cannot be replaced")
+}
@@ -0,0 +1,143 @@
+package chaintracks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// maxReorgHistory caps the number of reorg records kept in memory
+const maxReorgHistory = 1000
+
+// ReorgRecord is a persisted record of a detected chain reorganization
+type ReorgRecord struct {
+	Timestamp      time.Time        `json:"timestamp"`
+	Depth          int              `json:"depth"`      // Number of main chain blocks orphaned
+	ForkHeight     uint32           `json:"forkHeight"` // Height of the last block shared by both branches
+	OrphanedHashes []chainhash.Hash `json:"orphanedHashes"`
+	NewTipHash     chainhash.Hash   `json:"newTipHash"`
+	NewTipHeight   uint32           `json:"newTipHeight"`
+}
+
+// GetReorgs returns up to limit recorded reorgs, newest first
+// A limit of 0 or less returns all records held in memory
+func (cm *ChainManager) GetReorgs(limit int) []ReorgRecord {
+	cm.reorgMu.RLock()
+	defer cm.reorgMu.RUnlock()
+
+	if limit <= 0 || limit > len(cm.reorgs) {
+		limit = len(cm.reorgs)
+	}
+
+	result := make([]ReorgRecord, 0, limit)
+	for i := len(cm.reorgs) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, cm.reorgs[i])
+	}
+	return result
+}
+
+// recordReorg stores a reorg in memory and appends it to the reorg log
+func (cm *ChainManager) recordReorg(reorg *ReorgInfo, newTip *BlockHeader) {
+	record := ReorgRecord{
+		Timestamp:      time.Now().UTC(),
+		Depth:          len(reorg.OrphanedHashes),
+		ForkHeight:     reorg.ForkHeight,
+		OrphanedHashes: reorg.OrphanedHashes,
+		NewTipHash:     newTip.Hash,
+		NewTipHeight:   newTip.Height,
+	}
+
+	cm.reorgMu.Lock()
+	cm.reorgs = append(cm.reorgs, record)
+	if len(cm.reorgs) > maxReorgHistory {
+		cm.reorgs = cm.reorgs[len(cm.reorgs)-maxReorgHistory:]
+	}
+	cm.reorgMu.Unlock()
+	cm.metrics.addReorg()
+
+	if err := cm.appendReorgLog(record); err != nil {
+		cm.log().Error("Failed to persist reorg record", "error", err)
+	}
+}
+
+// reorgLogPath returns the path of the JSON-lines reorg log
+func (cm *ChainManager) reorgLogPath() string {
+	return filepath.Join(cm.localStoragePath, cm.network+"NetReorgs.jsonl")
+}
+
+// appendReorgLog appends a record to the reorg log file
+func (cm *ChainManager) appendReorgLog(record ReorgRecord) error {
+	if cm.localStoragePath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(cm.localStoragePath, 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	data, err := json.Marshal(&record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reorg record: %w", err)
+	}
+
+	f, err := os.OpenFile(cm.reorgLogPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open reorg log: %w", err)
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write reorg log: %w", err)
+	}
+
+	return f.Close()
+}
+
+// loadReorgLog restores the reorg history from disk
+// Malformed lines are skipped so a torn write never blocks startup
+func (cm *ChainManager) loadReorgLog() error {
+	if cm.localStoragePath == "" {
+		return nil
+	}
+
+	f, err := os.Open(cm.reorgLogPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open reorg log: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var records []ReorgRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record ReorgRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read reorg log: %w", err)
+	}
+
+	if len(records) > maxReorgHistory {
+		records = records[len(records)-maxReorgHistory:]
+	}
+
+	cm.reorgMu.Lock()
+	cm.reorgs = records
+	cm.reorgMu.Unlock()
+
+	cm.log().Info("Loaded reorg records", "count", len(records))
+	return nil
+}
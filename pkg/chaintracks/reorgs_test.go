@@ -0,0 +1,57 @@
+package chaintracks
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainManagerGetReorgs(t *testing.T) {
+	cm := &ChainManager{}
+	for i := 0; i < 5; i++ {
+		cm.recordReorg(&ReorgInfo{ForkHeight: uint32(i)}, testHeader(uint32(i+1), 1)) //nolint:gosec // Test data
+	}
+
+	tests := []struct {
+		name          string
+		limit         int
+		expectedForks []uint32
+	}{
+		{name: "ReturnsNewestFirst", limit: 2, expectedForks: []uint32{4, 3}},
+		{name: "ZeroLimitReturnsAll", limit: 0, expectedForks: []uint32{4, 3, 2, 1, 0}},
+		{name: "LimitLargerThanHistory", limit: 100, expectedForks: []uint32{4, 3, 2, 1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reorgs := cm.GetReorgs(tt.limit)
+			forks := make([]uint32, len(reorgs))
+			for i, r := range reorgs {
+				forks[i] = r.ForkHeight
+			}
+			assert.Equal(t, tt.expectedForks, forks)
+		})
+	}
+}
+
+func TestChainManagerReorgPersistence(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader), localStoragePath: dir, network: "test"}
+	require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(0, 1), testHeader(1, 1), testHeader(2, 1)}))
+	require.NoError(t, cm.SetChainTip(ctx, []*BlockHeader{testHeader(2, 2), testHeader(3, 2)}))
+	require.Len(t, cm.GetReorgs(0), 1)
+
+	restored := &ChainManager{localStoragePath: dir, network: "test"}
+	require.NoError(t, restored.loadReorgLog())
+
+	reorgs := restored.GetReorgs(0)
+	require.Len(t, reorgs, 1)
+	assert.Equal(t, uint32(1), reorgs[0].ForkHeight)
+	assert.Equal(t, 1, reorgs[0].Depth)
+	assert.Equal(t, []chainhash.Hash{testHeader(2, 1).Hash}, reorgs[0].OrphanedHashes)
+	assert.Equal(t, uint32(3), reorgs[0].NewTipHeight)
+}
@@ -0,0 +1,119 @@
+package chaintracks
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Client retries requests that fail transiently
+// Network errors, timeouts, 429 and 5xx responses are retried with a delay that doubles from InitialBackoff
+// up to MaxBackoff. A Retry-After header replaces the computed delay; one longer than MaxBackoff is not
+// waited out and the response is returned to the caller.
+type RetryPolicy struct {
+	MaxAttempts    int           // Attempts including the first, 1 or less disables retries
+	InitialBackoff time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Cap on the delay between attempts
+}
+
+// DefaultRetryPolicy rides out a server restart of a few seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// SetRetryPolicy replaces the client's retry policy; call it before Start
+func (cc *Client) SetRetryPolicy(policy RetryPolicy) {
+	cc.retry = policy
+}
+
+// withRetry sends req with send, retrying transient failures according to the client's policy
+// Requests with a body are only retried when it can be rewound through GetBody.
+func (cc *Client) withRetry(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	backoff := cc.retry.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send(req)
+		if attempt >= cc.retry.MaxAttempts || !retryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := min(backoff, cc.retry.MaxBackoff)
+		if after, ok := retryAfter(resp); ok {
+			if after > cc.retry.MaxBackoff {
+				return resp, err
+			}
+			delay = after
+		}
+
+		next := req.Clone(ctx)
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			next.Body = body
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		cc.log().Debug("Retrying request", "url", req.URL.String(), "attempt", attempt, "error", err, "retryIn", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		req = next
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a response or error is worth retrying
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusNotImplemented, resp.StatusCode == http.StatusHTTPVersionNotSupported:
+		return false
+	default:
+		return resp.StatusCode >= http.StatusInternalServerError
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,137 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRetryingClient returns a client for url with a fast three-attempt retry policy
+func newRetryingClient(url string) *Client {
+	client := NewClient(url)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+	return client
+}
+
+func TestClientRetry(t *testing.T) {
+	hashes := []chainhash.Hash{{1}}
+
+	tests := []struct {
+		name             string
+		failures         []int
+		retryAfter       string
+		expectedAttempts int32
+		expectedError    error
+	}{
+		{
+			name:             "RecoversFromServerErrors",
+			failures:         []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "HonorsRetryAfter",
+			failures:         []int{http.StatusTooManyRequests},
+			retryAfter:       "0",
+			expectedAttempts: 2,
+		},
+		{
+			name:             "GivesUpAfterMaxAttempts",
+			failures:         []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedAttempts: 3,
+			expectedError:    ErrServerRequestFailed,
+		},
+		{
+			name:             "ReturnsRetryAfterBeyondMaxBackoff",
+			failures:         []int{http.StatusServiceUnavailable},
+			retryAfter:       "60",
+			expectedAttempts: 1,
+			expectedError:    ErrServerRequestFailed,
+		},
+		{
+			name:             "DoesNotRetryClientErrors",
+			failures:         []int{http.StatusBadRequest},
+			expectedAttempts: 1,
+			expectedError:    ErrServerRequestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body []string
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body), "the body is replayed on every attempt")
+
+				attempt := int(attempts.Add(1))
+				if attempt <= len(tt.failures) {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.failures[attempt-1])
+					return
+				}
+				_, _ = w.Write([]byte(`{"status":"success","value":[{"height":7}]}`))
+			}))
+			defer server.Close()
+
+			headers, err := newRetryingClient(server.URL).GetHeadersByHashes(t.Context(), hashes)
+			assert.Equal(t, tt.expectedAttempts, attempts.Load())
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint32(7), headers[0].Height)
+		})
+	}
+}
+
+func TestClientRetryConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	client := newRetryingClient(url)
+	_, err := client.GetHeaderByHeight(t.Context(), 1)
+	require.Error(t, err)
+
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.GetHeaderByHeight(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded, "the backoff wait ends with the context")
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"Missing", "", 0, false},
+		{"Seconds", "3", 3 * time.Second, true},
+		{"PastDate", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+		{"Invalid", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.value != "" {
+				resp.Header.Set("Retry-After", tt.value)
+			}
+			delay, ok := retryAfter(resp)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, delay)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package chaintracks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // S3 ETags are MD5 digests, compared to skip unchanged uploads
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3TimeFormat    = "20060102T150405Z"
+)
+
+// S3Config locates a bucket in S3-compatible object storage
+// Objects are addressed path-style, Endpoint/Bucket/Prefix/name, which AWS, MinIO, Cloudflare R2 and Google Cloud
+// Storage (https://storage.googleapis.com with HMAC keys) all accept.
+type S3Config struct {
+	Endpoint  string       // e.g. https://s3.us-east-1.amazonaws.com or https://storage.googleapis.com
+	Region    string       // Signing region (default us-east-1; GCS accepts "auto")
+	Bucket    string       // Bucket name
+	Prefix    string       // Key prefix the files are stored under, e.g. "blockheaders"
+	AccessKey string       // Access key ID
+	SecretKey string       // Secret access key
+	Client    *http.Client // Defaults to a client with a 5 minute timeout
+}
+
+// S3Bucket uploads objects to S3-compatible storage, signing requests with AWS Signature Version 4
+type S3Bucket struct {
+	config S3Config
+	now    func() time.Time
+}
+
+// NewS3Bucket creates an S3Bucket for config
+func NewS3Bucket(config S3Config) (*S3Bucket, error) {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	config.Prefix = strings.Trim(config.Prefix, "/")
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("%w: S3 requires an endpoint and a bucket", ErrInvalidConfig)
+	}
+	if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, fmt.Errorf("%w: S3 requires an access key and a secret key", ErrInvalidConfig)
+	}
+	if config.Region == "" {
+		config.Region = defaultS3Region
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 5 * time.Minute}
+	}
+
+	return &S3Bucket{config: config, now: time.Now}, nil
+}
+
+// Put stores data under name, skipping the upload if the stored object already has the same content
+// Returns whether the object was uploaded. Unchanged objects are recognised by their ETag, the MD5 of the content
+// for single-part uploads.
+func (b *S3Bucket) Put(ctx context.Context, name string, data []byte, contentType string) (bool, error) {
+	sum := md5.Sum(data) //nolint:gosec // Compared with the S3 ETag, not used for security
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	resp, err := b.do(ctx, http.MethodHead, name, nil, "")
+	if err != nil {
+		return false, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") == etag {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return false, fmt.Errorf("%w: HEAD %s: status %d", ErrServerRequestFailed, name, resp.StatusCode)
+	}
+
+	resp, err = b.do(ctx, http.MethodPut, name, data, contentType)
+	if err != nil {
+		return false, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%w: PUT %s: status %d", ErrServerRequestFailed, name, resp.StatusCode)
+	}
+	return true, nil
+}
+
+// do sends a signed request for the object name
+func (b *S3Bucket) do(ctx context.Context, method, name string, body []byte, contentType string) (*http.Response, error) {
+	key := name
+	if b.config.Prefix != "" {
+		key = b.config.Prefix + "/" + name
+	}
+	target := b.config.Endpoint + "/" + s3Escape(b.config.Bucket) + "/" + s3Escape(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	b.sign(req, body, b.now())
+
+	resp, err := b.config.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s %s: %w", method, name, err)
+	}
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers to req, signing the host and every header already set
+func (b *S3Bucket) sign(req *http.Request, body []byte, now time.Time) {
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+	amzDate := now.UTC().Format(s3TimeFormat)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	date := amzDate[:8]
+	scope := date + "/" + b.config.Region + "/s3/aws4_request"
+	stringToSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := s3HMAC([]byte("AWS4"+b.config.SecretKey), date)
+	key = s3HMAC(key, b.config.Region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	signature := hex.EncodeToString(s3HMAC(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, b.config.AccessKey, scope, signedHeaders, signature))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Escape percent-encodes an object key for the request path, keeping its slashes
+// Everything but unreserved characters is encoded, as Signature Version 4 requires of the canonical URI.
+func s3Escape(key string) string {
+	var b strings.Builder
+	for _, c := range []byte(key) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', strings.IndexByte("-._~/", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package chaintracks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+const (
+	defaultStaleTipThreshold = 60 * time.Minute
+	defaultStaleTipInterval  = time.Minute
+	staleTipAlertBuffer      = 16
+)
+
+// StaleTip describes a local tip that has not changed for longer than the configured threshold
+type StaleTip struct {
+	Height     uint32         `json:"height"`
+	Hash       chainhash.Hash `json:"hash"`
+	SeenAt     time.Time      `json:"seenAt"` // When this node first saw the tip, not the header timestamp
+	DetectedAt time.Time      `json:"detectedAt"`
+}
+
+// StaleTipConfig configures a StaleTipWatchdog
+type StaleTipConfig struct {
+	Threshold     time.Duration             // Time without a new tip before alerting (default 60m)
+	CheckInterval time.Duration             // Time between checks (default 1m)
+	OnStale       func(StaleTip)            // Optional alert hook
+	Repoll        func(ctx context.Context) // Optional forced re-poll of bootstrap sources, e.g. ChainManager.BootstrapSync
+	Logger        Logger                    // Defaults to the package default logger
+}
+
+// StaleTipWatchdog alerts when no new tip has arrived for the configured threshold
+// Arrival is measured locally, so a node restarted on an old chain is only stale once it fails to move on.
+// While the tip stays put the alert repeats every Threshold, retrying Repoll each time.
+type StaleTipWatchdog struct {
+	local  Chaintracks
+	config StaleTipConfig
+	alerts chan StaleTip
+
+	mu        sync.RWMutex
+	hash      chainhash.Hash
+	seenAt    time.Time
+	alertedAt time.Time
+}
+
+// NewStaleTipWatchdog creates a watchdog for the tip of local
+func NewStaleTipWatchdog(local Chaintracks, config StaleTipConfig) *StaleTipWatchdog {
+	if config.Threshold <= 0 {
+		config.Threshold = defaultStaleTipThreshold
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultStaleTipInterval
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &StaleTipWatchdog{
+		local:  local,
+		config: config,
+		alerts: make(chan StaleTip, staleTipAlertBuffer),
+	}
+}
+
+// Alerts returns a channel receiving every alert
+// Alerts are dropped rather than blocking the watchdog when the channel is full.
+func (w *StaleTipWatchdog) Alerts() <-chan StaleTip {
+	return w.alerts
+}
+
+// Run performs checks every CheckInterval until the context is cancelled
+func (w *StaleTipWatchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.CheckInterval)
+	defer ticker.Stop()
+
+	w.Check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx)
+		}
+	}
+}
+
+// Check records tip changes and alerts if the tip is stale, returning the alert if one fired
+func (w *StaleTipWatchdog) Check(ctx context.Context) (StaleTip, bool) {
+	return w.check(ctx, time.Now())
+}
+
+// check runs Check as of now
+func (w *StaleTipWatchdog) check(ctx context.Context, now time.Time) (StaleTip, bool) {
+	tip := w.local.GetTip(ctx)
+	if tip == nil {
+		return StaleTip{}, false
+	}
+
+	w.mu.Lock()
+	if w.seenAt.IsZero() || !tip.Hash.IsEqual(&w.hash) {
+		wasStale := !w.alertedAt.IsZero()
+		w.hash = tip.Hash
+		w.seenAt = now
+		w.alertedAt = time.Time{}
+		w.mu.Unlock()
+
+		if wasStale {
+			w.config.Logger.Info("Tip advanced after stale period", "height", tip.Height, "hash", tip.Hash)
+		}
+		return StaleTip{}, false
+	}
+
+	last := w.seenAt
+	if !w.alertedAt.IsZero() {
+		last = w.alertedAt
+	}
+	if now.Sub(last) < w.config.Threshold {
+		w.mu.Unlock()
+		return StaleTip{}, false
+	}
+	w.alertedAt = now
+	alert := StaleTip{Height: tip.Height, Hash: tip.Hash, SeenAt: w.seenAt, DetectedAt: now}
+	w.mu.Unlock()
+
+	w.config.Logger.Warn("No new tip", "height", tip.Height, "hash", tip.Hash, "since", now.Sub(alert.SeenAt))
+	if w.config.OnStale != nil {
+		w.config.OnStale(alert)
+	}
+	select {
+	case w.alerts <- alert:
+	default:
+	}
+	if w.config.Repoll != nil {
+		w.config.Repoll(ctx)
+	}
+	return alert, true
+}
+
+// Stale reports whether an alert has fired for the current tip
+func (w *StaleTipWatchdog) Stale() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return !w.alertedAt.IsZero()
+}
+
+// TipAge returns how long ago the current tip was first seen, 0 before the first check
+func (w *StaleTipWatchdog) TipAge() time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.seenAt.IsZero() {
+		return 0
+	}
+	return time.Since(w.seenAt)
+}
@@ -0,0 +1,94 @@
+package chaintracks
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleTipWatchdogCheck(t *testing.T) {
+	cm := newLinearChainManager(3)
+	var hooked []StaleTip
+	repolls := 0
+	w := NewStaleTipWatchdog(cm, StaleTipConfig{
+		Threshold: time.Hour,
+		OnStale:   func(alert StaleTip) { hooked = append(hooked, alert) },
+		Repoll:    func(context.Context) { repolls++ },
+		Logger:    slog.New(slog.DiscardHandler),
+	})
+	start := time.Unix(1700000000, 0)
+
+	_, fired := w.check(t.Context(), start)
+	assert.False(t, fired, "first check only records the tip")
+
+	_, fired = w.check(t.Context(), start.Add(59*time.Minute))
+	assert.False(t, fired)
+	assert.False(t, w.Stale())
+
+	alert, fired := w.check(t.Context(), start.Add(time.Hour))
+	require.True(t, fired)
+	assert.Equal(t, uint32(2), alert.Height)
+	assert.Equal(t, chainhash.Hash{3}, alert.Hash)
+	assert.Equal(t, start, alert.SeenAt)
+	assert.Equal(t, start.Add(time.Hour), alert.DetectedAt)
+	assert.True(t, w.Stale())
+	assert.Equal(t, []StaleTip{alert}, hooked)
+	assert.Equal(t, 1, repolls)
+	assert.Equal(t, alert, <-w.Alerts())
+
+	_, fired = w.check(t.Context(), start.Add(90*time.Minute))
+	assert.False(t, fired, "alert does not repeat within the threshold")
+
+	_, fired = w.check(t.Context(), start.Add(2*time.Hour))
+	assert.True(t, fired, "alert repeats every threshold while stale")
+	assert.Equal(t, 2, repolls)
+
+	hash := chainhash.Hash{4}
+	header := &BlockHeader{Header: &block.Header{}, Height: 3, Hash: hash}
+	cm.byHeight = append(cm.byHeight, hash)
+	cm.byHash[hash] = header
+	cm.tip.Store(header)
+
+	_, fired = w.check(t.Context(), start.Add(3*time.Hour))
+	assert.False(t, fired)
+	assert.False(t, w.Stale(), "a new tip clears the alert")
+
+	_, fired = w.check(t.Context(), start.Add(3*time.Hour+59*time.Minute))
+	assert.False(t, fired, "threshold restarts from the new tip")
+}
+
+func TestStaleTipWatchdogEmptyChain(t *testing.T) {
+	w := NewStaleTipWatchdog(&ChainManager{}, StaleTipConfig{Logger: slog.New(slog.DiscardHandler)})
+
+	_, fired := w.check(t.Context(), time.Now().Add(24*time.Hour))
+	assert.False(t, fired)
+	assert.Zero(t, w.TipAge())
+}
+
+func TestNewStaleTipWatchdogDefaults(t *testing.T) {
+	w := NewStaleTipWatchdog(&ChainManager{}, StaleTipConfig{})
+	assert.Equal(t, defaultStaleTipThreshold, w.config.Threshold)
+	assert.Equal(t, defaultStaleTipInterval, w.config.CheckInterval)
+	assert.NotNil(t, w.config.Logger)
+}
+
+func TestStaleTipWatchdogDropsAlertsWhenFull(t *testing.T) {
+	w := NewStaleTipWatchdog(newLinearChainManager(1), StaleTipConfig{
+		Threshold: time.Minute,
+		Logger:    slog.New(slog.DiscardHandler),
+	})
+	start := time.Unix(1700000000, 0)
+	w.check(t.Context(), start)
+
+	for i := 1; i <= staleTipAlertBuffer+2; i++ {
+		_, fired := w.check(t.Context(), start.Add(time.Duration(i)*time.Minute))
+		require.True(t, fired)
+	}
+	assert.Len(t, w.Alerts(), staleTipAlertBuffer)
+}
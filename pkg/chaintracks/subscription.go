@@ -0,0 +1,190 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// subscriptionErrBuffer is how many undelivered errors a Subscription holds before dropping new ones
+const subscriptionErrBuffer = 4
+
+// subscribeConfig holds the options of a Subscription
+type subscribeConfig struct {
+	everyNth      uint32
+	confirmations uint32
+	bufferSize    int
+}
+
+// SubscribeOption configures a Subscription
+type SubscribeOption func(*subscribeConfig)
+
+// EveryNthBlock delivers only headers at heights divisible by n; 0 or 1 delivers every block
+func EveryNthBlock(n uint32) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.everyNth = max(n, 1)
+	}
+}
+
+// MinConfirmations delivers a header only once it has d confirmations, the tip itself having one
+// 0 or 1 delivers each header as it becomes the tip.
+func MinConfirmations(d uint32) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.confirmations = max(d, 1)
+	}
+}
+
+// SubscriptionBuffer sets how many headers the Headers channel holds for a slow consumer (default 16)
+func SubscriptionBuffer(n int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.bufferSize = max(n, 0)
+	}
+}
+
+// Subscription delivers main-chain headers in height order, filtered by its options
+// Every matching height is delivered once it qualifies: a consumer that falls behind is caught up from the chain
+// rather than skipped, and while it is the subscription stops reading events instead of blocking the chain.
+// A reorg replacing headers already delivered is reported on Err, wrapping ErrSubscriptionReorg, and the new
+// branch's headers follow on Headers.
+type Subscription struct {
+	headers chan *BlockHeader
+	errs    chan error
+	cancel  context.CancelFunc
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Headers returns the channel of delivered headers, closed when the subscription ends
+func (s *Subscription) Headers() <-chan *BlockHeader {
+	return s.headers
+}
+
+// Err returns the channel of non-fatal subscription errors, closed when the subscription ends
+// Errors arriving while the channel is full are dropped.
+func (s *Subscription) Err() <-chan error {
+	return s.errs
+}
+
+// Unsubscribe ends the subscription and waits until both channels are closed
+// Safe to call more than once; cancelling the context passed to Subscribe has the same effect.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(s.cancel)
+	<-s.done
+}
+
+// Subscribe returns a Subscription to main-chain headers that reach the chain after the call
+// Without options it delivers every new tip; see EveryNthBlock, MinConfirmations and SubscriptionBuffer.
+func (cm *ChainManager) Subscribe(ctx context.Context, opts ...SubscribeOption) *Subscription {
+	config := subscribeConfig{everyNth: 1, confirmations: 1, bufferSize: eventBufferSize}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Subscription{
+		headers: make(chan *BlockHeader, config.bufferSize),
+		errs:    make(chan error, subscriptionErrBuffer),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	w := &subscriptionWorker{cm: cm, sub: s, config: config}
+	if tip := cm.GetTip(ctx); tip != nil {
+		if confirmed, ok := w.confirmedHeight(tip.Height); ok {
+			w.next = confirmed + 1
+		}
+	}
+	events := cm.SubscribeEvents(ctx)
+
+	go func() {
+		defer close(s.done)
+		defer close(s.errs)
+		defer close(s.headers)
+		for event := range events {
+			if !w.handle(ctx, event) {
+				cancel()
+			}
+		}
+	}()
+	return s
+}
+
+// subscriptionWorker tracks what a Subscription has delivered
+type subscriptionWorker struct {
+	cm     *ChainManager
+	sub    *Subscription
+	config subscribeConfig
+
+	next     uint32       // Next height to consider for delivery
+	lastSent *BlockHeader // Last delivered header, nil when none is still on the main chain
+}
+
+// confirmedHeight returns the highest height with enough confirmations under a tip at tipHeight
+func (w *subscriptionWorker) confirmedHeight(tipHeight uint32) (uint32, bool) {
+	if uint64(tipHeight)+1 < uint64(w.config.confirmations) {
+		return 0, false
+	}
+	return tipHeight + 1 - w.config.confirmations, true
+}
+
+// handle delivers the headers that qualify after event, returning false once the consumer has gone
+// The chain is compared with the last delivered header rather than trusting reorg events, which a
+// subscription busy delivering may have missed.
+func (w *subscriptionWorker) handle(ctx context.Context, event *ChainEvent) bool {
+	if event.Type != EventTipAdvanced && event.Type != EventReorg {
+		return true
+	}
+	tip := w.cm.GetTip(ctx)
+	if tip == nil {
+		return true
+	}
+
+	if w.lastSent != nil {
+		if main, err := w.cm.GetHeaderByHeight(ctx, w.lastSent.Height); err != nil || main.Hash != w.lastSent.Hash {
+			fork := w.forkHeight(ctx, tip)
+			w.sendErr(fmt.Errorf("%w: fork at height %d", ErrSubscriptionReorg, fork))
+			w.next = min(w.next, fork+1)
+			w.lastSent = nil
+		}
+	}
+
+	confirmed, ok := w.confirmedHeight(tip.Height)
+	for ; ok && w.next <= confirmed; w.next++ {
+		if w.next%w.config.everyNth != 0 {
+			continue
+		}
+		header, err := w.cm.GetHeaderByHeight(ctx, w.next)
+		if err != nil {
+			// Retried on the next event
+			w.sendErr(fmt.Errorf("height %d: %w", w.next, err))
+			return true
+		}
+		select {
+		case w.sub.headers <- header:
+		case <-ctx.Done():
+			return false
+		}
+		w.lastSent = header
+	}
+	return true
+}
+
+// forkHeight returns the height of the last block the delivered branch shares with the chain ending at tip
+// Falls back to the block below the last delivered header when its branch has been pruned.
+func (w *subscriptionWorker) forkHeight(ctx context.Context, tip *BlockHeader) uint32 {
+	if ancestor, err := w.cm.FindCommonAncestor(ctx, &w.lastSent.Hash, &tip.Hash); err == nil {
+		return ancestor.Height
+	}
+	if w.lastSent.Height == 0 {
+		return 0
+	}
+	return w.lastSent.Height - 1
+}
+
+// sendErr reports err without blocking, dropping it when the Err channel is full
+func (w *subscriptionWorker) sendErr(err error) {
+	select {
+	case w.sub.errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,139 @@
+package chaintracks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSubscriptionChainManager returns a ChainManager whose main chain is heights 0 to 2
+func newSubscriptionChainManager(t *testing.T) *ChainManager {
+	t.Helper()
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(0, 1), testHeader(1, 1), testHeader(2, 1)}))
+	return cm
+}
+
+// receiveHeights waits for count headers and returns their heights
+func receiveHeights(t *testing.T, sub *Subscription, count int) []uint32 {
+	t.Helper()
+	heights := make([]uint32, 0, count)
+	for range count {
+		select {
+		case header := <-sub.Headers():
+			heights = append(heights, header.Height)
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for header", "received %v", heights)
+		}
+	}
+	return heights
+}
+
+func TestChainManagerSubscribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []SubscribeOption
+		expected []uint32
+	}{
+		{name: "EveryTip", expected: []uint32{3, 4, 5, 6}},
+		{name: "EveryNthBlock", opts: []SubscribeOption{EveryNthBlock(2)}, expected: []uint32{4, 6}},
+		{name: "MinConfirmations", opts: []SubscribeOption{MinConfirmations(3)}, expected: []uint32{1, 2, 3, 4}},
+		{name: "Combined", opts: []SubscribeOption{MinConfirmations(2), EveryNthBlock(3)}, expected: []uint32{3}},
+		{name: "UnbufferedWaitsForConsumer", opts: []SubscribeOption{SubscriptionBuffer(0)}, expected: []uint32{3, 4, 5, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newSubscriptionChainManager(t)
+			sub := cm.Subscribe(t.Context(), tt.opts...)
+			defer sub.Unsubscribe()
+
+			require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 1)}))
+			require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(4, 1), testHeader(5, 1), testHeader(6, 1)}))
+
+			assert.Equal(t, tt.expected, receiveHeights(t, sub, len(tt.expected)))
+			select {
+			case header := <-sub.Headers():
+				assert.Failf(t, "unexpected header", "height %d", header.Height)
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestChainManagerSubscribeReorg(t *testing.T) {
+	cm := newSubscriptionChainManager(t)
+	sub := cm.Subscribe(t.Context())
+	defer sub.Unsubscribe()
+
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 1)}))
+	assert.Equal(t, []uint32{3}, receiveHeights(t, sub, 1))
+
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 2), testHeader(4, 2)}))
+	select {
+	case err := <-sub.Err():
+		require.ErrorIs(t, err, ErrSubscriptionReorg)
+		assert.Contains(t, err.Error(), "fork at height 2")
+	case <-time.After(time.Second):
+		require.FailNow(t, "reorg was not reported")
+	}
+
+	header := <-sub.Headers()
+	assert.Equal(t, testHeader(3, 2).Hash, header.Hash, "the new branch is delivered from the fork")
+	assert.Equal(t, []uint32{4}, receiveHeights(t, sub, 1))
+}
+
+func TestChainManagerSubscribeShallowReorgBelowConfirmations(t *testing.T) {
+	cm := newSubscriptionChainManager(t)
+	sub := cm.Subscribe(t.Context(), MinConfirmations(2))
+	defer sub.Unsubscribe()
+
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 1)}))
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 2), testHeader(4, 2)}))
+
+	heights := receiveHeights(t, sub, 2)
+	assert.Equal(t, []uint32{2, 3}, heights)
+	select {
+	case err := <-sub.Err():
+		assert.Failf(t, "unexpected error", "%v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscriptionUnsubscribe(t *testing.T) {
+	t.Run("ClosesChannels", func(t *testing.T) {
+		sub := newSubscriptionChainManager(t).Subscribe(t.Context())
+		sub.Unsubscribe()
+		sub.Unsubscribe()
+
+		_, ok := <-sub.Headers()
+		assert.False(t, ok)
+		_, ok = <-sub.Err()
+		assert.False(t, ok)
+	})
+
+	t.Run("ContextCancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		sub := newSubscriptionChainManager(t).Subscribe(ctx)
+		cancel()
+
+		select {
+		case _, ok := <-sub.Headers():
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			require.FailNow(t, "channel was not closed")
+		}
+	})
+
+	t.Run("WhileBlockedOnConsumer", func(t *testing.T) {
+		cm := newSubscriptionChainManager(t)
+		sub := cm.Subscribe(t.Context(), SubscriptionBuffer(0))
+		require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{testHeader(3, 1)}))
+		time.Sleep(20 * time.Millisecond)
+		sub.Unsubscribe()
+	})
+}
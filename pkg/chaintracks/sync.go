@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"math/big"
 	"net/http"
+	"runtime"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/bsv-blockchain/go-sdk/block"
@@ -14,94 +16,131 @@ import (
 )
 
 const (
-	maxHeadersPerRequest = 1000
-	headerSize           = 80
+	defaultSyncBatchSize     = 1000
+	defaultSyncRequestWindow = 2
+	headerSize               = 80
 )
 
+// SyncConfig tunes catch-up sync from a remote node, used by bootstrap, Teranode and P2P crawl-back
+// Zero values keep the defaults. Besides the branch being imported, a sync holds about
+// BatchSize*(RequestWindow+1) fetched headers, so small values suit low-memory deployments.
+type SyncConfig struct {
+	BatchSize     int // Headers requested per round trip walking back from the remote tip (default 1000)
+	Workers       int // Goroutines hashing each fetched batch (default GOMAXPROCS)
+	RequestWindow int // Batches requested from the source ahead of the one being checked (default 2)
+}
+
+// withDefaults fills in unset fields
+func (c SyncConfig) withDefaults() SyncConfig {
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultSyncBatchSize
+	}
+	if c.Workers <= 0 {
+		c.Workers = max(runtime.GOMAXPROCS(0), 1)
+	}
+	if c.RequestWindow <= 0 {
+		c.RequestWindow = defaultSyncRequestWindow
+	}
+	return c
+}
+
+// SetSyncConfig sets how catch-up syncs fetch headers; call it before Start or BootstrapSync
+func (cm *ChainManager) SetSyncConfig(config SyncConfig) {
+	cm.syncConfig = config
+}
+
 // SyncFromRemoteTip walks backwards from a remote tip to find common ancestor,
 // then imports the entire branch in one operation. This is used for both
 // bootstrap sync and P2P block messages with unknown parents.
 //
-//nolint:gocyclo // Complex sync and ancestor finding logic
+// Progress is reported through SyncStatus and EventSyncProgress.
 func (cm *ChainManager) SyncFromRemoteTip(ctx context.Context, remoteTipHash chainhash.Hash, baseURL string) error {
+	return cm.syncFromRemote(ctx, remoteTipHash, baseURL, 0)
+}
+
+// syncFromRemote implements SyncFromRemoteTip; target is the remote tip height if known, 0 to estimate it
+//
+//nolint:gocyclo // Complex sync and ancestor finding logic
+func (cm *ChainManager) syncFromRemote(ctx context.Context, remoteTipHash chainhash.Hash, baseURL string, target uint32) (err error) {
 	// Check if we already have the remote tip
 	if _, err := cm.GetHeaderByHash(ctx, &remoteTipHash); err == nil {
-		log.Printf("Already have block %s", remoteTipHash.String())
+		cm.log().Debug("Already have block", "hash", remoteTipHash)
 		return nil
 	}
 
-	// Walk backwards from remote tip to find common ancestor
-	log.Printf("Walking backwards from remote tip %s to find common ancestor", remoteTipHash.String())
+	cm.beginSync(ctx, baseURL, target)
+	defer func() { cm.endSync(ctx, err) }()
+
+	// Walk backwards from remote tip to find common ancestor, fetching ahead while each batch is checked
+	config := cm.syncConfig.withDefaults()
+	cm.log().Info("Walking backwards from remote tip to find common ancestor", "hash", remoteTipHash, "url", baseURL, "batchSize", config.BatchSize)
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	defer cancelFetch()
+	batches := fetchBatches(fetchCtx, baseURL, remoteTipHash, config)
+
 	branch := make([]*block.Header, 0, 10000)
-	currentHash := remoteTipHash
+	hashes := make([]chainhash.Hash, 0, 10000)
 	var commonAncestor *BlockHeader
 
 	startTime := time.Now()
-	for {
-		// Check if we have this block in our chain
-		existingHeader, err := cm.GetHeaderByHash(ctx, &currentHash)
-		if err == nil {
-			// Found common ancestor!
-			commonAncestor = existingHeader
-			log.Printf("Found common ancestor at height %d after %v", commonAncestor.Height, time.Since(startTime))
-			break
+	for commonAncestor == nil {
+		batch, ok := <-batches
+		if !ok {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return ErrCommonAncestorNotFound
 		}
-
-		// Fetch batch of headers walking backwards
-		startFetch := time.Now()
-		headers, err := fetchHeadersBackward(baseURL, currentHash.String(), maxHeadersPerRequest)
-		fetchDuration := time.Since(startFetch)
-		if err != nil {
-			return fmt.Errorf("failed to fetch headers walking backward from %s: %w", currentHash.String(), err)
+		if batch.err != nil {
+			return fmt.Errorf("failed to fetch headers walking backward from %s: %w", batch.from.String(), batch.err)
 		}
-
+		headers := batch.headers
 		if len(headers) == 0 {
-			return fmt.Errorf("%w from %s/headers/%s?n=%d", ErrNoHeadersReturned, baseURL, currentHash.String(), maxHeadersPerRequest)
+			return fmt.Errorf("%w from %s/headers/%s?n=%d", ErrNoHeadersReturned, baseURL, batch.from.String(), config.BatchSize)
 		}
 
-		log.Printf("Fetched %d headers in %v from %s/headers/%s?n=%d", len(headers), fetchDuration, baseURL, currentHash.String(), maxHeadersPerRequest)
+		cm.log().Debug("Fetched headers", "count", len(headers), "elapsed", batch.elapsed, "url", baseURL, "from", batch.from)
 
-		// Add headers to branch (they're in reverse order - newest first)
-		branch = append(branch, headers...)
+		// The first header of the first batch is the remote tip, which dates the sync target
+		var newest *block.Header
+		if len(branch) == 0 {
+			newest = headers[0]
+		}
 
-		// Check if any of the fetched headers exist in our chain
-		found := false
-		for i, header := range headers {
-			hash := header.Hash()
-			if existingHeader, err := cm.GetHeaderByHash(ctx, &hash); err == nil {
-				// Found common ancestor!
+		// Keep the headers newer than the first one we already have (they're in reverse order - newest first)
+		fetched := len(headers)
+		for i := range headers {
+			if existingHeader, err := cm.GetHeaderByHash(ctx, &batch.hashes[i]); err == nil {
 				commonAncestor = existingHeader
-				// Trim the branch to only include headers after the common ancestor
-				branch = branch[:len(branch)-len(headers)+i]
-				found = true
-				log.Printf("Found common ancestor at height %d after %v", commonAncestor.Height, time.Since(startTime))
+				fetched = i
 				break
 			}
 		}
-
-		if found {
-			break
+		if commonAncestor == nil {
+			// The parent of the batch's last header may be known without fetching the next batch
+			if existingHeader, err := cm.GetHeaderByHash(ctx, &headers[len(headers)-1].PrevHash); err == nil {
+				commonAncestor = existingHeader
+			}
 		}
-
-		// Continue from the last header's parent
-		currentHash = headers[len(headers)-1].PrevHash
+		branch = append(branch, headers[:fetched]...)
+		hashes = append(hashes, batch.hashes[:fetched]...)
+		cm.advanceSync(ctx, fetched, newest)
 	}
+	cancelFetch()
+	cm.log().Info("Found common ancestor", "height", commonAncestor.Height, "elapsed", time.Since(startTime))
 
-	if commonAncestor == nil {
-		return ErrCommonAncestorNotFound
-	}
+	cm.fixSyncTarget(ctx, commonAncestor.Height+uint32(len(branch))) //nolint:gosec // Branch length fits in uint32
 
 	if len(branch) == 0 {
-		log.Printf("No new headers to sync")
+		cm.log().Info("No new headers to sync")
 		return nil
 	}
 
-	log.Printf("Found %d new headers to import", len(branch))
+	cm.log().Info("Found new headers to import", "count", len(branch))
 
 	// Reverse branch (it's currently newest to oldest, we need oldest to newest)
-	for i := 0; i < len(branch)/2; i++ {
-		branch[i], branch[len(branch)-1-i] = branch[len(branch)-1-i], branch[i]
-	}
+	slices.Reverse(branch)
+	slices.Reverse(hashes)
 
 	// Calculate heights and chainwork for the entire branch
 	startConvert := time.Now()
@@ -116,27 +155,84 @@ func (cm *ChainManager) SyncFromRemoteTip(ctx context.Context, remoteTipHash cha
 		blockHeaders[i] = &BlockHeader{
 			Header:    header,
 			Height:    currentHeight,
-			Hash:      header.Hash(),
+			Hash:      hashes[i],
 			ChainWork: new(big.Int).Set(currentChainWork),
 		}
 		currentHeight++
 	}
-	log.Printf("Calculated chainwork for %d headers in %v", len(blockHeaders), time.Since(startConvert))
+	cm.log().Debug("Calculated chainwork", "count", len(blockHeaders), "elapsed", time.Since(startConvert))
+	markValidated(ctx)
 
 	// Import entire branch in one operation
 	startSetTip := time.Now()
 	if err := cm.SetChainTip(ctx, blockHeaders); err != nil {
 		return fmt.Errorf("failed to set chain tip: %w", err)
 	}
-	log.Printf("SetChainTip took %v", time.Since(startSetTip))
+	cm.metrics.addHeaders(len(blockHeaders))
+	cm.log().Debug("SetChainTip complete", "elapsed", time.Since(startSetTip))
 
 	newTip := cm.GetTip(ctx)
-	log.Printf("Sync complete. New chain tip: %s at height %d (added %d headers)",
-		newTip.Header.Hash().String(), newTip.Height, len(blockHeaders))
+	cm.log().Info("Sync complete", "hash", newTip.Hash, "height", newTip.Height, "added", len(blockHeaders))
 
 	return nil
 }
 
+// syncBatch is one batch of headers fetched walking back from a remote tip, newest first, with their hashes
+type syncBatch struct {
+	from    chainhash.Hash
+	headers []*block.Header
+	hashes  []chainhash.Hash
+	elapsed time.Duration
+	err     error
+}
+
+// fetchBatches walks back from start in the background, delivering batches until a fetch fails, a batch comes
+// back short because the source reached genesis, or ctx is cancelled
+// The next request is sent as soon as a batch arrives, so up to config.RequestWindow batches wait ahead of the consumer.
+func fetchBatches(ctx context.Context, baseURL string, start chainhash.Hash, config SyncConfig) <-chan syncBatch {
+	batches := make(chan syncBatch, config.RequestWindow-1)
+	go func() {
+		defer close(batches)
+		current := start
+		for {
+			begin := time.Now()
+			headers, err := fetchHeadersBackward(ctx, baseURL, current.String(), config.BatchSize)
+			batch := syncBatch{from: current, headers: headers, elapsed: time.Since(begin), err: err}
+			if err == nil {
+				batch.hashes = hashHeaders(headers, config.Workers)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case batches <- batch:
+			}
+			if err != nil || len(headers) < config.BatchSize {
+				return
+			}
+			current = headers[len(headers)-1].PrevHash
+		}
+	}()
+	return batches
+}
+
+// hashHeaders hashes every header, splitting the work across up to workers goroutines
+func hashHeaders(headers []*block.Header, workers int) []chainhash.Hash {
+	hashes := make([]chainhash.Hash, len(headers))
+	chunk := (len(headers) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(headers); start += chunk {
+		end := min(start+chunk, len(headers))
+		wg.Go(func() {
+			for i := start; i < end; i++ {
+				hashes[i] = headers[i].Hash()
+			}
+		})
+	}
+	wg.Wait()
+	return hashes
+}
+
 // FetchLatestBlock gets the latest block hash from the node's bestblockheader endpoint
 func FetchLatestBlock(ctx context.Context, baseURL string) (chainhash.Hash, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/bestblockheader", baseURL), nil)
@@ -176,11 +272,16 @@ func FetchLatestBlock(ctx context.Context, baseURL string) (chainhash.Hash, erro
 // fetchHeadersBackward fetches headers walking backwards from a starting hash
 // Uses the /headers/:hash endpoint which traverses backwards (child -> parent)
 // Returns headers in reverse chronological order (newest first)
-func fetchHeadersBackward(baseURL, startHash string, count int) ([]*block.Header, error) {
+func fetchHeadersBackward(ctx context.Context, baseURL, startHash string, count int) ([]*block.Header, error) {
 	// Use binary endpoint for efficiency (80 bytes per header vs 160 for hex)
 	url := fmt.Sprintf("%s/headers/%s?n=%d", baseURL, startHash, count)
 
-	resp, err := http.Get(url) //nolint:noctx,gosec // URL is constructed from validated inputs
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch headers: %w", err)
 	}
@@ -4,8 +4,12 @@ import (
 	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/bsv-blockchain/go-sdk/block"
 	"github.com/bsv-blockchain/go-sdk/chainhash"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,3 +99,80 @@ func TestFetchLatestBlock(t *testing.T) {
 		})
 	}
 }
+
+// newBackwardHeadersServer serves /headers/<hash>?n=<count> walking back through chain, recording each requested count
+func newBackwardHeadersServer(t *testing.T, chain []*block.Header) (*httptest.Server, func() []int) {
+	t.Helper()
+
+	byHash := make(map[string]int, len(chain))
+	for i, header := range chain {
+		byHash[header.Hash().String()] = i
+	}
+
+	var mu sync.Mutex
+	var counts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+		mu.Lock()
+		counts = append(counts, n)
+		mu.Unlock()
+
+		i, ok := byHash[strings.TrimPrefix(r.URL.Path, "/headers/")]
+		if !ok {
+			return
+		}
+		for ; i >= 0 && n > 0; i, n = i-1, n-1 {
+			_, _ = w.Write(chain[i].Bytes())
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), counts...)
+	}
+}
+
+func TestSyncFromRemoteTipConfig(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 7, 0)
+	remote := append([]*block.Header{genesis}, chain...)
+
+	tests := []struct {
+		name   string
+		config SyncConfig
+	}{
+		{name: "Defaults"},
+		{name: "SmallBatches", config: SyncConfig{BatchSize: 2, Workers: 1, RequestWindow: 1}},
+		{name: "WideWindow", config: SyncConfig{BatchSize: 3, Workers: 4, RequestWindow: 8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, counts := newBackwardHeadersServer(t, remote)
+			cm := newWireChainManager(t, genesis, chain[:2])
+			cm.SetSyncConfig(tt.config)
+
+			require.NoError(t, cm.SyncFromRemoteTip(t.Context(), chain[6].Hash(), server.URL))
+			assert.Equal(t, chain[6].Hash(), cm.GetTip(t.Context()).Hash)
+			assert.Equal(t, uint32(7), cm.GetHeight(t.Context()))
+
+			batchSize := tt.config.withDefaults().BatchSize
+			for _, n := range counts() {
+				assert.Equal(t, batchSize, n)
+			}
+		})
+	}
+}
+
+func TestHashHeaders(t *testing.T) {
+	headers := mineRegtestChain(t, chainhash.Hash{}, 5, 0)
+	for _, workers := range []int{1, 2, 5, 8} {
+		hashes := hashHeaders(headers, workers)
+		require.Len(t, hashes, len(headers))
+		for i, header := range headers {
+			assert.Equal(t, header.Hash(), hashes[i], "workers=%d header %d", workers, i)
+		}
+	}
+	assert.Empty(t, hashHeaders(nil, 4))
+}
@@ -0,0 +1,139 @@
+package chaintracks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+)
+
+// targetBlockInterval is the expected time between blocks, used to estimate how far behind a remote tip is
+const targetBlockInterval = 10 * time.Minute
+
+// SyncProgress reports a bulk header sync from a remote node, either bootstrap or P2P catch-up
+// Headers are fetched walking backwards from the remote tip, so CurrentHeight counts fetched headers on top of
+// StartHeight rather than the local tip, which only moves when the whole branch is imported.
+type SyncProgress struct {
+	Active           bool      `json:"active"`
+	Source           string    `json:"source"`        // URL the headers are fetched from
+	StartHeight      uint32    `json:"startHeight"`   // Local tip height when the sync began
+	CurrentHeight    uint32    `json:"currentHeight"` // StartHeight plus headers fetched so far; the new tip height once complete
+	TargetHeight     uint32    `json:"targetHeight"`
+	TargetEstimated  bool      `json:"targetEstimated"` // TargetHeight is extrapolated from header timestamps until the common ancestor is found
+	HeadersPerSecond float64   `json:"headersPerSecond"`
+	ETASeconds       float64   `json:"etaSeconds"`
+	StartedAt        time.Time `json:"startedAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	Error            string    `json:"error,omitempty"` // Set when the sync failed
+}
+
+// syncTracker holds the progress of the current or most recent sync
+type syncTracker struct {
+	mu       sync.RWMutex
+	progress SyncProgress
+	fetched  int
+}
+
+// SyncStatus returns the progress of the current sync, or of the most recent one when Active is false
+// The zero value means no sync has run since startup.
+func (cm *ChainManager) SyncStatus() SyncProgress {
+	cm.syncs.mu.RLock()
+	defer cm.syncs.mu.RUnlock()
+	return cm.syncs.progress
+}
+
+// beginSync starts tracking a sync from source; a zero target is estimated once the first headers arrive
+func (cm *ChainManager) beginSync(ctx context.Context, source string, target uint32) {
+	start := cm.GetHeight(ctx)
+	now := time.Now()
+
+	cm.syncs.mu.Lock()
+	cm.syncs.fetched = 0
+	cm.syncs.progress = SyncProgress{
+		Active:          true,
+		Source:          source,
+		StartHeight:     start,
+		CurrentHeight:   start,
+		TargetHeight:    max(target, start),
+		TargetEstimated: target == 0,
+		StartedAt:       now,
+		UpdatedAt:       now,
+	}
+	cm.syncs.mu.Unlock()
+
+	cm.publishSyncProgress(ctx)
+}
+
+// advanceSync records fetched headers; newest is the first header of the first batch, used to estimate the target
+func (cm *ChainManager) advanceSync(ctx context.Context, fetched int, newest *block.Header) {
+	tip := cm.GetTip(ctx)
+
+	cm.syncs.mu.Lock()
+	p := &cm.syncs.progress
+	cm.syncs.fetched += fetched
+	p.CurrentHeight = p.StartHeight + uint32(cm.syncs.fetched) //nolint:gosec // Header counts fit in uint32
+	if p.TargetEstimated && newest != nil && tip != nil && newest.Timestamp > tip.Header.Timestamp {
+		behind := time.Duration(newest.Timestamp-tip.Header.Timestamp) * time.Second / targetBlockInterval
+		p.TargetHeight = p.StartHeight + uint32(behind) //nolint:gosec // Bounded by the header timestamp range
+	}
+	p.TargetHeight = max(p.TargetHeight, p.CurrentHeight)
+	cm.syncs.updateRateLocked(time.Now())
+	cm.syncs.mu.Unlock()
+
+	cm.publishSyncProgress(ctx)
+}
+
+// fixSyncTarget sets the exact target once the common ancestor and branch length are known
+func (cm *ChainManager) fixSyncTarget(ctx context.Context, target uint32) {
+	cm.syncs.mu.Lock()
+	p := &cm.syncs.progress
+	p.TargetHeight = target
+	p.TargetEstimated = false
+	p.CurrentHeight = min(p.CurrentHeight, target)
+	cm.syncs.updateRateLocked(time.Now())
+	cm.syncs.mu.Unlock()
+
+	cm.publishSyncProgress(ctx)
+}
+
+// endSync marks the sync finished, recording err if it failed
+func (cm *ChainManager) endSync(ctx context.Context, err error) {
+	height := cm.GetHeight(ctx)
+
+	cm.syncs.mu.Lock()
+	p := &cm.syncs.progress
+	p.Active = false
+	p.ETASeconds = 0
+	p.UpdatedAt = time.Now()
+	if err != nil {
+		p.Error = err.Error()
+	} else {
+		p.CurrentHeight = height
+		p.TargetHeight = max(p.TargetHeight, height)
+		p.TargetEstimated = false
+	}
+	cm.syncs.mu.Unlock()
+
+	cm.publishSyncProgress(ctx)
+}
+
+// updateRateLocked recomputes the rate and ETA (must be called with mu held)
+func (t *syncTracker) updateRateLocked(now time.Time) {
+	p := &t.progress
+	p.UpdatedAt = now
+
+	elapsed := now.Sub(p.StartedAt).Seconds()
+	if elapsed <= 0 || t.fetched == 0 {
+		return
+	}
+	p.HeadersPerSecond = float64(t.fetched) / elapsed
+	p.ETASeconds = float64(p.TargetHeight-p.CurrentHeight) / p.HeadersPerSecond
+}
+
+// publishSyncProgress emits the current progress, together with the lag status, to event subscribers
+func (cm *ChainManager) publishSyncProgress(ctx context.Context) {
+	progress := cm.SyncStatus()
+	lag := cm.GetLagStatus()
+	cm.publishEvent(&ChainEvent{Type: EventSyncProgress, Tip: cm.GetTip(ctx), Lag: &lag, Progress: &progress})
+}
@@ -0,0 +1,157 @@
+package chaintracks
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const syncTestGenesisTime = 1700000000
+
+// newRemoteHeaderServer serves /headers/:hash?n= walking backwards over chain, newest first
+func newRemoteHeaderServer(t *testing.T, chain []*block.Header) *httptest.Server {
+	t.Helper()
+
+	index := make(map[string]int, len(chain))
+	for i, header := range chain {
+		index[header.Hash().String()] = i
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i, ok := index[strings.TrimPrefix(r.URL.Path, "/headers/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+		for ; i >= 0 && n > 0; i, n = i-1, n-1 {
+			_, _ = w.Write(chain[i].Bytes())
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newSyncTestChain returns a local chain manager holding a genesis header, and count remote headers extending it
+func newSyncTestChain(t *testing.T, count int) (*ChainManager, []*block.Header) {
+	t.Helper()
+
+	genesis := &block.Header{Bits: 0x207fffff, Timestamp: syncTestGenesisTime}
+	chain := []*block.Header{genesis}
+	for i := 1; i <= count; i++ {
+		chain = append(chain, &block.Header{
+			Version:   1,
+			PrevHash:  chain[i-1].Hash(),
+			Bits:      0x207fffff,
+			Timestamp: syncTestGenesisTime + uint32(i)*600, //nolint:gosec // Test data
+			Nonce:     uint32(i),                           //nolint:gosec // Test data
+		})
+	}
+
+	cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{{
+		Header:    genesis,
+		Height:    0,
+		Hash:      genesis.Hash(),
+		ChainWork: big.NewInt(1),
+	}}))
+	return cm, chain
+}
+
+// collectSyncProgress drains sync-progress events until the sync reports inactive
+func collectSyncProgress(t *testing.T, events <-chan *ChainEvent) []SyncProgress {
+	t.Helper()
+
+	var progress []SyncProgress
+	for {
+		event := receiveEvent(t, events)
+		if event.Type != EventSyncProgress {
+			continue
+		}
+		require.NotNil(t, event.Lag)
+		progress = append(progress, *event.Progress)
+		if !event.Progress.Active {
+			return progress
+		}
+	}
+}
+
+func TestChainManagerSyncProgress(t *testing.T) {
+	cm, chain := newSyncTestChain(t, 2500)
+	server := newRemoteHeaderServer(t, chain)
+	events := cm.SubscribeEvents(t.Context())
+
+	assert.Equal(t, SyncProgress{}, cm.SyncStatus())
+
+	require.NoError(t, cm.SyncFromRemoteTip(t.Context(), chain[len(chain)-1].Hash(), server.URL))
+
+	progress := collectSyncProgress(t, events)
+	require.GreaterOrEqual(t, len(progress), 4)
+
+	first := progress[0]
+	assert.True(t, first.Active)
+	assert.Equal(t, server.URL, first.Source)
+	assert.Equal(t, uint32(0), first.StartHeight)
+	assert.True(t, first.TargetEstimated)
+
+	// The first batch dates the remote tip, so the estimate lands on the real height
+	assert.Equal(t, uint32(1000), progress[1].CurrentHeight)
+	assert.Equal(t, uint32(2500), progress[1].TargetHeight)
+	assert.Positive(t, progress[1].HeadersPerSecond)
+
+	for i := 1; i < len(progress); i++ {
+		assert.GreaterOrEqual(t, progress[i].CurrentHeight, progress[i-1].CurrentHeight)
+	}
+
+	final := cm.SyncStatus()
+	assert.Equal(t, progress[len(progress)-1], final)
+	assert.False(t, final.Active)
+	assert.False(t, final.TargetEstimated)
+	assert.Equal(t, uint32(2500), final.CurrentHeight)
+	assert.Equal(t, uint32(2500), final.TargetHeight)
+	assert.Zero(t, final.ETASeconds)
+	assert.Empty(t, final.Error)
+	assert.Equal(t, uint32(2500), cm.GetHeight(t.Context()))
+}
+
+func TestChainManagerSyncProgressKnownTarget(t *testing.T) {
+	cm, chain := newSyncTestChain(t, 3)
+	server := newRemoteHeaderServer(t, chain)
+	events := cm.SubscribeEvents(t.Context())
+
+	require.NoError(t, cm.syncFromRemote(t.Context(), chain[3].Hash(), server.URL, 3))
+
+	progress := collectSyncProgress(t, events)
+	assert.False(t, progress[0].TargetEstimated)
+	assert.Equal(t, uint32(3), progress[0].TargetHeight)
+}
+
+func TestChainManagerSyncProgressFailure(t *testing.T) {
+	cm, _ := newSyncTestChain(t, 0)
+	server := newRemoteHeaderServer(t, nil)
+	events := cm.SubscribeEvents(t.Context())
+
+	err := cm.SyncFromRemoteTip(t.Context(), chainhash.Hash{9}, server.URL)
+	require.ErrorIs(t, err, ErrServerRequestFailed)
+
+	progress := collectSyncProgress(t, events)
+	final := progress[len(progress)-1]
+	assert.False(t, final.Active)
+	assert.Contains(t, final.Error, ErrServerRequestFailed.Error())
+	assert.Equal(t, final, cm.SyncStatus())
+}
+
+func TestChainManagerSyncProgressSkipsKnownTip(t *testing.T) {
+	cm, chain := newSyncTestChain(t, 0)
+
+	require.NoError(t, cm.SyncFromRemoteTip(t.Context(), chain[0].Hash(), "http://unused"))
+	assert.Equal(t, SyncProgress{}, cm.SyncStatus())
+}
@@ -0,0 +1,73 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultTeranodePollInterval is how often a TeranodeSync checks the node's tip by default
+const defaultTeranodePollInterval = 5 * time.Second
+
+// TeranodeSyncConfig configures a TeranodeSync
+type TeranodeSyncConfig struct {
+	URLs         []string      // Asset Service API base URLs, e.g. http://teranode:8090/api/v1, tried in order
+	PollInterval time.Duration // Time between tip checks (default 5s)
+	Logger       Logger        // Defaults to the package default logger
+}
+
+// TeranodeSync follows the chain from the Asset Service of an operator's own Teranode, as an alternative to the
+// P2P message bus
+// Each poll reads the node's best block header and, when it is new, imports the branch leading to it through
+// SyncFromRemoteTip, so catch-up and reorgs take the same path as bootstrap sync and the node is trusted as it
+// is there. When a node fails the next one is tried.
+type TeranodeSync struct {
+	cm     *ChainManager
+	config TeranodeSyncConfig
+}
+
+// NewTeranodeSync creates a TeranodeSync feeding cm from the configured Asset Services
+func NewTeranodeSync(cm *ChainManager, config TeranodeSyncConfig) (*TeranodeSync, error) {
+	urls := make([]string, 0, len(config.URLs))
+	for _, url := range config.URLs {
+		if url = strings.TrimSuffix(url, "/"); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("%w: Teranode sync requires at least one Asset Service URL", ErrInvalidConfig)
+	}
+	config.URLs = urls
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultTeranodePollInterval
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &TeranodeSync{cm: cm, config: config}, nil
+}
+
+// Run polls immediately and then every PollInterval until ctx is cancelled
+func (s *TeranodeSync) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Poll(ctx); err != nil && ctx.Err() == nil {
+			s.config.Logger.Warn("Teranode sync failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll imports the best chain of the first node that answers, returning every node's error if none does
+func (s *TeranodeSync) Poll(ctx context.Context) error {
+	return syncFromFirst(ctx, s.cm, s.config.URLs)
+}
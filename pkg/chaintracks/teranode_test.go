@@ -0,0 +1,78 @@
+package chaintracks
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeranodeSyncPoll(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 3, 0)
+	fork := mineRegtestChain(t, chain[0].Hash(), 4, 1)
+
+	t.Run("CatchesUp", func(t *testing.T) {
+		node, _ := newBootstrapServer(t, 0, false, append([]*block.Header{genesis}, chain...)...)
+		cm := newWireChainManager(t, genesis, chain[:1])
+		s, err := NewTeranodeSync(cm, TeranodeSyncConfig{URLs: []string{node.URL}})
+		require.NoError(t, err)
+
+		require.NoError(t, s.Poll(t.Context()))
+		assert.Equal(t, chain[2].Hash(), cm.GetTip(t.Context()).Hash)
+	})
+
+	t.Run("FollowsReorg", func(t *testing.T) {
+		node, _ := newBootstrapServer(t, 0, false, append([]*block.Header{genesis, chain[0]}, fork...)...)
+		cm := newWireChainManager(t, genesis, chain)
+		s, err := NewTeranodeSync(cm, TeranodeSyncConfig{URLs: []string{node.URL}})
+		require.NoError(t, err)
+
+		require.NoError(t, s.Poll(t.Context()))
+		assert.Equal(t, fork[3].Hash(), cm.GetTip(t.Context()).Hash)
+	})
+
+	t.Run("SkipsKnownTip", func(t *testing.T) {
+		node, headerRequests := newBootstrapServer(t, 0, false, append([]*block.Header{genesis}, chain...)...)
+		cm := newWireChainManager(t, genesis, chain)
+		s, err := NewTeranodeSync(cm, TeranodeSyncConfig{URLs: []string{node.URL}})
+		require.NoError(t, err)
+
+		require.NoError(t, s.Poll(t.Context()))
+		assert.Equal(t, int32(0), headerRequests.Load())
+	})
+
+	t.Run("FailsOverToNextNode", func(t *testing.T) {
+		broken, _ := newBootstrapServer(t, 0, true, append([]*block.Header{genesis}, chain...)...)
+		node, _ := newBootstrapServer(t, 0, false, append([]*block.Header{genesis}, chain...)...)
+		cm := newWireChainManager(t, genesis, chain[:1])
+		s, err := NewTeranodeSync(cm, TeranodeSyncConfig{URLs: []string{broken.URL, node.URL}})
+		require.NoError(t, err)
+
+		require.NoError(t, s.Poll(t.Context()))
+		assert.Equal(t, chain[2].Hash(), cm.GetTip(t.Context()).Hash)
+	})
+
+	t.Run("ReportsEveryNodeFailure", func(t *testing.T) {
+		broken, _ := newBootstrapServer(t, 0, true, append([]*block.Header{genesis}, chain...)...)
+		cm := newWireChainManager(t, genesis, chain[:1])
+		s, err := NewTeranodeSync(cm, TeranodeSyncConfig{URLs: []string{broken.URL}})
+		require.NoError(t, err)
+
+		err = s.Poll(t.Context())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), broken.URL)
+		assert.Equal(t, chain[0].Hash(), cm.GetTip(t.Context()).Hash)
+	})
+}
+
+func TestNewTeranodeSyncValidatesConfig(t *testing.T) {
+	_, err := NewTeranodeSync(&ChainManager{}, TeranodeSyncConfig{URLs: []string{"", "/"}})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	s, err := NewTeranodeSync(&ChainManager{}, TeranodeSyncConfig{URLs: []string{"http://teranode:8090/api/v1/"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://teranode:8090/api/v1"}, s.config.URLs)
+	assert.Equal(t, defaultTeranodePollInterval, s.config.PollInterval)
+}
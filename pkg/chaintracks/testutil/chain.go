@@ -0,0 +1,130 @@
+// Package testutil provides synthetic header chains for testing code built on chaintracks.
+package testutil
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+const (
+	// DefaultBits is the regtest proof of work limit, which about half of all hashes meet
+	DefaultBits uint32 = 0x207fffff
+
+	// DefaultStartTime is the timestamp of the generated genesis block, that of the real mainnet genesis
+	DefaultStartTime uint32 = 1231006505
+
+	// DefaultBlockInterval is the number of seconds between generated block timestamps
+	DefaultBlockInterval uint32 = 600
+)
+
+// Options controls GenerateChain; the zero value gives a regtest-difficulty chain without solved proof of work
+type Options struct {
+	Bits          uint32 // Difficulty of every header, DefaultBits when zero
+	StartTime     uint32 // Genesis timestamp, DefaultStartTime when zero
+	BlockInterval uint32 // Seconds between timestamps, DefaultBlockInterval when zero
+	SolvePoW      bool   // Search nonces until each hash meets Bits, so headers pass chaintracks.CheckProofOfWork
+	Forks         []ForkSpec
+}
+
+// ForkSpec asks GenerateChain for an alternate branch whose first block is at Height
+type ForkSpec struct {
+	Height uint32 // Height of the first fork block, from 1 to the main chain tip
+	Length int    // Number of fork blocks; longer than the main chain from Height up makes it the heavier chain
+}
+
+// Fork is a generated alternate branch, oldest first, whose first header's parent is on the main chain
+type Fork struct {
+	Height  uint32
+	Headers []*chaintracks.BlockHeader
+}
+
+// Chain is a generated main chain, indexed by height, and its forks in the order they were requested
+type Chain struct {
+	Headers []*chaintracks.BlockHeader
+	Forks   []*Fork
+}
+
+// Tip returns the last main chain header
+func (c *Chain) Tip() *chaintracks.BlockHeader {
+	return c.Headers[len(c.Headers)-1]
+}
+
+// GenerateChain returns n linked headers from a synthetic genesis, plus any forks requested in opts
+// Height, Hash and ChainWork are set on every header, so the main chain can be handed straight to
+// ChainManager.SetChainTip and a fork to ChainManager.AddHeaders to trigger a reorg. Headers are deterministic
+// for a given n and opts. Panics if n is not positive or a fork does not start between height 1 and the tip.
+func GenerateChain(n int, opts Options) *Chain {
+	if n <= 0 {
+		panic("testutil: GenerateChain needs at least one header")
+	}
+	if opts.Bits == 0 {
+		opts.Bits = DefaultBits
+	}
+	if opts.StartTime == 0 {
+		opts.StartTime = DefaultStartTime
+	}
+	if opts.BlockInterval == 0 {
+		opts.BlockInterval = DefaultBlockInterval
+	}
+
+	chain := &Chain{Headers: extend(nil, 0, n, 0, opts)}
+	for i, spec := range opts.Forks {
+		if spec.Height == 0 || int(spec.Height) >= n {
+			panic(fmt.Sprintf("testutil: fork height %d outside 1..%d", spec.Height, n-1))
+		}
+		chain.Forks = append(chain.Forks, &Fork{
+			Height:  spec.Height,
+			Headers: extend(chain.Headers[spec.Height-1], spec.Height, spec.Length, uint32(i+1), opts), //nolint:gosec // Fork count is small
+		})
+	}
+	return chain
+}
+
+// extend builds count headers on parent, starting at height; branch keeps merkle roots distinct between forks
+func extend(parent *chaintracks.BlockHeader, height uint32, count int, branch uint32, opts Options) []*chaintracks.BlockHeader {
+	headers := make([]*chaintracks.BlockHeader, 0, count)
+	for range count {
+		header := &block.Header{
+			Version:    1,
+			MerkleRoot: merkleRoot(height, branch),
+			Timestamp:  opts.StartTime,
+			Bits:       opts.Bits,
+		}
+		work := chaintracks.CalculateWork(opts.Bits)
+		if parent != nil {
+			header.PrevHash = parent.Hash
+			header.Timestamp = parent.Timestamp + opts.BlockInterval
+			if parent.ChainWork != nil {
+				work.Add(work, parent.ChainWork)
+			}
+		}
+		if opts.SolvePoW {
+			solve(header)
+		}
+
+		parent = &chaintracks.BlockHeader{Header: header, Height: height, Hash: header.Hash(), ChainWork: work}
+		headers = append(headers, parent)
+		height++
+	}
+	return headers
+}
+
+// merkleRoot derives a stand-in merkle root unique to a height on a branch
+func merkleRoot(height, branch uint32) chainhash.Hash {
+	var seed [8]byte
+	binary.LittleEndian.PutUint32(seed[:4], height)
+	binary.LittleEndian.PutUint32(seed[4:], branch)
+	return chainhash.DoubleHashH(seed[:])
+}
+
+// solve increments the nonce until the header hash meets its own target
+func solve(header *block.Header) {
+	for chaintracks.CheckProofOfWork(header, header.Bits) != nil {
+		header.Nonce++
+	}
+}
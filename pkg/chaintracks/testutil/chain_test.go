@@ -0,0 +1,67 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+func TestGenerateChain(t *testing.T) {
+	t.Run("Linked", func(t *testing.T) {
+		chain := GenerateChain(20, Options{})
+		require.Len(t, chain.Headers, 20)
+		for i, header := range chain.Headers {
+			assert.Equal(t, uint32(i), header.Height) //nolint:gosec // Test heights are small
+			assert.Equal(t, header.Header.Hash(), header.Hash)
+			if i > 0 {
+				assert.Equal(t, chain.Headers[i-1].Hash, header.PrevHash)
+				assert.Equal(t, 1, header.ChainWork.Cmp(chain.Headers[i-1].ChainWork))
+				assert.Equal(t, DefaultBlockInterval, header.Timestamp-chain.Headers[i-1].Timestamp)
+			}
+		}
+		assert.Same(t, chain.Headers[19], chain.Tip())
+		assert.Equal(t, chain.Tip().Hash, GenerateChain(20, Options{}).Tip().Hash, "deterministic")
+	})
+
+	t.Run("SolvePoW", func(t *testing.T) {
+		chain := GenerateChain(10, Options{SolvePoW: true, Forks: []ForkSpec{{Height: 5, Length: 3}}})
+		for _, header := range append(chain.Headers, chain.Forks[0].Headers...) {
+			require.NoError(t, chaintracks.CheckProofOfWork(header.Header, 0))
+		}
+	})
+
+	t.Run("Forks", func(t *testing.T) {
+		chain := GenerateChain(10, Options{Forks: []ForkSpec{{Height: 5, Length: 3}, {Height: 5, Length: 6}}})
+		require.Len(t, chain.Forks, 2)
+		for _, fork := range chain.Forks {
+			assert.Equal(t, chain.Headers[4].Hash, fork.Headers[0].PrevHash)
+			assert.Equal(t, uint32(5), fork.Headers[0].Height)
+			assert.NotEqual(t, chain.Headers[5].Hash, fork.Headers[0].Hash)
+		}
+		assert.NotEqual(t, chain.Forks[0].Headers[0].Hash, chain.Forks[1].Headers[0].Hash, "forks at the same height differ")
+	})
+
+	t.Run("InvalidFork", func(t *testing.T) {
+		assert.Panics(t, func() { GenerateChain(10, Options{Forks: []ForkSpec{{Height: 10, Length: 1}}}) })
+		assert.Panics(t, func() { GenerateChain(10, Options{Forks: []ForkSpec{{Height: 0, Length: 1}}}) })
+	})
+}
+
+func TestGenerateChainReorg(t *testing.T) {
+	chain := GenerateChain(10, Options{Forks: []ForkSpec{{Height: 7, Length: 2}, {Height: 7, Length: 4}}})
+	cm, err := chaintracks.NewChainManager(t.Context(), "regtest", t.TempDir(), nil)
+	require.NoError(t, err)
+	require.NoError(t, cm.SetChainTip(t.Context(), chain.Headers))
+
+	require.NoError(t, cm.AddHeaders(t.Context(), chain.Forks[0].Headers))
+	assert.Equal(t, chain.Tip().Hash, cm.GetTip(t.Context()).Hash, "the shorter fork is kept as an alternate chain")
+
+	require.NoError(t, cm.AddHeaders(t.Context(), chain.Forks[1].Headers))
+	tip := cm.GetTip(t.Context())
+	assert.Equal(t, chain.Forks[1].Headers[3].Hash, tip.Hash)
+	assert.Equal(t, uint32(10), tip.Height)
+	assert.Equal(t, 0, tip.ChainWork.Cmp(chain.Forks[1].Headers[3].ChainWork))
+}
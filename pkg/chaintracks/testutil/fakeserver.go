@@ -0,0 +1,112 @@
+package testutil
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	"github.com/bsv-blockchain/go-chaintracks/routes/stdhttp"
+)
+
+// FakeServer is an httptest server speaking the chaintracks v2 API, including the SSE tip stream, over a chain
+// held in memory. Point a chaintracks.Client at URL, then extend or reorg the chain and drop streams to drive the
+// client through new tips, reorgs and reconnects.
+type FakeServer struct {
+	URL string
+
+	cm          *chaintracks.ChainManager
+	routes      *stdhttp.Routes
+	server      *httptest.Server
+	unavailable atomic.Bool
+
+	streamMu sync.Mutex
+	streams  map[int]context.CancelFunc
+	streamID int
+}
+
+// NewFakeServer serves chain's main chain and returns once the server is listening; forks are left for AddHeaders
+// The server is closed when the test ends.
+func NewFakeServer(t testing.TB, chain *Chain) *FakeServer {
+	t.Helper()
+
+	cm, err := chaintracks.NewChainManager(context.Background(), "main", t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("testutil: create chain manager: %v", err)
+	}
+	cm.SetLogger(slog.New(slog.DiscardHandler))
+	if err := cm.SetChainTip(context.Background(), chain.Headers); err != nil {
+		t.Fatalf("testutil: load chain: %v", err)
+	}
+
+	f := &FakeServer{cm: cm, routes: stdhttp.NewRoutes(cm), streams: make(map[int]context.CancelFunc)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2"+stdhttp.RouteTipStream, f.handleTipStream)
+	f.routes.Register(mux, "/v2")
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.unavailable.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	f.URL = f.server.URL
+	t.Cleanup(f.Close)
+	return f
+}
+
+// ChainManager returns the chain behind the server, for assertions or changes AddHeaders does not cover
+func (f *FakeServer) ChainManager() *chaintracks.ChainManager {
+	return f.cm
+}
+
+// AddHeaders links headers, oldest first, onto the chain; a branch with more work than the tip reorgs to it
+// Open streams receive the resulting tip or reorg event, as from a real server.
+func (f *FakeServer) AddHeaders(headers ...*chaintracks.BlockHeader) error {
+	return f.cm.AddHeaders(context.Background(), headers)
+}
+
+// DisconnectStreams closes every open SSE stream, so clients reconnect and resume with Last-Event-ID
+func (f *FakeServer) DisconnectStreams() {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+
+	for id, cancel := range f.streams {
+		cancel()
+		delete(f.streams, id)
+	}
+}
+
+// SetUnavailable makes every request fail with 503 until called with false; new streams cannot connect meanwhile
+func (f *FakeServer) SetUnavailable(unavailable bool) {
+	f.unavailable.Store(unavailable)
+}
+
+// Close disconnects streams and shuts the server down
+func (f *FakeServer) Close() {
+	f.DisconnectStreams()
+	f.server.CloseClientConnections()
+	f.server.Close()
+}
+
+// handleTipStream serves /v2/tip/stream as the stdhttp routes do, under a context DisconnectStreams cancels
+func (f *FakeServer) handleTipStream(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	f.streamMu.Lock()
+	f.streamID++
+	id := f.streamID
+	f.streams[id] = cancel
+	f.streamMu.Unlock()
+	defer func() {
+		f.streamMu.Lock()
+		delete(f.streams, id)
+		f.streamMu.Unlock()
+	}()
+
+	f.routes.HandleTipStream(w, r.WithContext(ctx))
+}
@@ -0,0 +1,81 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// receiveTip waits for the next tip on tips
+func receiveTip(t *testing.T, tips <-chan *chaintracks.BlockHeader) *chaintracks.BlockHeader {
+	t.Helper()
+	select {
+	case tip := <-tips:
+		return tip
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for tip")
+		return nil
+	}
+}
+
+func TestFakeServer(t *testing.T) {
+	chain := GenerateChain(12, Options{Forks: []ForkSpec{{Height: 9, Length: 4}}})
+	mainChain, extension := chain.Headers[:10], chain.Headers[10:]
+
+	t.Run("QueryRoutes", func(t *testing.T) {
+		server := NewFakeServer(t, &Chain{Headers: mainChain})
+		client := chaintracks.NewClient(server.URL)
+
+		header, err := client.GetHeaderByHeight(t.Context(), 5)
+		require.NoError(t, err)
+		assert.Equal(t, mainChain[5].Hash, header.Hash)
+
+		valid, err := client.IsValidRootForHeight(t.Context(), &mainChain[3].MerkleRoot, 3)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("TipsAndReorgs", func(t *testing.T) {
+		server := NewFakeServer(t, &Chain{Headers: mainChain})
+		client := chaintracks.NewClient(server.URL)
+		reorgs := make(chan *chaintracks.ReorgInfo, 4)
+		client.OnReorg(func(reorg *chaintracks.ReorgInfo) { reorgs <- reorg })
+
+		tips, err := client.Start(t.Context())
+		require.NoError(t, err)
+		defer func() { _ = client.Stop() }()
+		assert.Equal(t, mainChain[9].Hash, receiveTip(t, tips).Hash)
+
+		require.NoError(t, server.AddHeaders(extension[0]))
+		assert.Equal(t, extension[0].Hash, receiveTip(t, tips).Hash)
+
+		require.NoError(t, server.AddHeaders(chain.Forks[0].Headers...))
+		assert.Equal(t, chain.Forks[0].Headers[3].Hash, receiveTip(t, tips).Hash)
+		select {
+		case reorg := <-reorgs:
+			assert.Equal(t, uint32(8), reorg.ForkHeight)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for reorg")
+		}
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		server := NewFakeServer(t, &Chain{Headers: mainChain})
+		client := chaintracks.NewClient(server.URL)
+		tips, err := client.Start(t.Context())
+		require.NoError(t, err)
+		defer func() { _ = client.Stop() }()
+		receiveTip(t, tips)
+
+		server.SetUnavailable(true)
+		server.DisconnectStreams()
+		require.NoError(t, server.AddHeaders(extension...))
+		server.SetUnavailable(false)
+
+		assert.Equal(t, extension[1].Hash, receiveTip(t, tips).Hash, "missed tips are replayed on reconnect")
+	})
+}
@@ -0,0 +1,254 @@
+package testutil
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// mockTipBuffer is how many published tips Start's channel holds before Publish blocks
+const mockTipBuffer = 16
+
+var _ chaintracks.Chaintracks = (*MockChaintracks)(nil)
+
+// MockChaintracks is an in-memory Chaintracks with a scripted chain, for testing code that consumes one
+// The main chain is whatever was last set with SetHeaders or Publish; headers added with AddHeaders are found by
+// hash but are not on it. It is safe for concurrent use.
+type MockChaintracks struct {
+	mu       sync.RWMutex
+	byHeight []*chaintracks.BlockHeader
+	byHash   map[chainhash.Hash]*chaintracks.BlockHeader
+	network  string
+	errs     map[string]error
+	tips     chan *chaintracks.BlockHeader
+	stopped  chan struct{}
+}
+
+// NewMockChaintracks returns a mock on network "main" whose main chain is headers, oldest first
+// Pass GenerateChain(n, opts).Headers for a linked chain; headers need Height and Hash set.
+func NewMockChaintracks(headers ...*chaintracks.BlockHeader) *MockChaintracks {
+	m := &MockChaintracks{
+		byHash:  make(map[chainhash.Hash]*chaintracks.BlockHeader),
+		network: "main",
+		errs:    make(map[string]error),
+		tips:    make(chan *chaintracks.BlockHeader, mockTipBuffer),
+		stopped: make(chan struct{}),
+	}
+	m.SetHeaders(headers...)
+	return m
+}
+
+// SetHeaders puts headers on the main chain at their heights and makes the last one the tip
+// Main chain heights above the new tip are dropped, so passing a fork branch scripts a reorg.
+func (m *MockChaintracks) SetHeaders(headers ...*chaintracks.BlockHeader) {
+	if len(headers) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, header := range headers {
+		for uint32(len(m.byHeight)) <= header.Height { //nolint:gosec // Length comparison while growing
+			m.byHeight = append(m.byHeight, nil)
+		}
+		m.byHeight[header.Height] = header
+		m.byHash[header.Hash] = header
+	}
+	m.byHeight = m.byHeight[:headers[len(headers)-1].Height+1]
+}
+
+// SetTip makes header the tip, replacing the main chain at its height; see SetHeaders
+func (m *MockChaintracks) SetTip(header *chaintracks.BlockHeader) {
+	m.SetHeaders(header)
+}
+
+// AddHeaders makes headers findable by hash without changing the main chain, such as orphans or a lighter fork
+func (m *MockChaintracks) AddHeaders(headers ...*chaintracks.BlockHeader) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, header := range headers {
+		m.byHash[header.Hash] = header
+	}
+}
+
+// Publish applies headers as SetHeaders does and delivers the new tip on the channel returned by Start
+// It blocks while the channel is full, until the tip is received or Stop is called.
+func (m *MockChaintracks) Publish(headers ...*chaintracks.BlockHeader) {
+	if len(headers) == 0 {
+		return
+	}
+	m.SetHeaders(headers...)
+
+	select {
+	case m.tips <- headers[len(headers)-1]:
+	case <-m.stopped:
+	}
+}
+
+// SetNetwork sets the name GetNetwork returns
+func (m *MockChaintracks) SetNetwork(network string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.network = network
+}
+
+// SetError makes the named method, such as "GetHeaderByHeight" or "Start", return err until cleared with nil
+// Only methods of the Chaintracks interface that return an error can fail.
+func (m *MockChaintracks) SetError(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err == nil {
+		delete(m.errs, method)
+		return
+	}
+	m.errs[method] = err
+}
+
+// injected returns the error set for method, if any (must be called with lock held)
+func (m *MockChaintracks) injected(method string) error {
+	return m.errs[method]
+}
+
+// Start returns the channel Publish delivers tips on; every call returns the same channel
+func (m *MockChaintracks) Start(_ context.Context) (<-chan *chaintracks.BlockHeader, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.injected("Start"); err != nil {
+		return nil, err
+	}
+	return m.tips, nil
+}
+
+// Stop releases any Publish blocked on a full channel, and later Publish calls never block
+func (m *MockChaintracks) Stop() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.injected("Stop"); err != nil {
+		return err
+	}
+	select {
+	case <-m.stopped:
+	default:
+		close(m.stopped)
+	}
+	return nil
+}
+
+// GetHeight returns the tip height, 0 for an empty chain
+func (m *MockChaintracks) GetHeight(ctx context.Context) uint32 {
+	if tip := m.GetTip(ctx); tip != nil {
+		return tip.Height
+	}
+	return 0
+}
+
+// GetTip returns the last main chain header, nil for an empty chain
+func (m *MockChaintracks) GetTip(_ context.Context) *chaintracks.BlockHeader {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.byHeight) == 0 {
+		return nil
+	}
+	return m.byHeight[len(m.byHeight)-1]
+}
+
+// GetHeaderByHeight returns the main chain header at height
+func (m *MockChaintracks) GetHeaderByHeight(_ context.Context, height uint32) (*chaintracks.BlockHeader, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.injected("GetHeaderByHeight"); err != nil {
+		return nil, err
+	}
+	return m.headerAt(height)
+}
+
+// headerAt returns the main chain header at height (must be called with lock held)
+func (m *MockChaintracks) headerAt(height uint32) (*chaintracks.BlockHeader, error) {
+	if height >= uint32(len(m.byHeight)) || m.byHeight[height] == nil { //nolint:gosec // Length comparison
+		return nil, chaintracks.ErrHeaderNotFound
+	}
+	return m.byHeight[height], nil
+}
+
+// GetHeaderByHash returns any header the mock knows, on the main chain or not
+func (m *MockChaintracks) GetHeaderByHash(_ context.Context, hash *chainhash.Hash) (*chaintracks.BlockHeader, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.injected("GetHeaderByHash"); err != nil {
+		return nil, err
+	}
+	header, ok := m.byHash[*hash]
+	if !ok {
+		return nil, chaintracks.ErrHeaderNotFound
+	}
+	return header, nil
+}
+
+// GetHeadersBackwards follows PrevHash from fromHash through known headers, newest first
+func (m *MockChaintracks) GetHeadersBackwards(_ context.Context, fromHash *chainhash.Hash, count uint32) ([]*chaintracks.BlockHeader, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.injected("GetHeadersBackwards"); err != nil {
+		return nil, err
+	}
+	header, ok := m.byHash[*fromHash]
+	if !ok {
+		return nil, chaintracks.ErrHeaderNotFound
+	}
+
+	headers := make([]*chaintracks.BlockHeader, 0, min(count, header.Height+1))
+	for ok && uint32(len(headers)) < count { //nolint:gosec // Length comparison
+		headers = append(headers, header)
+		header, ok = m.byHash[header.PrevHash]
+	}
+	return headers, nil
+}
+
+// GetNetwork returns the network set with SetNetwork, "main" by default
+func (m *MockChaintracks) GetNetwork(_ context.Context) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.injected("GetNetwork"); err != nil {
+		return "", err
+	}
+	return m.network, nil
+}
+
+// IsValidRootForHeight reports whether root is the merkle root of the main chain header at height
+func (m *MockChaintracks) IsValidRootForHeight(_ context.Context, root *chainhash.Hash, height uint32) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.injected("IsValidRootForHeight"); err != nil {
+		return false, err
+	}
+	header, err := m.headerAt(height)
+	if err != nil {
+		return false, err
+	}
+	return header.MerkleRoot.IsEqual(root), nil
+}
+
+// CurrentHeight returns the tip height
+func (m *MockChaintracks) CurrentHeight(ctx context.Context) (uint32, error) {
+	m.mu.RLock()
+	err := m.injected("CurrentHeight")
+	m.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+	return m.GetHeight(ctx), nil
+}
@@ -0,0 +1,100 @@
+package testutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+func TestMockChaintracks(t *testing.T) {
+	chain := GenerateChain(10, Options{Forks: []ForkSpec{{Height: 8, Length: 3}}})
+
+	t.Run("ScriptedChain", func(t *testing.T) {
+		mock := NewMockChaintracks(chain.Headers...)
+		assert.Equal(t, uint32(9), mock.GetHeight(t.Context()))
+
+		header, err := mock.GetHeaderByHeight(t.Context(), 4)
+		require.NoError(t, err)
+		assert.Same(t, chain.Headers[4], header)
+
+		valid, err := mock.IsValidRootForHeight(t.Context(), &chain.Headers[4].MerkleRoot, 4)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		headers, err := mock.GetHeadersBackwards(t.Context(), &chain.Tip().Hash, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []*chaintracks.BlockHeader{chain.Headers[9], chain.Headers[8], chain.Headers[7]}, headers)
+
+		_, err = mock.GetHeaderByHeight(t.Context(), 10)
+		require.ErrorIs(t, err, chaintracks.ErrHeaderNotFound)
+	})
+
+	t.Run("ForkReplacesMainChain", func(t *testing.T) {
+		mock := NewMockChaintracks(chain.Headers...)
+		mock.SetHeaders(chain.Forks[0].Headers...)
+		assert.Same(t, chain.Forks[0].Headers[2], mock.GetTip(t.Context()))
+
+		header, err := mock.GetHeaderByHeight(t.Context(), 8)
+		require.NoError(t, err)
+		assert.Same(t, chain.Forks[0].Headers[0], header)
+
+		orphan, err := mock.GetHeaderByHash(t.Context(), &chain.Headers[9].Hash)
+		require.NoError(t, err, "replaced headers are still found by hash")
+		assert.Same(t, chain.Headers[9], orphan)
+
+		mock.SetTip(chain.Headers[5])
+		assert.Equal(t, uint32(5), mock.GetHeight(t.Context()))
+	})
+
+	t.Run("Publish", func(t *testing.T) {
+		mock := NewMockChaintracks(chain.Headers[:8]...)
+		tips, err := mock.Start(t.Context())
+		require.NoError(t, err)
+
+		go mock.Publish(chain.Headers[8], chain.Headers[9])
+		select {
+		case tip := <-tips:
+			assert.Same(t, chain.Headers[9], tip)
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for tip")
+		}
+		assert.Same(t, chain.Headers[9], mock.GetTip(t.Context()))
+
+		require.NoError(t, mock.Stop())
+		for range mockTipBuffer + 1 {
+			mock.Publish(chain.Headers[9]) // never blocks once stopped
+		}
+	})
+
+	t.Run("ErrorInjection", func(t *testing.T) {
+		mock := NewMockChaintracks(chain.Headers...)
+		errUnavailable := errors.New("unavailable")
+		mock.SetError("GetHeaderByHeight", errUnavailable)
+		mock.SetError("Start", errUnavailable)
+
+		_, err := mock.GetHeaderByHeight(t.Context(), 1)
+		require.ErrorIs(t, err, errUnavailable)
+		_, err = mock.Start(t.Context())
+		require.ErrorIs(t, err, errUnavailable)
+		_, err = mock.GetHeaderByHash(t.Context(), &chain.Headers[1].Hash)
+		require.NoError(t, err, "other methods are unaffected")
+
+		mock.SetError("GetHeaderByHeight", nil)
+		_, err = mock.GetHeaderByHeight(t.Context(), 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		mock := NewMockChaintracks()
+		assert.Nil(t, mock.GetTip(t.Context()))
+		mock.SetNetwork("test")
+		network, err := mock.GetNetwork(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "test", network)
+	})
+}
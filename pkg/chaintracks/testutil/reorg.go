@@ -0,0 +1,141 @@
+package testutil
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// reorgEventTimeout is how long Reorg waits for the chain manager to publish the reorg event
+const reorgEventTimeout = 5 * time.Second
+
+// reorgBranches numbers the branches Reorg mines, kept apart from GenerateChain's fork numbers so no two
+// branches share a header
+var reorgBranches atomic.Uint32 //nolint:gochecknoglobals // Process-wide branch counter
+
+// Reorg replaces the top depth blocks of cm's main chain with newLength competing blocks and checks the result
+// The branch is linked through ChainManager.AddHeaders, the path headers from any source take, with the bits of
+// the replaced tip so it wins on work when newLength exceeds depth. Reorg then fails t unless the tip moved to
+// the branch, heights resolve to it, and exactly one reorg event arrived with the next sequence number, the fork
+// height, the orphaned hashes oldest first and the new branch. It returns that event.
+func Reorg(t testing.TB, cm *chaintracks.ChainManager, depth, newLength int) *chaintracks.ChainEvent {
+	t.Helper()
+	ctx := context.Background()
+
+	tip := cm.GetTip(ctx)
+	if tip == nil {
+		t.Fatalf("testutil: Reorg needs a chain with a tip")
+	}
+	if depth < 1 || uint32(depth) > tip.Height || newLength <= depth { //nolint:gosec // depth is positive
+		t.Fatalf("testutil: Reorg needs 1 <= depth <= %d and newLength > depth, got depth %d and newLength %d",
+			tip.Height, depth, newLength)
+	}
+
+	forkHeight := tip.Height - uint32(depth) //nolint:gosec // Checked against the tip height
+	parent, err := cm.GetHeaderByHeight(ctx, forkHeight)
+	if err != nil {
+		t.Fatalf("testutil: fork point at height %d: %v", forkHeight, err)
+	}
+	orphaned := make([]*chaintracks.BlockHeader, 0, depth)
+	for height := forkHeight + 1; height <= tip.Height; height++ {
+		header, err := cm.GetHeaderByHeight(ctx, height)
+		if err != nil {
+			t.Fatalf("testutil: main chain at height %d: %v", height, err)
+		}
+		orphaned = append(orphaned, header)
+	}
+
+	branch := extend(parent, forkHeight+1, newLength, 1<<31|reorgBranches.Add(1), Options{
+		Bits:          tip.Bits,
+		BlockInterval: DefaultBlockInterval,
+	})
+
+	eventCtx, cancel := context.WithTimeout(ctx, reorgEventTimeout)
+	defer cancel()
+	events := cm.SubscribeEvents(eventCtx)
+	lastSeq := cm.LastEventSeq()
+
+	if err := cm.AddHeaders(ctx, branch); err != nil {
+		t.Fatalf("testutil: add competing branch: %v", err)
+	}
+
+	newTip := branch[len(branch)-1]
+	if got := cm.GetTip(ctx); got == nil || got.Hash != newTip.Hash {
+		t.Fatalf("testutil: tip did not move to the competing branch %s", newTip.Hash)
+	}
+	for _, header := range branch {
+		if got, err := cm.GetHeaderByHeight(ctx, header.Height); err != nil || got.Hash != header.Hash {
+			t.Errorf("testutil: height %d does not resolve to the competing branch", header.Height)
+		}
+	}
+
+	event := awaitReorgEvent(eventCtx, t, events)
+	checkReorgEvent(t, event, lastSeq+1, forkHeight, orphaned, branch)
+	return event
+}
+
+// awaitReorgEvent returns the next reorg event, failing t if a tip event or the timeout comes first
+func awaitReorgEvent(ctx context.Context, t testing.TB, events <-chan *chaintracks.ChainEvent) *chaintracks.ChainEvent {
+	t.Helper()
+	for {
+		var event *chaintracks.ChainEvent
+		select {
+		case <-ctx.Done():
+		case event = <-events:
+		}
+		if event == nil {
+			t.Fatalf("testutil: no reorg event within %s", reorgEventTimeout)
+			return nil
+		}
+		switch event.Type {
+		case chaintracks.EventReorg:
+			return event
+		case chaintracks.EventTipAdvanced:
+			t.Fatalf("testutil: got a tip event to %s instead of a reorg", event.Tip.Hash)
+		}
+	}
+}
+
+// checkReorgEvent fails t unless event describes the replacement of orphaned by branch after forkHeight
+func checkReorgEvent(t testing.TB, event *chaintracks.ChainEvent, seq uint64, forkHeight uint32,
+	orphaned, branch []*chaintracks.BlockHeader,
+) {
+	t.Helper()
+
+	if event.Seq != seq {
+		t.Errorf("testutil: reorg event sequence %d, want %d", event.Seq, seq)
+	}
+	if newTip := branch[len(branch)-1]; event.Tip == nil || event.Tip.Hash != newTip.Hash {
+		t.Errorf("testutil: reorg event tip is not the competing branch tip %s", newTip.Hash)
+	}
+	reorg := event.Reorg
+	if reorg == nil {
+		t.Fatalf("testutil: reorg event carries no reorg info")
+	}
+	if reorg.ForkHeight != forkHeight {
+		t.Errorf("testutil: reorg fork height %d, want %d", reorg.ForkHeight, forkHeight)
+	}
+
+	if len(reorg.OrphanedHashes) != len(orphaned) {
+		t.Errorf("testutil: reorg orphaned %d blocks, want %d", len(reorg.OrphanedHashes), len(orphaned))
+	} else {
+		for i, header := range orphaned {
+			if reorg.OrphanedHashes[i] != header.Hash {
+				t.Errorf("testutil: orphaned hash %d is %s, want %s", i, reorg.OrphanedHashes[i], header.Hash)
+			}
+		}
+	}
+
+	if len(reorg.NewBranch) != len(branch) {
+		t.Errorf("testutil: reorg new branch has %d blocks, want %d", len(reorg.NewBranch), len(branch))
+	} else {
+		for i, header := range branch {
+			if reorg.NewBranch[i].Hash != header.Hash {
+				t.Errorf("testutil: new branch block %d is %s, want %s", i, reorg.NewBranch[i].Hash, header.Hash)
+			}
+		}
+	}
+}
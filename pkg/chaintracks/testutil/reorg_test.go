@@ -0,0 +1,30 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+func TestReorg(t *testing.T) {
+	chain := GenerateChain(20, Options{})
+	cm, err := chaintracks.NewChainManager(t.Context(), "main", t.TempDir(), nil)
+	require.NoError(t, err)
+	require.NoError(t, cm.SetChainTip(t.Context(), chain.Headers))
+
+	event := Reorg(t, cm, 2, 3)
+	assert.Equal(t, uint32(17), event.Reorg.ForkHeight)
+	assert.Equal(t, uint32(20), cm.GetHeight(t.Context()))
+
+	// The same fork point again, which must not produce the branch just mined
+	event = Reorg(t, cm, 3, 4)
+	assert.Equal(t, uint32(17), event.Reorg.ForkHeight)
+	assert.Equal(t, uint32(21), cm.GetHeight(t.Context()))
+
+	orphan, err := cm.GetHeaderByHash(t.Context(), &chain.Headers[19].Hash)
+	require.NoError(t, err, "orphaned headers stay known")
+	assert.Equal(t, uint32(19), orphan.Height)
+}
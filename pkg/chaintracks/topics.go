@@ -0,0 +1,168 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+)
+
+const (
+	// resubscribeMinDelay is the first backoff after a topic subscription closes unexpectedly
+	resubscribeMinDelay = time.Second
+
+	// resubscribeMaxDelay caps the resubscription backoff
+	resubscribeMaxDelay = time.Minute
+
+	// activeTopicWindow is how recently a topic must have delivered a message to count as active
+	activeTopicWindow = time.Hour
+)
+
+// blockTopicVersion is one wire format of the block announcement topic
+type blockTopicVersion struct {
+	version string
+	decode  func(data []byte) (*BlockMessage, error)
+}
+
+// blockTopicVersions lists the supported topic versions, newest first
+// All versions are subscribed at once so peers still gossiping an older format keep being heard
+// while the network migrates. Add new versions at the front with their decoder.
+var blockTopicVersions = []blockTopicVersion{ //nolint:gochecknoglobals // Static protocol table
+	{version: "1.0.0", decode: decodeBlockMessageV1},
+}
+
+// BlockTopic returns the block announcement topic for a network and wire format version
+func BlockTopic(network, version string) string {
+	return fmt.Sprintf("teranode/bitcoin/%s/%snet-block", version, network)
+}
+
+// decodeBlockMessageV1 parses the 1.0.0 JSON block announcement
+func decodeBlockMessageV1(data []byte) (*BlockMessage, error) {
+	var blockMsg BlockMessage
+	if err := json.Unmarshal(data, &blockMsg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal block message: %w", err)
+	}
+	return &blockMsg, nil
+}
+
+// TopicStatus reports the subscription state of one block topic version
+type TopicStatus struct {
+	Topic        string    `json:"topic"`
+	Version      string    `json:"version"`
+	Messages     uint64    `json:"messages"`
+	LastMessage  time.Time `json:"lastMessage,omitempty"`
+	Resubscribes uint64    `json:"resubscribes"`
+	Preferred    bool      `json:"preferred"` // Newest version that delivered a message within the active window
+}
+
+// topicMessage is a message tagged with the topic version it arrived on
+type topicMessage struct {
+	version *blockTopicVersion
+	msg     p2p.Message
+}
+
+// topicTracker records per-version delivery statistics; the zero value is ready to use
+type topicTracker struct {
+	mu    sync.Mutex
+	stats map[string]*TopicStatus
+}
+
+func (t *topicTracker) status(topic, version string) *TopicStatus {
+	if t.stats == nil {
+		t.stats = make(map[string]*TopicStatus)
+	}
+	status, ok := t.stats[version]
+	if !ok {
+		status = &TopicStatus{Topic: topic, Version: version}
+		t.stats[version] = status
+	}
+	return status
+}
+
+func (t *topicTracker) subscribed(topic, version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status(topic, version)
+}
+
+func (t *topicTracker) received(topic, version string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := t.status(topic, version)
+	status.Messages++
+	status.LastMessage = at
+}
+
+func (t *topicTracker) resubscribed(topic, version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status(topic, version).Resubscribes++
+}
+
+// snapshot returns statuses in version order, marking the preferred version
+func (t *topicTracker) snapshot(now time.Time) []TopicStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]TopicStatus, 0, len(t.stats))
+	preferred := false
+	for _, v := range blockTopicVersions {
+		status, ok := t.stats[v.version]
+		if !ok {
+			continue
+		}
+		s := *status
+		if !preferred && !s.LastMessage.IsZero() && now.Sub(s.LastMessage) <= activeTopicWindow {
+			s.Preferred = true
+			preferred = true
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// subscribeTopic forwards messages from one topic version, resubscribing with backoff if the subscription closes
+func (cm *ChainManager) subscribeTopic(ctx context.Context, client p2p.Client, version *blockTopicVersion, out chan<- topicMessage) {
+	topic := BlockTopic(cm.network, version.version)
+	delay := resubscribeMinDelay
+
+	for {
+		cm.log().Info("Subscribing to P2P topic", "topic", topic)
+		cm.topics.subscribed(topic, version.version)
+
+		for msg := range client.Subscribe(topic) {
+			delay = resubscribeMinDelay
+			cm.topics.received(topic, version.version, time.Now())
+			select {
+			case out <- topicMessage{version: version, msg: msg}:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		cm.mu.RLock()
+		stopped := cm.p2pClient != client
+		cm.mu.RUnlock()
+		if stopped || ctx.Err() != nil {
+			return
+		}
+
+		cm.log().Warn("P2P subscription closed, resubscribing", "topic", topic, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, resubscribeMaxDelay)
+		cm.topics.resubscribed(topic, version.version)
+	}
+}
+
+// GetTopicStatus reports the block topic versions subscribed to and which one is preferred
+// Returns an empty slice if P2P has not been started
+func (cm *ChainManager) GetTopicStatus() []TopicStatus {
+	return cm.topics.snapshot(time.Now())
+}
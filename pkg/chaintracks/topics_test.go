@@ -0,0 +1,140 @@
+package chaintracks
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	p2p "github.com/bsv-blockchain/go-p2p-message-bus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeP2PClient hands out one scripted channel per Subscribe call
+type fakeP2PClient struct {
+	mu       sync.Mutex
+	channels []chan p2p.Message
+	calls    int
+}
+
+func (f *fakeP2PClient) Subscribe(_ string) <-chan p2p.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ch := f.channels[min(f.calls, len(f.channels)-1)]
+	f.calls++
+	return ch
+}
+
+func (f *fakeP2PClient) Publish(_ context.Context, _ string, _ []byte) error { return nil }
+func (f *fakeP2PClient) GetPeers() []p2p.PeerInfo                            { return nil }
+func (f *fakeP2PClient) GetID() string                                       { return "fake" }
+func (f *fakeP2PClient) Close() error                                        { return nil }
+
+func (f *fakeP2PClient) subscribeCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestBlockTopic(t *testing.T) {
+	assert.Equal(t, "teranode/bitcoin/1.0.0/mainnet-block", BlockTopic("main", "1.0.0"))
+	assert.Equal(t, "teranode/bitcoin/2.0.0/testnet-block", BlockTopic("test", "2.0.0"))
+}
+
+func TestDecodeBlockMessageV1(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expectErr bool
+	}{
+		{name: "ValidMessage", data: `{"Height":5,"Header":"00","PeerID":"p"}`},
+		{name: "InvalidJSON", data: `{"Height":`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := decodeBlockMessageV1([]byte(tt.data))
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint32(5), msg.Height)
+		})
+	}
+}
+
+func TestTopicTrackerPreferredVersion(t *testing.T) {
+	now := time.Now()
+	tracker := &topicTracker{}
+	topic := BlockTopic("main", "1.0.0")
+
+	tracker.subscribed(topic, "1.0.0")
+	statuses := tracker.snapshot(now)
+	require.Len(t, statuses, 1)
+	assert.False(t, statuses[0].Preferred, "no messages yet")
+
+	tracker.received(topic, "1.0.0", now)
+	statuses = tracker.snapshot(now)
+	assert.True(t, statuses[0].Preferred)
+	assert.Equal(t, uint64(1), statuses[0].Messages)
+
+	statuses = tracker.snapshot(now.Add(2 * activeTopicWindow))
+	assert.False(t, statuses[0].Preferred, "stale topic is not preferred")
+}
+
+func TestSubscribeTopicResubscribesWhenClosed(t *testing.T) {
+	first := make(chan p2p.Message, 1)
+	first <- p2p.Message{Data: []byte("one")}
+	close(first)
+	second := make(chan p2p.Message, 1)
+	second <- p2p.Message{Data: []byte("two")}
+
+	client := &fakeP2PClient{channels: []chan p2p.Message{first, second}}
+	cm := &ChainManager{network: "main", p2pClient: client}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	out := make(chan topicMessage, 2)
+	go cm.subscribeTopic(ctx, client, &blockTopicVersions[0], out)
+
+	for _, expected := range []string{"one", "two"} {
+		select {
+		case tm := <-out:
+			assert.Equal(t, expected, string(tm.msg.Data))
+			assert.Equal(t, "1.0.0", tm.version.version)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for message "+expected)
+		}
+	}
+
+	assert.Equal(t, 2, client.subscribeCalls())
+	statuses := cm.GetTopicStatus()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, uint64(2), statuses[0].Messages)
+	assert.Equal(t, uint64(1), statuses[0].Resubscribes)
+	assert.True(t, statuses[0].Preferred)
+}
+
+func TestSubscribeTopicStopsWhenClientReplaced(t *testing.T) {
+	closed := make(chan p2p.Message)
+	close(closed)
+
+	client := &fakeP2PClient{channels: []chan p2p.Message{closed}}
+	cm := &ChainManager{network: "main"}
+
+	done := make(chan struct{})
+	go func() {
+		cm.subscribeTopic(t.Context(), client, &blockTopicVersions[0], make(chan topicMessage))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "subscribeTopic did not return after the client was stopped")
+	}
+	assert.Equal(t, 1, client.subscribeCalls())
+}
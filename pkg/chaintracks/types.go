@@ -1,7 +1,10 @@
 package chaintracks
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/bsv-blockchain/go-sdk/block"
 	"github.com/bsv-blockchain/go-sdk/chainhash"
@@ -11,9 +14,45 @@ import (
 type BlockHeader struct {
 	*block.Header
 
-	Height    uint32         `json:"height"` // Block height in the chain
-	Hash      chainhash.Hash `json:"hash"`
-	ChainWork *big.Int       `json:"-"` // Cumulative chain work up to and including this block
+	Height     uint32         `json:"height"` // Block height in the chain
+	Hash       chainhash.Hash `json:"hash"`
+	ChainWork  *big.Int       `json:"-"`                    // Cumulative chain work up to and including this block, serialized as chainWork hex
+	MedianTime uint32         `json:"medianTime,omitempty"` // Median time past, see MedianTimePast; only set where requested
+}
+
+// blockHeaderJSON is the wire form of BlockHeader, adding chainWork as 64-character hex
+type blockHeaderJSON struct {
+	*blockHeaderFields
+	ChainWork string `json:"chainWork,omitempty"`
+}
+
+// blockHeaderFields has BlockHeader's fields without its JSON methods
+type blockHeaderFields BlockHeader
+
+// MarshalJSON adds chainWork to the header fields when it is known
+func (h BlockHeader) MarshalJSON() ([]byte, error) {
+	wire := blockHeaderJSON{blockHeaderFields: (*blockHeaderFields)(&h)}
+	if h.ChainWork != nil {
+		wire.ChainWork = ChainWorkToHex(h.ChainWork)
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON parses the header fields and the optional chainWork hex
+func (h *BlockHeader) UnmarshalJSON(data []byte) error {
+	wire := blockHeaderJSON{blockHeaderFields: (*blockHeaderFields)(h)}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	h.ChainWork = nil
+	if wire.ChainWork != "" {
+		work, err := ChainWorkFromHex(wire.ChainWork)
+		if err != nil {
+			return fmt.Errorf("invalid chainWork %q: %w", wire.ChainWork, err)
+		}
+		h.ChainWork = work
+	}
+	return nil
 }
 
 // CDNMetadata represents the JSON metadata file structure
@@ -22,6 +61,7 @@ type CDNMetadata struct {
 	JSONFilename   string         `json:"jsonFilename"`
 	HeadersPerFile int            `json:"headersPerFile"`
 	Files          []CDNFileEntry `json:"files"`
+	EventSeq       uint64         `json:"eventSeq,omitempty"` // Last chain event sequence number (local storage only)
 }
 
 // CDNFileEntry represents a single file entry in the metadata
@@ -50,8 +90,11 @@ type BlockMessage struct {
 }
 
 // PeerInfo contains information about a connected peer
+// LastSeen and LatencyMs come from the peer book and stay zero until the peer has been recorded.
 type PeerInfo struct {
-	ID    string
-	Name  string
-	Addrs []string
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Addrs     []string  `json:"addrs"`
+	LastSeen  time.Time `json:"lastSeen,omitzero"`
+	LatencyMs float64   `json:"latencyMs"` // Smoothed delay behind the first peer to announce each block
 }
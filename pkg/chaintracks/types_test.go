@@ -0,0 +1,61 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockHeaderJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		chainWork     *big.Int
+		expectedField string
+	}{
+		{name: "WithChainWork", chainWork: big.NewInt(0x1234), expectedField: `"chainWork":"` + ChainWorkToHex(big.NewInt(0x1234)) + `"`},
+		{name: "WithoutChainWork"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := &BlockHeader{
+				Header:    &block.Header{Version: 2, Nonce: 7},
+				Height:    5,
+				Hash:      chainhash.Hash{9},
+				ChainWork: tt.chainWork,
+			}
+
+			data, err := json.Marshal(header)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"height":5`)
+			assert.Contains(t, string(data), `"nonce":7`)
+			if tt.expectedField != "" {
+				assert.Contains(t, string(data), tt.expectedField)
+			} else {
+				assert.NotContains(t, string(data), "chainWork")
+			}
+
+			var decoded BlockHeader
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, header.Height, decoded.Height)
+			assert.Equal(t, header.Hash, decoded.Hash)
+			assert.Equal(t, header.Nonce, decoded.Nonce)
+			if tt.chainWork != nil {
+				require.NotNil(t, decoded.ChainWork)
+				assert.Equal(t, 0, tt.chainWork.Cmp(decoded.ChainWork))
+			} else {
+				assert.Nil(t, decoded.ChainWork)
+			}
+		})
+	}
+
+	t.Run("RejectsInvalidChainWork", func(t *testing.T) {
+		var decoded BlockHeader
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"height":1,"chainWork":"zz"}`), &decoded), ErrInvalidHeader)
+	})
+}
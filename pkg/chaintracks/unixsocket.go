@@ -0,0 +1,54 @@
+package chaintracks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unixScheme prefixes a server URL naming a unix domain socket, as in unix:///var/run/chaintracks.sock
+const unixScheme = "unix://"
+
+// unixSockets maps the placeholder hosts standing in for unix socket servers to their socket paths
+// Requests are built against http://<placeholder> like any other server URL, so failover and rebasing work
+// unchanged; only dialing differs.
+type unixSockets map[string]string
+
+// baseURL returns the base URL requests to a server are built against, registering a unix socket URL under a
+// new placeholder host
+func (s unixSockets) baseURL(serverURL string) string {
+	path, ok := strings.CutPrefix(serverURL, unixScheme)
+	if !ok {
+		return normalizeBaseURL(serverURL)
+	}
+	// .invalid is reserved (RFC 2606), so a placeholder never shadows a real host
+	host := "unix-" + strconv.Itoa(len(s)) + ".invalid"
+	s[host] = strings.TrimSuffix(path, "/")
+	return "http://" + host
+}
+
+// serverURL returns the URL a server was given as, undoing baseURL's placeholder for a unix socket
+func (s unixSockets) serverURL(baseURL string) string {
+	if path, ok := s[strings.TrimPrefix(baseURL, "http://")]; ok {
+		return unixScheme + path
+	}
+	return baseURL
+}
+
+// transport returns an HTTP transport dialing the socket of a placeholder host, and TCP for any other host
+func (s unixSockets) transport() *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // The default transport is always an *http.Transport
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			if path, ok := s[host]; ok {
+				return dialer.DialContext(ctx, "unix", path)
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return transport
+}
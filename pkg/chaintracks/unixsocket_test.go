@@ -0,0 +1,75 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// networkHandler answers /v2/network with network
+func networkHandler(network string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "value": network})
+	})
+}
+
+// serveUnixSocket serves handler on a unix socket in a temporary directory and returns its unix:// URL
+func serveUnixSocket(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "chaintracks.sock")
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	server := &http.Server{Handler: handler} //nolint:gosec // Test server
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+	return unixScheme + path
+}
+
+func TestClientUnixSocket(t *testing.T) {
+	socketURL := serveUnixSocket(t, networkHandler("main"))
+	tcp := httptest.NewServer(networkHandler("test"))
+	defer tcp.Close()
+
+	tests := []struct {
+		name      string
+		baseURL   string
+		fallbacks []string
+		expected  string
+	}{
+		{name: "Socket", baseURL: socketURL, expected: "main"},
+		{name: "TCPBesideSocket", baseURL: tcp.URL, fallbacks: []string{socketURL}, expected: "test"},
+		{name: "FailsOverToSocket", baseURL: unixScheme + filepath.Join(t.TempDir(), "missing.sock"), fallbacks: []string{socketURL}, expected: "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(tt.baseURL, tt.fallbacks...)
+			network, err := client.GetNetwork(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, network)
+			assert.Equal(t, tt.baseURL, client.Endpoints()[0].URL)
+		})
+	}
+}
+
+func TestUnixSocketsBaseURL(t *testing.T) {
+	sockets := unixSockets{}
+	assert.Equal(t, "http://example.com:3011", sockets.baseURL("example.com:3011/"))
+	assert.Equal(t, "http://unix-0.invalid", sockets.baseURL("unix:///var/run/chaintracks.sock"))
+	assert.Equal(t, "http://unix-1.invalid", sockets.baseURL("unix:///tmp/other.sock/"))
+	assert.Equal(t, unixSockets{"unix-0.invalid": "/var/run/chaintracks.sock", "unix-1.invalid": "/tmp/other.sock"}, sockets)
+	assert.Equal(t, "unix:///tmp/other.sock", sockets.serverURL("http://unix-1.invalid"))
+	assert.Equal(t, "http://example.com:3011", sockets.serverURL("http://example.com:3011"))
+}
@@ -0,0 +1,99 @@
+package chaintracks
+
+import (
+	"context"
+	"time"
+)
+
+// waitPollInterval is how often WaitForHeight and WaitForSync re-check a Chaintracks that cannot notify them
+const waitPollInterval = 100 * time.Millisecond
+
+// SyncReporter is implemented by Chaintracks that know whether they have caught up with the network
+type SyncReporter interface {
+	// IsSynced reports whether the chain has a tip and is not known to lag the network
+	IsSynced() bool
+}
+
+var (
+	_ SyncReporter = (*ChainManager)(nil)
+	_ SyncReporter = (*Client)(nil)
+	_ SyncReporter = (*Hybrid)(nil)
+)
+
+// IsSynced reports whether the chain has a tip, no bulk sync is running and it is not behind the network
+// Heights no peer or bootstrap node has announced yet cannot be known, so a fresh ChainManager with headers but
+// no network contact counts as synced.
+func (cm *ChainManager) IsSynced() bool {
+	return cm.tip.Load() != nil && !cm.SyncStatus().Active && cm.GetLagStatus().State != SyncStateBehind
+}
+
+// IsSynced reports whether the SSE stream has delivered a tip; the server keeps its own chain in sync
+func (cc *Client) IsSynced() bool {
+	return cc.GetTip(context.Background()) != nil
+}
+
+// IsSynced reports whether either source is synced
+func (h *Hybrid) IsSynced() bool {
+	return isSynced(h.primary) || isSynced(h.secondary)
+}
+
+// isSynced reports whether ct is synced, treating one that is not a SyncReporter as synced once it has a tip
+func isSynced(ct Chaintracks) bool {
+	if reporter, ok := ct.(SyncReporter); ok {
+		return reporter.IsSynced()
+	}
+	return ct.GetTip(context.Background()) != nil
+}
+
+// WaitForHeight blocks until the main chain of ct reaches height and returns the header there
+// Returns ctx.Err() if ctx ends first. A Client must be started for its height to advance.
+func WaitForHeight(ctx context.Context, ct Chaintracks, height uint32) (*BlockHeader, error) {
+	var header *BlockHeader
+	err := waitFor(ctx, ct, func() bool {
+		if tip := ct.GetTip(ctx); tip == nil || tip.Height < height {
+			return false
+		}
+		var err error
+		header, err = ct.GetHeaderByHeight(ctx, height)
+		return err == nil
+	})
+	return header, err
+}
+
+// WaitForSync blocks until ct reports itself synced, see SyncReporter, and returns its tip
+// Returns ctx.Err() if ctx ends first.
+func WaitForSync(ctx context.Context, ct Chaintracks) (*BlockHeader, error) {
+	if err := waitFor(ctx, ct, func() bool { return isSynced(ct) }); err != nil {
+		return nil, err
+	}
+	return ct.GetTip(ctx), nil
+}
+
+// waitFor checks ready each time ct may have changed until it reports true or ctx ends
+// A ChainManager wakes the wait on every chain event; anything else is polled every waitPollInterval.
+func waitFor(ctx context.Context, ct Chaintracks, ready func() bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var events <-chan *ChainEvent
+	if cm, ok := ct.(*ChainManager); ok {
+		events = cm.SubscribeEvents(ctx)
+	}
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if ready() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package chaintracks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForHeight(t *testing.T) {
+	t.Run("AlreadyReached", func(t *testing.T) {
+		header, err := WaitForHeight(t.Context(), newSubscriptionChainManager(t), 1)
+		require.NoError(t, err)
+		assert.Equal(t, testHeader(1, 1).Hash, header.Hash)
+	})
+
+	t.Run("WakesOnNewTip", func(t *testing.T) {
+		cm := newSubscriptionChainManager(t)
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = cm.SetChainTip(context.Background(), []*BlockHeader{testHeader(3, 1), testHeader(4, 1)})
+		}()
+
+		header, err := WaitForHeight(t.Context(), cm, 3)
+		require.NoError(t, err)
+		assert.Equal(t, testHeader(3, 1).Hash, header.Hash)
+	})
+
+	t.Run("PollsOtherImplementations", func(t *testing.T) {
+		source := newHybridSource(2)
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = source.SetChainTip(context.Background(), []*BlockHeader{testHeader(2, 3)})
+		}()
+
+		header, err := WaitForHeight(t.Context(), source, 2)
+		require.NoError(t, err)
+		assert.Equal(t, testHeader(2, 3).Hash, header.Hash)
+	})
+
+	t.Run("ContextEnds", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		header, err := WaitForHeight(ctx, newSubscriptionChainManager(t), 10)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, header)
+	})
+}
+
+func TestWaitForSync(t *testing.T) {
+	t.Run("WaitsForTip", func(t *testing.T) {
+		cm := &ChainManager{byHash: make(map[chainhash.Hash]*BlockHeader)}
+		assert.False(t, cm.IsSynced())
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = cm.SetChainTip(context.Background(), []*BlockHeader{testHeader(0, 1)})
+		}()
+
+		tip, err := WaitForSync(t.Context(), cm)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(0), tip.Height)
+	})
+
+	t.Run("WaitsWhileBehind", func(t *testing.T) {
+		cm := newSubscriptionChainManager(t)
+		cm.ObserveNetworkHeight(10)
+		assert.False(t, cm.IsSynced())
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = cm.SetChainTip(context.Background(), []*BlockHeader{testHeader(3, 1), testHeader(4, 1), testHeader(5, 1), testHeader(6, 1), testHeader(7, 1)})
+		}()
+
+		tip, err := WaitForSync(t.Context(), cm)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(7), tip.Height, "synced once within the lag threshold")
+	})
+
+	t.Run("Hybrid", func(t *testing.T) {
+		local := newHybridSource(0)
+		h := NewHybrid(local, newHybridSource(3), HybridConfig{})
+		assert.True(t, h.IsSynced(), "the remote source is synced")
+	})
+
+	t.Run("ContextEnds", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := WaitForSync(ctx, NewClient("http://127.0.0.1:0"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
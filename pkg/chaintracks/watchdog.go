@@ -0,0 +1,156 @@
+package chaintracks
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+const (
+	defaultWatchdogInterval = 5 * time.Minute
+	defaultWatchdogSamples  = 3
+)
+
+// ReferenceSource provides block hashes from an independent node
+// It is only ever used to cross-check local state, never as a sync source
+type ReferenceSource interface {
+	// HashAtHeight returns the main chain block hash at the given height
+	// Returns ErrHeaderNotFound if the reference does not have the height yet
+	HashAtHeight(ctx context.Context, height uint32) (chainhash.Hash, error)
+}
+
+// Divergence describes a height where the local chain disagrees with the reference
+type Divergence struct {
+	Height        uint32         `json:"height"`
+	LocalHash     chainhash.Hash `json:"localHash"`
+	ReferenceHash chainhash.Hash `json:"referenceHash"`
+	IsTip         bool           `json:"isTip"`
+	DetectedAt    time.Time      `json:"detectedAt"`
+}
+
+// WatchdogConfig configures a DivergenceWatchdog
+type WatchdogConfig struct {
+	Interval     time.Duration    // Time between checks (default 5m)
+	Samples      int              // Random historical heights checked per run (default 3)
+	OnDivergence func(Divergence) // Optional alert hook, called once per divergent height
+	Logger       Logger           // Defaults to the package default logger
+}
+
+// DivergenceWatchdog periodically compares the local chain against a reference node
+type DivergenceWatchdog struct {
+	local     Chaintracks
+	reference ReferenceSource
+	config    WatchdogConfig
+}
+
+// NewDivergenceWatchdog creates a watchdog comparing local against reference
+func NewDivergenceWatchdog(local Chaintracks, reference ReferenceSource, config WatchdogConfig) *DivergenceWatchdog {
+	if config.Interval <= 0 {
+		config.Interval = defaultWatchdogInterval
+	}
+	if config.Samples < 0 {
+		config.Samples = 0
+	} else if config.Samples == 0 {
+		config.Samples = defaultWatchdogSamples
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &DivergenceWatchdog{
+		local:     local,
+		reference: reference,
+		config:    config,
+	}
+}
+
+// Run performs checks every Interval until the context is cancelled
+func (w *DivergenceWatchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Check(ctx); err != nil {
+				w.config.Logger.Error("Watchdog check failed", "error", err)
+			}
+		}
+	}
+}
+
+// Check compares the local tip and a few random historical heights against the reference
+// Heights the reference does not know about yet are skipped
+func (w *DivergenceWatchdog) Check(ctx context.Context) ([]Divergence, error) {
+	tip := w.local.GetTip(ctx)
+	if tip == nil {
+		return nil, nil
+	}
+
+	heights := make([]uint32, 0, w.config.Samples+1)
+	heights = append(heights, tip.Height)
+	for i := 0; i < w.config.Samples && tip.Height > 0; i++ {
+		heights = append(heights, rand.Uint32N(tip.Height)) //nolint:gosec // Sampling does not need crypto randomness
+	}
+
+	var divergences []Divergence
+	for _, height := range heights {
+		local, err := w.local.GetHeaderByHeight(ctx, height)
+		if err != nil {
+			continue
+		}
+
+		refHash, err := w.reference.HashAtHeight(ctx, height)
+		if err != nil {
+			if height == tip.Height {
+				// Reference is most likely behind us, nothing to compare yet
+				continue
+			}
+			return divergences, fmt.Errorf("failed to query reference at height %d: %w", height, err)
+		}
+
+		if refHash.IsEqual(&local.Hash) {
+			continue
+		}
+
+		d := Divergence{
+			Height:        height,
+			LocalHash:     local.Hash,
+			ReferenceHash: refHash,
+			IsTip:         height == tip.Height,
+			DetectedAt:    time.Now(),
+		}
+		divergences = append(divergences, d)
+
+		w.config.Logger.Warn("Chain divergence", "height", height, "local", local.Hash, "reference", refHash, "tip", d.IsTip)
+		if w.config.OnDivergence != nil {
+			w.config.OnDivergence(d)
+		}
+	}
+
+	return divergences, nil
+}
+
+// chaintracksReference adapts any Chaintracks (usually a remote Client) as a ReferenceSource
+type chaintracksReference struct {
+	ct Chaintracks
+}
+
+// NewChaintracksReference uses another chaintracks instance as the reference
+func NewChaintracksReference(ct Chaintracks) ReferenceSource {
+	return &chaintracksReference{ct: ct}
+}
+
+// HashAtHeight implements ReferenceSource
+func (r *chaintracksReference) HashAtHeight(ctx context.Context, height uint32) (chainhash.Hash, error) {
+	header, err := r.ct.GetHeaderByHeight(ctx, height)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	return header.Hash, nil
+}
@@ -0,0 +1,157 @@
+package chaintracks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapReference is a ReferenceSource backed by a height → hash map
+type mapReference map[uint32]chainhash.Hash
+
+func (m mapReference) HashAtHeight(_ context.Context, height uint32) (chainhash.Hash, error) {
+	hash, ok := m[height]
+	if !ok {
+		return chainhash.Hash{}, ErrHeaderNotFound
+	}
+	return hash, nil
+}
+
+// newLinearChainManager builds a ChainManager with count main chain headers whose hash is {height+1}
+func newLinearChainManager(count int) *ChainManager {
+	cm := &ChainManager{
+		byHash: make(map[chainhash.Hash]*BlockHeader),
+	}
+	for i := 0; i < count; i++ {
+		hash := chainhash.Hash{byte(i + 1)}
+		header := &BlockHeader{Header: &block.Header{}, Height: uint32(i), Hash: hash} //nolint:gosec // Test data
+		cm.byHeight = append(cm.byHeight, hash)
+		cm.byHash[hash] = header
+		cm.tip.Store(header)
+	}
+	return cm
+}
+
+func TestDivergenceWatchdogCheck(t *testing.T) {
+	tests := []struct {
+		name              string
+		reference         mapReference
+		expectDivergences int
+		expectTip         bool
+		expectError       bool
+	}{
+		{
+			name:              "NoDivergenceWhenReferenceMatches",
+			reference:         mapReference{0: {1}, 1: {2}, 2: {3}, 3: {4}},
+			expectDivergences: 0,
+		},
+		{
+			name:              "DetectsTipDivergence",
+			reference:         mapReference{0: {1}, 1: {2}, 2: {3}, 3: {99}},
+			expectDivergences: 1,
+			expectTip:         true,
+		},
+		{
+			name:              "SkipsTipWhenReferenceIsBehind",
+			reference:         mapReference{0: {1}, 1: {2}, 2: {3}},
+			expectDivergences: 0,
+		},
+		{
+			name:        "ReturnsErrorWhenHistoricalHeightMissing",
+			reference:   mapReference{3: {4}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newLinearChainManager(4)
+
+			var alerts []Divergence
+			w := NewDivergenceWatchdog(cm, tt.reference, WatchdogConfig{
+				Samples:      5,
+				OnDivergence: func(d Divergence) { alerts = append(alerts, d) },
+			})
+
+			divergences, err := w.Check(t.Context())
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, divergences, tt.expectDivergences)
+			assert.Len(t, alerts, tt.expectDivergences)
+			if tt.expectTip {
+				assert.True(t, divergences[0].IsTip)
+				assert.Equal(t, uint32(3), divergences[0].Height)
+				assert.Equal(t, chainhash.Hash{99}, divergences[0].ReferenceHash)
+			}
+		})
+	}
+}
+
+func TestDivergenceWatchdogCheckEmptyChain(t *testing.T) {
+	w := NewDivergenceWatchdog(&ChainManager{}, mapReference{}, WatchdogConfig{})
+
+	divergences, err := w.Check(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, divergences)
+}
+
+func TestWhatsOnChainReferenceHashAtHeight(t *testing.T) {
+	expected := chainhash.Hash{7}
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedError error
+	}{
+		{
+			name: "ReturnsHashForValidResponse",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/block/height/100", r.URL.Path)
+				assert.Equal(t, "secret", r.Header.Get("Authorization"))
+				_, _ = w.Write([]byte(`{"hash":"` + expected.String() + `"}`))
+			},
+		},
+		{
+			name: "ReturnsNotFoundFor404",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedError: ErrHeaderNotFound,
+		},
+		{
+			name: "ReturnsErrorForServerError",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedError: ErrServerRequestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			ref := NewWhatsOnChainReference("main", "secret")
+			ref.baseURL = server.URL
+
+			hash, err := ref.HashAtHeight(t.Context(), 100)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, expected, hash)
+		})
+	}
+}
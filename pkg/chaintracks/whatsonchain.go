@@ -0,0 +1,310 @@
+package chaintracks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+const (
+	whatsOnChainBaseURL                = "https://api.whatsonchain.com/v1/bsv"
+	defaultWhatsOnChainPollInterval    = time.Minute
+	defaultWhatsOnChainRequestInterval = 350 * time.Millisecond // Stays under the free tier's three requests a second
+	whatsOnChainBatch                  = 100                    // Headers fetched before they are linked into the chain
+	whatsOnChainMaxRewind              = 100                    // Blocks searched back for the fork point of a reorg
+)
+
+// WhatsOnChainReference queries the WhatsOnChain public API
+type WhatsOnChainReference struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewWhatsOnChainReference creates a WhatsOnChain reference for the given network ("main" or "test")
+func NewWhatsOnChainReference(network, apiKey string) *WhatsOnChainReference {
+	return &WhatsOnChainReference{
+		baseURL:    fmt.Sprintf("%s/%s", whatsOnChainBaseURL, network),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// HashAtHeight implements ReferenceSource
+func (r *WhatsOnChainReference) HashAtHeight(ctx context.Context, height uint32) (chainhash.Hash, error) {
+	b, err := r.blockAtHeight(ctx, height)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+
+	hash, err := chainhash.NewHashFromHex(b.Hash)
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("failed to parse hash: %w", err)
+	}
+
+	return *hash, nil
+}
+
+// whatsOnChainBlock is the part of a WhatsOnChain block response that makes up its header
+type whatsOnChainBlock struct {
+	Hash              string `json:"hash"`
+	Version           int32  `json:"version"`
+	MerkleRoot        string `json:"merkleroot"`
+	Time              uint32 `json:"time"`
+	Nonce             uint32 `json:"nonce"`
+	Bits              string `json:"bits"`
+	PreviousBlockHash string `json:"previousblockhash"`
+}
+
+// header rebuilds the block header, checking it hashes to the reported hash
+func (b *whatsOnChainBlock) header() (*block.Header, error) {
+	header := &block.Header{Version: b.Version, Timestamp: b.Time, Nonce: b.Nonce}
+
+	bits, err := strconv.ParseUint(b.Bits, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bits %q: %w", ErrInvalidHeader, b.Bits, err)
+	}
+	header.Bits = uint32(bits)
+
+	merkleRoot, err := chainhash.NewHashFromHex(b.MerkleRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: merkle root: %w", ErrInvalidHeader, err)
+	}
+	header.MerkleRoot = *merkleRoot
+
+	if b.PreviousBlockHash != "" { // Absent for genesis
+		prevHash, err := chainhash.NewHashFromHex(b.PreviousBlockHash)
+		if err != nil {
+			return nil, fmt.Errorf("%w: previous block hash: %w", ErrInvalidHeader, err)
+		}
+		header.PrevHash = *prevHash
+	}
+
+	if hash := header.Hash(); hash.String() != b.Hash {
+		return nil, fmt.Errorf("%w: fields hash to %s, reported %s", ErrInvalidHeader, hash, b.Hash)
+	}
+	return header, nil
+}
+
+// blockAtHeight fetches the main chain block at height
+// Returns ErrHeaderNotFound if WhatsOnChain does not have the height yet.
+func (r *WhatsOnChainReference) blockAtHeight(ctx context.Context, height uint32) (*whatsOnChainBlock, error) {
+	var b whatsOnChainBlock
+	if err := r.get(ctx, fmt.Sprintf("/block/height/%d", height), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// chainHeight fetches the height of WhatsOnChain's tip
+func (r *WhatsOnChainReference) chainHeight(ctx context.Context) (uint32, error) {
+	var info struct {
+		Blocks uint32 `json:"blocks"`
+	}
+	if err := r.get(ctx, "/chain/info", &info); err != nil {
+		return 0, err
+	}
+	return info.Blocks, nil
+}
+
+// get decodes the JSON response to an API request into v
+func (r *WhatsOnChainReference) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", r.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", r.apiKey)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrHeaderNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d", ErrServerRequestFailed, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// WhatsOnChainSyncConfig configures a WhatsOnChainSync
+type WhatsOnChainSyncConfig struct {
+	APIKey          string        // Optional WhatsOnChain API key
+	PollInterval    time.Duration // Time between polls for new blocks (default 1m)
+	RequestInterval time.Duration // Minimum gap between API requests (default 350ms, the free tier's limit)
+	Logger          Logger        // Defaults to the package default logger
+}
+
+// WhatsOnChainSync follows the chain by polling the WhatsOnChain API, for deployments with nothing but
+// internet access
+// It is a fallback: polls are skipped while the ChainManager has P2P peers. Each poll finds the highest local
+// block WhatsOnChain agrees with and fetches the headers above it one block at a time, so a reorg is followed.
+// Headers must link and carry valid proof of work. Only main and test are served by WhatsOnChain. The
+// ChainManager must already hold at least the genesis header.
+type WhatsOnChainSync struct {
+	cm           *ChainManager
+	api          *WhatsOnChainReference
+	powLimitBits uint32
+	config       WhatsOnChainSyncConfig
+	next         time.Time // Earliest time for the next API request
+}
+
+// NewWhatsOnChainSync creates a WhatsOnChainSync feeding cm
+func NewWhatsOnChainSync(cm *ChainManager, config WhatsOnChainSyncConfig) (*WhatsOnChainSync, error) {
+	if cm.network != "main" && cm.network != "test" {
+		return nil, fmt.Errorf("%w: WhatsOnChain does not serve network %q", ErrInvalidConfig, cm.network)
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultWhatsOnChainPollInterval
+	}
+	if config.RequestInterval <= 0 {
+		config.RequestInterval = defaultWhatsOnChainRequestInterval
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &WhatsOnChainSync{
+		cm:           cm,
+		api:          NewWhatsOnChainReference(cm.network, config.APIKey),
+		powLimitBits: DefaultsForNetwork(cm.network).PowLimitBits,
+		config:       config,
+	}, nil
+}
+
+// Run polls every PollInterval until ctx is cancelled, skipping polls while P2P peers are connected
+func (s *WhatsOnChainSync) Run(ctx context.Context) {
+	for {
+		if s.cm.PeerCount() == 0 {
+			if err := s.Poll(ctx); err != nil && ctx.Err() == nil {
+				s.config.Logger.Warn("WhatsOnChain sync failed", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.config.PollInterval):
+		}
+	}
+}
+
+// Poll catches the local chain up with WhatsOnChain's tip
+func (s *WhatsOnChainSync) Poll(ctx context.Context) error {
+	tip := s.cm.GetTip(ctx)
+	if tip == nil {
+		return fmt.Errorf("%w: chain has no headers to extend", ErrHeaderNotFound)
+	}
+
+	if err := s.pace(ctx); err != nil {
+		return err
+	}
+	remote, err := s.api.chainHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get WhatsOnChain tip: %w", err)
+	}
+
+	height, err := s.forkPoint(ctx, min(tip.Height, remote))
+	if err != nil {
+		return err
+	}
+	if remote <= height {
+		return nil
+	}
+
+	syncing := remote > tip.Height+1
+	if syncing {
+		s.cm.beginSync(ctx, "whatsonchain", remote)
+	}
+	err = s.fetchFrom(ctx, height+1, remote, syncing)
+	if syncing {
+		s.cm.endSync(ctx, err)
+	}
+	if err == nil && syncing {
+		s.config.Logger.Info("WhatsOnChain sync caught up", "height", s.cm.GetHeight(ctx))
+	}
+	return err
+}
+
+// forkPoint returns the highest main-chain height at or below height where WhatsOnChain has the same block
+func (s *WhatsOnChainSync) forkPoint(ctx context.Context, height uint32) (uint32, error) {
+	for rewind := 0; rewind <= whatsOnChainMaxRewind; rewind++ {
+		local, err := s.cm.GetHeaderByHeight(ctx, height)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.pace(ctx); err != nil {
+			return 0, err
+		}
+		remote, err := s.api.HashAtHeight(ctx, height)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get WhatsOnChain block %d: %w", height, err)
+		}
+		if remote == local.Hash || height == 0 {
+			return height, nil
+		}
+		height--
+	}
+	return 0, fmt.Errorf("%w: no common block with WhatsOnChain in the last %d", ErrCommonAncestorNotFound, whatsOnChainMaxRewind)
+}
+
+// fetchFrom fetches the headers from height start to end, linking them into the chain in batches
+func (s *WhatsOnChainSync) fetchFrom(ctx context.Context, start, end uint32, syncing bool) error {
+	batch := make([]*block.Header, 0, whatsOnChainBatch)
+	for height := start; height <= end; height++ {
+		if err := s.pace(ctx); err != nil {
+			return err
+		}
+		b, err := s.api.blockAtHeight(ctx, height)
+		if err != nil {
+			return fmt.Errorf("failed to get WhatsOnChain block %d: %w", height, err)
+		}
+		header, err := b.header()
+		if err != nil {
+			return fmt.Errorf("WhatsOnChain block %d: %w", height, err)
+		}
+		batch = append(batch, header)
+
+		if len(batch) < whatsOnChainBatch && height < end {
+			continue
+		}
+		if err := s.cm.connectHeaders(ctx, batch, s.powLimitBits); err != nil {
+			return fmt.Errorf("rejected headers from WhatsOnChain: %w", err)
+		}
+		if syncing {
+			s.cm.advanceSync(ctx, len(batch), nil)
+		}
+		batch = make([]*block.Header, 0, whatsOnChainBatch)
+	}
+	return nil
+}
+
+// pace waits until the next API request is allowed
+func (s *WhatsOnChainSync) pace(ctx context.Context) error {
+	if wait := time.Until(s.next); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	s.next = time.Now().Add(s.config.RequestInterval)
+	return nil
+}
@@ -0,0 +1,130 @@
+package chaintracks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWhatsOnChainServer serves chain info and blocks by height for a chain, genesis first
+func newWhatsOnChainServer(t *testing.T, chain []*block.Header, tamper func(map[string]any)) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chain/info", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"chain":"test","blocks":%d}`, len(chain)-1)
+	})
+	mux.HandleFunc("/block/height/{height}", func(w http.ResponseWriter, r *http.Request) {
+		var height int
+		if _, err := fmt.Sscan(r.PathValue("height"), &height); err != nil || height >= len(chain) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		header := chain[height]
+		b := map[string]any{
+			"hash":       header.Hash().String(),
+			"height":     height,
+			"version":    header.Version,
+			"merkleroot": header.MerkleRoot.String(),
+			"time":       header.Timestamp,
+			"nonce":      header.Nonce,
+			"bits":       fmt.Sprintf("%08x", header.Bits),
+		}
+		if height > 0 {
+			b["previousblockhash"] = header.PrevHash.String()
+		}
+		if tamper != nil {
+			tamper(b)
+		}
+		_ = json.NewEncoder(w).Encode(b)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestWhatsOnChainSync returns a WhatsOnChainSync for cm polling server without pacing
+func newTestWhatsOnChainSync(t *testing.T, cm *ChainManager, server *httptest.Server) *WhatsOnChainSync {
+	t.Helper()
+
+	cm.network = "test"
+	s, err := NewWhatsOnChainSync(cm, WhatsOnChainSyncConfig{RequestInterval: 1})
+	require.NoError(t, err)
+	s.api.baseURL = server.URL
+	s.powLimitBits = regtestPowLimitBits
+	return s
+}
+
+func TestWhatsOnChainSyncPoll(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 250, 0)
+	fork := mineRegtestChain(t, chain[2].Hash(), 5, 1)
+
+	tests := []struct {
+		name          string
+		local         []*block.Header
+		remote        []*block.Header
+		tamper        func(map[string]any)
+		expectedTip   *block.Header
+		expectedError error
+	}{
+		{
+			name:        "CatchesUpInBatches",
+			local:       chain[:5],
+			remote:      chain,
+			expectedTip: chain[249],
+		},
+		{
+			name:        "AlreadyCaughtUp",
+			local:       chain[:5],
+			remote:      chain[:5],
+			expectedTip: chain[4],
+		},
+		{
+			name:        "FollowsReorg",
+			local:       chain[:5],
+			remote:      append(chain[:3:3], fork...),
+			expectedTip: fork[4],
+		},
+		{
+			name:          "RejectsMismatchedHeader",
+			local:         chain[:5],
+			remote:        chain[:10],
+			tamper:        func(b map[string]any) { b["nonce"] = 0 },
+			expectedError: ErrInvalidHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newWireChainManager(t, genesis, tt.local)
+			server := newWhatsOnChainServer(t, append([]*block.Header{genesis}, tt.remote...), tt.tamper)
+			s := newTestWhatsOnChainSync(t, cm, server)
+
+			err := s.Poll(t.Context())
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTip.Hash(), cm.GetTip(t.Context()).Hash)
+			assert.False(t, cm.SyncStatus().Active)
+		})
+	}
+}
+
+func TestNewWhatsOnChainSyncValidatesNetwork(t *testing.T) {
+	_, err := NewWhatsOnChainSync(&ChainManager{network: "stn"}, WhatsOnChainSyncConfig{})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	s, err := NewWhatsOnChainSync(&ChainManager{network: "main"}, WhatsOnChainSyncConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultWhatsOnChainPollInterval, s.config.PollInterval)
+	assert.Equal(t, defaultWhatsOnChainRequestInterval, s.config.RequestInterval)
+}
@@ -0,0 +1,208 @@
+package chaintracks
+
+import (
+	"fmt"
+	"maps"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// minMemoryWindow is the smallest memory window accepted, far deeper than any reorg the chain follows
+const minMemoryWindow = 1000
+
+// SetMemoryWindow keeps only the n most recent main-chain headers in memory, 0 keeps every header
+// Older headers are dropped from memory once written to the local header files and read back from them when
+// looked up, which costs one file read by height or hash. An evicted header is not free: its hash stays in the
+// height index, its chainwork in storedWork and its height in storedHeights, about 125 bytes against about 330
+// for a header in memory, so memory still grows with the chain, at under half the rate. Values below
+// minMemoryWindow are raised to it. Headers already evicted stay on disk when the window is widened, and
+// nothing is evicted without a storage path. NewWindowedChainManager also keeps the startup restore within it.
+func (cm *ChainManager) SetMemoryWindow(n uint32) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	cm.memoryWindow = windowSize(n)
+	cm.evictBelowWindow()
+
+	// Maps never shrink, so copy what is left after evicting the bulk of the chain
+	byHash := make(map[chainhash.Hash]*BlockHeader, len(cm.byHash))
+	maps.Copy(byHash, cm.byHash)
+	cm.byHash = byHash
+}
+
+// windowSize raises a nonzero memory window to minMemoryWindow
+func windowSize(n uint32) uint32 {
+	if n == 0 {
+		return 0
+	}
+	return max(n, minMemoryWindow)
+}
+
+// evictBelowWindow drops main-chain headers that fell out of the memory window (must be called with lock held)
+// Headers of a branch still being written stay, so only headers already on disk leave memory. Each evicted
+// header leaves its height in storedHeights and its 32-byte chainwork in storedWork, so lookups by hash and
+// chainwork below the window stay lookups; both grow by one entry per evicted header.
+func (cm *ChainManager) evictBelowWindow() {
+	tip := cm.tip.Load()
+	if cm.memoryWindow == 0 || cm.localStoragePath == "" || tip == nil || tip.Height < cm.memoryWindow {
+		return
+	}
+
+	floor := tip.Height - cm.memoryWindow + 1
+	for height := range cm.unwritten {
+		floor = min(floor, height)
+	}
+	if cm.storedHeights == nil {
+		cm.storedHeights = make(map[chainhash.Hash]uint32)
+	}
+	for ; cm.windowFloor < floor; cm.windowFloor++ {
+		hash := cm.byHeight[cm.windowFloor]
+		header, ok := cm.byHash[hash]
+		if !ok {
+			break
+		}
+		var work [32]byte
+		chainWorkOf(header).FillBytes(work[:])
+		cm.storedWork = append(cm.storedWork, work)
+		cm.storedHeights[hash] = cm.windowFloor
+		delete(cm.byHash, hash)
+	}
+}
+
+// branchApplied records that a branch from height was applied and is not yet on disk (must be called with lock held)
+// A branch reaching below the memory window brings the headers it replaces back into memory first.
+func (cm *ChainManager) branchApplied(height uint32) {
+	if height < cm.windowFloor {
+		for h := height; h < cm.windowFloor; h++ {
+			delete(cm.storedHeights, cm.byHeight[h])
+		}
+		cm.windowFloor = height
+		cm.storedWork = cm.storedWork[:height]
+	}
+
+	if cm.localStoragePath == "" {
+		return
+	}
+	if cm.unwritten == nil {
+		cm.unwritten = make(map[uint32]int)
+	}
+	cm.unwritten[height]++
+}
+
+// branchWritten records that a branch from height reached disk and evicts the headers that allows (must be
+// called with lock held)
+func (cm *ChainManager) branchWritten(height uint32) {
+	if n := cm.unwritten[height]; n > 1 {
+		cm.unwritten[height] = n - 1
+	} else {
+		delete(cm.unwritten, height)
+	}
+	cm.evictBelowWindow()
+}
+
+// mainHeaderAt returns the main-chain header at height, reading it from disk when it is below the memory
+// window (must be called with lock held)
+func (cm *ChainManager) mainHeaderAt(height uint32) (*BlockHeader, bool) {
+	if uint64(height) >= uint64(len(cm.byHeight)) {
+		return nil, false
+	}
+	if header, ok := cm.byHash[cm.byHeight[height]]; ok {
+		return header, true
+	}
+	if height >= cm.windowFloor {
+		return nil, false
+	}
+
+	headers, err := cm.readStoredHeaders(height, 1)
+	if err != nil {
+		cm.log().Warn("Failed to read stored header", "height", height, "error", err)
+		return nil, false
+	}
+	return headers[0], true
+}
+
+// headerByHash looks a header up by hash, falling back to the main chain below the memory window
+// (must be called with lock held)
+func (cm *ChainManager) headerByHash(hash chainhash.Hash) (*BlockHeader, bool) {
+	if header, ok := cm.byHash[hash]; ok {
+		return header, true
+	}
+	height, ok := cm.storedHeights[hash]
+	if !ok || height >= cm.windowFloor || cm.byHeight[height] != hash {
+		return nil, false
+	}
+	return cm.mainHeaderAt(height)
+}
+
+// parentHeader returns the parent of a header, reading it from disk when it is a main-chain header below
+// the memory window (must be called with lock held)
+func (cm *ChainManager) parentHeader(header *BlockHeader) (*BlockHeader, bool) {
+	if parent, ok := cm.byHash[header.PrevHash]; ok {
+		return parent, true
+	}
+	if header.Height == 0 || uint64(header.Height) > uint64(len(cm.byHeight)) || cm.byHeight[header.Height-1] != header.PrevHash {
+		return nil, false
+	}
+	return cm.mainHeaderAt(header.Height - 1)
+}
+
+// readStoredHeaders reads count main-chain headers from height out of the local header files, which must all
+// be below the memory window (must be called with lock held)
+// Each header comes back with its ChainWork from storedWork, and every header must match the height index.
+func (cm *ChainManager) readStoredHeaders(height, count uint32) ([]*BlockHeader, error) {
+	if height+count > cm.windowFloor || int(height+count) > len(cm.storedWork) {
+		return nil, fmt.Errorf("%w: height %d is not stored below the memory window", ErrHeaderNotFound, height)
+	}
+
+	data := make([]byte, count*headerSize)
+	for start := height; start < height+count; {
+		end := min(height+count, (start/defaultHeadersPerFile+1)*defaultHeadersPerFile)
+		if err := cm.readStoredBytes(data[(start-height)*headerSize:(end-height)*headerSize], start); err != nil {
+			return nil, err
+		}
+		start = end
+	}
+
+	headers := make([]*BlockHeader, 0, count)
+	for i := height; i < height+count; i++ {
+		header, err := block.NewHeaderFromBytes(data[(i-height)*headerSize : (i-height+1)*headerSize])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stored header %d: %w", i, err)
+		}
+		headers = append(headers, &BlockHeader{Header: header, Height: i, Hash: cm.byHeight[i], ChainWork: cm.storedWorkAt(i)})
+	}
+	return headers, nil
+}
+
+// storedWorkAt returns the chainwork kept for a main-chain height below the memory window (must be called with lock held)
+func (cm *ChainManager) storedWorkAt(height uint32) *big.Int {
+	return new(big.Int).SetBytes(cm.storedWork[height][:])
+}
+
+// readStoredBytes fills dst with the raw main-chain headers from height, all within one local header file,
+// checking each against the height index (must be called with lock held)
+func (cm *ChainManager) readStoredBytes(dst []byte, height uint32) error {
+	fileName := fmt.Sprintf("%sNet_%d.headers", cm.network, height/defaultHeadersPerFile)
+	f, err := os.Open(filepath.Join(cm.localStoragePath, fileName)) //nolint:gosec // Path is constructed internally
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", fileName, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := f.ReadAt(dst, int64(height%defaultHeadersPerFile)*headerSize); err != nil {
+		return fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+	for i := 0; i < len(dst); i += headerSize {
+		h := height + uint32(i/headerSize) //nolint:gosec // Bounded by len(dst)
+		if chainhash.DoubleHashH(dst[i:i+headerSize]) != cm.byHeight[h] {
+			return fmt.Errorf("%w: %s header %d does not match the chain", ErrFileHashMismatch, fileName, h)
+		}
+	}
+	return nil
+}
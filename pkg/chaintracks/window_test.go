@@ -0,0 +1,211 @@
+package chaintracks
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStoredChainManager builds a chain of count mined headers on top of genesis, persisted to a temp directory
+// It returns the chain as it was before any window was set, genesis first.
+func newStoredChainManager(t *testing.T, count int) (*ChainManager, []*BlockHeader) {
+	t.Helper()
+
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	cm := &ChainManager{network: "stn", localStoragePath: t.TempDir(), byHash: make(map[chainhash.Hash]*BlockHeader)}
+	root := &BlockHeader{Header: genesis, Hash: genesis.Hash(), ChainWork: big.NewInt(1)}
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{root}))
+	require.NoError(t, cm.connectHeaders(t.Context(), mineRegtestChain(t, root.Hash, count, 0), 0))
+
+	chain := make([]*BlockHeader, count+1)
+	for i := range chain {
+		header, err := cm.GetHeaderByHeight(t.Context(), uint32(i)) //nolint:gosec // Test chain is small
+		require.NoError(t, err)
+		chain[i] = header
+	}
+	return cm, chain
+}
+
+func TestChainManagerMemoryWindow(t *testing.T) {
+	cm, chain := newStoredChainManager(t, 2500)
+	ctx := t.Context()
+
+	cm.SetMemoryWindow(minMemoryWindow)
+	tip := chain[len(chain)-1]
+	floor := tip.Height - minMemoryWindow + 1
+	assert.Len(t, cm.byHash, minMemoryWindow)
+	assert.Equal(t, floor, cm.windowFloor)
+
+	t.Run("GetHeaderByHeight", func(t *testing.T) {
+		for _, height := range []uint32{0, 1, 99, 100, 150, floor - 1, floor, tip.Height} {
+			header, err := cm.GetHeaderByHeight(ctx, height)
+			require.NoError(t, err, "height %d", height)
+			expected := chain[height]
+			assert.Equal(t, expected.Hash, header.Hash)
+			assert.Equal(t, expected.Height, header.Height)
+			assert.Equal(t, expected.Bytes(), header.Bytes())
+			assert.Equal(t, 0, expected.ChainWork.Cmp(header.ChainWork), "chainwork at %d", height)
+		}
+	})
+
+	t.Run("GetChainWork", func(t *testing.T) {
+		assert.Len(t, cm.storedWork, int(floor))
+		for _, height := range []uint32{0, 1, 99, 100, 150, floor - 1, floor, tip.Height} {
+			work, err := cm.GetChainWork(ctx, height)
+			require.NoError(t, err, "height %d", height)
+			assert.Equal(t, 0, chain[height].ChainWork.Cmp(work), "chainwork at %d", height)
+		}
+	})
+
+	t.Run("GetHeaderByHash", func(t *testing.T) {
+		header, err := cm.GetHeaderByHash(ctx, &chain[42].Hash)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(42), header.Height)
+
+		onMain, err := cm.IsOnMainChain(ctx, &chain[42].Hash)
+		require.NoError(t, err)
+		assert.True(t, onMain)
+
+		assert.Len(t, cm.storedHeights, int(floor))
+		_, err = cm.GetHeaderByHash(ctx, &chainhash.Hash{1})
+		assert.ErrorIs(t, err, ErrHeaderNotFound)
+	})
+
+	t.Run("AddHeadersOnEvictedParent", func(t *testing.T) {
+		side := mineRegtestChain(t, chain[100].Hash, 3, 1)
+		require.NoError(t, cm.connectHeaders(ctx, side, 0))
+		assert.Equal(t, tip.Hash, cm.GetTip(ctx).Hash, "a shorter side chain leaves the tip alone")
+
+		hash := side[2].Hash()
+		header, err := cm.GetHeaderByHash(ctx, &hash)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(103), header.Height)
+	})
+
+	t.Run("GetHeadersBackwardsCrossesWindow", func(t *testing.T) {
+		headers, err := cm.GetHeadersBackwards(ctx, &chain[floor+1].Hash, 5)
+		require.NoError(t, err)
+		require.Len(t, headers, 5)
+		for i, header := range headers {
+			assert.Equal(t, chain[floor+1-uint32(i)].Hash, header.Hash) //nolint:gosec // Test data
+		}
+	})
+
+	t.Run("ReadHeadersIntoCopiesFromDisk", func(t *testing.T) {
+		var buf, expected bytes.Buffer
+		n, err := cm.ReadHeadersInto(&buf, 0, uint32(len(chain))) //nolint:gosec // Test chain is small
+		require.NoError(t, err)
+		assert.Equal(t, uint32(len(chain)), n) //nolint:gosec // Test chain is small
+		for _, header := range chain {
+			expected.Write(header.Bytes())
+		}
+		assert.Equal(t, expected.Bytes(), buf.Bytes())
+	})
+
+	t.Run("SnapshotMainChain", func(t *testing.T) {
+		snapshot, err := cm.snapshotMainChain()
+		require.NoError(t, err)
+		require.Len(t, snapshot, len(chain))
+		assert.Equal(t, 0, chain[floor-1].ChainWork.Cmp(snapshot[floor-1].ChainWork))
+	})
+
+	t.Run("FloorFollowsTip", func(t *testing.T) {
+		require.NoError(t, cm.connectHeaders(ctx, mineRegtestChain(t, tip.Hash, 150, 0), 0))
+		assert.Equal(t, floor+150, cm.windowFloor)
+		assert.Len(t, cm.byHash, minMemoryWindow)
+
+		header, err := cm.GetHeaderByHeight(ctx, floor+100)
+		require.NoError(t, err)
+		assert.Equal(t, chain[floor+100].Hash, header.Hash)
+	})
+}
+
+func TestChainManagerMemoryWindowMinimum(t *testing.T) {
+	cm, _ := newStoredChainManager(t, 10)
+
+	cm.SetMemoryWindow(10)
+	assert.Equal(t, uint32(minMemoryWindow), cm.memoryWindow)
+	assert.Len(t, cm.byHash, 11, "a chain shorter than the window stays in memory")
+}
+
+func TestChainManagerMemoryWindowEvictsOnlyWritten(t *testing.T) {
+	cm, chain := newStoredChainManager(t, 1100)
+	ctx := t.Context()
+	cm.SetMemoryWindow(minMemoryWindow)
+	floor := cm.windowFloor
+
+	// A storage path that is a file makes every header write fail
+	storagePath := cm.localStoragePath
+	cm.localStoragePath = filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(cm.localStoragePath, nil, 0o600))
+
+	unwritten := mineRegtestChain(t, chain[len(chain)-1].Hash, 50, 0)
+	require.Error(t, cm.connectHeaders(ctx, unwritten, 0))
+	assert.Equal(t, floor, cm.windowFloor, "headers that never reached disk stay in memory")
+	assert.Len(t, cm.byHash, minMemoryWindow+50)
+
+	// Headers written before the failed branch leave memory again, the failed branch stays
+	cm.localStoragePath = storagePath
+	require.NoError(t, cm.connectHeaders(ctx, mineRegtestChain(t, unwritten[49].Hash(), 10, 0), 0))
+	assert.Equal(t, floor+60, cm.windowFloor)
+	for _, header := range unwritten {
+		assert.Contains(t, cm.byHash, header.Hash())
+	}
+}
+
+func TestChainManagerMemoryWindowInvalidate(t *testing.T) {
+	cm, chain := newStoredChainManager(t, 1100)
+	ctx := t.Context()
+	cm.SetMemoryWindow(minMemoryWindow)
+
+	require.NoError(t, cm.InvalidateBlock(ctx, &chain[42].Hash))
+	assert.Equal(t, chain[41].Hash, cm.GetTip(ctx).Hash)
+	assert.Equal(t, uint32(41), cm.windowFloor, "the rewind brings the new tip back into memory")
+	assert.Len(t, cm.storedWork, 41)
+
+	header, err := cm.GetHeaderByHash(ctx, &chain[40].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(40), header.Height)
+	_, err = cm.GetHeaderByHash(ctx, &chain[42].Hash)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+
+	require.ErrorIs(t, cm.connectHeaders(ctx, mineRegtestChain(t, chain[42].Hash, 1, 0), 0), ErrHeaderNotFound)
+	require.NoError(t, cm.connectHeaders(ctx, mineRegtestChain(t, chain[41].Hash, 5, 1), 0))
+	assert.Equal(t, uint32(46), cm.GetHeight(ctx))
+}
+
+func TestNewWindowedChainManager(t *testing.T) {
+	stored, chain := newStoredChainManager(t, 2500)
+	ctx := t.Context()
+	full, err := NewChainManager(ctx, "stn", stored.localStoragePath, nil)
+	require.NoError(t, err)
+
+	cm, err := NewWindowedChainManager(ctx, "stn", stored.localStoragePath, nil, 10)
+	require.NoError(t, err)
+	tip := chain[len(chain)-1]
+	floor := tip.Height - minMemoryWindow + 1
+	assert.Equal(t, uint32(minMemoryWindow), cm.memoryWindow)
+	assert.Equal(t, floor, cm.windowFloor, "headers below the window leave memory while the files load")
+	assert.Len(t, cm.byHash, minMemoryWindow)
+	assert.Len(t, cm.storedWork, int(floor))
+	assert.Equal(t, tip.Hash, cm.GetTip(ctx).Hash)
+
+	for _, height := range []uint32{0, 42, floor - 1, floor, tip.Height} {
+		header, err := cm.GetHeaderByHash(ctx, &chain[height].Hash)
+		require.NoError(t, err, "height %d", height)
+		assert.Equal(t, height, header.Height)
+		work, err := full.GetChainWork(ctx, height)
+		require.NoError(t, err)
+		assert.Equal(t, 0, work.Cmp(header.ChainWork), "chainwork at %d", height)
+	}
+
+	require.NoError(t, cm.connectHeaders(ctx, mineRegtestChain(t, tip.Hash, 10, 0), 0))
+	assert.Equal(t, floor+10, cm.windowFloor)
+}
@@ -0,0 +1,242 @@
+package chaintracks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+// Bitcoin P2P protocol constants
+const (
+	wireProtocolVersion = 70016
+	wireHeaderSize      = 24       // Magic, command, payload length and checksum
+	wireMaxPayload      = 32 << 20 // Larger messages are treated as a protocol violation
+	wireMaxHeaders      = 2000     // Most headers a node returns for one getheaders
+	wireInvBlock        = 2        // Inventory type of a block
+	wireDefaultAgent    = "/go-chaintracks/"
+)
+
+// Wire message commands
+const (
+	wireCmdVersion     = "version"
+	wireCmdVerack      = "verack"
+	wireCmdPing        = "ping"
+	wireCmdPong        = "pong"
+	wireCmdGetHeaders  = "getheaders"
+	wireCmdHeaders     = "headers"
+	wireCmdSendHeaders = "sendheaders"
+	wireCmdInv         = "inv"
+)
+
+// wireMessage is one framed Bitcoin P2P message
+type wireMessage struct {
+	command string
+	payload []byte
+}
+
+// writeWireMessage frames payload under command and writes it
+func writeWireMessage(w io.Writer, magic [4]byte, command string, payload []byte) error {
+	frame := make([]byte, wireHeaderSize, wireHeaderSize+len(payload))
+	copy(frame[:4], magic[:])
+	copy(frame[4:16], command)
+	binary.LittleEndian.PutUint32(frame[16:20], uint32(len(payload))) //nolint:gosec // Payloads are far below 4GB
+	copy(frame[20:24], chainhash.DoubleHashB(payload)[:4])
+	_, err := w.Write(append(frame, payload...))
+	return err
+}
+
+// readWireMessage reads the next message, checking its magic, size and checksum
+func readWireMessage(r io.Reader, magic [4]byte) (wireMessage, error) {
+	var header [wireHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return wireMessage{}, err
+	}
+	if !bytes.Equal(header[:4], magic[:]) {
+		return wireMessage{}, fmt.Errorf("%w: wrong network magic %x", ErrWireProtocol, header[:4])
+	}
+
+	length := binary.LittleEndian.Uint32(header[16:20])
+	if length > wireMaxPayload {
+		return wireMessage{}, fmt.Errorf("%w: %d byte payload", ErrWireProtocol, length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return wireMessage{}, err
+	}
+	if !bytes.Equal(header[20:24], chainhash.DoubleHashB(payload)[:4]) {
+		return wireMessage{}, fmt.Errorf("%w: bad checksum", ErrWireProtocol)
+	}
+
+	command := strings.TrimRight(string(header[4:16]), "\x00")
+	return wireMessage{command: command, payload: payload}, nil
+}
+
+// encodeVersion builds a version payload announcing no services and no transaction relay
+func encodeVersion(nonce uint64, userAgent string, startHeight uint32, now time.Time) []byte {
+	var addr [26]byte // Services, IPv6 address and port of an unspecified peer
+
+	b := binary.LittleEndian.AppendUint32(nil, wireProtocolVersion)
+	b = binary.LittleEndian.AppendUint64(b, 0)
+	b = binary.LittleEndian.AppendUint64(b, uint64(now.Unix())) //nolint:gosec // Current time is positive
+	b = append(b, addr[:]...)
+	b = append(b, addr[:]...)
+	b = binary.LittleEndian.AppendUint64(b, nonce)
+	b = appendVarInt(b, uint64(len(userAgent)))
+	b = append(b, userAgent...)
+	b = binary.LittleEndian.AppendUint32(b, startHeight)
+	return append(b, 0)
+}
+
+// wireVersion is the part of a peer's version message WireSync uses
+type wireVersion struct {
+	protocol    uint32
+	nonce       uint64
+	userAgent   string
+	startHeight uint32
+}
+
+// decodeVersion parses a version payload
+func decodeVersion(payload []byte) (wireVersion, error) {
+	r := bytes.NewReader(payload)
+	var v wireVersion
+	var fixed struct {
+		Protocol  uint32
+		Services  uint64
+		Timestamp int64
+		AddrRecv  [26]byte
+		AddrFrom  [26]byte
+		Nonce     uint64
+	}
+	if err := binary.Read(r, binary.LittleEndian, &fixed); err != nil {
+		return v, fmt.Errorf("%w: short version message", ErrWireProtocol)
+	}
+	v.protocol, v.nonce = fixed.Protocol, fixed.Nonce
+
+	length, err := readVarInt(r)
+	if err != nil || length > uint64(r.Len()) {
+		return v, fmt.Errorf("%w: bad user agent", ErrWireProtocol)
+	}
+	agent := make([]byte, length)
+	_, _ = r.Read(agent)
+	v.userAgent = string(agent)
+
+	if err := binary.Read(r, binary.LittleEndian, &v.startHeight); err != nil {
+		return v, fmt.Errorf("%w: short version message", ErrWireProtocol)
+	}
+	return v, nil
+}
+
+// encodeGetHeaders builds a getheaders payload asking for headers after the first locator hash the peer knows
+func encodeGetHeaders(locator []chainhash.Hash) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, wireProtocolVersion)
+	b = appendVarInt(b, uint64(len(locator)))
+	for _, hash := range locator {
+		b = append(b, hash[:]...)
+	}
+	var stop chainhash.Hash
+	return append(b, stop[:]...)
+}
+
+// decodeHeaders parses a headers payload; each header is followed by an always-zero transaction count
+func decodeHeaders(payload []byte) ([]*block.Header, error) {
+	r := bytes.NewReader(payload)
+	count, err := readVarInt(r)
+	if err != nil || count > wireMaxHeaders {
+		return nil, fmt.Errorf("%w: bad headers count", ErrWireProtocol)
+	}
+
+	headers := make([]*block.Header, 0, count)
+	raw := make([]byte, headerSize)
+	for range count {
+		if _, err := io.ReadFull(r, raw); err != nil {
+			return nil, fmt.Errorf("%w: truncated headers message", ErrWireProtocol)
+		}
+		header, err := block.NewHeaderFromBytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse header: %w", err)
+		}
+		if _, err := readVarInt(r); err != nil {
+			return nil, fmt.Errorf("%w: truncated headers message", ErrWireProtocol)
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
+
+// encodeHeaders builds a headers payload
+func encodeHeaders(headers []*block.Header) []byte {
+	b := appendVarInt(nil, uint64(len(headers)))
+	for _, header := range headers {
+		b = append(b, header.Bytes()...)
+		b = append(b, 0)
+	}
+	return b
+}
+
+// invHasBlock reports whether an inv payload announces a block
+func invHasBlock(payload []byte) bool {
+	r := bytes.NewReader(payload)
+	count, err := readVarInt(r)
+	if err != nil {
+		return false
+	}
+	var item struct {
+		Type uint32
+		Hash chainhash.Hash
+	}
+	for range count {
+		if binary.Read(r, binary.LittleEndian, &item) != nil {
+			return false
+		}
+		if item.Type == wireInvBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// appendVarInt appends n in the Bitcoin CompactSize encoding
+func appendVarInt(b []byte, n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return append(b, byte(n))
+	case n <= 0xffff:
+		return binary.LittleEndian.AppendUint16(append(b, 0xfd), uint16(n))
+	case n <= 0xffffffff:
+		return binary.LittleEndian.AppendUint32(append(b, 0xfe), uint32(n))
+	default:
+		return binary.LittleEndian.AppendUint64(append(b, 0xff), n)
+	}
+}
+
+// readVarInt reads a Bitcoin CompactSize integer
+func readVarInt(r *bytes.Reader) (uint64, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	var size int
+	switch prefix {
+	case 0xfd:
+		size = 2
+	case 0xfe:
+		size = 4
+	case 0xff:
+		size = 8
+	default:
+		return uint64(prefix), nil
+	}
+
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:size]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
@@ -0,0 +1,73 @@
+package chaintracks
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWireMagic = [4]byte{0xfb, 0xce, 0xc4, 0xf9}
+
+func TestWireMessage(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeWireMessage(&buf, testWireMagic, wireCmdPing, []byte{1, 2, 3, 4, 5, 6, 7, 8}))
+		require.Len(t, buf.Bytes(), wireHeaderSize+8)
+
+		msg, err := readWireMessage(&buf, testWireMagic)
+		require.NoError(t, err)
+		assert.Equal(t, wireCmdPing, msg.command)
+		assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, msg.payload)
+	})
+
+	t.Run("WrongMagic", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeWireMessage(&buf, [4]byte{0xe3, 0xe1, 0xf3, 0xe8}, wireCmdVerack, nil))
+		_, err := readWireMessage(&buf, testWireMagic)
+		require.ErrorIs(t, err, ErrWireProtocol)
+	})
+
+	t.Run("BadChecksum", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeWireMessage(&buf, testWireMagic, wireCmdPing, []byte{1}))
+		raw := buf.Bytes()
+		raw[len(raw)-1] ^= 0xff
+		_, err := readWireMessage(bytes.NewReader(raw), testWireMagic)
+		require.ErrorIs(t, err, ErrWireProtocol)
+	})
+}
+
+func TestWireVersion(t *testing.T) {
+	payload := encodeVersion(42, wireDefaultAgent, 800000, time.Unix(1700000000, 0))
+	v, err := decodeVersion(payload)
+	require.NoError(t, err)
+	assert.Equal(t, wireVersion{protocol: wireProtocolVersion, nonce: 42, userAgent: wireDefaultAgent, startHeight: 800000}, v)
+
+	_, err = decodeVersion(payload[:40])
+	require.ErrorIs(t, err, ErrWireProtocol)
+}
+
+func TestWireHeaders(t *testing.T) {
+	headers := []*block.Header{{Nonce: 1}, {Nonce: 2, PrevHash: chainhash.Hash{1}}}
+	decoded, err := decodeHeaders(encodeHeaders(headers))
+	require.NoError(t, err)
+	require.Len(t, decoded, 2)
+	assert.Equal(t, headers[1].Hash(), decoded[1].Hash())
+
+	_, err = decodeHeaders(appendVarInt(nil, wireMaxHeaders+1))
+	require.ErrorIs(t, err, ErrWireProtocol)
+}
+
+func TestVarInt(t *testing.T) {
+	for _, n := range []uint64{0, 0xfc, 0xfd, 0xffff, 0x10000, 0xffffffff, 0x100000000} {
+		encoded := appendVarInt(nil, n)
+		decoded, err := readVarInt(bytes.NewReader(encoded))
+		require.NoError(t, err)
+		assert.Equal(t, n, decoded)
+	}
+}
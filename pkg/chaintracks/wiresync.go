@@ -0,0 +1,276 @@
+package chaintracks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+)
+
+const (
+	defaultWireReconnectDelay = 10 * time.Second
+	wireHandshakeTimeout      = 30 * time.Second
+	wireIdleTimeout           = 10 * time.Minute // Nodes ping every two minutes, so silence this long means a dead link
+	wireMaxUnconnected        = 3                // Consecutive unconnectable header batches before dropping a node
+	wireLocatorDense          = 10               // Locator entries one block apart before the step starts doubling
+)
+
+// WireSyncConfig configures a WireSync
+type WireSyncConfig struct {
+	Nodes          []string      // BSV nodes as host or host:port, tried in turn; the port defaults to the network's
+	UserAgent      string        // Sent in the version message (default /go-chaintracks/)
+	ReconnectDelay time.Duration // Wait before moving to the next node after a session ends (default 10s)
+	Logger         Logger        // Defaults to the package default logger
+}
+
+// WireSync follows the chain by speaking the Bitcoin P2P protocol directly to BSV nodes
+// It needs neither the message-bus overlay nor a bootstrap HTTP endpoint. After the version handshake it
+// sends sendheaders so new blocks are announced as headers, then catches up with getheaders from a locator
+// of the local chain. Headers must link and carry valid proof of work; a node sending anything else is dropped.
+// The ChainManager must already hold at least the genesis header.
+type WireSync struct {
+	cm           *ChainManager
+	magic        [4]byte
+	port         string
+	powLimitBits uint32
+	config       WireSyncConfig
+}
+
+// NewWireSync creates a WireSync feeding cm from the configured nodes
+func NewWireSync(cm *ChainManager, config WireSyncConfig) (*WireSync, error) {
+	defaults := DefaultsForNetwork(cm.network)
+	if defaults.NodeMagic == [4]byte{} {
+		return nil, fmt.Errorf("%w: no wire protocol parameters for network %q", ErrInvalidConfig, cm.network)
+	}
+	if len(config.Nodes) == 0 {
+		return nil, fmt.Errorf("%w: wire sync requires at least one node", ErrInvalidConfig)
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = wireDefaultAgent
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = defaultWireReconnectDelay
+	}
+	if config.Logger == nil {
+		config.Logger = getDefaultLogger()
+	}
+
+	return &WireSync{
+		cm:           cm,
+		magic:        defaults.NodeMagic,
+		port:         defaults.NodePort,
+		powLimitBits: defaults.PowLimitBits,
+		config:       config,
+	}, nil
+}
+
+// Run syncs from the configured nodes until ctx is cancelled, moving to the next node whenever a session ends
+func (w *WireSync) Run(ctx context.Context) {
+	for i := 0; ; i++ {
+		node := w.config.Nodes[i%len(w.config.Nodes)]
+		err := w.Sync(ctx, node)
+		if ctx.Err() != nil {
+			return
+		}
+		w.config.Logger.Warn("Wire sync session ended", "node", node, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.config.ReconnectDelay):
+		}
+	}
+}
+
+// Sync runs one session against node, catching up and then following its announcements
+// It returns when the connection fails, the node misbehaves or ctx is cancelled.
+func (w *WireSync) Sync(ctx context.Context, node string) error {
+	addr := node
+	if _, _, err := net.SplitHostPort(node); err != nil {
+		addr = net.JoinHostPort(node, w.port)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	s := &wireSession{WireSync: w, conn: conn, addr: addr}
+	err = s.run(ctx)
+	if s.syncing {
+		w.cm.endSync(ctx, err)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// wireSession is one connection to a node
+type wireSession struct {
+	*WireSync
+	conn        net.Conn
+	addr        string
+	peer        wireVersion
+	syncing     bool // A catch-up is being tracked in SyncStatus
+	unconnected int  // Consecutive header batches whose parent was unknown
+}
+
+// run performs the handshake, then serves the node's messages until the connection ends
+func (s *wireSession) run(ctx context.Context) error {
+	nonce := rand.Uint64() //nolint:gosec // Only detects connections to ourselves
+	if err := s.send(wireCmdVersion, encodeVersion(nonce, s.config.UserAgent, s.cm.GetHeight(ctx), time.Now())); err != nil {
+		return err
+	}
+
+	var gotVersion, gotVerack, ready bool
+	deadline := wireHandshakeTimeout
+	for {
+		_ = s.conn.SetReadDeadline(time.Now().Add(deadline))
+		msg, err := readWireMessage(s.conn, s.magic)
+		if err != nil {
+			return fmt.Errorf("failed to read from %s: %w", s.addr, err)
+		}
+
+		switch msg.command {
+		case wireCmdVersion:
+			if s.peer, err = decodeVersion(msg.payload); err != nil {
+				return err
+			}
+			if s.peer.nonce == nonce {
+				return fmt.Errorf("%w: connected to ourselves", ErrWireProtocol)
+			}
+			gotVersion = true
+			err = s.send(wireCmdVerack, nil)
+		case wireCmdVerack:
+			gotVerack = true
+		case wireCmdPing:
+			err = s.send(wireCmdPong, msg.payload)
+		case wireCmdHeaders:
+			if ready {
+				err = s.handleHeaders(ctx, msg.payload)
+			}
+		case wireCmdInv:
+			if ready && invHasBlock(msg.payload) {
+				err = s.requestHeaders(ctx)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if !ready && gotVersion && gotVerack {
+			ready = true
+			deadline = wireIdleTimeout
+			s.config.Logger.Info("Wire handshake complete", "node", s.addr, "agent", s.peer.userAgent, "height", s.peer.startHeight)
+			if s.peer.startHeight > s.cm.GetHeight(ctx) {
+				s.syncing = true
+				s.cm.beginSync(ctx, s.addr, s.peer.startHeight)
+			}
+			if err := s.send(wireCmdSendHeaders, nil); err != nil {
+				return err
+			}
+			if err := s.requestHeaders(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// send writes one message to the node
+func (s *wireSession) send(command string, payload []byte) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(wireHandshakeTimeout))
+	if err := writeWireMessage(s.conn, s.magic, command, payload); err != nil {
+		return fmt.Errorf("failed to send %s to %s: %w", command, s.addr, err)
+	}
+	return nil
+}
+
+// requestHeaders asks the node for the headers following the local chain
+func (s *wireSession) requestHeaders(ctx context.Context) error {
+	return s.send(wireCmdGetHeaders, encodeGetHeaders(s.cm.blockLocator(ctx)))
+}
+
+// handleHeaders applies a headers message, asking for the next batch while the node has more
+func (s *wireSession) handleHeaders(ctx context.Context, payload []byte) error {
+	headers, err := decodeHeaders(payload)
+	if err != nil {
+		return err
+	}
+	if len(headers) > 0 {
+		err = s.cm.connectHeaders(ctx, headers, s.powLimitBits)
+	}
+
+	switch {
+	case errors.Is(err, ErrHeaderNotFound):
+		// Announced on a branch we lack; a getheaders from our locator fills the gap
+		if s.unconnected++; s.unconnected > wireMaxUnconnected {
+			return fmt.Errorf("%w: %s keeps sending headers that do not connect", ErrWireProtocol, s.addr)
+		}
+		return s.requestHeaders(ctx)
+	case err != nil:
+		return fmt.Errorf("rejected headers from %s: %w", s.addr, err)
+	}
+	s.unconnected = 0
+
+	if s.syncing {
+		s.cm.advanceSync(ctx, len(headers), nil)
+	}
+	if len(headers) == wireMaxHeaders {
+		return s.requestHeaders(ctx)
+	}
+	if s.syncing {
+		s.syncing = false
+		s.cm.endSync(ctx, nil)
+		s.config.Logger.Info("Wire sync caught up", "node", s.addr, "height", s.cm.GetHeight(ctx))
+	}
+	return nil
+}
+
+// connectHeaders checks the proof of work of consecutive headers and links them in with AddHeaders
+// Returns ErrHeaderNotFound when the parent of the first header is unknown.
+func (cm *ChainManager) connectHeaders(ctx context.Context, headers []*block.Header, powLimitBits uint32) error {
+	branch := make([]*BlockHeader, len(headers))
+	for i, header := range headers {
+		if err := CheckProofOfWork(header, powLimitBits); err != nil {
+			return err
+		}
+		branch[i] = &BlockHeader{Header: header, Hash: header.Hash()}
+	}
+	return cm.AddHeaders(ctx, branch)
+}
+
+// blockLocator lists main-chain hashes from the tip back to genesis, one block apart at first and then
+// doubling the step, so a peer can find the fork point in a single round trip
+func (cm *ChainManager) blockLocator(ctx context.Context) []chainhash.Hash {
+	tip := cm.GetTip(ctx)
+	if tip == nil {
+		return nil
+	}
+
+	locator := make([]chainhash.Hash, 0, wireLocatorDense+32)
+	step := int64(1)
+	for height := int64(tip.Height); height > 0; height -= step {
+		if header, err := cm.GetHeaderByHeight(ctx, uint32(height)); err == nil { //nolint:gosec // 0 < height <= tip
+			locator = append(locator, header.Hash)
+		}
+		if len(locator) >= wireLocatorDense {
+			step *= 2
+		}
+	}
+	if genesis, err := cm.GetHeaderByHeight(ctx, 0); err == nil {
+		locator = append(locator, genesis.Hash)
+	}
+	return locator
+}
@@ -0,0 +1,221 @@
+package chaintracks
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mineRegtestChain returns count headers mined on top of parent; branches with different tags differ
+func mineRegtestChain(t *testing.T, parent chainhash.Hash, count int, tag uint32) []*block.Header {
+	t.Helper()
+
+	headers := make([]*block.Header, count)
+	for i := range headers {
+		header := &block.Header{Version: 1, PrevHash: parent, Timestamp: tag, Bits: regtestPowLimitBits}
+		for CheckProofOfWork(header, regtestPowLimitBits) != nil {
+			header.Nonce++
+		}
+		headers[i] = header
+		parent = header.Hash()
+	}
+	return headers
+}
+
+// newWireChainManager returns an stn ChainManager holding a regtest genesis and the first count headers of chain
+func newWireChainManager(t *testing.T, genesis *block.Header, chain []*block.Header) *ChainManager {
+	t.Helper()
+
+	cm := &ChainManager{network: "stn", byHash: make(map[chainhash.Hash]*BlockHeader)}
+	root := &BlockHeader{Header: genesis, Hash: genesis.Hash(), ChainWork: big.NewInt(1)}
+	require.NoError(t, cm.SetChainTip(t.Context(), []*BlockHeader{root}))
+	if len(chain) > 0 {
+		require.NoError(t, cm.connectHeaders(t.Context(), chain, 0))
+	}
+	return cm
+}
+
+// fakeNode answers the version handshake and getheaders from a fixed chain, and relays announcements
+type fakeNode struct {
+	listener net.Listener
+	chain    []*block.Header // Genesis first
+	announce chan *block.Header
+
+	mu       sync.Mutex
+	commands []string
+}
+
+func newFakeNode(t *testing.T, chain []*block.Header) *fakeNode {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	n := &fakeNode{listener: listener, chain: chain, announce: make(chan *block.Header)}
+	t.Cleanup(func() { _ = listener.Close() })
+	go n.serve()
+	return n
+}
+
+func (n *fakeNode) serve() {
+	conn, err := n.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var writeMu sync.Mutex
+	send := func(command string, payload []byte) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = writeWireMessage(conn, testWireMagic, command, payload)
+	}
+	go func() {
+		for header := range n.announce {
+			send(wireCmdHeaders, encodeHeaders([]*block.Header{header}))
+		}
+	}()
+
+	for {
+		msg, err := readWireMessage(conn, testWireMagic)
+		if err != nil {
+			return
+		}
+		n.mu.Lock()
+		n.commands = append(n.commands, msg.command)
+		n.mu.Unlock()
+
+		switch msg.command {
+		case wireCmdVersion:
+			send(wireCmdVersion, encodeVersion(7, "/fake/", uint32(len(n.chain)-1), time.Now())) //nolint:gosec // Test data
+			send(wireCmdVerack, nil)
+		case wireCmdGetHeaders:
+			send(wireCmdHeaders, encodeHeaders(n.headersAfter(msg.payload)))
+		}
+	}
+}
+
+// headersAfter returns up to wireMaxHeaders headers following the first locator hash in the chain
+func (n *fakeNode) headersAfter(payload []byte) []*block.Header {
+	r := bytes.NewReader(payload[4:])
+	count, _ := readVarInt(r)
+	for range count {
+		var hash chainhash.Hash
+		_, _ = r.Read(hash[:])
+		for i, header := range n.chain {
+			if header.Hash() == hash {
+				return n.chain[i+1 : min(i+1+wireMaxHeaders, len(n.chain))]
+			}
+		}
+	}
+	return nil
+}
+
+func (n *fakeNode) received(command string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	count := 0
+	for _, c := range n.commands {
+		if c == command {
+			count++
+		}
+	}
+	return count
+}
+
+func TestWireSyncCatchesUpAndFollows(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 2100, 0)
+	node := newFakeNode(t, append([]*block.Header{genesis}, chain...))
+	cm := newWireChainManager(t, genesis, chain[:5])
+
+	wireSync, err := NewWireSync(cm, WireSyncConfig{Nodes: []string{node.listener.Addr().String()}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- wireSync.Sync(ctx, node.listener.Addr().String()) }()
+
+	require.Eventually(t, func() bool { return cm.GetHeight(ctx) == 2100 }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, chain[2099].Hash(), cm.GetTip(ctx).Hash)
+	assert.Equal(t, 1, node.received(wireCmdSendHeaders))
+	assert.Equal(t, 2, node.received(wireCmdGetHeaders), "a full batch asks for the next one")
+	require.Eventually(t, func() bool { return !cm.SyncStatus().Active }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint32(2100), cm.SyncStatus().CurrentHeight)
+
+	next := mineRegtestHeader(t, chain[2099].Hash())
+	node.announce <- next
+	require.Eventually(t, func() bool { return cm.GetTip(ctx).Hash == next.Hash() }, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	close(node.announce)
+}
+
+func TestWireSyncDropsMisbehavingNode(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	unmined := &block.Header{Version: 1, PrevHash: genesis.Hash(), Bits: 0x1d00ffff}
+	node := newFakeNode(t, []*block.Header{genesis, unmined})
+	cm := newWireChainManager(t, genesis, nil)
+
+	wireSync, err := NewWireSync(cm, WireSyncConfig{Nodes: []string{"unused"}})
+	require.NoError(t, err)
+
+	err = wireSync.Sync(t.Context(), node.listener.Addr().String())
+	require.ErrorIs(t, err, ErrInvalidProofOfWork)
+	assert.Equal(t, uint32(0), cm.GetHeight(t.Context()))
+	assert.NotEmpty(t, cm.SyncStatus().Error)
+}
+
+func TestNewWireSyncValidatesConfig(t *testing.T) {
+	_, err := NewWireSync(&ChainManager{network: "stn"}, WireSyncConfig{})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = NewWireSync(&ChainManager{network: "regtest"}, WireSyncConfig{Nodes: []string{"localhost"}})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestChainManagerConnectHeaders(t *testing.T) {
+	genesis := &block.Header{Bits: regtestPowLimitBits}
+	chain := mineRegtestChain(t, genesis.Hash(), 3, 0)
+	fork := mineRegtestChain(t, chain[0].Hash(), 3, 1)
+	extension := mineRegtestChain(t, chain[2].Hash(), 2, 0)
+
+	tests := []struct {
+		name          string
+		headers       []*block.Header
+		expectedTip   chainhash.Hash
+		expectedError error
+	}{
+		{name: "ExtendsTip", headers: extension, expectedTip: extension[1].Hash()},
+		{name: "SkipsKnownHeaders", headers: chain[1:], expectedTip: chain[2].Hash()},
+		{name: "ReorgsToHeavierBranch", headers: fork, expectedTip: fork[2].Hash()},
+		{name: "KeepsLighterBranch", headers: fork[:1], expectedTip: chain[2].Hash()},
+		{name: "UnknownParent", headers: fork[1:], expectedError: ErrHeaderNotFound},
+		{name: "BrokenLinkage", headers: []*block.Header{chain[0], chain[2]}, expectedError: ErrBrokenChain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := newWireChainManager(t, genesis, chain)
+
+			err := cm.connectHeaders(t.Context(), tt.headers, 0)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTip, cm.GetTip(t.Context()).Hash)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Package chi mounts the chaintracks v2 API on chi routers, mirroring routes/fiber Register.
+// The handlers are those of routes/stdhttp, including the SSE tip stream, and chi middleware applies to them
+// as to any mounted handler.
+package chi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/bsv-blockchain/go-chaintracks/routes/stdhttp"
+)
+
+// Register mounts the v2 API on router under prefix (typically "/v2")
+func Register(router chi.Router, prefix string, routes *stdhttp.Routes) {
+	mount(router, prefix, routes.Handler())
+}
+
+// RegisterBHS mounts the Block Headers Service compatible API on router under prefix (typically "/api/v1")
+func RegisterBHS(router chi.Router, prefix string, routes *stdhttp.Routes) {
+	mount(router, prefix, routes.BHSHandler())
+}
+
+// mount serves handler under prefix
+// chi leaves the request path whole and routes mounts on the path left after their pattern, so the handler is
+// given that path, which also holds when router is a subrouter from chi.Route or chi.Group.
+func mount(router chi.Router, prefix string, handler http.Handler) {
+	router.Mount("/"+strings.Trim(prefix, "/"), http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handler.ServeHTTP(w, withPath(req, chi.RouteContext(req.Context()).RoutePath))
+	}))
+}
+
+// withPath returns a shallow copy of req with its URL path replaced
+func withPath(req *http.Request, path string) *http.Request {
+	r := new(http.Request)
+	*r = *req
+	r.URL = new(url.URL)
+	*r.URL = *req.URL
+	r.URL.Path = path
+	r.URL.RawPath = ""
+	return r
+}
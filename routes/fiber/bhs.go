@@ -0,0 +1,149 @@
+package fiber
+
+import (
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// Block Headers Service route keys, relative to the router passed to RegisterBHS (typically /api/v1)
+const (
+	RouteBHSTip         = "/chain/tip"
+	RouteBHSTipLongest  = "/chain/tip/longest"
+	RouteBHSHeader      = "/chain/header/:hash"
+	RouteBHSVerifyRoots = "/chain/merkleroot/verify"
+)
+
+// Block Headers Service states and error codes
+const (
+	bhsStateLongestChain  = "LONGEST_CHAIN"
+	bhsErrHeaderNotFound  = "ErrHeaderNotFound"
+	bhsErrInvalidRequest  = "ErrInvalidRequest"
+	bhsErrTipNotAvailable = "ErrTipNotAvailable"
+)
+
+// BHSHeader is a block header in Block Headers Service format
+type BHSHeader struct {
+	Hash             string `json:"hash"`
+	Version          int32  `json:"version"`
+	PrevBlockHash    string `json:"prevBlockHash"`
+	MerkleRoot       string `json:"merkleRoot"`
+	CreationTime     uint32 `json:"creationTimestamp"`
+	DifficultyTarget uint32 `json:"difficultyTarget"`
+	Nonce            uint32 `json:"nonce"`
+	Work             string `json:"work"` // Decimal work of this block
+}
+
+// BHSHeaderState is a header with its chain state in Block Headers Service format
+type BHSHeaderState struct {
+	Header    BHSHeader `json:"header"`
+	State     string    `json:"state"`
+	ChainWork string    `json:"chainWork"` // Decimal cumulative work
+	Height    uint32    `json:"height"`
+}
+
+// BHSMerkleRootConfirmation is the verification result for one merkle root
+type BHSMerkleRootConfirmation struct {
+	BlockHash    string `json:"blockHash"`
+	BlockHeight  uint32 `json:"blockHeight"`
+	MerkleRoot   string `json:"merkleRoot"`
+	Confirmation string `json:"confirmation"`
+}
+
+// BHSMerkleRootsResponse is the merkle root verification response
+type BHSMerkleRootsResponse struct {
+	ConfirmationState string                      `json:"confirmationState"`
+	Confirmations     []BHSMerkleRootConfirmation `json:"confirmations"`
+}
+
+// bhsError is the Block Headers Service error body
+type bhsError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RegisterBHS mounts a Block Headers Service compatible API on the given router (typically app.Group("/api/v1"))
+// so clients such as go-wallet-toolbox and ARC can use chaintracks as a drop-in replacement.
+// Global and per-route middleware apply as for Register.
+func (r *Routes) RegisterBHS(router fiber.Router) {
+	r.add(router, RouteBHSTip, r.HandleBHSTip)
+	r.add(router, RouteBHSTipLongest, r.HandleBHSTip)
+	r.add(router, RouteBHSHeader, r.HandleBHSHeader)
+	router.Post(RouteBHSVerifyRoots, r.chain(RouteBHSVerifyRoots, r.HandleBHSVerifyMerkleRoots)...)
+}
+
+// HandleBHSTip returns the longest chain tip with its state
+func (r *Routes) HandleBHSTip(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+
+	tip := r.ct.GetTip(c.UserContext())
+	if tip == nil || tip.Header == nil {
+		return c.Status(fiber.StatusNotFound).JSON(bhsError{Code: bhsErrTipNotAvailable, Message: "chain tip not available"})
+	}
+
+	chainWork := "0"
+	if tip.ChainWork != nil {
+		chainWork = tip.ChainWork.String()
+	}
+
+	return c.JSON(BHSHeaderState{
+		Header:    toBHSHeader(tip),
+		State:     bhsStateLongestChain,
+		ChainWork: chainWork,
+		Height:    tip.Height,
+	})
+}
+
+// HandleBHSHeader returns a header by hash
+func (r *Routes) HandleBHSHeader(c *fiber.Ctx) error {
+	hash, err := chainhash.NewHashFromHex(c.Params("hash"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(bhsError{Code: bhsErrInvalidRequest, Message: "invalid block hash"})
+	}
+
+	header, err := r.ct.GetHeaderByHash(c.UserContext(), hash)
+	if err != nil || header.Header == nil {
+		return c.Status(fiber.StatusNotFound).JSON(bhsError{Code: bhsErrHeaderNotFound, Message: "header not found"})
+	}
+
+	return c.JSON(toBHSHeader(header))
+}
+
+// HandleBHSVerifyMerkleRoots checks merkle roots against the main chain
+func (r *Routes) HandleBHSVerifyMerkleRoots(c *fiber.Ctx) error {
+	var requests []chaintracks.MerkleRootCheck
+	if err := c.BodyParser(&requests); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(bhsError{Code: bhsErrInvalidRequest, Message: "invalid request body"})
+	}
+
+	verification := chaintracks.VerifyMerkleRoots(c.UserContext(), r.ct, requests)
+	response := BHSMerkleRootsResponse{
+		ConfirmationState: string(verification.State),
+		Confirmations:     make([]BHSMerkleRootConfirmation, len(verification.Results)),
+	}
+	for i, result := range verification.Results {
+		response.Confirmations[i] = BHSMerkleRootConfirmation{
+			BlockHash:    result.BlockHash,
+			BlockHeight:  result.BlockHeight,
+			MerkleRoot:   result.MerkleRoot,
+			Confirmation: string(result.Status),
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// toBHSHeader converts a header to Block Headers Service format
+func toBHSHeader(header *chaintracks.BlockHeader) BHSHeader {
+	return BHSHeader{
+		Hash:             header.Hash.String(),
+		Version:          header.Version,
+		PrevBlockHash:    header.PrevHash.String(),
+		MerkleRoot:       header.MerkleRoot.String(),
+		CreationTime:     header.Timestamp,
+		DifficultyTarget: header.Bits,
+		Nonce:            header.Nonce,
+		Work:             chaintracks.CalculateWork(header.Bits).String(),
+	}
+}
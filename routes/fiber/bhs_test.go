@@ -0,0 +1,108 @@
+package fiber
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutesRegisterBHS(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).RegisterBHS(app.Group("/api/v1"))
+
+	tipHash := chainhash.Hash{1}.String()
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectContains string
+	}{
+		{name: "Tip", path: "/api/v1/chain/tip", expectedStatus: 200, expectContains: `"state":"LONGEST_CHAIN"`},
+		{name: "TipLongest", path: "/api/v1/chain/tip/longest", expectedStatus: 200, expectContains: `"hash":"` + tipHash + `"`},
+		{name: "HeaderByHash", path: "/api/v1/chain/header/" + tipHash, expectedStatus: 200, expectContains: `"creationTimestamp":0`},
+		{name: "HeaderNotFound", path: "/api/v1/chain/header/" + chainhash.Hash{2}.String(), expectedStatus: 404, expectContains: "ErrHeaderNotFound"},
+		{name: "InvalidHash", path: "/api/v1/chain/header/xyz", expectedStatus: 400, expectContains: "ErrInvalidRequest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			assert.Contains(t, body, tt.expectContains)
+		})
+	}
+}
+
+func TestRoutesBHSVerifyMerkleRoots(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).RegisterBHS(app.Group("/api/v1"))
+
+	validRoot := chainhash.Hash{}.String()
+	otherRoot := chainhash.Hash{9}.String()
+
+	tests := []struct {
+		name                  string
+		body                  string
+		expectedStatus        int
+		expectedState         string
+		expectedConfirmations []string
+	}{
+		{
+			name:                  "Confirmed",
+			body:                  `[{"merkleRoot":"` + validRoot + `","blockHeight":0}]`,
+			expectedStatus:        200,
+			expectedState:         "CONFIRMED",
+			expectedConfirmations: []string{"CONFIRMED"},
+		},
+		{
+			name:                  "WrongRootIsInvalid",
+			body:                  `[{"merkleRoot":"` + validRoot + `","blockHeight":0},{"merkleRoot":"` + otherRoot + `","blockHeight":0}]`,
+			expectedStatus:        200,
+			expectedState:         "INVALID",
+			expectedConfirmations: []string{"CONFIRMED", "INVALID"},
+		},
+		{
+			name:                  "AboveTipIsUnableToVerify",
+			body:                  `[{"merkleRoot":"` + validRoot + `","blockHeight":0},{"merkleRoot":"` + otherRoot + `","blockHeight":10}]`,
+			expectedStatus:        200,
+			expectedState:         "UNABLE_TO_VERIFY",
+			expectedConfirmations: []string{"CONFIRMED", "UNABLE_TO_VERIFY"},
+		},
+		{
+			name:                  "InvalidOutranksUnableToVerify",
+			body:                  `[{"merkleRoot":"` + otherRoot + `","blockHeight":10},{"merkleRoot":"zz","blockHeight":0}]`,
+			expectedStatus:        200,
+			expectedState:         "INVALID",
+			expectedConfirmations: []string{"UNABLE_TO_VERIFY", "INVALID"},
+		},
+		{
+			name:           "RejectsMalformedBody",
+			body:           `{"merkleRoot":`,
+			expectedStatus: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := post(t, app, "/api/v1/chain/merkleroot/verify", tt.body)
+			require.Equal(t, tt.expectedStatus, status, body)
+			if tt.expectedStatus != 200 {
+				return
+			}
+
+			var result BHSMerkleRootsResponse
+			require.NoError(t, json.Unmarshal([]byte(body), &result))
+			assert.Equal(t, tt.expectedState, result.ConfirmationState)
+
+			confirmations := make([]string, len(result.Confirmations))
+			for i, c := range result.Confirmations {
+				confirmations[i] = c.Confirmation
+			}
+			assert.Equal(t, tt.expectedConfirmations, confirmations)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package fiber
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheControlNoCache is the Cache-Control value for responses that must be revalidated on every request
+const CacheControlNoCache = "no-cache"
+
+const (
+	// DefaultImmutableDepth is how far below the tip a header must be before responses holding it are cached
+	DefaultImmutableDepth = 100
+
+	// DefaultImmutableMaxAge is how long responses holding only immutable headers are cached
+	DefaultImmutableMaxAge = time.Hour
+
+	// DefaultHeightMaxAge is how long the chain height is cached
+	DefaultHeightMaxAge = time.Minute
+)
+
+// CachePolicy decides the Cache-Control header of each route
+// Headers more than ImmutableDepth blocks below the tip are assumed never to be reorganized away, so
+// responses holding only such headers are cached for ImmutableMaxAge; everything nearer the tip is served
+// with no-cache. Zero values keep the defaults.
+type CachePolicy struct {
+	ImmutableDepth  uint32        // Blocks below the tip after which a header is immutable
+	ImmutableMaxAge time.Duration // max-age of responses holding only immutable headers
+	HeightMaxAge    time.Duration // max-age of the chain height
+	NoCache         []string      // Route keys always served with no-cache, overriding the above
+}
+
+// DefaultCachePolicy returns the cache policy used unless WithCachePolicy is given
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		ImmutableDepth:  DefaultImmutableDepth,
+		ImmutableMaxAge: DefaultImmutableMaxAge,
+		HeightMaxAge:    DefaultHeightMaxAge,
+	}
+}
+
+// withDefaults fills the zero fields of p from DefaultCachePolicy
+func (p CachePolicy) withDefaults() CachePolicy {
+	defaults := DefaultCachePolicy()
+	if p.ImmutableDepth == 0 {
+		p.ImmutableDepth = defaults.ImmutableDepth
+	}
+	if p.ImmutableMaxAge <= 0 {
+		p.ImmutableMaxAge = defaults.ImmutableMaxAge
+	}
+	if p.HeightMaxAge <= 0 {
+		p.HeightMaxAge = defaults.HeightMaxAge
+	}
+	return p
+}
+
+// Immutable reports whether a header at height is deep enough below tip to be cached
+// A chain shorter than ImmutableDepth has no immutable headers.
+func (p CachePolicy) Immutable(height, tip uint32) bool {
+	return tip >= p.ImmutableDepth && height < tip-p.ImmutableDepth
+}
+
+// ForHeight returns the Cache-Control value of a response on route whose newest header is at height
+func (p CachePolicy) ForHeight(route string, height, tip uint32) string {
+	if !p.Immutable(height, tip) {
+		return CacheControlNoCache
+	}
+	return p.maxAge(route, p.ImmutableMaxAge)
+}
+
+// ForChainHeight returns the Cache-Control value of the chain height route
+func (p CachePolicy) ForChainHeight() string {
+	return p.maxAge(RouteHeight, p.HeightMaxAge)
+}
+
+// maxAge returns a public max-age Cache-Control value, or no-cache when route is overridden
+func (p CachePolicy) maxAge(route string, age time.Duration) string {
+	if slices.Contains(p.NoCache, route) {
+		return CacheControlNoCache
+	}
+	return "public, max-age=" + strconv.Itoa(int(age.Seconds()))
+}
+
+// setCache sets the Cache-Control header of a response on route whose newest header is at height
+func (r *Routes) setCache(c *fiber.Ctx, route string, height, tip uint32) {
+	c.Set(fiber.HeaderCacheControl, r.cache.ForHeight(route, height, tip))
+}
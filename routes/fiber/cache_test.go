@@ -0,0 +1,91 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachePolicyForHeight(t *testing.T) {
+	policy := DefaultCachePolicy()
+
+	tests := []struct {
+		name     string
+		policy   CachePolicy
+		route    string
+		height   uint32
+		tip      uint32
+		expected string
+	}{
+		{name: "Deep", policy: policy, route: RouteHeaders, height: 10, tip: 1000, expected: "public, max-age=3600"},
+		{name: "AtDepth", policy: policy, route: RouteHeaders, height: 900, tip: 1000, expected: CacheControlNoCache},
+		{name: "NearTip", policy: policy, route: RouteHeaders, height: 990, tip: 1000, expected: CacheControlNoCache},
+		{name: "ShortChain", policy: policy, route: RouteHeaders, height: 0, tip: 50, expected: CacheControlNoCache},
+		{name: "ShortChainAtTip", policy: policy, route: RouteHeaders, height: 50, tip: 50, expected: CacheControlNoCache},
+		{
+			name:     "Configured",
+			policy:   CachePolicy{ImmutableDepth: 6, ImmutableMaxAge: 24 * time.Hour}.withDefaults(),
+			route:    RouteHeaders,
+			height:   10,
+			tip:      20,
+			expected: "public, max-age=86400",
+		},
+		{
+			name:     "NoCacheOverride",
+			policy:   CachePolicy{NoCache: []string{RouteHeaders}}.withDefaults(),
+			route:    RouteHeaders,
+			height:   10,
+			tip:      1000,
+			expected: CacheControlNoCache,
+		},
+		{
+			name:     "OverrideIsPerRoute",
+			policy:   CachePolicy{NoCache: []string{RouteHeaders}}.withDefaults(),
+			route:    RouteHeaderByHeight,
+			height:   10,
+			tip:      1000,
+			expected: "public, max-age=3600",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.policy.ForHeight(tt.route, tt.height, tt.tip))
+		})
+	}
+}
+
+func TestRoutesCachePolicy(t *testing.T) {
+	stub := newChainStub(5)
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		path     string
+		expected string
+	}{
+		{name: "ShortChainNotCached", path: "/v2/header/height/0", expected: CacheControlNoCache},
+		{name: "Height", path: "/v2/height", expected: "public, max-age=60"},
+		{name: "ImmutableDepth", opts: []Option{WithCachePolicy(CachePolicy{ImmutableDepth: 2})}, path: "/v2/header/height/1", expected: "public, max-age=3600"},
+		{name: "ImmutableDepthNearTip", opts: []Option{WithCachePolicy(CachePolicy{ImmutableDepth: 2})}, path: "/v2/header/height/2", expected: CacheControlNoCache},
+		{name: "HeightMaxAge", opts: []Option{WithCachePolicy(CachePolicy{HeightMaxAge: 10 * time.Second})}, path: "/v2/height", expected: "public, max-age=10"},
+		{name: "HeightNoCache", opts: []Option{WithCachePolicy(CachePolicy{NoCache: []string{RouteHeight}})}, path: "/v2/height", expected: CacheControlNoCache},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			NewRoutes(stub, tt.opts...).Register(app.Group("/v2"))
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.expected, resp.Header.Get(fiber.HeaderCacheControl))
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package fiber
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// Binary media types the header routes serve besides JSON, see chaintracks.proto for the protobuf schema
+const (
+	MIMEApplicationProtobuf = "application/protobuf"
+	MIMEApplicationCBOR     = "application/cbor"
+)
+
+// negotiate returns the media type to answer a header route in: protobuf or CBOR when the client prefers one,
+// JSON otherwise
+func negotiate(c *fiber.Ctx) string {
+	c.Vary(fiber.HeaderAccept)
+	if format := c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationProtobuf, MIMEApplicationCBOR); format != "" {
+		return format
+	}
+	return fiber.MIMEApplicationJSON
+}
+
+// formatTag returns the ETag suffix telling apart a format's representation of the same headers
+func formatTag(format string) string {
+	switch format {
+	case MIMEApplicationProtobuf:
+		return "pb"
+	case MIMEApplicationCBOR:
+		return "cbor"
+	}
+	return ""
+}
+
+// sendValue answers a successful request with value in format
+// JSON wraps value in the Response envelope; protobuf and CBOR carry the bare value, so they fall back to JSON
+// for a value type they have no encoding for.
+func sendValue(c *fiber.Ctx, format string, value any) error {
+	var body []byte
+	var ok bool
+	switch format {
+	case MIMEApplicationProtobuf:
+		body, ok = marshalProtobuf(value)
+	case MIMEApplicationCBOR:
+		body, ok = marshalCBOR(value)
+	}
+	if !ok {
+		return c.JSON(Response{
+			Status: "success",
+			Value:  value,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, format)
+	return c.Send(body)
+}
+
+// marshalProtobuf encodes value as its message in chaintracks.proto
+func marshalProtobuf(value any) ([]byte, bool) {
+	switch v := value.(type) {
+	case uint32:
+		return appendProtoUint(nil, 1, v), true
+	case *chainhash.Hash:
+		return protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), v[:]), true
+	case *chaintracks.BlockHeader:
+		return appendProtoHeader(nil, v), true
+	case []*chaintracks.BlockHeader:
+		var b, header []byte
+		for _, h := range v {
+			header = appendProtoHeader(header[:0], h)
+			b = protowire.AppendTag(b, 1, protowire.BytesType)
+			b = protowire.AppendBytes(b, header)
+		}
+		return b, true
+	}
+	return nil, false
+}
+
+// appendProtoHeader appends the fields of a BlockHeader message, none for a nil header
+func appendProtoHeader(b []byte, h *chaintracks.BlockHeader) []byte {
+	if h == nil {
+		return b
+	}
+	b = appendProtoUint(b, 1, h.Height)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, h.Hash[:])
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(int64(h.Version))) //nolint:gosec // Negative int32 fields are sign-extended on the wire
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendBytes(b, h.PrevHash[:])
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendBytes(b, h.MerkleRoot[:])
+	b = appendProtoUint(b, 6, h.Timestamp)
+	b = appendProtoUint(b, 7, h.Bits)
+	b = appendProtoUint(b, 8, h.Nonce)
+	if h.ChainWork != nil {
+		b = protowire.AppendTag(b, 9, protowire.BytesType)
+		b = protowire.AppendBytes(b, h.ChainWork.Bytes())
+	}
+	if h.MedianTime != 0 {
+		b = appendProtoUint(b, 10, h.MedianTime)
+	}
+	return b
+}
+
+// appendProtoUint appends a uint32 field
+func appendProtoUint(b []byte, num protowire.Number, v uint32) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+// CBOR major types, RFC 8949 section 3.1
+const (
+	cborUint     = 0
+	cborNegative = 1
+	cborBytes    = 2
+	cborText     = 3
+	cborArray    = 4
+	cborMap      = 5
+)
+
+// cborNull is the CBOR encoding of null
+const cborNull = 0xf6
+
+// marshalCBOR encodes value with the structure and field names of its JSON form, hashes as 32-byte strings in
+// header serialization order and chain work as a big-endian byte string
+func marshalCBOR(value any) ([]byte, bool) {
+	switch v := value.(type) {
+	case uint32:
+		return appendCBORHead(nil, cborUint, uint64(v)), true
+	case *chainhash.Hash:
+		return appendCBORBytes(nil, v[:]), true
+	case *chaintracks.BlockHeader:
+		return appendCBORHeader(nil, v), true
+	case []*chaintracks.BlockHeader:
+		b := appendCBORHead(nil, cborArray, uint64(len(v)))
+		for _, h := range v {
+			b = appendCBORHeader(b, h)
+		}
+		return b, true
+	}
+	return nil, false
+}
+
+// appendCBORHeader appends a header as a map, or null for a nil header
+func appendCBORHeader(b []byte, h *chaintracks.BlockHeader) []byte {
+	if h == nil {
+		return append(b, cborNull)
+	}
+	fields := uint64(8)
+	if h.ChainWork != nil {
+		fields++
+	}
+	if h.MedianTime != 0 {
+		fields++
+	}
+	b = appendCBORHead(b, cborMap, fields)
+	b = appendCBORHead(appendCBORText(b, "height"), cborUint, uint64(h.Height))
+	b = appendCBORBytes(appendCBORText(b, "hash"), h.Hash[:])
+	b = appendCBORText(b, "version")
+	if h.Version < 0 {
+		b = appendCBORHead(b, cborNegative, uint64(-1-int64(h.Version))) //nolint:gosec // Non-negative for a negative version
+	} else {
+		b = appendCBORHead(b, cborUint, uint64(h.Version)) //nolint:gosec // Checked non-negative
+	}
+	b = appendCBORBytes(appendCBORText(b, "previousHash"), h.PrevHash[:])
+	b = appendCBORBytes(appendCBORText(b, "merkleRoot"), h.MerkleRoot[:])
+	b = appendCBORHead(appendCBORText(b, "time"), cborUint, uint64(h.Timestamp))
+	b = appendCBORHead(appendCBORText(b, "bits"), cborUint, uint64(h.Bits))
+	b = appendCBORHead(appendCBORText(b, "nonce"), cborUint, uint64(h.Nonce))
+	if h.ChainWork != nil {
+		b = appendCBORBytes(appendCBORText(b, "chainWork"), h.ChainWork.Bytes())
+	}
+	if h.MedianTime != 0 {
+		b = appendCBORHead(appendCBORText(b, "medianTime"), cborUint, uint64(h.MedianTime))
+	}
+	return b
+}
+
+// appendCBORHead appends the initial bytes of a data item of the given major type and argument
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major<<5|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major<<5|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major<<5|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major<<5|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, major<<5|27), n)
+}
+
+// appendCBORBytes appends a byte string
+func appendCBORBytes(b, data []byte) []byte {
+	return append(appendCBORHead(b, cborBytes, uint64(len(data))), data...)
+}
+
+// appendCBORText appends a text string
+func appendCBORText(b []byte, s string) []byte {
+	return append(appendCBORHead(b, cborText, uint64(len(s))), s...)
+}
@@ -0,0 +1,172 @@
+package fiber
+
+import (
+	"encoding/hex"
+	"io"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// negotiatedGet requests path with the given Accept header and returns status, content type and body
+func negotiatedGet(t *testing.T, app *fiber.App, path, accept string) (int, string, []byte) {
+	t.Helper()
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("Accept", accept)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderContentType), body
+}
+
+// protoFields decodes a protobuf message into its fields by number, keeping the last value of each
+func protoFields(t *testing.T, b []byte) map[protowire.Number]any {
+	t.Helper()
+	fields := make(map[protowire.Number]any)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num], b = v, b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			fields[num], b = v, b[n:]
+		default:
+			require.Failf(t, "unexpected wire type", "field %d has type %d", num, typ)
+		}
+	}
+	return fields
+}
+
+func TestRoutesContentNegotiation(t *testing.T) {
+	stub := newStubChaintracks()
+	stub.tip = &chaintracks.BlockHeader{
+		Header:    &block.Header{Version: 2, PrevHash: chainhash.Hash{9}, MerkleRoot: chainhash.Hash{8}, Timestamp: 1231006505, Bits: 0x1d00ffff, Nonce: 7},
+		Height:    300,
+		Hash:      chainhash.Hash{1},
+		ChainWork: big.NewInt(0x0102),
+	}
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	t.Run("ProtobufHeader", func(t *testing.T) {
+		status, contentType, body := negotiatedGet(t, app, "/v2/tip/header", MIMEApplicationProtobuf)
+		require.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, MIMEApplicationProtobuf, contentType)
+
+		fields := protoFields(t, body)
+		assert.Equal(t, map[protowire.Number]any{
+			1: uint64(300),
+			2: stub.tip.Hash[:],
+			3: uint64(2),
+			4: stub.tip.PrevHash[:],
+			5: stub.tip.MerkleRoot[:],
+			6: uint64(1231006505),
+			7: uint64(0x1d00ffff),
+			8: uint64(7),
+			9: []byte{0x01, 0x02},
+		}, fields)
+	})
+
+	t.Run("ProtobufHeadersByHashes", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/v2/headers/byHashes", strings.NewReader(`["`+stub.tip.Hash.String()+`","`+chainhash.Hash{2}.String()+`"]`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAccept, MIMEApplicationProtobuf)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var headers [][]byte
+		for len(body) > 0 {
+			num, _, n := protowire.ConsumeTag(body)
+			require.Equal(t, protowire.Number(1), num)
+			header, m := protowire.ConsumeBytes(body[n:])
+			require.GreaterOrEqual(t, m, 0)
+			headers, body = append(headers, header), body[n+m:]
+		}
+		require.Len(t, headers, 2)
+		assert.Equal(t, stub.tip.Hash[:], protoFields(t, headers[0])[2])
+		assert.Empty(t, headers[1], "an unknown hash yields an empty header")
+	})
+
+	t.Run("CBORHeight", func(t *testing.T) {
+		status, contentType, body := negotiatedGet(t, app, "/v2/height", MIMEApplicationCBOR)
+		require.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, MIMEApplicationCBOR, contentType)
+		assert.Equal(t, "19012c", hex.EncodeToString(body))
+	})
+
+	t.Run("CBORTipHash", func(t *testing.T) {
+		_, _, body := negotiatedGet(t, app, "/v2/tip/hash", MIMEApplicationCBOR)
+		hash := stub.tip.Header.Hash()
+		assert.Equal(t, append([]byte{0x58, 0x20}, hash[:]...), body)
+	})
+
+	t.Run("JSONByDefault", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", "text/html"} {
+			status, contentType, body := negotiatedGet(t, app, "/v2/height", accept)
+			require.Equal(t, fiber.StatusOK, status)
+			assert.Equal(t, fiber.MIMEApplicationJSON, contentType, accept)
+			assert.JSONEq(t, `{"status":"success","value":300}`, string(body))
+		}
+	})
+
+	t.Run("ErrorsStayJSON", func(t *testing.T) {
+		status, contentType, _ := negotiatedGet(t, app, "/v2/header/height/5", MIMEApplicationProtobuf)
+		assert.Equal(t, fiber.StatusNotFound, status)
+		assert.Equal(t, fiber.MIMEApplicationJSON, contentType)
+	})
+}
+
+func TestMarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "SmallUint", value: uint32(23), expected: "17"},
+		{name: "OneByteUint", value: uint32(24), expected: "1818"},
+		{name: "TwoByteUint", value: uint32(1000), expected: "1903e8"},
+		{name: "FourByteUint", value: uint32(1000000), expected: "1a000f4240"},
+		{name: "EmptyList", value: []*chaintracks.BlockHeader{}, expected: "80"},
+		{name: "NullEntry", value: []*chaintracks.BlockHeader{nil}, expected: "81f6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, ok := marshalCBOR(tt.value)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, hex.EncodeToString(b))
+		})
+	}
+
+	t.Run("NegativeVersion", func(t *testing.T) {
+		b := appendCBORHeader(nil, &chaintracks.BlockHeader{Header: &block.Header{Version: -2}})
+		assert.Contains(t, hex.EncodeToString(b), hex.EncodeToString([]byte("version"))+"21")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, ok := marshalCBOR("main")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,159 @@
+package fiber
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strconv"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Media types served by the export route
+const (
+	MIMEApplicationNDJSON = "application/x-ndjson"
+	MIMETextCSV           = "text/csv"
+)
+
+// exportChunk is how many headers the export route reads and flushes at a time
+const exportChunk = 1000
+
+// exportCSVHeader is the first line of a CSV export
+const exportCSVHeader = "height,hash,time,bits,nonce,merkleRoot\n"
+
+// HandleExportHeaders streams every main-chain header from the from query parameter (default 0) to the tip,
+// one record per header with its height, hash, time, bits, nonce and merkle root
+// Records are NDJSON, or CSV with a header line when the client accepts text/csv. The body is chunked and
+// flushed every exportChunk headers; it stops at the tip as of the request, or early should the chain
+// reorganize under it, so an interrupted or cut-short export resumes from the height after its last record.
+func (r *Routes) HandleExportHeaders(c *fiber.Ctx) error {
+	var from uint32
+	if fromStr := c.Query("from"); fromStr != "" {
+		height, err := strconv.ParseUint(fromStr, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: "Invalid from parameter",
+			})
+		}
+		from = uint32(height)
+	}
+
+	format := c.Accepts(MIMEApplicationNDJSON, MIMETextCSV)
+	if format == "" {
+		return c.Status(fiber.StatusNotAcceptable).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_ACCEPTABLE",
+			Description: "Export is served as " + MIMEApplicationNDJSON + " or " + MIMETextCSV,
+		})
+	}
+
+	ctx := c.UserContext()
+	tip := r.ct.GetHeight(ctx)
+	if from > tip {
+		return c.Status(fiber.StatusRequestedRangeNotSatisfiable).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_OUT_OF_RANGE",
+			Description: "Height " + strconv.FormatUint(uint64(from), 10) + " is beyond the chain tip at " + strconv.FormatUint(uint64(tip), 10),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, format)
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	c.Vary(fiber.HeaderAccept)
+	csv := format == MIMETextCSV
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if csv {
+			if _, err := w.WriteString(exportCSVHeader); err != nil {
+				return
+			}
+		}
+		var line []byte
+		_ = r.exportHeaders(ctx, from, tip, func(height uint32, header *block.Header, hash chainhash.Hash) error {
+			if csv {
+				line = appendCSVRecord(line[:0], height, header, hash)
+			} else {
+				line = appendNDJSONRecord(line[:0], height, header, hash)
+			}
+			_, err := w.Write(line)
+			return err
+		}, w.Flush)
+	})
+	return nil
+}
+
+// exportHeaders calls emit for each main-chain header from from to to, linked to the one before it, and flush
+// after every exportChunk headers
+// Backends implementing headerReader are read a chunk at a time; others are looked up per height.
+func (r *Routes) exportHeaders(ctx context.Context, from, to uint32, emit func(uint32, *block.Header, chainhash.Hash) error, flush func() error) error {
+	var buf bytes.Buffer
+	var prev chainhash.Hash
+	for start := uint64(from); start <= uint64(to); start += exportChunk {
+		count := uint32(min(exportChunk, uint64(to)-start+1)) //nolint:gosec // At most exportChunk
+		height := uint32(start)                               //nolint:gosec // start <= to
+
+		buf.Reset()
+		if err := r.writeHeaders(ctx, &buf, height, count); err != nil {
+			return err
+		}
+		raw := buf.Bytes()
+		for i := 0; i+block.HeaderSize <= len(raw); i += block.HeaderSize {
+			header, err := block.NewHeaderFromBytes(raw[i : i+block.HeaderSize])
+			if err != nil {
+				return err
+			}
+			h := height + uint32(i/block.HeaderSize) //nolint:gosec // Bounded by count
+			if h > from && header.PrevHash != prev {
+				// The chain reorganized between chunks; the client resumes from here
+				return flush()
+			}
+			prev = chainhash.DoubleHashH(raw[i : i+block.HeaderSize])
+			if err := emit(h, header, prev); err != nil {
+				return err
+			}
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		if len(raw) < int(count)*block.HeaderSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// appendNDJSONRecord appends one export record as a JSON line
+func appendNDJSONRecord(dst []byte, height uint32, header *block.Header, hash chainhash.Hash) []byte {
+	dst = append(dst, `{"height":`...)
+	dst = strconv.AppendUint(dst, uint64(height), 10)
+	dst = append(dst, `,"hash":"`...)
+	dst = append(dst, hash.String()...)
+	dst = append(dst, `","time":`...)
+	dst = strconv.AppendUint(dst, uint64(header.Timestamp), 10)
+	dst = append(dst, `,"bits":`...)
+	dst = strconv.AppendUint(dst, uint64(header.Bits), 10)
+	dst = append(dst, `,"nonce":`...)
+	dst = strconv.AppendUint(dst, uint64(header.Nonce), 10)
+	dst = append(dst, `,"merkleRoot":"`...)
+	dst = append(dst, header.MerkleRoot.String()...)
+	return append(dst, "\"}\n"...)
+}
+
+// appendCSVRecord appends one export record as a CSV line, in the column order of exportCSVHeader
+func appendCSVRecord(dst []byte, height uint32, header *block.Header, hash chainhash.Hash) []byte {
+	dst = strconv.AppendUint(dst, uint64(height), 10)
+	dst = append(dst, ',')
+	dst = append(dst, hash.String()...)
+	dst = append(dst, ',')
+	dst = strconv.AppendUint(dst, uint64(header.Timestamp), 10)
+	dst = append(dst, ',')
+	dst = strconv.AppendUint(dst, uint64(header.Bits), 10)
+	dst = append(dst, ',')
+	dst = strconv.AppendUint(dst, uint64(header.Nonce), 10)
+	dst = append(dst, ',')
+	dst = append(dst, header.MerkleRoot.String()...)
+	return append(dst, '\n')
+}
@@ -0,0 +1,127 @@
+package fiber
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// newLinkedChainStub serves a main chain of length headers, each linked to the one before by its real hash
+func newLinkedChainStub(length int) *chainStub {
+	s := &chainStub{}
+	var prev chainhash.Hash
+	for i := range length {
+		header := &block.Header{PrevHash: prev, Timestamp: 1231006505 + uint32(i), Bits: 0x1d00ffff, Nonce: uint32(i)} //nolint:gosec // Test heights are small
+		prev = header.Hash()
+		s.main = append(s.main, &chaintracks.BlockHeader{Header: header, Height: uint32(i), Hash: prev}) //nolint:gosec // Test heights are small
+	}
+	s.stubChaintracks = &stubChaintracks{tip: s.main[length-1]}
+	return s
+}
+
+// exportGet requests an export and returns status and the body's lines
+func exportGet(t *testing.T, app *fiber.App, path, accept string) (int, []string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode, strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+}
+
+func TestRoutesExportHeaders(t *testing.T) {
+	stub := newLinkedChainStub(2*exportChunk + 500)
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	t.Run("NDJSON", func(t *testing.T) {
+		status, lines := exportGet(t, app, "/v2/headers/export", "")
+		require.Equal(t, fiber.StatusOK, status)
+		require.Len(t, lines, len(stub.main))
+
+		for _, height := range []int{0, exportChunk, len(stub.main) - 1} {
+			var record struct {
+				Height     uint32         `json:"height"`
+				Hash       chainhash.Hash `json:"hash"`
+				Time       uint32         `json:"time"`
+				Bits       uint32         `json:"bits"`
+				Nonce      uint32         `json:"nonce"`
+				MerkleRoot chainhash.Hash `json:"merkleRoot"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(lines[height]), &record))
+			expected := stub.main[height]
+			assert.Equal(t, expected.Height, record.Height)
+			assert.Equal(t, expected.Hash, record.Hash)
+			assert.Equal(t, expected.Timestamp, record.Time)
+			assert.Equal(t, expected.Bits, record.Bits)
+			assert.Equal(t, expected.Nonce, record.Nonce)
+			assert.Equal(t, expected.MerkleRoot, record.MerkleRoot)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		status, lines := exportGet(t, app, "/v2/headers/export?from=2400", MIMETextCSV)
+		require.Equal(t, fiber.StatusOK, status)
+		require.Len(t, lines, len(stub.main)-2400+1)
+		assert.Equal(t, strings.TrimSuffix(exportCSVHeader, "\n"), lines[0])
+
+		header := stub.main[2400]
+		expected := strings.Join([]string{
+			"2400", header.Hash.String(), strconv.FormatUint(uint64(header.Timestamp), 10),
+			strconv.FormatUint(uint64(header.Bits), 10), "2400", header.MerkleRoot.String(),
+		}, ",")
+		assert.Equal(t, expected, lines[1])
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		accept         string
+		expectedStatus int
+	}{
+		{name: "InvalidFrom", path: "/v2/headers/export?from=x", expectedStatus: fiber.StatusBadRequest},
+		{name: "BeyondTip", path: "/v2/headers/export?from=5000", expectedStatus: fiber.StatusRequestedRangeNotSatisfiable},
+		{name: "NotAcceptable", path: "/v2/headers/export", accept: fiber.MIMEApplicationJSON, expectedStatus: fiber.StatusNotAcceptable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := exportGet(t, app, tt.path, tt.accept)
+			assert.Equal(t, tt.expectedStatus, status)
+		})
+	}
+}
+
+func TestRoutesExportHeadersStopsAtReorg(t *testing.T) {
+	stub := newLinkedChainStub(exportChunk + 10)
+	stub.main[exportChunk].PrevHash = chainhash.Hash{0xff}
+	routes := NewRoutes(stub)
+
+	var heights []uint32
+	flushes := 0
+	err := routes.exportHeaders(t.Context(), 0, stub.tip.Height, func(height uint32, _ *block.Header, _ chainhash.Hash) error {
+		heights = append(heights, height)
+		return nil
+	}, func() error {
+		flushes++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, heights, exportChunk, "the export ends before the header that no longer links")
+	assert.Equal(t, 2, flushes)
+}
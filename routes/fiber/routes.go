@@ -0,0 +1,791 @@
+// Package fiber provides embeddable Fiber routes for the chaintracks v2 API.
+// The routes work with any chaintracks.Chaintracks implementation, so applications can
+// mount them in their own Fiber app in front of an embedded ChainManager or a remote Client.
+package fiber
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// Route keys identify individual routes for per-route middleware
+// Each key is the route path relative to the router the routes are registered on
+const (
+	RouteNetwork          = "/network"
+	RouteHeight           = "/height"
+	RouteTipHash          = "/tip/hash"
+	RouteTipHeader        = "/tip/header"
+	RouteHeaderByHeight   = "/header/height/:height"
+	RouteHeaderByHash     = "/header/hash/:hash"
+	RouteHeaders          = "/headers"
+	RouteHeadersBackwards = "/headers/backwards/:hash"
+	RouteAnchor           = "/anchor/:blockHash"
+	RouteConfirmations    = "/confirmations/:hash"
+	RouteMainChain        = "/mainchain/:hash"
+	RouteChainWork        = "/chainwork/:height"
+	RouteVerifyRoots      = "/merkleroots/verify"
+	RouteVerifyBump       = "/verify/bump"
+	RouteHeadersByHashes  = "/headers/byHashes"
+	RouteHeadersRange     = "/headers/range"
+	RouteHeadersExport    = "/headers/export"
+)
+
+const (
+	// DefaultMaxHeaders is the default cap on the count accepted by the headers route, see WithMaxHeaders
+	DefaultMaxHeaders = 10000
+
+	// MaxHeadersBackwards caps the count accepted by the backwards headers route
+	MaxHeadersBackwards = 2000
+
+	// MaxHeadersRange caps the number of headers returned by the hash range route
+	MaxHeadersRange = 2000
+
+	// MaxHeadersByHashes caps the number of hashes accepted by the batch header lookup route
+	MaxHeadersByHashes = 1000
+
+	// MaxMerkleRootsPerRequest caps the batch size accepted by the merkle root verification route
+	MaxMerkleRootsPerRequest = 1000
+)
+
+// Response represents the standard API response format
+type Response struct {
+	Status      string      `json:"status"`
+	Value       interface{} `json:"value,omitempty"`
+	Code        string      `json:"code,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// Routes serves the chaintracks v2 API on top of a Chaintracks implementation
+type Routes struct {
+	ct              chaintracks.Chaintracks
+	maxHeaders      uint32 // Most headers one /headers request may ask for
+	cache           CachePolicy
+	middleware      []fiber.Handler
+	routeMiddleware map[string][]fiber.Handler
+}
+
+// Option configures Routes
+type Option func(*Routes)
+
+// WithMiddleware adds handlers that run before every route, in the order given
+func WithMiddleware(handlers ...fiber.Handler) Option {
+	return func(r *Routes) {
+		r.middleware = append(r.middleware, handlers...)
+	}
+}
+
+// WithRouteMiddleware adds handlers that run before a single route, after the global middleware
+// route is one of the Route* keys
+func WithRouteMiddleware(route string, handlers ...fiber.Handler) Option {
+	return func(r *Routes) {
+		r.routeMiddleware[route] = append(r.routeMiddleware[route], handlers...)
+	}
+}
+
+// WithMaxHeaders caps the count accepted by the headers route, 0 keeps DefaultMaxHeaders
+func WithMaxHeaders(n uint32) Option {
+	return func(r *Routes) {
+		if n > 0 {
+			r.maxHeaders = n
+		}
+	}
+}
+
+// WithCachePolicy sets the Cache-Control policy, zero fields keep the DefaultCachePolicy values
+func WithCachePolicy(policy CachePolicy) Option {
+	return func(r *Routes) {
+		r.cache = policy.withDefaults()
+	}
+}
+
+// NewRoutes creates routes backed by the given Chaintracks implementation
+func NewRoutes(ct chaintracks.Chaintracks, opts ...Option) *Routes {
+	r := &Routes{
+		ct:              ct,
+		maxHeaders:      DefaultMaxHeaders,
+		cache:           DefaultCachePolicy(),
+		routeMiddleware: make(map[string][]fiber.Handler),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Register mounts all routes on the given router (typically app.Group("/v2"))
+func (r *Routes) Register(router fiber.Router) {
+	r.add(router, RouteNetwork, r.HandleGetNetwork)
+	r.add(router, RouteHeight, r.HandleGetHeight)
+	r.add(router, RouteTipHash, r.HandleGetTipHash)
+	r.add(router, RouteTipHeader, r.HandleGetTipHeader)
+	r.add(router, RouteHeaderByHeight, r.HandleGetHeaderByHeight)
+	r.add(router, RouteHeaderByHash, r.HandleGetHeaderByHash)
+	r.add(router, RouteHeaders, r.HandleGetHeaders)
+	r.add(router, RouteHeadersBackwards, r.HandleGetHeadersBackwards)
+	r.add(router, RouteHeadersRange, r.HandleGetHeadersRange)
+	r.add(router, RouteHeadersExport, r.HandleExportHeaders)
+	r.add(router, RouteAnchor, r.HandleGetAnchor)
+	r.add(router, RouteConfirmations, r.HandleGetConfirmations)
+	r.add(router, RouteMainChain, r.HandleGetMainChain)
+	r.add(router, RouteChainWork, r.HandleGetChainWork)
+	router.Post(RouteVerifyRoots, r.chain(RouteVerifyRoots, r.HandleVerifyMerkleRoots)...)
+	router.Post(RouteVerifyBump, r.chain(RouteVerifyBump, r.HandleVerifyBump)...)
+	router.Post(RouteHeadersByHashes, r.chain(RouteHeadersByHashes, r.HandleGetHeadersByHashes)...)
+}
+
+// add registers a GET route wrapped in the global and per-route middleware chains
+func (r *Routes) add(router fiber.Router, route string, handler fiber.Handler) {
+	router.Get(route, r.chain(route, handler)...)
+}
+
+// chain returns the global and per-route middleware followed by the handler
+func (r *Routes) chain(route string, handler fiber.Handler) []fiber.Handler {
+	chain := make([]fiber.Handler, 0, len(r.middleware)+len(r.routeMiddleware[route])+1)
+	chain = append(chain, r.middleware...)
+	chain = append(chain, r.routeMiddleware[route]...)
+	return append(chain, handler)
+}
+
+// HandleGetNetwork returns the network name
+func (r *Routes) HandleGetNetwork(c *fiber.Ctx) error {
+	network, err := r.ct.GetNetwork(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(Response{
+			Status: "error",
+			Value:  err.Error(),
+		})
+	}
+	return c.JSON(Response{
+		Status: "success",
+		Value:  network,
+	})
+}
+
+// HandleGetHeight returns the current blockchain height
+func (r *Routes) HandleGetHeight(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, r.cache.ForChainHeight())
+	return sendValue(c, negotiate(c), r.ct.GetHeight(c.UserContext()))
+}
+
+// HandleGetTipHash returns the chain tip hash
+func (r *Routes) HandleGetTipHash(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+
+	tip := r.ct.GetTip(c.UserContext())
+	if tip == nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NO_TIP",
+			Description: "Chain tip not found",
+		})
+	}
+
+	hash := tip.Header.Hash()
+	return sendValue(c, negotiate(c), &hash)
+}
+
+// HandleGetTipHeader returns the full chain tip header
+func (r *Routes) HandleGetTipHeader(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+
+	tip := r.ct.GetTip(c.UserContext())
+	if tip == nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NO_TIP",
+			Description: "Chain tip not found",
+		})
+	}
+
+	return sendValue(c, negotiate(c), tip)
+}
+
+// HandleGetHeaderByHeight returns a header by height
+// With include=mtp the header carries its median time past as medianTime.
+func (r *Routes) HandleGetHeaderByHeight(c *fiber.Ctx) error {
+	heightStr := c.Params("height")
+	if heightStr == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Missing height parameter",
+		})
+	}
+
+	height, err := strconv.ParseUint(heightStr, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid height parameter",
+		})
+	}
+
+	tip := r.ct.GetHeight(c.UserContext())
+	r.setCache(c, RouteHeaderByHeight, uint32(height), tip)
+
+	header, err := r.ct.GetHeaderByHeight(c.UserContext(), uint32(height))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_FOUND",
+			Description: "Header not found at height " + heightStr,
+		})
+	}
+	format := negotiate(c)
+	tag := formatTag(format)
+	includeMTP := slices.Contains(strings.Split(c.Query("include"), ","), "mtp")
+	if includeMTP {
+		tag = strings.TrimPrefix(tag+"-mtp", "-")
+	}
+	if notModified(c, headerETag(header.Hash, tag)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	if includeMTP {
+		median, err := chaintracks.MedianTimePast(c.UserContext(), r.ct, header)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(Response{
+				Status: "error",
+				Value:  err.Error(),
+			})
+		}
+		withMTP := *header
+		withMTP.MedianTime = median
+		header = &withMTP
+	}
+	return sendValue(c, format, header)
+}
+
+// HandleGetHeaderByHash returns a header by hash
+func (r *Routes) HandleGetHeaderByHash(c *fiber.Ctx) error {
+	hashStr := c.Params("hash")
+	if hashStr == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Missing hash parameter",
+		})
+	}
+
+	hash, err := chainhash.NewHashFromHex(hashStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid hash parameter",
+		})
+	}
+
+	header, err := r.ct.GetHeaderByHash(c.UserContext(), hash)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_FOUND",
+			Description: "Header not found for hash " + hashStr,
+		})
+	}
+
+	tip := r.ct.GetHeight(c.UserContext())
+	r.setCache(c, RouteHeaderByHash, header.Height, tip)
+	format := negotiate(c)
+	if notModified(c, headerETag(header.Hash, formatTag(format))) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return sendValue(c, format, header)
+}
+
+// HandleGetHeaders returns multiple headers as concatenated hex, or as raw 80-byte headers when the client
+// accepts application/octet-stream
+// The body is streamed with a Content-Length, so a large response is never built in memory. A count above the
+// configured maximum is rejected, and a height beyond the tip is answered with 416. The ETag follows the last
+// header returned, so a conditional request is answered with 304 until the range changes.
+func (r *Routes) HandleGetHeaders(c *fiber.Ctx) error {
+	heightStr := c.Query("height")
+	countStr := c.Query("count")
+
+	if heightStr == "" || countStr == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Missing height or count parameter",
+		})
+	}
+
+	height, err := strconv.ParseUint(heightStr, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid height parameter",
+		})
+	}
+
+	count, err := strconv.ParseUint(countStr, 10, 32)
+	if err != nil || count == 0 || count > uint64(r.maxHeaders) {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid count parameter (1-" + strconv.FormatUint(uint64(r.maxHeaders), 10) + ")",
+		})
+	}
+
+	ctx := c.UserContext()
+	tip := r.ct.GetHeight(ctx)
+	if uint32(height) > tip {
+		return c.Status(fiber.StatusRequestedRangeNotSatisfiable).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_OUT_OF_RANGE",
+			Description: "Height " + heightStr + " is beyond the chain tip at " + strconv.FormatUint(uint64(tip), 10),
+		})
+	}
+
+	r.setCache(c, RouteHeaders, uint32(height), tip)
+
+	start, n := uint32(height), min(uint32(count), tip-uint32(height)+1)
+	binary := c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMEOctetStream) == fiber.MIMEOctetStream
+	c.Vary(fiber.HeaderAccept)
+
+	// The last header's hash pins every header before it, so it tags the whole range
+	if last, err := r.ct.GetHeaderByHeight(ctx, start+n-1); err == nil {
+		format := ""
+		if binary {
+			format = "bin"
+		}
+		if notModified(c, headerETag(last.Hash, format)) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+	}
+
+	if binary {
+		c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+		streamBody(c, int(n)*block.HeaderSize, func(w io.Writer) error {
+			return r.writeHeaders(ctx, w, start, n)
+		})
+		return nil
+	}
+
+	const prefix, suffix = `{"status":"success","value":"`, `"}`
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	streamBody(c, len(prefix)+int(n)*2*block.HeaderSize+len(suffix), func(w io.Writer) error {
+		if _, err := io.WriteString(w, prefix); err != nil {
+			return err
+		}
+		if err := r.writeHeaders(ctx, hex.NewEncoder(w), start, n); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, suffix)
+		return err
+	})
+	return nil
+}
+
+// headerETag returns a strong entity tag for a response pinned by a header hash, with format telling apart
+// representations of the same headers
+func headerETag(hash chainhash.Hash, format string) string {
+	if format != "" {
+		return `"` + hash.String() + "-" + format + `"`
+	}
+	return `"` + hash.String() + `"`
+}
+
+// notModified sets the response ETag and reports whether the client's If-None-Match already matches it, in
+// which case the caller answers 304 without a body
+func notModified(c *fiber.Ctx, etag string) bool {
+	c.Set(fiber.HeaderETag, etag)
+	return c.Fresh()
+}
+
+// streamBody sets the response body to what write produces, piped to the client as it is sent
+// The body is declared as size bytes; should write come up short, as when the chain reorganizes mid-response,
+// the connection is dropped rather than the client being served a truncated body. write runs after the
+// handler has returned, so it must not use the fiber.Ctx.
+func streamBody(c *fiber.Ctx, size int, write func(w io.Writer) error) {
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(write(pw))
+	}()
+	c.Response().SetBodyStream(pr, size)
+}
+
+// headerReader is implemented by backends that serialize main-chain headers straight from their store,
+// such as chaintracks.ChainManager
+type headerReader interface {
+	ReadHeadersInto(w io.Writer, height, count uint32) (uint32, error)
+}
+
+// writeHeaders writes up to count raw main-chain headers from height to w, stopping at the tip
+// Backends implementing headerReader are read without a lookup or allocation per header.
+func (r *Routes) writeHeaders(ctx context.Context, w io.Writer, height, count uint32) error {
+	if reader, ok := r.ct.(headerReader); ok {
+		_, err := reader.ReadHeadersInto(w, height, count)
+		return err
+	}
+
+	for i := uint32(0); i < count; i++ {
+		header, err := r.ct.GetHeaderByHeight(ctx, height+i)
+		if err != nil {
+			break
+		}
+		if _, err := w.Write(header.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleGetHeadersBackwards returns headers from a hash back through its ancestors, newest first
+func (r *Routes) HandleGetHeadersBackwards(c *fiber.Ctx) error {
+	hash, err := chainhash.NewHashFromHex(c.Params("hash"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid hash parameter",
+		})
+	}
+
+	count := uint64(100)
+	if countStr := c.Query("count"); countStr != "" {
+		count, err = strconv.ParseUint(countStr, 10, 32)
+		if err != nil || count == 0 || count > MaxHeadersBackwards {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: "Invalid count parameter (1-" + strconv.Itoa(MaxHeadersBackwards) + ")",
+			})
+		}
+	}
+
+	headers, err := r.ct.GetHeadersBackwards(c.UserContext(), hash, uint32(count))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_FOUND",
+			Description: "Header not found for hash " + hash.String(),
+		})
+	}
+
+	tip := r.ct.GetHeight(c.UserContext())
+	newest := tip
+	if len(headers) > 0 {
+		newest = headers[0].Height
+	}
+	r.setCache(c, RouteHeadersBackwards, newest, tip)
+
+	return sendValue(c, negotiate(c), headers)
+}
+
+// HandleGetHeadersRange returns the main-chain headers between two block hashes, inclusive, oldest first
+func (r *Routes) HandleGetHeadersRange(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var bounds [2]*chaintracks.BlockHeader
+	for i, param := range []string{"from", "to"} {
+		hashStr := c.Query(param)
+		hash, err := chainhash.NewHashFromHex(hashStr)
+		if hashStr == "" || err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: "Invalid " + param + " parameter",
+			})
+		}
+
+		header, err := r.ct.GetHeaderByHash(ctx, hash)
+		if err == nil {
+			// Orphaned blocks have no place in a main-chain range
+			var main *chaintracks.BlockHeader
+			main, err = r.ct.GetHeaderByHeight(ctx, header.Height)
+			if err == nil && main.Hash != header.Hash {
+				err = chaintracks.ErrHeaderNotFound
+			}
+		}
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_NOT_FOUND",
+				Description: "Header not found on main chain for hash " + hash.String(),
+			})
+		}
+		bounds[i] = header
+	}
+
+	from, to := bounds[0], bounds[1]
+	if from.Height > to.Height {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "from must not be above to",
+		})
+	}
+	if to.Height-from.Height >= MaxHeadersRange {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Range too large (max " + strconv.Itoa(MaxHeadersRange) + " headers)",
+		})
+	}
+
+	headers := make([]*chaintracks.BlockHeader, 0, to.Height-from.Height+1)
+	for height := from.Height; height <= to.Height; height++ {
+		header, err := r.ct.GetHeaderByHeight(ctx, height)
+		if err != nil {
+			// The chain reorganized under us; report what is consistent
+			break
+		}
+		headers = append(headers, header)
+	}
+
+	tip := r.ct.GetHeight(ctx)
+	r.setCache(c, RouteHeadersRange, to.Height, tip)
+
+	return sendValue(c, negotiate(c), headers)
+}
+
+// HandleGetAnchor returns height, merkle root, confirmations, chainwork and raw header for a block in one payload
+func (r *Routes) HandleGetAnchor(c *fiber.Ctx) error {
+	hash, err := chainhash.NewHashFromHex(c.Params("blockHash"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid blockHash parameter",
+		})
+	}
+
+	anchor, err := chaintracks.GetAnchor(c.UserContext(), r.ct, hash)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_FOUND",
+			Description: "Header not found for hash " + hash.String(),
+		})
+	}
+
+	// Confirmations change with every block
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	return c.JSON(Response{
+		Status: "success",
+		Value:  anchor,
+	})
+}
+
+// HandleGetConfirmations returns the height, confirmation count and main-chain status of a block
+func (r *Routes) HandleGetConfirmations(c *fiber.Ctx) error {
+	hash, err := chainhash.NewHashFromHex(c.Params("hash"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid hash parameter",
+		})
+	}
+
+	confirmations, err := chaintracks.GetConfirmations(c.UserContext(), r.ct, hash)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_FOUND",
+			Description: "Header not found for hash " + hash.String(),
+		})
+	}
+
+	// Confirmations change with every block
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	return c.JSON(Response{
+		Status: "success",
+		Value:  confirmations,
+	})
+}
+
+// HandleGetMainChain reports whether a block is active, a known orphan or unknown
+// Unknown blocks are a successful answer, not a 404, so callers can poll a hash they depend on
+func (r *Routes) HandleGetMainChain(c *fiber.Ctx) error {
+	hash, err := chainhash.NewHashFromHex(c.Params("hash"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid hash parameter",
+		})
+	}
+
+	state, err := chaintracks.GetBlockChainState(c.UserContext(), r.ct, hash)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(Response{
+			Status: "error",
+			Value:  err.Error(),
+		})
+	}
+
+	// A reorg can change the answer at any time
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	return c.JSON(Response{
+		Status: "success",
+		Value:  state,
+	})
+}
+
+// HandleGetChainWork returns the cumulative main-chain work at a height
+func (r *Routes) HandleGetChainWork(c *fiber.Ctx) error {
+	height, err := strconv.ParseUint(c.Params("height"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid height parameter",
+		})
+	}
+
+	work, err := chaintracks.GetChainWorkAtHeight(c.UserContext(), r.ct, uint32(height))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_NOT_FOUND",
+			Description: "Chainwork not found for height " + strconv.FormatUint(height, 10),
+		})
+	}
+
+	tip := r.ct.GetHeight(c.UserContext())
+	r.setCache(c, RouteChainWork, uint32(height), tip)
+
+	return c.JSON(Response{
+		Status: "success",
+		Value:  work,
+	})
+}
+
+// HandleVerifyMerkleRoots checks a batch of {merkleRoot, blockHeight} pairs against the main chain in one round trip
+func (r *Routes) HandleVerifyMerkleRoots(c *fiber.Ctx) error {
+	var checks []chaintracks.MerkleRootCheck
+	if err := c.BodyParser(&checks); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Request body must be an array of {merkleRoot, blockHeight}",
+		})
+	}
+	if len(checks) > MaxMerkleRootsPerRequest {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Too many merkle roots (max " + strconv.Itoa(MaxMerkleRootsPerRequest) + ")",
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	return c.JSON(Response{
+		Status: "success",
+		Value:  chaintracks.VerifyMerkleRoots(c.UserContext(), r.ct, checks),
+	})
+}
+
+// verifyBumpRequest is the body of the BUMP verification route
+type verifyBumpRequest struct {
+	Bump        string  `json:"bump"`                  // BRC-74 merkle path as hex
+	BlockHeight *uint32 `json:"blockHeight,omitempty"` // Optional, must match the height in the path
+}
+
+// HandleVerifyBump checks a BUMP against the merkle root of the main chain block at its height
+// The result carries the verification status; malformed paths, or a blockHeight other than the path's, are
+// rejected with 400.
+func (r *Routes) HandleVerifyBump(c *fiber.Ctx) error {
+	var req verifyBumpRequest
+	if err := c.BodyParser(&req); err != nil || req.Bump == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Request body must be {bump, blockHeight} with the BUMP as hex",
+		})
+	}
+
+	bump, err := transaction.NewMerklePathFromHex(req.Bump)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid BUMP: " + err.Error(),
+		})
+	}
+	if req.BlockHeight != nil && *req.BlockHeight != bump.BlockHeight {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "blockHeight does not match the BUMP height " + strconv.FormatUint(uint64(bump.BlockHeight), 10),
+		})
+	}
+
+	verification, err := chaintracks.VerifyBump(c.UserContext(), r.ct, bump)
+	if errors.Is(err, chaintracks.ErrInvalidBump) {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(Response{
+			Status: "error",
+			Value:  err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	return c.JSON(Response{
+		Status: "success",
+		Value:  verification,
+	})
+}
+
+// HandleGetHeadersByHashes returns the headers for an array of hashes in request order
+// Unknown hashes yield null entries so callers can match results by index
+func (r *Routes) HandleGetHeadersByHashes(c *fiber.Ctx) error {
+	var hashStrs []string
+	if err := c.BodyParser(&hashStrs); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Request body must be an array of block hashes",
+		})
+	}
+	if len(hashStrs) > MaxHeadersByHashes {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Too many hashes (max " + strconv.Itoa(MaxHeadersByHashes) + ")",
+		})
+	}
+
+	ctx := c.UserContext()
+	headers := make([]*chaintracks.BlockHeader, len(hashStrs))
+	for i, hashStr := range hashStrs {
+		hash, err := chainhash.NewHashFromHex(hashStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(Response{
+				Status:      "error",
+				Code:        "ERR_INVALID_PARAMS",
+				Description: "Invalid hash at index " + strconv.Itoa(i),
+			})
+		}
+		if header, err := r.ct.GetHeaderByHash(ctx, hash); err == nil {
+			headers[i] = header
+		}
+	}
+
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	return sendValue(c, negotiate(c), headers)
+}
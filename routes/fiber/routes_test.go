@@ -0,0 +1,733 @@
+package fiber
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/block"
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// stubChaintracks serves a fixed single-header chain
+type stubChaintracks struct {
+	tip *chaintracks.BlockHeader
+}
+
+func newStubChaintracks() *stubChaintracks {
+	return &stubChaintracks{tip: &chaintracks.BlockHeader{Header: &block.Header{}, Height: 0, Hash: chainhash.Hash{1}}}
+}
+
+func (s *stubChaintracks) IsValidRootForHeight(_ context.Context, root *chainhash.Hash, _ uint32) (bool, error) {
+	return s.tip.MerkleRoot.IsEqual(root), nil
+}
+
+func (s *stubChaintracks) CurrentHeight(_ context.Context) (uint32, error) { return s.tip.Height, nil }
+
+func (s *stubChaintracks) Start(_ context.Context) (<-chan *chaintracks.BlockHeader, error) {
+	return nil, nil //nolint:nilnil // Stub does not stream
+}
+func (s *stubChaintracks) Stop() error                                       { return nil }
+func (s *stubChaintracks) GetHeight(_ context.Context) uint32                { return s.tip.Height }
+func (s *stubChaintracks) GetTip(_ context.Context) *chaintracks.BlockHeader { return s.tip }
+func (s *stubChaintracks) GetNetwork(_ context.Context) (string, error)      { return "main", nil }
+
+func (s *stubChaintracks) GetHeaderByHeight(_ context.Context, height uint32) (*chaintracks.BlockHeader, error) {
+	if height != s.tip.Height {
+		return nil, chaintracks.ErrHeaderNotFound
+	}
+	return s.tip, nil
+}
+
+func (s *stubChaintracks) GetHeaderByHash(_ context.Context, hash *chainhash.Hash) (*chaintracks.BlockHeader, error) {
+	if !hash.IsEqual(&s.tip.Hash) {
+		return nil, chaintracks.ErrHeaderNotFound
+	}
+	return s.tip, nil
+}
+
+func (s *stubChaintracks) GetHeadersBackwards(ctx context.Context, hash *chainhash.Hash, _ uint32) ([]*chaintracks.BlockHeader, error) {
+	header, err := s.GetHeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return []*chaintracks.BlockHeader{header}, nil
+}
+
+// get performs a GET request against the app and returns status and body
+func get(t *testing.T, app *fiber.App, path string) (int, string) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode, string(body)
+}
+
+// post performs a JSON POST request against the app and returns status and body
+func post(t *testing.T, app *fiber.App, path, body string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode, string(respBody)
+}
+
+func TestRoutesRegister(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectContains string
+	}{
+		{name: "Network", path: "/v2/network", expectedStatus: 200, expectContains: `"value":"main"`},
+		{name: "Height", path: "/v2/height", expectedStatus: 200, expectContains: `"value":0`},
+		{name: "TipHeader", path: "/v2/tip/header", expectedStatus: 200, expectContains: `"height":0`},
+		{name: "HeaderByHeight", path: "/v2/header/height/0", expectedStatus: 200, expectContains: `"status":"success"`},
+		{name: "HeaderByHeightNotFound", path: "/v2/header/height/5", expectedStatus: 404, expectContains: "ERR_NOT_FOUND"},
+		{name: "HeadersMissingParams", path: "/v2/headers", expectedStatus: 400, expectContains: "ERR_INVALID_PARAMS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Contains(t, body, tt.expectContains)
+		})
+	}
+}
+
+func TestRoutesMiddleware(t *testing.T) {
+	var calls []string
+	record := func(name string) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			calls = append(calls, name)
+			return c.Next()
+		}
+	}
+	deny := func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusUnauthorized).SendString("denied")
+	}
+
+	app := fiber.New()
+	NewRoutes(newStubChaintracks(),
+		WithMiddleware(record("global-1"), record("global-2")),
+		WithRouteMiddleware(RouteHeight, record("height")),
+		WithRouteMiddleware(RouteNetwork, deny),
+	).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedCalls  []string
+	}{
+		{
+			name:           "GlobalThenRouteMiddleware",
+			path:           "/v2/height",
+			expectedStatus: 200,
+			expectedCalls:  []string{"global-1", "global-2", "height"},
+		},
+		{
+			name:           "RouteMiddlewareOnlyAppliesToItsRoute",
+			path:           "/v2/tip/hash",
+			expectedStatus: 200,
+			expectedCalls:  []string{"global-1", "global-2"},
+		},
+		{
+			name:           "MiddlewareCanShortCircuit",
+			path:           "/v2/network",
+			expectedStatus: 401,
+			expectedCalls:  []string{"global-1", "global-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, tt.expectedCalls, calls)
+			if status == 401 {
+				assert.True(t, strings.Contains(body, "denied"))
+			}
+		})
+	}
+}
+
+// readerChaintracks is a stubChaintracks that also streams headers, recording the reads it serves
+type readerChaintracks struct {
+	*stubChaintracks
+	reads int
+}
+
+func (s *readerChaintracks) ReadHeadersInto(w io.Writer, height, count uint32) (uint32, error) {
+	s.reads++
+	if height != s.tip.Height || count == 0 {
+		return 0, nil
+	}
+	_, err := w.Write(s.tip.Bytes())
+	return 1, err
+}
+
+func TestRoutesGetHeaders(t *testing.T) {
+	stub := newStubChaintracks()
+	tipHex := hex.EncodeToString(stub.tip.Bytes())
+	reader := &readerChaintracks{stubChaintracks: newStubChaintracks()}
+
+	tests := []struct {
+		name         string
+		ct           chaintracks.Chaintracks
+		accept       string
+		expectedType string
+		expectedBody string
+	}{
+		{name: "Hex", ct: stub, expectedType: fiber.MIMEApplicationJSON, expectedBody: `"value":"` + tipHex + `"`},
+		{name: "Binary", ct: stub, accept: fiber.MIMEOctetStream, expectedType: fiber.MIMEOctetStream, expectedBody: string(stub.tip.Bytes())},
+		{name: "HexFromReader", ct: reader, expectedType: fiber.MIMEApplicationJSON, expectedBody: `"value":"` + tipHex + `"`},
+		{name: "BinaryFromReader", ct: reader, accept: fiber.MIMEOctetStream, expectedType: fiber.MIMEOctetStream, expectedBody: string(stub.tip.Bytes())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			NewRoutes(tt.ct).Register(app.Group("/v2"))
+
+			req := httptest.NewRequest("GET", "/v2/headers?height=0&count=5", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Contains(t, resp.Header.Get("Content-Type"), tt.expectedType)
+			if tt.accept == "" {
+				assert.Contains(t, string(body), tt.expectedBody)
+			} else {
+				assert.Equal(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+	assert.Equal(t, 2, reader.reads, "a backend that streams headers is read directly")
+}
+
+func TestRoutesGetHeadersLimits(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks(), WithMaxHeaders(3)).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedLength string
+	}{
+		{name: "ClampedToTip", path: "/v2/headers?height=0&count=3", expectedStatus: fiber.StatusOK, expectedLength: "80"},
+		{name: "ZeroCount", path: "/v2/headers?height=0&count=0", expectedStatus: fiber.StatusBadRequest},
+		{name: "CountAboveMax", path: "/v2/headers?height=0&count=4", expectedStatus: fiber.StatusBadRequest},
+		{name: "BeyondTip", path: "/v2/headers?height=1&count=1", expectedStatus: fiber.StatusRequestedRangeNotSatisfiable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("Accept", fiber.MIMEOctetStream)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedLength != "" {
+				assert.Equal(t, tt.expectedLength, resp.Header.Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestRoutesConditionalRequests(t *testing.T) {
+	stub := newChainStub(5)
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	request := func(t *testing.T, path, accept, ifNoneMatch string) (int, string, string) {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode, resp.Header.Get("ETag"), string(body)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		accept       string
+		expectedETag string
+	}{
+		{name: "HeaderByHeight", path: "/v2/header/height/2", expectedETag: `"` + stub.main[2].Hash.String() + `"`},
+		{name: "HeaderByHash", path: "/v2/header/hash/" + stub.main[2].Hash.String(), expectedETag: `"` + stub.main[2].Hash.String() + `"`},
+		{name: "HeaderByHeightProtobuf", path: "/v2/header/height/2", accept: MIMEApplicationProtobuf, expectedETag: `"` + stub.main[2].Hash.String() + `-pb"`},
+		{name: "HeadersHex", path: "/v2/headers?height=1&count=3", expectedETag: `"` + stub.main[3].Hash.String() + `"`},
+		{name: "HeadersBinary", path: "/v2/headers?height=1&count=3", accept: fiber.MIMEOctetStream, expectedETag: `"` + stub.main[3].Hash.String() + `-bin"`},
+		{name: "HeadersClampedToTip", path: "/v2/headers?height=3&count=10", expectedETag: `"` + stub.main[4].Hash.String() + `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, etag, _ := request(t, tt.path, tt.accept, "")
+			require.Equal(t, fiber.StatusOK, status)
+			assert.Equal(t, tt.expectedETag, etag)
+
+			status, _, body := request(t, tt.path, tt.accept, etag)
+			assert.Equal(t, fiber.StatusNotModified, status)
+			assert.Empty(t, body)
+
+			status, _, body = request(t, tt.path, tt.accept, `"stale"`)
+			assert.Equal(t, fiber.StatusOK, status)
+			assert.NotEmpty(t, body)
+		})
+	}
+}
+
+func TestRoutesGetHeadersBackwards(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).Register(app.Group("/v2"))
+
+	tipHash := chainhash.Hash{1}.String()
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "ReturnsHeadersForKnownHash", path: "/v2/headers/backwards/" + tipHash + "?count=5", expectedStatus: 200},
+		{name: "DefaultsCount", path: "/v2/headers/backwards/" + tipHash, expectedStatus: 200},
+		{name: "UnknownHashIsNotFound", path: "/v2/headers/backwards/" + chainhash.Hash{2}.String(), expectedStatus: 404},
+		{name: "RejectsInvalidHash", path: "/v2/headers/backwards/xyz", expectedStatus: 400},
+		{name: "RejectsZeroCount", path: "/v2/headers/backwards/" + tipHash + "?count=0", expectedStatus: 400},
+		{name: "RejectsCountAboveMax", path: "/v2/headers/backwards/" + tipHash + "?count=2001", expectedStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			if status == 200 {
+				assert.Contains(t, body, `"status":"success"`)
+				assert.Contains(t, body, tipHash)
+			}
+		})
+	}
+}
+
+func TestRoutesGetAnchor(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "ReturnsAnchorForKnownHash", path: "/v2/anchor/" + chainhash.Hash{1}.String(), expectedStatus: 200},
+		{name: "UnknownHashIsNotFound", path: "/v2/anchor/" + chainhash.Hash{2}.String(), expectedStatus: 404},
+		{name: "RejectsInvalidHash", path: "/v2/anchor/xyz", expectedStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			if status == 200 {
+				assert.Contains(t, body, `"confirmations":1`)
+				assert.Contains(t, body, `"merkleRoot":"`+chainhash.Hash{}.String()+`"`)
+				assert.Contains(t, body, `"headerHex":"`)
+			}
+		})
+	}
+}
+
+func TestRoutesGetConfirmations(t *testing.T) {
+	stub := newChainStub(5)
+	stub.orphans = append(stub.orphans, &chaintracks.BlockHeader{Header: &block.Header{}, Height: 3, Hash: chainhash.Hash{0xff}})
+
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectContains string
+	}{
+		{name: "MainChainBlock", path: "/v2/confirmations/" + stub.main[2].Hash.String(), expectedStatus: 200, expectContains: `{"height":2,"confirmations":3,"isOnMainChain":true}`},
+		{name: "Orphan", path: "/v2/confirmations/" + stub.orphans[0].Hash.String(), expectedStatus: 200, expectContains: `{"height":3,"confirmations":0,"isOnMainChain":false}`},
+		{name: "UnknownHashIsNotFound", path: "/v2/confirmations/" + chainhash.Hash{0xee}.String(), expectedStatus: 404, expectContains: "ERR_NOT_FOUND"},
+		{name: "RejectsInvalidHash", path: "/v2/confirmations/xyz", expectedStatus: 400, expectContains: "ERR_INVALID_PARAMS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			assert.Contains(t, body, tt.expectContains)
+		})
+	}
+}
+
+func TestRoutesGetMainChain(t *testing.T) {
+	stub := newChainStub(5)
+	stub.orphans = append(stub.orphans, &chaintracks.BlockHeader{Header: &block.Header{}, Height: 3, Hash: chainhash.Hash{0xff}})
+
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectContains string
+	}{
+		{name: "Active", path: "/v2/mainchain/" + stub.main[2].Hash.String(), expectedStatus: 200, expectContains: `"state":"active","height":2`},
+		{name: "Orphan", path: "/v2/mainchain/" + stub.orphans[0].Hash.String(), expectedStatus: 200, expectContains: `"state":"orphan","height":3`},
+		{name: "Unknown", path: "/v2/mainchain/" + chainhash.Hash{0xee}.String(), expectedStatus: 200, expectContains: `"state":"unknown","height":0`},
+		{name: "RejectsInvalidHash", path: "/v2/mainchain/xyz", expectedStatus: 400, expectContains: "ERR_INVALID_PARAMS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			assert.Contains(t, body, tt.expectContains)
+		})
+	}
+}
+
+func TestRoutesGetChainWork(t *testing.T) {
+	stub := newChainStub(3)
+	for i, header := range stub.main[:2] {
+		header.ChainWork = big.NewInt(int64(i + 1))
+	}
+
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectContains string
+	}{
+		{name: "ReturnsHexWork", path: "/v2/chainwork/1", expectedStatus: 200, expectContains: `"chainWork":"` + chaintracks.ChainWorkToHex(big.NewInt(2)) + `"`},
+		{name: "UntrackedWorkIsNotFound", path: "/v2/chainwork/2", expectedStatus: 404, expectContains: "ERR_NOT_FOUND"},
+		{name: "AboveTipIsNotFound", path: "/v2/chainwork/9", expectedStatus: 404, expectContains: "ERR_NOT_FOUND"},
+		{name: "RejectsInvalidHeight", path: "/v2/chainwork/abc", expectedStatus: 400, expectContains: "ERR_INVALID_PARAMS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			assert.Contains(t, body, tt.expectContains)
+		})
+	}
+}
+
+func TestRoutesVerifyMerkleRoots(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).Register(app.Group("/v2"))
+
+	validRoot := chainhash.Hash{}.String()
+	tooMany := "[" + strings.Repeat(`{"merkleRoot":"`+validRoot+`","blockHeight":0},`, MaxMerkleRootsPerRequest) +
+		`{"merkleRoot":"` + validRoot + `","blockHeight":0}]`
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectContains []string
+	}{
+		{
+			name:           "ReturnsPerItemStatus",
+			body:           `[{"merkleRoot":"` + validRoot + `","blockHeight":0},{"merkleRoot":"` + validRoot + `","blockHeight":7}]`,
+			expectedStatus: 200,
+			expectContains: []string{`"state":"UNABLE_TO_VERIFY"`, `"status":"CONFIRMED"`, `"status":"UNABLE_TO_VERIFY"`},
+		},
+		{
+			name:           "RejectsMalformedBody",
+			body:           `{"merkleRoot":`,
+			expectedStatus: 400,
+			expectContains: []string{"ERR_INVALID_PARAMS"},
+		},
+		{
+			name:           "RejectsOversizedBatch",
+			body:           tooMany,
+			expectedStatus: 400,
+			expectContains: []string{"Too many merkle roots"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := post(t, app, "/v2/merkleroots/verify", tt.body)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			for _, expected := range tt.expectContains {
+				assert.Contains(t, body, expected)
+			}
+		})
+	}
+}
+
+func TestRoutesVerifyBump(t *testing.T) {
+	isTxid := true
+	a, b := chainhash.Hash{0xa}, chainhash.Hash{0xb}
+	bumpHex := transaction.NewMerklePath(1, [][]*transaction.PathElement{{
+		{Offset: 0, Hash: &a, Txid: &isTxid},
+		{Offset: 1, Hash: &b, Txid: &isTxid},
+	}}).Hex()
+	aboveTipHex := transaction.NewMerklePath(9, [][]*transaction.PathElement{{
+		{Offset: 0, Hash: &a, Txid: &isTxid},
+		{Offset: 1, Hash: &b, Txid: &isTxid},
+	}}).Hex()
+
+	stub := newChainStub(3)
+	stub.main[1].Header = &block.Header{MerkleRoot: *transaction.MerkleTreeParent(&a, &b)}
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectContains []string
+	}{
+		{
+			name:           "Confirmed",
+			body:           `{"bump":"` + bumpHex + `","blockHeight":1}`,
+			expectedStatus: 200,
+			expectContains: []string{`"status":"CONFIRMED"`, `"blockHash":"` + stub.main[1].Hash.String() + `"`},
+		},
+		{
+			name:           "HeightOptional",
+			body:           `{"bump":"` + bumpHex + `"}`,
+			expectedStatus: 200,
+			expectContains: []string{`"status":"CONFIRMED"`},
+		},
+		{
+			name:           "AboveTip",
+			body:           `{"bump":"` + aboveTipHex + `"}`,
+			expectedStatus: 200,
+			expectContains: []string{`"status":"UNABLE_TO_VERIFY"`},
+		},
+		{
+			name:           "HeightMismatch",
+			body:           `{"bump":"` + bumpHex + `","blockHeight":2}`,
+			expectedStatus: 400,
+			expectContains: []string{"ERR_INVALID_PARAMS", "BUMP height 1"},
+		},
+		{
+			name:           "InvalidHex",
+			body:           `{"bump":"zz"}`,
+			expectedStatus: 400,
+			expectContains: []string{"Invalid BUMP"},
+		},
+		{
+			name:           "MissingBump",
+			body:           `{}`,
+			expectedStatus: 400,
+			expectContains: []string{"ERR_INVALID_PARAMS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := post(t, app, "/v2/verify/bump", tt.body)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			for _, expected := range tt.expectContains {
+				assert.Contains(t, body, expected)
+			}
+		})
+	}
+}
+
+func TestRoutesGetHeadersByHashes(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).Register(app.Group("/v2"))
+
+	known := chainhash.Hash{1}.String()
+	unknown := chainhash.Hash{2}.String()
+	tooMany := "[" + strings.Repeat(`"`+known+`",`, MaxHeadersByHashes) + `"` + known + `"]`
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectContains string
+	}{
+		{name: "ReturnsHeadersWithNullForUnknown", body: `["` + known + `","` + unknown + `"]`, expectedStatus: 200, expectContains: `"hash":"` + known + `"},null]`},
+		{name: "EmptyArray", body: `[]`, expectedStatus: 200, expectContains: `"status":"success"`},
+		{name: "RejectsInvalidHash", body: `["` + known + `","xyz"]`, expectedStatus: 400, expectContains: "Invalid hash at index 1"},
+		{name: "RejectsMalformedBody", body: `{`, expectedStatus: 400, expectContains: "ERR_INVALID_PARAMS"},
+		{name: "RejectsTooManyHashes", body: tooMany, expectedStatus: 400, expectContains: "Too many hashes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := post(t, app, "/v2/headers/byHashes", tt.body)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			assert.Contains(t, body, tt.expectContains)
+		})
+	}
+}
+
+// chainStub serves a main chain of linked headers plus any extra orphans
+type chainStub struct {
+	*stubChaintracks
+	main    []*chaintracks.BlockHeader
+	orphans []*chaintracks.BlockHeader
+}
+
+func newChainStub(length int) *chainStub {
+	s := &chainStub{}
+	for i := range length {
+		s.main = append(s.main, &chaintracks.BlockHeader{Header: &block.Header{}, Height: uint32(i), Hash: chainhash.Hash{byte(i + 1)}}) //nolint:gosec // Test heights are small
+	}
+	s.stubChaintracks = &stubChaintracks{tip: s.main[length-1]}
+	return s
+}
+
+func (s *chainStub) GetHeaderByHeight(_ context.Context, height uint32) (*chaintracks.BlockHeader, error) {
+	if int(height) >= len(s.main) {
+		return nil, chaintracks.ErrHeaderNotFound
+	}
+	return s.main[height], nil
+}
+
+func (s *chainStub) GetHeaderByHash(_ context.Context, hash *chainhash.Hash) (*chaintracks.BlockHeader, error) {
+	for _, header := range append(s.main, s.orphans...) {
+		if header.Hash.IsEqual(hash) {
+			return header, nil
+		}
+	}
+	return nil, chaintracks.ErrHeaderNotFound
+}
+
+// GetHeadersBackwards walks the main chain down from a main-chain hash
+func (s *chainStub) GetHeadersBackwards(ctx context.Context, hash *chainhash.Hash, count uint32) ([]*chaintracks.BlockHeader, error) {
+	header, err := s.GetHeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	var headers []*chaintracks.BlockHeader
+	for height := int(header.Height); height >= 0 && len(headers) < int(count); height-- {
+		headers = append(headers, s.main[height])
+	}
+	return headers, nil
+}
+
+func TestRoutesGetHeaderMedianTime(t *testing.T) {
+	stub := newChainStub(14)
+	for i, header := range stub.main {
+		header.Timestamp = uint32(1000 + 100*i) //nolint:gosec // Test heights are small
+	}
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	status, body := get(t, app, "/v2/header/height/13?include=mtp")
+	require.Equal(t, fiber.StatusOK, status)
+	var response struct {
+		Value struct {
+			Time       uint32 `json:"time"`
+			MedianTime uint32 `json:"medianTime"`
+		} `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	assert.Equal(t, uint32(2300), response.Value.Time)
+	assert.Equal(t, uint32(1800), response.Value.MedianTime, "the median of heights 3 to 13")
+	assert.Zero(t, stub.main[13].MedianTime, "the backend's header is not modified")
+
+	_, body = get(t, app, "/v2/header/height/13")
+	assert.NotContains(t, body, "medianTime")
+
+	status, contentType, protoBody := negotiatedGet(t, app, "/v2/header/height/13?include=mtp", MIMEApplicationProtobuf)
+	require.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, MIMEApplicationProtobuf, contentType)
+	assert.Equal(t, uint64(1800), protoFields(t, protoBody)[10])
+}
+
+func TestRoutesGetHeadersRange(t *testing.T) {
+	stub := newChainStub(5)
+	stub.orphans = append(stub.orphans, &chaintracks.BlockHeader{Header: &block.Header{}, Height: 3, Hash: chainhash.Hash{0xff}})
+
+	app := fiber.New()
+	NewRoutes(stub).Register(app.Group("/v2"))
+
+	hash := func(h *chaintracks.BlockHeader) string { return h.Hash.String() }
+	path := func(from, to string) string { return "/v2/headers/range?from=" + from + "&to=" + to }
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedHeights []uint32
+		expectContains  string
+	}{
+		{name: "Inclusive", path: path(hash(stub.main[1]), hash(stub.main[3])), expectedStatus: 200, expectedHeights: []uint32{1, 2, 3}},
+		{name: "SingleHeader", path: path(hash(stub.main[2]), hash(stub.main[2])), expectedStatus: 200, expectedHeights: []uint32{2}},
+		{name: "ReversedBounds", path: path(hash(stub.main[3]), hash(stub.main[1])), expectedStatus: 400, expectContains: "from must not be above to"},
+		{name: "InvalidHash", path: path("xyz", hash(stub.main[1])), expectedStatus: 400, expectContains: "Invalid from parameter"},
+		{name: "MissingTo", path: "/v2/headers/range?from=" + hash(stub.main[1]), expectedStatus: 400, expectContains: "Invalid to parameter"},
+		{name: "UnknownHash", path: path(hash(stub.main[0]), chainhash.Hash{0xee}.String()), expectedStatus: 404, expectContains: "ERR_NOT_FOUND"},
+		{name: "OrphanBoundary", path: path(hash(stub.main[0]), hash(stub.orphans[0])), expectedStatus: 404, expectContains: "not found on main chain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			require.Equal(t, tt.expectedStatus, status, body)
+			if tt.expectedStatus != 200 {
+				assert.Contains(t, body, tt.expectContains)
+				return
+			}
+
+			var resp struct {
+				Value []chaintracks.BlockHeader `json:"value"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+			heights := make([]uint32, len(resp.Value))
+			for i, h := range resp.Value {
+				heights[i] = h.Height
+			}
+			assert.Equal(t, tt.expectedHeights, heights)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package fiber
+
+import (
+	"strconv"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TypeScript chaintracks route keys, relative to the router passed to RegisterTS (typically the app root)
+const (
+	RouteTSGetChain                  = "/getChain"
+	RouteTSGetInfo                   = "/getInfo"
+	RouteTSGetPresentHeight          = "/getPresentHeight"
+	RouteTSGetHeaders                = "/getHeaders"
+	RouteTSFindChainTipHashHex       = "/findChainTipHashHex"
+	RouteTSFindChainTipHeaderHex     = "/findChainTipHeaderHex"
+	RouteTSFindHeaderHexForHeight    = "/findHeaderHexForHeight"
+	RouteTSFindHeaderHexForBlockHash = "/findHeaderHexForBlockHash"
+	RouteTSIsListening               = "/isListening"
+	RouteTSIsSynchronized            = "/isSynchronized"
+)
+
+// TSInfo mirrors ChaintracksInfoApi from the TypeScript wallet-toolbox
+type TSInfo struct {
+	Chain         string   `json:"chain"`
+	HeightBulk    uint32   `json:"heightBulk"`
+	HeightLive    uint32   `json:"heightLive"`
+	Storage       string   `json:"storage"`
+	BulkIngestors []string `json:"bulkIngestors"`
+	LiveIngestors []string `json:"liveIngestors"`
+	Packages      []string `json:"packages"`
+}
+
+// RegisterTS mounts the routes the TypeScript wallet-toolbox ChaintracksServiceClient calls,
+// so TS clients can use this server without modification. Headers serialize with the same
+// field names as the TS BlockHeader, and lookups that find nothing succeed with no value, as in TS.
+// Global and per-route middleware apply as for Register.
+func (r *Routes) RegisterTS(router fiber.Router) {
+	r.add(router, RouteTSGetChain, r.HandleGetNetwork)
+	r.add(router, RouteTSGetInfo, r.HandleTSGetInfo)
+	r.add(router, RouteTSGetPresentHeight, r.HandleGetHeight)
+	r.add(router, RouteTSGetHeaders, r.HandleGetHeaders)
+	r.add(router, RouteTSFindChainTipHashHex, r.HandleGetTipHash)
+	r.add(router, RouteTSFindChainTipHeaderHex, r.HandleGetTipHeader)
+	r.add(router, RouteTSFindHeaderHexForHeight, r.HandleTSFindHeaderForHeight)
+	r.add(router, RouteTSFindHeaderHexForBlockHash, r.HandleTSFindHeaderForBlockHash)
+	r.add(router, RouteTSIsListening, r.handleTSTrue)
+	r.add(router, RouteTSIsSynchronized, r.handleTSTrue)
+}
+
+// HandleTSGetInfo returns service information in ChaintracksInfoApi format
+func (r *Routes) HandleTSGetInfo(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+
+	network, err := r.ct.GetNetwork(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(Response{
+			Status: "error",
+			Value:  err.Error(),
+		})
+	}
+
+	height := r.ct.GetHeight(c.UserContext())
+	return c.JSON(Response{
+		Status: "success",
+		Value: TSInfo{
+			Chain:         network,
+			HeightBulk:    height,
+			HeightLive:    height,
+			Storage:       "go-chaintracks",
+			BulkIngestors: []string{},
+			LiveIngestors: []string{},
+			Packages:      []string{},
+		},
+	})
+}
+
+// HandleTSFindHeaderForHeight returns the header at the height query parameter, or no value if unknown
+func (r *Routes) HandleTSFindHeaderForHeight(c *fiber.Ctx) error {
+	height, err := strconv.ParseUint(c.Query("height"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid height parameter",
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	header, err := r.ct.GetHeaderByHeight(c.UserContext(), uint32(height))
+	if err != nil {
+		return c.JSON(Response{Status: "success"})
+	}
+	return c.JSON(Response{
+		Status: "success",
+		Value:  header,
+	})
+}
+
+// HandleTSFindHeaderForBlockHash returns the header for the hash query parameter, or no value if unknown
+func (r *Routes) HandleTSFindHeaderForBlockHash(c *fiber.Ctx) error {
+	hash, err := chainhash.NewHashFromHex(c.Query("hash"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(Response{
+			Status:      "error",
+			Code:        "ERR_INVALID_PARAMS",
+			Description: "Invalid hash parameter",
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, CacheControlNoCache)
+	header, err := r.ct.GetHeaderByHash(c.UserContext(), hash)
+	if err != nil {
+		return c.JSON(Response{Status: "success"})
+	}
+	return c.JSON(Response{
+		Status: "success",
+		Value:  header,
+	})
+}
+
+// handleTSTrue answers the TS listening and synchronization probes; this server is always live
+func (r *Routes) handleTSTrue(c *fiber.Ctx) error {
+	return c.JSON(Response{
+		Status: "success",
+		Value:  true,
+	})
+}
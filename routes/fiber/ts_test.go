@@ -0,0 +1,49 @@
+package fiber
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutesRegisterTS(t *testing.T) {
+	app := fiber.New()
+	NewRoutes(newStubChaintracks()).RegisterTS(app)
+
+	tipHash := chainhash.Hash{1}.String()
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectContains string
+		expectMissing  string
+	}{
+		{name: "GetChain", path: "/getChain", expectedStatus: 200, expectContains: `"value":"main"`},
+		{name: "GetInfo", path: "/getInfo", expectedStatus: 200, expectContains: `"heightLive":0`},
+		{name: "GetPresentHeight", path: "/getPresentHeight", expectedStatus: 200, expectContains: `"value":0`},
+		{name: "GetHeaders", path: "/getHeaders?height=0&count=1", expectedStatus: 200, expectContains: `"status":"success"`},
+		{name: "FindChainTipHashHex", path: "/findChainTipHashHex", expectedStatus: 200, expectContains: `"status":"success"`},
+		{name: "FindChainTipHeaderHex", path: "/findChainTipHeaderHex", expectedStatus: 200, expectContains: `"previousHash"`},
+		{name: "FindHeaderForHeight", path: "/findHeaderHexForHeight?height=0", expectedStatus: 200, expectContains: `"hash":"` + tipHash + `"`},
+		{name: "FindHeaderForUnknownHeightHasNoValue", path: "/findHeaderHexForHeight?height=5", expectedStatus: 200, expectContains: `"status":"success"`, expectMissing: `"value"`},
+		{name: "FindHeaderForHeightRejectsInvalid", path: "/findHeaderHexForHeight?height=abc", expectedStatus: 400, expectContains: "ERR_INVALID_PARAMS"},
+		{name: "FindHeaderForBlockHash", path: "/findHeaderHexForBlockHash?hash=" + tipHash, expectedStatus: 200, expectContains: `"height":0`},
+		{name: "FindHeaderForUnknownHashHasNoValue", path: "/findHeaderHexForBlockHash?hash=" + chainhash.Hash{2}.String(), expectedStatus: 200, expectMissing: `"value"`},
+		{name: "FindHeaderForBlockHashRejectsInvalid", path: "/findHeaderHexForBlockHash?hash=xyz", expectedStatus: 400, expectContains: "ERR_INVALID_PARAMS"},
+		{name: "IsListening", path: "/isListening", expectedStatus: 200, expectContains: `"value":true`},
+		{name: "IsSynchronized", path: "/isSynchronized", expectedStatus: 200, expectContains: `"value":true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			assert.Equal(t, tt.expectedStatus, status, body)
+			assert.Contains(t, body, tt.expectContains)
+			if tt.expectMissing != "" {
+				assert.NotContains(t, body, tt.expectMissing)
+			}
+		})
+	}
+}
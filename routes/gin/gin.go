@@ -0,0 +1,48 @@
+// Package gin mounts the chaintracks v2 API on gin routers, mirroring routes/fiber Register.
+// The handlers are those of routes/stdhttp, including the SSE tip stream, and gin middleware applies to them
+// as to any other route.
+package gin
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bsv-blockchain/go-chaintracks/routes/stdhttp"
+)
+
+// Register mounts the v2 API on router under prefix (typically "/v2")
+func Register(router gin.IRouter, prefix string, routes *stdhttp.Routes) {
+	mount(router, prefix, routes.Handler())
+}
+
+// RegisterBHS mounts the Block Headers Service compatible API on router under prefix (typically "/api/v1")
+func RegisterBHS(router gin.IRouter, prefix string, routes *stdhttp.Routes) {
+	mount(router, prefix, routes.BHSHandler())
+}
+
+// mount serves handler for every method on a catch-all route under prefix
+// The handler is given the path the catch-all matched, which also holds when router is a gin.RouterGroup.
+// Mounted at the root, the catch-all conflicts with any other route on router.
+func mount(router gin.IRouter, prefix string, handler http.Handler) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+	router.Any(prefix+"/*path", func(c *gin.Context) {
+		handler.ServeHTTP(c.Writer, withPath(c.Request, c.Param("path")))
+	})
+}
+
+// withPath returns a shallow copy of req with its URL path replaced
+func withPath(req *http.Request, path string) *http.Request {
+	r := new(http.Request)
+	*r = *req
+	r.URL = new(url.URL)
+	*r.URL = *req.URL
+	r.URL.Path = path
+	r.URL.RawPath = ""
+	return r
+}
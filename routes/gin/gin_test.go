@@ -0,0 +1,120 @@
+package gin
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	"github.com/bsv-blockchain/go-chaintracks/routes/stdhttp"
+)
+
+// newRegtestChain returns a regtest chain manager mined to height
+func newRegtestChain(t *testing.T, height int) *chaintracks.ChainManager {
+	t.Helper()
+
+	cm, err := chaintracks.NewChainManager(t.Context(), chaintracks.NetworkRegtest, t.TempDir(), nil)
+	require.NoError(t, err)
+	cm.SetLogger(slog.New(slog.DiscardHandler))
+	if height > 0 {
+		_, err = cm.MineBlocks(t.Context(), height)
+		require.NoError(t, err)
+	}
+	return cm
+}
+
+// newRouter returns a gin engine without gin's default logging
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+
+	resp, err := http.Get(url) //nolint:gosec,noctx // Test server URL
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestRegister(t *testing.T) {
+	cm := newRegtestChain(t, 3)
+	tip := cm.GetTip(t.Context())
+
+	router := newRouter()
+	router.Use(func(c *gin.Context) { c.Header("X-Served-By", "gin") })
+	routes := stdhttp.NewRoutes(cm)
+	Register(router, "/v2/", routes)
+	RegisterBHS(router.Group("/api"), "v1", routes)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/v2/network") //nolint:gosec,noctx // Test server URL
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "gin", resp.Header.Get("X-Served-By"), "gin middleware runs in front of the routes")
+
+	status, body := get(t, server.URL+"/v2/header/height/3")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, tip.Hash.String())
+
+	status, body = get(t, server.URL+"/v2/headers?height=0&count=4")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"status":"success"`)
+
+	status, body = get(t, server.URL+"/api/v1/chain/tip/longest")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, tip.Hash.String())
+
+	status, _ = get(t, server.URL+"/v3/network")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestRegisterAtRoot(t *testing.T) {
+	cm := newRegtestChain(t, 0)
+
+	router := newRouter()
+	Register(router, "", stdhttp.NewRoutes(cm))
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	status, body := get(t, server.URL+"/network")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, chaintracks.NetworkRegtest)
+}
+
+func TestRegisterTipStream(t *testing.T) {
+	cm := newRegtestChain(t, 1)
+
+	router := newRouter()
+	Register(router, "/v2", stdhttp.NewRoutes(cm))
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v2/tip/stream", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: tip", strings.TrimSpace(line))
+}
@@ -0,0 +1,147 @@
+// Package stdhttp serves the chaintracks v2 API on net/http, for services that do not use Fiber.
+// The request handlers are those of routes/fiber run through Fiber's net/http adaptor, so both packages answer
+// alike; the SSE tip stream is written natively through http.Flusher so events reach clients as they happen.
+package stdhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	fiberroutes "github.com/bsv-blockchain/go-chaintracks/routes/fiber"
+)
+
+// RouteTipStream is the path of the SSE tip stream, relative to Handler
+const RouteTipStream = "/tip/stream"
+
+// DefaultMaxReplay is the default cap on missed events replayed to a resuming tip stream client, see WithMaxReplay
+const DefaultMaxReplay = 1000
+
+// EventSource publishes sequenced chain events; the tip stream is served only for a Chaintracks implementing it,
+// such as ChainManager
+type EventSource interface {
+	SubscribeEvents(ctx context.Context) <-chan *chaintracks.ChainEvent
+	EventsSince(seq uint64) ([]*chaintracks.ChainEvent, bool)
+	LastEventSeq() uint64
+}
+
+var _ EventSource = (*chaintracks.ChainManager)(nil)
+
+// Routes serves the chaintracks v2 API on net/http on top of a Chaintracks implementation
+type Routes struct {
+	ct          chaintracks.Chaintracks
+	events      EventSource // nil when ct does not publish events
+	routeOpts   []fiberroutes.Option
+	maxReplay   int
+	v2, bhs, ts http.Handler
+}
+
+// Option configures Routes
+type Option func(*Routes)
+
+// WithRouteOptions applies routes/fiber options, such as WithMaxHeaders or WithCachePolicy, to the request handlers
+// Fiber middleware given this way runs inside the adaptor and does not apply to the tip stream.
+func WithRouteOptions(opts ...fiberroutes.Option) Option {
+	return func(r *Routes) {
+		r.routeOpts = append(r.routeOpts, opts...)
+	}
+}
+
+// WithMaxReplay caps the missed events replayed to a resuming tip stream client, 0 keeps DefaultMaxReplay
+func WithMaxReplay(n int) Option {
+	return func(r *Routes) {
+		if n > 0 {
+			r.maxReplay = n
+		}
+	}
+}
+
+// NewRoutes creates net/http routes backed by the given Chaintracks implementation
+func NewRoutes(ct chaintracks.Chaintracks, opts ...Option) *Routes {
+	r := &Routes{ct: ct, maxReplay: DefaultMaxReplay}
+	r.events, _ = ct.(EventSource)
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	routes := fiberroutes.NewRoutes(ct, r.routeOpts...)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+RouteTipStream, r.HandleTipStream)
+	mux.Handle("/", fiberHandler(routes.Register))
+	r.v2 = mux
+	r.bhs = fiberHandler(routes.RegisterBHS)
+	r.ts = fiberHandler(routes.RegisterTS)
+	return r
+}
+
+// fiberHandler adapts routes registered on a Fiber app to net/http
+// The adaptor buffers each response, so streamed bodies such as /headers/export are sent once complete.
+func fiberHandler(register func(fiber.Router)) http.Handler {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	register(app)
+	handler := adaptor.FiberApp(app)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// The adaptor routes on RequestURI, which http.StripPrefix and router mounts leave unchanged
+		req = req.WithContext(req.Context())
+		req.RequestURI = req.URL.RequestURI()
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// Handler serves the v2 API, including the tip stream, at paths relative to its root such as /height
+// Mount it under /v2, e.g. with Register or a router's own mount function.
+func (r *Routes) Handler() http.Handler {
+	return r.v2
+}
+
+// BHSHandler serves the Block Headers Service compatible API at paths relative to its root, see RegisterBHS
+func (r *Routes) BHSHandler() http.Handler {
+	return r.bhs
+}
+
+// TSHandler serves the TypeScript wallet-toolbox routes at paths relative to its root, see RegisterTS
+func (r *Routes) TSHandler() http.Handler {
+	return r.ts
+}
+
+// Register mounts the v2 API on mux under prefix (typically "/v2")
+func (r *Routes) Register(mux *http.ServeMux, prefix string) {
+	mount(mux, prefix, r.v2)
+}
+
+// RegisterBHS mounts the Block Headers Service compatible API on mux under prefix (typically "/api/v1")
+func (r *Routes) RegisterBHS(mux *http.ServeMux, prefix string) {
+	mount(mux, prefix, r.bhs)
+}
+
+// RegisterTS mounts the TypeScript wallet-toolbox routes on mux under prefix, empty for the root as TS clients expect
+func (r *Routes) RegisterTS(mux *http.ServeMux, prefix string) {
+	mount(mux, prefix, r.ts)
+}
+
+// mount serves handler under prefix with the prefix stripped from request paths
+func mount(mux *http.ServeMux, prefix string, handler http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		mux.Handle("/", handler)
+		return
+	}
+	mux.Handle(prefix+"/", http.StripPrefix(prefix, handler))
+}
+
+// writeError writes a JSON error in the format of the Fiber routes
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(fiberroutes.Response{
+		Status:      "error",
+		Code:        code,
+		Description: description,
+	})
+}
@@ -0,0 +1,127 @@
+package stdhttp
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+	fiberroutes "github.com/bsv-blockchain/go-chaintracks/routes/fiber"
+)
+
+// newRegtestChain returns a regtest chain manager mined to height
+func newRegtestChain(t *testing.T, height int) *chaintracks.ChainManager {
+	t.Helper()
+
+	cm, err := chaintracks.NewChainManager(t.Context(), chaintracks.NetworkRegtest, t.TempDir(), nil)
+	require.NoError(t, err)
+	cm.SetLogger(slog.New(slog.DiscardHandler))
+	if height > 0 {
+		_, err = cm.MineBlocks(t.Context(), height)
+		require.NoError(t, err)
+	}
+	return cm
+}
+
+// serve registers all routes on a new ServeMux and serves it for the duration of the test
+func serve(t *testing.T, r *Routes) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	r.Register(mux, "/v2")
+	r.RegisterBHS(mux, "/api/v1")
+	r.RegisterTS(mux, "")
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+
+	resp, err := http.Get(url) //nolint:gosec,noctx // Test server URL
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestRoutesRegister(t *testing.T) {
+	cm := newRegtestChain(t, 3)
+	server := serve(t, NewRoutes(cm))
+	tip := cm.GetTip(t.Context())
+
+	status, body := get(t, server.URL+"/v2/height")
+	assert.Equal(t, http.StatusOK, status)
+	var resp fiberroutes.Response
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.InDelta(t, 3, resp.Value, 0)
+
+	status, body = get(t, server.URL+"/v2/header/height/3")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, tip.Hash.String())
+
+	status, body = get(t, server.URL+"/v2/header/height/99")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "ERR_NOT_FOUND")
+
+	status, body = get(t, server.URL+"/v2/headers?height=0&count=4")
+	assert.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Len(t, resp.Value, 4*80*2)
+
+	status, body = get(t, server.URL+"/api/v1/chain/tip/longest")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, tip.Hash.String())
+
+	status, body = get(t, server.URL+"/findChainTipHashHex")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, tip.Hash.String())
+}
+
+func TestRoutesVerifyMerkleRoots(t *testing.T) {
+	cm := newRegtestChain(t, 0)
+	root := chaintracks.RegtestGenesis().MerkleRoot
+	server := serve(t, NewRoutes(cm))
+
+	body := `[{"merkleRoot":"` + root.String() + `","blockHeight":0}]`
+	resp, err := http.Post(server.URL+"/v2/merkleroots/verify", "application/json", strings.NewReader(body)) //nolint:noctx // Test server URL
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(data), `"state":"CONFIRMED"`)
+}
+
+func TestRoutesWithRouteOptions(t *testing.T) {
+	cm := newRegtestChain(t, 3)
+	server := serve(t, NewRoutes(cm, WithRouteOptions(fiberroutes.WithMaxHeaders(2))))
+
+	status, _ := get(t, server.URL+"/v2/headers?height=0&count=2")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body := get(t, server.URL+"/v2/headers?height=0&count=3")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "ERR_INVALID_PARAMS")
+}
+
+func TestHandler(t *testing.T) {
+	cm := newRegtestChain(t, 1)
+	server := httptest.NewServer(NewRoutes(cm).Handler())
+	t.Cleanup(server.Close)
+
+	status, body := get(t, server.URL+"/network")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, chaintracks.NetworkRegtest)
+}
@@ -0,0 +1,201 @@
+package stdhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// KeepAliveInterval is how often an idle tip stream sends a comment, so proxies do not close the connection
+const KeepAliveInterval = 15 * time.Second
+
+// SSE event names emitted on the tip stream, as by the chaintracks server
+const (
+	sseEventTip          = "tip"
+	sseEventReorg        = "reorg"
+	sseEventSyncProgress = "sync-progress"
+)
+
+// HandleTipStream streams tip, reorg and sync-progress events as Server-Sent Events in the chaintracks server format
+// A client resuming with Last-Event-ID gets the events it missed, otherwise the current tip; clients passing
+// fields=compact receive tips as height, hash and previous hash only. The stream ends when the request context is
+// cancelled, which http.Server.Shutdown does not do by itself.
+func (r *Routes) HandleTipStream(w http.ResponseWriter, req *http.Request) {
+	if r.events == nil {
+		writeError(w, http.StatusNotFound, "ERR_NOT_FOUND", "Tip stream needs a Chaintracks that publishes events")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "ERR_INTERNAL", "Response writer does not support streaming")
+		return
+	}
+
+	ctx := req.Context()
+	compact := req.URL.Query().Get("fields") == "compact"
+
+	// Subscribe before the initial events so nothing falls between them; clients skip a repeated tip
+	events := r.events.SubscribeEvents(ctx)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if err := r.writeInitialEvents(ctx, w, req.Header.Get("Last-Event-ID"), compact); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(KeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			message := formatEvent(event, compact)
+			if message == "" {
+				continue
+			}
+			if _, err := io.WriteString(w, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeInitialEvents writes the events a client has not seen yet
+// A resuming client receives every sequenced event after lastEventID when they are still held (capped at
+// maxReplay); otherwise the client gets the current tip and detects any gap from its ID.
+func (r *Routes) writeInitialEvents(ctx context.Context, w io.Writer, lastEventID string, compact bool) error {
+	if seq, err := strconv.ParseUint(lastEventID, 10, 64); err == nil {
+		events, ok := r.events.EventsSince(seq)
+		if ok && len(events) > 0 && len(events) <= r.maxReplay {
+			for _, event := range events {
+				if _, err := io.WriteString(w, formatEvent(event, compact)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	tip := r.ct.GetTip(ctx)
+	if tip == nil {
+		return nil
+	}
+	id := ""
+	if seq := r.events.LastEventSeq(); seq > 0 {
+		id = strconv.FormatUint(seq, 10)
+	}
+	message, err := formatTip(tip, id, compact)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, message)
+	return err
+}
+
+// formatEvent formats a chain event as SSE, using its sequence number as the event ID
+// A reorg is followed by an ID-less tip event so clients that only track tips stay current. Events without a
+// payload format as an empty string.
+func formatEvent(event *chaintracks.ChainEvent, compact bool) string {
+	if event == nil {
+		return ""
+	}
+	id := ""
+	if event.Seq > 0 {
+		id = strconv.FormatUint(event.Seq, 10)
+	}
+
+	switch event.Type {
+	case chaintracks.EventReorg:
+		if event.Tip == nil {
+			return ""
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return ""
+		}
+		tip, err := formatTip(event.Tip, "", compact)
+		if err != nil {
+			return ""
+		}
+		return formatSSE(sseEventReorg, id, data) + tip
+	case chaintracks.EventSyncStatus, chaintracks.EventSyncProgress:
+		if event.Lag == nil {
+			return ""
+		}
+		data, err := json.Marshal(syncProgress{LagStatus: *event.Lag, Progress: event.Progress})
+		if err != nil {
+			return ""
+		}
+		return formatSSE(sseEventSyncProgress, "", data)
+	case chaintracks.EventTipAdvanced:
+		if event.Tip == nil {
+			return ""
+		}
+		tip, err := formatTip(event.Tip, id, compact)
+		if err != nil {
+			return ""
+		}
+		return tip
+	}
+	return ""
+}
+
+// syncProgress is the sync-progress payload: the lag status, plus the bulk sync when the event reports one
+type syncProgress struct {
+	chaintracks.LagStatus
+	Progress *chaintracks.SyncProgress `json:"progress,omitempty"`
+}
+
+// compactTip is the tip payload of compact streams, with the field names of the full header
+type compactTip struct {
+	Height   uint32         `json:"height"`
+	Hash     chainhash.Hash `json:"hash"`
+	PrevHash chainhash.Hash `json:"previousHash"`
+}
+
+// formatTip formats a tip as a named SSE event, as a compactTip when compact is set
+func formatTip(tip *chaintracks.BlockHeader, id string, compact bool) (string, error) {
+	var payload any = tip
+	if compact {
+		// By pointer, so the hashes use their hex MarshalJSON
+		payload = &compactTip{Height: tip.Height, Hash: tip.Hash, PrevHash: tip.PrevHash}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return formatSSE(sseEventTip, id, data), nil
+}
+
+// formatSSE formats a single Server-Sent Event; an empty id omits the id field
+func formatSSE(event, id string, data []byte) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "event: %s\n", event)
+	if id != "" {
+		fmt.Fprintf(&sb, "id: %s\n", id)
+	}
+	fmt.Fprintf(&sb, "data: %s\n\n", data)
+	return sb.String()
+}
@@ -0,0 +1,180 @@
+package stdhttp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-chaintracks/pkg/chaintracks"
+)
+
+// sseEvent is one parsed Server-Sent Event
+type sseEvent struct {
+	name, id, data string
+}
+
+// openStream connects to the tip stream and returns a function reading the next event
+func openStream(t *testing.T, url, lastEventID string) func() sseEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	return func() sseEvent {
+		t.Helper()
+
+		var event sseEvent
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				if event.name != "" {
+					return event
+				}
+			case strings.HasPrefix(line, "event: "):
+				event.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}
+}
+
+// mineEach mines count blocks one at a time, so each produces its own tip event
+func mineEach(t *testing.T, cm *chaintracks.ChainManager, count int) []*chaintracks.BlockHeader {
+	t.Helper()
+
+	mined := make([]*chaintracks.BlockHeader, 0, count)
+	for range count {
+		headers, err := cm.MineBlocks(t.Context(), 1)
+		require.NoError(t, err)
+		mined = append(mined, headers...)
+	}
+	return mined
+}
+
+func TestHandleTipStream(t *testing.T) {
+	cm := newRegtestChain(t, 2)
+	server := serve(t, NewRoutes(cm))
+	next := openStream(t, server.URL+"/v2/tip/stream", "")
+
+	event := next()
+	assert.Equal(t, "tip", event.name)
+	assert.Equal(t, strconv.FormatUint(cm.LastEventSeq(), 10), event.id)
+	assert.Contains(t, event.data, cm.GetTip(t.Context()).Hash.String())
+
+	mined, err := cm.MineBlocks(t.Context(), 1)
+	require.NoError(t, err)
+	event = next()
+	assert.Equal(t, "tip", event.name)
+	assert.Equal(t, strconv.FormatUint(cm.LastEventSeq(), 10), event.id)
+	assert.Contains(t, event.data, mined[0].Hash.String())
+
+	branch, err := cm.MineReorg(t.Context(), 1, 2)
+	require.NoError(t, err)
+	event = next()
+	assert.Equal(t, "reorg", event.name)
+	assert.Equal(t, strconv.FormatUint(cm.LastEventSeq(), 10), event.id)
+	var reorg chaintracks.ChainEvent
+	require.NoError(t, json.Unmarshal([]byte(event.data), &reorg))
+	require.NotNil(t, reorg.Reorg)
+	assert.Equal(t, []chainhash.Hash{mined[0].Hash}, reorg.Reorg.OrphanedHashes)
+
+	event = next()
+	assert.Equal(t, "tip", event.name)
+	assert.Empty(t, event.id)
+	assert.Contains(t, event.data, branch[1].Hash.String())
+}
+
+func TestHandleTipStreamResume(t *testing.T) {
+	cm := newRegtestChain(t, 1)
+	server := serve(t, NewRoutes(cm))
+	seen := cm.LastEventSeq()
+
+	mined := mineEach(t, cm, 2)
+
+	next := openStream(t, server.URL+"/v2/tip/stream", strconv.FormatUint(seen, 10))
+	for i, header := range mined {
+		event := next()
+		assert.Equal(t, "tip", event.name)
+		assert.Equal(t, strconv.FormatUint(seen+uint64(i)+1, 10), event.id)
+		assert.Contains(t, event.data, header.Hash.String())
+	}
+}
+
+func TestHandleTipStreamMaxReplay(t *testing.T) {
+	cm := newRegtestChain(t, 1)
+	server := serve(t, NewRoutes(cm, WithMaxReplay(1)))
+	seen := cm.LastEventSeq()
+
+	mined := mineEach(t, cm, 2)
+
+	// Too many missed events to replay, so the client gets the current tip and sees the gap in its ID
+	next := openStream(t, server.URL+"/v2/tip/stream", strconv.FormatUint(seen, 10))
+	event := next()
+	assert.Equal(t, "tip", event.name)
+	assert.Equal(t, strconv.FormatUint(seen+2, 10), event.id)
+	assert.Contains(t, event.data, mined[1].Hash.String())
+}
+
+func TestHandleTipStreamCompact(t *testing.T) {
+	cm := newRegtestChain(t, 1)
+	server := serve(t, NewRoutes(cm))
+	tip := cm.GetTip(t.Context())
+
+	next := openStream(t, server.URL+"/v2/tip/stream?fields=compact", "")
+	event := next()
+	assert.Equal(t, "tip", event.name)
+	assert.JSONEq(t,
+		`{"height":1,"hash":"`+tip.Hash.String()+`","previousHash":"`+tip.PrevHash.String()+`"}`,
+		event.data)
+}
+
+func TestHandleTipStreamWithoutEvents(t *testing.T) {
+	// Hide the event methods of the chain manager behind the Chaintracks interface
+	ct := struct{ chaintracks.Chaintracks }{newRegtestChain(t, 1)}
+	server := serve(t, NewRoutes(ct))
+
+	status, body := get(t, server.URL+"/v2/tip/stream")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "ERR_NOT_FOUND")
+
+	status, body = get(t, server.URL+"/v2/height")
+	assert.Equal(t, http.StatusOK, status, body)
+}
+
+func TestFormatEvent(t *testing.T) {
+	assert.Empty(t, formatEvent(nil, false))
+	assert.Empty(t, formatEvent(&chaintracks.ChainEvent{Type: chaintracks.EventTipAdvanced}, false))
+	assert.Empty(t, formatEvent(&chaintracks.ChainEvent{Type: chaintracks.EventSyncStatus}, false))
+
+	message := formatEvent(&chaintracks.ChainEvent{
+		Type: chaintracks.EventSyncStatus,
+		Lag:  &chaintracks.LagStatus{},
+	}, false)
+	assert.True(t, strings.HasPrefix(message, "event: sync-progress\ndata: {"), message)
+}